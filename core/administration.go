@@ -18,6 +18,7 @@
 package core
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"health/core/model"
@@ -30,65 +31,52 @@ import (
 	"github.com/google/uuid"
 )
 
-func (app *Application) getCovid19Config() (*model.COVID19Config, error) {
-	config, err := app.storage.ReadCovid19Config()
+const (
+	newsStatusDraft     = "draft"
+	newsStatusPublished = "published"
+)
+
+func (app *Application) getCovid19Config(ctx context.Context) (*model.COVID19Config, error) {
+	config, err := app.storage.ReadCovid19Config(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return config, nil
 }
 
-func (app *Application) updateCovid19Config(config *model.COVID19Config) error {
-	err := app.storage.SaveCovid19Config(config)
+func (app *Application) updateCovid19Config(ctx context.Context, config *model.COVID19Config) error {
+	err := checkWaitTimeThresholds(config)
+	if err != nil {
+		return err
+	}
+	err = app.storage.SaveCovid19Config(ctx, config)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (app *Application) getAppVersions() ([]string, error) {
-	appVersions, err := app.storage.ReadAllAppVersions()
+func (app *Application) getAppVersions(ctx context.Context) ([]string, error) {
+	appVersions, err := app.storage.ReadAllAppVersions(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return appVersions, nil
 }
 
-func (app *Application) createAppVersion(current model.User, group string, audit *string, version string) error {
-	//First validate the version input. We accept x.x.x or x.x which is the short for x.x.0
+func (app *Application) createAppVersion(ctx context.Context, current model.User, group string, audit *string, version string) error {
+	//First validate the version input. We accept semantic versions - x.x.x, x.x which is the short for x.x.0, x.x.x-pre.release+build.
 	//If the input is 3.5.0 then we will store 3.5 as the system works with the short view when the patch is 0
-	var major, minor, patch int
-	var err error
-	elements := strings.Split(version, ".")
-	elementsCount := len(elements)
-	if !(elementsCount == 2 || elementsCount == 3) {
-		return errors.New("format must be x.x.x or x.x")
-	}
-
-	major, err = strconv.Atoi(elements[0])
-	if err != nil {
-		return err
-	}
-	minor, err = strconv.Atoi(elements[1])
+	parsed, err := utils.ParseVersion(version)
 	if err != nil {
 		return err
 	}
-	if elementsCount == 2 {
-		patch = 0
-	} else {
-		patch, err = strconv.Atoi(elements[2])
-		if err != nil {
-			return err
-		}
+	res := parsed.String()
+	if supported, _ := app.isVersionSupported(res); supported {
+		return errors.New("duplicate app version " + res)
 	}
 
-	res := fmt.Sprintf("%d.%d.%d", major, minor, patch)
-	//the system work with the shor view when patch is 0
-	if patch == 0 {
-		res = fmt.Sprintf("%d.%d", major, minor)
-	}
-
-	err = app.storage.CreateAppVersion(res)
+	err = app.storage.CreateAppVersion(ctx, res)
 	if err != nil {
 		return err
 	}
@@ -101,16 +89,22 @@ func (app *Application) createAppVersion(current model.User, group string, audit
 	return nil
 }
 
-func (app *Application) getAllNews() ([]*model.News, error) {
-	news, err := app.storage.ReadNews(0)
+func (app *Application) getAllNews(ctx context.Context) ([]*model.News, error) {
+	news, err := app.storage.ReadNews(ctx, 0)
 	if err != nil {
 		return nil, err
 	}
 	return news, nil
 }
 
-func (app *Application) createNews(current model.User, group string, audit *string, date time.Time, title string, description string, htmlContent string, link *string) (*model.News, error) {
-	news, err := app.storage.CreateNews(date, title, description, htmlContent, link)
+func (app *Application) createNews(ctx context.Context, current model.User, group string, audit *string, date time.Time, title string, description string, htmlContent string, link *string,
+	status string, publishAt *time.Time, expireAt *time.Time, pinned bool, categories []string) (*model.News, error) {
+	status, err := checkNewsSchedule(status, publishAt, expireAt)
+	if err != nil {
+		return nil, err
+	}
+
+	news, err := app.storage.CreateNews(ctx, date, title, description, htmlContent, link, status, publishAt, expireAt, pinned, normalizeNewsCategories(categories))
 	if err != nil {
 		return nil, err
 	}
@@ -119,13 +113,20 @@ func (app *Application) createNews(current model.User, group string, audit *stri
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "date", Value: fmt.Sprint(date)}, {Key: "title", Value: title}, {Key: "description", Value: description},
 		{Key: "htmlContent", Value: htmlContent}, {Key: "link", Value: utils.GetString(link)}}
+	lData = append(lData, newsScheduleLogData(news)...)
 	defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "news", news.ID, lData, audit)
 
 	return news, nil
 }
 
-func (app *Application) updateNews(current model.User, group string, audit *string, ID string, date time.Time, title string, description string, htmlContent string, link *string) (*model.News, error) {
-	news, err := app.storage.FindNews(ID)
+func (app *Application) updateNews(ctx context.Context, current model.User, group string, audit *string, ID string, date time.Time, title string, description string, htmlContent string, link *string,
+	status string, publishAt *time.Time, expireAt *time.Time, pinned bool, categories []string) (*model.News, error) {
+	status, err := checkNewsSchedule(status, publishAt, expireAt)
+	if err != nil {
+		return nil, err
+	}
+
+	news, err := app.storage.FindNews(ctx, ID)
 	if err != nil {
 		return nil, err
 	}
@@ -138,9 +139,14 @@ func (app *Application) updateNews(current model.User, group string, audit *stri
 	news.Title = title
 	news.Description = description
 	news.HTMLContent = htmlContent
+	news.Status = status
+	news.PublishAt = publishAt
+	news.ExpireAt = expireAt
+	news.Pinned = pinned
+	news.Categories = normalizeNewsCategories(categories)
 
 	//save it
-	err = app.storage.SaveNews(news)
+	err = app.storage.SaveNews(ctx, news)
 	if err != nil {
 		return nil, err
 	}
@@ -149,13 +155,56 @@ func (app *Application) updateNews(current model.User, group string, audit *stri
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "date", Value: fmt.Sprint(date)}, {Key: "title", Value: title}, {Key: "description", Value: description},
 		{Key: "htmlContent", Value: htmlContent}, {Key: "link", Value: utils.GetString(link)}}
+	lData = append(lData, newsScheduleLogData(news)...)
 	defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "news", ID, lData, audit)
 
 	return news, nil
 }
 
-func (app *Application) deleteNews(current model.User, group string, ID string) error {
-	err := app.storage.DeleteNews(ID)
+//checkNewsSchedule validates the status and the publish period of a news item. The status is published if not provided.
+func checkNewsSchedule(status string, publishAt *time.Time, expireAt *time.Time) (string, error) {
+	if len(status) == 0 {
+		status = newsStatusPublished
+	}
+	if status != newsStatusDraft && status != newsStatusPublished {
+		return "", errors.New("status must be draft or published")
+	}
+	if publishAt != nil && expireAt != nil && !expireAt.After(*publishAt) {
+		return "", errors.New("expire at must be after publish at")
+	}
+	return status, nil
+}
+
+func newsScheduleLogData(news *model.News) []AuditDataEntry {
+	var publishAt, expireAt string
+	if news.PublishAt != nil {
+		publishAt = fmt.Sprint(*news.PublishAt)
+	}
+	if news.ExpireAt != nil {
+		expireAt = fmt.Sprint(*news.ExpireAt)
+	}
+	return []AuditDataEntry{{Key: "status", Value: news.Status}, {Key: "publishAt", Value: publishAt},
+		{Key: "expireAt", Value: expireAt}, {Key: "pinned", Value: strconv.FormatBool(news.Pinned)},
+		{Key: "categories", Value: strings.Join(news.Categories, ",")}}
+}
+
+//normalizeNewsCategories gives the categories trimmed, in lower case and without duplicates, so they can be matched exactly
+func normalizeNewsCategories(categories []string) []string {
+	result := []string{}
+	added := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		category = strings.ToLower(strings.TrimSpace(category))
+		if len(category) == 0 || added[category] {
+			continue
+		}
+		added[category] = true
+		result = append(result, category)
+	}
+	return result
+}
+
+func (app *Application) deleteNews(ctx context.Context, current model.User, group string, ID string) error {
+	err := app.storage.DeleteNews(ctx, ID)
 	if err != nil {
 		return err
 	}
@@ -166,16 +215,16 @@ func (app *Application) deleteNews(current model.User, group string, ID string)
 	return nil
 }
 
-func (app *Application) getAllResources() ([]*model.Resource, error) {
-	news, err := app.storage.ReadAllResources()
+func (app *Application) getAllResources(ctx context.Context) ([]*model.Resource, error) {
+	news, err := app.storage.ReadAllResources(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return news, nil
 }
 
-func (app *Application) createResource(current model.User, group string, audit *string, title string, link string, displayOrder int) (*model.Resource, error) {
-	resource, err := app.storage.CreateResource(title, link, displayOrder)
+func (app *Application) createResource(ctx context.Context, current model.User, group string, audit *string, title string, link string, displayOrder int) (*model.Resource, error) {
+	resource, err := app.storage.CreateResource(ctx, title, link, displayOrder, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -188,8 +237,8 @@ func (app *Application) createResource(current model.User, group string, audit *
 	return resource, nil
 }
 
-func (app *Application) updateResource(current model.User, group string, audit *string, ID string, title string, link string, displayOrder int) (*model.Resource, error) {
-	resource, err := app.storage.FindResource(ID)
+func (app *Application) updateResource(ctx context.Context, current model.User, group string, audit *string, ID string, title string, link string, displayOrder int) (*model.Resource, error) {
+	resource, err := app.storage.FindResource(ctx, ID)
 	if err != nil {
 		return nil, err
 	}
@@ -203,7 +252,7 @@ func (app *Application) updateResource(current model.User, group string, audit *
 	resource.DisplayOrder = displayOrder
 
 	//save it
-	err = app.storage.SaveResource(resource)
+	err = app.storage.SaveResource(ctx, resource)
 	if err != nil {
 		return nil, err
 	}
@@ -216,8 +265,8 @@ func (app *Application) updateResource(current model.User, group string, audit *
 	return resource, nil
 }
 
-func (app *Application) deleteResource(current model.User, group string, ID string) error {
-	err := app.storage.DeleteResource(ID)
+func (app *Application) deleteResource(ctx context.Context, current model.User, group string, ID string) error {
+	err := app.storage.DeleteResource(ctx, ID)
 	if err != nil {
 		return err
 	}
@@ -229,14 +278,14 @@ func (app *Application) deleteResource(current model.User, group string, ID stri
 	return nil
 }
 
-func (app *Application) updateResourceDisplayOrder(IDs []string) error {
+func (app *Application) updateResourceDisplayOrder(ctx context.Context, IDs []string) error {
 	if IDs == nil {
 		return errors.New("updateResourceDisplayOrder() -> no IDs provided")
 	}
 
 	//1. validate if the input items are the same size as the stored items
 	inputCount := len(IDs)
-	allResources, err := app.storage.ReadAllResources()
+	allResources, err := app.storage.ReadAllResources(ctx)
 	if err != nil {
 		return err
 	}
@@ -249,7 +298,7 @@ func (app *Application) updateResourceDisplayOrder(IDs []string) error {
 	//TODO refactor
 	for _, resource := range allResources {
 		resource.DisplayOrder = app.findDisplayOrder(resource.ID, IDs)
-		app.storage.SaveResource(resource)
+		app.storage.SaveResource(ctx, resource)
 	}
 	return nil
 }
@@ -263,8 +312,8 @@ func (app *Application) findDisplayOrder(ID string, IDs []string) int {
 	return -1
 }
 
-func (app *Application) getFAQs() (*model.FAQ, error) {
-	faq, err := app.storage.ReadFAQ()
+func (app *Application) getFAQs(ctx context.Context) (*model.FAQ, error) {
+	faq, err := app.storage.ReadFAQ(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -274,8 +323,8 @@ func (app *Application) getFAQs() (*model.FAQ, error) {
 	return faq, nil
 }
 
-func (app *Application) createFAQ(current model.User, group string, audit *string, section string, sectionDisplayOrder int, title string, description string, questionDisplayOrder int) error {
-	faq, err := app.storage.ReadFAQ()
+func (app *Application) createFAQ(ctx context.Context, current model.User, group string, audit *string, section string, sectionDisplayOrder int, title string, description string, questionDisplayOrder int) error {
+	faq, err := app.storage.ReadFAQ(ctx)
 	if err != nil {
 		return err
 	}
@@ -316,7 +365,7 @@ func (app *Application) createFAQ(current model.User, group string, audit *strin
 	faq.DateUpdate = time.Now()
 
 	//store the update item
-	err = app.storage.SaveFAQ(faq)
+	err = app.storage.SaveFAQ(ctx, faq)
 	if err != nil {
 		return err
 	}
@@ -339,8 +388,8 @@ func (app *Application) findSection(title string, sections *[]*model.Section) *m
 	return nil
 }
 
-func (app *Application) updateFAQ(current model.User, group string, audit *string, ID string, title string, description string, displayOrder int) error {
-	faq, err := app.storage.ReadFAQ()
+func (app *Application) updateFAQ(ctx context.Context, current model.User, group string, audit *string, ID string, title string, description string, displayOrder int) error {
+	faq, err := app.storage.ReadFAQ(ctx)
 	if err != nil {
 		return err
 	}
@@ -371,7 +420,7 @@ func (app *Application) updateFAQ(current model.User, group string, audit *strin
 	faq.DateUpdate = time.Now()
 
 	//store the updated item
-	err = app.storage.SaveFAQ(faq)
+	err = app.storage.SaveFAQ(ctx, faq)
 	if err != nil {
 		return err
 	}
@@ -386,8 +435,8 @@ func (app *Application) updateFAQ(current model.User, group string, audit *strin
 	return nil
 }
 
-func (app *Application) deleteFAQ(current model.User, group string, ID string) error {
-	faq, err := app.storage.ReadFAQ()
+func (app *Application) deleteFAQ(ctx context.Context, current model.User, group string, ID string) error {
+	faq, err := app.storage.ReadFAQ(ctx)
 	if err != nil {
 		return err
 	}
@@ -421,7 +470,7 @@ func (app *Application) deleteFAQ(current model.User, group string, ID string) e
 	faq.DateUpdate = time.Now()
 
 	//store the updated item
-	err = app.storage.SaveFAQ(faq)
+	err = app.storage.SaveFAQ(ctx, faq)
 	if err != nil {
 		return err
 	}
@@ -433,8 +482,8 @@ func (app *Application) deleteFAQ(current model.User, group string, ID string) e
 	return nil
 }
 
-func (app *Application) deleteFAQSection(current model.User, group string, ID string) error {
-	err := app.storage.DeleteFAQSection(ID)
+func (app *Application) deleteFAQSection(ctx context.Context, current model.User, group string, ID string) error {
+	err := app.storage.DeleteFAQSection(ctx, ID)
 	if err != nil {
 		return err
 	}
@@ -450,8 +499,8 @@ func remove(questions []*model.Question, s int) []*model.Question {
 	return append(questions[:s], questions[s+1:]...)
 }
 
-func (app *Application) updateFAQSection(current model.User, group string, audit *string, ID string, title string, displayOrder int) error {
-	faq, err := app.storage.ReadFAQ()
+func (app *Application) updateFAQSection(ctx context.Context, current model.User, group string, audit *string, ID string, title string, displayOrder int) error {
+	faq, err := app.storage.ReadFAQ(ctx)
 	if err != nil {
 		return err
 	}
@@ -476,7 +525,7 @@ func (app *Application) updateFAQSection(current model.User, group string, audit
 	faq.DateUpdate = time.Now()
 
 	//store the updated item
-	err = app.storage.SaveFAQ(faq)
+	err = app.storage.SaveFAQ(ctx, faq)
 	if err != nil {
 		return err
 	}
@@ -489,8 +538,8 @@ func (app *Application) updateFAQSection(current model.User, group string, audit
 	return nil
 }
 
-func (app *Application) createProvider(current model.User, group string, audit *string, providerName string, manualTest bool, availableMechanisms []string) (*model.Provider, error) {
-	provider, err := app.storage.CreateProvider(providerName, manualTest, availableMechanisms)
+func (app *Application) createProvider(ctx context.Context, current model.User, group string, audit *string, providerName string, manualTest bool, availableMechanisms []string) (*model.Provider, error) {
+	provider, err := app.storage.CreateProvider(ctx, providerName, manualTest, availableMechanisms)
 	if err != nil {
 		return nil, err
 	}
@@ -502,8 +551,8 @@ func (app *Application) createProvider(current model.User, group string, audit *
 	return provider, nil
 }
 
-func (app *Application) updateProvider(current model.User, group string, audit *string, ID string, providerName string, manualTest bool, availableMechanisms []string) (*model.Provider, error) {
-	provider, err := app.storage.FindProvider(ID)
+func (app *Application) updateProvider(ctx context.Context, current model.User, group string, audit *string, ID string, providerName string, manualTest bool, availableMechanisms []string) (*model.Provider, error) {
+	provider, err := app.storage.FindProvider(ctx, ID)
 	if err != nil {
 		return nil, err
 	}
@@ -517,7 +566,7 @@ func (app *Application) updateProvider(current model.User, group string, audit *
 	provider.AvailableMechanisms = availableMechanisms
 
 	//save it
-	err = app.storage.SaveProvider(provider)
+	err = app.storage.SaveProvider(ctx, provider)
 	if err != nil {
 		return nil, err
 	}
@@ -530,8 +579,8 @@ func (app *Application) updateProvider(current model.User, group string, audit *
 	return provider, nil
 }
 
-func (app *Application) deleteProvider(current model.User, group string, ID string) error {
-	err := app.storage.DeleteProvider(ID)
+func (app *Application) deleteProvider(ctx context.Context, current model.User, group string, ID string) error {
+	err := app.storage.DeleteProvider(ctx, ID)
 	if err != nil {
 		return err
 	}
@@ -543,8 +592,8 @@ func (app *Application) deleteProvider(current model.User, group string, ID stri
 	return nil
 }
 
-func (app *Application) createCounty(current model.User, group string, audit *string, name string, stateProvince string, country string) (*model.County, error) {
-	county, err := app.storage.CreateCounty(name, stateProvince, country)
+func (app *Application) createCounty(ctx context.Context, current model.User, group string, audit *string, name string, stateProvince string, country string) (*model.County, error) {
+	county, err := app.storage.CreateCounty(ctx, name, stateProvince, country)
 	if err != nil {
 		return nil, err
 	}
@@ -557,8 +606,8 @@ func (app *Application) createCounty(current model.User, group string, audit *st
 	return county, nil
 }
 
-func (app *Application) updateCounty(current model.User, group string, audit *string, ID string, name string, stateProvince string, country string) (*model.County, error) {
-	county, err := app.storage.FindCounty(ID)
+func (app *Application) updateCounty(ctx context.Context, current model.User, group string, audit *string, ID string, name string, stateProvince string, country string) (*model.County, error) {
+	county, err := app.storage.FindCounty(ctx, ID)
 	if err != nil {
 		return nil, err
 	}
@@ -572,7 +621,7 @@ func (app *Application) updateCounty(current model.User, group string, audit *st
 	county.Country = country
 
 	//save it
-	err = app.storage.SaveCounty(county)
+	err = app.storage.SaveCounty(ctx, county)
 	if err != nil {
 		return nil, err
 	}
@@ -585,8 +634,8 @@ func (app *Application) updateCounty(current model.User, group string, audit *st
 	return county, nil
 }
 
-func (app *Application) deleteCounty(current model.User, group string, ID string) error {
-	err := app.storage.DeleteCounty(ID)
+func (app *Application) deleteCounty(ctx context.Context, current model.User, group string, ID string) error {
+	err := app.storage.DeleteCounty(ctx, ID)
 	if err != nil {
 		return err
 	}
@@ -598,9 +647,9 @@ func (app *Application) deleteCounty(current model.User, group string, ID string
 	return nil
 }
 
-func (app *Application) createGuideline(current model.User, group string, audit *string, countyID string, name string, description string, items []model.GuidelineItem) (*model.Guideline, error) {
+func (app *Application) createGuideline(ctx context.Context, current model.User, group string, audit *string, countyID string, name string, description string, items []model.GuidelineItem) (*model.Guideline, error) {
 	//1. find if we have a county for the provided ID
-	county, err := app.storage.FindCounty(countyID)
+	county, err := app.storage.FindCounty(ctx, countyID)
 	if err != nil {
 		return nil, err
 	}
@@ -609,7 +658,7 @@ func (app *Application) createGuideline(current model.User, group string, audit
 	}
 
 	//2. create the guideline entity
-	guideline, err := app.storage.CreateGuideline(countyID, name, description, items)
+	guideline, err := app.storage.CreateGuideline(ctx, countyID, name, description, items)
 	if err != nil {
 		return nil, err
 	}
@@ -622,8 +671,8 @@ func (app *Application) createGuideline(current model.User, group string, audit
 	return guideline, nil
 }
 
-func (app *Application) updateGuideline(current model.User, group string, audit *string, ID string, name string, description string, items []model.GuidelineItem) (*model.Guideline, error) {
-	guideline, err := app.storage.FindGuideline(ID)
+func (app *Application) updateGuideline(ctx context.Context, current model.User, group string, audit *string, ID string, name string, description string, items []model.GuidelineItem) (*model.Guideline, error) {
+	guideline, err := app.storage.FindGuideline(ctx, ID)
 	if err != nil {
 		return nil, err
 	}
@@ -637,7 +686,7 @@ func (app *Application) updateGuideline(current model.User, group string, audit
 	guideline.Items = items
 
 	//save it
-	err = app.storage.SaveGuideline(guideline)
+	err = app.storage.SaveGuideline(ctx, guideline)
 	if err != nil {
 		return nil, err
 	}
@@ -650,8 +699,8 @@ func (app *Application) updateGuideline(current model.User, group string, audit
 	return guideline, nil
 }
 
-func (app *Application) deleteGuideline(current model.User, group string, ID string) error {
-	err := app.storage.DeleteGuideline(ID)
+func (app *Application) deleteGuideline(ctx context.Context, current model.User, group string, ID string) error {
+	err := app.storage.DeleteGuideline(ctx, ID)
 	if err != nil {
 		return err
 	}
@@ -663,9 +712,9 @@ func (app *Application) deleteGuideline(current model.User, group string, ID str
 	return nil
 }
 
-func (app *Application) getGuidelinesByCountyID(countyID string) ([]*model.Guideline, error) {
+func (app *Application) getGuidelinesByCountyID(ctx context.Context, countyID string) ([]*model.Guideline, error) {
 	//1. first check if we have a county for the provided id
-	county, err := app.storage.FindCounty(countyID)
+	county, err := app.storage.FindCounty(ctx, countyID)
 	if err != nil {
 		return nil, err
 	}
@@ -674,16 +723,16 @@ func (app *Application) getGuidelinesByCountyID(countyID string) ([]*model.Guide
 	}
 
 	//2. find the guidelines
-	guidelines, err := app.storage.FindGuidelineByCountyID(countyID)
+	guidelines, err := app.storage.FindGuidelineByCountyID(ctx, countyID)
 	if err != nil {
 		return nil, err
 	}
 	return guidelines, nil
 }
 
-func (app *Application) createCountyStatus(current model.User, group string, audit *string, countyID string, name string, description string) (*model.CountyStatus, error) {
+func (app *Application) createCountyStatus(ctx context.Context, current model.User, group string, audit *string, countyID string, name string, description string) (*model.CountyStatus, error) {
 	//1. find if we have a county for the provided ID
-	county, err := app.storage.FindCounty(countyID)
+	county, err := app.storage.FindCounty(ctx, countyID)
 	if err != nil {
 		return nil, err
 	}
@@ -692,7 +741,7 @@ func (app *Application) createCountyStatus(current model.User, group string, aud
 	}
 
 	//2. create the county status entity
-	countyStatus, err := app.storage.CreateCountyStatus(countyID, name, description)
+	countyStatus, err := app.storage.CreateCountyStatus(ctx, countyID, name, description)
 	if err != nil {
 		return nil, err
 	}
@@ -705,8 +754,8 @@ func (app *Application) createCountyStatus(current model.User, group string, aud
 	return countyStatus, nil
 }
 
-func (app *Application) updateCountyStatus(current model.User, group string, audit *string, ID string, name string, description string) (*model.CountyStatus, error) {
-	countyStatus, err := app.storage.FindCountyStatus(ID)
+func (app *Application) updateCountyStatus(ctx context.Context, current model.User, group string, audit *string, ID string, name string, description string) (*model.CountyStatus, error) {
+	countyStatus, err := app.storage.FindCountyStatus(ctx, ID)
 	if err != nil {
 		return nil, err
 	}
@@ -719,7 +768,7 @@ func (app *Application) updateCountyStatus(current model.User, group string, aud
 	countyStatus.Description = description
 
 	//save it
-	err = app.storage.SaveCountyStatus(countyStatus)
+	err = app.storage.SaveCountyStatus(ctx, countyStatus)
 	if err != nil {
 		return nil, err
 	}
@@ -732,8 +781,8 @@ func (app *Application) updateCountyStatus(current model.User, group string, aud
 	return countyStatus, nil
 }
 
-func (app *Application) deleteCountyStatus(current model.User, group string, ID string) error {
-	err := app.storage.DeleteCountyStatus(ID)
+func (app *Application) deleteCountyStatus(ctx context.Context, current model.User, group string, ID string) error {
+	err := app.storage.DeleteCountyStatus(ctx, ID)
 	if err != nil {
 		return err
 	}
@@ -745,9 +794,9 @@ func (app *Application) deleteCountyStatus(current model.User, group string, ID
 	return nil
 }
 
-func (app *Application) getCountyStatusByCountyID(countyID string) ([]*model.CountyStatus, error) {
+func (app *Application) getCountyStatusByCountyID(ctx context.Context, countyID string) ([]*model.CountyStatus, error) {
 	//1. first check if we have a county for the provided id
-	county, err := app.storage.FindCounty(countyID)
+	county, err := app.storage.FindCounty(ctx, countyID)
 	if err != nil {
 		return nil, err
 	}
@@ -756,23 +805,23 @@ func (app *Application) getCountyStatusByCountyID(countyID string) ([]*model.Cou
 	}
 
 	//2. find the county statuses
-	countyStatuses, err := app.storage.FindCountyStatusesByCountyID(countyID)
+	countyStatuses, err := app.storage.FindCountyStatusesByCountyID(ctx, countyID)
 	if err != nil {
 		return nil, err
 	}
 	return countyStatuses, nil
 }
 
-func (app *Application) getTestTypes() ([]*model.TestType, error) {
-	testTypes, err := app.storage.ReadAllTestTypes()
+func (app *Application) getTestTypes(ctx context.Context) ([]*model.TestType, error) {
+	testTypes, err := app.storage.ReadAllTestTypes(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return testTypes, nil
 }
 
-func (app *Application) createTestType(current model.User, group string, audit *string, name string, priority *int) (*model.TestType, error) {
-	testType, err := app.storage.CreateTestType(name, priority)
+func (app *Application) createTestType(ctx context.Context, current model.User, group string, audit *string, name string, priority *int) (*model.TestType, error) {
+	testType, err := app.storage.CreateTestType(ctx, name, priority)
 	if err != nil {
 		return nil, err
 	}
@@ -788,8 +837,8 @@ func (app *Application) createTestType(current model.User, group string, audit *
 	return testType, nil
 }
 
-func (app *Application) updateTestType(current model.User, group string, audit *string, ID string, name string, priority *int) (*model.TestType, error) {
-	testType, err := app.storage.FindTestType(ID)
+func (app *Application) updateTestType(ctx context.Context, current model.User, group string, audit *string, ID string, name string, priority *int) (*model.TestType, error) {
+	testType, err := app.storage.FindTestType(ctx, ID)
 	if err != nil {
 		return nil, err
 	}
@@ -802,7 +851,7 @@ func (app *Application) updateTestType(current model.User, group string, audit *
 	testType.Priority = priority
 
 	//save it
-	err = app.storage.SaveTestType(testType)
+	err = app.storage.SaveTestType(ctx, testType)
 	if err != nil {
 		return nil, err
 	}
@@ -815,8 +864,8 @@ func (app *Application) updateTestType(current model.User, group string, audit *
 	return testType, nil
 }
 
-func (app *Application) deleteTestType(current model.User, group string, ID string) error {
-	err := app.storage.DeleteTestType(ID)
+func (app *Application) deleteTestType(ctx context.Context, current model.User, group string, ID string) error {
+	err := app.storage.DeleteTestType(ctx, ID)
 	if err != nil {
 		return err
 	}
@@ -828,9 +877,9 @@ func (app *Application) deleteTestType(current model.User, group string, ID stri
 	return nil
 }
 
-func (app *Application) createTestTypeResult(current model.User, group string, audit *string, testTypeID string, name string, nextStep string, nextStepOffset *int, resultExpiresOffset *int) (*model.TestTypeResult, error) {
+func (app *Application) createTestTypeResult(ctx context.Context, current model.User, group string, audit *string, testTypeID string, name string, nextStep string, nextStepOffset *int, resultExpiresOffset *int) (*model.TestTypeResult, error) {
 	//1. find if we have a test type for the provided ID
-	testType, err := app.storage.FindTestType(testTypeID)
+	testType, err := app.storage.FindTestType(ctx, testTypeID)
 	if err != nil {
 		return nil, err
 	}
@@ -839,7 +888,7 @@ func (app *Application) createTestTypeResult(current model.User, group string, a
 	}
 
 	//2. create the test type result entity
-	testTypeResult, err := app.storage.CreateTestTypeResult(testTypeID, name, nextStep, nextStepOffset, resultExpiresOffset)
+	testTypeResult, err := app.storage.CreateTestTypeResult(ctx, testTypeID, name, nextStep, nextStepOffset, resultExpiresOffset)
 	if err != nil {
 		return nil, err
 	}
@@ -853,8 +902,8 @@ func (app *Application) createTestTypeResult(current model.User, group string, a
 	return testTypeResult, nil
 }
 
-func (app *Application) updateTestTypeResult(current model.User, group string, audit *string, ID string, name string, nextStep string, nextStepOffset *int, resultExpiresOffset *int) (*model.TestTypeResult, error) {
-	testTypeResult, err := app.storage.FindTestTypeResult(ID)
+func (app *Application) updateTestTypeResult(ctx context.Context, current model.User, group string, audit *string, ID string, name string, nextStep string, nextStepOffset *int, resultExpiresOffset *int) (*model.TestTypeResult, error) {
+	testTypeResult, err := app.storage.FindTestTypeResult(ctx, ID)
 	if err != nil {
 		return nil, err
 	}
@@ -869,7 +918,7 @@ func (app *Application) updateTestTypeResult(current model.User, group string, a
 	testTypeResult.ResultExpiresOffset = resultExpiresOffset
 
 	//save it
-	err = app.storage.SaveTestTypeResult(testTypeResult)
+	err = app.storage.SaveTestTypeResult(ctx, testTypeResult)
 	if err != nil {
 		return nil, err
 	}
@@ -883,8 +932,8 @@ func (app *Application) updateTestTypeResult(current model.User, group string, a
 	return testTypeResult, nil
 }
 
-func (app *Application) deleteTestTypeResult(current model.User, group string, ID string) error {
-	err := app.storage.DeleteTestTypeResult(ID)
+func (app *Application) deleteTestTypeResult(ctx context.Context, current model.User, group string, ID string) error {
+	err := app.storage.DeleteTestTypeResult(ctx, ID)
 	if err != nil {
 		return err
 	}
@@ -896,9 +945,9 @@ func (app *Application) deleteTestTypeResult(current model.User, group string, I
 	return nil
 }
 
-func (app *Application) getTestTypeResultsByTestTypeID(testTypeID string) ([]*model.TestTypeResult, error) {
+func (app *Application) getTestTypeResultsByTestTypeID(ctx context.Context, testTypeID string) ([]*model.TestTypeResult, error) {
 	//1. first check if we have a test type for the provided id
-	testType, err := app.storage.FindTestType(testTypeID)
+	testType, err := app.storage.FindTestType(ctx, testTypeID)
 	if err != nil {
 		return nil, err
 	}
@@ -907,107 +956,85 @@ func (app *Application) getTestTypeResultsByTestTypeID(testTypeID string) ([]*mo
 	}
 
 	//2. find the test type results
-	testTypeResults, err := app.storage.FindTestTypeResultsByTestTypeID(testTypeID)
+	testTypeResults, err := app.storage.FindTestTypeResultsByTestTypeID(ctx, testTypeID)
 	if err != nil {
 		return nil, err
 	}
 	return testTypeResults, nil
 }
 
-func (app *Application) getRules() ([]*model.Rule, error) {
-	rules, err := app.storage.ReadAllRules()
+func (app *Application) getRules(ctx context.Context) ([]*model.Rule, error) {
+	rules, err := app.storage.ReadAllRules(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return rules, nil
 }
 
-func (app *Application) getCRules(countyID string, appVersion string) (*model.CRules, error) {
-	supported, v := app.isVersionSupported(appVersion)
-	if !supported {
-		return nil, errors.New("app version is not supported")
+func (app *Application) getCRules(ctx context.Context, countyID string, appVersion string) (*model.CRules, error) {
+	v, err := app.checkContentVersion(appVersion)
+	if err != nil {
+		return nil, err
 	}
 
-	cRules, err := app.storage.FindCRulesByCountyID(*v, countyID)
+	cRules, err := app.storage.FindCRulesByCountyID(ctx, *v, countyID)
 	if err != nil {
 		return nil, err
 	}
 	return cRules, nil
 }
 
-func (app *Application) createOrUpdateCRules(current model.User, group string, audit *string, countyID string, appVersion string, data string) error {
-	supported, v := app.isVersionSupported(appVersion)
-	if !supported {
-		return errors.New("app version is not supported")
-	}
-
-	create, err := app.storage.CreateOrUpdateCRules(*v, countyID, data)
+//createOrUpdateCRules publishes the data right away as a new revision
+func (app *Application) createOrUpdateCRules(ctx context.Context, current model.User, group string, audit *string, countyID string, appVersion string, data string) error {
+	v, err := app.checkContentVersion(appVersion)
 	if err != nil {
 		return err
 	}
 
-	//audit
-	userIdentifier, userInfo := current.GetLogData()
-	lData := []AuditDataEntry{{Key: "countyID", Value: countyID}, {Key: "appVersion", Value: appVersion}, {Key: "data", Value: data}}
-	if *create {
-		//create
-		defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "crules", "", lData, audit)
-	} else {
-		//update
-		defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "crules", "", lData, audit)
+	_, err = app.publishNewConfigRevision(ctx, current, group, audit, configTypeCRules, countyID, *v, data)
+	if err != nil {
+		return err
 	}
-
 	return nil
 }
 
-func (app *Application) getASymptoms(appVersion string) (*model.Symptoms, error) {
-	supported, v := app.isVersionSupported(appVersion)
-	if !supported {
-		return nil, errors.New("app version is not supported")
+func (app *Application) getASymptoms(ctx context.Context, appVersion string) (*model.Symptoms, error) {
+	v, err := app.checkContentVersion(appVersion)
+	if err != nil {
+		return nil, err
 	}
 
-	symptoms, err := app.storage.ReadSymptoms(*v)
+	symptoms, err := app.storage.ReadSymptoms(ctx, *v)
 	if err != nil {
 		return nil, err
 	}
 	return symptoms, nil
 }
 
-func (app *Application) createOrUpdateSymptoms(current model.User, group string, audit *string, appVersion string, items string) error {
-	supported, v := app.isVersionSupported(appVersion)
-	if !supported {
-		return errors.New("app version is not supported")
-	}
-
-	create, err := app.storage.CreateOrUpdateSymptoms(*v, items)
+//createOrUpdateSymptoms publishes the items right away as a new revision
+func (app *Application) createOrUpdateSymptoms(ctx context.Context, current model.User, group string, audit *string, appVersion string, items string) error {
+	v, err := app.checkContentVersion(appVersion)
 	if err != nil {
 		return err
 	}
 
-	//audit
-	userIdentifier, userInfo := current.GetLogData()
-	lData := []AuditDataEntry{{Key: "appVersion", Value: appVersion}, {Key: "items", Value: items}}
-	if *create {
-		//create
-		defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "symptoms", "", lData, audit)
-	} else {
-		//update
-		defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "symptoms", "", lData, audit)
+	_, err = app.publishNewConfigRevision(ctx, current, group, audit, configTypeSymptoms, "", *v, items)
+	if err != nil {
+		return err
 	}
-
 	return nil
 }
 
-func (app *Application) getUINOverrides(uin *string, sort *string) ([]*model.UINOverride, error) {
-	uinOverrides, err := app.storage.FindUINOverrides(uin, sort)
+func (app *Application) getUINOverrides(ctx context.Context, uin *string, sort *string) ([]*model.UINOverride, error) {
+	uinOverrides, err := app.storage.FindUINOverrides(ctx, uin, sort)
 	if err != nil {
 		return nil, err
 	}
 	return uinOverrides, nil
 }
 
-func (app *Application) createUINOverride(current model.User, group string, audit *string, uin string, interval int, category *string, expiration *time.Time) (*model.UINOverride, error) {
-	uinOverride, err := app.storage.CreateUINOverride(uin, interval, category, expiration)
+func (app *Application) createUINOverride(ctx context.Context, current model.User, group string, audit *string, uin string, interval int, category *string, expiration *time.Time) (*model.UINOverride, error) {
+	uinOverride, err := app.storage.CreateUINOverride(ctx, uin, interval, category, expiration)
 	if err != nil {
 		return nil, err
 	}
@@ -1020,8 +1047,8 @@ func (app *Application) createUINOverride(current model.User, group string, audi
 	return uinOverride, nil
 }
 
-func (app *Application) updateUINOverride(current model.User, group string, audit *string, uin string, interval int, category *string, expiration *time.Time) (*string, error) {
-	result, err := app.storage.UpdateUINOverride(uin, interval, category, expiration)
+func (app *Application) updateUINOverride(ctx context.Context, current model.User, group string, audit *string, uin string, interval int, category *string, expiration *time.Time) (*string, error) {
+	result, err := app.storage.UpdateUINOverride(ctx, uin, interval, category, expiration)
 	if err != nil {
 		return nil, err
 	}
@@ -1034,8 +1061,8 @@ func (app *Application) updateUINOverride(current model.User, group string, audi
 	return result, nil
 }
 
-func (app *Application) deleteUINOverride(current model.User, group string, uin string) error {
-	err := app.storage.DeleteUINOverride(uin)
+func (app *Application) deleteUINOverride(ctx context.Context, current model.User, group string, uin string) error {
+	err := app.storage.DeleteUINOverride(ctx, uin)
 	if err != nil {
 		return err
 	}
@@ -1047,14 +1074,14 @@ func (app *Application) deleteUINOverride(current model.User, group string, uin
 	return nil
 }
 
-func (app *Application) createRule(current model.User, group string, audit *string, countyID string, testTypeID string, priority *int,
+func (app *Application) createRule(ctx context.Context, current model.User, group string, audit *string, countyID string, testTypeID string, priority *int,
 	resultsStatuses []model.TestTypeResultCountyStatus) (*model.Rule, error) {
 
 	//TODO - transactions, consistency!!!
 
 	//First validate
 	//1. Check if we have a county with the provided ID
-	county, err := app.storage.FindCounty(countyID)
+	county, err := app.storage.FindCounty(ctx, countyID)
 	if err != nil {
 		return nil, err
 	}
@@ -1063,7 +1090,7 @@ func (app *Application) createRule(current model.User, group string, audit *stri
 	}
 
 	//2. Check if we have a test type with the provided ID
-	testType, err := app.storage.FindTestType(testTypeID)
+	testType, err := app.storage.FindTestType(ctx, testTypeID)
 	if err != nil {
 		return nil, err
 	}
@@ -1072,7 +1099,7 @@ func (app *Application) createRule(current model.User, group string, audit *stri
 	}
 
 	//3. Check if we already have a rule for the provided county id and test type id.
-	founded, err := app.storage.FindRuleByCountyIDTestTypeID(countyID, testTypeID)
+	founded, err := app.storage.FindRuleByCountyIDTestTypeID(ctx, countyID, testTypeID)
 	if err != nil {
 		return nil, err
 	}
@@ -1081,7 +1108,7 @@ func (app *Application) createRule(current model.User, group string, audit *stri
 	}
 
 	//4. Check if the rule data is vaid
-	valid, err := app.isRuleDataValid(countyID, testTypeID, resultsStatuses)
+	valid, err := app.isRuleDataValid(ctx, countyID, testTypeID, resultsStatuses)
 	if err != nil {
 		return nil, err
 	}
@@ -1090,7 +1117,7 @@ func (app *Application) createRule(current model.User, group string, audit *stri
 	}
 
 	//5. Now create it
-	rule, err := app.storage.CreateRule(countyID, testTypeID, priority, resultsStatuses)
+	rule, err := app.storage.CreateRule(ctx, countyID, testTypeID, priority, resultsStatuses)
 	if err != nil {
 		return nil, err
 	}
@@ -1104,9 +1131,9 @@ func (app *Application) createRule(current model.User, group string, audit *stri
 	return rule, nil
 }
 
-func (app *Application) updateRule(current model.User, group string, audit *string, ID string, priority *int, resultsStatuses []model.TestTypeResultCountyStatus) (*model.Rule, error) {
+func (app *Application) updateRule(ctx context.Context, current model.User, group string, audit *string, ID string, priority *int, resultsStatuses []model.TestTypeResultCountyStatus) (*model.Rule, error) {
 	//1. find the rule
-	rule, err := app.storage.FindRule(ID)
+	rule, err := app.storage.FindRule(ctx, ID)
 	if err != nil {
 		return nil, err
 	}
@@ -1115,7 +1142,7 @@ func (app *Application) updateRule(current model.User, group string, audit *stri
 	}
 
 	//2. Check if the rule data is valid
-	valid, err := app.isRuleDataValid(rule.County.ID, rule.TestType.ID, resultsStatuses)
+	valid, err := app.isRuleDataValid(ctx, rule.County.ID, rule.TestType.ID, resultsStatuses)
 	if err != nil {
 		return nil, err
 	}
@@ -1128,7 +1155,7 @@ func (app *Application) updateRule(current model.User, group string, audit *stri
 	rule.ResultsStates = resultsStatuses
 
 	//4. Save it
-	err = app.storage.SaveRule(rule)
+	err = app.storage.SaveRule(ctx, rule)
 	if err != nil {
 		return nil, err
 	}
@@ -1141,9 +1168,9 @@ func (app *Application) updateRule(current model.User, group string, audit *stri
 	return rule, nil
 }
 
-func (app *Application) isRuleDataValid(countyID string, testTypeID string, resultsStatuses []model.TestTypeResultCountyStatus) (bool, error) {
+func (app *Application) isRuleDataValid(ctx context.Context, countyID string, testTypeID string, resultsStatuses []model.TestTypeResultCountyStatus) (bool, error) {
 	//1. Check if the provided test type results are part from this test type
-	testTypeResultsValid, err := app.areTestTypeResultsValid(testTypeID, resultsStatuses)
+	testTypeResultsValid, err := app.areTestTypeResultsValid(ctx, testTypeID, resultsStatuses)
 	if err != nil {
 		return false, err
 	}
@@ -1152,7 +1179,7 @@ func (app *Application) isRuleDataValid(countyID string, testTypeID string, resu
 	}
 
 	//2. Check if the provided county statuses are part from this county
-	countyStatusesValid, err := app.areCountyStatusesValid(countyID, resultsStatuses)
+	countyStatusesValid, err := app.areCountyStatusesValid(ctx, countyID, resultsStatuses)
 	if err != nil {
 		return false, err
 	}
@@ -1162,8 +1189,8 @@ func (app *Application) isRuleDataValid(countyID string, testTypeID string, resu
 	return true, nil
 }
 
-func (app *Application) areTestTypeResultsValid(testTypeID string, resultsStates []model.TestTypeResultCountyStatus) (bool, error) {
-	testTypeResults, err := app.storage.FindTestTypeResultsByTestTypeID(testTypeID)
+func (app *Application) areTestTypeResultsValid(ctx context.Context, testTypeID string, resultsStates []model.TestTypeResultCountyStatus) (bool, error) {
+	testTypeResults, err := app.storage.FindTestTypeResultsByTestTypeID(ctx, testTypeID)
 	if err != nil {
 		return false, err
 	}
@@ -1182,8 +1209,8 @@ func (app *Application) areTestTypeResultsValid(testTypeID string, resultsStates
 	return true, nil
 }
 
-func (app *Application) areCountyStatusesValid(countyID string, resultsStates []model.TestTypeResultCountyStatus) (bool, error) {
-	countyStatuses, err := app.storage.FindCountyStatusesByCountyID(countyID)
+func (app *Application) areCountyStatusesValid(ctx context.Context, countyID string, resultsStates []model.TestTypeResultCountyStatus) (bool, error) {
+	countyStatuses, err := app.storage.FindCountyStatusesByCountyID(ctx, countyID)
 	if err != nil {
 		return false, err
 	}
@@ -1226,8 +1253,8 @@ func (app *Application) containsTestTypeResult(ID string, list []model.TestTypeR
 	return false
 }
 
-func (app *Application) deleteRule(current model.User, group string, ID string) error {
-	err := app.storage.DeleteRule(ID)
+func (app *Application) deleteRule(ctx context.Context, current model.User, group string, ID string) error {
+	err := app.storage.DeleteRule(ctx, ID)
 	if err != nil {
 		return err
 	}
@@ -1239,25 +1266,35 @@ func (app *Application) deleteRule(current model.User, group string, ID string)
 	return nil
 }
 
-func (app *Application) getLocations() ([]*model.Location, error) {
-	locations, err := app.storage.ReadAllLocations()
+func (app *Application) getLocations(ctx context.Context) ([]*model.Location, error) {
+	locations, err := app.storage.ReadAllLocations(ctx)
 	if err != nil {
 		return nil, err
 	}
+	now := time.Now()
+	for _, location := range locations {
+		if location.WaitTime != nil {
+			location.WaitTime.Stale = app.isWaitTimeStale(*location.WaitTime, now)
+		}
+	}
 	return locations, nil
 }
 
-func (app *Application) createLocation(current model.User, group string, audit *string, providerID string, countyID string, name string, address1 string, address2 string, city string,
+func (app *Application) createLocation(ctx context.Context, current model.User, group string, audit *string, providerID string, countyID string, name string, address1 string, address2 string, city string,
 	state string, zip string, country string, latitude float64, longitude float64, contact string,
 	daysOfOperation []model.OperationDay, url string, notes string, waitTimeColor *string, availableTests []string) (*model.Location, error) {
 	//1. check if the location data is valid
-	err := app.isLocationDataValid(providerID, countyID, availableTests)
+	err := app.isLocationDataValid(ctx, providerID, countyID, availableTests)
+	if err != nil {
+		return nil, err
+	}
+	daysOfOperation, err = checkDaysOfOperation(daysOfOperation)
 	if err != nil {
 		return nil, err
 	}
 
 	//2. create the entity
-	location, err := app.storage.CreateLocation(providerID, countyID, name, address1, address2, city,
+	location, err := app.storage.CreateLocation(ctx, providerID, countyID, name, address1, address2, city,
 		state, zip, country, latitude, longitude, contact, daysOfOperation, url, notes, waitTimeColor, availableTests)
 	if err != nil {
 		return nil, err
@@ -1275,12 +1312,12 @@ func (app *Application) createLocation(current model.User, group string, audit *
 	return location, nil
 }
 
-func (app *Application) updateLocation(current model.User, group string, audit *string, ID string, name string, address1 string, address2 string, city string,
+func (app *Application) updateLocation(ctx context.Context, current model.User, group string, audit *string, ID string, name string, address1 string, address2 string, city string,
 	state string, zip string, country string, latitude float64, longitude float64, contact string,
 	daysOfOperation []model.OperationDay, url string, notes string, waitTimeColor *string, availableTests []string) (*model.Location, error) {
 
 	// find if we have a location for the provided id
-	location, err := app.storage.FindLocation(ID)
+	location, err := app.storage.FindLocation(ctx, ID)
 	if err != nil {
 		return nil, err
 	}
@@ -1289,7 +1326,7 @@ func (app *Application) updateLocation(current model.User, group string, audit *
 	}
 
 	// check if the provided test types ids are valid
-	areTestTypesValid, err := app.areTestTypesValid(availableTests)
+	areTestTypesValid, err := app.areTestTypesValid(ctx, availableTests)
 	if err != nil {
 		return nil, err
 	}
@@ -1297,6 +1334,12 @@ func (app *Application) updateLocation(current model.User, group string, audit *
 		return nil, errors.New("the provided test types are not valid")
 	}
 
+	// check if the days of operation are valid
+	daysOfOperation, err = checkDaysOfOperation(daysOfOperation)
+	if err != nil {
+		return nil, err
+	}
+
 	// add the new values
 	location.Name = name
 	location.Address1 = address1
@@ -1323,7 +1366,7 @@ func (app *Application) updateLocation(current model.User, group string, audit *
 	location.AvailableTests = avTR
 
 	// save it
-	err = app.storage.SaveLocation(location)
+	err = app.storage.SaveLocation(ctx, location)
 	if err != nil {
 		return nil, err
 	}
@@ -1340,9 +1383,9 @@ func (app *Application) updateLocation(current model.User, group string, audit *
 	return location, nil
 }
 
-func (app *Application) isLocationDataValid(providerID string, countyID string, availableTests []string) error {
+func (app *Application) isLocationDataValid(ctx context.Context, providerID string, countyID string, availableTests []string) error {
 	//1. check if we have a provider for the provided id
-	provider, err := app.storage.FindProvider(providerID)
+	provider, err := app.storage.FindProvider(ctx, providerID)
 	if err != nil {
 		return err
 	}
@@ -1351,7 +1394,7 @@ func (app *Application) isLocationDataValid(providerID string, countyID string,
 	}
 
 	//2. check if we have a county for the provided id
-	county, err := app.storage.FindCounty(countyID)
+	county, err := app.storage.FindCounty(ctx, countyID)
 	if err != nil {
 		return err
 	}
@@ -1360,7 +1403,7 @@ func (app *Application) isLocationDataValid(providerID string, countyID string,
 	}
 
 	//3. check if the test types ids are valid
-	areTestTypesValid, err := app.areTestTypesValid(availableTests)
+	areTestTypesValid, err := app.areTestTypesValid(ctx, availableTests)
 	if err != nil {
 		return err
 	}
@@ -1371,12 +1414,12 @@ func (app *Application) isLocationDataValid(providerID string, countyID string,
 	return nil
 }
 
-func (app *Application) areTestTypesValid(availableTests []string) (bool, error) {
+func (app *Application) areTestTypesValid(ctx context.Context, availableTests []string) (bool, error) {
 	if availableTests == nil {
 		return true, nil
 	}
 
-	allTestTypes, err := app.storage.ReadAllTestTypes()
+	allTestTypes, err := app.storage.ReadAllTestTypes(ctx)
 	if err != nil {
 		return false, err
 	}
@@ -1402,12 +1445,21 @@ func (app *Application) containsTestType(ID string, list []*model.TestType) bool
 	return false
 }
 
-func (app *Application) deleteLocation(current model.User, group string, ID string) error {
-	err := app.storage.DeleteLocation(ID)
+func (app *Application) deleteLocation(ctx context.Context, current model.User, group string, ID string) error {
+	location, err := app.storage.FindLocation(ctx, ID)
+	if err != nil {
+		return err
+	}
+	err = app.storage.DeleteLocation(ctx, ID)
 	if err != nil {
 		return err
 	}
 
+	//the users must not come for the appointments at a deleted location
+	if location != nil {
+		app.cancelLocationAppointments(ctx, location)
+	}
+
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
 	defer app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "location", ID)
@@ -1415,8 +1467,8 @@ func (app *Application) deleteLocation(current model.User, group string, ID stri
 	return nil
 }
 
-func (app *Application) createSymptom(current model.User, group string, name string, symptomGroup string) (*model.Symptom, error) {
-	symptom, err := app.storage.CreateSymptom(name, symptomGroup)
+func (app *Application) createSymptom(ctx context.Context, current model.User, group string, name string, symptomGroup string) (*model.Symptom, error) {
+	symptom, err := app.storage.CreateSymptom(ctx, name, symptomGroup)
 	if err != nil {
 		return nil, err
 	}
@@ -1429,8 +1481,8 @@ func (app *Application) createSymptom(current model.User, group string, name str
 	return symptom, nil
 }
 
-func (app *Application) updateSymptom(current model.User, group string, ID string, name string) (*model.Symptom, error) {
-	symptom, err := app.storage.FindSymptom(ID)
+func (app *Application) updateSymptom(ctx context.Context, current model.User, group string, ID string, name string) (*model.Symptom, error) {
+	symptom, err := app.storage.FindSymptom(ctx, ID)
 	if err != nil {
 		return nil, err
 	}
@@ -1442,7 +1494,7 @@ func (app *Application) updateSymptom(current model.User, group string, ID strin
 	symptom.Name = name
 
 	//save it
-	err = app.storage.SaveSymptom(symptom)
+	err = app.storage.SaveSymptom(ctx, symptom)
 	if err != nil {
 		return nil, err
 	}
@@ -1455,8 +1507,8 @@ func (app *Application) updateSymptom(current model.User, group string, ID strin
 	return symptom, nil
 }
 
-func (app *Application) deleteSymptom(current model.User, group string, ID string) error {
-	err := app.storage.DeleteSymptom(ID)
+func (app *Application) deleteSymptom(ctx context.Context, current model.User, group string, ID string) error {
+	err := app.storage.DeleteSymptom(ctx, ID)
 	if err != nil {
 		return err
 	}
@@ -1467,23 +1519,23 @@ func (app *Application) deleteSymptom(current model.User, group string, ID strin
 	return nil
 }
 
-func (app *Application) getSymptomRules() ([]*model.SymptomRule, error) {
-	symptomRules, err := app.storage.ReadAllSymptomRules()
+func (app *Application) getSymptomRules(ctx context.Context) ([]*model.SymptomRule, error) {
+	symptomRules, err := app.storage.ReadAllSymptomRules(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return symptomRules, nil
 }
 
-func (app *Application) createSymptomRule(current model.User, group string, countyID string, gr1Count int, gr2Count int, items []model.SymptomRuleItem) (*model.SymptomRule, error) {
+func (app *Application) createSymptomRule(ctx context.Context, current model.User, group string, countyID string, gr1Count int, gr2Count int, items []model.SymptomRuleItem) (*model.SymptomRule, error) {
 	// validate the data
-	err := app.validateSymptomRuleData(countyID, gr1Count, gr2Count, items)
+	err := app.validateSymptomRuleData(ctx, countyID, gr1Count, gr2Count, items)
 	if err != nil {
 		return nil, err
 	}
 
 	//create it
-	symptomRule, err := app.storage.CreateSymptomRule(countyID, gr1Count, gr2Count, items)
+	symptomRule, err := app.storage.CreateSymptomRule(ctx, countyID, gr1Count, gr2Count, items)
 	if err != nil {
 		return nil, err
 	}
@@ -1497,9 +1549,9 @@ func (app *Application) createSymptomRule(current model.User, group string, coun
 	return symptomRule, nil
 }
 
-func (app *Application) updateSymptomRule(current model.User, group string, ID string, countyID string, gr1Count int, gr2Count int, items []model.SymptomRuleItem) (*model.SymptomRule, error) {
+func (app *Application) updateSymptomRule(ctx context.Context, current model.User, group string, ID string, countyID string, gr1Count int, gr2Count int, items []model.SymptomRuleItem) (*model.SymptomRule, error) {
 	//1. find the symptom rule
-	symptomRule, err := app.storage.FindSymptomRule(ID)
+	symptomRule, err := app.storage.FindSymptomRule(ctx, ID)
 	if err != nil {
 		return nil, err
 	}
@@ -1508,7 +1560,7 @@ func (app *Application) updateSymptomRule(current model.User, group string, ID s
 	}
 
 	// validate the data
-	err = app.validateSymptomRuleData(countyID, gr1Count, gr2Count, items)
+	err = app.validateSymptomRuleData(ctx, countyID, gr1Count, gr2Count, items)
 	if err != nil {
 		return nil, err
 	}
@@ -1520,7 +1572,7 @@ func (app *Application) updateSymptomRule(current model.User, group string, ID s
 	symptomRule.Items = items
 
 	//4. Save it
-	err = app.storage.SaveSymptomRule(symptomRule)
+	err = app.storage.SaveSymptomRule(ctx, symptomRule)
 	if err != nil {
 		return nil, err
 	}
@@ -1534,9 +1586,9 @@ func (app *Application) updateSymptomRule(current model.User, group string, ID s
 	return symptomRule, nil
 }
 
-func (app *Application) validateSymptomRuleData(countyID string, gr1Count int, gr2Count int, items []model.SymptomRuleItem) error {
+func (app *Application) validateSymptomRuleData(ctx context.Context, countyID string, gr1Count int, gr2Count int, items []model.SymptomRuleItem) error {
 	//check if there is a county for the provided id
-	county, err := app.storage.FindCounty(countyID)
+	county, err := app.storage.FindCounty(ctx, countyID)
 	if err != nil {
 		return err
 	}
@@ -1583,8 +1635,8 @@ func (app *Application) areGr1Gr2Valid(gr1 bool, gr2 bool, items []model.Symptom
 	return false
 }
 
-func (app *Application) deleteSymptomRule(current model.User, group string, ID string) error {
-	err := app.storage.DeleteSymptomRule(ID)
+func (app *Application) deleteSymptomRule(ctx context.Context, current model.User, group string, ID string) error {
+	err := app.storage.DeleteSymptomRule(ctx, ID)
 	if err != nil {
 		return err
 	}
@@ -1596,40 +1648,40 @@ func (app *Application) deleteSymptomRule(current model.User, group string, ID s
 	return nil
 }
 
-func (app *Application) getManualTestByCountyID(countyID string, status *string) ([]*model.EManualTest, error) {
-	manualTests, err := app.storage.FindManualTestsByCountyIDDeep(countyID, status)
+func (app *Application) getManualTestByCountyID(ctx context.Context, countyID string, status *string) ([]*model.EManualTest, error) {
+	manualTests, err := app.storage.FindManualTestsByCountyIDDeep(ctx, countyID, status)
 	if err != nil {
 		return nil, err
 	}
 	return manualTests, nil
 }
 
-func (app *Application) processManualTest(ID string, status string, encryptedKey *string, encryptedBlob *string) error {
-	err := app.storage.ProcessManualTest(ID, status, encryptedKey, encryptedBlob)
+func (app *Application) processManualTest(ctx context.Context, ID string, status string, encryptedKey *string, encryptedBlob *string) error {
+	err := app.storage.ProcessManualTest(ctx, ID, status, encryptedKey, encryptedBlob)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (app *Application) getManualTestImage(ID string) (*string, *string, error) {
-	encryptedImageKey, encryptedImageBlob, err := app.storage.FindManualTestImage(ID)
+func (app *Application) getManualTestImage(ctx context.Context, ID string) (*string, *string, error) {
+	encryptedImageKey, encryptedImageBlob, err := app.storage.FindManualTestImage(ctx, ID)
 	if err != nil {
 		return nil, nil, err
 	}
 	return encryptedImageKey, encryptedImageBlob, nil
 }
 
-func (app *Application) getAccessRules() ([]*model.AccessRule, error) {
-	accessRules, err := app.storage.ReadAllAccessRules()
+func (app *Application) getAccessRules(ctx context.Context) ([]*model.AccessRule, error) {
+	accessRules, err := app.storage.ReadAllAccessRules(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return accessRules, nil
 }
 
-func (app *Application) createAccessRule(current model.User, group string, audit *string, countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error) {
-	accessRule, err := app.storage.CreateAccessRule(countyID, rules)
+func (app *Application) createAccessRule(ctx context.Context, current model.User, group string, audit *string, countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error) {
+	accessRule, err := app.storage.CreateAccessRule(ctx, countyID, rules)
 	if err != nil {
 		return nil, err
 	}
@@ -1642,8 +1694,8 @@ func (app *Application) createAccessRule(current model.User, group string, audit
 	return accessRule, nil
 }
 
-func (app *Application) updateAccessRule(current model.User, group string, audit *string, ID string, countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error) {
-	accessRule, err := app.storage.UpdateAccessRule(ID, countyID, rules)
+func (app *Application) updateAccessRule(ctx context.Context, current model.User, group string, audit *string, ID string, countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error) {
+	accessRule, err := app.storage.UpdateAccessRule(ctx, ID, countyID, rules)
 	if err != nil {
 		return nil, err
 	}
@@ -1656,8 +1708,8 @@ func (app *Application) updateAccessRule(current model.User, group string, audit
 	return accessRule, nil
 }
 
-func (app *Application) deleteAccessRule(current model.User, group string, ID string) error {
-	err := app.storage.DeleteAccessRule(ID)
+func (app *Application) deleteAccessRule(ctx context.Context, current model.User, group string, ID string) error {
+	err := app.storage.DeleteAccessRule(ctx, ID)
 	if err != nil {
 		return err
 	}
@@ -1668,17 +1720,17 @@ func (app *Application) deleteAccessRule(current model.User, group string, ID st
 	return nil
 }
 
-func (app *Application) getUserByExternalID(externalID string) (*model.User, error) {
-	user, err := app.storage.FindUserByExternalID(externalID)
+func (app *Application) getUserByExternalID(ctx context.Context, externalID string) (*model.User, error) {
+	user, err := app.storage.FindUserByExternalID(ctx, externalID)
 	if err != nil {
 		return nil, err
 	}
 	return user, nil
 }
 
-func (app *Application) createAction(current model.User, group string, audit *string, providerID string, userID string, encryptedKey string, encryptedBlob string) (*model.CTest, error) {
+func (app *Application) createAction(ctx context.Context, current model.User, group string, audit *string, providerID string, userID string, encryptedKey string, encryptedBlob string) (*model.CTest, error) {
 	//1. create a ctest
-	item, user, err := app.storage.CreateAdminCTest(providerID, userID, encryptedKey, encryptedBlob, false, nil)
+	item, user, err := app.storage.CreateAdminCTest(ctx, providerID, userID, encryptedKey, encryptedBlob, false, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1690,7 +1742,9 @@ func (app *Application) createAction(current model.User, group string, audit *st
 			return
 		}
 		//1. load the user data, we need the fcm tokens
-		userData, err := app.profileBB.LoadUserData(user.UUID)
+		loadCtx, cancel := context.WithTimeout(context.Background(), notificationTimeout)
+		defer cancel()
+		userData, err := app.profileBB.LoadUserData(loadCtx, userUUID)
 		if err != nil {
 			log.Printf("Error loading user data - %s\n", err)
 			return
@@ -1716,7 +1770,7 @@ func (app *Application) createAction(current model.User, group string, audit *st
 	return item, nil
 }
 
-func (app *Application) getAudit(current model.User, group string, userIdentifier *string, entity *string, entityID *string, operation *string,
+func (app *Application) getAudit(ctx context.Context, current model.User, group string, userIdentifier *string, entity *string, entityID *string, operation *string,
 	clientData *string, createdAt *time.Time, sortBy *string, asc *bool, limit *int64) ([]*AuditEntity, error) {
 
 	//Admin can look all logs
@@ -18,12 +18,11 @@
 package core
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"health/core/model"
 	"health/utils"
 	"log"
-	"strings"
 	"sync"
 	"time"
 )
@@ -49,8 +48,18 @@ type Application struct {
 	cachedCovid19Config *model.COVID19Config
 
 	//cache app versions
-	avLock            *sync.RWMutex
-	cachedAppVersions []string
+	avLock                    *sync.RWMutex
+	cachedAppVersions         []string
+	cachedAppVersionsStatuses map[string]string
+
+	//cache reference data - counties, rules, locations etc
+	cache *referenceDataCache
+
+	//one resources sync at a time
+	rsLock *sync.Mutex
+
+	//signs the erasure receipts
+	erasureSigningKey []byte
 
 	listeners []ApplicationListener
 }
@@ -67,11 +76,15 @@ func (app *Application) Start() {
 	//cache the app versions
 	app.loadAppVersions()
 
-	go app.loadNewsData()
-	//Disable the resource data loading as we cannot map the new created data
-	//go app.loadResourcesData()
+	go app.setupNewsTimer()
+
+	go app.setupResourcesTimer()
 
 	go app.setupLocationWaitTimeColorTimer()
+
+	go app.setupRetentionTimer()
+
+	go app.setupAppointmentRemindersTimer()
 }
 
 //AddListener adds application listener
@@ -129,32 +142,46 @@ func (app *Application) setupLocationWaitTimeColorTimer() {
 
 func (app *Application) checkLocationsWaitTimesColors() {
 	log.Println("Application -> checkLocationsWaitTimesColors")
+	ctx := context.Background()
 
 	// load locations
-	locations, err := app.storage.ReadAllLocations()
+	locations, err := app.storage.ReadAllLocations(ctx)
 	if err != nil {
 		log.Printf("error loading locations for wait time color check - %s", err)
 	}
 
 	for _, loc := range locations {
-		app.checkLocationWaitTimeColor(loc)
+		app.checkLocationWaitTimeColor(ctx, loc)
 	}
 }
 
-func (app *Application) checkLocationWaitTimeColor(location *model.Location) {
+func (app *Application) checkLocationWaitTimeColor(ctx context.Context, location *model.Location) {
 	log.Printf("Application -> checkLocationWaitTimeColor for %s with timezone %s", location.Name, location.Timezone)
 
-	//find the day of the week and the passed seconds within the day
-	timeLocation, err := time.LoadLocation(location.Timezone)
-	if err != nil {
-		log.Printf("Error getting time location:%s\n", err.Error())
+	//find the day of the week and the passed seconds within the day, utc is used if the timezone is not valid
+	now := locationTime(location, time.Now())
+	log.Printf("... -> now week day - %s, now date - %s\n", now.Weekday(), now.Format(locationExceptionDateLayout))
+
+	isLocationOpen := app.isLocationOpen(location, now)
+	if isLocationOpen && location.WaitTime != nil {
+		//the reported wait time color is shown while the report is not stale, then it goes back to green
+		waitTimeColor := location.WaitTime.Color
+		stale := app.isWaitTimeStale(*location.WaitTime, now)
+		if stale {
+			waitTimeColor = waitTimeColorGreen
+		}
+		if !stale || (location.WaitTimeColor != nil && *location.WaitTimeColor == location.WaitTime.Color) {
+			if location.WaitTimeColor == nil || *location.WaitTimeColor != waitTimeColor {
+				log.Printf("... -> %s is OPEN, setting the reported wait time color %s, stale - %t\n", location.Name, waitTimeColor, stale)
+				location.WaitTimeColor = &waitTimeColor
+				err := app.storage.SaveLocation(ctx, location)
+				if err != nil {
+					log.Printf("error saving a location after setting the reported wait time color - %s", err)
+				}
+			}
+			return
+		}
 	}
-	now := time.Now().In(timeLocation)
-	nowWeekDay := now.Weekday()
-	nowMomentInSec := (now.Hour() * 60 * 60) + (now.Minute() * 60) + now.Second()
-	log.Printf("... -> now week day - %s, now moment in secs - %d\n", nowWeekDay, nowMomentInSec)
-
-	isLocationOpen := app.isLocationOpen(location, nowWeekDay.String(), nowMomentInSec)
 	if isLocationOpen {
 		log.Printf("... -> %s is OPEN, set it to green only if nil or grey\n", location.Name)
 		if location.WaitTimeColor == nil || *location.WaitTimeColor == "grey" {
@@ -162,7 +189,7 @@ func (app *Application) checkLocationWaitTimeColor(location *model.Location) {
 
 			waitTimeColor := "green"
 			location.WaitTimeColor = &waitTimeColor
-			err = app.storage.SaveLocation(location)
+			err := app.storage.SaveLocation(ctx, location)
 			if err != nil {
 				log.Printf("error saving a location after setting green wait time color - %s", err)
 			} else {
@@ -178,7 +205,7 @@ func (app *Application) checkLocationWaitTimeColor(location *model.Location) {
 
 			waitTimeColor := "grey"
 			location.WaitTimeColor = &waitTimeColor
-			err = app.storage.SaveLocation(location)
+			err := app.storage.SaveLocation(ctx, location)
 			if err != nil {
 				log.Printf("error saving a location after setting grey wait time color - %s", err)
 			} else {
@@ -191,44 +218,32 @@ func (app *Application) checkLocationWaitTimeColor(location *model.Location) {
 
 }
 
-func (app *Application) isLocationOpen(location *model.Location, day string, passedSeconds int) bool {
-	daysOfOperations := location.DaysOfOperation
-	if len(daysOfOperations) == 0 {
-		return false
-	}
+//isLocationOpen checks if the location is open at the moment. The moment must be in the location timezone.
+//The exception for the date is used instead of the days of operation if there is such.
+func (app *Application) isLocationOpen(location *model.Location, now time.Time) bool {
+	passedSeconds := daySeconds(now)
 
-	//check if the location is open this day
-	var operationDay *model.OperationDay
-	for _, current := range daysOfOperations {
-		if day == current.Name {
-			operationDay = &current
-			break
+	//check if it is still open from the previous day
+	_, previousIntervals := operationIntervals(location, now.AddDate(0, 0, -1))
+	for _, interval := range previousIntervals {
+		if isOpenAfterMidnight(interval, passedSeconds) {
+			log.Printf("... -> %s is open from the previous day until %s", location.Name, interval.CloseTime)
+			return true
 		}
 	}
-	if operationDay == nil {
-		return false
-	}
 
-	//check if the location is open this moment in the day
-	openTime, err := time.Parse("03:04pm", operationDay.OpenTime)
-	if err != nil {
-		log.Printf("error parsing open time - %s", openTime)
-	}
-	openTimeInSec := (openTime.Hour() * 60 * 60) + (openTime.Minute() * 60) + openTime.Second()
-	log.Printf("... open moment is secs - %d", openTimeInSec)
-
-	closeTime, err := time.Parse("03:04pm", operationDay.CloseTime)
-	if err != nil {
-		log.Printf("error parsing close time - %s", closeTime)
+	//check if it is open this moment in the day
+	allDay, intervals := operationIntervals(location, now)
+	if allDay {
+		return true
 	}
-	closeTimeInSec := (closeTime.Hour() * 60 * 60) + (closeTime.Minute() * 60) + closeTime.Second()
-	log.Printf("... close moment is secs - %d", closeTimeInSec)
-	if !(passedSeconds >= openTimeInSec && passedSeconds < closeTimeInSec) {
-		return false
+	for _, interval := range intervals {
+		if isOpenAt(interval, passedSeconds) {
+			log.Printf("... -> %s is open in %s-%s", location.Name, interval.OpenTime, interval.CloseTime)
+			return true
+		}
 	}
-
-	//it is open
-	return true
+	return false
 }
 
 func (app *Application) notifyListeners(message string, data interface{}) {
@@ -244,9 +259,14 @@ func (app *Application) notifyListeners(message string, data interface{}) {
 }
 
 func (app *Application) checkAppVersion(v *string) (*string, error) {
+	versions := app.getCachedAppVersions()
+	if len(versions) == 0 {
+		return nil, errors.New("Not supported version")
+	}
+
 	//use the latest version if not provided
 	if v == nil {
-		latest := app.getLatestVersion()
+		latest := versions[0]
 		return &latest, nil
 	}
 
@@ -258,7 +278,7 @@ func (app *Application) checkAppVersion(v *string) (*string, error) {
 
 	//if it does not match then use the latest one which is less that the desired one
 	//the versions are sorted as the latest one is on possition 0
-	for _, current := range app.getCachedAppVersions() {
+	for _, current := range versions {
 		if utils.IsVersionLess(current, *v) {
 			return &current, nil
 		}
@@ -267,28 +287,23 @@ func (app *Application) checkAppVersion(v *string) (*string, error) {
 	return nil, errors.New("Not supported version")
 }
 
-func (app *Application) getLatestVersion() string {
-	//the versions list is sorted, the first element is the latest one
-	return app.getCachedAppVersions()[0]
+func (app *Application) isVersionSupported(v string) (bool, *string) {
+	return matchVersion(v, app.getCachedAppVersions())
 }
 
-func (app *Application) isVersionSupported(v string) (bool, *string) {
-	//if the input is 2.8.0 then we search for 2.8 because the system works with the short view when patch is 0
-	forSearch := v
-	elements := strings.Split(v, ".")
-	elementsCount := len(elements)
-	if !(elementsCount == 2 || elementsCount == 3) {
+//matchVersion finds the version in the provided versions list by the semver precedence - 2.8.0 and 2.8.0+build are 2.8
+func matchVersion(v string, versions []string) (bool, *string) {
+	version, err := utils.ParseVersion(v)
+	if err != nil {
 		return false, nil
 	}
-	lastElement := elements[elementsCount-1]
-	if elementsCount == 3 && lastElement == "0" {
-		forSearch = fmt.Sprintf("%s.%s", elements[0], elements[1])
-	}
 
 	//search for it
-	for _, current := range app.getCachedAppVersions() {
-		if current == forSearch {
-			return true, &current
+	for _, current := range versions {
+		currentVersion, err := utils.ParseVersion(current)
+		if err == nil && currentVersion.Compare(*version) == 0 {
+			result := current
+			return true, &result
 		}
 	}
 	return false, nil
@@ -296,18 +311,28 @@ func (app *Application) isVersionSupported(v string) (bool, *string) {
 
 func (app *Application) loadAppVersions() {
 	log.Println("Load App versions")
+	ctx := context.Background()
 
-	versions, err := app.storage.ReadAllAppVersions()
+	versions, err := app.storage.ReadAllAppVersions(ctx)
 	if err != nil {
 		log.Printf("Error reading the app versions %s", err)
 	}
-	app.setCachedAppVersions(versions)
+	details, err := app.storage.FindAppVersions(ctx)
+	if err != nil {
+		log.Printf("Error reading the app versions statuses %s", err)
+	}
+	statuses := make(map[string]string, len(details))
+	for _, item := range details {
+		statuses[item.Version] = item.Status
+	}
+	app.setCachedAppVersions(versions, statuses)
 }
 
-func (app *Application) setCachedAppVersions(versions []string) {
-	app.avLock.RLock()
+func (app *Application) setCachedAppVersions(versions []string, statuses map[string]string) {
+	app.avLock.Lock()
 	app.cachedAppVersions = versions
-	app.avLock.RUnlock()
+	app.cachedAppVersionsStatuses = statuses
+	app.avLock.Unlock()
 }
 
 func (app *Application) getCachedAppVersions() []string {
@@ -317,10 +342,22 @@ func (app *Application) getCachedAppVersions() []string {
 	return app.cachedAppVersions
 }
 
+func (app *Application) getCachedAppVersionStatus(version string) string {
+	app.avLock.RLock()
+	defer app.avLock.RUnlock()
+
+	status, ok := app.cachedAppVersionsStatuses[version]
+	if !ok || len(status) == 0 {
+		return appVersionStatusSupported
+	}
+	return status
+}
+
 func (app *Application) loadCovid19Config() {
 	log.Println("Load Covid19 config")
+	ctx := context.Background()
 
-	covid19Config, err := app.storage.ReadCovid19Config()
+	covid19Config, err := app.storage.ReadCovid19Config(ctx)
 	if err != nil {
 		log.Printf("Error reading the covid19 config %s", err)
 	}
@@ -340,194 +377,9 @@ func (app *Application) getCachedCovid19Config() *model.COVID19Config {
 	return app.cachedCovid19Config
 }
 
-func (app *Application) loadNewsData() {
-	log.Println("loadNewsData() -> load data from the provider")
-
-	//1. load the provider data
-	providerData, err := app.dataProvider.LoadNews()
-	if err != nil {
-		log.Printf("loadNewsData() -> error on loading the provider data %s", err)
-
-		go app.execNewsTimer()
-		return
-	}
-
-	//2. find the latest news date
-	var latestDate *time.Time
-	newsList, err := app.storage.ReadNews(0)
-	if err != nil {
-		log.Printf("loadNewsData() -> error on finding the latest news date %s", err)
-
-		go app.execNewsTimer()
-		return
-	}
-	if newsList != nil && len(newsList) > 0 {
-		latestItem := newsList[0]
-
-		log.Printf("loadNewsData() -> latest item is %s with date %s", latestItem.Title, latestItem.Date)
-
-		latestDate = &latestItem.Date
-	} else {
-		log.Println("loadNewsData() -> the news list is empty")
-	}
-
-	//3. find only the new items
-	newItems := app.findNewNewsItems(providerData, latestDate)
-
-	var addedNews []*model.News
-
-	//4. store the new items
-	newItemsCount := len(newItems)
-	if newItems != nil && newItemsCount > 0 {
-		log.Printf("loadNewsData() -> there are %d new news items\n", newItemsCount)
-
-		for _, item := range newItems {
-			description := utils.ModifyHTMLContent(item.Description)
-			htmlContent := utils.ModifyHTMLContent(item.ContentEncoded)
-			created, err := app.storage.CreateNews(item.PubDate, item.Title, description, htmlContent, nil)
-			if err != nil {
-				log.Printf("loadNewsData() -> error on saving news - %s\n", item.Title)
-			} else {
-				log.Printf("loadNewsData() -> successfully saved news - %s\n", item.Title)
-				addedNews = append(addedNews, created)
-			}
-		}
-	} else {
-		log.Println("loadNewsData() -> there is no new news items")
-	}
-
-	//stop sending emails
-	//5. notify the recipients for the added items
-	//if len(addedNews) > 0 {
-	//	go app.sender.SendForNews(addedNews)
-	//}
-
-	go app.execNewsTimer()
-}
-
-func (app *Application) execNewsTimer() {
-	periodInMinutes := app.getCachedCovid19Config().NewsUpdatePeriod
-	nextLoad := time.Minute * time.Duration(periodInMinutes)
-	log.Printf("execNewsTimer() -> next exec after %s\n", nextLoad)
-	timer := time.NewTimer(nextLoad)
-	<-timer.C
-	log.Println("execNewsTimer() -> timer expired")
-	app.loadNewsData()
-}
-
-func (app *Application) loadResourcesData() {
-	/*log.Println("loadResourcesData() -> load data from the provider")
-
-	//1. load the provider data
-	providerData, err := app.dataProvider.LoadResources()
-	if err != nil {
-		log.Printf("loadResourcesData() -> error on loading the provider data %s", err)
-
-		go app.execResourcesTimer()
-		return
-	}
-
-	//2. Load the resoruces from the storage. They are prety small size
-	resourceList, err := app.storage.ReadAllResources()
-	if err != nil {
-		log.Printf("loadResourcesData() -> error on reading all the resources %s", err)
-
-		go app.execResourcesTimer()
-		return
-	}
-
-	//3. find only the new items
-	newResources := app.findNewResourcesItems(resourceList, providerData)
-
-	var addedResources []*model.Resource
-
-	//4. store the new items
-	newItemsCount := len(newResources)
-	if newResources != nil && newItemsCount > 0 {
-		log.Printf("loadResourcesData() -> there are %d new resource items\n", newItemsCount)
-
-		for _, item := range newResources {
-			created, err := app.storage.CreateResource(item.Title, item.Link)
-			if err != nil {
-				log.Printf("loadResourcesData() -> error on saving resource - %s\n", item.Title)
-			} else {
-				log.Printf("loadResourcesData() -> successfully saved resource - %s\n", item.Title)
-				addedResources = append(addedResources, created)
-			}
-		}
-	} else {
-		log.Println("loadResourcesData() -> there is no new resource items")
-	}
-
-	//5. notify the recipients for the added resource items
-	if len(addedResources) > 0 {
-		go app.sender.SendForResources(addedResources)
-	}
-
-	go app.execResourcesTimer() */
-
-}
-
-func (app *Application) findNewResourcesItems(list []*model.Resource, providerList []ProviderResource) []ProviderResource {
-	log.Println("findNewResourcesItems() -> start")
-
-	var result []ProviderResource
-
-	if providerList != nil {
-		for _, pItem := range providerList {
-			if !app.containsResourceItem(list, pItem.Link) {
-				result = append(result, pItem)
-			}
-		}
-	}
-
-	log.Println("findNewResourcesItems() -> end")
-	return result
-}
-
-func (app *Application) containsResourceItem(list []*model.Resource, link string) bool {
-	if list != nil {
-		for _, item := range list {
-			if item.Link == link {
-				return true
-			}
-		}
-	}
-	return false
-}
-
-func (app *Application) execResourcesTimer() {
-	nextLoad := time.Hour * 1
-	timer := time.NewTimer(nextLoad)
-	<-timer.C
-	log.Println("execResourcesTimer() -> timer expired")
-	app.loadResourcesData()
-}
-
-func (app *Application) findNewNewsItems(list []ProviderNews, latestDate *time.Time) []ProviderNews {
-	log.Println("findNewNewsItems() -> start")
-
-	if latestDate == nil {
-		//if there is no latest date then it means that the list is empty, so return the full list
-		return list
-	}
-
-	var result []ProviderNews
-	if list != nil {
-		for _, item := range list {
-			if item.PubDate.Unix() > latestDate.Unix() {
-				result = append(result, item)
-			}
-		}
-	}
-
-	log.Println("findNewNewsItems() -> end")
-	return result
-}
-
 //FindUserByShibbolethID finds an user for the provided shibboleth id
-func (app *Application) FindUserByShibbolethID(shibbolethID string) (*model.User, error) {
-	user, err := app.storage.FindUserByShibbolethID(shibbolethID)
+func (app *Application) FindUserByShibbolethID(ctx context.Context, shibbolethID string) (*model.User, error) {
+	user, err := app.storage.FindUserByShibbolethID(ctx, shibbolethID)
 	if err != nil {
 		return nil, err
 	}
@@ -535,8 +387,8 @@ func (app *Application) FindUserByShibbolethID(shibbolethID string) (*model.User
 }
 
 //FindUserByExternalID finds an user for the provided external id
-func (app *Application) FindUserByExternalID(externalID string) (*model.User, error) {
-	user, err := app.storage.FindUserByExternalID(externalID)
+func (app *Application) FindUserByExternalID(ctx context.Context, externalID string) (*model.User, error) {
+	user, err := app.storage.FindUserByExternalID(ctx, externalID)
 	if err != nil {
 		return nil, err
 	}
@@ -544,9 +396,9 @@ func (app *Application) FindUserByExternalID(externalID string) (*model.User, er
 }
 
 //CreateAppUser creates an app user
-func (app *Application) CreateAppUser(externalID string, uuid string, publicKey string,
+func (app *Application) CreateAppUser(ctx context.Context, externalID string, uuid string, publicKey string,
 	consent bool, exposureNotification bool, rePost bool, encryptedKey *string, encryptedBlob *string) (*model.User, error) {
-	user, err := app.storage.CreateUser(nil, externalID, uuid, publicKey, consent, exposureNotification, rePost, encryptedKey, encryptedBlob)
+	user, err := app.storage.CreateUser(ctx, nil, externalID, uuid, publicKey, consent, exposureNotification, rePost, encryptedKey, encryptedBlob)
 	if err != nil {
 		return nil, err
 	}
@@ -555,73 +407,70 @@ func (app *Application) CreateAppUser(externalID string, uuid string, publicKey
 }
 
 //CreateAdminAppUser creates an admin app user
-func (app *Application) CreateAdminAppUser(shibboAuth *model.ShibbolethAuth) (*model.User, error) {
+func (app *Application) CreateAdminAppUser(ctx context.Context, shibboAuth *model.ShibbolethAuth) (*model.User, error) {
 	externalID := "a_" + shibboAuth.Uin //TODO
-	user, err := app.storage.CreateUser(shibboAuth, externalID, "", "", false, false, false, nil, nil)
+	user, err := app.storage.CreateUser(ctx, shibboAuth, externalID, "", "", false, false, false, nil, nil)
 	if err != nil {
 		return nil, err
 	}
 	return user, nil
 }
 
-//UpdateUser updates the user
-func (app *Application) UpdateUser(user *model.User) error {
-	err := app.storage.SaveUser(user)
+//UpdateUser updates the user. A new public key is kept as a new key version, the items encrypted for the previous
+//versions become pending for re-encryption.
+func (app *Application) UpdateUser(ctx context.Context, user *model.User) error {
+	if user.RotatePublicKey(user.PublicKey) {
+		log.Printf("the public key of user %s is rotated to version %d", user.ID, user.KeyVersion)
+	}
+
+	err := app.storage.SaveUser(ctx, user)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (app *Application) getEHistoriesByUserID(userID string) ([]*model.EHistory, error) {
-	histories, err := app.storage.FindEHistories(userID)
+func (app *Application) getEHistoriesByUserID(ctx context.Context, userID string) ([]*model.EHistory, error) {
+	histories, err := app.storage.FindEHistories(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 	return histories, nil
 }
 
-func (app *Application) createЕHistory(userID string, date time.Time, eType string, encryptedKey string, encryptedBlob string) (*model.EHistory, error) {
-	history, err := app.storage.CreateEHistory(userID, date, eType, encryptedKey, encryptedBlob)
+func (app *Application) createЕHistory(ctx context.Context, userID string, keyVersion int, date time.Time, eType string, encryptedKey string, encryptedBlob string) (*model.EHistory, error) {
+	history, err := app.storage.CreateEHistory(ctx, userID, keyVersion, date, eType, encryptedKey, encryptedBlob)
 	if err != nil {
 		return nil, err
 	}
 	return history, nil
 }
 
-func (app *Application) createManualЕHistory(userID string, date time.Time, encryptedKey string, encryptedBlob string, encryptedImageKey *string, encryptedImageBlob *string,
+func (app *Application) createManualЕHistory(ctx context.Context, userID string, keyVersion int, date time.Time, encryptedKey string, encryptedBlob string, encryptedImageKey *string, encryptedImageBlob *string,
 	countyID *string, locationID *string) (*model.EHistory, error) {
-	history, err := app.storage.CreateManualЕHistory(userID, date, encryptedKey, encryptedBlob, encryptedImageKey, encryptedImageBlob, countyID, locationID)
+	history, err := app.storage.CreateManualЕHistory(ctx, userID, keyVersion, date, encryptedKey, encryptedBlob, encryptedImageKey, encryptedImageBlob, countyID, locationID)
 	if err != nil {
 		return nil, err
 	}
 	return history, nil
 }
 
-func (app *Application) getProviders() ([]*model.Provider, error) {
-	providers, err := app.storage.ReadAllProviders()
+func (app *Application) getProviders(ctx context.Context) ([]*model.Provider, error) {
+	providers, err := app.storage.ReadAllProviders(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return providers, nil
 }
 
-func (app *Application) findCounties(f *utils.Filter) ([]*model.County, error) {
-	counties, err := app.storage.FindCounties(f)
+func (app *Application) findCounties(ctx context.Context, f *utils.Filter) ([]*model.County, error) {
+	counties, err := app.storage.FindCounties(ctx, f)
 	if err != nil {
 		return nil, err
 	}
 	return counties, nil
 }
 
-func (app *Application) getCounty(ID string) (*model.County, error) {
-	county, err := app.storage.FindCounty(ID)
-	if err != nil {
-		return nil, err
-	}
-	return county, nil
-}
-
 func (app *Application) containsCountyStatusP(ID string, list []*model.CountyStatus) bool {
 	if list == nil {
 		return false
@@ -646,8 +495,8 @@ func (app *Application) containsCountyStatus(ID string, list []model.CountyStatu
 	return false
 }
 
-func (app *Application) getSymptomGroups() ([]*model.SymptomGroup, error) {
-	symptomGroups, err := app.storage.ReadAllSymptomGroups()
+func (app *Application) getSymptomGroups(ctx context.Context) ([]*model.SymptomGroup, error) {
+	symptomGroups, err := app.storage.ReadAllSymptomGroups(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -655,13 +504,17 @@ func (app *Application) getSymptomGroups() ([]*model.SymptomGroup, error) {
 }
 
 //NewApplication creates new Application
-func NewApplication(version string, build string, dataProvider DataProvider, sender Sender, messaging Messaging, profileBB ProfileBuildingBlock, storage Storage, audit Audit) *Application {
+func NewApplication(version string, build string, dataProvider DataProvider, sender Sender, messaging Messaging, profileBB ProfileBuildingBlock,
+	storage Storage, audit Audit, erasureSigningKey string) *Application {
 	cvLock := &sync.RWMutex{}
 	avLock := &sync.RWMutex{}
+	rsLock := &sync.Mutex{}
+	cache := newReferenceDataCache()
 	listeners := []ApplicationListener{}
 
 	application := Application{version: version, build: build, dataProvider: dataProvider, sender: sender, messaging: messaging,
-		profileBB: profileBB, storage: storage, audit: audit, cvLock: cvLock, avLock: avLock, listeners: listeners}
+		profileBB: profileBB, storage: storage, audit: audit, cvLock: cvLock, avLock: avLock, rsLock: rsLock, cache: cache,
+		erasureSigningKey: []byte(erasureSigningKey), listeners: listeners}
 
 	//add the drivers ports/interfaces
 	application.Services = &servicesImpl{app: &application}
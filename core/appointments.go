@@ -0,0 +1,479 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"health/core/model"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	appointmentStatusBooked    = "booked"
+	appointmentStatusCancelled = "cancelled"
+	appointmentStatusCompleted = "completed" //a booked appointment which has ended, set when the user books again at the location
+
+	maxAppointmentSlotMinutes = 240
+	maxAppointmentCapacity    = 1000
+	maxAppointmentDaysAhead   = 90
+
+	appointmentReminderBefore    = 24 * time.Hour
+	appointmentRemindersJobDelay = 5 * time.Minute
+	appointmentRemindersPeriod   = 15 * time.Minute
+
+	appointmentTimeLayout = "Monday, January 2 at 03:04pm"
+)
+
+//updateLocationAppointmentSettings sets how the appointment slots of the location are generated. The location does not offer
+//appointments if the settings are nil, the booked appointments are kept.
+func (app *Application) updateLocationAppointmentSettings(ctx context.Context, current model.User, group string, audit *string,
+	ID string, settings *model.AppointmentSettings) (*model.Location, error) {
+	location, err := app.storage.FindLocation(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	if location == nil {
+		return nil, errors.New("there is no a location for the provided id")
+	}
+
+	err = checkAppointmentSettings(settings)
+	if err != nil {
+		return nil, err
+	}
+	err = app.storage.UpdateLocationAppointmentSettings(ctx, ID, settings)
+	if err != nil {
+		return nil, err
+	}
+	location.AppointmentSettings = settings
+
+	//audit
+	userIdentifier, userInfo := current.GetLogData()
+	lData := []AuditDataEntry{{Key: "settings", Value: fmt.Sprint(settings)}}
+	defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "location-appointment-settings", ID, lData, audit)
+
+	return location, nil
+}
+
+func checkAppointmentSettings(settings *model.AppointmentSettings) error {
+	if settings == nil {
+		return nil
+	}
+	if settings.SlotMinutes < 5 || settings.SlotMinutes > maxAppointmentSlotMinutes {
+		return fmt.Errorf("the slot minutes must be between 5 and %d", maxAppointmentSlotMinutes)
+	}
+	if settings.Capacity < 1 || settings.Capacity > maxAppointmentCapacity {
+		return fmt.Errorf("the capacity must be between 1 and %d", maxAppointmentCapacity)
+	}
+	if settings.DaysAhead < 1 || settings.DaysAhead > maxAppointmentDaysAhead {
+		return fmt.Errorf("the days ahead must be between 1 and %d", maxAppointmentDaysAhead)
+	}
+	return nil
+}
+
+//getAppointmentSlots gives the slots of the location which can be booked with the number of the booked appointments for every one
+func (app *Application) getAppointmentSlots(ctx context.Context, locationID string) ([]model.AppointmentSlot, error) {
+	location, err := app.findAppointmentsLocation(ctx, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	end := appointmentsPeriodEnd(location, now)
+	slots := appointmentSlots(location, now, end)
+	appointments, err := app.storage.FindLocationAppointments(ctx, locationID, now, end)
+	if err != nil {
+		return nil, err
+	}
+	booked := make(map[int64]int, len(slots))
+	for _, appointment := range appointments {
+		booked[appointment.Start.Unix()]++
+	}
+	for i, slot := range slots {
+		slots[i].Booked = booked[slot.Start.Unix()]
+	}
+	return slots, nil
+}
+
+//bookAppointment books an appointment for the slot which starts at the provided time. The user can have only one booked appointment
+//at a location, it must be rescheduled instead. The booked appointments at the location which have ended are completed.
+func (app *Application) bookAppointment(ctx context.Context, current model.User, locationID string, start time.Time) (*model.Appointment, error) {
+	location, err := app.findAppointmentsLocation(ctx, locationID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	slot := findAppointmentSlot(location, start, now)
+	if slot == nil {
+		return nil, errors.New("there is no an appointment slot for the provided start")
+	}
+
+	userAppointments, err := app.storage.FindUserAppointments(ctx, current.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range userAppointments {
+		if item.LocationID != locationID || item.Status != appointmentStatusBooked {
+			continue
+		}
+		if item.End.After(now) {
+			return nil, errors.New("there is a booked appointment at this location, reschedule it instead")
+		}
+		//the storage allows only one booked appointment per user and location
+		err = app.storage.SetAppointmentStatus(ctx, item.ID, appointmentStatusCompleted)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+	//there is no reminder if the confirmation is close enough to the appointment
+	appointment := &model.Appointment{ID: id.String(), LocationID: locationID, UserID: current.ID, Start: slot.Start.UTC(), End: slot.End.UTC(),
+		Status: appointmentStatusBooked, ReminderSent: slot.Start.Sub(now) <= appointmentReminderBefore, DateCreated: now.UTC()}
+	created, err := app.storage.CreateAppointment(ctx, appointment, slot.Capacity)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, errors.New("the appointment slot is full")
+	}
+
+	app.sendAppointmentNotification(current.UUID, *appointment, "appointment-booked",
+		fmt.Sprintf("Your appointment at %s is booked for %s", location.Name, locationTime(location, slot.Start).Format(appointmentTimeLayout)))
+
+	return appointment, nil
+}
+
+//rescheduleAppointment moves the appointment of the user to the slot which starts at the provided time
+func (app *Application) rescheduleAppointment(ctx context.Context, current model.User, ID string, start time.Time) (*model.Appointment, error) {
+	appointment, err := app.findUserBookedAppointment(ctx, current, ID)
+	if err != nil {
+		return nil, err
+	}
+	location, err := app.findAppointmentsLocation(ctx, appointment.LocationID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	slot := findAppointmentSlot(location, start, now)
+	if slot == nil {
+		return nil, errors.New("there is no an appointment slot for the provided start")
+	}
+	if slot.Start.Equal(appointment.Start) {
+		//nothing to change
+		return appointment, nil
+	}
+
+	previousStart := appointment.Start
+	appointment.Start = slot.Start.UTC()
+	appointment.End = slot.End.UTC()
+	appointment.ReminderSent = slot.Start.Sub(now) <= appointmentReminderBefore
+	dateUpdated := now.UTC()
+	appointment.DateUpdated = &dateUpdated
+	rescheduled, err := app.storage.RescheduleAppointment(ctx, appointment, previousStart, slot.Capacity)
+	if err != nil {
+		return nil, err
+	}
+	if !rescheduled {
+		return nil, errors.New("the appointment slot is full")
+	}
+
+	app.sendAppointmentNotification(current.UUID, *appointment, "appointment-rescheduled",
+		fmt.Sprintf("Your appointment at %s is rescheduled for %s", location.Name, locationTime(location, slot.Start).Format(appointmentTimeLayout)))
+
+	return appointment, nil
+}
+
+//cancelAppointment cancels the appointment of the user, its place in the slot becomes free
+func (app *Application) cancelAppointment(ctx context.Context, current model.User, ID string) (*model.Appointment, error) {
+	appointment, err := app.findUserBookedAppointment(ctx, current, ID)
+	if err != nil {
+		return nil, err
+	}
+	err = app.storage.CancelAppointment(ctx, appointment)
+	if err != nil {
+		return nil, err
+	}
+	dateUpdated := time.Now().UTC()
+	appointment.Status = appointmentStatusCancelled
+	appointment.DateUpdated = &dateUpdated
+
+	//the location could be deleted
+	locationName := "the testing location"
+	location, err := app.storage.FindLocation(ctx, appointment.LocationID)
+	if err == nil && location != nil {
+		locationName = location.Name
+	}
+	app.sendAppointmentNotification(current.UUID, *appointment, "appointment-cancelled",
+		fmt.Sprintf("Your appointment at %s on %s is cancelled", locationName, appointmentStartText(location, appointment.Start)))
+
+	return appointment, nil
+}
+
+//cancelLocationAppointments cancels the upcoming appointments at the location and notifies the users
+func (app *Application) cancelLocationAppointments(ctx context.Context, location *model.Location) {
+	now := time.Now()
+	appointments, err := app.storage.FindLocationAppointments(ctx, location.ID, now, now.AddDate(0, 0, maxAppointmentDaysAhead+1))
+	if err != nil {
+		log.Printf("error finding the appointments of the location %s - %s", location.ID, err)
+		return
+	}
+	for _, appointment := range appointments {
+		err = app.storage.CancelAppointment(ctx, appointment)
+		if err != nil {
+			log.Printf("error cancelling the appointment %s - %s", appointment.ID, err)
+			continue
+		}
+		user, err := app.storage.FindUser(ctx, appointment.UserID)
+		if err != nil || user == nil {
+			log.Printf("error finding the user for the appointment %s - %v", appointment.ID, err)
+			continue
+		}
+		app.sendAppointmentNotification(user.UUID, *appointment, "appointment-cancelled",
+			fmt.Sprintf("Your appointment at %s on %s is cancelled, the location is closed", location.Name, appointmentStartText(location, appointment.Start)))
+	}
+}
+
+func (app *Application) getUserAppointments(ctx context.Context, current model.User) ([]*model.Appointment, error) {
+	return app.storage.FindUserAppointments(ctx, current.ID)
+}
+
+//getLocationAppointments gives the booked appointments of the location for the date in the location time zone, today if the date is empty
+func (app *Application) getLocationAppointments(ctx context.Context, locationID string, date string) ([]*model.Appointment, error) {
+	location, err := app.storage.FindLocation(ctx, locationID)
+	if err != nil {
+		return nil, err
+	}
+	if location == nil {
+		return nil, errors.New("there is no a location for the provided id")
+	}
+
+	today := locationTime(location, time.Now())
+	from := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+	if len(date) > 0 {
+		from, err = time.ParseInLocation(locationExceptionDateLayout, date, today.Location())
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %s, it must be yyyy-mm-dd", date)
+		}
+	}
+	return app.storage.FindLocationAppointments(ctx, locationID, from, from.AddDate(0, 0, 1))
+}
+
+//findAppointmentsLocation gives the location if it offers appointments
+func (app *Application) findAppointmentsLocation(ctx context.Context, ID string) (*model.Location, error) {
+	location, err := app.storage.FindLocation(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	if location == nil {
+		return nil, errors.New("there is no a location for the provided id")
+	}
+	if location.AppointmentSettings == nil {
+		return nil, errors.New("the location does not offer appointments")
+	}
+	return location, nil
+}
+
+//findUserBookedAppointment gives the appointment if it is of the user and it is booked
+func (app *Application) findUserBookedAppointment(ctx context.Context, current model.User, ID string) (*model.Appointment, error) {
+	appointment, err := app.storage.FindAppointment(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	if appointment == nil || appointment.UserID != current.ID {
+		return nil, errors.New("there is no an appointment for the provided id")
+	}
+	if appointment.Status != appointmentStatusBooked {
+		return nil, errors.New("the appointment is " + appointment.Status)
+	}
+	if !appointment.Start.After(time.Now()) {
+		return nil, errors.New("the appointment has already started")
+	}
+	return appointment, nil
+}
+
+//appointmentSlots gives the slots of the location which start in the period. They are generated from the operation intervals
+//of every day, the exceptions included, and a slot must end until the close time.
+func appointmentSlots(location *model.Location, from time.Time, to time.Time) []model.AppointmentSlot {
+	settings := location.AppointmentSettings
+	if settings == nil {
+		return nil
+	}
+	slotDuration := time.Duration(settings.SlotMinutes) * time.Minute
+
+	var result []model.AppointmentSlot
+	localFrom := locationTime(location, from)
+	//start from the previous day because of the intervals which cross midnight
+	for date := time.Date(localFrom.Year(), localFrom.Month(), localFrom.Day()-1, 0, 0, 0, 0, localFrom.Location()); date.Before(to); date = date.AddDate(0, 0, 1) {
+		allDay, intervals := operationIntervals(location, date)
+
+		//the open and the close seconds from the start of the date
+		var periods [][2]int
+		if allDay {
+			periods = append(periods, [2]int{0, 24 * 60 * 60})
+		}
+		for _, interval := range intervals {
+			openTime, closeTime, err := parseOperationInterval(interval.OpenTime, interval.CloseTime)
+			if err != nil {
+				log.Printf("error parsing operation interval - %s", err)
+				continue
+			}
+			if closeTime < openTime {
+				closeTime += 24 * 60 * 60
+			}
+			periods = append(periods, [2]int{openTime, closeTime})
+		}
+
+		for _, period := range periods {
+			closeAt := dateWithSeconds(date, period[1])
+			for start := dateWithSeconds(date, period[0]); !start.Add(slotDuration).After(closeAt); start = start.Add(slotDuration) {
+				if start.Before(from) || !start.Before(to) {
+					continue
+				}
+				result = append(result, model.AppointmentSlot{Start: start, End: start.Add(slotDuration), Capacity: settings.Capacity})
+			}
+		}
+	}
+	return result
+}
+
+//findAppointmentSlot gives the slot which starts at the provided time if it can be booked
+func findAppointmentSlot(location *model.Location, start time.Time, now time.Time) *model.AppointmentSlot {
+	if start.Before(now) || !start.Before(appointmentsPeriodEnd(location, now)) {
+		return nil
+	}
+	slots := appointmentSlots(location, start, start.Add(time.Second))
+	for _, slot := range slots {
+		if slot.Start.Equal(start) {
+			return &slot
+		}
+	}
+	return nil
+}
+
+//appointmentsPeriodEnd gives the end of the last day which can be booked
+func appointmentsPeriodEnd(location *model.Location, now time.Time) time.Time {
+	today := locationTime(location, now)
+	return time.Date(today.Year(), today.Month(), today.Day()+location.AppointmentSettings.DaysAhead, 0, 0, 0, 0, today.Location())
+}
+
+func dateWithSeconds(date time.Time, seconds int) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, seconds, 0, date.Location())
+}
+
+func appointmentStartText(location *model.Location, start time.Time) string {
+	if location == nil {
+		return start.UTC().Format(appointmentTimeLayout) + " UTC"
+	}
+	return locationTime(location, start).Format(appointmentTimeLayout)
+}
+
+//sendAppointmentNotification sends a firebase notification to the user about the appointment
+func (app *Application) sendAppointmentNotification(userUUID string, appointment model.Appointment, notificationType string, body string) {
+	go func() {
+		if len(userUUID) <= 0 {
+			log.Println("user uuid is empty")
+			return
+		}
+		//1. load the user data, we need the fcm tokens
+		loadCtx, cancel := context.WithTimeout(context.Background(), notificationTimeout)
+		defer cancel()
+		userData, err := app.profileBB.LoadUserData(loadCtx, userUUID)
+		if err != nil {
+			log.Printf("Error loading user data - %s\n", err)
+			return
+		}
+
+		//2. send notification message
+		data := make(map[string]string)
+		data["type"] = "health.covid19.notification"
+		data["health.covid19.notification.type"] = notificationType
+		data["appointment_id"] = appointment.ID
+		data["location_id"] = appointment.LocationID
+		data["title"] = "Testing appointment"
+		data["body"] = body
+		data["click_action"] = "FLUTTER_NOTIFICATION_CLICK"
+		app.messaging.SendNotificationMessage(userData.FCMTokens, "Testing appointment", body, data)
+	}()
+}
+
+func (app *Application) setupAppointmentRemindersTimer() {
+	log.Printf("Application -> setupAppointmentRemindersTimer -> start after - %s", appointmentRemindersJobDelay)
+	timer := time.NewTimer(appointmentRemindersJobDelay)
+	<-timer.C
+
+	//send them for first time
+	app.sendAppointmentReminders()
+
+	//send them every 15 minutes
+	ticker := time.NewTicker(appointmentRemindersPeriod)
+	for range ticker.C {
+		app.sendAppointmentReminders()
+	}
+}
+
+//sendAppointmentReminders reminds the users for the appointments which start in the next 24 hours. Every appointment is marked
+//before the reminder is sent, so it is sent once even if there are many instances.
+func (app *Application) sendAppointmentReminders() {
+	log.Println("Application -> sendAppointmentReminders")
+	ctx := context.Background()
+
+	now := time.Now()
+	appointments, err := app.storage.FindAppointmentsToRemind(ctx, now, now.Add(appointmentReminderBefore))
+	if err != nil {
+		log.Printf("error finding the appointments to remind - %s", err)
+		return
+	}
+
+	locations := make(map[string]*model.Location)
+	for _, appointment := range appointments {
+		marked, err := app.storage.SetAppointmentReminderSent(ctx, appointment.ID)
+		if err != nil {
+			log.Printf("error marking the appointment %s reminder - %s", appointment.ID, err)
+			continue
+		}
+		if !marked {
+			//another instance has sent it
+			continue
+		}
+		user, err := app.storage.FindUser(ctx, appointment.UserID)
+		if err != nil || user == nil {
+			log.Printf("error finding the user for the appointment %s - %v", appointment.ID, err)
+			continue
+		}
+		location, found := locations[appointment.LocationID]
+		if !found {
+			location, err = app.storage.FindLocation(ctx, appointment.LocationID)
+			if err != nil || location == nil {
+				log.Printf("error finding the location for the appointment %s - %v", appointment.ID, err)
+				continue
+			}
+			locations[appointment.LocationID] = location
+		}
+
+		app.sendAppointmentNotification(user.UUID, *appointment, "appointment-reminder",
+			fmt.Sprintf("Reminder - your appointment at %s is on %s", location.Name, appointmentStartText(location, appointment.Start)))
+	}
+}
@@ -0,0 +1,148 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"context"
+	"errors"
+	"health/core/model"
+	"health/utils"
+)
+
+const (
+	appVersionStatusSupported  = "supported"
+	appVersionStatusDeprecated = "deprecated"
+	appVersionStatusBlocked    = "blocked"
+)
+
+func (app *Application) getAppVersionsStatuses(ctx context.Context) ([]*model.AppVersion, error) {
+	versions, err := app.storage.FindAppVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range versions {
+		if len(item.Status) == 0 {
+			item.Status = appVersionStatusSupported
+		}
+	}
+	return versions, nil
+}
+
+func (app *Application) updateAppVersionStatus(ctx context.Context, current model.User, group string, audit *string, version string, status string) error {
+	if status != appVersionStatusSupported && status != appVersionStatusDeprecated && status != appVersionStatusBlocked {
+		return errors.New("status must be supported, deprecated or blocked")
+	}
+	supported, v := app.isVersionSupported(version)
+	if !supported {
+		return errors.New("there is no an app version for the provided version")
+	}
+
+	err := app.storage.UpdateAppVersionStatus(ctx, *v, status)
+	if err != nil {
+		return err
+	}
+
+	//audit
+	userIdentifier, userInfo := current.GetLogData()
+	lData := []AuditDataEntry{{Key: "version", Value: *v}, {Key: "status", Value: status}}
+	defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "app-version", *v, lData, audit)
+
+	return nil
+}
+
+//getAppVersionStatus gives the lifecycle status of the version the client uses. The unknown versions are supported,
+//they are served with the content of the closest supported version.
+func (app *Application) getAppVersionStatus(appVersion string) string {
+	supported, v := app.isVersionSupported(appVersion)
+	if !supported {
+		return appVersionStatusSupported
+	}
+	return app.getCachedAppVersionStatus(*v)
+}
+
+//checkContentVersion gives the app version key the content is stored with. The content can be for a supported version,
+//stored in the short view, or for a range of versions - ">=2.8 <3.0", stored with normalized spaces.
+func (app *Application) checkContentVersion(appVersion string) (*string, error) {
+	return contentVersionKey(appVersion, app.getCachedAppVersions())
+}
+
+func contentVersionKey(appVersion string, versions []string) (*string, error) {
+	if supported, v := matchVersion(appVersion, versions); supported {
+		return v, nil
+	}
+	if utils.IsVersionRange(appVersion) {
+		versionRange, err := utils.ParseVersionRange(appVersion)
+		if err != nil {
+			return nil, err
+		}
+		result := versionRange.String()
+		return &result, nil
+	}
+	return nil, errors.New("app version is not supported")
+}
+
+//resolveContentVersion gives the key of the content for the version the client uses:
+//1. the content stored for the same version
+//2. the content stored for a range containing the version, the most specific range is used if there are several
+//3. the latest supported version which is not greater than the desired one
+func (app *Application) resolveContentVersion(appVersion *string, keys []string) (*string, error) {
+	v := appVersion
+	if v == nil {
+		//use the latest version if not provided
+		latest, err := app.checkAppVersion(nil)
+		if err != nil {
+			return nil, err
+		}
+		v = latest
+	}
+
+	if key := matchContentVersion(*v, keys); key != nil {
+		return key, nil
+	}
+	return app.checkAppVersion(v)
+}
+
+//matchContentVersion finds the key for the version - the same version or the most specific range containing it
+func matchContentVersion(v string, keys []string) *string {
+	version, err := utils.ParseVersion(v)
+	if err != nil {
+		return nil
+	}
+
+	var result *string
+	var resultRange *utils.VersionRange
+	for i, key := range keys {
+		if !utils.IsVersionRange(key) {
+			keyVersion, err := utils.ParseVersion(key)
+			if err == nil && keyVersion.Compare(*version) == 0 {
+				return &keys[i]
+			}
+			continue
+		}
+
+		keyRange, err := utils.ParseVersionRange(key)
+		if err != nil || !keyRange.Contains(*version) {
+			continue
+		}
+		if resultRange == nil || keyRange.MinVersion().Compare(resultRange.MinVersion()) > 0 {
+			result = &keys[i]
+			resultRange = keyRange
+		}
+	}
+	return result
+}
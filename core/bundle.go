@@ -0,0 +1,777 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"health/core/model"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	//2 - the test types and the test type results ids in the crules data are replaced with placeholders
+	countyBundleFormatVersion = 2
+
+	//{{county}}, {{county-status:ref}}, {{test-type:name}} and {{test-type-result:test type name/result name}}
+	bundleCountyPlaceholder         = "{{county}}"
+	bundlePlaceholderCountyStatus   = "county-status"
+	bundlePlaceholderTestType       = "test-type"
+	bundlePlaceholderTestTypeResult = "test-type-result"
+
+	defaultLocationTimezone = "America/Chicago"
+)
+
+var bundlePlaceholderRegexp = regexp.MustCompile(`\{\{(county-status|test-type|test-type-result):([^}]*)\}\}`)
+
+//countyConfiguration is a county with all its configuration
+type countyConfiguration struct {
+	county      *model.County
+	rules       []*model.Rule
+	accessRule  *model.AccessRule
+	symptomRule *model.SymptomRule
+	cRules      []*model.CRules
+	locations   []*model.Location
+}
+
+func (app *Application) exportCountyBundle(ctx context.Context, countyID string) (*model.CountyBundle, error) {
+	county, err := app.storage.FindCounty(ctx, countyID)
+	if err != nil {
+		return nil, err
+	}
+	if county == nil {
+		return nil, errors.New("there is no a county for the provided id")
+	}
+
+	bundle := model.CountyBundle{FormatVersion: countyBundleFormatVersion, ExportedAt: time.Now().UTC(),
+		County: model.BundleCounty{Name: county.Name, StateProvince: county.StateProvince, Country: county.Country}}
+
+	//county statuses
+	statusesRefs := make(map[string]string, len(county.CountyStatuses))
+	refs := bundleCountyStatusesRefs(county.CountyStatuses)
+	for i, cs := range county.CountyStatuses {
+		statusesRefs[cs.ID] = refs[i]
+		bundle.CountyStatuses = append(bundle.CountyStatuses, model.BundleCountyStatus{Ref: refs[i], Name: cs.Name, Description: cs.Description})
+	}
+	statusRef := func(ID string) (string, error) {
+		ref, found := statusesRefs[ID]
+		if !found {
+			return "", errors.New("there is no a county status for id " + ID)
+		}
+		return ref, nil
+	}
+
+	//guidelines
+	for _, gl := range county.Guidelines {
+		var items []model.BundleGuidelineItem
+		for _, item := range gl.Items {
+			items = append(items, model.BundleGuidelineItem{Icon: item.Icon, Description: item.Description, Type: item.Type.Value})
+		}
+		bundle.Guidelines = append(bundle.Guidelines, model.BundleGuideline{Name: gl.Name, Description: gl.Description, Items: items})
+	}
+
+	//test types rules - the test types are shared so they are referred by name
+	testTypes, err := app.storage.ReadAllTestTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	testTypesMap := make(map[string]*model.TestType, len(testTypes))
+	for _, tt := range testTypes {
+		testTypesMap[tt.ID] = tt
+	}
+	rules, err := app.storage.FindRulesByCountyID(ctx, countyID)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		testType, found := testTypesMap[rule.TestType.ID]
+		if !found {
+			return nil, errors.New("there is no a test type for id " + rule.TestType.ID)
+		}
+		var resultsStates []model.BundleRuleResultState
+		for _, rs := range rule.ResultsStates {
+			testTypeResult := findTestTypeResult(rs.TestTypeResultID, testType.Results)
+			if testTypeResult == nil {
+				return nil, errors.New("there is no a test type result for id " + rs.TestTypeResultID)
+			}
+			ref, err := statusRef(rs.CountyStatusID)
+			if err != nil {
+				return nil, err
+			}
+			resultsStates = append(resultsStates, model.BundleRuleResultState{TestTypeResult: testTypeResult.Name, CountyStatus: ref})
+		}
+		bundle.Rules = append(bundle.Rules, model.BundleRule{TestType: testType.Name, Priority: rule.Priority, ResultsStates: resultsStates})
+	}
+
+	//access rule
+	accessRule, err := app.storage.FindAccessRuleByCountyID(ctx, countyID)
+	if err != nil {
+		return nil, err
+	}
+	if accessRule != nil {
+		bundleAccessRule := model.BundleAccessRule{}
+		for _, item := range accessRule.Rules {
+			ref, err := statusRef(item.CountyStatusID)
+			if err != nil {
+				return nil, err
+			}
+			bundleAccessRule.Rules = append(bundleAccessRule.Rules, model.BundleAccessRuleItem{CountyStatus: ref, Value: item.Value})
+		}
+		bundle.AccessRule = &bundleAccessRule
+	}
+
+	//symptom rule
+	symptomRule, err := app.storage.FindSymptomRuleByCountyID(ctx, countyID)
+	if err != nil {
+		return nil, err
+	}
+	if symptomRule != nil {
+		bundleSymptomRule := model.BundleSymptomRule{Gr1Count: symptomRule.Gr1Count, Gr2Count: symptomRule.Gr2Count}
+		for _, item := range symptomRule.Items {
+			ref, err := statusRef(item.CountyStatus.ID)
+			if err != nil {
+				return nil, err
+			}
+			bundleSymptomRule.Items = append(bundleSymptomRule.Items,
+				model.BundleSymptomRuleItem{Gr1: item.Gr1, Gr2: item.Gr2, CountyStatus: ref, NextStep: item.NextStep})
+		}
+		bundle.SymptomRule = &bundleSymptomRule
+	}
+
+	//crules - the ids in the raw data are replaced with placeholders
+	cRules, err := app.storage.FindAllCRulesByCountyID(ctx, countyID)
+	if err != nil {
+		return nil, err
+	}
+	replacements := []string{countyID, bundleCountyPlaceholder}
+	for ID, ref := range statusesRefs {
+		replacements = append(replacements, ID, bundlePlaceholder(bundlePlaceholderCountyStatus, ref))
+	}
+	for _, tt := range testTypes {
+		replacements = append(replacements, tt.ID, bundlePlaceholder(bundlePlaceholderTestType, tt.Name))
+		for _, result := range tt.Results {
+			replacements = append(replacements, result.ID, bundlePlaceholder(bundlePlaceholderTestTypeResult, tt.Name+"/"+result.Name))
+		}
+	}
+	replacer := strings.NewReplacer(withoutEmptyReplacements(replacements)...)
+	for _, item := range cRules {
+		bundle.CRules = append(bundle.CRules, model.BundleCRules{AppVersion: item.AppVersion, Data: replacer.Replace(item.Data)})
+	}
+
+	//locations - the providers are shared so they are referred by name
+	locations, err := app.storage.FindLocationsByCountyIDDeep(ctx, countyID)
+	if err != nil {
+		return nil, err
+	}
+	for _, location := range locations {
+		var daysOfOperation []model.BundleOperationDay
+		for _, day := range location.DaysOfOperation {
+			var intervals []model.BundleOperationInterval
+			for _, interval := range day.Intervals {
+				intervals = append(intervals, model.BundleOperationInterval{OpenTime: interval.OpenTime, CloseTime: interval.CloseTime})
+			}
+			daysOfOperation = append(daysOfOperation, model.BundleOperationDay{Name: day.Name, AllDay: day.AllDay, Intervals: intervals})
+		}
+		var availableTests []string
+		for _, tt := range location.AvailableTests {
+			testType, found := testTypesMap[tt.ID]
+			if !found {
+				return nil, errors.New("there is no a test type for id " + tt.ID)
+			}
+			availableTests = append(availableTests, testType.Name)
+		}
+		bundle.Locations = append(bundle.Locations, model.BundleLocation{Provider: location.Provider.Name, Name: location.Name,
+			Address1: location.Address1, Address2: location.Address2, City: location.City, State: location.State, ZIP: location.ZIP,
+			Country: location.Country, Latitude: location.Latitude, Longitude: location.Longitude, Timezone: location.Timezone,
+			Contact: location.Contact, DaysOfOperation: daysOfOperation, URL: location.URL, Notes: location.Notes, AvailableTests: availableTests})
+	}
+
+	return &bundle, nil
+}
+
+//importCountyBundle creates a new county with all its configuration from a bundle. New ids are generated for all entities.
+//All problems in the bundle are returned together and nothing is created if there is any of them.
+//The crules are validated and published as revisions in the same way as the admin changes.
+func (app *Application) importCountyBundle(ctx context.Context, current model.User, group string, audit *string, bundle model.CountyBundle, name *string) (*model.County, error) {
+	countyName := bundle.County.Name
+	if name != nil {
+		countyName = *name
+	}
+
+	config, problems, err := app.buildCountyConfiguration(ctx, bundle, countyName, nil)
+	if err != nil {
+		return nil, err
+	}
+	//check if the county exists
+	otherCounty, err := app.findSameCounty(ctx, config.county)
+	if err != nil {
+		return nil, err
+	}
+	if otherCounty != nil {
+		problems = append(problems, fmt.Sprintf("there is already a county %s, %s, %s - apply the bundle to it instead",
+			otherCounty.Name, otherCounty.StateProvince, otherCounty.Country))
+	}
+	if len(problems) > 0 {
+		return nil, errors.New("invalid county bundle - " + strings.Join(problems, "; "))
+	}
+
+	revisions, err := app.newBundleConfigRevisions(ctx, current, config, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	//create everything at once
+	county := config.county
+	err = app.storage.CreateCountyConfiguration(ctx, county, config.rules, config.accessRule, config.symptomRule, revisions, config.locations)
+	if err != nil {
+		return nil, err
+	}
+
+	//audit
+	for _, revision := range revisions {
+		app.logConfigRevisionPublished(current, group, audit, revision, true)
+	}
+	userIdentifier, userInfo := current.GetLogData()
+	lData := []AuditDataEntry{{Key: "name", Value: county.Name}, {Key: "stateProvince", Value: county.StateProvince}, {Key: "country", Value: county.Country},
+		{Key: "exportedAt", Value: bundle.ExportedAt.Format(time.RFC3339)}, {Key: "countyStatuses", Value: fmt.Sprint(len(county.CountyStatuses))},
+		{Key: "guidelines", Value: fmt.Sprint(len(county.Guidelines))}, {Key: "rules", Value: fmt.Sprint(len(config.rules))},
+		{Key: "accessRule", Value: fmt.Sprint(config.accessRule != nil)}, {Key: "symptomRule", Value: fmt.Sprint(config.symptomRule != nil)},
+		{Key: "crules", Value: fmt.Sprint(len(revisions))}, {Key: "locations", Value: fmt.Sprint(len(config.locations))}}
+	defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "county-bundle", county.ID, lData, audit)
+
+	return county, nil
+}
+
+//applyCountyBundle updates an existing county so it matches the bundle and gives the applied changes - the same ones
+//the diff gives. The ids of the matched entities are kept, so the users data and the crules which refer them stay valid.
+//The locations and the crules app versions which are not in the bundle are kept.
+func (app *Application) applyCountyBundle(ctx context.Context, current model.User, group string, audit *string, countyID string, bundle model.CountyBundle) ([]model.BundleChange, error) {
+	currentConfig, err := app.loadCountyConfiguration(ctx, countyID)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := app.diffCountyBundle(ctx, countyID, bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	config, problems, err := app.buildCountyConfiguration(ctx, bundle, bundle.County.Name, currentConfig)
+	if err != nil {
+		return nil, err
+	}
+	otherCounty, err := app.findSameCounty(ctx, config.county)
+	if err != nil {
+		return nil, err
+	}
+	if otherCounty != nil {
+		problems = append(problems, fmt.Sprintf("there is already another county %s, %s, %s",
+			otherCounty.Name, otherCounty.StateProvince, otherCounty.Country))
+	}
+	if len(problems) > 0 {
+		return nil, errors.New("invalid county bundle - " + strings.Join(problems, "; "))
+	}
+	if len(changes) == 0 {
+		//nothing to apply
+		return changes, nil
+	}
+
+	revisions, err := app.newBundleConfigRevisions(ctx, current, config, currentConfig.cRules)
+	if err != nil {
+		return nil, err
+	}
+
+	//update everything at once
+	err = app.storage.UpdateCountyConfiguration(ctx, config.county, config.rules, config.accessRule, config.symptomRule, revisions, config.locations)
+	if err != nil {
+		return nil, err
+	}
+
+	//audit
+	for _, revision := range revisions {
+		app.logConfigRevisionPublished(current, group, audit, revision, findCRules(revision.AppVersion, currentConfig.cRules) == nil)
+	}
+	userIdentifier, userInfo := current.GetLogData()
+	lData := []AuditDataEntry{{Key: "exportedAt", Value: bundle.ExportedAt.Format(time.RFC3339)}}
+	for _, change := range changes {
+		lData = append(lData, AuditDataEntry{Key: change.Entity, Value: strings.TrimSpace(change.Operation + " " + change.Key + " " + change.Field)})
+	}
+	defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "county-bundle", countyID, lData, audit)
+
+	return changes, nil
+}
+
+//loadCountyConfiguration loads the county with all its configuration
+func (app *Application) loadCountyConfiguration(ctx context.Context, countyID string) (*countyConfiguration, error) {
+	county, err := app.storage.FindCounty(ctx, countyID)
+	if err != nil {
+		return nil, err
+	}
+	if county == nil {
+		return nil, errors.New("there is no a county for the provided id")
+	}
+	rules, err := app.storage.FindRulesByCountyID(ctx, countyID)
+	if err != nil {
+		return nil, err
+	}
+	accessRule, err := app.storage.FindAccessRuleByCountyID(ctx, countyID)
+	if err != nil {
+		return nil, err
+	}
+	symptomRule, err := app.storage.FindSymptomRuleByCountyID(ctx, countyID)
+	if err != nil {
+		return nil, err
+	}
+	cRules, err := app.storage.FindAllCRulesByCountyID(ctx, countyID)
+	if err != nil {
+		return nil, err
+	}
+	locations, err := app.storage.FindLocationsByCountyIDDeep(ctx, countyID)
+	if err != nil {
+		return nil, err
+	}
+	return &countyConfiguration{county: county, rules: rules, accessRule: accessRule, symptomRule: symptomRule,
+		cRules: cRules, locations: locations}, nil
+}
+
+//buildCountyConfiguration creates the county configuration from the bundle and gives all problems in the bundle together.
+//The ids of the current configuration entities are kept when it is provided, otherwise new ids are generated.
+func (app *Application) buildCountyConfiguration(ctx context.Context, bundle model.CountyBundle, countyName string, current *countyConfiguration) (*countyConfiguration, []string, error) {
+	var problems []string
+	addProblem := func(format string, a ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, a...))
+	}
+
+	if bundle.FormatVersion < 1 || bundle.FormatVersion > countyBundleFormatVersion {
+		addProblem("not supported format version %d", bundle.FormatVersion)
+	}
+	if len(countyName) == 0 {
+		addProblem("the county name is empty")
+	}
+
+	county := &model.County{ID: newBundleID(), Name: countyName, StateProvince: bundle.County.StateProvince, Country: bundle.County.Country}
+	if current == nil {
+		current = &countyConfiguration{county: &model.County{}}
+	} else {
+		county.ID = current.county.ID
+	}
+	//the current ids by the bundle keys
+	keepID := func(IDs map[string]string, key string) string {
+		if ID, found := IDs[key]; found {
+			return ID
+		}
+		return newBundleID()
+	}
+
+	//county statuses
+	currentStatusesIDs := make(map[string]string, len(current.county.CountyStatuses))
+	currentRefs := bundleCountyStatusesRefs(current.county.CountyStatuses)
+	for i, cs := range current.county.CountyStatuses {
+		currentStatusesIDs[currentRefs[i]] = cs.ID
+	}
+	statusesIDs := make(map[string]string, len(bundle.CountyStatuses))
+	for i, cs := range bundle.CountyStatuses {
+		if len(cs.Ref) == 0 {
+			addProblem("county_statuses[%d]: the ref is empty", i)
+			continue
+		}
+		if _, found := statusesIDs[cs.Ref]; found {
+			addProblem("county_statuses[%d]: duplicated ref %s", i, cs.Ref)
+			continue
+		}
+		statusesIDs[cs.Ref] = keepID(currentStatusesIDs, cs.Ref)
+		county.CountyStatuses = append(county.CountyStatuses, model.CountyStatus{ID: statusesIDs[cs.Ref], Name: cs.Name, Description: cs.Description})
+	}
+	statusID := func(path string, ref string) string {
+		ID, found := statusesIDs[ref]
+		if !found {
+			addProblem("%s: there is no a county status for ref %s", path, ref)
+		}
+		return ID
+	}
+
+	//guidelines - matched by name as in the diff
+	currentGuidelines := make([]model.BundleGuideline, len(current.county.Guidelines))
+	for i, gl := range current.county.Guidelines {
+		currentGuidelines[i] = model.BundleGuideline{Name: gl.Name}
+	}
+	currentGuidelinesIDs := make(map[string]string, len(currentGuidelines))
+	for i, key := range guidelinesKeys(currentGuidelines) {
+		currentGuidelinesIDs[key] = current.county.Guidelines[i].ID
+	}
+	bundleGuidelinesKeys := guidelinesKeys(bundle.Guidelines)
+	for i, gl := range bundle.Guidelines {
+		var items []model.GuidelineItem
+		for _, item := range gl.Items {
+			items = append(items, model.GuidelineItem{Icon: item.Icon, Description: item.Description, Type: model.GuidelineItemType{Value: item.Type}})
+		}
+		county.Guidelines = append(county.Guidelines, model.Guideline{ID: keepID(currentGuidelinesIDs, bundleGuidelinesKeys[i]),
+			Name: gl.Name, Description: gl.Description, Items: items})
+	}
+
+	//test types rules
+	testTypes, err := app.storage.ReadAllTestTypes(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	testTypesMap := make(map[string]*model.TestType, len(testTypes))
+	for _, tt := range testTypes {
+		testTypesMap[tt.Name] = tt
+	}
+	currentRulesIDs := make(map[string]string, len(current.rules))
+	for _, rule := range current.rules {
+		currentRulesIDs[rule.TestType.ID] = rule.ID
+	}
+	var rules []*model.Rule
+	rulesTestTypes := make(map[string]bool, len(bundle.Rules))
+	for i, br := range bundle.Rules {
+		path := fmt.Sprintf("rules[%d]", i)
+		testType, found := testTypesMap[br.TestType]
+		if !found {
+			addProblem("%s: there is no a test type %s", path, br.TestType)
+			continue
+		}
+		if rulesTestTypes[br.TestType] {
+			addProblem("%s: there is already a rule for test type %s", path, br.TestType)
+			continue
+		}
+		rulesTestTypes[br.TestType] = true
+
+		var resultsStates []model.TestTypeResultCountyStatus
+		for j, rs := range br.ResultsStates {
+			itemPath := fmt.Sprintf("%s.results_states[%d]", path, j)
+			testTypeResult := findTestTypeResultByName(rs.TestTypeResult, testType.Results)
+			if testTypeResult == nil {
+				addProblem("%s: there is no a test type result %s for test type %s", itemPath, rs.TestTypeResult, br.TestType)
+				continue
+			}
+			resultsStates = append(resultsStates, model.TestTypeResultCountyStatus{TestTypeResultID: testTypeResult.ID,
+				CountyStatusID: statusID(itemPath, rs.CountyStatus)})
+		}
+		rules = append(rules, &model.Rule{ID: keepID(currentRulesIDs, testType.ID), County: model.County{ID: county.ID},
+			TestType: model.TestType{ID: testType.ID}, Priority: br.Priority, ResultsStates: resultsStates})
+	}
+
+	//access rule
+	var accessRule *model.AccessRule
+	if bundle.AccessRule != nil {
+		accessRule = &model.AccessRule{ID: newBundleID(), County: model.County{ID: county.ID}}
+		if current.accessRule != nil {
+			accessRule.ID = current.accessRule.ID
+		}
+		for i, item := range bundle.AccessRule.Rules {
+			path := fmt.Sprintf("access_rule.rules[%d]", i)
+			if !(item.Value == "granted" || item.Value == "denied") {
+				addProblem("%s: the value must be granted or denied", path)
+			}
+			accessRule.Rules = append(accessRule.Rules, model.AccessRuleCountyStatus{CountyStatusID: statusID(path, item.CountyStatus), Value: item.Value})
+		}
+	}
+
+	//symptom rule
+	var symptomRule *model.SymptomRule
+	if bundle.SymptomRule != nil {
+		symptomRule = &model.SymptomRule{ID: newBundleID(), County: model.County{ID: county.ID},
+			Gr1Count: bundle.SymptomRule.Gr1Count, Gr2Count: bundle.SymptomRule.Gr2Count}
+		if current.symptomRule != nil {
+			symptomRule.ID = current.symptomRule.ID
+		}
+		for i, item := range bundle.SymptomRule.Items {
+			path := fmt.Sprintf("symptom_rule.items[%d]", i)
+			symptomRule.Items = append(symptomRule.Items, model.SymptomRuleItem{Gr1: item.Gr1, Gr2: item.Gr2,
+				CountyStatus: model.CountyStatus{ID: statusID(path, item.CountyStatus)}, NextStep: item.NextStep})
+		}
+		//check if we have a full combination
+		if !(app.areGr1Gr2Valid(true, true, symptomRule.Items) && app.areGr1Gr2Valid(true, false, symptomRule.Items) &&
+			app.areGr1Gr2Valid(false, true, symptomRule.Items) && app.areGr1Gr2Valid(false, false, symptomRule.Items)) {
+			addProblem("symptom_rule.items: invalid gr1 and gr2 items")
+		}
+	}
+
+	//crules - the placeholders are replaced with the ids and the data is validated as the admin changes are
+	appVersions, err := app.storage.ReadAllAppVersions(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	references := newConfigCountyReferences(county, rules)
+	var cRules []*model.CRules
+	cRulesVersions := make(map[string]bool, len(bundle.CRules))
+	for i, item := range bundle.CRules {
+		path := fmt.Sprintf("crules[%d]", i)
+		version, err := contentVersionKey(item.AppVersion, appVersions)
+		if err != nil {
+			addProblem("%s: not supported app version %s", path, item.AppVersion)
+			continue
+		}
+		if cRulesVersions[*version] {
+			addProblem("%s: duplicated app version %s", path, item.AppVersion)
+			continue
+		}
+		cRulesVersions[*version] = true
+
+		problemsCount := len(problems)
+		data := strings.ReplaceAll(item.Data, bundleCountyPlaceholder, county.ID)
+		data = bundlePlaceholderRegexp.ReplaceAllStringFunc(data, func(placeholder string) string {
+			match := bundlePlaceholderRegexp.FindStringSubmatch(placeholder)
+			switch match[1] {
+			case bundlePlaceholderCountyStatus:
+				return statusID(path+".data", match[2])
+			case bundlePlaceholderTestType:
+				if testType, found := testTypesMap[match[2]]; found {
+					return testType.ID
+				}
+				addProblem("%s.data: there is no a test type %s", path, match[2])
+			default:
+				if testTypeResult := findBundleTestTypeResult(match[2], testTypes); testTypeResult != nil {
+					return testTypeResult.ID
+				}
+				addProblem("%s.data: there is no a test type result %s", path, match[2])
+			}
+			return ""
+		})
+		if len(problems) > problemsCount {
+			//the not resolved references are already reported
+			continue
+		}
+		err = app.validateConfigData(ctx, configTypeCRules, county.ID, *version, data, references)
+		if err != nil {
+			if _, ok := err.(*ConfigValidationError); !ok {
+				return nil, nil, err
+			}
+			addProblem("%s.data: %s", path, err)
+		}
+		cRules = append(cRules, &model.CRules{AppVersion: *version, CountyID: county.ID, Data: data})
+	}
+	//the kept crules must not refer the removed county statuses
+	for _, item := range current.cRules {
+		if cRulesVersions[item.AppVersion] {
+			continue
+		}
+		for i, cs := range current.county.CountyStatuses {
+			if _, found := statusesIDs[currentRefs[i]]; !found && strings.Contains(item.Data, cs.ID) {
+				addProblem("the crules for app version %s refer the county status %s which is not in the bundle", item.AppVersion, currentRefs[i])
+			}
+		}
+	}
+
+	//locations - matched by name, the current locations keep their exceptions, wait time and appointment settings
+	providers, err := app.storage.ReadAllProviders(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	currentLocations := make(map[string]*model.Location, len(current.locations))
+	for _, location := range current.locations {
+		if _, found := currentLocations[location.Name]; !found {
+			currentLocations[location.Name] = location
+		}
+	}
+	var locations []*model.Location
+	locationsNames := make(map[string]bool, len(bundle.Locations))
+	for i, bl := range bundle.Locations {
+		path := fmt.Sprintf("locations[%d]", i)
+		if locationsNames[bl.Name] {
+			addProblem("%s: duplicated location name %s", path, bl.Name)
+			continue
+		}
+		locationsNames[bl.Name] = true
+		provider := findProviderByName(bl.Provider, providers)
+		if provider == nil {
+			addProblem("%s: there is no a provider %s", path, bl.Provider)
+			continue
+		}
+		var availableTests []model.TestType
+		for _, name := range bl.AvailableTests {
+			testType, found := testTypesMap[name]
+			if !found {
+				addProblem("%s: there is no a test type %s", path, name)
+				continue
+			}
+			availableTests = append(availableTests, model.TestType{ID: testType.ID})
+		}
+		var daysOfOperation []model.OperationDay
+		for _, day := range bl.DaysOfOperation {
+			var intervals []model.OperationInterval
+			for _, interval := range day.Intervals {
+				intervals = append(intervals, model.OperationInterval{OpenTime: interval.OpenTime, CloseTime: interval.CloseTime})
+			}
+			if len(intervals) == 0 && len(day.OpenTime) > 0 {
+				intervals = append(intervals, model.OperationInterval{OpenTime: day.OpenTime, CloseTime: day.CloseTime})
+			}
+			daysOfOperation = append(daysOfOperation, model.OperationDay{Name: day.Name, AllDay: day.AllDay, Intervals: intervals})
+		}
+		daysOfOperation, err = checkDaysOfOperation(daysOfOperation)
+		if err != nil {
+			addProblem("%s: %s", path, err)
+		}
+		timezone := bl.Timezone
+		if len(timezone) == 0 {
+			timezone = defaultLocationTimezone
+		}
+
+		location := &model.Location{ID: newBundleID()}
+		if currentLocation, found := currentLocations[bl.Name]; found {
+			locationCopy := *currentLocation
+			location = &locationCopy
+		}
+		location.Name = bl.Name
+		location.Address1 = bl.Address1
+		location.Address2 = bl.Address2
+		location.City = bl.City
+		location.State = bl.State
+		location.ZIP = bl.ZIP
+		location.Country = bl.Country
+		location.Latitude = bl.Latitude
+		location.Longitude = bl.Longitude
+		location.Timezone = timezone
+		location.Contact = bl.Contact
+		location.DaysOfOperation = daysOfOperation
+		location.URL = bl.URL
+		location.Notes = bl.Notes
+		location.Provider = model.Provider{ID: provider.ID}
+		location.County = model.County{ID: county.ID}
+		location.AvailableTests = availableTests
+		locations = append(locations, location)
+	}
+
+	result := &countyConfiguration{county: county, rules: rules, accessRule: accessRule, symptomRule: symptomRule,
+		cRules: cRules, locations: locations}
+	return result, problems, nil
+}
+
+//newBundleConfigRevisions creates the revisions which publish the bundle crules. The crules which data is not changed are skipped.
+func (app *Application) newBundleConfigRevisions(ctx context.Context, current model.User, config *countyConfiguration, currentCRules []*model.CRules) ([]*model.ConfigRevision, error) {
+	userIdentifier, _ := current.GetLogData()
+	var revisions []*model.ConfigRevision
+	for _, item := range config.cRules {
+		if currentItem := findCRules(item.AppVersion, currentCRules); currentItem != nil && isSameJSON(currentItem.Data, item.Data) {
+			continue
+		}
+		existing, err := app.storage.FindConfigRevisions(ctx, configTypeCRules, item.AppVersion, config.county.ID)
+		if err != nil {
+			return nil, err
+		}
+		revision := &model.ConfigRevision{ID: newBundleID(), Type: configTypeCRules, AppVersion: item.AppVersion, CountyID: config.county.ID,
+			Number: nextConfigRevisionNumber(existing), Data: item.Data, CreatedBy: userIdentifier, DateCreated: time.Now().UTC()}
+		setConfigRevisionPublished(current, revision)
+		revisions = append(revisions, revision)
+	}
+	return revisions, nil
+}
+
+//findSameCounty gives another county with the same name, state and country
+func (app *Application) findSameCounty(ctx context.Context, county *model.County) (*model.County, error) {
+	counties, err := app.storage.FindCounties(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range counties {
+		if c.ID != county.ID && strings.EqualFold(c.Name, county.Name) && strings.EqualFold(c.StateProvince, county.StateProvince) &&
+			strings.EqualFold(c.Country, county.Country) {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+//bundleCountyStatusesRefs gives the refs for the county statuses - the name, suffixed if there are statuses with the same name
+func bundleCountyStatusesRefs(statuses []model.CountyStatus) []string {
+	refs := make([]string, len(statuses))
+	usedRefs := make(map[string]bool, len(statuses))
+	for i, cs := range statuses {
+		ref := cs.Name
+		for n := 2; len(ref) == 0 || usedRefs[ref]; n++ {
+			ref = fmt.Sprintf("%s-%d", cs.Name, n)
+		}
+		usedRefs[ref] = true
+		refs[i] = ref
+	}
+	return refs
+}
+
+func bundlePlaceholder(kind string, key string) string {
+	return "{{" + kind + ":" + key + "}}"
+}
+
+//withoutEmptyReplacements removes the old, new pairs with an empty old string as it would match everywhere
+func withoutEmptyReplacements(pairs []string) []string {
+	var result []string
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if len(pairs[i]) > 0 {
+			result = append(result, pairs[i], pairs[i+1])
+		}
+	}
+	return result
+}
+
+//findBundleTestTypeResult finds the test type result by "test type name/result name"
+func findBundleTestTypeResult(key string, testTypes []*model.TestType) *model.TestTypeResult {
+	for _, tt := range testTypes {
+		if !strings.HasPrefix(key, tt.Name+"/") {
+			continue
+		}
+		if result := findTestTypeResultByName(strings.TrimPrefix(key, tt.Name+"/"), tt.Results); result != nil {
+			return result
+		}
+	}
+	return nil
+}
+
+func findCRules(appVersion string, list []*model.CRules) *model.CRules {
+	for _, item := range list {
+		if item.AppVersion == appVersion {
+			return item
+		}
+	}
+	return nil
+}
+
+func newBundleID() string {
+	id, _ := uuid.NewUUID()
+	return id.String()
+}
+
+func findTestTypeResult(ID string, list []model.TestTypeResult) *model.TestTypeResult {
+	for i := range list {
+		if list[i].ID == ID {
+			return &list[i]
+		}
+	}
+	return nil
+}
+
+func findTestTypeResultByName(name string, list []model.TestTypeResult) *model.TestTypeResult {
+	for i := range list {
+		if list[i].Name == name {
+			return &list[i]
+		}
+	}
+	return nil
+}
+
+func findProviderByName(name string, list []*model.Provider) *model.Provider {
+	for _, item := range list {
+		if item.Name == name {
+			return item
+		}
+	}
+	return nil
+}
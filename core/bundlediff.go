@@ -0,0 +1,378 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"health/core/model"
+	"reflect"
+)
+
+const (
+	bundleChangeAdd    = "add"
+	bundleChangeModify = "modify"
+	bundleChangeDelete = "delete"
+)
+
+//bundleDiff collects the changes in the order they are found
+type bundleDiff struct {
+	changes []model.BundleChange
+}
+
+func (d *bundleDiff) add(entity string, key string, value interface{}) {
+	d.changes = append(d.changes, model.BundleChange{Operation: bundleChangeAdd, Entity: entity, Key: key, New: value})
+}
+
+func (d *bundleDiff) delete(entity string, key string, value interface{}) {
+	d.changes = append(d.changes, model.BundleChange{Operation: bundleChangeDelete, Entity: entity, Key: key, Current: value})
+}
+
+func (d *bundleDiff) modify(entity string, key string, field string, current interface{}, updated interface{}) {
+	if reflect.DeepEqual(current, updated) {
+		return
+	}
+	d.changes = append(d.changes, model.BundleChange{Operation: bundleChangeModify, Entity: entity, Key: key, Field: field, Current: current, New: updated})
+}
+
+//diffCountyBundle gives what will change in the county configuration if the bundle is applied to it.
+//The current configuration is exported in the same format, so the items are matched by ref, name, test type name and app version.
+func (app *Application) diffCountyBundle(ctx context.Context, countyID string, bundle model.CountyBundle) ([]model.BundleChange, error) {
+	current, err := app.exportCountyBundle(ctx, countyID)
+	if err != nil {
+		return nil, err
+	}
+	testTypes, err := app.storage.ReadAllTestTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	appVersions, err := app.storage.ReadAllAppVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &bundleDiff{changes: []model.BundleChange{}}
+
+	//county
+	d.modify("county", current.County.Name, "name", current.County.Name, bundle.County.Name)
+	d.modify("county", current.County.Name, "state_province", current.County.StateProvince, bundle.County.StateProvince)
+	d.modify("county", current.County.Name, "country", current.County.Country, bundle.County.Country)
+
+	diffCountyStatuses(d, current.CountyStatuses, bundle.CountyStatuses)
+	diffGuidelines(d, current.Guidelines, bundle.Guidelines)
+	diffTestTypes(d, testTypes, bundle)
+	diffRules(d, current.Rules, bundle.Rules)
+	diffAccessRule(d, current.AccessRule, bundle.AccessRule)
+	diffSymptomRule(d, current.SymptomRule, bundle.SymptomRule)
+	diffCRules(d, current.CRules, bundle.CRules, appVersions)
+	diffLocations(d, current.Locations, bundle.Locations)
+
+	return d.changes, nil
+}
+
+func diffCountyStatuses(d *bundleDiff, current []model.BundleCountyStatus, updated []model.BundleCountyStatus) {
+	currentMap := make(map[string]model.BundleCountyStatus, len(current))
+	for _, item := range current {
+		currentMap[item.Ref] = item
+	}
+	updatedMap := make(map[string]bool, len(updated))
+	for _, item := range updated {
+		updatedMap[item.Ref] = true
+		currentItem, found := currentMap[item.Ref]
+		if !found {
+			d.add("county-status", item.Ref, item)
+			continue
+		}
+		d.modify("county-status", item.Ref, "name", currentItem.Name, item.Name)
+		d.modify("county-status", item.Ref, "description", currentItem.Description, item.Description)
+	}
+	for _, item := range current {
+		if !updatedMap[item.Ref] {
+			d.delete("county-status", item.Ref, item)
+		}
+	}
+}
+
+func diffGuidelines(d *bundleDiff, current []model.BundleGuideline, updated []model.BundleGuideline) {
+	//the guidelines do not have a ref, they are matched by name
+	currentKeys := guidelinesKeys(current)
+	updatedKeys := guidelinesKeys(updated)
+
+	currentMap := make(map[string]model.BundleGuideline, len(current))
+	for i, item := range current {
+		currentMap[currentKeys[i]] = item
+	}
+	updatedMap := make(map[string]bool, len(updated))
+	for i, item := range updated {
+		key := updatedKeys[i]
+		updatedMap[key] = true
+		currentItem, found := currentMap[key]
+		if !found {
+			d.add("guideline", key, item)
+			continue
+		}
+		d.modify("guideline", key, "description", currentItem.Description, item.Description)
+
+		//the items do not have a key, they are matched by position
+		for j := 0; j < len(item.Items) || j < len(currentItem.Items); j++ {
+			itemKey := fmt.Sprintf("%s/%d", key, j)
+			switch {
+			case j >= len(currentItem.Items):
+				d.add("guideline-item", itemKey, item.Items[j])
+			case j >= len(item.Items):
+				d.delete("guideline-item", itemKey, currentItem.Items[j])
+			default:
+				d.modify("guideline-item", itemKey, "icon", currentItem.Items[j].Icon, item.Items[j].Icon)
+				d.modify("guideline-item", itemKey, "description", currentItem.Items[j].Description, item.Items[j].Description)
+				d.modify("guideline-item", itemKey, "type", currentItem.Items[j].Type, item.Items[j].Type)
+			}
+		}
+	}
+	for i, item := range current {
+		if !updatedMap[currentKeys[i]] {
+			d.delete("guideline", currentKeys[i], item)
+		}
+	}
+}
+
+//guidelinesKeys gives unique keys for the guidelines - the name, suffixed if there are guidelines with the same name
+func guidelinesKeys(list []model.BundleGuideline) []string {
+	keys := make([]string, len(list))
+	used := make(map[string]bool, len(list))
+	for i, item := range list {
+		key := item.Name
+		for n := 2; used[key]; n++ {
+			key = fmt.Sprintf("%s-%d", item.Name, n)
+		}
+		used[key] = true
+		keys[i] = key
+	}
+	return keys
+}
+
+//diffTestTypes gives the test types and results which the bundle refers but they do not exist.
+//The test types are shared between the counties so the bundle never modifies or deletes them.
+func diffTestTypes(d *bundleDiff, testTypes []*model.TestType, bundle model.CountyBundle) {
+	testTypesMap := make(map[string]*model.TestType, len(testTypes))
+	for _, tt := range testTypes {
+		testTypesMap[tt.Name] = tt
+	}
+
+	reported := make(map[string]bool)
+	addTestType := func(name string) *model.TestType {
+		testType, found := testTypesMap[name]
+		if !found && !reported[name] {
+			reported[name] = true
+			d.add("test-type", name, name)
+		}
+		return testType
+	}
+
+	for _, rule := range bundle.Rules {
+		testType := addTestType(rule.TestType)
+		for _, rs := range rule.ResultsStates {
+			key := rule.TestType + "/" + rs.TestTypeResult
+			if reported[key] || (testType != nil && findTestTypeResultByName(rs.TestTypeResult, testType.Results) != nil) {
+				continue
+			}
+			reported[key] = true
+			d.add("test-type-result", key, rs.TestTypeResult)
+		}
+	}
+	for _, location := range bundle.Locations {
+		for _, name := range location.AvailableTests {
+			addTestType(name)
+		}
+	}
+}
+
+func diffRules(d *bundleDiff, current []model.BundleRule, updated []model.BundleRule) {
+	currentMap := make(map[string]model.BundleRule, len(current))
+	for _, item := range current {
+		currentMap[item.TestType] = item
+	}
+	updatedMap := make(map[string]bool, len(updated))
+	for _, item := range updated {
+		updatedMap[item.TestType] = true
+		currentItem, found := currentMap[item.TestType]
+		if !found {
+			d.add("rule", item.TestType, item)
+			continue
+		}
+		d.modify("rule", item.TestType, "priority", intValue(currentItem.Priority), intValue(item.Priority))
+
+		//results states
+		currentStates := make(map[string]string, len(currentItem.ResultsStates))
+		for _, rs := range currentItem.ResultsStates {
+			currentStates[rs.TestTypeResult] = rs.CountyStatus
+		}
+		updatedStates := make(map[string]bool, len(item.ResultsStates))
+		for _, rs := range item.ResultsStates {
+			updatedStates[rs.TestTypeResult] = true
+			key := item.TestType + "/" + rs.TestTypeResult
+			currentStatus, found := currentStates[rs.TestTypeResult]
+			if !found {
+				d.add("rule-result-state", key, rs)
+				continue
+			}
+			d.modify("rule-result-state", key, "county_status", currentStatus, rs.CountyStatus)
+		}
+		for _, rs := range currentItem.ResultsStates {
+			if !updatedStates[rs.TestTypeResult] {
+				d.delete("rule-result-state", item.TestType+"/"+rs.TestTypeResult, rs)
+			}
+		}
+	}
+	for _, item := range current {
+		if !updatedMap[item.TestType] {
+			d.delete("rule", item.TestType, item)
+		}
+	}
+}
+
+func diffAccessRule(d *bundleDiff, current *model.BundleAccessRule, updated *model.BundleAccessRule) {
+	switch {
+	case current == nil && updated == nil:
+		return
+	case current == nil:
+		d.add("access-rule", "", updated)
+		return
+	case updated == nil:
+		d.delete("access-rule", "", current)
+		return
+	}
+
+	currentValues := make(map[string]string, len(current.Rules))
+	for _, item := range current.Rules {
+		currentValues[item.CountyStatus] = item.Value
+	}
+	updatedValues := make(map[string]bool, len(updated.Rules))
+	for _, item := range updated.Rules {
+		updatedValues[item.CountyStatus] = true
+		currentValue, found := currentValues[item.CountyStatus]
+		if !found {
+			d.add("access-rule-item", item.CountyStatus, item)
+			continue
+		}
+		d.modify("access-rule-item", item.CountyStatus, "value", currentValue, item.Value)
+	}
+	for _, item := range current.Rules {
+		if !updatedValues[item.CountyStatus] {
+			d.delete("access-rule-item", item.CountyStatus, item)
+		}
+	}
+}
+
+func diffSymptomRule(d *bundleDiff, current *model.BundleSymptomRule, updated *model.BundleSymptomRule) {
+	switch {
+	case current == nil && updated == nil:
+		return
+	case current == nil:
+		d.add("symptom-rule", "", updated)
+		return
+	case updated == nil:
+		d.delete("symptom-rule", "", current)
+		return
+	}
+
+	d.modify("symptom-rule", "", "gr1_count", current.Gr1Count, updated.Gr1Count)
+	d.modify("symptom-rule", "", "gr2_count", current.Gr2Count, updated.Gr2Count)
+	d.modify("symptom-rule", "", "items", current.Items, updated.Items)
+}
+
+//diffCRules gives the crules changes. Applying a bundle does not remove the crules for the app versions
+//which are not in the bundle, so there are no deletes.
+func diffCRules(d *bundleDiff, current []model.BundleCRules, updated []model.BundleCRules, appVersions []string) {
+	currentMap := make(map[string]string, len(current))
+	for _, item := range current {
+		currentMap[item.AppVersion] = item.Data
+	}
+	for _, item := range updated {
+		//the stored versions are in the short view - 2.8 instead of 2.8.0, the ranges are with normalized spaces
+		appVersion := item.AppVersion
+		if v, err := contentVersionKey(item.AppVersion, appVersions); err == nil {
+			appVersion = *v
+		}
+
+		currentData, found := currentMap[appVersion]
+		if !found {
+			d.add("crules", appVersion, item.Data)
+			continue
+		}
+		if !isSameJSON(currentData, item.Data) {
+			d.modify("crules", appVersion, "data", currentData, item.Data)
+		}
+	}
+}
+
+//diffLocations gives the locations changes, they are matched by name. Applying a bundle does not remove the locations
+//which are not in the bundle, so there are no deletes.
+func diffLocations(d *bundleDiff, current []model.BundleLocation, updated []model.BundleLocation) {
+	currentMap := make(map[string]model.BundleLocation, len(current))
+	for _, item := range current {
+		if _, found := currentMap[item.Name]; !found {
+			currentMap[item.Name] = item
+		}
+	}
+	for _, item := range updated {
+		currentItem, found := currentMap[item.Name]
+		if !found {
+			d.add("location", item.Name, item)
+			continue
+		}
+		timezone := item.Timezone
+		if len(timezone) == 0 {
+			timezone = defaultLocationTimezone
+		}
+		d.modify("location", item.Name, "provider", currentItem.Provider, item.Provider)
+		d.modify("location", item.Name, "address_1", currentItem.Address1, item.Address1)
+		d.modify("location", item.Name, "address_2", currentItem.Address2, item.Address2)
+		d.modify("location", item.Name, "city", currentItem.City, item.City)
+		d.modify("location", item.Name, "state", currentItem.State, item.State)
+		d.modify("location", item.Name, "zip", currentItem.ZIP, item.ZIP)
+		d.modify("location", item.Name, "country", currentItem.Country, item.Country)
+		d.modify("location", item.Name, "latitude", currentItem.Latitude, item.Latitude)
+		d.modify("location", item.Name, "longitude", currentItem.Longitude, item.Longitude)
+		d.modify("location", item.Name, "timezone", currentItem.Timezone, timezone)
+		d.modify("location", item.Name, "contact", currentItem.Contact, item.Contact)
+		d.modify("location", item.Name, "days_of_operation", currentItem.DaysOfOperation, item.DaysOfOperation)
+		d.modify("location", item.Name, "url", currentItem.URL, item.URL)
+		d.modify("location", item.Name, "notes", currentItem.Notes, item.Notes)
+		d.modify("location", item.Name, "available_tests", currentItem.AvailableTests, item.AvailableTests)
+	}
+}
+
+//isSameJSON checks if the raw data is the same, the formatting and the keys order are ignored when it is JSON
+func isSameJSON(a string, b string) bool {
+	if a == b {
+		return true
+	}
+	var aValue, bValue interface{}
+	if json.Unmarshal([]byte(a), &aValue) != nil || json.Unmarshal([]byte(b), &bValue) != nil {
+		return false
+	}
+	return reflect.DeepEqual(aValue, bValue)
+}
+
+func intValue(v *int) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
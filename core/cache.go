@@ -0,0 +1,241 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"context"
+	"health/core/model"
+	"health/utils"
+	"strings"
+	"sync"
+)
+
+const (
+	cacheGroupCounties    = "counties" //the county statuses and the guidelines are part of the county
+	cacheGroupRules       = "rules"
+	cacheGroupAccessRules = "accessrules"
+	cacheGroupTestTypes   = "testtypes"
+	cacheGroupLocations   = "locations"
+	cacheGroupProviders   = "providers"
+	cacheGroupCRules      = "crules"
+)
+
+//referenceDataCache keeps the reference data which is needed on every public request.
+//The entries are loaded on first use and are removed when the storage notifies that a collection is changed.
+//The cached items are shared between the requests so they must not be modified.
+type referenceDataCache struct {
+	lock    *sync.RWMutex
+	entries map[string]interface{}
+	//every invalidation increments the group version, so a load started before it is not stored
+	versions map[string]int64
+}
+
+func (c *referenceDataCache) get(group string, key string, load func() (interface{}, error)) (interface{}, error) {
+	entryKey := group + "/" + key
+
+	c.lock.RLock()
+	value, found := c.entries[entryKey]
+	version := c.versions[group]
+	c.lock.RUnlock()
+	if found {
+		return value, nil
+	}
+
+	//not cached - load it, the errors are not cached
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	if c.versions[group] == version {
+		c.entries[entryKey] = value
+	}
+	c.lock.Unlock()
+	return value, nil
+}
+
+func (c *referenceDataCache) invalidate(group string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.versions[group]++
+	for key := range c.entries {
+		if strings.HasPrefix(key, group+"/") {
+			delete(c.entries, key)
+		}
+	}
+}
+
+func newReferenceDataCache() *referenceDataCache {
+	return &referenceDataCache{lock: &sync.RWMutex{}, entries: make(map[string]interface{}), versions: make(map[string]int64)}
+}
+
+func (app *Application) getCachedCounties(ctx context.Context) ([]*model.County, error) {
+	value, err := app.cache.get(cacheGroupCounties, "all", func() (interface{}, error) {
+		return app.storage.FindCounties(ctx, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]*model.County), nil
+}
+
+func (app *Application) getCachedCounty(ctx context.Context, ID string) (*model.County, error) {
+	counties, err := app.getCachedCounties(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, county := range counties {
+		if county.ID == ID {
+			return county, nil
+		}
+	}
+	//not found
+	return nil, nil
+}
+
+func (app *Application) getCachedCountyStatuses(ctx context.Context, countyID string) ([]*model.CountyStatus, error) {
+	county, err := app.getCachedCounty(ctx, countyID)
+	if err != nil {
+		return nil, err
+	}
+	if county == nil {
+		//not found
+		return nil, nil
+	}
+
+	var resultList []*model.CountyStatus
+	for i := range county.CountyStatuses {
+		resultList = append(resultList, &county.CountyStatuses[i])
+	}
+	return resultList, nil
+}
+
+func (app *Application) getCachedRules(ctx context.Context) ([]*model.Rule, error) {
+	value, err := app.cache.get(cacheGroupRules, "all", func() (interface{}, error) {
+		return app.storage.ReadAllRules(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]*model.Rule), nil
+}
+
+func (app *Application) getCachedAccessRules(ctx context.Context) ([]*model.AccessRule, error) {
+	value, err := app.cache.get(cacheGroupAccessRules, "all", func() (interface{}, error) {
+		return app.storage.ReadAllAccessRules(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]*model.AccessRule), nil
+}
+
+func (app *Application) getCachedTestTypes(ctx context.Context) ([]*model.TestType, error) {
+	value, err := app.cache.get(cacheGroupTestTypes, "all", func() (interface{}, error) {
+		return app.storage.ReadAllTestTypes(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]*model.TestType), nil
+}
+
+func (app *Application) getCachedLocations(ctx context.Context) ([]*model.Location, error) {
+	value, err := app.cache.get(cacheGroupLocations, "all", func() (interface{}, error) {
+		return app.storage.ReadAllLocations(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]*model.Location), nil
+}
+
+func (app *Application) getCachedProviders(ctx context.Context) ([]*model.Provider, error) {
+	value, err := app.cache.get(cacheGroupProviders, "all", func() (interface{}, error) {
+		return app.storage.ReadAllProviders(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]*model.Provider), nil
+}
+
+//getCachedCountyCRules gives the crules for all app versions and ranges of the county
+func (app *Application) getCachedCountyCRules(ctx context.Context, countyID string) ([]*model.CRules, error) {
+	value, err := app.cache.get(cacheGroupCRules, countyID, func() (interface{}, error) {
+		return app.storage.FindAllCRulesByCountyID(ctx, countyID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]*model.CRules), nil
+}
+
+func (app *Application) findCountiesFromCache(ctx context.Context, f *utils.Filter) ([]*model.County, error) {
+	//only the full list is cached
+	if f == nil || len(f.Items) == 0 {
+		return app.getCachedCounties(ctx)
+	}
+	return app.findCounties(ctx, f)
+}
+
+//getCachedLocationsDeep gives the locations for the provided counties with the provider data.
+//The locations without a provider are skipped as the storage deep requests do.
+func (app *Application) getCachedLocationsDeep(ctx context.Context, countyIDs []string) ([]*model.Location, error) {
+	locations, err := app.getCachedLocations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	providers, err := app.getCachedProviders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	providersMap := make(map[string]*model.Provider, len(providers))
+	for _, provider := range providers {
+		providersMap[provider.ID] = provider
+	}
+
+	var resultList []*model.Location
+	for _, location := range locations {
+		if !containsID(location.County.ID, countyIDs) {
+			continue
+		}
+		provider, found := providersMap[location.Provider.ID]
+		if !found {
+			continue
+		}
+
+		//the cached location is shared, so give a copy with the provider data
+		item := *location
+		item.Provider = model.Provider{ID: provider.ID, Name: provider.Name, AvailableMechanisms: provider.AvailableMechanisms}
+		resultList = append(resultList, &item)
+	}
+	return resultList, nil
+}
+
+func containsID(ID string, list []string) bool {
+	for _, current := range list {
+		if current == ID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,433 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"health/core/model"
+	"health/utils"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+const (
+	referenceCountyStatusID   = "county-status-id"
+	referenceTestTypeID       = "test-type-id"
+	referenceTestTypeResultID = "test-type-result-id"
+	referenceSymptomID        = "symptom-id"
+
+	//protects from cyclic local references
+	maxSchemaReferencesDepth = 64
+)
+
+//ConfigValidationError is given when the crules data or the symptoms items are not valid
+type ConfigValidationError struct {
+	Issues []model.ValidationIssue
+}
+
+func (e *ConfigValidationError) Error() string {
+	problems := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		problems[i] = issue.Path + ": " + issue.Message
+	}
+	return "invalid data - " + strings.Join(problems, ", ")
+}
+
+func (app *Application) getConfigSchemas(ctx context.Context, appVersion *string) ([]*model.ConfigSchema, error) {
+	if appVersion != nil {
+		v, err := app.checkContentVersion(*appVersion)
+		if err != nil {
+			return nil, err
+		}
+		appVersion = v
+	}
+	return app.storage.FindConfigSchemas(ctx, appVersion)
+}
+
+//saveConfigSchema registers the schema for the crules or the symptoms of an app version, it replaces the current one
+func (app *Application) saveConfigSchema(ctx context.Context, current model.User, group string, audit *string,
+	configType string, appVersion string, schema string) (*model.ConfigSchema, error) {
+	err := checkConfigType(configType)
+	if err != nil {
+		return nil, err
+	}
+	v, err := app.checkContentVersion(appVersion)
+	if err != nil {
+		return nil, err
+	}
+	_, err = gojsonschema.NewSchema(gojsonschema.NewStringLoader(schema))
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema - %s", err)
+	}
+
+	item, err := app.storage.FindConfigSchema(ctx, configType, *v)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	create := item == nil
+	if create {
+		id, err := uuid.NewUUID()
+		if err != nil {
+			return nil, err
+		}
+		item = &model.ConfigSchema{ID: id.String(), Type: configType, AppVersion: *v, Schema: schema, DateCreated: now}
+	} else {
+		item.Schema = schema
+		item.DateUpdated = &now
+	}
+	err = app.storage.SaveConfigSchema(ctx, item)
+	if err != nil {
+		return nil, err
+	}
+
+	//audit
+	userIdentifier, userInfo := current.GetLogData()
+	lData := []AuditDataEntry{{Key: "type", Value: configType}, {Key: "appVersion", Value: *v}, {Key: "schema", Value: schema}}
+	if create {
+		defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "config-schema", item.ID, lData, audit)
+	} else {
+		defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "config-schema", item.ID, lData, audit)
+	}
+
+	return item, nil
+}
+
+func (app *Application) deleteConfigSchema(ctx context.Context, current model.User, group string, configType string, appVersion string) error {
+	v, err := app.checkContentVersion(appVersion)
+	if err != nil {
+		return err
+	}
+	item, err := app.storage.FindConfigSchema(ctx, configType, *v)
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		return errors.New("there is no a schema for the provided type and app version")
+	}
+	err = app.storage.DeleteConfigSchema(ctx, item.ID)
+	if err != nil {
+		return err
+	}
+
+	//audit
+	userIdentifier, userInfo := current.GetLogData()
+	defer app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "config-schema", item.ID)
+
+	return nil
+}
+
+//findConfigSchema gives the schema registered for the app version or for the most specific range containing it
+func (app *Application) findConfigSchema(ctx context.Context, configType string, appVersion string) (*model.ConfigSchema, error) {
+	configSchema, err := app.storage.FindConfigSchema(ctx, configType, appVersion)
+	if err != nil || configSchema != nil || utils.IsVersionRange(appVersion) {
+		return configSchema, err
+	}
+
+	schemas, err := app.storage.FindConfigSchemas(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, item := range schemas {
+		if item.Type == configType {
+			keys = append(keys, item.AppVersion)
+		}
+	}
+	key := matchContentVersion(appVersion, keys)
+	if key == nil {
+		return nil, nil
+	}
+	for _, item := range schemas {
+		if item.Type == configType && item.AppVersion == *key {
+			return item, nil
+		}
+	}
+	return nil, nil
+}
+
+//validateConfigData checks that the data is JSON, that it follows the schema registered for the app version if there is one
+//and that the references in it exist. It gives *ConfigValidationError if the data is not valid.
+//The county references are loaded for the crules county if they are not provided.
+func (app *Application) validateConfigData(ctx context.Context, configType string, countyID string, appVersion string, data string,
+	countyReferences *configCountyReferences) error {
+	var document interface{}
+	err := json.Unmarshal([]byte(data), &document)
+	if err != nil {
+		return &ConfigValidationError{Issues: []model.ValidationIssue{{Path: "(root)", Message: "not valid JSON - " + err.Error()}}}
+	}
+
+	configSchema, err := app.findConfigSchema(ctx, configType, appVersion)
+	if err != nil {
+		return err
+	}
+	if configSchema == nil {
+		//nothing more to check
+		return nil
+	}
+
+	//1. the schema
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(configSchema.Schema))
+	if err != nil {
+		return fmt.Errorf("invalid schema for %s %s - %s", configType, appVersion, err)
+	}
+	result, err := schema.Validate(gojsonschema.NewGoLoader(document))
+	if err != nil {
+		return err
+	}
+	var issues []model.ValidationIssue
+	for _, item := range result.Errors() {
+		issues = append(issues, model.ValidationIssue{Path: item.Context().String(), Message: item.Description()})
+	}
+	if len(issues) > 0 {
+		return &ConfigValidationError{Issues: issues}
+	}
+
+	//2. the references
+	var rootSchema interface{}
+	err = json.Unmarshal([]byte(configSchema.Schema), &rootSchema)
+	if err != nil {
+		return err
+	}
+	references := make(map[string][]schemaReference)
+	collectSchemaReferences(rootSchema, rootSchema, document, "(root)", references, 0)
+	issues, err = app.checkSchemaReferences(ctx, configType, countyID, countyReferences, references)
+	if err != nil {
+		return err
+	}
+	if len(issues) > 0 {
+		return &ConfigValidationError{Issues: issues}
+	}
+	return nil
+}
+
+//schemaReference represents a value marked as a reference by the schema
+type schemaReference struct {
+	path  string
+	value interface{}
+}
+
+//configCountyReferences are the county entities the crules can refer. They are provided when the crules
+//are validated for a county configuration which is not stored yet.
+type configCountyReferences struct {
+	countyStatusesIDs map[string]bool
+	testTypesIDs      map[string]bool //the test types the county has rules for
+}
+
+func newConfigCountyReferences(county *model.County, rules []*model.Rule) *configCountyReferences {
+	result := &configCountyReferences{countyStatusesIDs: make(map[string]bool), testTypesIDs: make(map[string]bool)}
+	if county != nil {
+		for _, cs := range county.CountyStatuses {
+			result.countyStatusesIDs[cs.ID] = true
+		}
+	}
+	for _, rule := range rules {
+		result.testTypesIDs[rule.TestType.ID] = true
+	}
+	return result
+}
+
+func (app *Application) loadConfigCountyReferences(ctx context.Context, countyID string) (*configCountyReferences, error) {
+	county, err := app.getCachedCounty(ctx, countyID)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := app.getCachedRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var countyRules []*model.Rule
+	for _, rule := range rules {
+		if rule.County.ID == countyID {
+			countyRules = append(countyRules, rule)
+		}
+	}
+	return newConfigCountyReferences(county, countyRules), nil
+}
+
+//checkSchemaReferences checks that the referred entities exist. The county statuses must be of the crules county
+//and the crules test types must be used in the county rules.
+func (app *Application) checkSchemaReferences(ctx context.Context, configType string, countyID string, countyReferences *configCountyReferences,
+	references map[string][]schemaReference) ([]model.ValidationIssue, error) {
+	var issues []model.ValidationIssue
+	check := func(format string, exists func(value string) bool, message string) {
+		for _, reference := range references[format] {
+			value, ok := reference.value.(string)
+			if !ok || !exists(value) {
+				issues = append(issues, model.ValidationIssue{Path: reference.path, Message: fmt.Sprintf(message, reference.value)})
+			}
+		}
+	}
+
+	if configType == configTypeCRules && countyReferences == nil &&
+		(len(references[referenceCountyStatusID]) > 0 || len(references[referenceTestTypeID]) > 0) {
+		var err error
+		countyReferences, err = app.loadConfigCountyReferences(ctx, countyID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(references[referenceCountyStatusID]) > 0 {
+		if configType != configTypeCRules {
+			for _, reference := range references[referenceCountyStatusID] {
+				issues = append(issues, model.ValidationIssue{Path: reference.path, Message: "county status references are allowed only in crules"})
+			}
+		} else {
+			statuses := countyReferences.countyStatusesIDs
+			check(referenceCountyStatusID, func(value string) bool { return statuses[value] }, "there is no a county status %v for the county")
+		}
+	}
+
+	if len(references[referenceTestTypeID]) > 0 || len(references[referenceTestTypeResultID]) > 0 {
+		testTypes, err := app.getCachedTestTypes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		testTypesIDs := make(map[string]bool, len(testTypes))
+		resultsIDs := make(map[string]bool)
+		for _, testType := range testTypes {
+			testTypesIDs[testType.ID] = true
+			for _, result := range testType.Results {
+				resultsIDs[result.ID] = true
+			}
+		}
+
+		if configType == configTypeCRules {
+			//only the test types the county has rules for
+			testTypesIDs = countyReferences.testTypesIDs
+			check(referenceTestTypeID, func(value string) bool { return testTypesIDs[value] }, "there is no a rule for test type %v in the county")
+		} else {
+			check(referenceTestTypeID, func(value string) bool { return testTypesIDs[value] }, "there is no a test type %v")
+		}
+		check(referenceTestTypeResultID, func(value string) bool { return resultsIDs[value] }, "there is no a test type result %v")
+	}
+
+	if len(references[referenceSymptomID]) > 0 {
+		symptomGroups, err := app.storage.ReadAllSymptomGroups(ctx)
+		if err != nil {
+			return nil, err
+		}
+		symptomsIDs := make(map[string]bool)
+		for _, group := range symptomGroups {
+			for _, symptom := range group.Symptoms {
+				symptomsIDs[symptom.ID] = true
+			}
+		}
+		check(referenceSymptomID, func(value string) bool { return symptomsIDs[value] }, "there is no a symptom %v")
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Path < issues[j].Path })
+	return issues, nil
+}
+
+//collectSchemaReferences finds the values the schema marks as references. It follows properties, patternProperties,
+//additionalProperties, items, additionalItems, allOf and the local $ref. The anyOf and oneOf branches are not followed.
+func collectSchemaReferences(rootSchema interface{}, schemaValue interface{}, data interface{}, path string, references map[string][]schemaReference, depth int) {
+	schema, ok := schemaValue.(map[string]interface{})
+	if !ok || depth > maxSchemaReferencesDepth {
+		return
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		collectSchemaReferences(rootSchema, resolveLocalSchemaRef(rootSchema, ref), data, path, references, depth+1)
+	}
+	if format, ok := schema["format"].(string); ok {
+		switch format {
+		case referenceCountyStatusID, referenceTestTypeID, referenceTestTypeResultID, referenceSymptomID:
+			references[format] = append(references[format], schemaReference{path: path, value: data})
+		}
+	}
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		for _, item := range allOf {
+			collectSchemaReferences(rootSchema, item, data, path, references, depth+1)
+		}
+	}
+
+	switch value := data.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		patternProperties, _ := schema["patternProperties"].(map[string]interface{})
+		//the keys are sorted so the references are always in the same order
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			itemPath := path + "." + key
+			matched := false
+			if propertySchema, ok := properties[key]; ok {
+				matched = true
+				collectSchemaReferences(rootSchema, propertySchema, value[key], itemPath, references, depth+1)
+			}
+			for pattern, patternSchema := range patternProperties {
+				if re, err := regexp.Compile(pattern); err == nil && re.MatchString(key) {
+					matched = true
+					collectSchemaReferences(rootSchema, patternSchema, value[key], itemPath, references, depth+1)
+				}
+			}
+			if !matched {
+				collectSchemaReferences(rootSchema, schema["additionalProperties"], value[key], itemPath, references, depth+1)
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			itemPath := path + "." + strconv.Itoa(i)
+			switch items := schema["items"].(type) {
+			case map[string]interface{}:
+				collectSchemaReferences(rootSchema, items, item, itemPath, references, depth+1)
+			case []interface{}:
+				if i < len(items) {
+					collectSchemaReferences(rootSchema, items[i], item, itemPath, references, depth+1)
+				} else {
+					collectSchemaReferences(rootSchema, schema["additionalItems"], item, itemPath, references, depth+1)
+				}
+			}
+		}
+	}
+}
+
+//resolveLocalSchemaRef gives the schema for a reference in the same document - #/definitions/item
+func resolveLocalSchemaRef(rootSchema interface{}, ref string) interface{} {
+	if !strings.HasPrefix(ref, "#") {
+		return nil
+	}
+	current := rootSchema
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#"), "/") {
+		if len(token) == 0 {
+			continue
+		}
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = object[token]
+	}
+	return current
+}
@@ -0,0 +1,222 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"health/core/model"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	erasureStatusInProgress = "in-progress"
+	erasureStatusCompleted  = "completed"
+	erasureStatusFailed     = "failed"
+)
+
+//eraseUserData removes all the data linked to the user id or uin - the user record, ctests, ehistories, estatuses,
+//manual tests with their images, appointments, uin overrides and building access. The audit entries are kept but the user references are anonymized.
+//A signed receipt with the counts per collection is stored for every erasure, even the failed ones.
+func (app *Application) eraseUserData(ctx context.Context, current model.User) (*model.ErasureReceipt, error) {
+	//the receipt cannot be proven without a signature
+	if len(app.erasureSigningKey) == 0 {
+		return nil, errors.New("there is no an erasure signing key")
+	}
+
+	receiptID, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+	uins := userUINs(current)
+	subject := current.ID
+	if len(uins) > 0 {
+		subject = uins[0]
+	}
+
+	//the times are kept in milliseconds as the storage does, so the signature can be checked later
+	receipt := &model.ErasureReceipt{ID: receiptID.String(), SubjectHash: app.hashErasureSubject(subject), Status: erasureStatusInProgress,
+		Counts: map[string]int64{}, RequestedAt: time.Now().UTC().Truncate(time.Millisecond)}
+	err = app.saveErasureReceipt(ctx, receipt)
+	if err != nil {
+		return nil, err
+	}
+
+	//1. remove the data from the storage
+	counts, err := app.storage.ClearUserData(ctx, current.ID, uins)
+	if err != nil {
+		app.failErasure(ctx, receipt, err)
+		return nil, err
+	}
+	for collection, count := range counts {
+		receipt.Counts[collection] = count
+	}
+
+	//2. anonymize the audit
+	identifiers := append([]string{current.ID}, uins...)
+	auditCount, err := app.audit.AnonymizeUser(ctx, identifiers)
+	if err != nil {
+		app.failErasure(ctx, receipt, err)
+		return nil, err
+	}
+	receipt.Counts["audit"] = auditCount
+
+	//3. complete the receipt
+	completedAt := time.Now().UTC().Truncate(time.Millisecond)
+	receipt.Status = erasureStatusCompleted
+	receipt.CompletedAt = &completedAt
+	err = app.saveErasureReceipt(ctx, receipt)
+	if err != nil {
+		return nil, err
+	}
+
+	//audit the erasure without any user data
+	lData := []AuditDataEntry{{Key: "subjectHash", Value: receipt.SubjectHash}, {Key: "counts", Value: fmt.Sprint(receipt.Counts)}}
+	defer app.audit.LogCreateEvent("system", "erasure", "", auditEntityErasureReceipt, receipt.ID, lData, nil)
+
+	defer app.notifyListeners("onClearUserData", current)
+
+	return receipt, nil
+}
+
+func (app *Application) failErasure(ctx context.Context, receipt *model.ErasureReceipt, erasureErr error) {
+	errMessage := erasureErr.Error()
+	completedAt := time.Now().UTC().Truncate(time.Millisecond)
+	receipt.Status = erasureStatusFailed
+	receipt.Error = &errMessage
+	receipt.CompletedAt = &completedAt
+	err := app.saveErasureReceipt(ctx, receipt)
+	if err != nil {
+		log.Printf("error saving the failed erasure receipt %s - %s", receipt.ID, err)
+	}
+}
+
+//saveErasureReceipt signs the receipt and saves it
+func (app *Application) saveErasureReceipt(ctx context.Context, receipt *model.ErasureReceipt) error {
+	signature, err := app.signErasureReceipt(*receipt)
+	if err != nil {
+		return err
+	}
+	receipt.Signature = signature
+	return app.storage.SaveErasureReceipt(ctx, receipt)
+}
+
+//signErasureReceipt gives the signature of the receipt
+func (app *Application) signErasureReceipt(receipt model.ErasureReceipt) (*string, error) {
+	if len(app.erasureSigningKey) == 0 {
+		return nil, errors.New("there is no an erasure signing key")
+	}
+
+	//sign everything except the signature, the map keys are sorted by the json encoding
+	receipt.Signature = nil
+	data, err := json.Marshal(receipt)
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, app.erasureSigningKey)
+	mac.Write(data)
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return &signature, nil
+}
+
+//isErasureReceiptSignatureValid checks if the receipt has not been changed after it was signed
+func (app *Application) isErasureReceiptSignatureValid(receipt model.ErasureReceipt) bool {
+	if receipt.Signature == nil {
+		return false
+	}
+	signature, err := app.signErasureReceipt(receipt)
+	if err != nil || signature == nil {
+		return false
+	}
+	return hmac.Equal([]byte(*signature), []byte(*receipt.Signature))
+}
+
+func (app *Application) getErasureReceipt(ctx context.Context, ID string) (*model.ErasureReceipt, bool, error) {
+	receipt, err := app.storage.FindErasureReceipt(ctx, ID)
+	if err != nil {
+		return nil, false, err
+	}
+	if receipt == nil {
+		return nil, false, nil
+	}
+	return receipt, app.isErasureReceiptSignatureValid(*receipt), nil
+}
+
+//getErasureReceiptsByUIN gives the receipts for the previous erasures of the same uin
+func (app *Application) getErasureReceiptsByUIN(ctx context.Context, uin string) ([]*model.ErasureReceipt, error) {
+	return app.storage.FindErasureReceiptsBySubjectHash(ctx, app.hashErasureSubject(uin))
+}
+
+//getUserErasureReceipts gives the receipts for the previous erasures of the current user id and uins
+func (app *Application) getUserErasureReceipts(ctx context.Context, current model.User) ([]*model.ErasureReceipt, error) {
+	result := []*model.ErasureReceipt{}
+	for _, subject := range append([]string{current.ID}, userUINs(current)...) {
+		receipts, err := app.storage.FindErasureReceiptsBySubjectHash(ctx, app.hashErasureSubject(subject))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, receipts...)
+	}
+	return result, nil
+}
+
+//getUserErasureReceipt gives the receipt only if it is for the current user
+func (app *Application) getUserErasureReceipt(ctx context.Context, current model.User, ID string) (*model.ErasureReceipt, error) {
+	receipt, err := app.storage.FindErasureReceipt(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	if receipt == nil {
+		return nil, nil
+	}
+	for _, subject := range append([]string{current.ID}, userUINs(current)...) {
+		if receipt.SubjectHash == app.hashErasureSubject(subject) {
+			return receipt, nil
+		}
+	}
+	return nil, nil
+}
+
+//userUINs gives the uins the user data could be linked with
+func userUINs(user model.User) []string {
+	var uins []string
+	if len(user.ExternalID) > 0 {
+		uins = append(uins, user.ExternalID)
+	}
+	if user.ShibbolethAuth != nil && len(user.ShibbolethAuth.Uin) > 0 && user.ShibbolethAuth.Uin != user.ExternalID {
+		uins = append(uins, user.ShibbolethAuth.Uin)
+	}
+	return uins
+}
+
+//hashErasureSubject pseudonymises the uin or the user id. It is keyed as a plain hash of a uin could be reversed by trying all uins.
+//The prefix separates it from the receipts signatures which use the same key.
+func (app *Application) hashErasureSubject(subject string) string {
+	mac := hmac.New(sha256.New, app.erasureSigningKey)
+	mac.Write([]byte("subject:" + subject))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,227 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"health/core/model"
+	"io"
+	"time"
+)
+
+const (
+	userDataExportFormatVersion = 1
+
+	userDataEncryption = "The items are in the same encrypted form as they are stored - the encrypted_key is encrypted with the user public key " +
+		"and it is the AES key for the encrypted_blob."
+)
+
+//userDataArchive writes the files of the archive and keeps them in the manifest
+type userDataArchive struct {
+	writer   *zip.Writer
+	manifest model.UserDataManifest
+}
+
+func (a *userDataArchive) add(name string, description string, count int, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	fileWriter, err := a.writer.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = fileWriter.Write(data)
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.Sum256(data)
+	a.manifest.Files = append(a.manifest.Files, model.UserDataManifestFile{Name: name, Description: description,
+		Count: count, Size: len(data), SHA256: hex.EncodeToString(hash[:])})
+	return nil
+}
+
+//exportUserData writes a zip archive with everything stored about the user. The data is kept encrypted, the manifest
+//is the last file in the archive and it has the hash of every other file.
+func (app *Application) exportUserData(ctx context.Context, current model.User, w io.Writer) (*model.UserDataManifest, error) {
+	//load everything first so that nothing is written if the storage fails
+	user, err := app.storage.FindUser(ctx, current.ID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		user = &current
+	}
+	ctests, err := app.userDataCTests(ctx, current.ID)
+	if err != nil {
+		return nil, err
+	}
+	histories, err := app.storage.FindEHistories(ctx, current.ID)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := app.storage.FindEStatusesByUserID(ctx, current.ID)
+	if err != nil {
+		return nil, err
+	}
+	manualTests, err := app.storage.FindManualTestsByUserID(ctx, current.ID)
+	if err != nil {
+		return nil, err
+	}
+	appointments, err := app.storage.FindUserAppointments(ctx, current.ID)
+	if err != nil {
+		return nil, err
+	}
+	uins := userUINs(*user)
+	uinOverrides := []*model.UINOverride{}
+	for _, uin := range uins {
+		//the expired ones too
+		items, err := app.storage.FindUINOverrides(ctx, &uin, nil)
+		if err != nil {
+			return nil, err
+		}
+		uinOverrides = append(uinOverrides, items...)
+	}
+	buildingAccess := []*model.UINBuildingAccess{}
+	if len(uins) > 0 {
+		buildingAccess, err = app.storage.FindUINBuildingAccessByUINs(ctx, uins)
+		if err != nil {
+			return nil, err
+		}
+		if buildingAccess == nil {
+			buildingAccess = []*model.UINBuildingAccess{}
+		}
+	}
+	if histories == nil {
+		histories = []*model.EHistory{}
+	}
+	if statuses == nil {
+		statuses = []*model.EStatus{}
+	}
+	if appointments == nil {
+		appointments = []*model.Appointment{}
+	}
+
+	archive := &userDataArchive{writer: zip.NewWriter(w),
+		manifest: model.UserDataManifest{FormatVersion: userDataExportFormatVersion, UserID: current.ID,
+			GeneratedAt: time.Now().UTC(), Encryption: userDataEncryption, Files: []model.UserDataManifestFile{}}}
+
+	err = archive.add("user.json", "The user record", 1, user)
+	if err != nil {
+		return nil, err
+	}
+	err = archive.add("ctests.json", "The tests results from the providers", len(ctests), ctests)
+	if err != nil {
+		return nil, err
+	}
+	err = archive.add("ehistories.json", "The user history", len(histories), histories)
+	if err != nil {
+		return nil, err
+	}
+	err = archive.add("estatuses.json", "The user statuses for all app versions", len(statuses), statuses)
+	if err != nil {
+		return nil, err
+	}
+
+	//manual tests - every image is in a separate file
+	mtList := make([]model.UserDataManualTest, len(manualTests))
+	for i, mt := range manualTests {
+		mtList[i] = model.UserDataManualTest{ID: mt.ID, HistoryID: mt.HistoryID, LocationID: mt.LocationID, CountyID: mt.CountyID,
+			EncryptedKey: mt.EncryptedKey, EncryptedBlob: mt.EncryptedBlob, Status: mt.Status, Date: mt.Date}
+		//the image is cleared after the retention period
+		if len(mt.EncryptedImageBlob) == 0 {
+			continue
+		}
+		imageName := "manual-tests/images/" + mt.ID + ".json"
+		image := model.UserDataManualTestImage{EncryptedImageKey: mt.EncryptedImageKey, EncryptedImageBlob: mt.EncryptedImageBlob}
+		err = archive.add(imageName, "The image of the manual test "+mt.ID, 1, image)
+		if err != nil {
+			return nil, err
+		}
+		mtList[i].Image = &imageName
+	}
+	err = archive.add("manual-tests.json", "The manual tests", len(mtList), mtList)
+	if err != nil {
+		return nil, err
+	}
+
+	err = archive.add("appointments.json", "The appointments at the testing locations", len(appointments), appointments)
+	if err != nil {
+		return nil, err
+	}
+	err = archive.add("uin-overrides.json", "The uin overrides", len(uinOverrides), uinOverrides)
+	if err != nil {
+		return nil, err
+	}
+	err = archive.add("building-access.json", "The building access", len(buildingAccess), buildingAccess)
+	if err != nil {
+		return nil, err
+	}
+
+	//the manifest is not in the files list
+	manifestData, err := json.MarshalIndent(archive.manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	manifestWriter, err := archive.writer.Create("manifest.json")
+	if err != nil {
+		return nil, err
+	}
+	_, err = manifestWriter.Write(manifestData)
+	if err != nil {
+		return nil, err
+	}
+	err = archive.writer.Close()
+	if err != nil {
+		return nil, err
+	}
+	return &archive.manifest, nil
+}
+
+//userDataCTests gives the processed and not processed ctests of the user with the providers names
+func (app *Application) userDataCTests(ctx context.Context, userID string) ([]model.UserDataCTest, error) {
+	providers, err := app.storage.ReadAllProviders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	providersNames := make(map[string]string, len(providers))
+	for _, provider := range providers {
+		providersNames[provider.ID] = provider.Name
+	}
+
+	result := []model.UserDataCTest{}
+	for _, processed := range []bool{true, false} {
+		ctests, err := app.storage.FindCTests(ctx, userID, processed)
+		if err != nil {
+			return nil, err
+		}
+		for _, ctest := range ctests {
+			item := model.UserDataCTest{CTest: *ctest}
+			if name, found := providersNames[ctest.ProviderID]; found {
+				item.ProviderName = &name
+			}
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
@@ -18,79 +18,101 @@
 package core
 
 import (
+	"context"
 	"health/core/model"
 	"health/utils"
+	"io"
 	"time"
 )
 
 //Services exposes APIs for the driver adapters
 type Services interface {
 	GetVersion() string
+	GetAppVersionStatus(appVersion string) string
 
-	ClearUserData(current model.User) error
+	ClearUserData(ctx context.Context, current model.User) error
+	EraseUserData(ctx context.Context, current model.User) (*model.ErasureReceipt, error)
+	GetUserErasureReceipts(ctx context.Context, current model.User) ([]*model.ErasureReceipt, error)
+	GetUserErasureReceipt(ctx context.Context, current model.User, ID string) (*model.ErasureReceipt, error)
 
-	GetUserByShibbolethUIN(shibbolethUIN string) (*model.User, error)
-	GetUsersForRePost() ([]*model.User, error)
-	GetUINsByOrderNumbers(orderNumbers []string) (map[string]*string, error)
-	GetCTestsByExternalUserIDs(externalUserIDs []string) (map[string][]*model.CTest, error)
+	ExportUserData(ctx context.Context, current model.User, w io.Writer) (*model.UserDataManifest, error)
 
-	GetResources() ([]*model.Resource, error)
+	GetUserByShibbolethUIN(ctx context.Context, shibbolethUIN string) (*model.User, error)
+	GetUsersForRePost(ctx context.Context) ([]*model.User, error)
+	GetUINsByOrderNumbers(ctx context.Context, orderNumbers []string) (map[string]*string, error)
+	GetCTestsByExternalUserIDs(ctx context.Context, externalUserIDs []string) (map[string][]*model.CTest, error)
 
-	GetFAQ() (*model.FAQ, error)
+	GetResources(ctx context.Context) ([]*model.Resource, error)
 
-	GetNews(limit int64) ([]*model.News, error)
+	GetFAQ(ctx context.Context) (*model.FAQ, error)
 
-	GetEStatusByUserID(userID string, appVersion *string) (*model.EStatus, error)
-	CreateOrUpdateEStatus(userID string, appVersion *string, date *time.Time, encryptedKey string, encryptedBlob string) (*model.EStatus, error)
-	DeleteEStatus(userID string, appVersion *string) error
+	GetNews(ctx context.Context, limit int64, category *string) ([]*model.News, error)
 
-	GetEHistoriesByUserID(userID string) ([]*model.EHistory, error)
-	CreateЕHistory(userID string, date time.Time, eType string, encryptedKey string, encryptedBlob string) (*model.EHistory, error)
-	CreateManualЕHistory(userID string, date time.Time, encryptedKey string, encryptedBlob string, encryptedImageKey *string, encryptedImageBlob *string,
+	GetEStatusByUserID(ctx context.Context, userID string, appVersion *string) (*model.EStatus, error)
+	CreateOrUpdateEStatus(ctx context.Context, userID string, appVersion *string, date *time.Time, encryptedKey string, encryptedBlob string) (*model.EStatus, error)
+	DeleteEStatus(ctx context.Context, userID string, appVersion *string) error
+
+	GetEHistoriesByUserID(ctx context.Context, userID string) ([]*model.EHistory, error)
+	CreateЕHistory(ctx context.Context, userID string, keyVersion int, date time.Time, eType string, encryptedKey string, encryptedBlob string) (*model.EHistory, error)
+	CreateManualЕHistory(ctx context.Context, userID string, keyVersion int, date time.Time, encryptedKey string, encryptedBlob string, encryptedImageKey *string, encryptedImageBlob *string,
 		countyID *string, locationID *string) (*model.EHistory, error)
-	DeleteEHitories(userID string) (int64, error)
-	UpdateEHistory(userID string, ID string, date *time.Time, encryptedKey *string, encryptedBlob *string) (*model.EHistory, error)
+	DeleteEHitories(ctx context.Context, userID string) (int64, error)
+	UpdateEHistory(ctx context.Context, userID string, keyVersion int, ID string, date *time.Time, encryptedKey *string, encryptedBlob *string) (*model.EHistory, error)
+
+	GetCTests(ctx context.Context, urrent model.User, processed bool) ([]*model.CTest, []*model.Provider, error)
+	CreateExternalCTest(ctx context.Context, providerID string, uin string, keyVersion *int, encryptedKey string, encryptedBlob string, orderNumber *string) error
+	DeleteCTests(ctx context.Context, userID string) (int64, error)
+	UpdateCTest(ctx context.Context, current model.User, ID string, processed bool) (*model.CTest, error)
+
+	GetUserKeysStatus(ctx context.Context, current model.User) (*model.UserKeysStatus, error)
+	GetPendingReEncryptions(ctx context.Context, providerID *string) ([]*model.PendingReEncryption, error)
+	ReEncryptCTest(ctx context.Context, providerID string, ID string, keyVersion int, encryptedKey string, encryptedBlob string) error
 
-	GetCTests(urrent model.User, processed bool) ([]*model.CTest, []*model.Provider, error)
-	CreateExternalCTest(providerID string, uin string, encryptedKey string, encryptedBlob string, orderNumber *string) error
-	DeleteCTests(userID string) (int64, error)
-	UpdateCTest(current model.User, ID string, processed bool) (*model.CTest, error)
+	GetProviders(ctx context.Context) ([]*model.Provider, error)
 
-	GetProviders() ([]*model.Provider, error)
+	FindCounties(ctx context.Context, f *utils.Filter) ([]*model.County, error)
+	GetCounty(ctx context.Context, ID string) (*model.County, error)
 
-	FindCounties(f *utils.Filter) ([]*model.County, error)
-	GetCounty(ID string) (*model.County, error)
+	GetRulesByCounty(ctx context.Context, countyID string) ([]*model.Rule, []*model.CountyStatus, []*model.TestType, error)
+	EvaluateCountyStatus(ctx context.Context, countyID string, input model.StatusEvaluationInput) (*model.StatusEvaluation, error)
 
-	GetRulesByCounty(countyID string) ([]*model.Rule, []*model.CountyStatus, []*model.TestType, error)
+	GetLocation(ctx context.Context, ID string) (*model.Location, error)
+	GetLocationsByProviderIDCountyID(ctx context.Context, providerID string, countyID string) ([]*model.Location, error)
+	GetLocationsByCountyID(ctx context.Context, countyID string) ([]*model.Location, error)
+	GetLocationsByCounties(ctx context.Context, countyIDs []string) ([]*model.Location, error)
+	GetLocationsNearby(ctx context.Context, latitude float64, longitude float64, radius float64, limit int,
+		testTypeID *string, providerID *string, openNow bool) ([]*model.NearbyLocation, error)
+	ReportWaitTime(ctx context.Context, locationID string, waitMinutes int, queueLength *int) (*model.WaitTimeReport, error)
 
-	GetLocation(ID string) (*model.Location, error)
-	GetLocationsByProviderIDCountyID(providerID string, countyID string) ([]*model.Location, error)
-	GetLocationsByCountyID(countyID string) ([]*model.Location, error)
-	GetLocationsByCounties(countyIDs []string) ([]*model.Location, error)
+	GetAppointmentSlots(ctx context.Context, locationID string) ([]model.AppointmentSlot, error)
+	GetUserAppointments(ctx context.Context, current model.User) ([]*model.Appointment, error)
+	BookAppointment(ctx context.Context, current model.User, locationID string, start time.Time) (*model.Appointment, error)
+	RescheduleAppointment(ctx context.Context, current model.User, ID string, start time.Time) (*model.Appointment, error)
+	CancelAppointment(ctx context.Context, current model.User, ID string) (*model.Appointment, error)
 
-	GetAllTestTypes() ([]*model.TestType, error)
-	GetTestTypesByIDs(ids []string) ([]*model.TestType, error)
+	GetAllTestTypes(ctx context.Context) ([]*model.TestType, error)
+	GetTestTypesByIDs(ctx context.Context, ids []string) ([]*model.TestType, error)
 
-	GetSymptomGroups() ([]*model.SymptomGroup, error)
-	GetSymptoms(appVersion *string) (*model.Symptoms, error)
+	GetSymptomGroups(ctx context.Context) ([]*model.SymptomGroup, error)
+	GetSymptoms(ctx context.Context, appVersion *string) (*model.Symptoms, error)
 
-	GetSymptomRuleByCounty(countyID string) (*model.SymptomRule, []*model.CountyStatus, error)
-	GetCRulesByCounty(appVersion *string, countyID string) (*model.CRules, error)
-	GetAccessRuleByCounty(countyID string) (*model.AccessRule, []*model.CountyStatus, error)
+	GetSymptomRuleByCounty(ctx context.Context, countyID string) (*model.SymptomRule, []*model.CountyStatus, error)
+	GetCRulesByCounty(ctx context.Context, appVersion *string, countyID string) (*model.CRules, error)
+	GetAccessRuleByCounty(ctx context.Context, countyID string) (*model.AccessRule, []*model.CountyStatus, error)
 
-	AddTraceReport(items []model.TraceExposure) (int, error)
-	GetExposures(timestamp *int64, dateAdded *int64) ([]model.TraceExposure, error)
+	AddTraceReport(ctx context.Context, items []model.TraceExposure) (int, error)
+	GetExposures(ctx context.Context, timestamp *int64, dateAdded *int64) ([]model.TraceExposure, error)
 
-	GetUINOverride(current model.User) (*model.UINOverride, error)
-	CreateOrUpdateUINOverride(current model.User, interval int, category *string, expiration *time.Time) error
+	GetUINOverride(ctx context.Context, current model.User) (*model.UINOverride, error)
+	CreateOrUpdateUINOverride(ctx context.Context, current model.User, interval int, category *string, expiration *time.Time) error
 
-	GetExtUINOverrides(uin *string, sort *string) ([]*model.UINOverride, error)
-	CreateExtUINOverride(uin string, interval int, category *string, expiration *time.Time) (*model.UINOverride, error)
-	UpdateExtUINOverride(uin string, interval int, category *string, expiration *time.Time) (*string, error)
-	DeleteExtUINOverride(uin string) error
+	GetExtUINOverrides(ctx context.Context, uin *string, sort *string) ([]*model.UINOverride, error)
+	CreateExtUINOverride(ctx context.Context, uin string, interval int, category *string, expiration *time.Time) (*model.UINOverride, error)
+	UpdateExtUINOverride(ctx context.Context, uin string, interval int, category *string, expiration *time.Time) (*string, error)
+	DeleteExtUINOverride(ctx context.Context, uin string) error
 
-	SetUINBuildingAccess(current model.User, date time.Time, access string) error
-	GetExtUINBuildingAccess(uin string) (*model.UINBuildingAccess, error)
+	SetUINBuildingAccess(ctx context.Context, current model.User, date time.Time, access string) error
+	GetExtUINBuildingAccess(ctx context.Context, uin string) (*model.UINBuildingAccess, error)
 }
 
 type servicesImpl struct {
@@ -101,781 +123,1108 @@ func (s *servicesImpl) GetVersion() string {
 	return s.app.getVersion()
 }
 
-func (s *servicesImpl) ClearUserData(current model.User) error {
-	return s.app.clearUserData(current)
+func (s *servicesImpl) GetAppVersionStatus(appVersion string) string {
+	return s.app.getAppVersionStatus(appVersion)
+}
+
+func (s *servicesImpl) ClearUserData(ctx context.Context, current model.User) error {
+	return s.app.clearUserData(ctx, current)
+}
+
+func (s *servicesImpl) EraseUserData(ctx context.Context, current model.User) (*model.ErasureReceipt, error) {
+	return s.app.eraseUserData(ctx, current)
+}
+
+func (s *servicesImpl) GetUserErasureReceipts(ctx context.Context, current model.User) ([]*model.ErasureReceipt, error) {
+	return s.app.getUserErasureReceipts(ctx, current)
 }
 
-func (s *servicesImpl) GetUserByShibbolethUIN(shibbolethUIN string) (*model.User, error) {
-	return s.app.getUserByShibbolethUIN(shibbolethUIN)
+func (s *servicesImpl) GetUserErasureReceipt(ctx context.Context, current model.User, ID string) (*model.ErasureReceipt, error) {
+	return s.app.getUserErasureReceipt(ctx, current, ID)
 }
 
-func (s *servicesImpl) GetUsersForRePost() ([]*model.User, error) {
-	return s.app.getUsersForRePost()
+func (s *servicesImpl) ExportUserData(ctx context.Context, current model.User, w io.Writer) (*model.UserDataManifest, error) {
+	return s.app.exportUserData(ctx, current, w)
 }
 
-func (s *servicesImpl) GetUINsByOrderNumbers(orderNumbers []string) (map[string]*string, error) {
-	return s.app.getUINsByOrderNumbers(orderNumbers)
+func (s *servicesImpl) GetUserByShibbolethUIN(ctx context.Context, shibbolethUIN string) (*model.User, error) {
+	return s.app.getUserByShibbolethUIN(ctx, shibbolethUIN)
 }
 
-func (s *servicesImpl) GetCTestsByExternalUserIDs(externalUserIDs []string) (map[string][]*model.CTest, error) {
-	return s.app.getCTestsByExternalUserIDs(externalUserIDs)
+func (s *servicesImpl) GetUsersForRePost(ctx context.Context) ([]*model.User, error) {
+	return s.app.getUsersForRePost(ctx)
 }
 
-func (s *servicesImpl) GetResources() ([]*model.Resource, error) {
-	return s.app.getResources()
+func (s *servicesImpl) GetUINsByOrderNumbers(ctx context.Context, orderNumbers []string) (map[string]*string, error) {
+	return s.app.getUINsByOrderNumbers(ctx, orderNumbers)
 }
 
-func (s *servicesImpl) GetFAQ() (*model.FAQ, error) {
-	return s.app.getFAQ()
+func (s *servicesImpl) GetCTestsByExternalUserIDs(ctx context.Context, externalUserIDs []string) (map[string][]*model.CTest, error) {
+	return s.app.getCTestsByExternalUserIDs(ctx, externalUserIDs)
 }
 
-func (s *servicesImpl) GetNews(limit int64) ([]*model.News, error) {
-	return s.app.getNews(limit)
+func (s *servicesImpl) GetResources(ctx context.Context) ([]*model.Resource, error) {
+	return s.app.getResources(ctx)
 }
 
-func (s *servicesImpl) GetEStatusByUserID(userID string, appVersion *string) (*model.EStatus, error) {
-	return s.app.getEStatusByUserID(userID, appVersion)
+func (s *servicesImpl) GetFAQ(ctx context.Context) (*model.FAQ, error) {
+	return s.app.getFAQ(ctx)
 }
 
-func (s *servicesImpl) CreateOrUpdateEStatus(userID string, appVersion *string, date *time.Time, encryptedKey string, encryptedBlob string) (*model.EStatus, error) {
-	return s.app.createOrUpdateEStatus(userID, appVersion, date, encryptedKey, encryptedBlob)
+func (s *servicesImpl) GetNews(ctx context.Context, limit int64, category *string) ([]*model.News, error) {
+	return s.app.getNews(ctx, limit, category)
 }
 
-func (s *servicesImpl) DeleteEStatus(userID string, appVersion *string) error {
-	return s.app.deleteEStatus(userID, appVersion)
+func (s *servicesImpl) GetEStatusByUserID(ctx context.Context, userID string, appVersion *string) (*model.EStatus, error) {
+	return s.app.getEStatusByUserID(ctx, userID, appVersion)
 }
 
-func (s *servicesImpl) GetEHistoriesByUserID(userID string) ([]*model.EHistory, error) {
-	return s.app.getEHistoriesByUserID(userID)
+func (s *servicesImpl) CreateOrUpdateEStatus(ctx context.Context, userID string, appVersion *string, date *time.Time, encryptedKey string, encryptedBlob string) (*model.EStatus, error) {
+	return s.app.createOrUpdateEStatus(ctx, userID, appVersion, date, encryptedKey, encryptedBlob)
 }
 
-func (s *servicesImpl) CreateЕHistory(userID string, date time.Time, eType string, encryptedKey string, encryptedBlob string) (*model.EHistory, error) {
-	return s.app.createЕHistory(userID, date, eType, encryptedKey, encryptedBlob)
+func (s *servicesImpl) DeleteEStatus(ctx context.Context, userID string, appVersion *string) error {
+	return s.app.deleteEStatus(ctx, userID, appVersion)
 }
 
-func (s *servicesImpl) CreateManualЕHistory(userID string, date time.Time, encryptedKey string, encryptedBlob string, encryptedImageKey *string, encryptedImageBlob *string,
+func (s *servicesImpl) GetEHistoriesByUserID(ctx context.Context, userID string) ([]*model.EHistory, error) {
+	return s.app.getEHistoriesByUserID(ctx, userID)
+}
+
+func (s *servicesImpl) CreateЕHistory(ctx context.Context, userID string, keyVersion int, date time.Time, eType string, encryptedKey string, encryptedBlob string) (*model.EHistory, error) {
+	return s.app.createЕHistory(ctx, userID, keyVersion, date, eType, encryptedKey, encryptedBlob)
+}
+
+func (s *servicesImpl) CreateManualЕHistory(ctx context.Context, userID string, keyVersion int, date time.Time, encryptedKey string, encryptedBlob string, encryptedImageKey *string, encryptedImageBlob *string,
 	countyID *string, locationID *string) (*model.EHistory, error) {
-	return s.app.createManualЕHistory(userID, date, encryptedKey, encryptedBlob, encryptedImageKey, encryptedImageBlob, countyID, locationID)
+	return s.app.createManualЕHistory(ctx, userID, keyVersion, date, encryptedKey, encryptedBlob, encryptedImageKey, encryptedImageBlob, countyID, locationID)
+}
+
+func (s *servicesImpl) DeleteEHitories(ctx context.Context, userID string) (int64, error) {
+	return s.app.deleteEHitories(ctx, userID)
+}
+
+func (s *servicesImpl) UpdateEHistory(ctx context.Context, userID string, keyVersion int, ID string, date *time.Time, encryptedKey *string, encryptedBlob *string) (*model.EHistory, error) {
+	return s.app.updateEHistory(ctx, userID, keyVersion, ID, date, encryptedKey, encryptedBlob)
+}
+
+func (s *servicesImpl) GetCTests(ctx context.Context, current model.User, processed bool) ([]*model.CTest, []*model.Provider, error) {
+	return s.app.getCTests(ctx, current, processed)
+}
+
+func (s *servicesImpl) CreateExternalCTest(ctx context.Context, providerID string, uin string, keyVersion *int, encryptedKey string, encryptedBlob string, orderNumber *string) error {
+	return s.app.createExternalCTest(ctx, providerID, uin, keyVersion, encryptedKey, encryptedBlob, orderNumber)
+}
+
+func (s *servicesImpl) DeleteCTests(ctx context.Context, userID string) (int64, error) {
+	return s.app.deleteCTests(ctx, userID)
+}
+
+func (s *servicesImpl) UpdateCTest(ctx context.Context, current model.User, ID string, processed bool) (*model.CTest, error) {
+	return s.app.updateCTest(ctx, current, ID, processed)
+}
+
+func (s *servicesImpl) GetUserKeysStatus(ctx context.Context, current model.User) (*model.UserKeysStatus, error) {
+	return s.app.getUserKeysStatus(ctx, current)
+}
+
+func (s *servicesImpl) GetPendingReEncryptions(ctx context.Context, providerID *string) ([]*model.PendingReEncryption, error) {
+	return s.app.getPendingReEncryptions(ctx, providerID)
+}
+
+func (s *servicesImpl) ReEncryptCTest(ctx context.Context, providerID string, ID string, keyVersion int, encryptedKey string, encryptedBlob string) error {
+	return s.app.reEncryptCTest(ctx, providerID, ID, keyVersion, encryptedKey, encryptedBlob)
+}
+
+func (s *servicesImpl) EvaluateCountyStatus(ctx context.Context, countyID string, input model.StatusEvaluationInput) (*model.StatusEvaluation, error) {
+	return s.app.evaluateCountyStatus(ctx, countyID, input)
 }
 
-func (s *servicesImpl) DeleteEHitories(userID string) (int64, error) {
-	return s.app.deleteEHitories(userID)
+func (s *servicesImpl) GetProviders(ctx context.Context) ([]*model.Provider, error) {
+	return s.app.getCachedProviders(ctx)
 }
 
-func (s *servicesImpl) UpdateEHistory(userID string, ID string, date *time.Time, encryptedKey *string, encryptedBlob *string) (*model.EHistory, error) {
-	return s.app.updateEHistory(userID, ID, date, encryptedKey, encryptedBlob)
+func (s *servicesImpl) FindCounties(ctx context.Context, f *utils.Filter) ([]*model.County, error) {
+	return s.app.findCountiesFromCache(ctx, f)
 }
 
-func (s *servicesImpl) GetCTests(current model.User, processed bool) ([]*model.CTest, []*model.Provider, error) {
-	return s.app.getCTests(current, processed)
+func (s *servicesImpl) GetCounty(ctx context.Context, ID string) (*model.County, error) {
+	return s.app.getCachedCounty(ctx, ID)
 }
 
-func (s *servicesImpl) CreateExternalCTest(providerID string, uin string, encryptedKey string, encryptedBlob string, orderNumber *string) error {
-	return s.app.createExternalCTest(providerID, uin, encryptedKey, encryptedBlob, orderNumber)
+func (s *servicesImpl) GetRulesByCounty(ctx context.Context, countyID string) ([]*model.Rule, []*model.CountyStatus, []*model.TestType, error) {
+	return s.app.getRulesByCounty(ctx, countyID)
 }
 
-func (s *servicesImpl) DeleteCTests(userID string) (int64, error) {
-	return s.app.deleteCTests(userID)
+func (s *servicesImpl) GetLocation(ctx context.Context, ID string) (*model.Location, error) {
+	return s.app.getLocation(ctx, ID)
 }
 
-func (s *servicesImpl) UpdateCTest(current model.User, ID string, processed bool) (*model.CTest, error) {
-	return s.app.updateCTest(current, ID, processed)
+func (s *servicesImpl) GetLocationsByProviderIDCountyID(ctx context.Context, providerID string, countyID string) ([]*model.Location, error) {
+	return s.app.getLocationsByProviderIDCountyID(ctx, providerID, countyID)
 }
 
-func (s *servicesImpl) GetProviders() ([]*model.Provider, error) {
-	return s.app.getProviders()
+func (s *servicesImpl) GetLocationsByCountyID(ctx context.Context, countyID string) ([]*model.Location, error) {
+	return s.app.getLocationsByCountyID(ctx, countyID)
 }
 
-func (s *servicesImpl) FindCounties(f *utils.Filter) ([]*model.County, error) {
-	return s.app.findCounties(f)
+func (s *servicesImpl) GetLocationsByCounties(ctx context.Context, countyIDs []string) ([]*model.Location, error) {
+	return s.app.getLocationsByCounties(ctx, countyIDs)
 }
 
-func (s *servicesImpl) GetCounty(ID string) (*model.County, error) {
-	return s.app.getCounty(ID)
+func (s *servicesImpl) GetLocationsNearby(ctx context.Context, latitude float64, longitude float64, radius float64, limit int,
+	testTypeID *string, providerID *string, openNow bool) ([]*model.NearbyLocation, error) {
+	return s.app.getLocationsNearby(ctx, latitude, longitude, radius, limit, testTypeID, providerID, openNow)
 }
 
-func (s *servicesImpl) GetRulesByCounty(countyID string) ([]*model.Rule, []*model.CountyStatus, []*model.TestType, error) {
-	return s.app.getRulesByCounty(countyID)
+func (s *servicesImpl) ReportWaitTime(ctx context.Context, locationID string, waitMinutes int, queueLength *int) (*model.WaitTimeReport, error) {
+	return s.app.reportWaitTime(ctx, locationID, waitMinutes, queueLength, waitTimeSourceProvider, "")
 }
 
-func (s *servicesImpl) GetLocation(ID string) (*model.Location, error) {
-	return s.app.getLocation(ID)
+func (s *servicesImpl) GetAppointmentSlots(ctx context.Context, locationID string) ([]model.AppointmentSlot, error) {
+	return s.app.getAppointmentSlots(ctx, locationID)
 }
 
-func (s *servicesImpl) GetLocationsByProviderIDCountyID(providerID string, countyID string) ([]*model.Location, error) {
-	return s.app.getLocationsByProviderIDCountyID(providerID, countyID)
+func (s *servicesImpl) GetUserAppointments(ctx context.Context, current model.User) ([]*model.Appointment, error) {
+	return s.app.getUserAppointments(ctx, current)
 }
 
-func (s *servicesImpl) GetLocationsByCountyID(countyID string) ([]*model.Location, error) {
-	return s.app.getLocationsByCountyID(countyID)
+func (s *servicesImpl) BookAppointment(ctx context.Context, current model.User, locationID string, start time.Time) (*model.Appointment, error) {
+	return s.app.bookAppointment(ctx, current, locationID, start)
 }
 
-func (s *servicesImpl) GetLocationsByCounties(countyIDs []string) ([]*model.Location, error) {
-	return s.app.getLocationsByCounties(countyIDs)
+func (s *servicesImpl) RescheduleAppointment(ctx context.Context, current model.User, ID string, start time.Time) (*model.Appointment, error) {
+	return s.app.rescheduleAppointment(ctx, current, ID, start)
 }
 
-func (s *servicesImpl) GetAllTestTypes() ([]*model.TestType, error) {
-	return s.app.getAllTestTypes()
+func (s *servicesImpl) CancelAppointment(ctx context.Context, current model.User, ID string) (*model.Appointment, error) {
+	return s.app.cancelAppointment(ctx, current, ID)
 }
 
-func (s *servicesImpl) GetTestTypesByIDs(ids []string) ([]*model.TestType, error) {
-	return s.app.getTestTypesByIDs(ids)
+func (s *servicesImpl) GetAllTestTypes(ctx context.Context) ([]*model.TestType, error) {
+	return s.app.getAllTestTypes(ctx)
 }
 
-func (s *servicesImpl) GetSymptomGroups() ([]*model.SymptomGroup, error) {
-	return s.app.getSymptomGroups()
+func (s *servicesImpl) GetTestTypesByIDs(ctx context.Context, ids []string) ([]*model.TestType, error) {
+	return s.app.getTestTypesByIDs(ctx, ids)
 }
 
-func (s *servicesImpl) GetSymptoms(appVersion *string) (*model.Symptoms, error) {
-	return s.app.getSymptoms(appVersion)
+func (s *servicesImpl) GetSymptomGroups(ctx context.Context) ([]*model.SymptomGroup, error) {
+	return s.app.getSymptomGroups(ctx)
 }
 
-func (s *servicesImpl) GetSymptomRuleByCounty(countyID string) (*model.SymptomRule, []*model.CountyStatus, error) {
-	return s.app.getSymptomRuleByCounty(countyID)
+func (s *servicesImpl) GetSymptoms(ctx context.Context, appVersion *string) (*model.Symptoms, error) {
+	return s.app.getSymptoms(ctx, appVersion)
 }
 
-func (s *servicesImpl) GetCRulesByCounty(appVersion *string, countyID string) (*model.CRules, error) {
-	return s.app.getCRulesByCounty(appVersion, countyID)
+func (s *servicesImpl) GetSymptomRuleByCounty(ctx context.Context, countyID string) (*model.SymptomRule, []*model.CountyStatus, error) {
+	return s.app.getSymptomRuleByCounty(ctx, countyID)
 }
 
-func (s *servicesImpl) GetAccessRuleByCounty(countyID string) (*model.AccessRule, []*model.CountyStatus, error) {
-	return s.app.getAccessRuleByCounty(countyID)
+func (s *servicesImpl) GetCRulesByCounty(ctx context.Context, appVersion *string, countyID string) (*model.CRules, error) {
+	return s.app.getCRulesByCounty(ctx, appVersion, countyID)
 }
 
-func (s *servicesImpl) AddTraceReport(items []model.TraceExposure) (int, error) {
-	return s.app.аddTraceReport(items)
+func (s *servicesImpl) GetAccessRuleByCounty(ctx context.Context, countyID string) (*model.AccessRule, []*model.CountyStatus, error) {
+	return s.app.getAccessRuleByCounty(ctx, countyID)
 }
 
-func (s *servicesImpl) GetExposures(timestamp *int64, dateAdded *int64) ([]model.TraceExposure, error) {
-	return s.app.getExposures(timestamp, dateAdded)
+func (s *servicesImpl) AddTraceReport(ctx context.Context, items []model.TraceExposure) (int, error) {
+	return s.app.аddTraceReport(ctx, items)
 }
 
-func (s *servicesImpl) GetUINOverride(current model.User) (*model.UINOverride, error) {
-	return s.app.getUINOverride(current)
+func (s *servicesImpl) GetExposures(ctx context.Context, timestamp *int64, dateAdded *int64) ([]model.TraceExposure, error) {
+	return s.app.getExposures(ctx, timestamp, dateAdded)
 }
 
-func (s *servicesImpl) CreateOrUpdateUINOverride(current model.User, interval int, category *string, expiration *time.Time) error {
-	return s.app.createOrUpdateUINOverride(current, interval, category, expiration)
+func (s *servicesImpl) GetUINOverride(ctx context.Context, current model.User) (*model.UINOverride, error) {
+	return s.app.getUINOverride(ctx, current)
 }
 
-func (s *servicesImpl) GetExtUINOverrides(uin *string, sort *string) ([]*model.UINOverride, error) {
-	return s.app.getExtUINOverrides(uin, sort)
+func (s *servicesImpl) CreateOrUpdateUINOverride(ctx context.Context, current model.User, interval int, category *string, expiration *time.Time) error {
+	return s.app.createOrUpdateUINOverride(ctx, current, interval, category, expiration)
 }
 
-func (s *servicesImpl) CreateExtUINOverride(uin string, interval int, category *string, expiration *time.Time) (*model.UINOverride, error) {
-	return s.app.createExtUINOverride(uin, interval, category, expiration)
+func (s *servicesImpl) GetExtUINOverrides(ctx context.Context, uin *string, sort *string) ([]*model.UINOverride, error) {
+	return s.app.getExtUINOverrides(ctx, uin, sort)
 }
 
-func (s *servicesImpl) UpdateExtUINOverride(uin string, interval int, category *string, expiration *time.Time) (*string, error) {
-	return s.app.updateExtUINOverride(uin, interval, category, expiration)
+func (s *servicesImpl) CreateExtUINOverride(ctx context.Context, uin string, interval int, category *string, expiration *time.Time) (*model.UINOverride, error) {
+	return s.app.createExtUINOverride(ctx, uin, interval, category, expiration)
 }
 
-func (s *servicesImpl) DeleteExtUINOverride(uin string) error {
-	return s.app.deleteExtUINOverride(uin)
+func (s *servicesImpl) UpdateExtUINOverride(ctx context.Context, uin string, interval int, category *string, expiration *time.Time) (*string, error) {
+	return s.app.updateExtUINOverride(ctx, uin, interval, category, expiration)
 }
 
-func (s *servicesImpl) SetUINBuildingAccess(current model.User, date time.Time, access string) error {
-	return s.app.setUINBuildingAccess(current, date, access)
+func (s *servicesImpl) DeleteExtUINOverride(ctx context.Context, uin string) error {
+	return s.app.deleteExtUINOverride(ctx, uin)
 }
 
-func (s *servicesImpl) GetExtUINBuildingAccess(uin string) (*model.UINBuildingAccess, error) {
-	return s.app.getExtUINBuildingAccess(uin)
+func (s *servicesImpl) SetUINBuildingAccess(ctx context.Context, current model.User, date time.Time, access string) error {
+	return s.app.setUINBuildingAccess(ctx, current, date, access)
+}
+
+func (s *servicesImpl) GetExtUINBuildingAccess(ctx context.Context, uin string) (*model.UINBuildingAccess, error) {
+	return s.app.getExtUINBuildingAccess(ctx, uin)
 }
 
 //Administration exposes administration APIs for the driver adapters
 type Administration interface {
-	GetCovid19Config() (*model.COVID19Config, error)
-	UpdateCovid19Config(config *model.COVID19Config) error
-
-	GetAppVersions() ([]string, error)
-	CreateAppVersion(current model.User, group string, audit *string, version string) error
-
-	GetNews() ([]*model.News, error)
-	CreateNews(current model.User, group string, audit *string, date time.Time, title string, description string, htmlContent string, link *string) (*model.News, error)
-	UpdateNews(current model.User, group string, audit *string, ID string, date time.Time, title string, description string, htmlContent string, link *string) (*model.News, error)
-	DeleteNews(current model.User, group string, ID string) error
-
-	GetResources() ([]*model.Resource, error)
-	CreateResource(current model.User, group string, audit *string, title string, link string, displayOrder int) (*model.Resource, error)
-	UpdateResource(current model.User, group string, audit *string, ID string, title string, link string, displayOrder int) (*model.Resource, error)
-	DeleteResource(current model.User, group string, ID string) error
-	UpdateResourceDisplayOrder(IDs []string) error
-
-	GetFAQs() (*model.FAQ, error)
-	CreateFAQ(current model.User, group string, audit *string, section string, sectionDisplayOrder int, title string, description string, questionDisplayOrder int) error
-	UpdateFAQ(current model.User, group string, audit *string, ID string, title string, description string, displayOrder int) error
-	DeleteFAQ(current model.User, group string, ID string) error
-
-	DeleteFAQSection(current model.User, group string, ID string) error
-	UpdateFAQSection(current model.User, group string, audit *string, ID string, title string, displayOrder int) error
-
-	GetProviders() ([]*model.Provider, error)
-	CreateProvider(current model.User, group string, audit *string, providerName string, manualTest bool, availableMechanisms []string) (*model.Provider, error)
-	UpdateProvider(current model.User, group string, audit *string, ID string, providerName string, manualTest bool, availableMechanisms []string) (*model.Provider, error)
-	DeleteProvider(current model.User, group string, ID string) error
-
-	FindCounties(f *utils.Filter) ([]*model.County, error)
-	CreateCounty(current model.User, group string, audit *string, name string, stateProvince string, country string) (*model.County, error)
-	UpdateCounty(current model.User, group string, audit *string, ID string, name string, stateProvince string, country string) (*model.County, error)
-	DeleteCounty(current model.User, group string, ID string) error
-
-	CreateGuideline(current model.User, group string, audit *string, countyID string, name string, description string, items []model.GuidelineItem) (*model.Guideline, error)
-	UpdateGuideline(current model.User, group string, audit *string, ID string, name string, description string, items []model.GuidelineItem) (*model.Guideline, error)
-	DeleteGuideline(current model.User, group string, ID string) error
-	GetGuidelinesByCountyID(countyID string) ([]*model.Guideline, error)
-
-	CreateCountyStatus(current model.User, group string, audit *string, countyID string, name string, description string) (*model.CountyStatus, error)
-	UpdateCountyStatus(current model.User, group string, audit *string, ID string, name string, description string) (*model.CountyStatus, error)
-	DeleteCountyStatus(current model.User, group string, ID string) error
-	GetCountyStatusByCountyID(countyID string) ([]*model.CountyStatus, error)
-
-	GetTestTypes() ([]*model.TestType, error)
-	CreateTestType(current model.User, group string, audit *string, name string, priority *int) (*model.TestType, error)
-	UpdateTestType(current model.User, group string, audit *string, ID string, name string, priority *int) (*model.TestType, error)
-	DeleteTestType(current model.User, group string, ID string) error
-
-	CreateTestTypeResult(current model.User, group string, audit *string, testTypeID string, name string, nextStep string, nextStepOffset *int, resultExpiresOffset *int) (*model.TestTypeResult, error)
-	UpdateTestTypeResult(current model.User, group string, audit *string, ID string, name string, nextStep string, nextStepOffset *int, resultExpiresOffset *int) (*model.TestTypeResult, error)
-	DeleteTestTypeResult(current model.User, group string, ID string) error
-	GetTestTypeResultsByTestTypeID(testTypeID string) ([]*model.TestTypeResult, error)
-
-	GetRules() ([]*model.Rule, error)
-	CreateRule(current model.User, group string, audit *string, countyID string, testTypeID string, priority *int, resultsStates []model.TestTypeResultCountyStatus) (*model.Rule, error)
-	UpdateRule(current model.User, group string, audit *string, ID string, priority *int, resultsStates []model.TestTypeResultCountyStatus) (*model.Rule, error)
-	DeleteRule(current model.User, group string, ID string) error
-
-	GetLocations() ([]*model.Location, error)
-	CreateLocation(current model.User, group string, audit *string, providerID string, countyID string, name string, address1 string, address2 string, city string,
+	GetCovid19Config(ctx context.Context) (*model.COVID19Config, error)
+	UpdateCovid19Config(ctx context.Context, config *model.COVID19Config) error
+
+	GetAppVersions(ctx context.Context) ([]string, error)
+	CreateAppVersion(ctx context.Context, current model.User, group string, audit *string, version string) error
+	GetAppVersionsStatuses(ctx context.Context) ([]*model.AppVersion, error)
+	UpdateAppVersionStatus(ctx context.Context, current model.User, group string, audit *string, version string, status string) error
+
+	GetNews(ctx context.Context) ([]*model.News, error)
+	CreateNews(ctx context.Context, current model.User, group string, audit *string, date time.Time, title string, description string, htmlContent string, link *string,
+		status string, publishAt *time.Time, expireAt *time.Time, pinned bool, categories []string) (*model.News, error)
+	UpdateNews(ctx context.Context, current model.User, group string, audit *string, ID string, date time.Time, title string, description string, htmlContent string, link *string,
+		status string, publishAt *time.Time, expireAt *time.Time, pinned bool, categories []string) (*model.News, error)
+	DeleteNews(ctx context.Context, current model.User, group string, ID string) error
+
+	GetNewsSources(ctx context.Context) ([]*model.NewsSource, error)
+	CreateNewsSource(ctx context.Context, current model.User, group string, audit *string, name string, URL string, format string, pollInterval int, enabled bool) (*model.NewsSource, error)
+	UpdateNewsSource(ctx context.Context, current model.User, group string, audit *string, ID string, name string, URL string, format string, pollInterval int, enabled bool) (*model.NewsSource, error)
+	DeleteNewsSource(ctx context.Context, current model.User, group string, ID string) error
+	GetDataProviderMetrics() []model.ProviderFetchMetrics
+
+	GetResources(ctx context.Context) ([]*model.Resource, error)
+	CreateResource(ctx context.Context, current model.User, group string, audit *string, title string, link string, displayOrder int) (*model.Resource, error)
+	UpdateResource(ctx context.Context, current model.User, group string, audit *string, ID string, title string, link string, displayOrder int) (*model.Resource, error)
+	DeleteResource(ctx context.Context, current model.User, group string, ID string) error
+	UpdateResourceDisplayOrder(ctx context.Context, IDs []string) error
+
+	GetResourceChanges(ctx context.Context, status *string) ([]*model.ResourceChange, error)
+	SyncResources(ctx context.Context) ([]*model.ResourceChange, error)
+	ApproveResourceChange(ctx context.Context, current model.User, group string, audit *string, ID string) (*model.ResourceChange, error)
+	RejectResourceChange(ctx context.Context, current model.User, group string, audit *string, ID string) (*model.ResourceChange, error)
+
+	GetFAQs(ctx context.Context) (*model.FAQ, error)
+	CreateFAQ(ctx context.Context, current model.User, group string, audit *string, section string, sectionDisplayOrder int, title string, description string, questionDisplayOrder int) error
+	UpdateFAQ(ctx context.Context, current model.User, group string, audit *string, ID string, title string, description string, displayOrder int) error
+	DeleteFAQ(ctx context.Context, current model.User, group string, ID string) error
+
+	DeleteFAQSection(ctx context.Context, current model.User, group string, ID string) error
+	UpdateFAQSection(ctx context.Context, current model.User, group string, audit *string, ID string, title string, displayOrder int) error
+
+	GetProviders(ctx context.Context) ([]*model.Provider, error)
+	CreateProvider(ctx context.Context, current model.User, group string, audit *string, providerName string, manualTest bool, availableMechanisms []string) (*model.Provider, error)
+	UpdateProvider(ctx context.Context, current model.User, group string, audit *string, ID string, providerName string, manualTest bool, availableMechanisms []string) (*model.Provider, error)
+	DeleteProvider(ctx context.Context, current model.User, group string, ID string) error
+
+	FindCounties(ctx context.Context, f *utils.Filter) ([]*model.County, error)
+	CreateCounty(ctx context.Context, current model.User, group string, audit *string, name string, stateProvince string, country string) (*model.County, error)
+	UpdateCounty(ctx context.Context, current model.User, group string, audit *string, ID string, name string, stateProvince string, country string) (*model.County, error)
+	DeleteCounty(ctx context.Context, current model.User, group string, ID string) error
+
+	CreateGuideline(ctx context.Context, current model.User, group string, audit *string, countyID string, name string, description string, items []model.GuidelineItem) (*model.Guideline, error)
+	UpdateGuideline(ctx context.Context, current model.User, group string, audit *string, ID string, name string, description string, items []model.GuidelineItem) (*model.Guideline, error)
+	DeleteGuideline(ctx context.Context, current model.User, group string, ID string) error
+	GetGuidelinesByCountyID(ctx context.Context, countyID string) ([]*model.Guideline, error)
+
+	CreateCountyStatus(ctx context.Context, current model.User, group string, audit *string, countyID string, name string, description string) (*model.CountyStatus, error)
+	UpdateCountyStatus(ctx context.Context, current model.User, group string, audit *string, ID string, name string, description string) (*model.CountyStatus, error)
+	DeleteCountyStatus(ctx context.Context, current model.User, group string, ID string) error
+	GetCountyStatusByCountyID(ctx context.Context, countyID string) ([]*model.CountyStatus, error)
+
+	GetTestTypes(ctx context.Context) ([]*model.TestType, error)
+	CreateTestType(ctx context.Context, current model.User, group string, audit *string, name string, priority *int) (*model.TestType, error)
+	UpdateTestType(ctx context.Context, current model.User, group string, audit *string, ID string, name string, priority *int) (*model.TestType, error)
+	DeleteTestType(ctx context.Context, current model.User, group string, ID string) error
+
+	CreateTestTypeResult(ctx context.Context, current model.User, group string, audit *string, testTypeID string, name string, nextStep string, nextStepOffset *int, resultExpiresOffset *int) (*model.TestTypeResult, error)
+	UpdateTestTypeResult(ctx context.Context, current model.User, group string, audit *string, ID string, name string, nextStep string, nextStepOffset *int, resultExpiresOffset *int) (*model.TestTypeResult, error)
+	DeleteTestTypeResult(ctx context.Context, current model.User, group string, ID string) error
+	GetTestTypeResultsByTestTypeID(ctx context.Context, testTypeID string) ([]*model.TestTypeResult, error)
+
+	GetRules(ctx context.Context) ([]*model.Rule, error)
+	CreateRule(ctx context.Context, current model.User, group string, audit *string, countyID string, testTypeID string, priority *int, resultsStates []model.TestTypeResultCountyStatus) (*model.Rule, error)
+	UpdateRule(ctx context.Context, current model.User, group string, audit *string, ID string, priority *int, resultsStates []model.TestTypeResultCountyStatus) (*model.Rule, error)
+	DeleteRule(ctx context.Context, current model.User, group string, ID string) error
+
+	GetLocations(ctx context.Context) ([]*model.Location, error)
+	CreateLocation(ctx context.Context, current model.User, group string, audit *string, providerID string, countyID string, name string, address1 string, address2 string, city string,
 		state string, zip string, country string, latitude float64, longitude float64, contact string,
 		daysOfOperation []model.OperationDay, url string, notes string, waitTimeColor *string, availableTests []string) (*model.Location, error)
-	UpdateLocation(current model.User, group string, audit *string, ID string, name string, address1 string, address2 string, city string,
+	UpdateLocation(ctx context.Context, current model.User, group string, audit *string, ID string, name string, address1 string, address2 string, city string,
 		state string, zip string, country string, latitude float64, longitude float64, contact string,
 		daysOfOperation []model.OperationDay, url string, notes string, waitTimeColor *string, availableTests []string) (*model.Location, error)
-	DeleteLocation(current model.User, group string, ID string) error
+	DeleteLocation(ctx context.Context, current model.User, group string, ID string) error
+	UpdateLocationExceptions(ctx context.Context, current model.User, group string, audit *string, ID string, exceptions []model.LocationException) (*model.Location, error)
+	ReportWaitTime(ctx context.Context, current model.User, group string, audit *string, locationID string, waitMinutes int, queueLength *int) (*model.WaitTimeReport, error)
+	GetWaitTimeReports(ctx context.Context, locationID string, limit int64) ([]*model.WaitTimeReport, error)
+	UpdateLocationAppointmentSettings(ctx context.Context, current model.User, group string, audit *string, ID string, settings *model.AppointmentSettings) (*model.Location, error)
+	GetLocationAppointments(ctx context.Context, locationID string, date string) ([]*model.Appointment, error)
+
+	CreateSymptom(ctx context.Context, current model.User, group string, Name string, SymptomGroup string) (*model.Symptom, error)
+	UpdateSymptom(ctx context.Context, current model.User, group string, ID string, name string) (*model.Symptom, error)
+	DeleteSymptom(ctx context.Context, current model.User, group string, ID string) error
 
-	CreateSymptom(current model.User, group string, Name string, SymptomGroup string) (*model.Symptom, error)
-	UpdateSymptom(current model.User, group string, ID string, name string) (*model.Symptom, error)
-	DeleteSymptom(current model.User, group string, ID string) error
+	GetSymptomGroups(ctx context.Context) ([]*model.SymptomGroup, error)
 
-	GetSymptomGroups() ([]*model.SymptomGroup, error)
+	GetSymptomRules(ctx context.Context) ([]*model.SymptomRule, error)
+	CreateSymptomRule(ctx context.Context, current model.User, group string, countyID string, gr1Count int, gr2Count int, items []model.SymptomRuleItem) (*model.SymptomRule, error)
+	UpdateSymptomRule(ctx context.Context, current model.User, group string, ID string, countyID string, gr1Count int, gr2Count int, items []model.SymptomRuleItem) (*model.SymptomRule, error)
+	DeleteSymptomRule(ctx context.Context, current model.User, group string, ID string) error
 
-	GetSymptomRules() ([]*model.SymptomRule, error)
-	CreateSymptomRule(current model.User, group string, countyID string, gr1Count int, gr2Count int, items []model.SymptomRuleItem) (*model.SymptomRule, error)
-	UpdateSymptomRule(current model.User, group string, ID string, countyID string, gr1Count int, gr2Count int, items []model.SymptomRuleItem) (*model.SymptomRule, error)
-	DeleteSymptomRule(current model.User, group string, ID string) error
+	GetManualTestByCountyID(ctx context.Context, countyID string, status *string) ([]*model.EManualTest, error)
+	ProcessManualTest(ctx context.Context, ID string, status string, encryptedKey *string, encryptedBlob *string) error
+	GetManualTestImage(ctx context.Context, ID string) (*string, *string, error)
 
-	GetManualTestByCountyID(countyID string, status *string) ([]*model.EManualTest, error)
-	ProcessManualTest(ID string, status string, encryptedKey *string, encryptedBlob *string) error
-	GetManualTestImage(ID string) (*string, *string, error)
+	GetAccessRules(ctx context.Context) ([]*model.AccessRule, error)
+	CreateAccessRule(ctx context.Context, current model.User, group string, audit *string, countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error)
+	UpdateAccessRule(ctx context.Context, current model.User, group string, audit *string, ID string, countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error)
+	DeleteAccessRule(ctx context.Context, current model.User, group string, ID string) error
 
-	GetAccessRules() ([]*model.AccessRule, error)
-	CreateAccessRule(current model.User, group string, audit *string, countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error)
-	UpdateAccessRule(current model.User, group string, audit *string, ID string, countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error)
-	DeleteAccessRule(current model.User, group string, ID string) error
+	GetCRules(ctx context.Context, countyID string, appVersion string) (*model.CRules, error)
+	CreateOrUpdateCRules(ctx context.Context, current model.User, group string, audit *string, countyID string, appVersion string, data string) error
 
-	GetCRules(countyID string, appVersion string) (*model.CRules, error)
-	CreateOrUpdateCRules(current model.User, group string, audit *string, countyID string, appVersion string, data string) error
+	GetSymptoms(ctx context.Context, appVersion string) (*model.Symptoms, error)
+	CreateOrUpdateSymptoms(ctx context.Context, current model.User, group string, audit *string, appVersion string, items string) error
 
-	GetSymptoms(appVersion string) (*model.Symptoms, error)
-	CreateOrUpdateSymptoms(current model.User, group string, audit *string, appVersion string, items string) error
+	GetConfigRevisions(ctx context.Context, configType string, countyID string, appVersion string) ([]*model.ConfigRevision, error)
+	SaveConfigDraft(ctx context.Context, current model.User, group string, audit *string, configType string, countyID string, appVersion string, data string) (*model.ConfigRevision, error)
+	DiscardConfigDraft(ctx context.Context, current model.User, group string, audit *string, configType string, countyID string, appVersion string) (*model.ConfigRevision, error)
+	PublishConfigDraft(ctx context.Context, current model.User, group string, audit *string, configType string, countyID string, appVersion string) (*model.ConfigRevision, error)
+	RollbackConfig(ctx context.Context, current model.User, group string, audit *string, configType string, countyID string, appVersion string, number int) (*model.ConfigRevision, error)
 
-	GetUINOverrides(uin *string, sort *string) ([]*model.UINOverride, error)
-	CreateUINOverride(current model.User, group string, audit *string, uin string, interval int, category *string, expiration *time.Time) (*model.UINOverride, error)
-	UpdateUINOverride(current model.User, group string, audit *string, uin string, interval int, category *string, expiration *time.Time) (*string, error)
-	DeleteUINOverride(current model.User, group string, uin string) error
+	GetConfigSchemas(ctx context.Context, appVersion *string) ([]*model.ConfigSchema, error)
+	SaveConfigSchema(ctx context.Context, current model.User, group string, audit *string, configType string, appVersion string, schema string) (*model.ConfigSchema, error)
+	DeleteConfigSchema(ctx context.Context, current model.User, group string, configType string, appVersion string) error
 
-	GetUserByExternalID(externalID string) (*model.User, error)
+	GetUINOverrides(ctx context.Context, uin *string, sort *string) ([]*model.UINOverride, error)
+	CreateUINOverride(ctx context.Context, current model.User, group string, audit *string, uin string, interval int, category *string, expiration *time.Time) (*model.UINOverride, error)
+	UpdateUINOverride(ctx context.Context, current model.User, group string, audit *string, uin string, interval int, category *string, expiration *time.Time) (*string, error)
+	DeleteUINOverride(ctx context.Context, current model.User, group string, uin string) error
 
-	CreateAction(current model.User, group string, audit *string, providerID string, userID string, encryptedKey string, encryptedBlob string) (*model.CTest, error)
+	GetUserByExternalID(ctx context.Context, externalID string) (*model.User, error)
 
-	GetAudit(current model.User, group string, userIdentifier *string, entity *string, entityID *string, operation *string, clientData *string,
+	CreateAction(ctx context.Context, current model.User, group string, audit *string, providerID string, userID string, encryptedKey string, encryptedBlob string) (*model.CTest, error)
+
+	GetAudit(ctx context.Context, current model.User, group string, userIdentifier *string, entity *string, entityID *string, operation *string, clientData *string,
 		createdAt *time.Time, sortBy *string, asc *bool, limit *int64) ([]*AuditEntity, error)
+
+	GetRetentionPolicies(ctx context.Context) ([]*model.RetentionPolicy, error)
+	UpdateRetentionPolicy(ctx context.Context, current model.User, group string, audit *string, entity string, days int, enabled bool) (*model.RetentionPolicy, error)
+
+	ExportCountyBundle(ctx context.Context, countyID string) (*model.CountyBundle, error)
+	ImportCountyBundle(ctx context.Context, current model.User, group string, audit *string, bundle model.CountyBundle, name *string) (*model.County, error)
+	ApplyCountyBundle(ctx context.Context, current model.User, group string, audit *string, countyID string, bundle model.CountyBundle) ([]model.BundleChange, error)
+	DiffCountyBundle(ctx context.Context, countyID string, bundle model.CountyBundle) ([]model.BundleChange, error)
+
+	SimulateCountyStatus(ctx context.Context, countyID string, draft *model.RulesDraft, timeline []model.SimulationEvent, points []time.Time) ([]model.SimulationStep, error)
+
+	GetErasureReceiptsByUIN(ctx context.Context, uin string) ([]*model.ErasureReceipt, error)
+	GetErasureReceipt(ctx context.Context, ID string) (*model.ErasureReceipt, bool, error)
 }
 
 type administrationImpl struct {
 	app *Application
 }
 
-func (s *administrationImpl) GetCovid19Config() (*model.COVID19Config, error) {
-	return s.app.getCovid19Config()
+func (s *administrationImpl) GetCovid19Config(ctx context.Context) (*model.COVID19Config, error) {
+	return s.app.getCovid19Config(ctx)
 }
 
-func (s *administrationImpl) UpdateCovid19Config(config *model.COVID19Config) error {
-	return s.app.updateCovid19Config(config)
+func (s *administrationImpl) UpdateCovid19Config(ctx context.Context, config *model.COVID19Config) error {
+	return s.app.updateCovid19Config(ctx, config)
 }
 
-func (s *administrationImpl) GetNews() ([]*model.News, error) {
-	return s.app.getAllNews()
+func (s *administrationImpl) GetNews(ctx context.Context) ([]*model.News, error) {
+	return s.app.getAllNews(ctx)
 }
 
-func (s *administrationImpl) GetAppVersions() ([]string, error) {
-	return s.app.getAppVersions()
+func (s *administrationImpl) GetAppVersions(ctx context.Context) ([]string, error) {
+	return s.app.getAppVersions(ctx)
 }
 
-func (s *administrationImpl) CreateAppVersion(current model.User, group string, audit *string, version string) error {
-	return s.app.createAppVersion(current, group, audit, version)
+func (s *administrationImpl) CreateAppVersion(ctx context.Context, current model.User, group string, audit *string, version string) error {
+	return s.app.createAppVersion(ctx, current, group, audit, version)
 }
 
-func (s *administrationImpl) CreateNews(current model.User, group string, audit *string, date time.Time, title string, description string, htmlContent string, link *string) (*model.News, error) {
-	return s.app.createNews(current, group, audit, date, title, description, htmlContent, link)
+func (s *administrationImpl) GetAppVersionsStatuses(ctx context.Context) ([]*model.AppVersion, error) {
+	return s.app.getAppVersionsStatuses(ctx)
 }
 
-func (s *administrationImpl) UpdateNews(current model.User, group string, audit *string, ID string, date time.Time, title string, description string, htmlContent string, link *string) (*model.News, error) {
-	return s.app.updateNews(current, group, audit, ID, date, title, description, htmlContent, nil)
+func (s *administrationImpl) UpdateAppVersionStatus(ctx context.Context, current model.User, group string, audit *string, version string, status string) error {
+	return s.app.updateAppVersionStatus(ctx, current, group, audit, version, status)
 }
 
-func (s *administrationImpl) DeleteNews(current model.User, group string, ID string) error {
-	return s.app.deleteNews(current, group, ID)
+func (s *administrationImpl) CreateNews(ctx context.Context, current model.User, group string, audit *string, date time.Time, title string, description string, htmlContent string, link *string,
+	status string, publishAt *time.Time, expireAt *time.Time, pinned bool, categories []string) (*model.News, error) {
+	return s.app.createNews(ctx, current, group, audit, date, title, description, htmlContent, link, status, publishAt, expireAt, pinned, categories)
 }
 
-func (s *administrationImpl) GetResources() ([]*model.Resource, error) {
-	return s.app.getAllResources()
+func (s *administrationImpl) UpdateNews(ctx context.Context, current model.User, group string, audit *string, ID string, date time.Time, title string, description string, htmlContent string, link *string,
+	status string, publishAt *time.Time, expireAt *time.Time, pinned bool, categories []string) (*model.News, error) {
+	return s.app.updateNews(ctx, current, group, audit, ID, date, title, description, htmlContent, nil, status, publishAt, expireAt, pinned, categories)
 }
 
-func (s *administrationImpl) CreateResource(current model.User, group string, audit *string, title string, link string, displayOrder int) (*model.Resource, error) {
-	return s.app.createResource(current, group, audit, title, link, displayOrder)
+func (s *administrationImpl) DeleteNews(ctx context.Context, current model.User, group string, ID string) error {
+	return s.app.deleteNews(ctx, current, group, ID)
 }
 
-func (s *administrationImpl) UpdateResource(current model.User, group string, audit *string, ID string, title string, link string, displayOrder int) (*model.Resource, error) {
-	return s.app.updateResource(current, group, audit, ID, title, link, displayOrder)
+func (s *administrationImpl) GetNewsSources(ctx context.Context) ([]*model.NewsSource, error) {
+	return s.app.getNewsSources(ctx)
 }
 
-func (s *administrationImpl) DeleteResource(current model.User, group string, ID string) error {
-	return s.app.deleteResource(current, group, ID)
+func (s *administrationImpl) CreateNewsSource(ctx context.Context, current model.User, group string, audit *string, name string, URL string, format string, pollInterval int, enabled bool) (*model.NewsSource, error) {
+	return s.app.createNewsSource(ctx, current, group, audit, name, URL, format, pollInterval, enabled)
 }
 
-func (s *administrationImpl) UpdateResourceDisplayOrder(IDs []string) error {
-	return s.app.updateResourceDisplayOrder(IDs)
+func (s *administrationImpl) UpdateNewsSource(ctx context.Context, current model.User, group string, audit *string, ID string, name string, URL string, format string, pollInterval int, enabled bool) (*model.NewsSource, error) {
+	return s.app.updateNewsSource(ctx, current, group, audit, ID, name, URL, format, pollInterval, enabled)
 }
 
-func (s *administrationImpl) GetFAQs() (*model.FAQ, error) {
-	return s.app.getFAQs()
+func (s *administrationImpl) DeleteNewsSource(ctx context.Context, current model.User, group string, ID string) error {
+	return s.app.deleteNewsSource(ctx, current, group, ID)
 }
 
-func (s *administrationImpl) CreateFAQ(current model.User, group string, audit *string, section string, sectionDisplayOrder int, title string, description string, questionDisplayOrder int) error {
-	return s.app.createFAQ(current, group, audit, section, sectionDisplayOrder, title, description, questionDisplayOrder)
+func (s *administrationImpl) GetDataProviderMetrics() []model.ProviderFetchMetrics {
+	return s.app.dataProvider.GetFetchMetrics()
 }
 
-func (s *administrationImpl) UpdateFAQ(current model.User, group string, audit *string, ID string, title string, description string, displayOrder int) error {
-	return s.app.updateFAQ(current, group, audit, ID, title, description, displayOrder)
+func (s *administrationImpl) GetResources(ctx context.Context) ([]*model.Resource, error) {
+	return s.app.getAllResources(ctx)
 }
 
-func (s *administrationImpl) DeleteFAQ(current model.User, group string, ID string) error {
-	return s.app.deleteFAQ(current, group, ID)
+func (s *administrationImpl) CreateResource(ctx context.Context, current model.User, group string, audit *string, title string, link string, displayOrder int) (*model.Resource, error) {
+	return s.app.createResource(ctx, current, group, audit, title, link, displayOrder)
 }
 
-func (s *administrationImpl) DeleteFAQSection(current model.User, group string, ID string) error {
-	return s.app.deleteFAQSection(current, group, ID)
+func (s *administrationImpl) UpdateResource(ctx context.Context, current model.User, group string, audit *string, ID string, title string, link string, displayOrder int) (*model.Resource, error) {
+	return s.app.updateResource(ctx, current, group, audit, ID, title, link, displayOrder)
 }
 
-func (s *administrationImpl) UpdateFAQSection(current model.User, group string, audit *string, ID string, title string, displayOrder int) error {
-	return s.app.updateFAQSection(current, group, audit, ID, title, displayOrder)
+func (s *administrationImpl) DeleteResource(ctx context.Context, current model.User, group string, ID string) error {
+	return s.app.deleteResource(ctx, current, group, ID)
 }
 
-func (s *administrationImpl) GetProviders() ([]*model.Provider, error) {
-	return s.app.getProviders()
+func (s *administrationImpl) UpdateResourceDisplayOrder(ctx context.Context, IDs []string) error {
+	return s.app.updateResourceDisplayOrder(ctx, IDs)
 }
 
-func (s *administrationImpl) CreateProvider(current model.User, group string, audit *string, providerName string, manualTest bool, availableMechanisms []string) (*model.Provider, error) {
-	return s.app.createProvider(current, group, audit, providerName, manualTest, availableMechanisms)
+func (s *administrationImpl) GetResourceChanges(ctx context.Context, status *string) ([]*model.ResourceChange, error) {
+	return s.app.getResourceChanges(ctx, status)
 }
 
-func (s *administrationImpl) UpdateProvider(current model.User, group string, audit *string, ID string, providerName string, manualTest bool, availableMechanisms []string) (*model.Provider, error) {
-	return s.app.updateProvider(current, group, audit, ID, providerName, manualTest, availableMechanisms)
+func (s *administrationImpl) SyncResources(ctx context.Context) ([]*model.ResourceChange, error) {
+	return s.app.syncResources(ctx)
 }
 
-func (s *administrationImpl) DeleteProvider(current model.User, group string, ID string) error {
-	return s.app.deleteProvider(current, group, ID)
+func (s *administrationImpl) ApproveResourceChange(ctx context.Context, current model.User, group string, audit *string, ID string) (*model.ResourceChange, error) {
+	return s.app.approveResourceChange(ctx, current, group, audit, ID)
 }
 
-func (s *administrationImpl) FindCounties(f *utils.Filter) ([]*model.County, error) {
-	return s.app.findCounties(f)
+func (s *administrationImpl) RejectResourceChange(ctx context.Context, current model.User, group string, audit *string, ID string) (*model.ResourceChange, error) {
+	return s.app.rejectResourceChange(ctx, current, group, audit, ID)
 }
 
-func (s *administrationImpl) CreateCounty(current model.User, group string, audit *string, name string, stateProvince string, country string) (*model.County, error) {
-	return s.app.createCounty(current, group, audit, name, stateProvince, country)
+func (s *administrationImpl) GetFAQs(ctx context.Context) (*model.FAQ, error) {
+	return s.app.getFAQs(ctx)
 }
 
-func (s *administrationImpl) UpdateCounty(current model.User, group string, audit *string, ID string, name string, stateProvince string, country string) (*model.County, error) {
-	return s.app.updateCounty(current, group, audit, ID, name, stateProvince, country)
+func (s *administrationImpl) CreateFAQ(ctx context.Context, current model.User, group string, audit *string, section string, sectionDisplayOrder int, title string, description string, questionDisplayOrder int) error {
+	return s.app.createFAQ(ctx, current, group, audit, section, sectionDisplayOrder, title, description, questionDisplayOrder)
 }
 
-func (s *administrationImpl) DeleteCounty(current model.User, group string, ID string) error {
-	return s.app.deleteCounty(current, group, ID)
+func (s *administrationImpl) UpdateFAQ(ctx context.Context, current model.User, group string, audit *string, ID string, title string, description string, displayOrder int) error {
+	return s.app.updateFAQ(ctx, current, group, audit, ID, title, description, displayOrder)
 }
 
-func (s *administrationImpl) CreateGuideline(current model.User, group string, audit *string, countyID string, name string, description string, items []model.GuidelineItem) (*model.Guideline, error) {
-	return s.app.createGuideline(current, group, audit, countyID, name, description, items)
+func (s *administrationImpl) DeleteFAQ(ctx context.Context, current model.User, group string, ID string) error {
+	return s.app.deleteFAQ(ctx, current, group, ID)
 }
 
-func (s *administrationImpl) UpdateGuideline(current model.User, group string, audit *string, ID string, name string, description string, items []model.GuidelineItem) (*model.Guideline, error) {
-	return s.app.updateGuideline(current, group, audit, ID, name, description, items)
+func (s *administrationImpl) DeleteFAQSection(ctx context.Context, current model.User, group string, ID string) error {
+	return s.app.deleteFAQSection(ctx, current, group, ID)
 }
 
-func (s *administrationImpl) DeleteGuideline(current model.User, group string, ID string) error {
-	return s.app.deleteGuideline(current, group, ID)
+func (s *administrationImpl) UpdateFAQSection(ctx context.Context, current model.User, group string, audit *string, ID string, title string, displayOrder int) error {
+	return s.app.updateFAQSection(ctx, current, group, audit, ID, title, displayOrder)
 }
 
-func (s *administrationImpl) GetGuidelinesByCountyID(countyID string) ([]*model.Guideline, error) {
-	return s.app.getGuidelinesByCountyID(countyID)
+func (s *administrationImpl) GetProviders(ctx context.Context) ([]*model.Provider, error) {
+	return s.app.getProviders(ctx)
 }
 
-func (s *administrationImpl) CreateCountyStatus(current model.User, group string, audit *string, countyID string, name string, description string) (*model.CountyStatus, error) {
-	return s.app.createCountyStatus(current, group, audit, countyID, name, description)
+func (s *administrationImpl) CreateProvider(ctx context.Context, current model.User, group string, audit *string, providerName string, manualTest bool, availableMechanisms []string) (*model.Provider, error) {
+	return s.app.createProvider(ctx, current, group, audit, providerName, manualTest, availableMechanisms)
 }
 
-func (s *administrationImpl) UpdateCountyStatus(current model.User, group string, audit *string, ID string, name string, description string) (*model.CountyStatus, error) {
-	return s.app.updateCountyStatus(current, group, audit, ID, name, description)
+func (s *administrationImpl) UpdateProvider(ctx context.Context, current model.User, group string, audit *string, ID string, providerName string, manualTest bool, availableMechanisms []string) (*model.Provider, error) {
+	return s.app.updateProvider(ctx, current, group, audit, ID, providerName, manualTest, availableMechanisms)
 }
 
-func (s *administrationImpl) DeleteCountyStatus(current model.User, group string, ID string) error {
-	return s.app.deleteCountyStatus(current, group, ID)
+func (s *administrationImpl) DeleteProvider(ctx context.Context, current model.User, group string, ID string) error {
+	return s.app.deleteProvider(ctx, current, group, ID)
 }
 
-func (s *administrationImpl) GetCountyStatusByCountyID(countyID string) ([]*model.CountyStatus, error) {
-	return s.app.getCountyStatusByCountyID(countyID)
+func (s *administrationImpl) FindCounties(ctx context.Context, f *utils.Filter) ([]*model.County, error) {
+	return s.app.findCounties(ctx, f)
 }
 
-func (s *administrationImpl) GetTestTypes() ([]*model.TestType, error) {
-	return s.app.getTestTypes()
+func (s *administrationImpl) CreateCounty(ctx context.Context, current model.User, group string, audit *string, name string, stateProvince string, country string) (*model.County, error) {
+	return s.app.createCounty(ctx, current, group, audit, name, stateProvince, country)
 }
 
-func (s *administrationImpl) CreateTestType(current model.User, group string, audit *string, name string, priority *int) (*model.TestType, error) {
-	return s.app.createTestType(current, group, audit, name, priority)
+func (s *administrationImpl) UpdateCounty(ctx context.Context, current model.User, group string, audit *string, ID string, name string, stateProvince string, country string) (*model.County, error) {
+	return s.app.updateCounty(ctx, current, group, audit, ID, name, stateProvince, country)
 }
 
-func (s *administrationImpl) UpdateTestType(current model.User, group string, audit *string, ID string, name string, priority *int) (*model.TestType, error) {
-	return s.app.updateTestType(current, group, audit, ID, name, priority)
+func (s *administrationImpl) DeleteCounty(ctx context.Context, current model.User, group string, ID string) error {
+	return s.app.deleteCounty(ctx, current, group, ID)
 }
 
-func (s *administrationImpl) DeleteTestType(current model.User, group string, ID string) error {
-	return s.app.deleteTestType(current, group, ID)
+func (s *administrationImpl) CreateGuideline(ctx context.Context, current model.User, group string, audit *string, countyID string, name string, description string, items []model.GuidelineItem) (*model.Guideline, error) {
+	return s.app.createGuideline(ctx, current, group, audit, countyID, name, description, items)
 }
 
-func (s *administrationImpl) CreateTestTypeResult(current model.User, group string, audit *string, testTypeID string, name string, nextStep string, nextStepOffset *int, resultExpiresOffset *int) (*model.TestTypeResult, error) {
-	return s.app.createTestTypeResult(current, group, audit, testTypeID, name, nextStep, nextStepOffset, resultExpiresOffset)
+func (s *administrationImpl) UpdateGuideline(ctx context.Context, current model.User, group string, audit *string, ID string, name string, description string, items []model.GuidelineItem) (*model.Guideline, error) {
+	return s.app.updateGuideline(ctx, current, group, audit, ID, name, description, items)
 }
 
-func (s *administrationImpl) UpdateTestTypeResult(current model.User, group string, audit *string, ID string, name string, nextStep string, nextStepOffset *int, resultExpiresOffset *int) (*model.TestTypeResult, error) {
-	return s.app.updateTestTypeResult(current, group, audit, ID, name, nextStep, nextStepOffset, resultExpiresOffset)
+func (s *administrationImpl) DeleteGuideline(ctx context.Context, current model.User, group string, ID string) error {
+	return s.app.deleteGuideline(ctx, current, group, ID)
 }
 
-func (s *administrationImpl) DeleteTestTypeResult(current model.User, group string, ID string) error {
-	return s.app.deleteTestTypeResult(current, group, ID)
+func (s *administrationImpl) GetGuidelinesByCountyID(ctx context.Context, countyID string) ([]*model.Guideline, error) {
+	return s.app.getGuidelinesByCountyID(ctx, countyID)
 }
 
-func (s *administrationImpl) GetTestTypeResultsByTestTypeID(testTypeID string) ([]*model.TestTypeResult, error) {
-	return s.app.getTestTypeResultsByTestTypeID(testTypeID)
+func (s *administrationImpl) CreateCountyStatus(ctx context.Context, current model.User, group string, audit *string, countyID string, name string, description string) (*model.CountyStatus, error) {
+	return s.app.createCountyStatus(ctx, current, group, audit, countyID, name, description)
 }
 
-func (s *administrationImpl) GetRules() ([]*model.Rule, error) {
-	return s.app.getRules()
+func (s *administrationImpl) UpdateCountyStatus(ctx context.Context, current model.User, group string, audit *string, ID string, name string, description string) (*model.CountyStatus, error) {
+	return s.app.updateCountyStatus(ctx, current, group, audit, ID, name, description)
 }
 
-func (s *administrationImpl) CreateRule(current model.User, group string, audit *string, countyID string, testTypeID string, priority *int, resultsStatuses []model.TestTypeResultCountyStatus) (*model.Rule, error) {
-	return s.app.createRule(current, group, audit, countyID, testTypeID, priority, resultsStatuses)
+func (s *administrationImpl) DeleteCountyStatus(ctx context.Context, current model.User, group string, ID string) error {
+	return s.app.deleteCountyStatus(ctx, current, group, ID)
 }
 
-func (s *administrationImpl) UpdateRule(current model.User, group string, audit *string, ID string, priority *int, resultsStates []model.TestTypeResultCountyStatus) (*model.Rule, error) {
-	return s.app.updateRule(current, group, audit, ID, priority, resultsStates)
+func (s *administrationImpl) GetCountyStatusByCountyID(ctx context.Context, countyID string) ([]*model.CountyStatus, error) {
+	return s.app.getCountyStatusByCountyID(ctx, countyID)
 }
 
-func (s *administrationImpl) DeleteRule(current model.User, group string, ID string) error {
-	return s.app.deleteRule(current, group, ID)
+func (s *administrationImpl) GetTestTypes(ctx context.Context) ([]*model.TestType, error) {
+	return s.app.getTestTypes(ctx)
 }
 
-func (s *administrationImpl) GetLocations() ([]*model.Location, error) {
-	return s.app.getLocations()
+func (s *administrationImpl) CreateTestType(ctx context.Context, current model.User, group string, audit *string, name string, priority *int) (*model.TestType, error) {
+	return s.app.createTestType(ctx, current, group, audit, name, priority)
 }
 
-func (s *administrationImpl) CreateLocation(current model.User, group string, audit *string, providerID string, countyID string, name string, address1 string, address2 string, city string,
+func (s *administrationImpl) UpdateTestType(ctx context.Context, current model.User, group string, audit *string, ID string, name string, priority *int) (*model.TestType, error) {
+	return s.app.updateTestType(ctx, current, group, audit, ID, name, priority)
+}
+
+func (s *administrationImpl) DeleteTestType(ctx context.Context, current model.User, group string, ID string) error {
+	return s.app.deleteTestType(ctx, current, group, ID)
+}
+
+func (s *administrationImpl) CreateTestTypeResult(ctx context.Context, current model.User, group string, audit *string, testTypeID string, name string, nextStep string, nextStepOffset *int, resultExpiresOffset *int) (*model.TestTypeResult, error) {
+	return s.app.createTestTypeResult(ctx, current, group, audit, testTypeID, name, nextStep, nextStepOffset, resultExpiresOffset)
+}
+
+func (s *administrationImpl) UpdateTestTypeResult(ctx context.Context, current model.User, group string, audit *string, ID string, name string, nextStep string, nextStepOffset *int, resultExpiresOffset *int) (*model.TestTypeResult, error) {
+	return s.app.updateTestTypeResult(ctx, current, group, audit, ID, name, nextStep, nextStepOffset, resultExpiresOffset)
+}
+
+func (s *administrationImpl) DeleteTestTypeResult(ctx context.Context, current model.User, group string, ID string) error {
+	return s.app.deleteTestTypeResult(ctx, current, group, ID)
+}
+
+func (s *administrationImpl) GetTestTypeResultsByTestTypeID(ctx context.Context, testTypeID string) ([]*model.TestTypeResult, error) {
+	return s.app.getTestTypeResultsByTestTypeID(ctx, testTypeID)
+}
+
+func (s *administrationImpl) GetRules(ctx context.Context) ([]*model.Rule, error) {
+	return s.app.getRules(ctx)
+}
+
+func (s *administrationImpl) CreateRule(ctx context.Context, current model.User, group string, audit *string, countyID string, testTypeID string, priority *int, resultsStatuses []model.TestTypeResultCountyStatus) (*model.Rule, error) {
+	return s.app.createRule(ctx, current, group, audit, countyID, testTypeID, priority, resultsStatuses)
+}
+
+func (s *administrationImpl) UpdateRule(ctx context.Context, current model.User, group string, audit *string, ID string, priority *int, resultsStates []model.TestTypeResultCountyStatus) (*model.Rule, error) {
+	return s.app.updateRule(ctx, current, group, audit, ID, priority, resultsStates)
+}
+
+func (s *administrationImpl) DeleteRule(ctx context.Context, current model.User, group string, ID string) error {
+	return s.app.deleteRule(ctx, current, group, ID)
+}
+
+func (s *administrationImpl) GetLocations(ctx context.Context) ([]*model.Location, error) {
+	return s.app.getLocations(ctx)
+}
+
+func (s *administrationImpl) CreateLocation(ctx context.Context, current model.User, group string, audit *string, providerID string, countyID string, name string, address1 string, address2 string, city string,
 	state string, zip string, country string, latitude float64, longitude float64, contact string,
 	daysOfOperation []model.OperationDay, url string, notes string, waitTimeColor *string, availableTests []string) (*model.Location, error) {
-	return s.app.createLocation(current, group, audit, providerID, countyID, name, address1, address2, city, state, zip, country,
+	return s.app.createLocation(ctx, current, group, audit, providerID, countyID, name, address1, address2, city, state, zip, country,
 		latitude, longitude, contact, daysOfOperation, url, notes, waitTimeColor, availableTests)
 }
 
-func (s *administrationImpl) UpdateLocation(current model.User, group string, audit *string, ID string, name string, address1 string, address2 string, city string,
+func (s *administrationImpl) UpdateLocation(ctx context.Context, current model.User, group string, audit *string, ID string, name string, address1 string, address2 string, city string,
 	state string, zip string, country string, latitude float64, longitude float64, contact string,
 	daysOfOperation []model.OperationDay, url string, notes string, waitTimeColor *string, availableTests []string) (*model.Location, error) {
-	return s.app.updateLocation(current, group, audit, ID, name, address1, address2, city, state, zip, country,
+	return s.app.updateLocation(ctx, current, group, audit, ID, name, address1, address2, city, state, zip, country,
 		latitude, longitude, contact, daysOfOperation, url, notes, waitTimeColor, availableTests)
 }
 
-func (s *administrationImpl) DeleteLocation(current model.User, group string, ID string) error {
-	return s.app.deleteLocation(current, group, ID)
+func (s *administrationImpl) DeleteLocation(ctx context.Context, current model.User, group string, ID string) error {
+	return s.app.deleteLocation(ctx, current, group, ID)
 }
 
-func (s *administrationImpl) CreateSymptom(current model.User, group string, name string, symptomGroup string) (*model.Symptom, error) {
-	return s.app.createSymptom(current, group, name, symptomGroup)
+func (s *administrationImpl) UpdateLocationExceptions(ctx context.Context, current model.User, group string, audit *string, ID string, exceptions []model.LocationException) (*model.Location, error) {
+	return s.app.updateLocationExceptions(ctx, current, group, audit, ID, exceptions)
 }
 
-func (s *administrationImpl) UpdateSymptom(current model.User, group string, ID string, name string) (*model.Symptom, error) {
-	return s.app.updateSymptom(current, group, ID, name)
+func (s *administrationImpl) ReportWaitTime(ctx context.Context, current model.User, group string, audit *string, locationID string, waitMinutes int, queueLength *int) (*model.WaitTimeReport, error) {
+	return s.app.reportWaitTimeByAdmin(ctx, current, group, audit, locationID, waitMinutes, queueLength)
 }
 
-func (s *administrationImpl) DeleteSymptom(current model.User, group string, ID string) error {
-	return s.app.deleteSymptom(current, group, ID)
+func (s *administrationImpl) GetWaitTimeReports(ctx context.Context, locationID string, limit int64) ([]*model.WaitTimeReport, error) {
+	return s.app.getWaitTimeReports(ctx, locationID, limit)
 }
 
-func (s *administrationImpl) GetSymptomGroups() ([]*model.SymptomGroup, error) {
-	return s.app.getSymptomGroups()
+func (s *administrationImpl) UpdateLocationAppointmentSettings(ctx context.Context, current model.User, group string, audit *string, ID string, settings *model.AppointmentSettings) (*model.Location, error) {
+	return s.app.updateLocationAppointmentSettings(ctx, current, group, audit, ID, settings)
 }
 
-func (s *administrationImpl) GetSymptomRules() ([]*model.SymptomRule, error) {
-	return s.app.getSymptomRules()
+func (s *administrationImpl) GetLocationAppointments(ctx context.Context, locationID string, date string) ([]*model.Appointment, error) {
+	return s.app.getLocationAppointments(ctx, locationID, date)
 }
 
-func (s *administrationImpl) CreateSymptomRule(current model.User, group string, countyID string, gr1Count int, gr2Count int, items []model.SymptomRuleItem) (*model.SymptomRule, error) {
-	return s.app.createSymptomRule(current, group, countyID, gr1Count, gr2Count, items)
+func (s *administrationImpl) CreateSymptom(ctx context.Context, current model.User, group string, name string, symptomGroup string) (*model.Symptom, error) {
+	return s.app.createSymptom(ctx, current, group, name, symptomGroup)
 }
 
-func (s *administrationImpl) UpdateSymptomRule(current model.User, group string, ID string, countyID string, gr1Count int, gr2Count int, items []model.SymptomRuleItem) (*model.SymptomRule, error) {
-	return s.app.updateSymptomRule(current, group, ID, countyID, gr1Count, gr2Count, items)
+func (s *administrationImpl) UpdateSymptom(ctx context.Context, current model.User, group string, ID string, name string) (*model.Symptom, error) {
+	return s.app.updateSymptom(ctx, current, group, ID, name)
 }
 
-func (s *administrationImpl) DeleteSymptomRule(current model.User, group string, ID string) error {
-	return s.app.deleteSymptomRule(current, group, ID)
+func (s *administrationImpl) DeleteSymptom(ctx context.Context, current model.User, group string, ID string) error {
+	return s.app.deleteSymptom(ctx, current, group, ID)
 }
 
-func (s *administrationImpl) GetManualTestByCountyID(countyID string, status *string) ([]*model.EManualTest, error) {
-	return s.app.getManualTestByCountyID(countyID, status)
+func (s *administrationImpl) GetSymptomGroups(ctx context.Context) ([]*model.SymptomGroup, error) {
+	return s.app.getSymptomGroups(ctx)
 }
 
-func (s *administrationImpl) ProcessManualTest(ID string, status string, encryptedKey *string, encryptedBlob *string) error {
-	return s.app.processManualTest(ID, status, encryptedKey, encryptedBlob)
+func (s *administrationImpl) GetSymptomRules(ctx context.Context) ([]*model.SymptomRule, error) {
+	return s.app.getSymptomRules(ctx)
 }
 
-func (s *administrationImpl) GetManualTestImage(ID string) (*string, *string, error) {
-	return s.app.getManualTestImage(ID)
+func (s *administrationImpl) CreateSymptomRule(ctx context.Context, current model.User, group string, countyID string, gr1Count int, gr2Count int, items []model.SymptomRuleItem) (*model.SymptomRule, error) {
+	return s.app.createSymptomRule(ctx, current, group, countyID, gr1Count, gr2Count, items)
 }
 
-func (s *administrationImpl) GetAccessRules() ([]*model.AccessRule, error) {
-	return s.app.getAccessRules()
+func (s *administrationImpl) UpdateSymptomRule(ctx context.Context, current model.User, group string, ID string, countyID string, gr1Count int, gr2Count int, items []model.SymptomRuleItem) (*model.SymptomRule, error) {
+	return s.app.updateSymptomRule(ctx, current, group, ID, countyID, gr1Count, gr2Count, items)
 }
 
-func (s *administrationImpl) CreateAccessRule(current model.User, group string, audit *string, countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error) {
-	return s.app.createAccessRule(current, group, audit, countyID, rules)
+func (s *administrationImpl) DeleteSymptomRule(ctx context.Context, current model.User, group string, ID string) error {
+	return s.app.deleteSymptomRule(ctx, current, group, ID)
 }
 
-func (s *administrationImpl) UpdateAccessRule(current model.User, group string, audit *string, ID string, countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error) {
-	return s.app.updateAccessRule(current, group, audit, ID, countyID, rules)
+func (s *administrationImpl) GetManualTestByCountyID(ctx context.Context, countyID string, status *string) ([]*model.EManualTest, error) {
+	return s.app.getManualTestByCountyID(ctx, countyID, status)
 }
 
-func (s *administrationImpl) DeleteAccessRule(current model.User, group string, ID string) error {
-	return s.app.deleteAccessRule(current, group, ID)
+func (s *administrationImpl) ProcessManualTest(ctx context.Context, ID string, status string, encryptedKey *string, encryptedBlob *string) error {
+	return s.app.processManualTest(ctx, ID, status, encryptedKey, encryptedBlob)
 }
 
-func (s *administrationImpl) GetCRules(countyID string, appVersion string) (*model.CRules, error) {
-	return s.app.getCRules(countyID, appVersion)
+func (s *administrationImpl) GetManualTestImage(ctx context.Context, ID string) (*string, *string, error) {
+	return s.app.getManualTestImage(ctx, ID)
 }
 
-func (s *administrationImpl) CreateOrUpdateCRules(current model.User, group string, audit *string, countyID string, appVersion string, data string) error {
-	return s.app.createOrUpdateCRules(current, group, audit, countyID, appVersion, data)
+func (s *administrationImpl) GetAccessRules(ctx context.Context) ([]*model.AccessRule, error) {
+	return s.app.getAccessRules(ctx)
 }
 
-func (s *administrationImpl) GetSymptoms(appVersion string) (*model.Symptoms, error) {
-	return s.app.getASymptoms(appVersion)
+func (s *administrationImpl) CreateAccessRule(ctx context.Context, current model.User, group string, audit *string, countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error) {
+	return s.app.createAccessRule(ctx, current, group, audit, countyID, rules)
 }
 
-func (s *administrationImpl) CreateOrUpdateSymptoms(current model.User, group string, audit *string, appVersion string, items string) error {
-	return s.app.createOrUpdateSymptoms(current, group, audit, appVersion, items)
+func (s *administrationImpl) UpdateAccessRule(ctx context.Context, current model.User, group string, audit *string, ID string, countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error) {
+	return s.app.updateAccessRule(ctx, current, group, audit, ID, countyID, rules)
 }
 
-func (s *administrationImpl) GetUINOverrides(uin *string, sort *string) ([]*model.UINOverride, error) {
-	return s.app.getUINOverrides(uin, sort)
+func (s *administrationImpl) DeleteAccessRule(ctx context.Context, current model.User, group string, ID string) error {
+	return s.app.deleteAccessRule(ctx, current, group, ID)
 }
 
-func (s *administrationImpl) CreateUINOverride(current model.User, group string, audit *string, uin string, interval int, category *string, expiration *time.Time) (*model.UINOverride, error) {
-	return s.app.createUINOverride(current, group, audit, uin, interval, category, expiration)
+func (s *administrationImpl) GetCRules(ctx context.Context, countyID string, appVersion string) (*model.CRules, error) {
+	return s.app.getCRules(ctx, countyID, appVersion)
 }
 
-func (s *administrationImpl) UpdateUINOverride(current model.User, group string, audit *string, uin string, interval int, category *string, expiration *time.Time) (*string, error) {
-	return s.app.updateUINOverride(current, group, audit, uin, interval, category, expiration)
+func (s *administrationImpl) CreateOrUpdateCRules(ctx context.Context, current model.User, group string, audit *string, countyID string, appVersion string, data string) error {
+	return s.app.createOrUpdateCRules(ctx, current, group, audit, countyID, appVersion, data)
 }
 
-func (s *administrationImpl) DeleteUINOverride(current model.User, group string, uin string) error {
-	return s.app.deleteUINOverride(current, group, uin)
+func (s *administrationImpl) GetSymptoms(ctx context.Context, appVersion string) (*model.Symptoms, error) {
+	return s.app.getASymptoms(ctx, appVersion)
 }
 
-func (s *administrationImpl) GetUserByExternalID(externalID string) (*model.User, error) {
-	return s.app.getUserByExternalID(externalID)
+func (s *administrationImpl) CreateOrUpdateSymptoms(ctx context.Context, current model.User, group string, audit *string, appVersion string, items string) error {
+	return s.app.createOrUpdateSymptoms(ctx, current, group, audit, appVersion, items)
 }
 
-func (s *administrationImpl) CreateAction(current model.User, group string, audit *string, providerID string, userID string, encryptedKey string, encryptedBlob string) (*model.CTest, error) {
-	return s.app.createAction(current, group, audit, providerID, userID, encryptedKey, encryptedBlob)
+func (s *administrationImpl) GetConfigRevisions(ctx context.Context, configType string, countyID string, appVersion string) ([]*model.ConfigRevision, error) {
+	return s.app.getConfigRevisions(ctx, configType, countyID, appVersion)
 }
 
-func (s *administrationImpl) GetAudit(current model.User, group string, userIdentifier *string, entity *string, entityID *string, operation *string,
-	clientData *string, createdAt *time.Time, sortBy *string, asc *bool, limit *int64) ([]*AuditEntity, error) {
-	return s.app.getAudit(current, group, userIdentifier, entity, entityID, operation, clientData, createdAt, sortBy, asc, limit)
+func (s *administrationImpl) SaveConfigDraft(ctx context.Context, current model.User, group string, audit *string, configType string, countyID string, appVersion string, data string) (*model.ConfigRevision, error) {
+	return s.app.saveConfigDraft(ctx, current, group, audit, configType, countyID, appVersion, data)
 }
 
-//Storage is used by core to storage data - DB storage adapter, file storage adapter etc
-type Storage interface {
-	SetStorageListener(storageListener StorageListener)
+func (s *administrationImpl) DiscardConfigDraft(ctx context.Context, current model.User, group string, audit *string, configType string, countyID string, appVersion string) (*model.ConfigRevision, error) {
+	return s.app.discardConfigDraft(ctx, current, group, audit, configType, countyID, appVersion)
+}
 
-	ReadAllAppVersions() ([]string, error)
-	CreateAppVersion(version string) error
+func (s *administrationImpl) PublishConfigDraft(ctx context.Context, current model.User, group string, audit *string, configType string, countyID string, appVersion string) (*model.ConfigRevision, error) {
+	return s.app.publishConfigDraft(ctx, current, group, audit, configType, countyID, appVersion)
+}
 
-	ClearUserData(userID string) error
-	FindUser(userID string) (*model.User, error)
-	FindUserByExternalID(externalID string) (*model.User, error)
-	FindUserByShibbolethID(shibbolethID string) (*model.User, error)
-	FindUsersByRePost(rePost bool) ([]*model.User, error)
-	CreateUser(shibboAuth *model.ShibbolethAuth, externalID string,
-		uuid string, publicKey string, consent bool, exposureNotification bool, rePost bool, encryptedKey *string, encryptedBlob *string) (*model.User, error)
-	SaveUser(user *model.User) error
-
-	ReadCovid19Config() (*model.COVID19Config, error)
-	SaveCovid19Config(covid19Config *model.COVID19Config) error
-
-	ReadAllResources() ([]*model.Resource, error)
-	CreateResource(title string, link string, displayOrder int) (*model.Resource, error)
-	DeleteResource(ID string) error
-	FindResource(ID string) (*model.Resource, error)
-	SaveResource(resource *model.Resource) error
-
-	ReadFAQ() (*model.FAQ, error)
-	SaveFAQ(faq *model.FAQ) error
-	DeleteFAQSection(ID string) error
-
-	ReadNews(limit int64) ([]*model.News, error)
-	CreateNews(date time.Time, title string, description string, htmlContent string, link *string) (*model.News, error)
-	DeleteNews(ID string) error
-	FindNews(ID string) (*model.News, error)
-	SaveNews(news *model.News) error
-
-	CreateEStatus(appVersion *string, userID string, date *time.Time, encryptedKey string, encryptedBlob string) (*model.EStatus, error)
-	FindEStatusByUserID(appVersion *string, userID string) (*model.EStatus, error)
-	SaveEStatus(status *model.EStatus) error
-	DeleteEStatus(appVersion *string, userID string) error
-
-	CreateEHistory(userID string, date time.Time, eType string, encryptedKey string, encryptedBlob string) (*model.EHistory, error)
-	CreateManualЕHistory(userID string, date time.Time, encryptedKey string, encryptedBlob string, encryptedImageKey *string, encryptedImageBlob *string,
-		countyID *string, locationID *string) (*model.EHistory, error)
-	FindEHistories(userID string) ([]*model.EHistory, error)
-	DeleteEHistories(userID string) (int64, error)
-	FindEHistory(ID string) (*model.EHistory, error)
-	SaveEHistory(history *model.EHistory) error
-
-	ReadAllProviders() ([]*model.Provider, error)
-	CreateProvider(providerName string, manualTest bool, availableMechanisms []string) (*model.Provider, error)
-	FindProvider(ID string) (*model.Provider, error)
-	SaveProvider(provider *model.Provider) error
-	DeleteProvider(ID string) error
-
-	CreateExternalCTest(providerID string, uin string, encryptedKey string, encryptedBlob string, processed bool, orderNumber *string) (*model.CTest, *model.User, error)
-	CreateAdminCTest(providerID string, userID string, encryptedKey string, encryptedBlob string, processed bool, orderNumber *string) (*model.CTest, *model.User, error)
-	FindCTest(ID string) (*model.CTest, error)
-	FindCTests(userID string, processed bool) ([]*model.CTest, error)
-	FindCTestsByExternalUserIDs(externalUserIDs []string) (map[string][]*model.CTest, error)
-	DeleteCTests(userID string) (int64, error)
-	SaveCTest(ctest *model.CTest) error
-
-	FindCounties(f *utils.Filter) ([]*model.County, error)
-	CreateCounty(name string, stateProvince string, country string) (*model.County, error)
-	FindCounty(ID string) (*model.County, error)
-	SaveCounty(county *model.County) error
-	DeleteCounty(ID string) error
-
-	CreateGuideline(countyID string, name string, description string, items []model.GuidelineItem) (*model.Guideline, error)
-	FindGuideline(ID string) (*model.Guideline, error)
-	FindGuidelineByCountyID(countyID string) ([]*model.Guideline, error)
-	SaveGuideline(guideline *model.Guideline) error
-	DeleteGuideline(ID string) error
-
-	CreateCountyStatus(countyID string, name string, description string) (*model.CountyStatus, error)
-	FindCountyStatus(ID string) (*model.CountyStatus, error)
-	FindCountyStatusesByCountyID(countyID string) ([]*model.CountyStatus, error)
-	SaveCountyStatus(countyStatus *model.CountyStatus) error
-	DeleteCountyStatus(ID string) error
-
-	ReadAllTestTypes() ([]*model.TestType, error)
-	CreateTestType(name string, priority *int) (*model.TestType, error)
-	FindTestType(ID string) (*model.TestType, error)
-	FindTestTypesByIDs(ids []string) ([]*model.TestType, error)
-	SaveTestType(testType *model.TestType) error
-	DeleteTestType(ID string) error
-
-	CreateTestTypeResult(testTypeID string, name string, nextStep string, nextStepOffset *int, resultExpiresOffset *int) (*model.TestTypeResult, error)
-	FindTestTypeResult(ID string) (*model.TestTypeResult, error)
-	FindTestTypeResultsByTestTypeID(testTypeID string) ([]*model.TestTypeResult, error)
-	SaveTestTypeResult(testTypeResult *model.TestTypeResult) error
-	DeleteTestTypeResult(ID string) error
-
-	ReadAllRules() ([]*model.Rule, error)
-	FindRulesByCountyID(countyID string) ([]*model.Rule, error)
-	FindRule(ID string) (*model.Rule, error)
-	FindRuleByCountyIDTestTypeID(countyID string, testTypeID string) (*model.Rule, error)
-	CreateRule(countyID string, testTypeID string, priority *int, resultsStates []model.TestTypeResultCountyStatus) (*model.Rule, error)
-	SaveRule(rule *model.Rule) error
-	DeleteRule(ID string) error
-
-	ReadAllLocations() ([]*model.Location, error)
-	CreateLocation(providerID string, countyID string, name string, address1 string, address2 string, city string,
-		state string, zip string, country string, latitude float64, longitude float64, contact string,
-		daysOfOperation []model.OperationDay, url string, notes string, waitTimeColor *string, availableTests []string) (*model.Location, error)
-	FindLocationsByProviderIDCountyID(providerID string, countyID string) ([]*model.Location, error)
-	FindLocationsByCountyIDDeep(countyID string) ([]*model.Location, error)
-	FindLocationsByCountiesDeep(countyIDs []string) ([]*model.Location, error)
-	FindLocation(ID string) (*model.Location, error)
-	SaveLocation(location *model.Location) error
-	DeleteLocation(ID string) error
+func (s *administrationImpl) RollbackConfig(ctx context.Context, current model.User, group string, audit *string, configType string, countyID string, appVersion string, number int) (*model.ConfigRevision, error) {
+	return s.app.rollbackConfig(ctx, current, group, audit, configType, countyID, appVersion, number)
+}
 
-	FindSymptom(ID string) (*model.Symptom, error)
-	CreateSymptom(name string, symptomGroup string) (*model.Symptom, error)
-	DeleteSymptom(ID string) error
-	SaveSymptom(symptom *model.Symptom) error
+func (s *administrationImpl) GetConfigSchemas(ctx context.Context, appVersion *string) ([]*model.ConfigSchema, error) {
+	return s.app.getConfigSchemas(ctx, appVersion)
+}
 
-	ReadAllSymptomGroups() ([]*model.SymptomGroup, error)
+func (s *administrationImpl) SaveConfigSchema(ctx context.Context, current model.User, group string, audit *string, configType string, appVersion string, schema string) (*model.ConfigSchema, error) {
+	return s.app.saveConfigSchema(ctx, current, group, audit, configType, appVersion, schema)
+}
 
-	ReadSymptoms(appVersion string) (*model.Symptoms, error)
-	CreateOrUpdateSymptoms(appVersion string, items string) (*bool, error)
+func (s *administrationImpl) DeleteConfigSchema(ctx context.Context, current model.User, group string, configType string, appVersion string) error {
+	return s.app.deleteConfigSchema(ctx, current, group, configType, appVersion)
+}
 
-	ReadAllSymptomRules() ([]*model.SymptomRule, error)
-	CreateSymptomRule(countyID string, gr1Count int, gr2Count int, items []model.SymptomRuleItem) (*model.SymptomRule, error)
-	FindSymptomRule(ID string) (*model.SymptomRule, error)
-	FindSymptomRuleByCountyID(countyID string) (*model.SymptomRule, error)
-	SaveSymptomRule(symptomRule *model.SymptomRule) error
-	DeleteSymptomRule(ID string) error
+func (s *administrationImpl) GetUINOverrides(ctx context.Context, uin *string, sort *string) ([]*model.UINOverride, error) {
+	return s.app.getUINOverrides(ctx, uin, sort)
+}
 
-	FindCRulesByCountyID(appVersion string, countyID string) (*model.CRules, error)
-	CreateOrUpdateCRules(appVersion string, countyID string, data string) (*bool, error)
+func (s *administrationImpl) CreateUINOverride(ctx context.Context, current model.User, group string, audit *string, uin string, interval int, category *string, expiration *time.Time) (*model.UINOverride, error) {
+	return s.app.createUINOverride(ctx, current, group, audit, uin, interval, category, expiration)
+}
 
-	CreateTraceReports(items []model.TraceExposure) (int, error)
-	ReadTraceExposures(timestamp *int64, dateAdded *int64) ([]model.TraceExposure, error)
+func (s *administrationImpl) UpdateUINOverride(ctx context.Context, current model.User, group string, audit *string, uin string, interval int, category *string, expiration *time.Time) (*string, error) {
+	return s.app.updateUINOverride(ctx, current, group, audit, uin, interval, category, expiration)
+}
 
-	FindManualTestsByCountyIDDeep(countyID string, status *string) ([]*model.EManualTest, error)
-	FindManualTestImage(ID string) (*string, *string, error)
-	ProcessManualTest(ID string, status string, encryptedKey *string, encryptedBlob *string) error
+func (s *administrationImpl) DeleteUINOverride(ctx context.Context, current model.User, group string, uin string) error {
+	return s.app.deleteUINOverride(ctx, current, group, uin)
+}
 
-	ReadAllAccessRules() ([]*model.AccessRule, error)
-	CreateAccessRule(countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error)
-	UpdateAccessRule(ID string, countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error)
-	FindAccessRuleByCountyID(countyID string) (*model.AccessRule, error)
-	DeleteAccessRule(ID string) error
+func (s *administrationImpl) GetUserByExternalID(ctx context.Context, externalID string) (*model.User, error) {
+	return s.app.getUserByExternalID(ctx, externalID)
+}
 
-	FindExternalUserIDsByTestsOrderNumbers(orderNumbers []string) (map[string]*string, error)
+func (s *administrationImpl) CreateAction(ctx context.Context, current model.User, group string, audit *string, providerID string, userID string, encryptedKey string, encryptedBlob string) (*model.CTest, error) {
+	return s.app.createAction(ctx, current, group, audit, providerID, userID, encryptedKey, encryptedBlob)
+}
 
-	CreateOrUpdateUINOverride(uin string, interval int, category *string, expiration *time.Time) error
+func (s *administrationImpl) GetAudit(ctx context.Context, current model.User, group string, userIdentifier *string, entity *string, entityID *string, operation *string,
+	clientData *string, createdAt *time.Time, sortBy *string, asc *bool, limit *int64) ([]*AuditEntity, error) {
+	return s.app.getAudit(ctx, current, group, userIdentifier, entity, entityID, operation, clientData, createdAt, sortBy, asc, limit)
+}
+
+func (s *administrationImpl) GetRetentionPolicies(ctx context.Context) ([]*model.RetentionPolicy, error) {
+	return s.app.getRetentionPolicies(ctx)
+}
+
+func (s *administrationImpl) UpdateRetentionPolicy(ctx context.Context, current model.User, group string, audit *string, entity string, days int, enabled bool) (*model.RetentionPolicy, error) {
+	return s.app.updateRetentionPolicy(ctx, current, group, audit, entity, days, enabled)
+}
+
+func (s *administrationImpl) ExportCountyBundle(ctx context.Context, countyID string) (*model.CountyBundle, error) {
+	return s.app.exportCountyBundle(ctx, countyID)
+}
+
+func (s *administrationImpl) ImportCountyBundle(ctx context.Context, current model.User, group string, audit *string, bundle model.CountyBundle, name *string) (*model.County, error) {
+	return s.app.importCountyBundle(ctx, current, group, audit, bundle, name)
+}
+
+func (s *administrationImpl) ApplyCountyBundle(ctx context.Context, current model.User, group string, audit *string, countyID string, bundle model.CountyBundle) ([]model.BundleChange, error) {
+	return s.app.applyCountyBundle(ctx, current, group, audit, countyID, bundle)
+}
+
+func (s *administrationImpl) DiffCountyBundle(ctx context.Context, countyID string, bundle model.CountyBundle) ([]model.BundleChange, error) {
+	return s.app.diffCountyBundle(ctx, countyID, bundle)
+}
+
+func (s *administrationImpl) SimulateCountyStatus(ctx context.Context, countyID string, draft *model.RulesDraft, timeline []model.SimulationEvent, points []time.Time) ([]model.SimulationStep, error) {
+	return s.app.simulateCountyStatus(ctx, countyID, draft, timeline, points)
+}
+
+func (s *administrationImpl) GetErasureReceiptsByUIN(ctx context.Context, uin string) ([]*model.ErasureReceipt, error) {
+	return s.app.getErasureReceiptsByUIN(ctx, uin)
+}
+
+func (s *administrationImpl) GetErasureReceipt(ctx context.Context, ID string) (*model.ErasureReceipt, bool, error) {
+	return s.app.getErasureReceipt(ctx, ID)
+}
+
+//Storage is used by core to storage data - DB storage adapter, file storage adapter etc
+type Storage interface {
+	SetStorageListener(storageListener StorageListener)
+
+	ReadAllAppVersions(ctx context.Context) ([]string, error)
+	CreateAppVersion(ctx context.Context, version string) error
+	FindAppVersions(ctx context.Context) ([]*model.AppVersion, error)
+	UpdateAppVersionStatus(ctx context.Context, version string, status string) error
+
+	//ClearUserData removes all the data linked to the user id or uins and gives the removed items count per collection
+	ClearUserData(ctx context.Context, userID string, uins []string) (map[string]int64, error)
+	FindUser(ctx context.Context, userID string) (*model.User, error)
+	FindUserByExternalID(ctx context.Context, externalID string) (*model.User, error)
+	FindUserByShibbolethID(ctx context.Context, shibbolethID string) (*model.User, error)
+	FindUsersByRePost(ctx context.Context, rePost bool) ([]*model.User, error)
+	CreateUser(ctx context.Context, shibboAuth *model.ShibbolethAuth, externalID string,
+		uuid string, publicKey string, consent bool, exposureNotification bool, rePost bool, encryptedKey *string, encryptedBlob *string) (*model.User, error)
+	SaveUser(ctx context.Context, user *model.User) error
+
+	ReadCovid19Config(ctx context.Context) (*model.COVID19Config, error)
+	SaveCovid19Config(ctx context.Context, covid19Config *model.COVID19Config) error
+
+	ReadAllResources(ctx context.Context) ([]*model.Resource, error)
+	CreateResource(ctx context.Context, title string, link string, displayOrder int, sourceKey *string) (*model.Resource, error)
+	DeleteResource(ctx context.Context, ID string) error
+	FindResource(ctx context.Context, ID string) (*model.Resource, error)
+	SaveResource(ctx context.Context, resource *model.Resource) error
+
+	FindResourceChanges(ctx context.Context, status *string) ([]*model.ResourceChange, error)
+	FindResourceChange(ctx context.Context, ID string) (*model.ResourceChange, error)
+	SaveResourceChange(ctx context.Context, change *model.ResourceChange) error
+	DeleteResourceChange(ctx context.Context, ID string) error
+
+	ReadFAQ(ctx context.Context) (*model.FAQ, error)
+	SaveFAQ(ctx context.Context, faq *model.FAQ) error
+	DeleteFAQSection(ctx context.Context, ID string) error
+
+	ReadNews(ctx context.Context, limit int64) ([]*model.News, error)
+	ReadPublishedNews(ctx context.Context, now time.Time, limit int64, category *string) ([]*model.News, error)
+	CreateNews(ctx context.Context, date time.Time, title string, description string, htmlContent string, link *string,
+		status string, publishAt *time.Time, expireAt *time.Time, pinned bool, categories []string) (*model.News, error)
+	DeleteNews(ctx context.Context, ID string) error
+	FindNews(ctx context.Context, ID string) (*model.News, error)
+	SaveNews(ctx context.Context, news *model.News) error
+	InsertSourceNews(ctx context.Context, news *model.News) (bool, error)
+
+	FindNewsSources(ctx context.Context) ([]*model.NewsSource, error)
+	FindNewsSource(ctx context.Context, ID string) (*model.NewsSource, error)
+	SaveNewsSource(ctx context.Context, source *model.NewsSource) error
+	DeleteNewsSource(ctx context.Context, ID string) error
+	UpdateNewsSourceFetchStatus(ctx context.Context, source *model.NewsSource) error
+
+	CreateEStatus(ctx context.Context, appVersion *string, userID string, date *time.Time, encryptedKey string, encryptedBlob string) (*model.EStatus, error)
+	FindEStatusByUserID(ctx context.Context, appVersion *string, userID string) (*model.EStatus, error)
+	FindEStatusesByUserID(ctx context.Context, userID string) ([]*model.EStatus, error)
+	SaveEStatus(ctx context.Context, status *model.EStatus) error
+	DeleteEStatus(ctx context.Context, appVersion *string, userID string) error
+
+	CreateEHistory(ctx context.Context, userID string, keyVersion int, date time.Time, eType string, encryptedKey string, encryptedBlob string) (*model.EHistory, error)
+	CreateManualЕHistory(ctx context.Context, userID string, keyVersion int, date time.Time, encryptedKey string, encryptedBlob string, encryptedImageKey *string, encryptedImageBlob *string,
+		countyID *string, locationID *string) (*model.EHistory, error)
+	FindEHistories(ctx context.Context, userID string) ([]*model.EHistory, error)
+	DeleteEHistories(ctx context.Context, userID string) (int64, error)
+	FindEHistory(ctx context.Context, ID string) (*model.EHistory, error)
+	SaveEHistory(ctx context.Context, history *model.EHistory) error
+
+	ReadAllProviders(ctx context.Context) ([]*model.Provider, error)
+	CreateProvider(ctx context.Context, providerName string, manualTest bool, availableMechanisms []string) (*model.Provider, error)
+	FindProvider(ctx context.Context, ID string) (*model.Provider, error)
+	SaveProvider(ctx context.Context, provider *model.Provider) error
+	DeleteProvider(ctx context.Context, ID string) error
+
+	CreateExternalCTest(ctx context.Context, providerID string, uin string, keyVersion *int, encryptedKey string, encryptedBlob string, processed bool, orderNumber *string) (*model.CTest, *model.User, error)
+	CreateAdminCTest(ctx context.Context, providerID string, userID string, encryptedKey string, encryptedBlob string, processed bool, orderNumber *string) (*model.CTest, *model.User, error)
+	FindCTest(ctx context.Context, ID string) (*model.CTest, error)
+	FindCTests(ctx context.Context, userID string, processed bool) ([]*model.CTest, error)
+	FindCTestsByExternalUserIDs(ctx context.Context, externalUserIDs []string) (map[string][]*model.CTest, error)
+	DeleteCTests(ctx context.Context, userID string) (int64, error)
+	SaveCTest(ctx context.Context, ctest *model.CTest) error
+	//finds the not processed ctests which are encrypted for an old public key of their user
+	FindCTestsWithOldKeyVersion(ctx context.Context, providerID *string, userID *string) ([]*model.CTest, error)
+
+	FindCounties(ctx context.Context, f *utils.Filter) ([]*model.County, error)
+	CreateCounty(ctx context.Context, name string, stateProvince string, country string) (*model.County, error)
+	FindCounty(ctx context.Context, ID string) (*model.County, error)
+	SaveCounty(ctx context.Context, county *model.County) error
+	DeleteCounty(ctx context.Context, ID string) error
+
+	CreateGuideline(ctx context.Context, countyID string, name string, description string, items []model.GuidelineItem) (*model.Guideline, error)
+	FindGuideline(ctx context.Context, ID string) (*model.Guideline, error)
+	FindGuidelineByCountyID(ctx context.Context, countyID string) ([]*model.Guideline, error)
+	SaveGuideline(ctx context.Context, guideline *model.Guideline) error
+	DeleteGuideline(ctx context.Context, ID string) error
+
+	CreateCountyStatus(ctx context.Context, countyID string, name string, description string) (*model.CountyStatus, error)
+	FindCountyStatus(ctx context.Context, ID string) (*model.CountyStatus, error)
+	FindCountyStatusesByCountyID(ctx context.Context, countyID string) ([]*model.CountyStatus, error)
+	SaveCountyStatus(ctx context.Context, countyStatus *model.CountyStatus) error
+	DeleteCountyStatus(ctx context.Context, ID string) error
+
+	ReadAllTestTypes(ctx context.Context) ([]*model.TestType, error)
+	CreateTestType(ctx context.Context, name string, priority *int) (*model.TestType, error)
+	FindTestType(ctx context.Context, ID string) (*model.TestType, error)
+	FindTestTypesByIDs(ctx context.Context, ids []string) ([]*model.TestType, error)
+	SaveTestType(ctx context.Context, testType *model.TestType) error
+	DeleteTestType(ctx context.Context, ID string) error
+
+	CreateTestTypeResult(ctx context.Context, testTypeID string, name string, nextStep string, nextStepOffset *int, resultExpiresOffset *int) (*model.TestTypeResult, error)
+	FindTestTypeResult(ctx context.Context, ID string) (*model.TestTypeResult, error)
+	FindTestTypeResultsByTestTypeID(ctx context.Context, testTypeID string) ([]*model.TestTypeResult, error)
+	SaveTestTypeResult(ctx context.Context, testTypeResult *model.TestTypeResult) error
+	DeleteTestTypeResult(ctx context.Context, ID string) error
+
+	ReadAllRules(ctx context.Context) ([]*model.Rule, error)
+	FindRulesByCountyID(ctx context.Context, countyID string) ([]*model.Rule, error)
+	FindRule(ctx context.Context, ID string) (*model.Rule, error)
+	FindRuleByCountyIDTestTypeID(ctx context.Context, countyID string, testTypeID string) (*model.Rule, error)
+	CreateRule(ctx context.Context, countyID string, testTypeID string, priority *int, resultsStates []model.TestTypeResultCountyStatus) (*model.Rule, error)
+	SaveRule(ctx context.Context, rule *model.Rule) error
+	DeleteRule(ctx context.Context, ID string) error
+
+	ReadAllLocations(ctx context.Context) ([]*model.Location, error)
+	CreateLocation(ctx context.Context, providerID string, countyID string, name string, address1 string, address2 string, city string,
+		state string, zip string, country string, latitude float64, longitude float64, contact string,
+		daysOfOperation []model.OperationDay, url string, notes string, waitTimeColor *string, availableTests []string) (*model.Location, error)
+	FindLocationsByProviderIDCountyID(ctx context.Context, providerID string, countyID string) ([]*model.Location, error)
+	FindLocationsByCountyIDDeep(ctx context.Context, countyID string) ([]*model.Location, error)
+	FindLocationsByCountiesDeep(ctx context.Context, countyIDs []string) ([]*model.Location, error)
+	FindLocationsNearby(ctx context.Context, latitude float64, longitude float64, radius float64,
+		testTypeID *string, providerID *string, limit int64) ([]*model.NearbyLocation, error)
+	FindLocation(ctx context.Context, ID string) (*model.Location, error)
+	SaveLocation(ctx context.Context, location *model.Location) error
+	UpdateLocationExceptions(ctx context.Context, ID string, exceptions []model.LocationException) error
+	UpdateLocationWaitTime(ctx context.Context, ID string, waitTime *model.WaitTimeReport, waitTimeColor *string) error
+	UpdateLocationAppointmentSettings(ctx context.Context, ID string, settings *model.AppointmentSettings) error
+
+	CreateWaitTimeReport(ctx context.Context, report *model.WaitTimeReport) error
+	FindWaitTimeReports(ctx context.Context, locationID string, limit int64) ([]*model.WaitTimeReport, error)
+
+	//the booking operations check the capacity of the slot atomically, they give false if the slot is full
+	CreateAppointment(ctx context.Context, appointment *model.Appointment, capacity int) (bool, error)
+	RescheduleAppointment(ctx context.Context, appointment *model.Appointment, previousStart time.Time, capacity int) (bool, error)
+	CancelAppointment(ctx context.Context, appointment *model.Appointment) error
+	SetAppointmentStatus(ctx context.Context, ID string, status string) error
+	FindAppointment(ctx context.Context, ID string) (*model.Appointment, error)
+	FindUserAppointments(ctx context.Context, userID string) ([]*model.Appointment, error)
+	FindLocationAppointments(ctx context.Context, locationID string, from time.Time, to time.Time) ([]*model.Appointment, error)
+	FindAppointmentsToRemind(ctx context.Context, from time.Time, to time.Time) ([]*model.Appointment, error)
+	SetAppointmentReminderSent(ctx context.Context, ID string) (bool, error)
+	DeleteLocation(ctx context.Context, ID string) error
+
+	FindSymptom(ctx context.Context, ID string) (*model.Symptom, error)
+	CreateSymptom(ctx context.Context, name string, symptomGroup string) (*model.Symptom, error)
+	DeleteSymptom(ctx context.Context, ID string) error
+	SaveSymptom(ctx context.Context, symptom *model.Symptom) error
+
+	ReadAllSymptomGroups(ctx context.Context) ([]*model.SymptomGroup, error)
+
+	ReadSymptoms(ctx context.Context, appVersion string) (*model.Symptoms, error)
+	ReadAllSymptoms(ctx context.Context) ([]*model.Symptoms, error)
+	CreateOrUpdateSymptoms(ctx context.Context, appVersion string, items string) (*bool, error)
+
+	ReadAllSymptomRules(ctx context.Context) ([]*model.SymptomRule, error)
+	CreateSymptomRule(ctx context.Context, countyID string, gr1Count int, gr2Count int, items []model.SymptomRuleItem) (*model.SymptomRule, error)
+	FindSymptomRule(ctx context.Context, ID string) (*model.SymptomRule, error)
+	FindSymptomRuleByCountyID(ctx context.Context, countyID string) (*model.SymptomRule, error)
+	SaveSymptomRule(ctx context.Context, symptomRule *model.SymptomRule) error
+	DeleteSymptomRule(ctx context.Context, ID string) error
+
+	FindCRulesByCountyID(ctx context.Context, appVersion string, countyID string) (*model.CRules, error)
+	FindAllCRulesByCountyID(ctx context.Context, countyID string) ([]*model.CRules, error)
+	CreateOrUpdateCRules(ctx context.Context, appVersion string, countyID string, data string) (*bool, error)
+
+	FindConfigRevisions(ctx context.Context, configType string, appVersion string, countyID string) ([]*model.ConfigRevision, error)
+	SaveConfigRevision(ctx context.Context, revision *model.ConfigRevision) error
+	PublishConfigRevision(ctx context.Context, revision *model.ConfigRevision) (*bool, error)
+
+	FindConfigSchemas(ctx context.Context, appVersion *string) ([]*model.ConfigSchema, error)
+	FindConfigSchema(ctx context.Context, configType string, appVersion string) (*model.ConfigSchema, error)
+	SaveConfigSchema(ctx context.Context, schema *model.ConfigSchema) error
+	DeleteConfigSchema(ctx context.Context, ID string) error
+
+	CreateTraceReports(ctx context.Context, items []model.TraceExposure) (int, error)
+	ReadTraceExposures(ctx context.Context, timestamp *int64, dateAdded *int64) ([]model.TraceExposure, error)
+
+	FindManualTestsByCountyIDDeep(ctx context.Context, countyID string, status *string) ([]*model.EManualTest, error)
+	FindManualTestsByUserID(ctx context.Context, userID string) ([]*model.EManualTest, error)
+	FindManualTestImage(ctx context.Context, ID string) (*string, *string, error)
+	ProcessManualTest(ctx context.Context, ID string, status string, encryptedKey *string, encryptedBlob *string) error
+
+	ReadAllAccessRules(ctx context.Context) ([]*model.AccessRule, error)
+	CreateAccessRule(ctx context.Context, countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error)
+	UpdateAccessRule(ctx context.Context, ID string, countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error)
+	FindAccessRuleByCountyID(ctx context.Context, countyID string) (*model.AccessRule, error)
+	DeleteAccessRule(ctx context.Context, ID string) error
+
+	FindExternalUserIDsByTestsOrderNumbers(ctx context.Context, orderNumbers []string) (map[string]*string, error)
+
+	CreateOrUpdateUINOverride(ctx context.Context, uin string, interval int, category *string, expiration *time.Time) error
 	//finds the uin override for the provided uin. It makes additional check for the expiration because of the mongoDB TTL delay
-	FindUINOverride(uin string) (*model.UINOverride, error)
+	FindUINOverride(ctx context.Context, uin string) (*model.UINOverride, error)
 
 	//finds the uin override for the provided uin. If uin is nil then it gives all
-	FindUINOverrides(uin *string, sort *string) ([]*model.UINOverride, error)
-	CreateUINOverride(uin string, interval int, category *string, expiration *time.Time) (*model.UINOverride, error)
-	UpdateUINOverride(uin string, interval int, category *string, expiration *time.Time) (*string, error)
-	DeleteUINOverride(uin string) error
-
-	FindUINBuildingAccess(uin string) (*model.UINBuildingAccess, error)
-	CreateOrUpdateUINBuildingAccess(uin string, date time.Time, access string) error
+	FindUINOverrides(ctx context.Context, uin *string, sort *string) ([]*model.UINOverride, error)
+	CreateUINOverride(ctx context.Context, uin string, interval int, category *string, expiration *time.Time) (*model.UINOverride, error)
+	UpdateUINOverride(ctx context.Context, uin string, interval int, category *string, expiration *time.Time) (*string, error)
+	DeleteUINOverride(ctx context.Context, uin string) error
+
+	FindUINBuildingAccess(ctx context.Context, uin string) (*model.UINBuildingAccess, error)
+	FindUINBuildingAccessByUINs(ctx context.Context, uins []string) ([]*model.UINBuildingAccess, error)
+	CreateOrUpdateUINBuildingAccess(ctx context.Context, uin string, date time.Time, access string) error
+
+	ReadRetentionPolicies(ctx context.Context) ([]*model.RetentionPolicy, error)
+	SaveRetentionPolicy(ctx context.Context, policy *model.RetentionPolicy) error
+
+	//the purge operations give the number of the affected records
+	DeleteEHistoriesOlderThan(ctx context.Context, date time.Time) (int64, error)
+	DeleteProcessedCTestsOlderThan(ctx context.Context, date time.Time) (int64, error)
+	ClearManualTestsImagesOlderThan(ctx context.Context, date time.Time) (int64, error)
+	DeleteTraceExposuresExpiredBefore(ctx context.Context, expirestamp int64) (int64, error)
+	DeleteUINOverridesExpiredBefore(ctx context.Context, date time.Time) (int64, error)
+	DeleteWaitTimeReportsOlderThan(ctx context.Context, date time.Time) (int64, error)
+	DeleteAppointmentsOlderThan(ctx context.Context, date time.Time) (int64, error)
+
+	//CreateCountyConfiguration creates a county with all its configuration in one transaction, the crules revisions are published
+	CreateCountyConfiguration(ctx context.Context, county *model.County, rules []*model.Rule, accessRule *model.AccessRule,
+		symptomRule *model.SymptomRule, cRulesRevisions []*model.ConfigRevision, locations []*model.Location) error
+	//UpdateCountyConfiguration replaces the county data, rules, access rule and symptom rule, publishes the crules revisions
+	//and creates or updates the locations in one transaction
+	UpdateCountyConfiguration(ctx context.Context, county *model.County, rules []*model.Rule, accessRule *model.AccessRule,
+		symptomRule *model.SymptomRule, cRulesRevisions []*model.ConfigRevision, locations []*model.Location) error
+
+	SaveErasureReceipt(ctx context.Context, receipt *model.ErasureReceipt) error
+	FindErasureReceipt(ctx context.Context, ID string) (*model.ErasureReceipt, error)
+	FindErasureReceiptsBySubjectHash(ctx context.Context, subjectHash string) ([]*model.ErasureReceipt, error)
 }
 
 //StorageListener listenes for change data storage events
 type StorageListener interface {
 	OnConfigsChanged()
 	OnAppVersionsChanged()
+
+	//reference data - counties(with the county statuses and guidelines), rules, access rules, test types, locations, providers and crules
+	OnCountiesChanged()
+	OnRulesChanged()
+	OnAccessRulesChanged()
+	OnTestTypesChanged()
+	OnLocationsChanged()
+	OnProvidersChanged()
+	OnCRulesChanged()
 }
 
 type storageListenerImpl struct {
@@ -892,18 +1241,57 @@ func (a *storageListenerImpl) OnAppVersionsChanged() {
 	a.app.loadAppVersions()
 }
 
+func (a *storageListenerImpl) OnCountiesChanged() {
+	a.app.cache.invalidate(cacheGroupCounties)
+}
+
+func (a *storageListenerImpl) OnRulesChanged() {
+	a.app.cache.invalidate(cacheGroupRules)
+}
+
+func (a *storageListenerImpl) OnAccessRulesChanged() {
+	a.app.cache.invalidate(cacheGroupAccessRules)
+}
+
+func (a *storageListenerImpl) OnTestTypesChanged() {
+	a.app.cache.invalidate(cacheGroupTestTypes)
+}
+
+func (a *storageListenerImpl) OnLocationsChanged() {
+	a.app.cache.invalidate(cacheGroupLocations)
+}
+
+func (a *storageListenerImpl) OnProvidersChanged() {
+	a.app.cache.invalidate(cacheGroupProviders)
+}
+
+func (a *storageListenerImpl) OnCRulesChanged() {
+	a.app.cache.invalidate(cacheGroupCRules)
+}
+
 //DataProvider is used by core to access needed data
 type DataProvider interface {
-	LoadNews() ([]ProviderNews, error)
+	GetDefaultNewsURL() string
+	LoadNews(url string, format string, validators ProviderValidators) ([]ProviderNews, ProviderValidators, error)
 	LoadResources() ([]ProviderResource, error)
+	GetFetchMetrics() []model.ProviderFetchMetrics
+}
+
+//ProviderValidators are the ETag and the Last-Modified of a loaded feed, empty if the feed has not been loaded yet
+type ProviderValidators struct {
+	ETag         string
+	LastModified string
 }
 
 //ProviderNews represents data provider news entity
 type ProviderNews struct {
+	GUID           string //empty if the feed does not give it
+	Link           string
 	PubDate        time.Time
 	Title          string
 	Description    string
 	ContentEncoded string
+	Categories     []string
 }
 
 //ProviderResource represents data provider resource entity
@@ -925,7 +1313,7 @@ type Messaging interface {
 
 //ProfileBuildingBlock is used by core to communicate with the profile building block.
 type ProfileBuildingBlock interface {
-	LoadUserData(uuid string) (*ProfileUserData, error)
+	LoadUserData(ctx context.Context, uuid string) (*ProfileUserData, error)
 }
 
 //ProfileUserData represents the profile building block user data entity
@@ -941,6 +1329,11 @@ type Audit interface {
 
 	Find(userIdentifier *string, usedGroup *string, entity *string, entityID *string, operation *string,
 		clientData *string, createdAt *time.Time, sortBy *string, asc *bool, limit *int64) ([]*AuditEntity, error)
+
+	//deletes the items created before the provided date except the ones for the excluded entities and gives the deleted count
+	DeleteOlderThan(ctx context.Context, date time.Time, excludedEntities []string) (int64, error)
+	//replaces the provided user identifiers in the items and gives the changed count
+	AnonymizeUser(ctx context.Context, identifiers []string) (int64, error)
 }
 
 //AuditEntity represents audit module entity
@@ -0,0 +1,126 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"health/core/model"
+	"log"
+)
+
+//getPendingReEncryptions gives the not processed ctests which are encrypted for an old user public key, grouped by user.
+//It gives only the ctests of the provider if it is provided.
+func (app *Application) getPendingReEncryptions(ctx context.Context, providerID *string) ([]*model.PendingReEncryption, error) {
+	ctests, err := app.storage.FindCTestsWithOldKeyVersion(ctx, providerID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*model.PendingReEncryption{}
+	usersItems := make(map[string]*model.PendingReEncryption)
+	for _, ctest := range ctests {
+		item, found := usersItems[ctest.UserID]
+		if !found {
+			user, err := app.storage.FindUser(ctx, ctest.UserID)
+			if err != nil {
+				return nil, err
+			}
+			if user == nil {
+				continue
+			}
+			item = &model.PendingReEncryption{UserID: user.ID, UIN: user.ExternalID, PublicKey: user.PublicKey, KeyVersion: user.KeyVersion}
+			usersItems[ctest.UserID] = item
+			result = append(result, item)
+		}
+		item.CTests = append(item.CTests, ctest)
+	}
+	return result, nil
+}
+
+//reEncryptCTest replaces the encrypted data of a ctest with data encrypted for the current user public key
+func (app *Application) reEncryptCTest(ctx context.Context, providerID string, ID string, keyVersion int, encryptedKey string, encryptedBlob string) error {
+	ctest, err := app.storage.FindCTest(ctx, ID)
+	if err != nil {
+		return err
+	}
+	if ctest == nil {
+		return errors.New("there is no a ctest for the provided id")
+	}
+	if ctest.ProviderID != providerID {
+		return errors.New("the ctest is not created by the provider")
+	}
+	if ctest.Processed {
+		return errors.New("the ctest is already processed")
+	}
+
+	user, err := app.storage.FindUser(ctx, ctest.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.New("there is no a user for the ctest")
+	}
+	//only the current key is accepted, the user could have rotated the key again
+	if keyVersion != user.KeyVersion {
+		return fmt.Errorf("the key version %d is not the current user key version %d", keyVersion, user.KeyVersion)
+	}
+
+	ctest.EncryptedKey = encryptedKey
+	ctest.EncryptedBlob = encryptedBlob
+	ctest.KeyVersion = keyVersion
+	err = app.storage.SaveCTest(ctx, ctest)
+	if err != nil {
+		return err
+	}
+	log.Printf("ctest %s is re-encrypted for key version %d", ctest.ID, keyVersion)
+
+	//the user can process it now
+	defer app.notifyListeners("onUserUpdated", *user)
+	app.sendPendingTestsNotification(*user)
+
+	return nil
+}
+
+//getUserKeysStatus gives the user key versions and the items encrypted for the previous versions
+func (app *Application) getUserKeysStatus(ctx context.Context, current model.User) (*model.UserKeysStatus, error) {
+	ctests, err := app.storage.FindCTestsWithOldKeyVersion(ctx, nil, &current.ID)
+	if err != nil {
+		return nil, err
+	}
+	histories, err := app.storage.FindEHistories(ctx, current.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := model.UserKeysStatus{KeyVersion: current.KeyVersion, PublicKeys: current.PublicKeys,
+		PendingCTests: []string{}, PendingHistories: []string{}}
+	if result.PublicKeys == nil {
+		result.PublicKeys = []model.UserPublicKey{}
+	}
+	for _, ctest := range ctests {
+		result.PendingCTests = append(result.PendingCTests, ctest.ID)
+	}
+	for _, history := range histories {
+		if history.KeyVersion < current.KeyVersion {
+			result.PendingHistories = append(result.PendingHistories, history.ID)
+		}
+	}
+	return &result, nil
+}
@@ -0,0 +1,182 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"health/core/model"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	locationExceptionClosed       = "closed"
+	locationExceptionSpecialHours = "special-hours"
+	locationExceptionRelocated    = "relocated"
+
+	locationExceptionDateLayout = "2006-01-02"
+)
+
+//updateLocationExceptions replaces the exceptions of the location. The wait time color is checked right away
+//so a location which has just been closed does not show open until the next check.
+func (app *Application) updateLocationExceptions(ctx context.Context, current model.User, group string, audit *string,
+	ID string, exceptions []model.LocationException) (*model.Location, error) {
+	location, err := app.storage.FindLocation(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	if location == nil {
+		return nil, errors.New("there is no a location for the provided id")
+	}
+
+	exceptions, err = checkLocationExceptions(exceptions)
+	if err != nil {
+		return nil, err
+	}
+	err = app.storage.UpdateLocationExceptions(ctx, ID, exceptions)
+	if err != nil {
+		return nil, err
+	}
+	location.Exceptions = exceptions
+
+	app.checkLocationWaitTimeColor(ctx, location)
+
+	//audit
+	userIdentifier, userInfo := current.GetLogData()
+	lData := []AuditDataEntry{{Key: "exceptions", Value: fmt.Sprint(exceptions)}}
+	defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "location-exceptions", ID, lData, audit)
+
+	return location, nil
+}
+
+//checkLocationExceptions validates the exceptions and gives them sorted by date with ids and end dates
+func checkLocationExceptions(exceptions []model.LocationException) ([]model.LocationException, error) {
+	result := make([]model.LocationException, len(exceptions))
+	for i, exception := range exceptions {
+		if len(exception.ID) == 0 {
+			id, err := uuid.NewUUID()
+			if err != nil {
+				return nil, err
+			}
+			exception.ID = id.String()
+		}
+		if len(exception.EndDate) == 0 {
+			exception.EndDate = exception.StartDate
+		}
+
+		startDate, err := time.Parse(locationExceptionDateLayout, exception.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start date %s, it must be yyyy-mm-dd", exception.StartDate)
+		}
+		endDate, err := time.Parse(locationExceptionDateLayout, exception.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end date %s, it must be yyyy-mm-dd", exception.EndDate)
+		}
+		if endDate.Before(startDate) {
+			return nil, fmt.Errorf("the end date %s is before the start date %s", exception.EndDate, exception.StartDate)
+		}
+
+		switch exception.Type {
+		case locationExceptionClosed:
+		case locationExceptionSpecialHours:
+			err = checkLocationExceptionHours(exception, true)
+		case locationExceptionRelocated:
+			if len(exception.Address) == 0 {
+				return nil, errors.New("the address is required for relocated")
+			}
+			err = checkLocationExceptionHours(exception, false)
+		default:
+			return nil, errors.New("the type must be closed, special-hours or relocated")
+		}
+		if err != nil {
+			return nil, err
+		}
+		result[i] = exception
+	}
+
+	//only one exception per date
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].StartDate < result[j].StartDate
+	})
+	for i := 1; i < len(result); i++ {
+		if result[i].StartDate <= result[i-1].EndDate {
+			return nil, fmt.Errorf("the exceptions from %s and %s overlap", result[i-1].StartDate, result[i].StartDate)
+		}
+	}
+	return result, nil
+}
+
+//checkLocationExceptionHours validates the open and close times, they can be skipped only if not required
+func checkLocationExceptionHours(exception model.LocationException, required bool) error {
+	if !required && len(exception.OpenTime) == 0 && len(exception.CloseTime) == 0 {
+		return nil
+	}
+	_, _, err := parseOperationInterval(exception.OpenTime, exception.CloseTime)
+	return err
+}
+
+//findLocationException gives the exception for the date, the dates are compared as yyyy-mm-dd strings
+func findLocationException(exceptions []model.LocationException, date string) *model.LocationException {
+	for i, exception := range exceptions {
+		if exception.StartDate <= date && date <= exception.EndDate {
+			return &exceptions[i]
+		}
+	}
+	return nil
+}
+
+//upcomingLocationExceptions gives the exceptions which have not ended yet in the location timezone
+func upcomingLocationExceptions(location *model.Location, now time.Time) []model.LocationException {
+	timeLocation, err := time.LoadLocation(location.Timezone)
+	if err != nil {
+		log.Printf("Error getting time location:%s\n", err.Error())
+		timeLocation = time.UTC
+	}
+	today := now.In(timeLocation).Format(locationExceptionDateLayout)
+
+	var result []model.LocationException
+	for _, exception := range location.Exceptions {
+		if exception.EndDate >= today {
+			result = append(result, exception)
+		}
+	}
+	return result
+}
+
+//publicLocations gives copies of the locations with only the exceptions which have not ended, the past ones are for the admins only.
+//The reported wait time is marked if it is stale. The cached locations are shared, so they are not changed.
+func (app *Application) publicLocations(locations []*model.Location) []*model.Location {
+	now := time.Now()
+	var result []*model.Location
+	for _, location := range locations {
+		item := *location
+		item.Exceptions = upcomingLocationExceptions(location, now)
+		if location.WaitTime != nil {
+			waitTime := *location.WaitTime
+			waitTime.Stale = app.isWaitTimeStale(waitTime, now)
+			item.WaitTime = &waitTime
+		}
+		result = append(result, &item)
+	}
+	return result
+}
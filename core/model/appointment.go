@@ -0,0 +1,49 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package model
+
+import "time"
+
+//AppointmentSettings represents how the appointment slots of a location are generated from its days of operation
+type AppointmentSettings struct {
+	SlotMinutes int `json:"slot_minutes" bson:"slot_minutes"`
+	Capacity    int `json:"capacity" bson:"capacity"`     //appointments per slot
+	DaysAhead   int `json:"days_ahead" bson:"days_ahead"` //how many days can be booked, today included
+} // @name AppointmentSettings
+
+//AppointmentSlot represents a period at a location for which the users can book appointments
+type AppointmentSlot struct {
+	Start    time.Time
+	End      time.Time
+	Capacity int
+	Booked   int
+}
+
+//Appointment represents a booked appointment of a user at a location
+type Appointment struct {
+	ID         string    `json:"id" bson:"_id"`
+	LocationID string    `json:"location_id" bson:"location_id"`
+	UserID     string    `json:"user_id" bson:"user_id"`
+	Start      time.Time `json:"start" bson:"start"`
+	End        time.Time `json:"end" bson:"end"`
+	Status     string    `json:"status" bson:"status"` //booked, cancelled or completed
+
+	ReminderSent bool       `json:"-" bson:"reminder_sent"`
+	DateCreated  time.Time  `json:"date_created" bson:"date_created"`
+	DateUpdated  *time.Time `json:"date_updated" bson:"date_updated"`
+} // @name Appointment
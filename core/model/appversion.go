@@ -0,0 +1,28 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package model
+
+import "time"
+
+//AppVersion represents an app version with its lifecycle status.
+//The supported and the deprecated versions are served, the deprecated ones are notified to upgrade. The blocked versions are rejected.
+type AppVersion struct {
+	Version     string     `json:"version" bson:"version"`
+	Status      string     `json:"status" bson:"status"` //supported, deprecated or blocked
+	DateUpdated *time.Time `json:"date_updated" bson:"date_updated"`
+} // @name AppVersion
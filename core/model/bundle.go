@@ -0,0 +1,159 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package model
+
+import "time"
+
+//CountyBundle represents the complete configuration of a county in a self-contained format.
+//The county statuses are referred by their ref instead of id. The test types and the providers are shared
+// between the counties so they are referred by name and must exist where the bundle is imported.
+type CountyBundle struct {
+	FormatVersion int       `json:"format_version"`
+	ExportedAt    time.Time `json:"exported_at"`
+
+	County         BundleCounty         `json:"county"`
+	CountyStatuses []BundleCountyStatus `json:"county_statuses"`
+	Guidelines     []BundleGuideline    `json:"guidelines"`
+	Rules          []BundleRule         `json:"rules"`
+	AccessRule     *BundleAccessRule    `json:"access_rule"`
+	SymptomRule    *BundleSymptomRule   `json:"symptom_rule"`
+	CRules         []BundleCRules       `json:"crules"`
+	Locations      []BundleLocation     `json:"locations"`
+} // @name CountyBundle
+
+//BundleCounty represents the county data in a bundle
+type BundleCounty struct {
+	Name          string `json:"name"`
+	StateProvince string `json:"state_province"`
+	Country       string `json:"country"`
+} // @name BundleCounty
+
+//BundleCountyStatus represents a county status in a bundle
+type BundleCountyStatus struct {
+	Ref         string `json:"ref"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+} // @name BundleCountyStatus
+
+//BundleGuideline represents a guideline in a bundle
+type BundleGuideline struct {
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	Items       []BundleGuidelineItem `json:"items"`
+} // @name BundleGuideline
+
+//BundleGuidelineItem represents a guideline item in a bundle
+type BundleGuidelineItem struct {
+	Icon        string `json:"icon"`
+	Description string `json:"description"`
+	Type        string `json:"type"`
+} // @name BundleGuidelineItem
+
+//BundleRule represents a test type rule in a bundle
+type BundleRule struct {
+	TestType      string                  `json:"test_type"` //test type name
+	Priority      *int                    `json:"priority"`
+	ResultsStates []BundleRuleResultState `json:"results_states"`
+} // @name BundleRule
+
+//BundleRuleResultState represents test type result and county status mapping in a bundle
+type BundleRuleResultState struct {
+	TestTypeResult string `json:"test_type_result"` //test type result name
+	CountyStatus   string `json:"county_status"`    //county status ref
+} // @name BundleRuleResultState
+
+//BundleAccessRule represents an access rule in a bundle
+type BundleAccessRule struct {
+	Rules []BundleAccessRuleItem `json:"rules"`
+} // @name BundleAccessRule
+
+//BundleAccessRuleItem represents "granted"/"denied" county status mapping in a bundle
+type BundleAccessRuleItem struct {
+	CountyStatus string `json:"county_status"` //county status ref
+	Value        string `json:"value"`
+} // @name BundleAccessRuleItem
+
+//BundleSymptomRule represents a symptom rule in a bundle
+type BundleSymptomRule struct {
+	Gr1Count int                     `json:"gr1_count"`
+	Gr2Count int                     `json:"gr2_count"`
+	Items    []BundleSymptomRuleItem `json:"items"`
+} // @name BundleSymptomRule
+
+//BundleSymptomRuleItem represents a symptom rule item in a bundle
+type BundleSymptomRuleItem struct {
+	Gr1          bool   `json:"gr1"`
+	Gr2          bool   `json:"gr2"`
+	CountyStatus string `json:"county_status"` //county status ref
+	NextStep     string `json:"next_step"`
+} // @name BundleSymptomRuleItem
+
+//BundleCRules represents the rules for an app version in a bundle. The county, the county statuses, the test types and
+// the test type results ids in the data are replaced with {{county}}, {{county-status:ref}}, {{test-type:name}}
+// and {{test-type-result:test type name/result name}} placeholders.
+type BundleCRules struct {
+	AppVersion string `json:"app_version"`
+	Data       string `json:"data"`
+} // @name BundleCRules
+
+//BundleLocation represents a location in a bundle
+type BundleLocation struct {
+	Provider        string               `json:"provider"` //provider name
+	Name            string               `json:"name"`
+	Address1        string               `json:"address_1"`
+	Address2        string               `json:"address_2"`
+	City            string               `json:"city"`
+	State           string               `json:"state"`
+	ZIP             string               `json:"zip"`
+	Country         string               `json:"country"`
+	Latitude        float64              `json:"latitude"`
+	Longitude       float64              `json:"longitude"`
+	Timezone        string               `json:"timezone"`
+	Contact         string               `json:"contact"`
+	DaysOfOperation []BundleOperationDay `json:"days_of_operation"`
+	URL             string               `json:"url"`
+	Notes           string               `json:"notes"`
+	AvailableTests  []string             `json:"available_tests"` //test types names
+} // @name BundleLocation
+
+//BundleOperationDay represents a location operation day in a bundle
+type BundleOperationDay struct {
+	Name      string                    `json:"name"`
+	AllDay    bool                      `json:"all_day"`
+	Intervals []BundleOperationInterval `json:"intervals"`
+
+	//one interval in the bundles exported before the intervals, used only if there are no intervals
+	OpenTime  string `json:"open_time,omitempty"`
+	CloseTime string `json:"close_time,omitempty"`
+} // @name BundleOperationDay
+
+//BundleOperationInterval represents an interval of a location operation day in a bundle
+type BundleOperationInterval struct {
+	OpenTime  string `json:"open_time"`
+	CloseTime string `json:"close_time"`
+} // @name BundleOperationInterval
+
+//BundleChange represents a difference between a bundle and the current county configuration
+type BundleChange struct {
+	Operation string      `json:"operation"` //add, modify or delete
+	Entity    string      `json:"entity"`    //county, county-status, guideline, guideline-item, rule, rule-result-state, access-rule, access-rule-item, symptom-rule, test-type, test-type-result, crules or location
+	Key       string      `json:"key"`       //identifies the item - ref, name, test type name or app version
+	Field     string      `json:"field"`     //the modified field, empty for add and delete
+	Current   interface{} `json:"current"`
+	New       interface{} `json:"new"`
+} // @name BundleChange
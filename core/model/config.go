@@ -21,4 +21,9 @@ package model
 type COVID19Config struct {
 	Name             string `json:"name" bson:"name"`
 	NewsUpdatePeriod int    `json:"news_update_period" bson:"news_update_period"` //in minutes
+
+	//the reported wait time is yellow from and red from these minutes, it is stale after the stale minutes
+	WaitTimeYellowMinutes int `json:"wait_time_yellow_minutes" bson:"wait_time_yellow_minutes"`
+	WaitTimeRedMinutes    int `json:"wait_time_red_minutes" bson:"wait_time_red_minutes"`
+	WaitTimeStaleMinutes  int `json:"wait_time_stale_minutes" bson:"wait_time_stale_minutes"`
 }
@@ -0,0 +1,38 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package model
+
+import "time"
+
+//ConfigSchema represents the JSON Schema the crules data or the symptoms items must follow for an app version.
+//Besides the standard keywords the schema can mark string values as references with the formats
+//county-status-id, test-type-id, test-type-result-id and symptom-id.
+type ConfigSchema struct {
+	ID          string     `json:"id" bson:"_id"`
+	Type        string     `json:"type" bson:"type"` //crules or symptoms
+	AppVersion  string     `json:"app_version" bson:"app_version"`
+	Schema      string     `json:"schema" bson:"schema"`
+	DateCreated time.Time  `json:"date_created" bson:"date_created"`
+	DateUpdated *time.Time `json:"date_updated" bson:"date_updated"`
+} // @name ConfigSchema
+
+//ValidationIssue represents a problem found in a validated JSON document
+type ValidationIssue struct {
+	Path    string `json:"path"` //(root).items.0.id
+	Message string `json:"message"`
+} // @name ValidationIssue
@@ -0,0 +1,37 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package model
+
+import "time"
+
+//ProviderFetchMetrics represents the fetch health of a data provider url since the service is started
+type ProviderFetchMetrics struct {
+	URL         string `json:"url"`
+	Fetches     int64  `json:"fetches"`
+	Successes   int64  `json:"successes"`
+	NotModified int64  `json:"not_modified"`
+	Failures    int64  `json:"failures"`
+	Retries     int64  `json:"retries"`
+
+	LastFetchedAt   *time.Time `json:"last_fetched_at"`
+	LastSucceededAt *time.Time `json:"last_succeeded_at"`
+	LastStatusCode  int        `json:"last_status_code"` //0 if there is no response
+	LastDurationMs  int64      `json:"last_duration_ms"` //with the retries
+	LastBytes       int64      `json:"last_bytes"`
+	LastError       *string    `json:"last_error"`
+} // @name ProviderFetchMetrics
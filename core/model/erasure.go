@@ -0,0 +1,34 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package model
+
+import "time"
+
+//ErasureReceipt represents a proof that the data of a user has been erased. It does not keep any user data,
+//the user is referred by a hash so the receipts can be found later for the same uin.
+type ErasureReceipt struct {
+	ID          string           `json:"id" bson:"_id"`
+	SubjectHash string           `json:"subject_hash" bson:"subject_hash"` //hmac-sha256 of the uin or of the user id if there is no uin
+	Status      string           `json:"status" bson:"status"`             //in-progress, completed or failed
+	Error       *string          `json:"error" bson:"error"`
+	Counts      map[string]int64 `json:"counts" bson:"counts"` //erased or anonymized items per collection
+	RequestedAt time.Time        `json:"requested_at" bson:"requested_at"`
+	CompletedAt *time.Time       `json:"completed_at" bson:"completed_at"`
+
+	Signature *string `json:"signature" bson:"signature"` //base64 hmac-sha256 of the receipt without the signature
+} // @name ErasureReceipt
@@ -0,0 +1,78 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package model
+
+import "time"
+
+//StatusEvaluationInput represents the data a county status is evaluated from
+type StatusEvaluationInput struct {
+	Date           time.Time                 `json:"date"` //the moment of the evaluation
+	TestResults    []EvaluationTestResult    `json:"test_results"`
+	SymptomReports []EvaluationSymptomReport `json:"symptom_reports"`
+} // @name StatusEvaluationInput
+
+//EvaluationTestResult represents a test result. The test type and the result are given by id or by name.
+type EvaluationTestResult struct {
+	TestType string    `json:"test_type"`
+	Result   string    `json:"result"`
+	Date     time.Time `json:"date"`
+} // @name EvaluationTestResult
+
+//EvaluationSymptomReport represents the symptoms reported at a moment. The symptoms are given by id or by name.
+type EvaluationSymptomReport struct {
+	Symptoms []string  `json:"symptoms"`
+	Date     time.Time `json:"date"`
+} // @name EvaluationSymptomReport
+
+//StatusEvaluation represents the result of a county status evaluation
+type StatusEvaluation struct {
+	CountyID         string     `json:"county_id"`
+	CountyStatusID   *string    `json:"county_status_id"` //nil if there is no a valid test result or symptom report
+	CountyStatusName *string    `json:"county_status_name"`
+	Access           *string    `json:"access"` //granted or denied from the county access rule
+	NextStep         *string    `json:"next_step"`
+	NextStepDate     *time.Time `json:"next_step_date"`
+	ExpiresAt        *time.Time `json:"expires_at"`
+
+	Source      *string  `json:"source"`       //test-result or symptoms
+	SourceIndex *int     `json:"source_index"` //the index of the test result or the symptom report the status comes from
+	Explanation []string `json:"explanation"`  //how the status is chosen
+} // @name StatusEvaluation
+
+//RulesDraft represents not published county rules. Every provided part replaces the current one in a simulation.
+//The test types, the results and the county statuses are referred by id or by name.
+type RulesDraft struct {
+	Rules       []BundleRule       `json:"rules"`
+	AccessRule  *BundleAccessRule  `json:"access_rule"`
+	SymptomRule *BundleSymptomRule `json:"symptom_rule"`
+} // @name RulesDraft
+
+//SimulationEvent represents a hypothetical test result or symptom report in a simulation timeline
+type SimulationEvent struct {
+	Date     time.Time `json:"date"`
+	TestType *string   `json:"test_type"`
+	Result   *string   `json:"result"`
+	Symptoms []string  `json:"symptoms"` //used when there is no a test type
+} // @name SimulationEvent
+
+//SimulationStep represents the status evaluated at a point of a simulation timeline
+type SimulationStep struct {
+	Date       time.Time        `json:"date"`
+	Reason     string           `json:"reason"` //event, expiry or point
+	Evaluation StatusEvaluation `json:"evaluation"`
+} // @name SimulationStep
@@ -0,0 +1,63 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package model
+
+import "time"
+
+//UserDataManifest describes the files in a user data export archive
+type UserDataManifest struct {
+	FormatVersion int                    `json:"format_version"`
+	UserID        string                 `json:"user_id"`
+	GeneratedAt   time.Time              `json:"generated_at"`
+	Encryption    string                 `json:"encryption"`
+	Files         []UserDataManifestFile `json:"files"`
+} // @name UserDataManifest
+
+//UserDataManifestFile represents a file in a user data export archive
+type UserDataManifestFile struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Count       int    `json:"count"` //the number of items in the file
+	Size        int    `json:"size"`
+	SHA256      string `json:"sha256"`
+} // @name UserDataManifestFile
+
+//UserDataCTest represents a ctest in a user data export with the name of its provider
+type UserDataCTest struct {
+	CTest
+	ProviderName *string `json:"provider_name"`
+} // @name UserDataCTest
+
+//UserDataManualTest represents a manual test in a user data export, the image is in a separate file
+type UserDataManualTest struct {
+	ID            string    `json:"id"`
+	HistoryID     string    `json:"ehistory_id"`
+	LocationID    *string   `json:"location_id"`
+	CountyID      *string   `json:"county_id"`
+	EncryptedKey  string    `json:"encrypted_key"`
+	EncryptedBlob string    `json:"encrypted_blob"`
+	Status        string    `json:"status"`
+	Date          time.Time `json:"date"`
+	Image         *string   `json:"image"` //the image file name in the archive
+} // @name UserDataManualTest
+
+//UserDataManualTestImage represents a manual test image in a user data export
+type UserDataManualTestImage struct {
+	EncryptedImageKey  string `json:"encrypted_image_key"`
+	EncryptedImageBlob string `json:"encrypted_image_blob"`
+} // @name UserDataManualTestImage
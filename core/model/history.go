@@ -27,4 +27,5 @@ type EHistory struct {
 	Type          string    `json:"type" bson:"type"`
 	EncryptedKey  string    `json:"encrypted_key" bson:"encrypted_key"`
 	EncryptedBlob string    `json:"encrypted_blob" bson:"encrypted_blob"`
+	KeyVersion    int       `json:"key_version" bson:"key_version"` //the user public key version the item is encrypted for
 } // @name History
@@ -0,0 +1,36 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package model
+
+//PendingReEncryption represents the ctests of a user which are encrypted for an old public key.
+//The providers re-submit them for the current key.
+type PendingReEncryption struct {
+	UserID     string   `json:"-"`
+	UIN        string   `json:"uin"`
+	PublicKey  string   `json:"public_key"`
+	KeyVersion int      `json:"key_version"`
+	CTests     []*CTest `json:"ctests"`
+} // @name PendingReEncryption
+
+//UserKeysStatus represents the user key versions and the items which the user cannot decrypt with the current key
+type UserKeysStatus struct {
+	KeyVersion       int             `json:"key_version"`
+	PublicKeys       []UserPublicKey `json:"public_keys"`
+	PendingCTests    []string        `json:"pending_ctests"`    //waiting for the providers to re-submit them
+	PendingHistories []string        `json:"pending_histories"` //the app must re-encrypt them if it still has the old key, otherwise they are lost
+} // @name UserKeysStatus
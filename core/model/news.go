@@ -19,7 +19,7 @@ package model
 
 import "time"
 
-//News represents news entity
+//News represents news entity. The apps receive it only when it is published, the publish time has come and it is not expired.
 type News struct {
 	ID          string    `json:"id" bson:"_id"`
 	Date        time.Time `json:"date" bson:"date"`
@@ -27,4 +27,38 @@ type News struct {
 	Description string    `json:"description" bson:"description"`
 	HTMLContent string    `json:"htmlContent" bson:"htmlContent"`
 	Link        *string   `json:"link" bson:"link"`
+
+	Status    string     `json:"status" bson:"status"`        //draft or published
+	PublishAt *time.Time `json:"publishAt" bson:"publish_at"` //right away if not set
+	ExpireAt  *time.Time `json:"expireAt" bson:"expire_at"`   //never if not set
+	Pinned    bool       `json:"pinned" bson:"pinned"`        //the pinned items are before the others
+
+	Categories []string `json:"categories" bson:"categories"`
+
+	SourceID *string `json:"source_id" bson:"source_id"` //nil for the news created by the admins
+	GUID     *string `json:"guid" bson:"guid"`           //the feed item guid or link, the items are imported once per source
+
+	DateUpdated *time.Time `json:"dateUpdated" bson:"date_updated"` //when it has been created or changed last, nil for the old items
 } // @name News
+
+//NewsSource represents a news feed the news are imported from
+type NewsSource struct {
+	ID           string `json:"id" bson:"_id"`
+	Name         string `json:"name" bson:"name"`
+	URL          string `json:"url" bson:"url"`
+	Format       string `json:"format" bson:"format"`               //rss, atom or json
+	PollInterval int    `json:"poll_interval" bson:"poll_interval"` //in minutes, the news update period from the config or 60 is used if 0
+	Enabled      bool   `json:"enabled" bson:"enabled"`
+
+	LastFetchedAt   *time.Time `json:"last_fetched_at" bson:"last_fetched_at"`
+	LastFetchStatus string     `json:"last_fetch_status" bson:"last_fetch_status"` //ok or failed, empty if not fetched yet
+	LastError       *string    `json:"last_error" bson:"last_error"`
+	LastAddedCount  int        `json:"last_added_count" bson:"last_added_count"`
+	LastSucceededAt *time.Time `json:"last_succeeded_at" bson:"last_succeeded_at"`
+	//the ETag and the Last-Modified of the last imported feed, they are sent with the next fetch
+	LastETag     string `json:"-" bson:"last_etag"`
+	LastModified string `json:"-" bson:"last_modified"`
+
+	DateCreated time.Time  `json:"date_created" bson:"date_created"`
+	DateUpdated *time.Time `json:"date_updated" bson:"date_updated"`
+} // @name NewsSource
@@ -43,9 +43,13 @@ type Location struct {
 	Timezone        string
 	Contact         string //phone
 	DaysOfOperation []OperationDay
+	Exceptions      []LocationException //date specific changes of the days of operation
 	URL             string
 	Notes           string
 	WaitTimeColor   *string
+	WaitTime        *WaitTimeReport //the latest reported wait time
+
+	AppointmentSettings *AppointmentSettings //nil if the location does not offer appointments
 
 	Provider Provider
 	County   County
@@ -53,9 +57,33 @@ type Location struct {
 	AvailableTests []TestType
 }
 
+//NearbyLocation represents a location found near a point
+type NearbyLocation struct {
+	Location *Location
+	Distance float64 //in meters
+}
+
 //OperationDay represents a day from the week saying the operation hours
 type OperationDay struct {
-	Name      string
-	OpenTime  string
+	Name      string //Monday, Tuesday..
+	AllDay    bool   //open for 24 hours, there are no intervals
+	Intervals []OperationInterval
+}
+
+//OperationInterval represents a period of the day when the location is open. It crosses midnight if the close time is before the open time.
+type OperationInterval struct {
+	OpenTime  string //03:04pm
+	CloseTime string
+}
+
+//LocationException represents a period when the location does not work by its days of operation - a holiday, special hours or a temporary relocation
+type LocationException struct {
+	ID        string
+	StartDate string //2006-01-02 in the location timezone
+	EndDate   string //inclusive, the same as the start date for one day
+	Type      string //closed, special-hours or relocated
+	OpenTime  string //03:04pm, required for special-hours, the days of operation hours are used for relocated if not set
 	CloseTime string
+	Address   string //the temporary address, required for relocated
+	Note      string
 }
@@ -17,6 +17,8 @@
 
 package model
 
+import "time"
+
 //Resource represents resource entity
 type Resource struct {
 	ID           string  `json:"id" bson:"_id"`
@@ -24,4 +26,23 @@ type Resource struct {
 	Link         string  `json:"link" bson:"link"`
 	Icon         *string `json:"icon" bson:"icon"`
 	DisplayOrder int     `json:"display_order" bson:"display_order"`
+
+	SourceKey *string `json:"source_key" bson:"source_key"` //the upstream item it is synced with, nil for the resources created by the admins
+	Stale     bool    `json:"stale" bson:"stale"`           //the upstream item has been removed
 } // @name Resource
+
+//ResourceChange represents an upstream change of a resource which waits for an admin review before it goes live
+type ResourceChange struct {
+	ID         string  `json:"id" bson:"_id"`
+	SourceKey  string  `json:"source_key" bson:"source_key"`
+	Operation  string  `json:"operation" bson:"operation"`     //add, modify or remove
+	ResourceID *string `json:"resource_id" bson:"resource_id"` //nil for add
+	Title      string  `json:"title" bson:"title"`             //the upstream values, the current ones for remove
+	Link       string  `json:"link" bson:"link"`
+	Status     string  `json:"status" bson:"status"` //pending, approved or rejected
+
+	ReviewedBy   *string    `json:"reviewed_by" bson:"reviewed_by"`
+	DateReviewed *time.Time `json:"date_reviewed" bson:"date_reviewed"`
+	DateCreated  time.Time  `json:"date_created" bson:"date_created"`
+	DateUpdated  *time.Time `json:"date_updated" bson:"date_updated"`
+} // @name ResourceChange
@@ -0,0 +1,28 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package model
+
+import "time"
+
+//RetentionPolicy represents how long the records of an entity type are kept
+type RetentionPolicy struct {
+	Entity      string     `json:"entity" bson:"_id"` //ehistory, ctest, emanualtest-image, trace-exposure, uin-override, wait-time-report, appointment or audit
+	Days        int        `json:"days" bson:"days"`  //the records older than this are purged
+	Enabled     bool       `json:"enabled" bson:"enabled"`
+	DateUpdated *time.Time `json:"date_updated" bson:"date_updated"`
+} // @name RetentionPolicy
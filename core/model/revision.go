@@ -0,0 +1,41 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package model
+
+import "time"
+
+//ConfigRevision represents a revision of the crules for a county and an app version or of the symptoms for an app version.
+//Only one revision is published at a time, it is the one the apps receive.
+type ConfigRevision struct {
+	ID         string `json:"id" bson:"_id"`
+	Type       string `json:"type" bson:"type"` //crules or symptoms
+	AppVersion string `json:"app_version" bson:"app_version"`
+	CountyID   string `json:"county_id" bson:"county_id"` //empty for the symptoms
+	Number     int    `json:"number" bson:"number"`
+	Data       string `json:"data" bson:"data"`
+	Status     string `json:"status" bson:"status"` //draft, published, archived or discarded
+
+	RolledBackFrom *int `json:"rolled_back_from" bson:"rolled_back_from"` //the revision number the data is taken from
+
+	CreatedBy     string     `json:"created_by" bson:"created_by"`
+	DateCreated   time.Time  `json:"date_created" bson:"date_created"`
+	UpdatedBy     *string    `json:"updated_by" bson:"updated_by"`
+	DateUpdated   *time.Time `json:"date_updated" bson:"date_updated"`
+	PublishedBy   *string    `json:"published_by" bson:"published_by"`
+	DatePublished *time.Time `json:"date_published" bson:"date_published"`
+} // @name ConfigRevision
@@ -28,6 +28,7 @@ type CTest struct {
 
 	EncryptedKey  string `json:"encrypted_key" bson:"encrypted_key"`
 	EncryptedBlob string `json:"encrypted_blob" bson:"encrypted_blob"`
+	KeyVersion    int    `json:"key_version" bson:"key_version"` //the user public key version the item is encrypted for
 
 	OrderNumber *string `json:"order_number" bson:"order_number"`
 
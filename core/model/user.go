@@ -29,16 +29,37 @@ type User struct {
 
 	UUID                 string  `json:"uuid" bson:"uuid"`
 	PublicKey            string  `json:"public_key" bson:"public_key"`
+	KeyVersion           int     `json:"key_version" bson:"key_version"` //the version of the current public key
 	Consent              bool    `json:"consent" bson:"consent"`
 	ExposureNotification bool    `json:"exposure_notification" bson:"exposure_notification"`
 	RePost               bool    `json:"re_post" bson:"re_post"`
 	EncryptedKey         *string `json:"encrypted_key" bson:"encrypted_key"`
 	EncryptedBlob        *string `json:"encrypted_blob" bson:"encrypted_blob"`
 
+	PublicKeys []UserPublicKey `json:"public_keys" bson:"public_keys"` //all public keys the user has had, the last one is the current
+
 	DateCreated time.Time  `json:"date_created" bson:"date_created"`
 	DateUpdated *time.Time `json:"date_updated" bson:"date_updated"`
 }
 
+//UserPublicKey represents a version of the user public key
+type UserPublicKey struct {
+	Version     int       `json:"version" bson:"version"`
+	PublicKey   string    `json:"public_key" bson:"public_key"`
+	DateCreated time.Time `json:"date_created" bson:"date_created"`
+} // @name UserPublicKey
+
+//RotatePublicKey keeps the new public key as a new key version. It does nothing if the key is the same as the current one.
+func (user *User) RotatePublicKey(publicKey string) bool {
+	if len(publicKey) == 0 || (len(user.PublicKeys) > 0 && user.PublicKeys[len(user.PublicKeys)-1].PublicKey == publicKey) {
+		return false
+	}
+	user.KeyVersion++
+	user.PublicKey = publicKey
+	user.PublicKeys = append(user.PublicKeys, UserPublicKey{Version: user.KeyVersion, PublicKey: publicKey, DateCreated: time.Now().UTC()})
+	return true
+}
+
 //IsAdmin says if the user is admin
 func (user User) IsAdmin() bool {
 	if user.ShibbolethAuth == nil {
@@ -0,0 +1,34 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package model
+
+import "time"
+
+//WaitTimeReport represents the wait time of a location reported by a provider system or by the staff
+type WaitTimeReport struct {
+	ID          string    `json:"id" bson:"_id"`
+	LocationID  string    `json:"location_id" bson:"location_id"`
+	WaitMinutes int       `json:"wait_minutes" bson:"wait_minutes"`
+	QueueLength *int      `json:"queue_length" bson:"queue_length"`
+	Color       string    `json:"color" bson:"color"`             //green, yellow or red by the thresholds at the moment of the report
+	Source      string    `json:"source" bson:"source"`           //provider or admin
+	ReportedBy  string    `json:"reported_by" bson:"reported_by"` //the admin user identifier, empty for the providers
+	DateCreated time.Time `json:"date_created" bson:"date_created"`
+
+	Stale bool `json:"stale" bson:"-"` //set when the location is given, the report is older than the stale minutes
+} // @name WaitTimeReport
@@ -0,0 +1,325 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"health/core/model"
+	"health/utils"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	newsSourceFormatRSS  = "rss"
+	newsSourceFormatAtom = "atom"
+	newsSourceFormatJSON = "json"
+
+	newsSourceFetchOK     = "ok"
+	newsSourceFetchFailed = "failed"
+
+	//the source created from the data provider feed
+	defaultNewsSourceID = "default"
+
+	//how often the sources are checked if they must be fetched
+	newsSourcesCheckPeriod = time.Minute
+	//used when neither the source nor the config gives the poll interval
+	defaultNewsPollInterval = 60 //in minutes
+)
+
+func (app *Application) getNewsSources(ctx context.Context) ([]*model.NewsSource, error) {
+	return app.storage.FindNewsSources(ctx)
+}
+
+func (app *Application) createNewsSource(ctx context.Context, current model.User, group string, audit *string,
+	name string, URL string, format string, pollInterval int, enabled bool) (*model.NewsSource, error) {
+	err := checkNewsSource(format, pollInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+	source := &model.NewsSource{ID: id.String(), Name: name, URL: URL, Format: format, PollInterval: pollInterval,
+		Enabled: enabled, DateCreated: time.Now().UTC()}
+	err = app.storage.SaveNewsSource(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+
+	//audit
+	userIdentifier, userInfo := current.GetLogData()
+	defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "news-source", source.ID, newsSourceLogData(source), audit)
+
+	return source, nil
+}
+
+func (app *Application) updateNewsSource(ctx context.Context, current model.User, group string, audit *string,
+	ID string, name string, URL string, format string, pollInterval int, enabled bool) (*model.NewsSource, error) {
+	err := checkNewsSource(format, pollInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := app.storage.FindNewsSource(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		return nil, errors.New("there is no a news source for the provided id")
+	}
+
+	//the validators of another feed cannot be used
+	if source.URL != URL || source.Format != format {
+		source.LastETag = ""
+		source.LastModified = ""
+	}
+
+	//add the new values
+	now := time.Now().UTC()
+	source.Name = name
+	source.URL = URL
+	source.Format = format
+	source.PollInterval = pollInterval
+	source.Enabled = enabled
+	source.DateUpdated = &now
+
+	//save it
+	err = app.storage.SaveNewsSource(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+
+	//audit
+	userIdentifier, userInfo := current.GetLogData()
+	defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "news-source", ID, newsSourceLogData(source), audit)
+
+	return source, nil
+}
+
+func (app *Application) deleteNewsSource(ctx context.Context, current model.User, group string, ID string) error {
+	err := app.storage.DeleteNewsSource(ctx, ID)
+	if err != nil {
+		return err
+	}
+
+	//audit
+	userIdentifier, userInfo := current.GetLogData()
+	defer app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "news-source", ID)
+	return nil
+}
+
+func checkNewsSource(format string, pollInterval int) error {
+	if format != newsSourceFormatRSS && format != newsSourceFormatAtom && format != newsSourceFormatJSON {
+		return errors.New("format must be rss, atom or json")
+	}
+	if pollInterval < 0 {
+		return errors.New("poll interval cannot be negative")
+	}
+	return nil
+}
+
+func newsSourceLogData(source *model.NewsSource) []AuditDataEntry {
+	return []AuditDataEntry{{Key: "name", Value: source.Name}, {Key: "url", Value: source.URL}, {Key: "format", Value: source.Format},
+		{Key: "pollInterval", Value: strconv.Itoa(source.PollInterval)}, {Key: "enabled", Value: strconv.FormatBool(source.Enabled)}}
+}
+
+func (app *Application) setupNewsTimer() {
+	app.ensureDefaultNewsSource()
+
+	//load for first time
+	app.loadNewsData()
+
+	//check the sources every minute, every source is fetched on its own poll interval
+	ticker := time.NewTicker(newsSourcesCheckPeriod)
+	for range ticker.C {
+		app.loadNewsData()
+	}
+}
+
+//ensureDefaultNewsSource registers the data provider feed as a news source if there is no any source
+func (app *Application) ensureDefaultNewsSource() {
+	ctx := context.Background()
+
+	URL := app.dataProvider.GetDefaultNewsURL()
+	if len(URL) == 0 {
+		return
+	}
+	sources, err := app.storage.FindNewsSources(ctx)
+	if err != nil {
+		log.Printf("ensureDefaultNewsSource() -> error on reading the news sources %s", err)
+		return
+	}
+	if len(sources) > 0 {
+		return
+	}
+
+	//the id is fixed, so it is created once if several instances start together
+	source := &model.NewsSource{ID: defaultNewsSourceID, Name: "Default", URL: URL, Format: newsSourceFormatRSS,
+		Enabled: true, DateCreated: time.Now().UTC()}
+	err = app.storage.SaveNewsSource(ctx, source)
+	if err != nil {
+		log.Printf("ensureDefaultNewsSource() -> error on saving the default news source %s", err)
+		return
+	}
+	log.Printf("ensureDefaultNewsSource() -> %s is registered as the default news source", URL)
+}
+
+//loadNewsData fetches the enabled sources which poll interval has passed
+func (app *Application) loadNewsData() {
+	ctx := context.Background()
+
+	sources, err := app.storage.FindNewsSources(ctx)
+	if err != nil {
+		log.Printf("loadNewsData() -> error on reading the news sources %s", err)
+		return
+	}
+	now := time.Now().UTC()
+	for _, source := range sources {
+		if !source.Enabled {
+			continue
+		}
+		if source.LastFetchedAt != nil && now.Sub(*source.LastFetchedAt) < app.getNewsPollInterval(source) {
+			continue
+		}
+		app.fetchNewsSource(ctx, source)
+	}
+}
+
+func (app *Application) getNewsPollInterval(source *model.NewsSource) time.Duration {
+	periodInMinutes := source.PollInterval
+	if periodInMinutes == 0 {
+		if config := app.getCachedCovid19Config(); config != nil {
+			periodInMinutes = config.NewsUpdatePeriod
+		}
+	}
+	if periodInMinutes <= 0 {
+		periodInMinutes = defaultNewsPollInterval
+	}
+	return time.Minute * time.Duration(periodInMinutes)
+}
+
+//fetchNewsSource imports the new items of the source. The items are unique per source by their guid or link if there is no guid.
+func (app *Application) fetchNewsSource(ctx context.Context, source *model.NewsSource) {
+	log.Printf("fetchNewsSource() -> fetch %s - %s", source.Name, source.URL)
+
+	now := time.Now().UTC()
+	source.LastFetchedAt = &now
+
+	addedCount, validators, err := app.importNewsItems(ctx, source)
+	if err != nil {
+		log.Printf("fetchNewsSource() -> error on fetching %s - %s", source.Name, err)
+		errMessage := err.Error()
+		source.LastFetchStatus = newsSourceFetchFailed
+		source.LastError = &errMessage
+	} else {
+		log.Printf("fetchNewsSource() -> %d news items added from %s", addedCount, source.Name)
+		source.LastFetchStatus = newsSourceFetchOK
+		source.LastError = nil
+		source.LastSucceededAt = &now
+		//the validators are kept only once all the items are imported, so a failed import is retried on the next fetch
+		source.LastETag = validators.ETag
+		source.LastModified = validators.LastModified
+	}
+	source.LastAddedCount = addedCount
+
+	err = app.storage.UpdateNewsSourceFetchStatus(ctx, source)
+	if err != nil {
+		log.Printf("fetchNewsSource() -> error on saving the fetch status for %s - %s", source.Name, err)
+	}
+}
+
+//importNewsItems adds the new items of the source. It gives the validators of the loaded feed which are sent with the next load.
+func (app *Application) importNewsItems(ctx context.Context, source *model.NewsSource) (int, ProviderValidators, error) {
+	validators := ProviderValidators{ETag: source.LastETag, LastModified: source.LastModified}
+	items, validators, err := app.dataProvider.LoadNews(source.URL, source.Format, validators)
+	if err != nil {
+		return 0, validators, err
+	}
+
+	//the default source was read before the sources were added, so its old items do not have a guid
+	var legacyItems map[string]bool
+	if source.ID == defaultNewsSourceID {
+		legacyItems, err = app.findLegacyNewsItems(ctx)
+		if err != nil {
+			return 0, validators, err
+		}
+	}
+
+	addedCount := 0
+	for _, item := range items {
+		guid := item.GUID
+		if len(guid) == 0 {
+			guid = item.Link
+		}
+		if len(guid) == 0 {
+			log.Printf("importNewsItems() -> skip %s as it does not have a guid or a link", item.Title)
+			continue
+		}
+		if legacyItems[legacyNewsItemKey(item.Title, item.PubDate)] {
+			continue
+		}
+
+		id, err := uuid.NewUUID()
+		if err != nil {
+			return addedCount, validators, err
+		}
+		var link *string
+		if len(item.Link) > 0 {
+			link = &item.Link
+		}
+		sourceID := source.ID
+		news := &model.News{ID: id.String(), Date: item.PubDate, Title: item.Title, Description: utils.ModifyHTMLContent(item.Description),
+			HTMLContent: utils.ModifyHTMLContent(item.ContentEncoded), Link: link, Status: newsStatusPublished, SourceID: &sourceID, GUID: &guid,
+			Categories: normalizeNewsCategories(item.Categories)}
+		added, err := app.storage.InsertSourceNews(ctx, news)
+		if err != nil {
+			return addedCount, validators, err
+		}
+		if added {
+			addedCount++
+		}
+	}
+	return addedCount, validators, nil
+}
+
+//findLegacyNewsItems gives the keys of the imported news which are not linked to a source
+func (app *Application) findLegacyNewsItems(ctx context.Context) (map[string]bool, error) {
+	newsList, err := app.storage.ReadNews(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]bool)
+	for _, news := range newsList {
+		if news.SourceID == nil && news.GUID == nil {
+			result[legacyNewsItemKey(news.Title, news.Date)] = true
+		}
+	}
+	return result, nil
+}
+
+func legacyNewsItemKey(title string, date time.Time) string {
+	return fmt.Sprintf("%s/%d", title, date.Unix())
+}
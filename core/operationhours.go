@@ -0,0 +1,210 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"health/core/model"
+	"log"
+	"sort"
+	"time"
+)
+
+const operationTimeLayout = "03:04pm"
+
+//checkDaysOfOperation validates the days of operation and gives them with the intervals sorted by the open time.
+//Every day can be only once and it must be open all day or have at least one interval. Only the last interval of a day can cross midnight
+//and its part after midnight cannot overlap the next day.
+func checkDaysOfOperation(days []model.OperationDay) ([]model.OperationDay, error) {
+	if days == nil {
+		return nil, nil
+	}
+
+	type parsedInterval struct {
+		interval  model.OperationInterval
+		openTime  int
+		closeTime int
+	}
+
+	result := make([]model.OperationDay, len(days))
+	used := make(map[string]bool, len(days))
+	//the first interval of the days and the last one if it crosses midnight, for the check with the next day
+	firstIntervals := make(map[string]parsedInterval, len(days))
+	overnightIntervals := make(map[string]parsedInterval, len(days))
+	for i, day := range days {
+		if !isWeekdayName(day.Name) {
+			return nil, fmt.Errorf("invalid day %s, it must be Monday, Tuesday..", day.Name)
+		}
+		if used[day.Name] {
+			return nil, fmt.Errorf("%s is more than once in the days of operation", day.Name)
+		}
+		used[day.Name] = true
+
+		if day.AllDay {
+			if len(day.Intervals) > 0 {
+				return nil, fmt.Errorf("%s is open all day, it cannot have intervals", day.Name)
+			}
+			result[i] = model.OperationDay{Name: day.Name, AllDay: true}
+			continue
+		}
+		if len(day.Intervals) == 0 {
+			return nil, fmt.Errorf("there are no intervals for %s", day.Name)
+		}
+
+		intervals := make([]parsedInterval, len(day.Intervals))
+		for j, interval := range day.Intervals {
+			openTime, closeTime, err := parseOperationInterval(interval.OpenTime, interval.CloseTime)
+			if err != nil {
+				return nil, fmt.Errorf("%s - %s", day.Name, err)
+			}
+			intervals[j] = parsedInterval{interval: interval, openTime: openTime, closeTime: closeTime}
+		}
+		sort.Slice(intervals, func(a, b int) bool {
+			return intervals[a].openTime < intervals[b].openTime
+		})
+
+		//an interval which crosses midnight ends at the end of the day for this check
+		sorted := make([]model.OperationInterval, len(intervals))
+		for j, item := range intervals {
+			if j > 0 {
+				previous := intervals[j-1]
+				if previous.closeTime < previous.openTime || item.openTime < previous.closeTime {
+					return nil, fmt.Errorf("%s - the intervals %s-%s and %s-%s overlap", day.Name, previous.interval.OpenTime,
+						previous.interval.CloseTime, item.interval.OpenTime, item.interval.CloseTime)
+				}
+			}
+			sorted[j] = item.interval
+		}
+		result[i] = model.OperationDay{Name: day.Name, Intervals: sorted}
+
+		firstIntervals[day.Name] = intervals[0]
+		if last := intervals[len(intervals)-1]; last.closeTime < last.openTime {
+			overnightIntervals[day.Name] = last
+		}
+	}
+
+	for _, day := range result {
+		overnight, crossesMidnight := overnightIntervals[day.Name]
+		nextDayName := nextWeekdayName(day.Name)
+		if !crossesMidnight || !used[nextDayName] {
+			continue
+		}
+		first, hasIntervals := firstIntervals[nextDayName]
+		if !hasIntervals {
+			return nil, fmt.Errorf("%s - the interval %s-%s overlaps %s which is open all day", day.Name, overnight.interval.OpenTime,
+				overnight.interval.CloseTime, nextDayName)
+		}
+		if first.openTime < overnight.closeTime {
+			return nil, fmt.Errorf("%s - the interval %s-%s overlaps the %s interval %s-%s", day.Name, overnight.interval.OpenTime,
+				overnight.interval.CloseTime, nextDayName, first.interval.OpenTime, first.interval.CloseTime)
+		}
+	}
+	return result, nil
+}
+
+//parseOperationInterval gives the open and the close times as seconds within the day
+func parseOperationInterval(openTimeValue string, closeTimeValue string) (int, int, error) {
+	openTime, err := time.Parse(operationTimeLayout, openTimeValue)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid open time %s, it must be like 08:00am", openTimeValue)
+	}
+	closeTime, err := time.Parse(operationTimeLayout, closeTimeValue)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid close time %s, it must be like 05:00pm", closeTimeValue)
+	}
+	openTimeInSec := daySeconds(openTime)
+	closeTimeInSec := daySeconds(closeTime)
+	if openTimeInSec == closeTimeInSec {
+		return 0, 0, errors.New("the open and the close times cannot be the same, use all day for 24 hours")
+	}
+	return openTimeInSec, closeTimeInSec, nil
+}
+
+//operationIntervals gives if the location is open all day and the intervals for the date. The exception for the date is used
+//instead of the days of operation if there is such.
+func operationIntervals(location *model.Location, date time.Time) (bool, []model.OperationInterval) {
+	exception := findLocationException(location.Exceptions, date.Format(locationExceptionDateLayout))
+	if exception != nil {
+		switch exception.Type {
+		case locationExceptionClosed:
+			return false, nil
+		case locationExceptionSpecialHours:
+			return false, []model.OperationInterval{{OpenTime: exception.OpenTime, CloseTime: exception.CloseTime}}
+		case locationExceptionRelocated:
+			if len(exception.OpenTime) > 0 {
+				return false, []model.OperationInterval{{OpenTime: exception.OpenTime, CloseTime: exception.CloseTime}}
+			}
+		}
+	}
+
+	day := date.Weekday().String()
+	for _, current := range location.DaysOfOperation {
+		if current.Name == day {
+			return current.AllDay, current.Intervals
+		}
+	}
+	return false, nil
+}
+
+//isOpenAt checks if the passed seconds within the day are in the interval. The part after midnight of an interval
+//which crosses midnight is checked with the next day.
+func isOpenAt(interval model.OperationInterval, passedSeconds int) bool {
+	openTime, closeTime, err := parseOperationInterval(interval.OpenTime, interval.CloseTime)
+	if err != nil {
+		log.Printf("error parsing operation interval - %s", err)
+		return false
+	}
+	if closeTime < openTime {
+		return passedSeconds >= openTime
+	}
+	return passedSeconds >= openTime && passedSeconds < closeTime
+}
+
+//isOpenAfterMidnight checks if the passed seconds within the day are in the part after midnight of the interval
+func isOpenAfterMidnight(interval model.OperationInterval, passedSeconds int) bool {
+	openTime, closeTime, err := parseOperationInterval(interval.OpenTime, interval.CloseTime)
+	if err != nil {
+		log.Printf("error parsing operation interval - %s", err)
+		return false
+	}
+	return closeTime < openTime && passedSeconds < closeTime
+}
+
+func daySeconds(t time.Time) int {
+	return (t.Hour() * 60 * 60) + (t.Minute() * 60) + t.Second()
+}
+
+//nextWeekdayName gives the name of the day after the passed one
+func nextWeekdayName(name string) string {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if day.String() == name {
+			return ((day + 1) % 7).String()
+		}
+	}
+	return ""
+}
+
+func isWeekdayName(name string) bool {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if day.String() == name {
+			return true
+		}
+	}
+	return false
+}
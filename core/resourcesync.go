@@ -0,0 +1,370 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"context"
+	"errors"
+	"health/core/model"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	resourceChangeAdd    = "add"
+	resourceChangeModify = "modify"
+	resourceChangeRemove = "remove"
+
+	resourceChangePending  = "pending"
+	resourceChangeApproved = "approved"
+	resourceChangeRejected = "rejected"
+
+	resourcesSyncPeriod = time.Hour
+)
+
+func (app *Application) setupResourcesTimer() {
+	//load for first time
+	app.loadResourcesData()
+
+	ticker := time.NewTicker(resourcesSyncPeriod)
+	for range ticker.C {
+		app.loadResourcesData()
+	}
+}
+
+func (app *Application) loadResourcesData() {
+	log.Println("loadResourcesData() -> load data from the provider")
+
+	pending, err := app.syncResources(context.Background())
+	if err != nil {
+		log.Printf("loadResourcesData() -> error on syncing the resources %s", err)
+		return
+	}
+	log.Printf("loadResourcesData() -> there are %d pending resource changes", len(pending))
+}
+
+//syncResources compares the upstream items with the resources and queues the changes for a review. Nothing goes live before an admin approves it.
+//The resources are matched to the upstream items by their source key, the admins fields as the icon and the display order are never changed.
+//It gives the pending changes.
+func (app *Application) syncResources(ctx context.Context) ([]*model.ResourceChange, error) {
+	app.rsLock.Lock()
+	defer app.rsLock.Unlock()
+
+	//1. load the provider data
+	providerData, err := app.dataProvider.LoadResources()
+	if err != nil {
+		return nil, err
+	}
+	if len(providerData) == 0 {
+		//most likely the page has been changed, do not propose removing all the resources
+		return nil, errors.New("the provider does not give any resources")
+	}
+
+	//2. load the resources and the changes
+	resources, err := app.storage.ReadAllResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pendingStatus := resourceChangePending
+	pending, err := app.storage.FindResourceChanges(ctx, &pendingStatus)
+	if err != nil {
+		return nil, err
+	}
+	rejectedStatus := resourceChangeRejected
+	rejected, err := app.storage.FindResourceChanges(ctx, &rejectedStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	//3. map the resources to the upstream items
+	err = app.mapResourcesSourceKeys(ctx, resources, providerData)
+	if err != nil {
+		return nil, err
+	}
+
+	//4. find the changes
+	changes := findResourceChanges(resources, providerData)
+
+	//5. queue them
+	return app.queueResourceChanges(ctx, changes, pending, rejected)
+}
+
+//mapResourcesSourceKeys links the resources created before the sync or by the admins to the upstream items with the same link
+func (app *Application) mapResourcesSourceKeys(ctx context.Context, resources []*model.Resource, providerData []ProviderResource) error {
+	mapped := make(map[string]bool, len(resources))
+	for _, resource := range resources {
+		if resource.SourceKey != nil {
+			mapped[*resource.SourceKey] = true
+		}
+	}
+
+	for _, item := range providerData {
+		key := resourceSourceKey(item.Link)
+		if mapped[key] {
+			continue
+		}
+		for _, resource := range resources {
+			if resource.SourceKey != nil || resourceSourceKey(resource.Link) != key {
+				continue
+			}
+			resource.SourceKey = &key
+			err := app.storage.SaveResource(ctx, resource)
+			if err != nil {
+				return err
+			}
+			mapped[key] = true
+			log.Printf("mapResourcesSourceKeys() -> %s is mapped to %s", resource.ID, key)
+			break
+		}
+	}
+	return nil
+}
+
+//findResourceChanges gives the changes needed for the resources to match the upstream items
+func findResourceChanges(resources []*model.Resource, providerData []ProviderResource) []*model.ResourceChange {
+	resourcesMap := make(map[string]*model.Resource, len(resources))
+	for _, resource := range resources {
+		if resource.SourceKey != nil {
+			resourcesMap[*resource.SourceKey] = resource
+		}
+	}
+
+	var result []*model.ResourceChange
+	upstream := make(map[string]bool, len(providerData))
+	for _, item := range providerData {
+		key := resourceSourceKey(item.Link)
+		if upstream[key] {
+			//the same item is listed more than once
+			continue
+		}
+		upstream[key] = true
+
+		resource, found := resourcesMap[key]
+		if !found {
+			result = append(result, &model.ResourceChange{SourceKey: key, Operation: resourceChangeAdd, Title: item.Title, Link: item.Link})
+			continue
+		}
+		if resource.Stale || resource.Title != item.Title || resource.Link != item.Link {
+			resourceID := resource.ID
+			result = append(result, &model.ResourceChange{SourceKey: key, Operation: resourceChangeModify, ResourceID: &resourceID,
+				Title: item.Title, Link: item.Link})
+		}
+	}
+	for _, resource := range resources {
+		if resource.SourceKey == nil || resource.Stale || upstream[*resource.SourceKey] {
+			continue
+		}
+		resourceID := resource.ID
+		result = append(result, &model.ResourceChange{SourceKey: *resource.SourceKey, Operation: resourceChangeRemove, ResourceID: &resourceID,
+			Title: resource.Title, Link: resource.Link})
+	}
+	return result
+}
+
+//queueResourceChanges replaces the pending changes with the found ones. The changes which have been rejected are not queued again.
+func (app *Application) queueResourceChanges(ctx context.Context, changes []*model.ResourceChange,
+	pending []*model.ResourceChange, rejected []*model.ResourceChange) ([]*model.ResourceChange, error) {
+	pendingMap := make(map[string]*model.ResourceChange, len(pending))
+	for _, change := range pending {
+		pendingMap[change.SourceKey] = change
+	}
+	//the latest rejected change per item
+	rejectedMap := make(map[string]*model.ResourceChange, len(rejected))
+	for _, change := range rejected {
+		if _, found := rejectedMap[change.SourceKey]; !found {
+			rejectedMap[change.SourceKey] = change
+		}
+	}
+
+	now := time.Now().UTC()
+	result := []*model.ResourceChange{}
+	queued := make(map[string]bool, len(changes))
+	for _, change := range changes {
+		if rejectedChange, found := rejectedMap[change.SourceKey]; found && isSameResourceChange(rejectedChange, change) {
+			continue
+		}
+		queued[change.SourceKey] = true
+
+		current, found := pendingMap[change.SourceKey]
+		if found && isSameResourceChange(current, change) {
+			result = append(result, current)
+			continue
+		}
+		if found {
+			change.ID = current.ID
+			change.DateCreated = current.DateCreated
+			change.DateUpdated = &now
+		} else {
+			id, err := uuid.NewUUID()
+			if err != nil {
+				return nil, err
+			}
+			change.ID = id.String()
+			change.DateCreated = now
+		}
+		change.Status = resourceChangePending
+		err := app.storage.SaveResourceChange(ctx, change)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, change)
+	}
+
+	//remove the pending changes which are not needed anymore
+	for _, change := range pending {
+		if queued[change.SourceKey] {
+			continue
+		}
+		err := app.storage.DeleteResourceChange(ctx, change.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func isSameResourceChange(a *model.ResourceChange, b *model.ResourceChange) bool {
+	if a.Operation != b.Operation || a.Title != b.Title || a.Link != b.Link {
+		return false
+	}
+	if a.ResourceID == nil || b.ResourceID == nil {
+		return a.ResourceID == nil && b.ResourceID == nil
+	}
+	return *a.ResourceID == *b.ResourceID
+}
+
+//resourceSourceKey gives the stable key of an upstream item - its link without the fragment, the trailing slash and the case of the host
+func resourceSourceKey(link string) string {
+	link = strings.TrimSpace(link)
+	u, err := url.Parse(link)
+	if err != nil || len(u.Host) == 0 {
+		return strings.TrimSuffix(link, "/")
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	return u.String()
+}
+
+func (app *Application) getResourceChanges(ctx context.Context, status *string) ([]*model.ResourceChange, error) {
+	return app.storage.FindResourceChanges(ctx, status)
+}
+
+//approveResourceChange applies the change - add creates the resource at the end of the list, modify updates the title and the link,
+//remove marks the resource as stale. The admins fields are kept.
+func (app *Application) approveResourceChange(ctx context.Context, current model.User, group string, audit *string, ID string) (*model.ResourceChange, error) {
+	change, err := app.findPendingResourceChange(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var created *model.Resource
+	switch change.Operation {
+	case resourceChangeAdd:
+		resources, err := app.storage.ReadAllResources(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sourceKey := change.SourceKey
+		created, err = app.storage.CreateResource(ctx, change.Title, change.Link, len(resources), &sourceKey)
+		if err != nil {
+			return nil, err
+		}
+		resourceID := created.ID
+		change.ResourceID = &resourceID
+	case resourceChangeModify, resourceChangeRemove:
+		resource, err := app.storage.FindResource(ctx, *change.ResourceID)
+		if err != nil {
+			return nil, err
+		}
+		if resource == nil {
+			return nil, errors.New("there is no a resource for the provided id")
+		}
+		if change.Operation == resourceChangeModify {
+			resource.Title = change.Title
+			resource.Link = change.Link
+			resource.Stale = false
+		} else {
+			resource.Stale = true
+		}
+		err = app.storage.SaveResource(ctx, resource)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	change, err = app.reviewResourceChange(ctx, current, group, audit, change, resourceChangeApproved)
+	if err != nil {
+		return nil, err
+	}
+
+	//notify the recipients for the added resource
+	if created != nil {
+		go app.sender.SendForResources([]*model.Resource{created})
+	}
+	return change, nil
+}
+
+//rejectResourceChange keeps the resource as it is. The same change is not queued again.
+func (app *Application) rejectResourceChange(ctx context.Context, current model.User, group string, audit *string, ID string) (*model.ResourceChange, error) {
+	change, err := app.findPendingResourceChange(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	return app.reviewResourceChange(ctx, current, group, audit, change, resourceChangeRejected)
+}
+
+func (app *Application) findPendingResourceChange(ctx context.Context, ID string) (*model.ResourceChange, error) {
+	change, err := app.storage.FindResourceChange(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	if change == nil {
+		return nil, errors.New("there is no a resource change for the provided id")
+	}
+	if change.Status != resourceChangePending {
+		return nil, errors.New("the resource change has already been " + change.Status)
+	}
+	return change, nil
+}
+
+func (app *Application) reviewResourceChange(ctx context.Context, current model.User, group string, audit *string,
+	change *model.ResourceChange, status string) (*model.ResourceChange, error) {
+	userIdentifier, userInfo := current.GetLogData()
+
+	now := time.Now().UTC()
+	change.Status = status
+	change.ReviewedBy = &userIdentifier
+	change.DateReviewed = &now
+	err := app.storage.SaveResourceChange(ctx, change)
+	if err != nil {
+		return nil, err
+	}
+
+	//audit
+	lData := []AuditDataEntry{{Key: "operation", Value: change.Operation}, {Key: "sourceKey", Value: change.SourceKey},
+		{Key: "title", Value: change.Title}, {Key: "link", Value: change.Link}, {Key: "status", Value: status}}
+	defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "resource-change", change.ID, lData, audit)
+
+	return change, nil
+}
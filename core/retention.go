@@ -0,0 +1,182 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"health/core/model"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+//the entity types which support retention
+const (
+	retentionEntityEHistory         = "ehistory"
+	retentionEntityCTest            = "ctest"             //only the processed ones
+	retentionEntityEManualTestImage = "emanualtest-image" //the manual test is kept, only the image is removed
+	retentionEntityTraceExposure    = "trace-exposure"    //counted from the expirestamp
+	retentionEntityUINOverride      = "uin-override"      //counted from the expiration
+	retentionEntityWaitTimeReport   = "wait-time-report"
+	retentionEntityAppointment      = "appointment" //counted from the start, together with the slots
+	retentionEntityAudit            = "audit"       //without the retention runs and the erasure receipts
+)
+
+var retentionEntities = []string{retentionEntityEHistory, retentionEntityCTest, retentionEntityEManualTestImage,
+	retentionEntityTraceExposure, retentionEntityUINOverride, retentionEntityWaitTimeReport, retentionEntityAppointment, retentionEntityAudit}
+
+//the audit entities which prove the retention runs and the erasures, the audit policy never purges them
+const (
+	auditEntityRetentionRun   = "retention-run"
+	auditEntityErasureReceipt = "erasure-receipt"
+)
+
+var retentionProtectedAuditEntities = []string{auditEntityRetentionRun, auditEntityErasureReceipt}
+
+const (
+	retentionJobDelay  = 10 * time.Minute //do not load the database on start
+	retentionJobPeriod = 24 * time.Hour
+)
+
+func (app *Application) getRetentionPolicies(ctx context.Context) ([]*model.RetentionPolicy, error) {
+	policies, err := app.storage.ReadRetentionPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	//give all supported entities, the ones without a policy are kept forever
+	var result []*model.RetentionPolicy
+	for _, entity := range retentionEntities {
+		policy := findRetentionPolicy(entity, policies)
+		if policy == nil {
+			policy = &model.RetentionPolicy{Entity: entity, Days: 0, Enabled: false}
+		}
+		result = append(result, policy)
+	}
+	return result, nil
+}
+
+func (app *Application) updateRetentionPolicy(ctx context.Context, current model.User, group string, audit *string, entity string, days int, enabled bool) (*model.RetentionPolicy, error) {
+	//validate
+	if !containsID(entity, retentionEntities) {
+		return nil, errors.New("not supported retention entity " + entity)
+	}
+	if days < 1 {
+		return nil, errors.New("the retention period must be at least 1 day")
+	}
+
+	now := time.Now()
+	policy := &model.RetentionPolicy{Entity: entity, Days: days, Enabled: enabled, DateUpdated: &now}
+	err := app.storage.SaveRetentionPolicy(ctx, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	//audit
+	userIdentifier, userInfo := current.GetLogData()
+	lData := []AuditDataEntry{{Key: "entity", Value: entity}, {Key: "days", Value: fmt.Sprint(days)}, {Key: "enabled", Value: fmt.Sprint(enabled)}}
+	defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "retention-policy", entity, lData, audit)
+
+	return policy, nil
+}
+
+func (app *Application) setupRetentionTimer() {
+	log.Printf("Application -> setupRetentionTimer -> start after - %s", retentionJobDelay)
+	timer := time.NewTimer(retentionJobDelay)
+	<-timer.C
+
+	//apply it for first time
+	app.applyRetentionPolicies()
+
+	//apply it every day
+	ticker := time.NewTicker(retentionJobPeriod)
+	for range ticker.C {
+		app.applyRetentionPolicies()
+	}
+}
+
+//applyRetentionPolicies purges the records which are older than their policy allows.
+//Every run is audited with the cut off date and the purged count for every entity, so the deletion schedule can be proven.
+func (app *Application) applyRetentionPolicies() {
+	log.Println("Application -> applyRetentionPolicies")
+	ctx := context.Background()
+
+	policies, err := app.storage.ReadRetentionPolicies(ctx)
+	if err != nil {
+		log.Printf("error reading the retention policies - %s", err)
+		return
+	}
+
+	runID, _ := uuid.NewUUID()
+	now := time.Now()
+	var lData []AuditDataEntry
+	for _, policy := range policies {
+		if !policy.Enabled || policy.Days < 1 {
+			continue
+		}
+
+		cutOff := now.AddDate(0, 0, -policy.Days)
+		count, err := app.purgeRetentionEntity(ctx, policy.Entity, cutOff)
+		if err != nil {
+			log.Printf("error applying the %s retention policy - %s", policy.Entity, err)
+			lData = append(lData, AuditDataEntry{Key: policy.Entity, Value: fmt.Sprintf("error before %s - %s", cutOff.Format(time.RFC3339), err)})
+			continue
+		}
+		log.Printf("... -> %s - purged %d items before %s", policy.Entity, count, cutOff.Format(time.RFC3339))
+		lData = append(lData, AuditDataEntry{Key: policy.Entity, Value: fmt.Sprintf("purged %d before %s", count, cutOff.Format(time.RFC3339))})
+	}
+
+	//audit the run even if there is nothing to purge
+	app.audit.LogCreateEvent("system", "retention job", "", auditEntityRetentionRun, runID.String(), lData, nil)
+}
+
+func (app *Application) purgeRetentionEntity(ctx context.Context, entity string, cutOff time.Time) (int64, error) {
+	switch entity {
+	case retentionEntityEHistory:
+		return app.storage.DeleteEHistoriesOlderThan(ctx, cutOff)
+	case retentionEntityCTest:
+		return app.storage.DeleteProcessedCTestsOlderThan(ctx, cutOff)
+	case retentionEntityEManualTestImage:
+		return app.storage.ClearManualTestsImagesOlderThan(ctx, cutOff)
+	case retentionEntityTraceExposure:
+		//the expirestamp is in milliseconds
+		return app.storage.DeleteTraceExposuresExpiredBefore(ctx, cutOff.UnixNano()/1000000)
+	case retentionEntityUINOverride:
+		return app.storage.DeleteUINOverridesExpiredBefore(ctx, cutOff)
+	case retentionEntityWaitTimeReport:
+		return app.storage.DeleteWaitTimeReportsOlderThan(ctx, cutOff)
+	case retentionEntityAppointment:
+		return app.storage.DeleteAppointmentsOlderThan(ctx, cutOff)
+	case retentionEntityAudit:
+		return app.audit.DeleteOlderThan(ctx, cutOff, retentionProtectedAuditEntities)
+	default:
+		return 0, errors.New("not supported retention entity " + entity)
+	}
+}
+
+func findRetentionPolicy(entity string, list []*model.RetentionPolicy) *model.RetentionPolicy {
+	for _, item := range list {
+		if item.Entity == entity {
+			return item
+		}
+	}
+	return nil
+}
@@ -0,0 +1,315 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"health/core/model"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	configTypeCRules   = "crules"
+	configTypeSymptoms = "symptoms"
+
+	revisionStatusDraft     = "draft"
+	revisionStatusPublished = "published"
+	revisionStatusArchived  = "archived"
+	revisionStatusDiscarded = "discarded"
+)
+
+//getConfigRevisions gives the revisions for the crules of a county or for the symptoms, the latest are first
+func (app *Application) getConfigRevisions(ctx context.Context, configType string, countyID string, appVersion string) ([]*model.ConfigRevision, error) {
+	err := checkConfigType(configType)
+	if err != nil {
+		return nil, err
+	}
+	v, err := app.checkContentVersion(appVersion)
+	if err != nil {
+		return nil, err
+	}
+	return app.storage.FindConfigRevisions(ctx, configType, *v, countyID)
+}
+
+//saveConfigDraft creates a draft revision or updates the current draft. The apps do not receive it until it is published.
+func (app *Application) saveConfigDraft(ctx context.Context, current model.User, group string, audit *string,
+	configType string, countyID string, appVersion string, data string) (*model.ConfigRevision, error) {
+	err := checkConfigType(configType)
+	if err != nil {
+		return nil, err
+	}
+	v, err := app.checkContentVersion(appVersion)
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := app.storage.FindConfigRevisions(ctx, configType, *v, countyID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = app.validateConfigData(ctx, configType, countyID, *v, data, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	userIdentifier, userInfo := current.GetLogData()
+	now := time.Now().UTC()
+
+	draft := findConfigRevisionByStatus(revisions, revisionStatusDraft)
+	create := draft == nil
+	if create {
+		id, err := uuid.NewUUID()
+		if err != nil {
+			return nil, err
+		}
+		draft = &model.ConfigRevision{ID: id.String(), Type: configType, AppVersion: *v, CountyID: countyID,
+			Number: nextConfigRevisionNumber(revisions), Data: data, Status: revisionStatusDraft,
+			CreatedBy: userIdentifier, DateCreated: now}
+	} else {
+		draft.Data = data
+		draft.UpdatedBy = &userIdentifier
+		draft.DateUpdated = &now
+	}
+	err = app.storage.SaveConfigRevision(ctx, draft)
+	if err != nil {
+		return nil, err
+	}
+
+	//audit
+	lData := configRevisionLogData(draft)
+	if create {
+		defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, configType+"-revision", draft.ID, lData, audit)
+	} else {
+		defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, configType+"-revision", draft.ID, lData, audit)
+	}
+
+	return draft, nil
+}
+
+//discardConfigDraft discards the current draft, it stays in the history
+func (app *Application) discardConfigDraft(ctx context.Context, current model.User, group string, audit *string,
+	configType string, countyID string, appVersion string) (*model.ConfigRevision, error) {
+	draft, err := app.findConfigDraft(ctx, configType, countyID, appVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	userIdentifier, userInfo := current.GetLogData()
+	now := time.Now().UTC()
+	draft.Status = revisionStatusDiscarded
+	draft.UpdatedBy = &userIdentifier
+	draft.DateUpdated = &now
+	err = app.storage.SaveConfigRevision(ctx, draft)
+	if err != nil {
+		return nil, err
+	}
+
+	//audit
+	lData := configRevisionLogData(draft)
+	defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, configType+"-revision", draft.ID, lData, audit)
+
+	return draft, nil
+}
+
+//publishConfigDraft makes the current draft the data the apps receive
+func (app *Application) publishConfigDraft(ctx context.Context, current model.User, group string, audit *string,
+	configType string, countyID string, appVersion string) (*model.ConfigRevision, error) {
+	draft, err := app.findConfigDraft(ctx, configType, countyID, appVersion)
+	if err != nil {
+		return nil, err
+	}
+	err = app.publishConfigRevision(ctx, current, group, audit, draft)
+	if err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+//rollbackConfig publishes a new revision with the data of an earlier published revision
+func (app *Application) rollbackConfig(ctx context.Context, current model.User, group string, audit *string,
+	configType string, countyID string, appVersion string, number int) (*model.ConfigRevision, error) {
+	err := checkConfigType(configType)
+	if err != nil {
+		return nil, err
+	}
+	v, err := app.checkContentVersion(appVersion)
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := app.storage.FindConfigRevisions(ctx, configType, *v, countyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var target *model.ConfigRevision
+	for _, revision := range revisions {
+		if revision.Number == number {
+			target = revision
+			break
+		}
+	}
+	if target == nil {
+		return nil, errors.New("there is no a revision for the provided number")
+	}
+	switch target.Status {
+	case revisionStatusPublished:
+		return nil, errors.New("the revision is the published one")
+	case revisionStatusDraft, revisionStatusDiscarded:
+		return nil, fmt.Errorf("cannot roll back to a %s revision", target.Status)
+	}
+
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+	userIdentifier, _ := current.GetLogData()
+	revision := &model.ConfigRevision{ID: id.String(), Type: configType, AppVersion: *v, CountyID: countyID,
+		Number: nextConfigRevisionNumber(revisions), Data: target.Data, RolledBackFrom: &target.Number,
+		CreatedBy: userIdentifier, DateCreated: time.Now().UTC()}
+	err = app.publishConfigRevision(ctx, current, group, audit, revision)
+	if err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+//publishNewConfigRevision creates a revision and publishes it right away
+func (app *Application) publishNewConfigRevision(ctx context.Context, current model.User, group string, audit *string,
+	configType string, countyID string, appVersion string, data string) (*model.ConfigRevision, error) {
+	revisions, err := app.storage.FindConfigRevisions(ctx, configType, appVersion, countyID)
+	if err != nil {
+		return nil, err
+	}
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+	userIdentifier, _ := current.GetLogData()
+	revision := &model.ConfigRevision{ID: id.String(), Type: configType, AppVersion: appVersion, CountyID: countyID,
+		Number: nextConfigRevisionNumber(revisions), Data: data, CreatedBy: userIdentifier, DateCreated: time.Now().UTC()}
+	err = app.publishConfigRevision(ctx, current, group, audit, revision)
+	if err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+//publishConfigRevision publishes the revision - the previous published one is archived and the apps receive the revision data.
+//The data is validated again as the schema or the referred entities could be changed after the revision was created.
+func (app *Application) publishConfigRevision(ctx context.Context, current model.User, group string, audit *string, revision *model.ConfigRevision) error {
+	err := app.validateConfigData(ctx, revision.Type, revision.CountyID, revision.AppVersion, revision.Data, nil)
+	if err != nil {
+		return err
+	}
+
+	setConfigRevisionPublished(current, revision)
+	create, err := app.storage.PublishConfigRevision(ctx, revision)
+	if err != nil {
+		return err
+	}
+
+	app.logConfigRevisionPublished(current, group, audit, revision, *create)
+	return nil
+}
+
+func setConfigRevisionPublished(current model.User, revision *model.ConfigRevision) {
+	userIdentifier, _ := current.GetLogData()
+	now := time.Now().UTC()
+	revision.Status = revisionStatusPublished
+	revision.PublishedBy = &userIdentifier
+	revision.DatePublished = &now
+}
+
+//logConfigRevisionPublished audits the published revision, the entity id links the entry to the revision
+func (app *Application) logConfigRevisionPublished(current model.User, group string, audit *string, revision *model.ConfigRevision, create bool) {
+	userIdentifier, userInfo := current.GetLogData()
+	lData := configRevisionLogData(revision)
+	if revision.Type == configTypeCRules {
+		lData = append(lData, AuditDataEntry{Key: "data", Value: revision.Data})
+	} else {
+		lData = append(lData, AuditDataEntry{Key: "items", Value: revision.Data})
+	}
+	if create {
+		app.audit.LogCreateEvent(userIdentifier, userInfo, group, revision.Type, revision.ID, lData, audit)
+	} else {
+		app.audit.LogUpdateEvent(userIdentifier, userInfo, group, revision.Type, revision.ID, lData, audit)
+	}
+}
+
+func (app *Application) findConfigDraft(ctx context.Context, configType string, countyID string, appVersion string) (*model.ConfigRevision, error) {
+	err := checkConfigType(configType)
+	if err != nil {
+		return nil, err
+	}
+	v, err := app.checkContentVersion(appVersion)
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := app.storage.FindConfigRevisions(ctx, configType, *v, countyID)
+	if err != nil {
+		return nil, err
+	}
+	draft := findConfigRevisionByStatus(revisions, revisionStatusDraft)
+	if draft == nil {
+		return nil, errors.New("there is no a draft for the provided app version")
+	}
+	return draft, nil
+}
+
+func checkConfigType(configType string) error {
+	if configType != configTypeCRules && configType != configTypeSymptoms {
+		return errors.New("the type must be crules or symptoms")
+	}
+	return nil
+}
+
+func findConfigRevisionByStatus(revisions []*model.ConfigRevision, status string) *model.ConfigRevision {
+	for _, revision := range revisions {
+		if revision.Status == status {
+			return revision
+		}
+	}
+	return nil
+}
+
+func nextConfigRevisionNumber(revisions []*model.ConfigRevision) int {
+	number := 0
+	for _, revision := range revisions {
+		if revision.Number > number {
+			number = revision.Number
+		}
+	}
+	return number + 1
+}
+
+func configRevisionLogData(revision *model.ConfigRevision) []AuditDataEntry {
+	lData := []AuditDataEntry{{Key: "appVersion", Value: revision.AppVersion}, {Key: "revision", Value: strconv.Itoa(revision.Number)},
+		{Key: "status", Value: revision.Status}}
+	if len(revision.CountyID) > 0 {
+		lData = append([]AuditDataEntry{{Key: "countyID", Value: revision.CountyID}}, lData...)
+	}
+	if revision.RolledBackFrom != nil {
+		lData = append(lData, AuditDataEntry{Key: "rolledBackFrom", Value: strconv.Itoa(*revision.RolledBackFrom)})
+	}
+	return lData
+}
@@ -0,0 +1,332 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"health/core/model"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	evaluationSourceTestResult = "test-result"
+	evaluationSourceSymptoms   = "symptoms"
+
+	symptomGroup1 = "gr1"
+	symptomGroup2 = "gr2"
+)
+
+//statusRules is the county configuration a status is evaluated with
+type statusRules struct {
+	county        *model.County
+	rules         []*model.Rule //only the county rules
+	testTypes     []*model.TestType
+	symptomRule   *model.SymptomRule
+	symptomGroups []*model.SymptomGroup
+	accessRule    *model.AccessRule
+}
+
+//statusCandidate is a county status which a test result or a symptom report leads to
+type statusCandidate struct {
+	countyStatusID string
+	priority       int
+	date           time.Time
+	nextStep       *string
+	nextStepDate   *time.Time
+	expiresAt      *time.Time
+	source         string
+	sourceIndex    int
+}
+
+//evaluateCountyStatus evaluates the county status for the provided test results and symptom reports
+func (app *Application) evaluateCountyStatus(ctx context.Context, countyID string, input model.StatusEvaluationInput) (*model.StatusEvaluation, error) {
+	rules, err := app.loadStatusRules(ctx, countyID)
+	if err != nil {
+		return nil, err
+	}
+	if input.Date.IsZero() {
+		input.Date = time.Now().UTC()
+	}
+	return evaluateStatus(*rules, input)
+}
+
+//loadStatusRules loads the current county configuration
+func (app *Application) loadStatusRules(ctx context.Context, countyID string) (*statusRules, error) {
+	county, err := app.getCachedCounty(ctx, countyID)
+	if err != nil {
+		return nil, err
+	}
+	if county == nil {
+		return nil, errors.New("there is no a county for the provided id")
+	}
+	allRules, err := app.getCachedRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var rules []*model.Rule
+	for _, rule := range allRules {
+		if rule.County.ID == countyID {
+			rules = append(rules, rule)
+		}
+	}
+	testTypes, err := app.getCachedTestTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	symptomRule, err := app.storage.FindSymptomRuleByCountyID(ctx, countyID)
+	if err != nil {
+		return nil, err
+	}
+	symptomGroups, err := app.storage.ReadAllSymptomGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	accessRules, err := app.getCachedAccessRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var accessRule *model.AccessRule
+	for _, item := range accessRules {
+		if item.County.ID == countyID {
+			accessRule = item
+			break
+		}
+	}
+	return &statusRules{county: county, rules: rules, testTypes: testTypes, symptomRule: symptomRule,
+		symptomGroups: symptomGroups, accessRule: accessRule}, nil
+}
+
+//evaluateStatus is the reference status evaluation. It does not load anything so it gives the same result for the same rules and input.
+//Every valid test result and the latest symptom report give a candidate status. The candidate with the highest priority wins,
+//the latest one wins between candidates with the same priority. The rule priority overrides the test type priority, the symptoms
+//and the test types without a priority have priority 0. The expired test results and the items after the evaluation date are ignored.
+func evaluateStatus(rules statusRules, input model.StatusEvaluationInput) (*model.StatusEvaluation, error) {
+	result := model.StatusEvaluation{CountyID: rules.county.ID, Explanation: []string{}}
+	explain := func(format string, a ...interface{}) {
+		result.Explanation = append(result.Explanation, fmt.Sprintf(format, a...))
+	}
+
+	var candidates []statusCandidate
+
+	//test results
+	for i, testResult := range input.TestResults {
+		testType := findEvaluationTestType(testResult.TestType, rules.testTypes)
+		if testType == nil {
+			return nil, fmt.Errorf("test result %d - there is no a test type %s", i, testResult.TestType)
+		}
+		testTypeResult := findEvaluationTestTypeResult(testResult.Result, testType.Results)
+		if testTypeResult == nil {
+			return nil, fmt.Errorf("test result %d - there is no a result %s for test type %s", i, testResult.Result, testType.Name)
+		}
+		if testResult.Date.After(input.Date) {
+			explain("test result %d is ignored - it is after the evaluation date", i)
+			continue
+		}
+		var expiresAt *time.Time
+		if testTypeResult.ResultExpiresOffset != nil {
+			expires := testResult.Date.Add(time.Duration(*testTypeResult.ResultExpiresOffset) * time.Hour)
+			if !expires.After(input.Date) {
+				explain("test result %d is ignored - it expired at %s", i, expires.Format(time.RFC3339))
+				continue
+			}
+			expiresAt = &expires
+		}
+
+		rule := findEvaluationRule(testType.ID, rules.rules)
+		if rule == nil {
+			explain("test result %d is ignored - there is no a county rule for test type %s", i, testType.Name)
+			continue
+		}
+		countyStatusID := ""
+		for _, rs := range rule.ResultsStates {
+			if rs.TestTypeResultID == testTypeResult.ID {
+				countyStatusID = rs.CountyStatusID
+				break
+			}
+		}
+		if len(countyStatusID) == 0 {
+			explain("test result %d is ignored - the county rule for test type %s does not have a status for result %s", i, testType.Name, testTypeResult.Name)
+			continue
+		}
+
+		priority := 0
+		if testType.Priority != nil {
+			priority = *testType.Priority
+		}
+		if rule.Priority != nil {
+			priority = *rule.Priority
+		}
+		candidate := statusCandidate{countyStatusID: countyStatusID, priority: priority, date: testResult.Date, expiresAt: expiresAt,
+			source: evaluationSourceTestResult, sourceIndex: i}
+		if len(testTypeResult.NextStep) > 0 {
+			nextStep := testTypeResult.NextStep
+			candidate.nextStep = &nextStep
+			if testTypeResult.NextStepOffset != nil {
+				nextStepDate := testResult.Date.Add(time.Duration(*testTypeResult.NextStepOffset) * time.Hour)
+				candidate.nextStepDate = &nextStepDate
+			}
+		}
+		explain("test result %d - %s %s gives status %s with priority %d", i, testType.Name, testTypeResult.Name, countyStatusName(rules.county, countyStatusID), priority)
+		candidates = append(candidates, candidate)
+	}
+
+	//symptoms - only the latest report matters
+	symptomsCandidate, err := evaluateSymptoms(rules, input, explain)
+	if err != nil {
+		return nil, err
+	}
+	if symptomsCandidate != nil {
+		candidates = append(candidates, *symptomsCandidate)
+	}
+
+	if len(candidates) == 0 {
+		explain("there is no a valid test result or symptom report")
+		return &result, nil
+	}
+
+	//the highest priority wins, then the latest
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].priority != candidates[j].priority {
+			return candidates[i].priority > candidates[j].priority
+		}
+		return candidates[i].date.After(candidates[j].date)
+	})
+	winner := candidates[0]
+
+	statusName := countyStatusName(rules.county, winner.countyStatusID)
+	result.CountyStatusID = &winner.countyStatusID
+	result.CountyStatusName = &statusName
+	result.NextStep = winner.nextStep
+	result.NextStepDate = winner.nextStepDate
+	result.ExpiresAt = winner.expiresAt
+	result.Source = &winner.source
+	result.SourceIndex = &winner.sourceIndex
+	explain("the status is %s from %s %d", statusName, winner.source, winner.sourceIndex)
+
+	if rules.accessRule != nil {
+		for _, item := range rules.accessRule.Rules {
+			if item.CountyStatusID == winner.countyStatusID {
+				access := item.Value
+				result.Access = &access
+				break
+			}
+		}
+	}
+	return &result, nil
+}
+
+//evaluateSymptoms gives the status for the latest symptom report. The groups are present when the reported symptoms
+//in them are at least the counts of the symptom rule.
+func evaluateSymptoms(rules statusRules, input model.StatusEvaluationInput, explain func(format string, a ...interface{})) (*statusCandidate, error) {
+	latest := -1
+	for i, report := range input.SymptomReports {
+		if report.Date.After(input.Date) {
+			explain("symptom report %d is ignored - it is after the evaluation date", i)
+			continue
+		}
+		if latest == -1 || report.Date.After(input.SymptomReports[latest].Date) {
+			latest = i
+		}
+	}
+	if latest == -1 {
+		return nil, nil
+	}
+	if rules.symptomRule == nil {
+		explain("symptom report %d is ignored - there is no a county symptom rule", latest)
+		return nil, nil
+	}
+
+	report := input.SymptomReports[latest]
+	groupsCounts := make(map[string]int)
+	for _, symptom := range report.Symptoms {
+		group := findEvaluationSymptomGroup(symptom, rules.symptomGroups)
+		if group == nil {
+			return nil, fmt.Errorf("symptom report %d - there is no a symptom %s", latest, symptom)
+		}
+		groupsCounts[strings.ToLower(group.Name)]++
+	}
+	gr1 := groupsCounts[symptomGroup1] > 0 && groupsCounts[symptomGroup1] >= rules.symptomRule.Gr1Count
+	gr2 := groupsCounts[symptomGroup2] > 0 && groupsCounts[symptomGroup2] >= rules.symptomRule.Gr2Count
+
+	for _, item := range rules.symptomRule.Items {
+		if item.Gr1 != gr1 || item.Gr2 != gr2 {
+			continue
+		}
+		candidate := statusCandidate{countyStatusID: item.CountyStatus.ID, date: report.Date,
+			source: evaluationSourceSymptoms, sourceIndex: latest}
+		if len(item.NextStep) > 0 {
+			nextStep := item.NextStep
+			candidate.nextStep = &nextStep
+		}
+		explain("symptom report %d - gr1:%t gr2:%t gives status %s with priority 0", latest, gr1, gr2, countyStatusName(rules.county, item.CountyStatus.ID))
+		return &candidate, nil
+	}
+	explain("symptom report %d is ignored - the county symptom rule does not have a status for gr1:%t gr2:%t", latest, gr1, gr2)
+	return nil, nil
+}
+
+func findEvaluationTestType(value string, testTypes []*model.TestType) *model.TestType {
+	for _, testType := range testTypes {
+		if testType.ID == value || strings.EqualFold(testType.Name, value) {
+			return testType
+		}
+	}
+	return nil
+}
+
+func findEvaluationTestTypeResult(value string, results []model.TestTypeResult) *model.TestTypeResult {
+	for i, result := range results {
+		if result.ID == value || strings.EqualFold(result.Name, value) {
+			return &results[i]
+		}
+	}
+	return nil
+}
+
+func findEvaluationRule(testTypeID string, rules []*model.Rule) *model.Rule {
+	for _, rule := range rules {
+		if rule.TestType.ID == testTypeID {
+			return rule
+		}
+	}
+	return nil
+}
+
+func findEvaluationSymptomGroup(value string, groups []*model.SymptomGroup) *model.SymptomGroup {
+	for _, group := range groups {
+		for _, symptom := range group.Symptoms {
+			if symptom.ID == value || strings.EqualFold(symptom.Name, value) {
+				return group
+			}
+		}
+	}
+	return nil
+}
+
+func countyStatusName(county *model.County, ID string) string {
+	for _, cs := range county.CountyStatuses {
+		if cs.ID == ID {
+			return cs.Name
+		}
+	}
+	return ID
+}
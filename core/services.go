@@ -18,6 +18,7 @@
 package core
 
 import (
+	"context"
 	"errors"
 	"health/core/model"
 	"log"
@@ -28,88 +29,99 @@ func (app *Application) getVersion() string {
 	return app.version
 }
 
-func (app *Application) clearUserData(current model.User) error {
-	err := app.storage.ClearUserData(current.ID)
-	if err != nil {
-		return err
-	}
-
-	defer app.notifyListeners("onClearUserData", current)
-
-	return nil
+func (app *Application) clearUserData(ctx context.Context, current model.User) error {
+	_, err := app.eraseUserData(ctx, current)
+	return err
 }
 
-func (app *Application) getUserByShibbolethUIN(shibbolethUIN string) (*model.User, error) {
-	user, err := app.storage.FindUserByExternalID(shibbolethUIN)
+func (app *Application) getUserByShibbolethUIN(ctx context.Context, shibbolethUIN string) (*model.User, error) {
+	user, err := app.storage.FindUserByExternalID(ctx, shibbolethUIN)
 	if err != nil {
 		return nil, err
 	}
 	return user, nil
 }
 
-func (app *Application) getUsersForRePost() ([]*model.User, error) {
-	users, err := app.storage.FindUsersByRePost(true)
+func (app *Application) getUsersForRePost(ctx context.Context) ([]*model.User, error) {
+	users, err := app.storage.FindUsersByRePost(ctx, true)
 	if err != nil {
 		return nil, err
 	}
 	return users, nil
 }
 
-func (app *Application) getUINsByOrderNumbers(orderNumbers []string) (map[string]*string, error) {
-	data, err := app.storage.FindExternalUserIDsByTestsOrderNumbers(orderNumbers)
+func (app *Application) getUINsByOrderNumbers(ctx context.Context, orderNumbers []string) (map[string]*string, error) {
+	data, err := app.storage.FindExternalUserIDsByTestsOrderNumbers(ctx, orderNumbers)
 	if err != nil {
 		return nil, err
 	}
 	return data, nil
 }
 
-func (app *Application) getCTestsByExternalUserIDs(externalUserIDs []string) (map[string][]*model.CTest, error) {
-	data, err := app.storage.FindCTestsByExternalUserIDs(externalUserIDs)
+func (app *Application) getCTestsByExternalUserIDs(ctx context.Context, externalUserIDs []string) (map[string][]*model.CTest, error) {
+	data, err := app.storage.FindCTestsByExternalUserIDs(ctx, externalUserIDs)
 	if err != nil {
 		return nil, err
 	}
 	return data, nil
 }
 
-func (app *Application) getResources() ([]*model.Resource, error) {
-	resources, err := app.storage.ReadAllResources()
+func (app *Application) getResources(ctx context.Context) ([]*model.Resource, error) {
+	resources, err := app.storage.ReadAllResources(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return resources, nil
+
+	//the stale resources are kept for the admins only
+	result := []*model.Resource{}
+	for _, resource := range resources {
+		if !resource.Stale {
+			result = append(result, resource)
+		}
+	}
+	return result, nil
 }
 
-func (app *Application) getFAQ() (*model.FAQ, error) {
-	faq, err := app.storage.ReadFAQ()
+func (app *Application) getFAQ(ctx context.Context) (*model.FAQ, error) {
+	faq, err := app.storage.ReadFAQ(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return faq, nil
 }
 
-func (app *Application) getNews(limit int64) ([]*model.News, error) {
+func (app *Application) getNews(ctx context.Context, limit int64, category *string) ([]*model.News, error) {
 	if limit < 0 {
 		return nil, errors.New("cannot pass limit < 0")
 	}
 
-	news, err := app.storage.ReadNews(limit)
+	if category != nil {
+		//the categories are stored normalized
+		normalized := normalizeNewsCategories([]string{*category})
+		if len(normalized) == 0 {
+			return nil, errors.New("category cannot be empty")
+		}
+		category = &normalized[0]
+	}
+
+	news, err := app.storage.ReadPublishedNews(ctx, time.Now().UTC(), limit, category)
 	if err != nil {
 		return nil, err
 	}
 	return news, nil
 }
 
-func (app *Application) getCTests(current model.User, processed bool) ([]*model.CTest, []*model.Provider, error) {
+func (app *Application) getCTests(ctx context.Context, current model.User, processed bool) ([]*model.CTest, []*model.Provider, error) {
 	//We get the data with two requests to the database - NoSQL approach in some cases!
 
 	//1. first get the ctest
-	ctests, err := app.storage.FindCTests(current.ID, processed)
+	ctests, err := app.storage.FindCTests(ctx, current.ID, processed)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	//2. we need to get the providers
-	providers, err := app.storage.ReadAllProviders()
+	providers, err := app.getCachedProviders(ctx)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -117,9 +129,9 @@ func (app *Application) getCTests(current model.User, processed bool) ([]*model.
 	return ctests, providers, nil
 }
 
-func (app *Application) createExternalCTest(providerID string, uin string, encryptedKey string, encryptedBlob string, orderNumber *string) error {
+func (app *Application) createExternalCTest(ctx context.Context, providerID string, uin string, keyVersion *int, encryptedKey string, encryptedBlob string, orderNumber *string) error {
 	//1. create a ctest
-	_, user, err := app.storage.CreateExternalCTest(providerID, uin, encryptedKey, encryptedBlob, false, orderNumber)
+	_, user, err := app.storage.CreateExternalCTest(ctx, providerID, uin, keyVersion, encryptedKey, encryptedBlob, false, orderNumber)
 	if err != nil {
 		return err
 	}
@@ -128,13 +140,26 @@ func (app *Application) createExternalCTest(providerID string, uin string, encry
 	defer app.notifyListeners("onUserUpdated", *user)
 
 	//3. send a firebase notification to the user that the ctest is arrived.
+	app.sendPendingTestsNotification(*user)
+
+	return nil
+}
+
+//notificationTimeout limits the loading of the user data for a notification. The notifications are sent after the request is
+//completed so they cannot use the request context.
+const notificationTimeout = 30 * time.Second
+
+//sendPendingTestsNotification sends a firebase notification to the user that there are ctests for processing
+func (app *Application) sendPendingTestsNotification(user model.User) {
 	go func(userUUID string) {
 		if len(userUUID) <= 0 {
 			log.Println("user uuid is empty")
 			return
 		}
 		//1. load the user data, we need the fcm tokens
-		userData, err := app.profileBB.LoadUserData(user.UUID)
+		loadCtx, cancel := context.WithTimeout(context.Background(), notificationTimeout)
+		defer cancel()
+		userData, err := app.profileBB.LoadUserData(loadCtx, userUUID)
 		if err != nil {
 			log.Printf("Error loading user data - %s\n", err)
 			return
@@ -150,21 +175,19 @@ func (app *Application) createExternalCTest(providerID string, uin string, encry
 		data["click_action"] = "FLUTTER_NOTIFICATION_CLICK"
 		app.messaging.SendNotificationMessage(userData.FCMTokens, "COVID-19", "You have received a COVID-19 update", data)
 	}(user.UUID)
-
-	return nil
 }
 
-func (app *Application) deleteCTests(userID string) (int64, error) {
-	deletedCount, err := app.storage.DeleteCTests(userID)
+func (app *Application) deleteCTests(ctx context.Context, userID string) (int64, error) {
+	deletedCount, err := app.storage.DeleteCTests(ctx, userID)
 	if err != nil {
 		return -1, err
 	}
 	return deletedCount, nil
 }
 
-func (app *Application) updateCTest(current model.User, ID string, processed bool) (*model.CTest, error) {
+func (app *Application) updateCTest(ctx context.Context, current model.User, ID string, processed bool) (*model.CTest, error) {
 	//check if we have a such ctest entity
-	ctest, err := app.storage.FindCTest(ID)
+	ctest, err := app.storage.FindCTest(ctx, ID)
 	if err != nil {
 		return nil, err
 	}
@@ -181,7 +204,7 @@ func (app *Application) updateCTest(current model.User, ID string, processed boo
 	ctest.Processed = processed
 
 	//save it
-	err = app.storage.SaveCTest(ctest)
+	err = app.storage.SaveCTest(ctx, ctest)
 	if err != nil {
 		return nil, err
 	}
@@ -189,9 +212,9 @@ func (app *Application) updateCTest(current model.User, ID string, processed boo
 	return ctest, nil
 }
 
-func (app *Application) getRulesByCounty(countyID string) ([]*model.Rule, []*model.CountyStatus, []*model.TestType, error) {
+func (app *Application) getRulesByCounty(ctx context.Context, countyID string) ([]*model.Rule, []*model.CountyStatus, []*model.TestType, error) {
 	//1. first check if we have a county for the provided id
-	county, err := app.storage.FindCounty(countyID)
+	county, err := app.getCachedCounty(ctx, countyID)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -200,18 +223,23 @@ func (app *Application) getRulesByCounty(countyID string) ([]*model.Rule, []*mod
 	}
 
 	//2. get the rules
-	rules, err := app.storage.FindRulesByCountyID(countyID)
+	allRules, err := app.getCachedRules(ctx)
 	if err != nil {
 		return nil, nil, nil, err
 	}
-	log.Println(rules)
+	var rules []*model.Rule
+	for _, rule := range allRules {
+		if rule.County.ID == countyID {
+			rules = append(rules, rule)
+		}
+	}
 
 	//3. we need to get the county statuses and the test types also - NoSQL!
-	countyStatuses, err := app.storage.FindCountyStatusesByCountyID(countyID)
+	countyStatuses, err := app.getCachedCountyStatuses(ctx, countyID)
 	if err != nil {
 		return nil, nil, nil, err
 	}
-	testTypes, err := app.storage.ReadAllTestTypes()
+	testTypes, err := app.getCachedTestTypes(ctx)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -219,16 +247,21 @@ func (app *Application) getRulesByCounty(countyID string) ([]*model.Rule, []*mod
 	return rules, countyStatuses, testTypes, nil
 }
 
-func (app *Application) createOrUpdateEStatus(userID string, appVersion *string, date *time.Time, encryptedKey string, encryptedBlob string) (*model.EStatus, error) {
+func (app *Application) createOrUpdateEStatus(ctx context.Context, userID string, appVersion *string, date *time.Time, encryptedKey string, encryptedBlob string) (*model.EStatus, error) {
+	appVersion, err := app.resolveEStatusVersion(ctx, userID, appVersion)
+	if err != nil {
+		return nil, err
+	}
+
 	//determine if we need to create or update it
-	status, err := app.storage.FindEStatusByUserID(appVersion, userID)
+	status, err := app.storage.FindEStatusByUserID(ctx, appVersion, userID)
 	if err != nil {
 		return nil, err
 	}
 
 	if status == nil {
 		//we need to create it
-		status, err = app.storage.CreateEStatus(appVersion, userID, date, encryptedKey, encryptedBlob)
+		status, err = app.storage.CreateEStatus(ctx, appVersion, userID, date, encryptedKey, encryptedBlob)
 		if err != nil {
 			return nil, err
 		}
@@ -242,80 +275,184 @@ func (app *Application) createOrUpdateEStatus(userID string, appVersion *string,
 	status.EncryptedBlob = encryptedBlob
 
 	//save it
-	err = app.storage.SaveEStatus(status)
+	err = app.storage.SaveEStatus(ctx, status)
 	if err != nil {
 		return nil, err
 	}
 	return status, nil
 }
 
-func (app *Application) getEStatusByUserID(userID string, appVersion *string) (*model.EStatus, error) {
-	status, err := app.storage.FindEStatusByUserID(appVersion, userID)
+func (app *Application) getEStatusByUserID(ctx context.Context, userID string, appVersion *string) (*model.EStatus, error) {
+	appVersion, err := app.resolveEStatusVersion(ctx, userID, appVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := app.storage.FindEStatusByUserID(ctx, appVersion, userID)
 	if err != nil {
 		return nil, err
 	}
 	return status, nil
 }
 
-func (app *Application) deleteEStatus(userID string, appVersion *string) error {
-	err := app.storage.DeleteEStatus(appVersion, userID)
+func (app *Application) deleteEStatus(ctx context.Context, userID string, appVersion *string) error {
+	appVersion, err := app.resolveEStatusVersion(ctx, userID, appVersion)
+	if err != nil {
+		return err
+	}
+
+	err = app.storage.DeleteEStatus(ctx, appVersion, userID)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (app *Application) getLocation(ID string) (*model.Location, error) {
-	location, err := app.storage.FindLocation(ID)
+//resolveEStatusVersion gives the app version the user status is stored with - the same version, 2.8.0 is 2.8,
+//or a range containing it. The requested version is used if the user does not have a status for it.
+func (app *Application) resolveEStatusVersion(ctx context.Context, userID string, appVersion *string) (*string, error) {
+	if appVersion == nil {
+		return nil, nil
+	}
+	statuses, err := app.storage.FindEStatusesByUserID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
-	return location, nil
+	var keys []string
+	for _, item := range statuses {
+		if item.AppVersion != nil {
+			keys = append(keys, *item.AppVersion)
+		}
+	}
+	if key := matchContentVersion(*appVersion, keys); key != nil {
+		return key, nil
+	}
+	return appVersion, nil
 }
 
-func (app *Application) getLocationsByProviderIDCountyID(providerID string, countyID string) ([]*model.Location, error) {
-	locations, err := app.storage.FindLocationsByProviderIDCountyID(providerID, countyID)
+func (app *Application) getLocation(ctx context.Context, ID string) (*model.Location, error) {
+	locations, err := app.getCachedLocations(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return locations, nil
+	for _, location := range locations {
+		if location.ID == ID {
+			return app.publicLocations([]*model.Location{location})[0], nil
+		}
+	}
+	//not found
+	return nil, nil
 }
 
-func (app *Application) getLocationsByCountyID(countyID string) ([]*model.Location, error) {
-	locations, err := app.storage.FindLocationsByCountyIDDeep(countyID)
+func (app *Application) getLocationsByProviderIDCountyID(ctx context.Context, providerID string, countyID string) ([]*model.Location, error) {
+	locations, err := app.getCachedLocations(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return locations, nil
+	var resultList []*model.Location
+	for _, location := range locations {
+		if location.Provider.ID == providerID && location.County.ID == countyID {
+			resultList = append(resultList, location)
+		}
+	}
+	return app.publicLocations(resultList), nil
+}
+
+func (app *Application) getLocationsByCountyID(ctx context.Context, countyID string) ([]*model.Location, error) {
+	locations, err := app.getCachedLocationsDeep(ctx, []string{countyID})
+	if err != nil {
+		return nil, err
+	}
+	return app.publicLocations(locations), nil
 }
 
-func (app *Application) getLocationsByCounties(countyIDs []string) ([]*model.Location, error) {
-	locations, err := app.storage.FindLocationsByCountiesDeep(countyIDs)
+func (app *Application) getLocationsByCounties(ctx context.Context, countyIDs []string) ([]*model.Location, error) {
+	locations, err := app.getCachedLocationsDeep(ctx, countyIDs)
+	if err != nil {
+		return nil, err
+	}
+	return app.publicLocations(locations), nil
+}
+
+const (
+	defaultNearbyRadius = 10000 //meters
+	maxNearbyRadius     = 100000
+	defaultNearbyLimit  = 20
+	maxNearbyLimit      = 100
+)
+
+//getLocationsNearby gives the locations within the radius in meters from the point, the nearest first. The defaults are used for 0 radius and limit,
+//the too big ones are reduced to the max. The open now filter is applied after the storage query, so the limit is applied after it too.
+func (app *Application) getLocationsNearby(ctx context.Context, latitude float64, longitude float64, radius float64, limit int,
+	testTypeID *string, providerID *string, openNow bool) ([]*model.NearbyLocation, error) {
+	if latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+		return nil, errors.New("invalid coordinates")
+	}
+	if radius < 0 || limit < 0 {
+		return nil, errors.New("the radius and the limit cannot be negative")
+	}
+	switch {
+	case radius == 0:
+		radius = defaultNearbyRadius
+	case radius > maxNearbyRadius:
+		radius = maxNearbyRadius
+	}
+	switch {
+	case limit == 0:
+		limit = defaultNearbyLimit
+	case limit > maxNearbyLimit:
+		limit = maxNearbyLimit
+	}
+
+	storageLimit := int64(limit)
+	if openNow {
+		storageLimit = 0
+	}
+	items, err := app.storage.FindLocationsNearby(ctx, latitude, longitude, radius, testTypeID, providerID, storageLimit)
 	if err != nil {
 		return nil, err
 	}
-	return locations, nil
+
+	now := time.Now()
+	var result []*model.NearbyLocation
+	for _, item := range items {
+		if len(result) == limit {
+			break
+		}
+		if openNow && !app.isLocationOpen(item.Location, locationTime(item.Location, now)) {
+			continue
+		}
+		item.Location = app.publicLocations([]*model.Location{item.Location})[0]
+		result = append(result, item)
+	}
+	return result, nil
 }
 
-func (app *Application) getAllTestTypes() ([]*model.TestType, error) {
-	testTypes, err := app.storage.ReadAllTestTypes()
+func (app *Application) getAllTestTypes(ctx context.Context) ([]*model.TestType, error) {
+	testTypes, err := app.getCachedTestTypes(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return testTypes, nil
 }
 
-func (app *Application) getTestTypesByIDs(ids []string) ([]*model.TestType, error) {
-	testTypes, err := app.storage.FindTestTypesByIDs(ids)
+func (app *Application) getTestTypesByIDs(ctx context.Context, ids []string) ([]*model.TestType, error) {
+	allTestTypes, err := app.getCachedTestTypes(ctx)
 	if err != nil {
 		return nil, err
 	}
+	var testTypes []*model.TestType
+	for _, testType := range allTestTypes {
+		if containsID(testType.ID, ids) {
+			testTypes = append(testTypes, testType)
+		}
+	}
 	return testTypes, nil
 }
 
-func (app *Application) getSymptomRuleByCounty(countyID string) (*model.SymptomRule, []*model.CountyStatus, error) {
+func (app *Application) getSymptomRuleByCounty(ctx context.Context, countyID string) (*model.SymptomRule, []*model.CountyStatus, error) {
 	//get the symptom rule
-	symptomRule, err := app.storage.FindSymptomRuleByCountyID(countyID)
+	symptomRule, err := app.storage.FindSymptomRuleByCountyID(ctx, countyID)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -325,7 +462,7 @@ func (app *Application) getSymptomRuleByCounty(countyID string) (*model.SymptomR
 	}
 
 	//get the county statuses for the provided county
-	countyStatuses, err := app.storage.FindCountyStatusesByCountyID(countyID)
+	countyStatuses, err := app.getCachedCountyStatuses(ctx, countyID)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -333,32 +470,48 @@ func (app *Application) getSymptomRuleByCounty(countyID string) (*model.SymptomR
 	return symptomRule, countyStatuses, nil
 }
 
-func (app *Application) getCRulesByCounty(appVersion *string, countyID string) (*model.CRules, error) {
-	v, err := app.checkAppVersion(appVersion)
+func (app *Application) getCRulesByCounty(ctx context.Context, appVersion *string, countyID string) (*model.CRules, error) {
+	rules, err := app.getCachedCountyCRules(ctx, countyID)
 	if err != nil {
 		return nil, err
 	}
-
-	rules, err := app.storage.FindCRulesByCountyID(*v, countyID)
+	keys := make([]string, len(rules))
+	for i, item := range rules {
+		keys[i] = item.AppVersion
+	}
+	v, err := app.resolveContentVersion(appVersion, keys)
 	if err != nil {
 		return nil, err
 	}
-	return rules, nil
+
+	for _, item := range rules {
+		if item.AppVersion == *v {
+			return item, nil
+		}
+	}
+	return nil, nil
 }
 
-func (app *Application) getAccessRuleByCounty(countyID string) (*model.AccessRule, []*model.CountyStatus, error) {
+func (app *Application) getAccessRuleByCounty(ctx context.Context, countyID string) (*model.AccessRule, []*model.CountyStatus, error) {
 	//get the access rule
-	accessRule, err := app.storage.FindAccessRuleByCountyID(countyID)
+	accessRules, err := app.getCachedAccessRules(ctx)
 	if err != nil {
 		return nil, nil, err
 	}
+	var accessRule *model.AccessRule
+	for _, current := range accessRules {
+		if current.County.ID == countyID {
+			accessRule = current
+			break
+		}
+	}
 	if accessRule == nil {
 		//no item
 		return nil, nil, nil
 	}
 
 	//get the county statuses for the provided county
-	countyStatuses, err := app.storage.FindCountyStatusesByCountyID(countyID)
+	countyStatuses, err := app.getCachedCountyStatuses(ctx, countyID)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -366,26 +519,26 @@ func (app *Application) getAccessRuleByCounty(countyID string) (*model.AccessRul
 	return accessRule, countyStatuses, nil
 }
 
-func (app *Application) аddTraceReport(items []model.TraceExposure) (int, error) {
-	insertedCount, err := app.storage.CreateTraceReports(items)
+func (app *Application) аddTraceReport(ctx context.Context, items []model.TraceExposure) (int, error) {
+	insertedCount, err := app.storage.CreateTraceReports(ctx, items)
 	if err != nil {
 		return 0, err
 	}
 	return insertedCount, nil
 }
 
-func (app *Application) getExposures(timestamp *int64, dateAdded *int64) ([]model.TraceExposure, error) {
-	items, err := app.storage.ReadTraceExposures(timestamp, dateAdded)
+func (app *Application) getExposures(ctx context.Context, timestamp *int64, dateAdded *int64) ([]model.TraceExposure, error) {
+	items, err := app.storage.ReadTraceExposures(ctx, timestamp, dateAdded)
 	if err != nil {
 		return nil, err
 	}
 	return items, nil
 }
 
-func (app *Application) getUINOverride(current model.User) (*model.UINOverride, error) {
+func (app *Application) getUINOverride(ctx context.Context, current model.User) (*model.UINOverride, error) {
 	//supported only for Shibboleth users - uin
 	uin := current.ExternalID
-	uinOverride, err := app.storage.FindUINOverride(uin)
+	uinOverride, err := app.storage.FindUINOverride(ctx, uin)
 	if err != nil {
 		return nil, err
 	}
@@ -393,10 +546,10 @@ func (app *Application) getUINOverride(current model.User) (*model.UINOverride,
 	return uinOverride, nil
 }
 
-func (app *Application) createOrUpdateUINOverride(current model.User, interval int, category *string, expiration *time.Time) error {
+func (app *Application) createOrUpdateUINOverride(ctx context.Context, current model.User, interval int, category *string, expiration *time.Time) error {
 	//supported only for Shibboleth users - uin
 	uin := current.ExternalID
-	err := app.storage.CreateOrUpdateUINOverride(uin, interval, category, expiration)
+	err := app.storage.CreateOrUpdateUINOverride(ctx, uin, interval, category, expiration)
 	if err != nil {
 		return err
 	}
@@ -404,16 +557,16 @@ func (app *Application) createOrUpdateUINOverride(current model.User, interval i
 	return nil
 }
 
-func (app *Application) getExtUINOverrides(uin *string, sort *string) ([]*model.UINOverride, error) {
-	uinOverrides, err := app.storage.FindUINOverrides(uin, sort)
+func (app *Application) getExtUINOverrides(ctx context.Context, uin *string, sort *string) ([]*model.UINOverride, error) {
+	uinOverrides, err := app.storage.FindUINOverrides(ctx, uin, sort)
 	if err != nil {
 		return nil, err
 	}
 	return uinOverrides, nil
 }
 
-func (app *Application) createExtUINOverride(uin string, interval int, category *string, expiration *time.Time) (*model.UINOverride, error) {
-	uinOverride, err := app.storage.CreateUINOverride(uin, interval, category, expiration)
+func (app *Application) createExtUINOverride(ctx context.Context, uin string, interval int, category *string, expiration *time.Time) (*model.UINOverride, error) {
+	uinOverride, err := app.storage.CreateUINOverride(ctx, uin, interval, category, expiration)
 	if err != nil {
 		return nil, err
 	}
@@ -421,8 +574,8 @@ func (app *Application) createExtUINOverride(uin string, interval int, category
 	return uinOverride, nil
 }
 
-func (app *Application) updateExtUINOverride(uin string, interval int, category *string, expiration *time.Time) (*string, error) {
-	uinOverride, err := app.storage.UpdateUINOverride(uin, interval, category, expiration)
+func (app *Application) updateExtUINOverride(ctx context.Context, uin string, interval int, category *string, expiration *time.Time) (*string, error) {
+	uinOverride, err := app.storage.UpdateUINOverride(ctx, uin, interval, category, expiration)
 	if err != nil {
 		return nil, err
 	}
@@ -430,8 +583,8 @@ func (app *Application) updateExtUINOverride(uin string, interval int, category
 	return uinOverride, nil
 }
 
-func (app *Application) deleteExtUINOverride(uin string) error {
-	err := app.storage.DeleteUINOverride(uin)
+func (app *Application) deleteExtUINOverride(ctx context.Context, uin string) error {
+	err := app.storage.DeleteUINOverride(ctx, uin)
 	if err != nil {
 		return err
 	}
@@ -439,34 +592,34 @@ func (app *Application) deleteExtUINOverride(uin string) error {
 	return nil
 }
 
-func (app *Application) setUINBuildingAccess(current model.User, date time.Time, access string) error {
+func (app *Application) setUINBuildingAccess(ctx context.Context, current model.User, date time.Time, access string) error {
 	//supported only for Shibboleth users - uin
 	uin := current.ExternalID
-	err := app.storage.CreateOrUpdateUINBuildingAccess(uin, date, access)
+	err := app.storage.CreateOrUpdateUINBuildingAccess(ctx, uin, date, access)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (app *Application) getExtUINBuildingAccess(uin string) (*model.UINBuildingAccess, error) {
-	uinBuildingAccess, err := app.storage.FindUINBuildingAccess(uin)
+func (app *Application) getExtUINBuildingAccess(ctx context.Context, uin string) (*model.UINBuildingAccess, error) {
+	uinBuildingAccess, err := app.storage.FindUINBuildingAccess(ctx, uin)
 	if err != nil {
 		return nil, err
 	}
 	return uinBuildingAccess, nil
 }
 
-func (app *Application) deleteEHitories(userID string) (int64, error) {
-	deletedCount, err := app.storage.DeleteEHistories(userID)
+func (app *Application) deleteEHitories(ctx context.Context, userID string) (int64, error) {
+	deletedCount, err := app.storage.DeleteEHistories(ctx, userID)
 	if err != nil {
 		return -1, err
 	}
 	return deletedCount, nil
 }
 
-func (app *Application) updateEHistory(userID string, ID string, date *time.Time, encryptedKey *string, encryptedBlob *string) (*model.EHistory, error) {
-	history, err := app.storage.FindEHistory(ID)
+func (app *Application) updateEHistory(ctx context.Context, userID string, keyVersion int, ID string, date *time.Time, encryptedKey *string, encryptedBlob *string) (*model.EHistory, error) {
+	history, err := app.storage.FindEHistory(ctx, ID)
 	if err != nil {
 		return nil, err
 	}
@@ -483,13 +636,15 @@ func (app *Application) updateEHistory(userID string, ID string, date *time.Time
 	}
 	if encryptedKey != nil {
 		history.EncryptedKey = *encryptedKey
+		//the key is encrypted with the current user key
+		history.KeyVersion = keyVersion
 	}
 	if encryptedBlob != nil {
 		history.EncryptedBlob = *encryptedBlob
 	}
 
 	//save it
-	err = app.storage.SaveEHistory(history)
+	err = app.storage.SaveEHistory(ctx, history)
 	if err != nil {
 		return nil, err
 	}
@@ -497,15 +652,24 @@ func (app *Application) updateEHistory(userID string, ID string, date *time.Time
 	return history, nil
 }
 
-func (app *Application) getSymptoms(appVersion *string) (*model.Symptoms, error) {
-	v, err := app.checkAppVersion(appVersion)
+func (app *Application) getSymptoms(ctx context.Context, appVersion *string) (*model.Symptoms, error) {
+	list, err := app.storage.ReadAllSymptoms(ctx)
 	if err != nil {
 		return nil, err
 	}
-
-	symptoms, err := app.storage.ReadSymptoms(*v)
+	keys := make([]string, len(list))
+	for i, item := range list {
+		keys[i] = item.AppVersion
+	}
+	v, err := app.resolveContentVersion(appVersion, keys)
 	if err != nil {
 		return nil, err
 	}
-	return symptoms, nil
+
+	for _, item := range list {
+		if item.AppVersion == *v {
+			return item, nil
+		}
+	}
+	return nil, nil
 }
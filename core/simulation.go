@@ -0,0 +1,163 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"context"
+	"errors"
+	"health/core/model"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	simulationReasonEvent  = "event"
+	simulationReasonExpiry = "expiry"
+	simulationReasonPoint  = "point"
+)
+
+//simulateCountyStatus evaluates the status at every event of the timeline, at every provided point and when a test result
+//which gives the status expires. The draft rules replace the current county rules, nothing is stored.
+func (app *Application) simulateCountyStatus(ctx context.Context, countyID string, draft *model.RulesDraft,
+	timeline []model.SimulationEvent, points []time.Time) ([]model.SimulationStep, error) {
+	rules, err := app.loadStatusRules(ctx, countyID)
+	if err != nil {
+		return nil, err
+	}
+	if draft != nil {
+		err = applyRulesDraft(rules, *draft)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	//the input with all events, the evaluation ignores the ones after its date
+	var input model.StatusEvaluationInput
+	for _, event := range timeline {
+		if event.TestType != nil {
+			result := ""
+			if event.Result != nil {
+				result = *event.Result
+			}
+			input.TestResults = append(input.TestResults, model.EvaluationTestResult{TestType: *event.TestType, Result: result, Date: event.Date})
+		} else {
+			input.SymptomReports = append(input.SymptomReports, model.EvaluationSymptomReport{Symptoms: event.Symptoms, Date: event.Date})
+		}
+	}
+
+	type simulationPoint struct {
+		date   time.Time
+		reason string
+	}
+	var queue []simulationPoint
+	for _, event := range timeline {
+		queue = append(queue, simulationPoint{date: event.Date, reason: simulationReasonEvent})
+	}
+	for _, point := range points {
+		queue = append(queue, simulationPoint{date: point, reason: simulationReasonPoint})
+	}
+	evaluated := make(map[int64]bool)
+
+	steps := []model.SimulationStep{}
+	for len(queue) > 0 {
+		//always the earliest point - the expiries are added while evaluating
+		sort.SliceStable(queue, func(i, j int) bool { return queue[i].date.Before(queue[j].date) })
+		point := queue[0]
+		queue = queue[1:]
+		if evaluated[point.date.UnixNano()] {
+			continue
+		}
+		evaluated[point.date.UnixNano()] = true
+
+		input.Date = point.date
+		evaluation, err := evaluateStatus(*rules, input)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, model.SimulationStep{Date: point.date, Reason: point.reason, Evaluation: *evaluation})
+
+		//show what happens when the status expires
+		if evaluation.ExpiresAt != nil && !evaluated[evaluation.ExpiresAt.UnixNano()] {
+			queue = append(queue, simulationPoint{date: *evaluation.ExpiresAt, reason: simulationReasonExpiry})
+		}
+	}
+	return steps, nil
+}
+
+//applyRulesDraft replaces the rules with the provided draft parts
+func applyRulesDraft(rules *statusRules, draft model.RulesDraft) error {
+	var problems []string
+	countyStatusID := func(value string) string {
+		for _, cs := range rules.county.CountyStatuses {
+			if cs.ID == value || strings.EqualFold(cs.Name, value) {
+				return cs.ID
+			}
+		}
+		problems = append(problems, "there is no a county status "+value)
+		return ""
+	}
+
+	if draft.Rules != nil {
+		var draftRules []*model.Rule
+		for _, item := range draft.Rules {
+			testType := findEvaluationTestType(item.TestType, rules.testTypes)
+			if testType == nil {
+				problems = append(problems, "there is no a test type "+item.TestType)
+				continue
+			}
+			var resultsStates []model.TestTypeResultCountyStatus
+			for _, rs := range item.ResultsStates {
+				testTypeResult := findEvaluationTestTypeResult(rs.TestTypeResult, testType.Results)
+				if testTypeResult == nil {
+					problems = append(problems, "there is no a result "+rs.TestTypeResult+" for test type "+testType.Name)
+					continue
+				}
+				resultsStates = append(resultsStates, model.TestTypeResultCountyStatus{TestTypeResultID: testTypeResult.ID,
+					CountyStatusID: countyStatusID(rs.CountyStatus)})
+			}
+			draftRules = append(draftRules, &model.Rule{County: *rules.county, TestType: *testType, Priority: item.Priority, ResultsStates: resultsStates})
+		}
+		rules.rules = draftRules
+	}
+
+	if draft.AccessRule != nil {
+		accessRule := &model.AccessRule{County: *rules.county}
+		for _, item := range draft.AccessRule.Rules {
+			if item.Value != "granted" && item.Value != "denied" {
+				problems = append(problems, "the access rule value must be granted or denied - "+item.Value)
+			}
+			accessRule.Rules = append(accessRule.Rules, model.AccessRuleCountyStatus{CountyStatusID: countyStatusID(item.CountyStatus), Value: item.Value})
+		}
+		rules.accessRule = accessRule
+	}
+
+	if draft.SymptomRule != nil {
+		symptomRule := &model.SymptomRule{County: *rules.county, Gr1Count: draft.SymptomRule.Gr1Count, Gr2Count: draft.SymptomRule.Gr2Count}
+		for _, item := range draft.SymptomRule.Items {
+			symptomRule.Items = append(symptomRule.Items, model.SymptomRuleItem{Gr1: item.Gr1, Gr2: item.Gr2,
+				CountyStatus: model.CountyStatus{ID: countyStatusID(item.CountyStatus)}, NextStep: item.NextStep})
+		}
+		rules.symptomRule = symptomRule
+	}
+
+	if len(problems) > 0 {
+		return errors.New("invalid rules draft - " + strings.Join(problems, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"health/core/model"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	waitTimeColorGreen  = "green"
+	waitTimeColorYellow = "yellow"
+	waitTimeColorRed    = "red"
+	waitTimeColorGrey   = "grey"
+
+	waitTimeSourceProvider = "provider"
+	waitTimeSourceAdmin    = "admin"
+
+	//used if they are not set in the covid19 config
+	defaultWaitTimeYellowMinutes = 15
+	defaultWaitTimeRedMinutes    = 30
+	defaultWaitTimeStaleMinutes  = 60
+)
+
+//reportWaitTime saves the reported wait time in the history and as the latest wait time of the location.
+//The color is shown right away if the location is open, the closed locations stay grey.
+func (app *Application) reportWaitTime(ctx context.Context, locationID string, waitMinutes int, queueLength *int,
+	source string, reportedBy string) (*model.WaitTimeReport, error) {
+	if waitMinutes < 0 {
+		return nil, errors.New("the wait minutes cannot be negative")
+	}
+	if queueLength != nil && *queueLength < 0 {
+		return nil, errors.New("the queue length cannot be negative")
+	}
+
+	location, err := app.storage.FindLocation(ctx, locationID)
+	if err != nil {
+		return nil, err
+	}
+	if location == nil {
+		return nil, errors.New("there is no a location for the provided id")
+	}
+
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+	report := &model.WaitTimeReport{ID: id.String(), LocationID: locationID, WaitMinutes: waitMinutes, QueueLength: queueLength,
+		Color: app.waitTimeColor(waitMinutes), Source: source, ReportedBy: reportedBy, DateCreated: time.Now().UTC().Truncate(time.Millisecond)}
+	err = app.storage.CreateWaitTimeReport(ctx, report)
+	if err != nil {
+		return nil, err
+	}
+
+	waitTimeColor := report.Color
+	if !app.isLocationOpen(location, locationTime(location, time.Now())) {
+		waitTimeColor = waitTimeColorGrey
+	}
+	err = app.storage.UpdateLocationWaitTime(ctx, locationID, report, &waitTimeColor)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (app *Application) reportWaitTimeByAdmin(ctx context.Context, current model.User, group string, audit *string, locationID string,
+	waitMinutes int, queueLength *int) (*model.WaitTimeReport, error) {
+	userIdentifier, userInfo := current.GetLogData()
+	report, err := app.reportWaitTime(ctx, locationID, waitMinutes, queueLength, waitTimeSourceAdmin, userIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	//audit
+	lData := []AuditDataEntry{{Key: "locationID", Value: locationID}, {Key: "waitMinutes", Value: fmt.Sprint(waitMinutes)},
+		{Key: "queueLength", Value: fmt.Sprint(intValue(queueLength))}, {Key: "color", Value: report.Color}}
+	defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "wait-time-report", report.ID, lData, audit)
+
+	return report, nil
+}
+
+func (app *Application) getWaitTimeReports(ctx context.Context, locationID string, limit int64) ([]*model.WaitTimeReport, error) {
+	reports, err := app.storage.FindWaitTimeReports(ctx, locationID, limit)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, report := range reports {
+		report.Stale = app.isWaitTimeStale(*report, now)
+	}
+	return reports, nil
+}
+
+//waitTimeColor gives the color for the wait minutes by the thresholds
+func (app *Application) waitTimeColor(waitMinutes int) string {
+	yellowMinutes, redMinutes, _ := app.waitTimeThresholds()
+	switch {
+	case waitMinutes >= redMinutes:
+		return waitTimeColorRed
+	case waitMinutes >= yellowMinutes:
+		return waitTimeColorYellow
+	default:
+		return waitTimeColorGreen
+	}
+}
+
+//isWaitTimeStale checks if the report is too old to be shown as the current wait time
+func (app *Application) isWaitTimeStale(report model.WaitTimeReport, now time.Time) bool {
+	_, _, staleMinutes := app.waitTimeThresholds()
+	return now.Sub(report.DateCreated) > time.Duration(staleMinutes)*time.Minute
+}
+
+//waitTimeThresholds gives the yellow, the red and the stale minutes, the defaults are used for the ones which are not configured
+func (app *Application) waitTimeThresholds() (int, int, int) {
+	yellowMinutes := defaultWaitTimeYellowMinutes
+	redMinutes := defaultWaitTimeRedMinutes
+	staleMinutes := defaultWaitTimeStaleMinutes
+	if config := app.getCachedCovid19Config(); config != nil {
+		if config.WaitTimeYellowMinutes > 0 {
+			yellowMinutes = config.WaitTimeYellowMinutes
+		}
+		if config.WaitTimeRedMinutes > 0 {
+			redMinutes = config.WaitTimeRedMinutes
+		}
+		if config.WaitTimeStaleMinutes > 0 {
+			staleMinutes = config.WaitTimeStaleMinutes
+		}
+	}
+	return yellowMinutes, redMinutes, staleMinutes
+}
+
+//checkWaitTimeThresholds validates the wait time thresholds in the covid19 config, 0 means the default value
+func checkWaitTimeThresholds(config *model.COVID19Config) error {
+	if config.WaitTimeYellowMinutes < 0 || config.WaitTimeRedMinutes < 0 || config.WaitTimeStaleMinutes < 0 {
+		return errors.New("the wait time minutes cannot be negative")
+	}
+	yellowMinutes := config.WaitTimeYellowMinutes
+	if yellowMinutes == 0 {
+		yellowMinutes = defaultWaitTimeYellowMinutes
+	}
+	redMinutes := config.WaitTimeRedMinutes
+	if redMinutes == 0 {
+		redMinutes = defaultWaitTimeRedMinutes
+	}
+	if redMinutes <= yellowMinutes {
+		return fmt.Errorf("the wait time red minutes %d must be greater than the yellow minutes %d", redMinutes, yellowMinutes)
+	}
+	return nil
+}
+
+//locationTime gives the time in the location timezone
+func locationTime(location *model.Location, t time.Time) time.Time {
+	timeLocation, err := time.LoadLocation(location.Timezone)
+	if err != nil {
+		log.Printf("Error getting time location:%s\n", err.Error())
+		return t.UTC()
+	}
+	return t.In(timeLocation)
+}
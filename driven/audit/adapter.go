@@ -19,10 +19,14 @@ package audit
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"health/core"
 	"log"
+	"regexp"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -33,6 +37,9 @@ import (
 //Adapter implements the Audit interface
 type Adapter struct {
 	db *database
+
+	//the events are logged asynchronously
+	pending *sync.WaitGroup
 }
 
 //Start starts the audit
@@ -41,10 +48,17 @@ func (a *Adapter) Start() error {
 	return err
 }
 
+//Wait waits for the pending events to be logged
+func (a *Adapter) Wait() {
+	a.pending.Wait()
+}
+
 //LogCreateEvent logs a create event item
 func (a *Adapter) LogCreateEvent(userIdentifier string, userInfo string, usedGroup string, entity string, entityID string,
 	data []core.AuditDataEntry, clientData *string) {
+	a.pending.Add(1)
 	go func(userIdentifier string, userInfo string, usedGroup string, entity string, entityID string) {
+		defer a.pending.Done()
 		dataFormatted := a.prepareData(data)
 		auditEntity := core.AuditEntity{UserIdentifier: userIdentifier, UserInfo: userInfo,
 			UsedGroup: usedGroup, Entity: entity, EntityID: entityID,
@@ -58,7 +72,9 @@ func (a *Adapter) LogCreateEvent(userIdentifier string, userInfo string, usedGro
 //LogUpdateEvent logs an update event item
 func (a *Adapter) LogUpdateEvent(userIdentifier string, userInfo string, usedGroup string, entity string, entityID string,
 	data []core.AuditDataEntry, clientData *string) {
+	a.pending.Add(1)
 	go func(userIdentifier string, userInfo string, usedGroup string, entity string, entityID string) {
+		defer a.pending.Done()
 		dataFormatted := a.prepareData(data)
 		auditEntity := core.AuditEntity{UserIdentifier: userIdentifier, UserInfo: userInfo,
 			UsedGroup: usedGroup, Entity: entity, EntityID: entityID,
@@ -92,7 +108,9 @@ func (a *Adapter) prepareData(data []core.AuditDataEntry) *string {
 
 //LogDeleteEvent logs a delete event item
 func (a *Adapter) LogDeleteEvent(userIdentifier string, userInfo string, usedGroup string, entity string, entityID string) {
+	a.pending.Add(1)
 	go func(userIdentifier string, userInfo string, usedGroup string, entity string, entityID string) {
+		defer a.pending.Done()
 		auditEntity := core.AuditEntity{UserIdentifier: userIdentifier, UserInfo: userInfo,
 			UsedGroup: usedGroup, Entity: entity, EntityID: entityID,
 			Operation: "delete", Data: nil, CreatedAt: time.Now()}
@@ -163,6 +181,122 @@ func (a *Adapter) Find(userIdentifier *string, usedGroup *string, entity *string
 	return result, nil
 }
 
+//DeleteOlderThan deletes the items created before the provided date except the ones for the excluded entities.
+//It works on small batches as one big delete could exceed the mongoDB timeout.
+func (a *Adapter) DeleteOlderThan(ctx context.Context, date time.Time, excludedEntities []string) (int64, error) {
+	filter := bson.D{primitive.E{Key: "created_at", Value: bson.M{"$lt": date}}}
+	if len(excludedEntities) > 0 {
+		filter = append(filter, primitive.E{Key: "entity", Value: bson.M{"$nin": excludedEntities}})
+	}
+
+	var count int64
+	for {
+		//find the next batch
+		findOptions := options.Find()
+		findOptions.SetLimit(deleteBatchSize)
+		findOptions.SetProjection(bson.D{primitive.E{Key: "_id", Value: 1}})
+		var result []bson.M
+		err := a.db.audit.FindWithContext(ctx, filter, &result, findOptions)
+		if err != nil {
+			return count, err
+		}
+		if len(result) == 0 {
+			return count, nil
+		}
+
+		ids := make([]interface{}, len(result))
+		for i, item := range result {
+			ids[i] = item["_id"]
+		}
+		idsFilter := bson.D{primitive.E{Key: "_id", Value: bson.M{"$in": ids}}}
+		deleteResult, err := a.db.audit.DeleteManyWithContext(ctx, idsFilter, nil)
+		if err != nil {
+			return count, err
+		}
+		count += deleteResult.DeletedCount
+
+		if len(result) < deleteBatchSize {
+			return count, nil
+		}
+	}
+}
+
+const deleteBatchSize = 500
+
+//auditItem is an audit entity with its id, so it can be replaced
+type auditItem struct {
+	ID               primitive.ObjectID `bson:"_id"`
+	core.AuditEntity `bson:",inline"`
+}
+
+//AnonymizeUser replaces the provided user identifiers in the items. The items are kept as they are needed
+//as evidence of the changes but they must not point to an erased user.
+func (a *Adapter) AnonymizeUser(ctx context.Context, identifiers []string) (int64, error) {
+	if len(identifiers) == 0 {
+		return 0, nil
+	}
+	quoted := make([]string, len(identifiers))
+	for i, identifier := range identifiers {
+		quoted[i] = regexp.QuoteMeta(identifier)
+	}
+	pattern := primitive.Regex{Pattern: strings.Join(quoted, "|")}
+	filter := bson.D{primitive.E{Key: "$or", Value: bson.A{
+		bson.M{"user_identifier": bson.M{"$in": identifiers}},
+		bson.M{"entity_id": bson.M{"$in": identifiers}},
+		bson.M{"data": bson.M{"$regex": pattern}},
+		bson.M{"client_data": bson.M{"$regex": pattern}},
+	}}}
+
+	var result []*auditItem
+	err := a.db.audit.FindWithContext(ctx, filter, &result, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	for _, item := range result {
+		if !anonymizeAuditEntity(&item.AuditEntity, identifiers) {
+			continue
+		}
+		itemFilter := bson.D{primitive.E{Key: "_id", Value: item.ID}}
+		err = a.db.audit.ReplaceOneWithContext(ctx, itemFilter, item, nil)
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+//anonymizedValue replaces the erased user identifiers
+const anonymizedValue = "erased"
+
+func anonymizeAuditEntity(item *core.AuditEntity, identifiers []string) bool {
+	changed := false
+	for _, identifier := range identifiers {
+		if item.UserIdentifier == identifier {
+			item.UserIdentifier = anonymizedValue
+			item.UserInfo = ""
+			changed = true
+		}
+		if item.EntityID == identifier {
+			item.EntityID = anonymizedValue
+			changed = true
+		}
+		if item.Data != nil && strings.Contains(*item.Data, identifier) {
+			data := strings.ReplaceAll(*item.Data, identifier, anonymizedValue)
+			item.Data = &data
+			changed = true
+		}
+		if item.ClientData != nil && strings.Contains(*item.ClientData, identifier) {
+			clientData := strings.ReplaceAll(*item.ClientData, identifier, anonymizedValue)
+			item.ClientData = &clientData
+			changed = true
+		}
+	}
+	return changed
+}
+
 //NewAuditAdapter creates a new audit adapter instance
 func NewAuditAdapter(mongoDBAuth string, mongoDBName string, mongoTimeout string) *Adapter {
 	timeout, err := strconv.Atoi(mongoTimeout)
@@ -173,5 +307,5 @@ func NewAuditAdapter(mongoDBAuth string, mongoDBName string, mongoTimeout string
 	timeoutMS := time.Millisecond * time.Duration(timeout)
 
 	db := &database{mongoDBAuth: mongoDBAuth, mongoDBName: mongoDBName, mongoTimeout: timeoutMS}
-	return &Adapter{db: db}
+	return &Adapter{db: db, pending: &sync.WaitGroup{}}
 }
@@ -18,11 +18,14 @@
 package dataprovider
 
 import (
+	"bytes"
+	"encoding/json"
 	"encoding/xml"
+	"errors"
+	"fmt"
 	"health/core"
-	"io/ioutil"
+	"health/core/model"
 	"log"
-	"net/http"
 	"strings"
 	"time"
 
@@ -33,83 +36,168 @@ import (
 type Adapter struct {
 	newsRSSURL   string
 	resourcesURL string
+
+	fetcher *fetcher
+}
+
+//GetDefaultNewsURL gives the RSS feed which is registered as a news source if there is no any
+func (a *Adapter) GetDefaultNewsURL() string {
+	return a.newsRSSURL
 }
 
-//LoadNews loads the provider news
-func (a *Adapter) LoadNews() ([]core.ProviderNews, error) {
-	log.Println("LoadNews() -> start loading data provider news...")
+//LoadNews loads the news from a RSS 2.0, Atom 1.0 or JSON Feed feed. The validators of the previous load are sent, it gives an empty list
+//and the same validators if the feed is not changed since then.
+func (a *Adapter) LoadNews(url string, format string, validators core.ProviderValidators) ([]core.ProviderNews, core.ProviderValidators, error) {
+	log.Printf("LoadNews() -> start loading %s news from %s...", format, url)
 
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", a.newsRSSURL, nil)
+	body, responseValidators, err := a.fetcher.get(url, &fetchValidators{etag: validators.ETag, lastModified: validators.LastModified})
+	if err == errNotModified {
+		log.Printf("LoadNews() -> %s is not modified", url)
+		return []core.ProviderNews{}, validators, nil
+	}
 	if err != nil {
-		log.Printf("Error creating news rss request:%s\n", err.Error())
-		return nil, err
+		log.Printf("Error loading news %s\n", err.Error())
+		return nil, validators, fmt.Errorf("error loading news - %s", err)
 	}
 
-	resp, err := client.Do(req)
+	var result []core.ProviderNews
+	switch format {
+	case "rss":
+		result, err = parseRSS(body)
+	case "atom":
+		result, err = parseAtom(body)
+	case "json":
+		result, err = parseJSONFeed(body)
+	default:
+		err = errors.New("not supported news feed format " + format)
+	}
 	if err != nil {
-		log.Printf("Error loading news %s\n", err.Error())
-		return nil, err
+		log.Printf("Error unmarshal the news %s\n", err.Error())
+		return nil, validators, err
 	}
-	defer resp.Body.Close()
 
-	body, err := ioutil.ReadAll(resp.Body)
+	log.Printf("LoadNews() -> end loading news from %s - %d items", url, len(result))
+	return result, core.ProviderValidators{ETag: responseValidators.etag, LastModified: responseValidators.lastModified}, nil
+}
+
+func parseRSS(body []byte) ([]core.ProviderNews, error) {
+	var feed rss
+	err := xml.Unmarshal(body, &feed)
 	if err != nil {
-		log.Printf("Error getting the body for the get news response %s\n", err.Error())
 		return nil, err
 	}
 
-	var getNewsResponse rss
-	err = xml.Unmarshal(body, &getNewsResponse)
+	var result []core.ProviderNews
+	for _, item := range feed.Channel.Item {
+		//Mon, 23 Mar 2020 16:38:23 +0000
+		pubDate, err := parseNewsDate(item.PubDate, time.RFC1123Z, time.RFC1123)
+		if err != nil {
+			//do not add the item if the time parsing fails
+			log.Printf("time parse error %s", err)
+			continue
+		}
+		result = append(result, core.ProviderNews{GUID: strings.TrimSpace(item.GUID), Link: strings.TrimSpace(item.Link),
+			PubDate: pubDate, Title: item.Title, Description: item.Description, ContentEncoded: item.Encoded, Categories: item.Category})
+	}
+	return result, nil
+}
+
+func parseAtom(body []byte) ([]core.ProviderNews, error) {
+	var feed atom
+	err := xml.Unmarshal(body, &feed)
 	if err != nil {
-		log.Printf("Error unmarshal the news %s\n", err.Error())
 		return nil, err
 	}
 
 	var result []core.ProviderNews
-
-	items := getNewsResponse.Channel.Item
-	if items != nil {
-		for _, item := range items {
-			//Mon, 23 Mar 2020 16:38:23 +0000
-			layout := time.RFC1123Z
-			parsedDate, err := time.Parse(layout, item.PubDate)
-			if err != nil {
-				//do not add the item if the time parsing fails
-				log.Printf("time parse error %s", err)
-			} else {
-
-				/* we need to workaround .000Z
-				It appears when it is marshaled in Golang!!
-				this is good
-				2020-03-20T10:00:00.001Z
-
-				this is bad
-				2020-03-20T10:00:00.000Z
-				*/
-				modDate := parsedDate.Add(time.Millisecond * 1)
-
-				pubDate := modDate
-				title := item.Title
-				description := item.Description
-				contentEncoded := item.Encoded
-
-				cItem := core.ProviderNews{PubDate: pubDate, Title: title,
-					Description: description, ContentEncoded: contentEncoded}
-				result = append(result, cItem)
+	for _, entry := range feed.Entry {
+		date := entry.Published
+		if len(date) == 0 {
+			date = entry.Updated
+		}
+		pubDate, err := parseNewsDate(date, time.RFC3339)
+		if err != nil {
+			//do not add the item if the time parsing fails
+			log.Printf("time parse error %s", err)
+			continue
+		}
+		var link string
+		for _, item := range entry.Link {
+			if len(item.Rel) == 0 || item.Rel == "alternate" {
+				link = strings.TrimSpace(item.Href)
+				break
 			}
 		}
+		var categories []string
+		for _, item := range entry.Category {
+			categories = append(categories, item.Term)
+		}
+		result = append(result, core.ProviderNews{GUID: strings.TrimSpace(entry.ID), Link: link,
+			PubDate: pubDate, Title: entry.Title, Description: entry.Summary, ContentEncoded: entry.Content, Categories: categories})
+	}
+	return result, nil
+}
+
+func parseJSONFeed(body []byte) ([]core.ProviderNews, error) {
+	var feed jsonFeed
+	err := json.Unmarshal(body, &feed)
+	if err != nil {
+		return nil, err
 	}
 
-	log.Println("LoadNews() -> end loading data provider news...")
+	var result []core.ProviderNews
+	for _, item := range feed.Items {
+		date := item.DatePublished
+		if len(date) == 0 {
+			date = item.DateModified
+		}
+		pubDate, err := parseNewsDate(date, time.RFC3339)
+		if err != nil {
+			//do not add the item if the time parsing fails
+			log.Printf("time parse error %s", err)
+			continue
+		}
+		content := item.ContentHTML
+		if len(content) == 0 {
+			content = item.ContentText
+		}
+		result = append(result, core.ProviderNews{GUID: strings.TrimSpace(item.ID), Link: strings.TrimSpace(item.URL),
+			PubDate: pubDate, Title: item.Title, Description: item.Summary, ContentEncoded: content, Categories: item.Tags})
+	}
 	return result, nil
 }
 
+//parseNewsDate parses the date with the first matching layout
+func parseNewsDate(value string, layouts ...string) (time.Time, error) {
+	var err error
+	for _, layout := range layouts {
+		var parsedDate time.Time
+		parsedDate, err = time.Parse(layout, strings.TrimSpace(value))
+		if err == nil {
+			/* we need to workaround .000Z
+			It appears when it is marshaled in Golang!!
+			this is good
+			2020-03-20T10:00:00.001Z
+
+			this is bad
+			2020-03-20T10:00:00.000Z
+			*/
+			return parsedDate.Add(time.Millisecond * 1), nil
+		}
+	}
+	return time.Time{}, err
+}
+
 //LoadResources loads the provider resources
 func (a *Adapter) LoadResources() ([]core.ProviderResource, error) {
 	log.Println("LoadResources() -> start loading data provider resources...")
 
-	doc, err := goquery.NewDocument(a.resourcesURL)
+	body, _, err := a.fetcher.get(a.resourcesURL, nil)
+	if err != nil {
+		log.Printf("LoadResources() -> error loading the resources - %s\n", err)
+		return nil, err
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
 	if err != nil {
 		log.Printf("LoadResources() -> error creating reader from url - %s\n", err)
 		//there is no what to do so return the input
@@ -133,20 +221,61 @@ func (a *Adapter) LoadResources() ([]core.ProviderResource, error) {
 	return resources, nil
 }
 
+//GetFetchMetrics gives the fetch health for every loaded url
+func (a *Adapter) GetFetchMetrics() []model.ProviderFetchMetrics {
+	return a.fetcher.getMetrics()
+}
+
 //NewDataProviderAdapter creates a new provider adapter instance
 func NewDataProviderAdapter(newsRSSURL string, resourcesURL string) *Adapter {
-	return &Adapter{newsRSSURL: newsRSSURL, resourcesURL: resourcesURL}
+	return &Adapter{newsRSSURL: newsRSSURL, resourcesURL: resourcesURL, fetcher: newFetcher()}
 }
 
 type rss struct {
 	XMLName xml.Name `xml:"rss"`
 	Channel struct {
 		Item []struct {
-			Text        string `xml:",chardata"`
-			Title       string `xml:"title"`
-			PubDate     string `xml:"pubDate"`
-			Description string `xml:"description"`
-			Encoded     string `xml:"encoded"`
+			Text        string   `xml:",chardata"`
+			GUID        string   `xml:"guid"`
+			Link        string   `xml:"link"`
+			Title       string   `xml:"title"`
+			PubDate     string   `xml:"pubDate"`
+			Description string   `xml:"description"`
+			Encoded     string   `xml:"encoded"`
+			Category    []string `xml:"category"`
 		} `xml:"item"`
 	} `xml:"channel"`
 }
+
+type atom struct {
+	XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+	Entry   []struct {
+		ID   string `xml:"id"`
+		Link []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Title     string `xml:"title"`
+		Published string `xml:"published"`
+		Updated   string `xml:"updated"`
+		Summary   string `xml:"summary"`
+		Content   string `xml:"content"`
+		Category  []struct {
+			Term string `xml:"term,attr"`
+		} `xml:"category"`
+	} `xml:"entry"`
+}
+
+type jsonFeed struct {
+	Items []struct {
+		ID            string   `json:"id"`
+		URL           string   `json:"url"`
+		Title         string   `json:"title"`
+		Summary       string   `json:"summary"`
+		ContentHTML   string   `json:"content_html"`
+		ContentText   string   `json:"content_text"`
+		DatePublished string   `json:"date_published"`
+		DateModified  string   `json:"date_modified"`
+		Tags          []string `json:"tags"`
+	} `json:"items"`
+}
@@ -0,0 +1,181 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package dataprovider
+
+import (
+	"errors"
+	"fmt"
+	"health/core/model"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	fetchTimeout      = 30 * time.Second
+	fetchMaxAttempts  = 3
+	fetchRetryBackoff = 2 * time.Second //doubled on every retry
+	fetchMaxBodySize  = 5 * 1024 * 1024
+)
+
+//errNotModified is given when the content is not changed since the previous fetch
+var errNotModified = errors.New("not modified")
+
+//fetcher makes the requests to the upstream sites. The requests have a timeout, the failed ones are retried
+//with a backoff and the responses are limited in size, so an outage or a slowdown cannot hang the loading.
+type fetcher struct {
+	client *http.Client
+
+	lock    *sync.Mutex
+	metrics map[string]*model.ProviderFetchMetrics
+}
+
+//fetchValidators are the ETag and the Last-Modified of a response
+type fetchValidators struct {
+	etag         string
+	lastModified string
+}
+
+//get loads the url and gives the validators of the response. If validators are provided then they are sent
+//and errNotModified is given if the content is not changed. The caller keeps the validators once it has processed the content.
+func (f *fetcher) get(url string, validators *fetchValidators) ([]byte, fetchValidators, error) {
+	var body []byte
+	var responseValidators fetchValidators
+	var statusCode int
+	var err error
+	start := time.Now()
+	backoff := fetchRetryBackoff
+	attempts := 0
+	for attempts < fetchMaxAttempts {
+		if attempts > 0 {
+			log.Printf("fetcher -> retry %s after %s - %s", url, backoff, err)
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		attempts++
+
+		var retry bool
+		body, responseValidators, statusCode, retry, err = f.doGet(url, validators)
+		if err == nil || !retry {
+			break
+		}
+	}
+	f.recordMetrics(url, attempts, statusCode, time.Since(start), len(body), err)
+	return body, responseValidators, err
+}
+
+//doGet makes one request, it gives if the request can be retried on error
+func (f *fetcher) doGet(url string, validators *fetchValidators) ([]byte, fetchValidators, int, bool, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fetchValidators{}, 0, false, err
+	}
+	if validators != nil {
+		if len(validators.etag) > 0 {
+			req.Header.Set("If-None-Match", validators.etag)
+		}
+		if len(validators.lastModified) > 0 {
+			req.Header.Set("If-Modified-Since", validators.lastModified)
+		}
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		//network errors and timeouts
+		return nil, fetchValidators{}, 0, true, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		return nil, fetchValidators{}, resp.StatusCode, false, errNotModified
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError:
+		return nil, fetchValidators{}, resp.StatusCode, true, fmt.Errorf("status code %d", resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fetchValidators{}, resp.StatusCode, false, fmt.Errorf("status code %d", resp.StatusCode)
+	}
+
+	//read one byte more than the limit to know if it is exceeded
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, fetchMaxBodySize+1))
+	if err != nil {
+		return nil, fetchValidators{}, resp.StatusCode, true, err
+	}
+	if len(body) > fetchMaxBodySize {
+		return nil, fetchValidators{}, resp.StatusCode, false, fmt.Errorf("response is larger than %d bytes", fetchMaxBodySize)
+	}
+
+	responseValidators := fetchValidators{etag: resp.Header.Get("ETag"), lastModified: resp.Header.Get("Last-Modified")}
+	return body, responseValidators, resp.StatusCode, false, nil
+}
+
+func (f *fetcher) recordMetrics(url string, attempts int, statusCode int, duration time.Duration, size int, err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	metrics, found := f.metrics[url]
+	if !found {
+		metrics = &model.ProviderFetchMetrics{URL: url}
+		f.metrics[url] = metrics
+	}
+
+	now := time.Now().UTC()
+	metrics.Fetches++
+	metrics.Retries += int64(attempts - 1)
+	metrics.LastFetchedAt = &now
+	metrics.LastStatusCode = statusCode
+	metrics.LastDurationMs = duration.Milliseconds()
+	metrics.LastBytes = int64(size)
+	switch err {
+	case nil:
+		metrics.Successes++
+		metrics.LastError = nil
+		metrics.LastSucceededAt = &now
+	case errNotModified:
+		metrics.NotModified++
+		metrics.LastError = nil
+		metrics.LastSucceededAt = &now
+	default:
+		metrics.Failures++
+		errMessage := err.Error()
+		metrics.LastError = &errMessage
+	}
+}
+
+//getMetrics gives a copy of the metrics for all urls
+func (f *fetcher) getMetrics() []model.ProviderFetchMetrics {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	result := make([]model.ProviderFetchMetrics, 0, len(f.metrics))
+	for _, item := range f.metrics {
+		result = append(result, *item)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].URL < result[j].URL
+	})
+	return result
+}
+
+func newFetcher() *fetcher {
+	return &fetcher{client: &http.Client{Timeout: fetchTimeout}, lock: &sync.Mutex{},
+		metrics: make(map[string]*model.ProviderFetchMetrics)}
+}
@@ -0,0 +1,4033 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"health/core"
+	"health/core/model"
+	"health/utils"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+//errNoDocuments mirrors the error the mongoDB adapter gives for a missing single document
+var errNoDocuments = errors.New("no documents in result")
+
+//Adapter implements the Storage interface keeping all the data in memory. It is used for local development and tests
+type Adapter struct {
+	lock *sync.RWMutex
+
+	appVersions       []*model.AppVersion
+	covid19Config     *model.COVID19Config
+	users             []*model.User
+	resources         []*model.Resource
+	resourceChanges   []*model.ResourceChange
+	faq               *model.FAQ
+	news              []*model.News
+	newsSources       []*model.NewsSource
+	estatuses         []*model.EStatus
+	ehistories        []*model.EHistory
+	providers         []*model.Provider
+	ctests            []*model.CTest
+	counties          []*model.County
+	testTypes         []*model.TestType
+	rules             []*model.Rule
+	locations         []*model.Location
+	waitTimeReports   []*model.WaitTimeReport
+	appointments      []*model.Appointment
+	symptomGroups     []*model.SymptomGroup
+	symptoms          []*model.Symptoms
+	symptomRules      []*model.SymptomRule
+	cRules            []*model.CRules
+	traceExposures    []model.TraceExposure
+	manualTests       []*model.EManualTest
+	accessRules       []*model.AccessRule
+	uinOverrides      []*model.UINOverride
+	uinBuildingAccess []*model.UINBuildingAccess
+	retentionPolicies []*model.RetentionPolicy
+	erasureReceipts   []*model.ErasureReceipt
+	configRevisions   []*model.ConfigRevision
+	configSchemas     []*model.ConfigSchema
+
+	listener core.StorageListener
+}
+
+//Start starts the storage
+func (sa *Adapter) Start() error {
+	log.Println("memory storage -> start")
+	return nil
+}
+
+//SetStorageListener sets listener for the storage
+func (sa *Adapter) SetStorageListener(storageListener core.StorageListener) {
+	sa.listener = storageListener
+}
+
+//ReadAllAppVersions reads all the versions. It gives them in a sorted way as the latest version is on position 0
+func (sa *Adapter) ReadAllAppVersions(ctx context.Context) ([]string, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	if len(sa.appVersions) == 0 {
+		return nil, nil
+	}
+
+	res := make([]string, len(sa.appVersions))
+	for i, item := range sa.appVersions {
+		res[i] = item.Version
+	}
+
+	//sort the versions list
+	utils.SortVersions(res)
+
+	return res, nil
+}
+
+//CreateAppVersion creates app version
+func (sa *Adapter) CreateAppVersion(ctx context.Context, version string) error {
+	sa.lock.Lock()
+	for _, current := range sa.appVersions {
+		if current.Version == version {
+			sa.lock.Unlock()
+			return errors.New("duplicate app version " + version)
+		}
+	}
+	now := time.Now().UTC()
+	sa.appVersions = append(sa.appVersions, &model.AppVersion{Version: version, Status: "supported", DateUpdated: &now})
+	sa.lock.Unlock()
+
+	//there is no change stream so notify directly
+	sa.notifyChanged("appversions")
+	return nil
+}
+
+//FindAppVersions finds all the app versions with their statuses
+func (sa *Adapter) FindAppVersions(ctx context.Context) ([]*model.AppVersion, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	result := make([]*model.AppVersion, len(sa.appVersions))
+	for i, item := range sa.appVersions {
+		copied := *item
+		result[i] = &copied
+	}
+	return result, nil
+}
+
+//UpdateAppVersionStatus updates the status of an app version
+func (sa *Adapter) UpdateAppVersionStatus(ctx context.Context, version string, status string) error {
+	sa.lock.Lock()
+	var item *model.AppVersion
+	for _, current := range sa.appVersions {
+		if current.Version == version {
+			item = current
+			break
+		}
+	}
+	if item == nil {
+		sa.lock.Unlock()
+		return errors.New("there is no an app version for the provided version")
+	}
+	now := time.Now().UTC()
+	item.Status = status
+	item.DateUpdated = &now
+	sa.lock.Unlock()
+
+	//there is no change stream so notify directly
+	sa.notifyChanged("appversions")
+	return nil
+}
+
+//ClearUserData removes all the user data in the storage
+func (sa *Adapter) ClearUserData(ctx context.Context, userID string, uins []string) (map[string]int64, error) {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	//the old collections are not kept in memory
+	counts := map[string]int64{"status": 0, "history": 0, "manualtests": 0}
+
+	var ctests []*model.CTest
+	for _, item := range sa.ctests {
+		if item.UserID != userID {
+			ctests = append(ctests, item)
+		}
+	}
+	counts["ctests"] = int64(len(sa.ctests) - len(ctests))
+	sa.ctests = ctests
+
+	var ehistories []*model.EHistory
+	for _, item := range sa.ehistories {
+		if item.UserID != userID {
+			ehistories = append(ehistories, item)
+		}
+	}
+	counts["ehistory"] = int64(len(sa.ehistories) - len(ehistories))
+	sa.ehistories = ehistories
+
+	var estatuses []*model.EStatus
+	for _, item := range sa.estatuses {
+		if item.UserID != userID {
+			estatuses = append(estatuses, item)
+		}
+	}
+	counts["estatus"] = int64(len(sa.estatuses) - len(estatuses))
+	sa.estatuses = estatuses
+
+	//the images are part of the manual tests
+	var manualTests []*model.EManualTest
+	for _, item := range sa.manualTests {
+		if item.User.ID != userID {
+			manualTests = append(manualTests, item)
+		}
+	}
+	counts["emanualtests"] = int64(len(sa.manualTests) - len(manualTests))
+	sa.manualTests = manualTests
+
+	var appointments []*model.Appointment
+	for _, item := range sa.appointments {
+		if item.UserID != userID {
+			appointments = append(appointments, item)
+		}
+	}
+	counts["appointments"] = int64(len(sa.appointments) - len(appointments))
+	sa.appointments = appointments
+
+	var uinOverrides []*model.UINOverride
+	for _, item := range sa.uinOverrides {
+		if !containsString(uins, item.UIN) {
+			uinOverrides = append(uinOverrides, item)
+		}
+	}
+	counts["uinoverrides"] = int64(len(sa.uinOverrides) - len(uinOverrides))
+	sa.uinOverrides = uinOverrides
+
+	var uinBuildingAccess []*model.UINBuildingAccess
+	for _, item := range sa.uinBuildingAccess {
+		if !containsString(uins, item.UIN) {
+			uinBuildingAccess = append(uinBuildingAccess, item)
+		}
+	}
+	counts["uinbuildingaccess"] = int64(len(sa.uinBuildingAccess) - len(uinBuildingAccess))
+	sa.uinBuildingAccess = uinBuildingAccess
+
+	counts["users"] = 0
+	for index, item := range sa.users {
+		if item.ID == userID {
+			sa.users = append(sa.users[:index], sa.users[index+1:]...)
+			counts["users"] = 1
+			break
+		}
+	}
+	return counts, nil
+}
+
+//FindUser finds the user for the provided id
+func (sa *Adapter) FindUser(ctx context.Context, ID string) (*model.User, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	for _, item := range sa.users {
+		if item.ID == ID {
+			return copyUser(item), nil
+		}
+	}
+	//not found
+	return nil, nil
+}
+
+//FindUserByExternalID finds the user for the provided external id
+func (sa *Adapter) FindUserByExternalID(ctx context.Context, externalID string) (*model.User, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	user := sa.findUserByExternalID(externalID)
+	if user == nil {
+		//not found
+		return nil, nil
+	}
+	return copyUser(user), nil
+}
+
+//FindUserByShibbolethID finds the user for the provided shibboleth id
+func (sa *Adapter) FindUserByShibbolethID(ctx context.Context, shibbolethID string) (*model.User, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	for _, item := range sa.users {
+		if item.ShibbolethAuth != nil && item.ShibbolethAuth.Uin == shibbolethID {
+			return copyUser(item), nil
+		}
+	}
+	//not found
+	return nil, nil
+}
+
+//FindUsersByRePost finds the users filtered by re_post
+func (sa *Adapter) FindUsersByRePost(ctx context.Context, rePost bool) ([]*model.User, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var result []*model.User
+	for _, item := range sa.users {
+		if item.RePost == rePost {
+			result = append(result, copyUser(item))
+		}
+	}
+	return result, nil
+}
+
+//CreateUser creates an user
+func (sa *Adapter) CreateUser(ctx context.Context, shibboAuth *model.ShibbolethAuth, externalID string,
+	userUUID string, publicKey string, consent bool, exposureNotification bool, rePost bool, encryptedKey *string, encryptedBlob *string) (*model.User, error) {
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	//external id is unique
+	if sa.findUserByExternalID(externalID) != nil {
+		return nil, errors.New("duplicate user external id " + externalID)
+	}
+
+	user := model.User{ID: id.String(), ShibbolethAuth: shibboAuth, ExternalID: externalID, UUID: userUUID,
+		PublicKey: publicKey, Consent: consent, ExposureNotification: exposureNotification, RePost: rePost,
+		EncryptedKey: encryptedKey, EncryptedBlob: encryptedBlob, DateCreated: time.Now()}
+	user.RotatePublicKey(publicKey)
+	sa.users = append(sa.users, copyUser(&user))
+
+	//return the inserted item
+	return &user, nil
+}
+
+//SaveUser saves the user
+func (sa *Adapter) SaveUser(ctx context.Context, user *model.User) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	dateUpdated := time.Now()
+	user.DateUpdated = &dateUpdated
+
+	for index, item := range sa.users {
+		if item.ID == user.ID {
+			sa.users[index] = copyUser(user)
+			return nil
+		}
+	}
+	return errors.New("replace one - no record replaced")
+}
+
+//ReadCovid19Config reads the covid19 configuration from the storage
+func (sa *Adapter) ReadCovid19Config(ctx context.Context) (*model.COVID19Config, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	if sa.covid19Config == nil {
+		return nil, errors.New("no covid19 config found")
+	}
+	config := *sa.covid19Config
+	return &config, nil
+}
+
+//SaveCovid19Config saves the covid19 configuration to the storage
+func (sa *Adapter) SaveCovid19Config(ctx context.Context, covid19Config *model.COVID19Config) error {
+	sa.lock.Lock()
+	if sa.covid19Config == nil || sa.covid19Config.Name != covid19Config.Name {
+		sa.lock.Unlock()
+		return errors.New("replace one - no record replaced")
+	}
+	config := *covid19Config
+	sa.covid19Config = &config
+	sa.lock.Unlock()
+
+	//there is no change stream so notify directly
+	sa.notifyChanged("configs")
+	return nil
+}
+
+//ReadAllResources reads all covid19 resources
+func (sa *Adapter) ReadAllResources(ctx context.Context) ([]*model.Resource, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var result []*model.Resource
+	for _, item := range sa.resources {
+		result = append(result, copyResource(item))
+	}
+	return result, nil
+}
+
+//CreateResource creates a resource item
+func (sa *Adapter) CreateResource(ctx context.Context, title string, link string, displayOrder int, sourceKey *string) (*model.Resource, error) {
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	resource := model.Resource{ID: id.String(), Title: title, Link: link, DisplayOrder: displayOrder, SourceKey: sourceKey}
+	sa.resources = append(sa.resources, copyResource(&resource))
+
+	//return the inserted item
+	return &resource, nil
+}
+
+//DeleteResource deletes a resource item
+func (sa *Adapter) DeleteResource(ctx context.Context, ID string) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for index, item := range sa.resources {
+		if item.ID == ID {
+			sa.resources = append(sa.resources[:index], sa.resources[index+1:]...)
+			return nil
+		}
+	}
+	return errors.New("error occured while deleting a resource item with id " + ID)
+}
+
+//FindResource finds resource
+func (sa *Adapter) FindResource(ctx context.Context, ID string) (*model.Resource, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	for _, item := range sa.resources {
+		if item.ID == ID {
+			return copyResource(item), nil
+		}
+	}
+	//not found
+	return nil, nil
+}
+
+//SaveResource saves resource entity to the storage
+func (sa *Adapter) SaveResource(ctx context.Context, resource *model.Resource) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for index, item := range sa.resources {
+		if item.ID == resource.ID {
+			sa.resources[index] = copyResource(resource)
+			return nil
+		}
+	}
+	return errors.New("replace one - no record replaced")
+}
+
+//FindResourceChanges finds the resource changes, all of them if the status is nil. The latest are first.
+func (sa *Adapter) FindResourceChanges(ctx context.Context, status *string) ([]*model.ResourceChange, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var result []*model.ResourceChange
+	for _, item := range sa.resourceChanges {
+		if status == nil || item.Status == *status {
+			change := *item
+			result = append(result, &change)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].DateCreated.After(result[j].DateCreated)
+	})
+	return result, nil
+}
+
+//FindResourceChange finds a resource change
+func (sa *Adapter) FindResourceChange(ctx context.Context, ID string) (*model.ResourceChange, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	for _, item := range sa.resourceChanges {
+		if item.ID == ID {
+			change := *item
+			return &change, nil
+		}
+	}
+	//not found
+	return nil, nil
+}
+
+//SaveResourceChange saves a resource change, it creates it if it does not exist
+func (sa *Adapter) SaveResourceChange(ctx context.Context, change *model.ResourceChange) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	item := *change
+	for index, current := range sa.resourceChanges {
+		if current.ID == change.ID {
+			sa.resourceChanges[index] = &item
+			return nil
+		}
+	}
+	sa.resourceChanges = append(sa.resourceChanges, &item)
+	return nil
+}
+
+//DeleteResourceChange deletes a resource change
+func (sa *Adapter) DeleteResourceChange(ctx context.Context, ID string) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for index, item := range sa.resourceChanges {
+		if item.ID == ID {
+			sa.resourceChanges = append(sa.resourceChanges[:index], sa.resourceChanges[index+1:]...)
+			return nil
+		}
+	}
+	return errors.New("there is no a resource change for the provided id")
+}
+
+//ReadFAQ reads the covid19 FAQs
+func (sa *Adapter) ReadFAQ(ctx context.Context) (*model.FAQ, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	if sa.faq == nil {
+		return nil, errors.New("no faq data")
+	}
+	var faq model.FAQ
+	copyEntity(sa.faq, &faq)
+	return &faq, nil
+}
+
+//SaveFAQ saves faq entity to the storage
+func (sa *Adapter) SaveFAQ(ctx context.Context, faq *model.FAQ) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	if sa.faq == nil {
+		return errors.New("replace one - no record replaced")
+	}
+	var item model.FAQ
+	copyEntity(faq, &item)
+	sa.faq = &item
+	return nil
+}
+
+//DeleteFAQSection deletes a faq section
+func (sa *Adapter) DeleteFAQSection(ctx context.Context, ID string) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	if sa.faq == nil {
+		return errors.New("no faq data")
+	}
+
+	//1. find the section
+	sections := sa.faq.Sections
+	if sections == nil {
+		return errors.New("for some reasons the sections are nil")
+	}
+	sectionIndex := -1
+	for sIndex, section := range *sections {
+		if section.ID == ID {
+			sectionIndex = sIndex
+		}
+	}
+	if sectionIndex == -1 {
+		return errors.New("cannot remove faq section with the provided id " + ID)
+	}
+	section := (*sections)[sectionIndex]
+
+	//2. check if there are question for this section
+	if section.Questions != nil && len(*section.Questions) > 0 {
+		return errors.New("cannot remove faq section because there are associated questions")
+	}
+
+	//3. remove the section from the faq
+	modifiedList := append((*sections)[:sectionIndex], (*sections)[sectionIndex+1:]...)
+	sa.faq.Sections = &modifiedList
+	return nil
+}
+
+//ReadNews reads all covid19 news
+func (sa *Adapter) ReadNews(ctx context.Context, limit int64) ([]*model.News, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var result []*model.News
+	for _, item := range sa.news {
+		news := *item
+		result = append(result, &news)
+	}
+
+	//sort by "date"
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Date.After(result[j].Date)
+	})
+
+	if limit > 0 && int64(len(result)) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+//ReadPublishedNews reads the news the apps receive - published, with a publish time which has come and not expired.
+//The pinned news are first, the others are sorted by date. If the category is provided only the news in it are given.
+func (sa *Adapter) ReadPublishedNews(ctx context.Context, now time.Time, limit int64, category *string) ([]*model.News, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var result []*model.News
+	for _, item := range sa.news {
+		if item.Status == "draft" || (item.PublishAt != nil && item.PublishAt.After(now)) || (item.ExpireAt != nil && !item.ExpireAt.After(now)) {
+			continue
+		}
+		if category != nil && !containsString(item.Categories, *category) {
+			continue
+		}
+		news := *item
+		result = append(result, &news)
+	}
+
+	//sort by "pinned" and "date"
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Pinned != result[j].Pinned {
+			return result[i].Pinned
+		}
+		return result[i].Date.After(result[j].Date)
+	})
+
+	if limit > 0 && int64(len(result)) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+//CreateNews creates a new covid19 news
+func (sa *Adapter) CreateNews(ctx context.Context, date time.Time, title string, description string, htmlContent string, link *string,
+	status string, publishAt *time.Time, expireAt *time.Time, pinned bool, categories []string) (*model.News, error) {
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	now := time.Now().UTC()
+	news := model.News{ID: id.String(), Date: date, Title: title, Description: description,
+		HTMLContent: htmlContent, Link: link, Status: status, PublishAt: publishAt, ExpireAt: expireAt, Pinned: pinned, Categories: copyStrings(categories),
+		DateUpdated: &now}
+	item := news
+	sa.news = append(sa.news, &item)
+
+	//return the inserted item
+	return &news, nil
+}
+
+//DeleteNews deletes a new covid19 news
+func (sa *Adapter) DeleteNews(ctx context.Context, ID string) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for index, item := range sa.news {
+		if item.ID == ID {
+			sa.news = append(sa.news[:index], sa.news[index+1:]...)
+			return nil
+		}
+	}
+	return errors.New("error occured while deleting an item with id " + ID)
+}
+
+//FindNews finds news
+func (sa *Adapter) FindNews(ctx context.Context, ID string) (*model.News, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	for _, item := range sa.news {
+		if item.ID == ID {
+			news := *item
+			return &news, nil
+		}
+	}
+	//not found
+	return nil, nil
+}
+
+//SaveNews saves news entity to the storage
+func (sa *Adapter) SaveNews(ctx context.Context, news *model.News) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for index, item := range sa.news {
+		if item.ID == news.ID {
+			now := time.Now().UTC()
+			news.DateUpdated = &now
+			entity := *news
+			sa.news[index] = &entity
+			return nil
+		}
+	}
+	return errors.New("replace one - no record replaced")
+}
+
+//InsertSourceNews inserts the news imported from a source if there is no a news with the same guid for the source.
+//It gives true if the news is inserted.
+func (sa *Adapter) InsertSourceNews(ctx context.Context, news *model.News) (bool, error) {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for _, item := range sa.news {
+		if utils.GetString(item.SourceID) == utils.GetString(news.SourceID) && item.GUID != nil && news.GUID != nil && *item.GUID == *news.GUID {
+			return false, nil
+		}
+	}
+	now := time.Now().UTC()
+	news.DateUpdated = &now
+	item := *news
+	sa.news = append(sa.news, &item)
+	return true, nil
+}
+
+//FindNewsSources finds all the news sources
+func (sa *Adapter) FindNewsSources(ctx context.Context) ([]*model.NewsSource, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	result := make([]*model.NewsSource, len(sa.newsSources))
+	for i, item := range sa.newsSources {
+		source := *item
+		result[i] = &source
+	}
+	return result, nil
+}
+
+//FindNewsSource finds a news source
+func (sa *Adapter) FindNewsSource(ctx context.Context, ID string) (*model.NewsSource, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	for _, item := range sa.newsSources {
+		if item.ID == ID {
+			source := *item
+			return &source, nil
+		}
+	}
+	//not found
+	return nil, nil
+}
+
+//SaveNewsSource saves a news source, it creates it if it does not exist
+func (sa *Adapter) SaveNewsSource(ctx context.Context, source *model.NewsSource) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	item := *source
+	for index, current := range sa.newsSources {
+		if current.ID == source.ID {
+			sa.newsSources[index] = &item
+			return nil
+		}
+	}
+	sa.newsSources = append(sa.newsSources, &item)
+	return nil
+}
+
+//DeleteNewsSource deletes a news source, the news imported from it are kept
+func (sa *Adapter) DeleteNewsSource(ctx context.Context, ID string) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for index, item := range sa.newsSources {
+		if item.ID == ID {
+			sa.newsSources = append(sa.newsSources[:index], sa.newsSources[index+1:]...)
+			return nil
+		}
+	}
+	return errors.New("there is no a news source for the provided id")
+}
+
+//UpdateNewsSourceFetchStatus updates only the last fetch fields, so the admin changes made during the fetch are kept
+func (sa *Adapter) UpdateNewsSourceFetchStatus(ctx context.Context, source *model.NewsSource) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for _, item := range sa.newsSources {
+		if item.ID == source.ID {
+			item.LastFetchedAt = source.LastFetchedAt
+			item.LastFetchStatus = source.LastFetchStatus
+			item.LastError = source.LastError
+			item.LastAddedCount = source.LastAddedCount
+			item.LastSucceededAt = source.LastSucceededAt
+			item.LastETag = source.LastETag
+			item.LastModified = source.LastModified
+			return nil
+		}
+	}
+	return nil
+}
+
+//CreateEStatus creates a new covid19 passport status
+func (sa *Adapter) CreateEStatus(ctx context.Context, appVersion *string, userID string, date *time.Time, encryptedKey string, encryptedBlob string) (*model.EStatus, error) {
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	status := model.EStatus{ID: id.String(), AppVersion: appVersion, UserID: userID, Date: date, EncryptedKey: encryptedKey, EncryptedBlob: encryptedBlob}
+	item := status
+	sa.estatuses = append(sa.estatuses, &item)
+
+	//return the inserted item
+	return &status, nil
+}
+
+//FindEStatusByUserID finds a status by user id
+func (sa *Adapter) FindEStatusByUserID(ctx context.Context, appVersion *string, userID string) (*model.EStatus, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	for _, item := range sa.estatuses {
+		if item.UserID == userID && equalStringPointers(item.AppVersion, appVersion) {
+			status := *item
+			return &status, nil
+		}
+	}
+	//not found
+	return nil, nil
+}
+
+//FindEStatusesByUserID finds the statuses of the user for all app versions
+func (sa *Adapter) FindEStatusesByUserID(ctx context.Context, userID string) ([]*model.EStatus, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var result []*model.EStatus
+	for _, item := range sa.estatuses {
+		if item.UserID == userID {
+			status := *item
+			result = append(result, &status)
+		}
+	}
+	return result, nil
+}
+
+//SaveEStatus saves the status
+func (sa *Adapter) SaveEStatus(ctx context.Context, status *model.EStatus) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	dateUpdated := time.Now()
+	status.DateUpdated = &dateUpdated
+
+	for index, item := range sa.estatuses {
+		if item.ID == status.ID {
+			entity := *status
+			sa.estatuses[index] = &entity
+			return nil
+		}
+	}
+	return errors.New("replace one - no record replaced")
+}
+
+//DeleteEStatus deletes the status for the user
+func (sa *Adapter) DeleteEStatus(ctx context.Context, appVersion *string, userID string) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for index, item := range sa.estatuses {
+		if item.UserID == userID && equalStringPointers(item.AppVersion, appVersion) {
+			sa.estatuses = append(sa.estatuses[:index], sa.estatuses[index+1:]...)
+			return nil
+		}
+	}
+	return errors.New("there is no a estatus for user id " + userID)
+}
+
+//CreateEHistory creates a history
+func (sa *Adapter) CreateEHistory(ctx context.Context, userID string, keyVersion int, date time.Time, eType string, encryptedKey string, encryptedBlob string) (*model.EHistory, error) {
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	history := model.EHistory{ID: id.String(), UserID: userID, Date: date, Type: eType,
+		EncryptedKey: encryptedKey, EncryptedBlob: encryptedBlob, KeyVersion: keyVersion}
+	item := history
+	sa.ehistories = append(sa.ehistories, &item)
+
+	//return the inserted item
+	return &history, nil
+}
+
+//CreateManualЕHistory creates a history
+func (sa *Adapter) CreateManualЕHistory(ctx context.Context, userID string, keyVersion int, date time.Time, encryptedKey string, encryptedBlob string, encryptedImageKey *string, encryptedImageBlob *string,
+	countyID *string, locationID *string) (*model.EHistory, error) {
+	if encryptedImageKey == nil || encryptedImageBlob == nil {
+		return nil, errors.New("the manual test image is required")
+	}
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	//1. insert history item
+	historyID, _ := uuid.NewUUID()
+	history := model.EHistory{ID: historyID.String(), UserID: userID, Date: date, Type: "unverified_manual_test",
+		EncryptedKey: encryptedKey, EncryptedBlob: encryptedBlob, KeyVersion: keyVersion}
+	item := history
+	sa.ehistories = append(sa.ehistories, &item)
+
+	//2. insert manual test item
+	manualTestID, _ := uuid.NewUUID()
+	manualTest := model.EManualTest{ID: manualTestID.String(), User: model.User{ID: userID}, HistoryID: history.ID,
+		LocationID: locationID, CountyID: countyID, EncryptedKey: encryptedKey, EncryptedBlob: encryptedBlob,
+		EncryptedImageKey: *encryptedImageKey, EncryptedImageBlob: *encryptedImageBlob, Status: "unverified", Date: time.Now()}
+	sa.manualTests = append(sa.manualTests, &manualTest)
+
+	return &history, nil
+}
+
+//FindEHistories finds all histories for an user
+func (sa *Adapter) FindEHistories(ctx context.Context, userID string) ([]*model.EHistory, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var result []*model.EHistory
+	for _, item := range sa.ehistories {
+		if item.UserID == userID {
+			history := *item
+			result = append(result, &history)
+		}
+	}
+
+	//sort by "date"
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Date.After(result[j].Date)
+	})
+	return result, nil
+}
+
+//DeleteEHistories deletes all histories for an user
+func (sa *Adapter) DeleteEHistories(ctx context.Context, userID string) (int64, error) {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	var deletedCount int64
+	var ehistories []*model.EHistory
+	for _, item := range sa.ehistories {
+		if item.UserID == userID {
+			deletedCount++
+		} else {
+			ehistories = append(ehistories, item)
+		}
+	}
+	sa.ehistories = ehistories
+	return deletedCount, nil
+}
+
+//FindEHistory finds a history item
+func (sa *Adapter) FindEHistory(ctx context.Context, ID string) (*model.EHistory, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	for _, item := range sa.ehistories {
+		if item.ID == ID {
+			history := *item
+			return &history, nil
+		}
+	}
+	//not found
+	return nil, nil
+}
+
+//SaveEHistory saves a history item
+func (sa *Adapter) SaveEHistory(ctx context.Context, history *model.EHistory) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for index, item := range sa.ehistories {
+		if item.ID == history.ID {
+			entity := *history
+			sa.ehistories[index] = &entity
+			return nil
+		}
+	}
+	return errors.New("replace one - no record replaced")
+}
+
+//ReadAllProviders reads all the providers
+func (sa *Adapter) ReadAllProviders(ctx context.Context) ([]*model.Provider, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var resultList []*model.Provider
+	for _, item := range sa.providers {
+		resultList = append(resultList, copyProvider(item))
+	}
+	return resultList, nil
+}
+
+//CreateProvider creates a provider
+func (sa *Adapter) CreateProvider(ctx context.Context, providerName string, manualTest bool, availableMechanisms []string) (*model.Provider, error) {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("providers")
+
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	provider := model.Provider{ID: id.String(), Name: providerName, ManualTest: manualTest, AvailableMechanisms: availableMechanisms}
+	sa.providers = append(sa.providers, copyProvider(&provider))
+
+	//return the inserted item
+	return &provider, nil
+}
+
+//FindProvider finds a provider
+func (sa *Adapter) FindProvider(ctx context.Context, ID string) (*model.Provider, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	provider := sa.findProvider(ID)
+	if provider == nil {
+		//not found
+		return nil, nil
+	}
+	return copyProvider(provider), nil
+}
+
+//SaveProvider saves a provider
+func (sa *Adapter) SaveProvider(ctx context.Context, entity *model.Provider) error {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("providers")
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	provider := sa.findProvider(entity.ID)
+	if provider == nil {
+		//not found
+		return errors.New("there is no a provider for the provided id")
+	}
+
+	//update the values
+	provider.Name = entity.Name
+	provider.ManualTest = entity.ManualTest
+	provider.AvailableMechanisms = copyStrings(entity.AvailableMechanisms)
+	return nil
+}
+
+//DeleteProvider deletes a provider
+func (sa *Adapter) DeleteProvider(ctx context.Context, ID string) error {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("providers")
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	//1. check if there are associated locations for this provider
+	for _, item := range sa.locations {
+		if item.Provider.ID == ID {
+			return errors.New("there are associated locations for this provider")
+		}
+	}
+
+	//2. check if there are associated ctests for this provider
+	for _, item := range sa.ctests {
+		if item.ProviderID == ID {
+			return errors.New("there are associated ctests for this provider")
+		}
+	}
+
+	//3. now we can delete the provider
+	for index, item := range sa.providers {
+		if item.ID == ID {
+			sa.providers = append(sa.providers[:index], sa.providers[index+1:]...)
+			return nil
+		}
+	}
+	return errors.New("there is no a provider for id " + ID)
+}
+
+//CreateExternalCTest creates an external ctests record
+func (sa *Adapter) CreateExternalCTest(ctx context.Context, providerID string, uin string, keyVersion *int, encryptedKey string, encryptedBlob string, processed bool, orderNumber *string) (*model.CTest, *model.User, error) {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	//1. check if there is a provider for the provided identifier
+	if sa.findProvider(providerID) == nil {
+		return nil, nil, errors.New("there is no a provider for the provided identifier")
+	}
+
+	//2. check if there is a user with the provided uin
+	user := sa.findUserByExternalID(uin)
+	if user == nil {
+		return nil, nil, errors.New("there is no a user for the provided identifier")
+	}
+
+	//3. create a ctest, it is encrypted for the current user key if the provider does not say another version
+	ctestKeyVersion := user.KeyVersion
+	if keyVersion != nil {
+		ctestKeyVersion = *keyVersion
+	}
+	cTest, err := sa.createCTest(providerID, user.ID, ctestKeyVersion, encryptedKey, encryptedBlob, processed, orderNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	//4. Set the user re-post field as "false"
+	dateUpdated := time.Now()
+	user.DateUpdated = &dateUpdated
+	user.RePost = false
+
+	return cTest, copyUser(user), nil
+}
+
+//CreateAdminCTest creates an admin ctests record
+func (sa *Adapter) CreateAdminCTest(ctx context.Context, providerID string, userID string, encryptedKey string, encryptedBlob string, processed bool, orderNumber *string) (*model.CTest, *model.User, error) {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	//1. check if there is a provider for the provided identifier
+	if sa.findProvider(providerID) == nil {
+		return nil, nil, errors.New("there is no a provider for the provided identifier")
+	}
+
+	//2. check if there is a user with the id
+	var user *model.User
+	for _, item := range sa.users {
+		if item.ID == userID {
+			user = item
+			break
+		}
+	}
+	if user == nil {
+		return nil, nil, errors.New("there is no a user for the provided identifier")
+	}
+
+	//3. create a ctest
+	cTest, err := sa.createCTest(providerID, user.ID, user.KeyVersion, encryptedKey, encryptedBlob, processed, orderNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cTest, copyUser(user), nil
+}
+
+//FindCTest finds ctest
+func (sa *Adapter) FindCTest(ctx context.Context, ID string) (*model.CTest, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	for _, item := range sa.ctests {
+		if item.ID == ID {
+			ctest := *item
+			return &ctest, nil
+		}
+	}
+	//not found
+	return nil, nil
+}
+
+//FindCTests finds ctests for user and processed
+func (sa *Adapter) FindCTests(ctx context.Context, userID string, processed bool) ([]*model.CTest, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var result []*model.CTest
+	for _, item := range sa.ctests {
+		if item.UserID == userID && item.Processed == processed {
+			ctest := *item
+			result = append(result, &ctest)
+		}
+	}
+
+	//sort by "date_created"
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].DateCreated.Before(result[j].DateCreated)
+	})
+	return result, nil
+}
+
+//FindCTestsByExternalUserIDs finds ctests lists for the provided external user IDs
+func (sa *Adapter) FindCTestsByExternalUserIDs(ctx context.Context, externalUserIDs []string) (map[string][]*model.CTest, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	mapData := make(map[string][]*model.CTest, len(externalUserIDs))
+	for _, item := range sa.ctests {
+		user := sa.findUser(item.UserID)
+		if user == nil || !containsString(externalUserIDs, user.ExternalID) {
+			continue
+		}
+		ctest := *item
+		mapData[user.ExternalID] = append(mapData[user.ExternalID], &ctest)
+	}
+	if len(mapData) == 0 {
+		//not found
+		return nil, nil
+	}
+	return mapData, nil
+}
+
+//DeleteCTests deletes all ctest for a user
+func (sa *Adapter) DeleteCTests(ctx context.Context, userID string) (int64, error) {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	var deletedCount int64
+	var ctests []*model.CTest
+	for _, item := range sa.ctests {
+		if item.UserID == userID {
+			deletedCount++
+		} else {
+			ctests = append(ctests, item)
+		}
+	}
+	sa.ctests = ctests
+	return deletedCount, nil
+}
+
+//SaveCTest saves the ctest
+func (sa *Adapter) SaveCTest(ctx context.Context, entity *model.CTest) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for _, item := range sa.ctests {
+		if item.ID == entity.ID {
+			//update the values
+			item.Processed = entity.Processed
+			item.EncryptedKey = entity.EncryptedKey
+			item.EncryptedBlob = entity.EncryptedBlob
+			item.KeyVersion = entity.KeyVersion
+			dateUpdated := time.Now()
+			item.DateUpdated = &dateUpdated
+			return nil
+		}
+	}
+	return errors.New("there is no a ctest for the provided id")
+}
+
+//FindCTestsWithOldKeyVersion finds the not processed ctests which are encrypted for an old public key of their user
+func (sa *Adapter) FindCTestsWithOldKeyVersion(ctx context.Context, providerID *string, userID *string) ([]*model.CTest, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var result []*model.CTest
+	for _, item := range sa.ctests {
+		if item.Processed || (providerID != nil && item.ProviderID != *providerID) || (userID != nil && item.UserID != *userID) {
+			continue
+		}
+		//the same as $unwind - skip the ctests without a user
+		user := sa.findUser(item.UserID)
+		if user == nil || item.KeyVersion >= user.KeyVersion {
+			continue
+		}
+		ctest := *item
+		result = append(result, &ctest)
+	}
+
+	//sort by "date_created"
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].DateCreated.Before(result[j].DateCreated)
+	})
+	return result, nil
+}
+
+//FindCounties finds counties
+func (sa *Adapter) FindCounties(ctx context.Context, f *utils.Filter) ([]*model.County, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var resultList []*model.County
+	for _, item := range sa.counties {
+		if matchesCountyFilter(item, f) {
+			resultList = append(resultList, copyCounty(item))
+		}
+	}
+	return resultList, nil
+}
+
+//CreateCounty creates a county
+func (sa *Adapter) CreateCounty(ctx context.Context, name string, stateProvince string, country string) (*model.County, error) {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("counties")
+
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	county := model.County{ID: id.String(), Name: name, StateProvince: stateProvince, Country: country}
+	sa.counties = append(sa.counties, copyCounty(&county))
+
+	//return the inserted item
+	return &county, nil
+}
+
+//FindCounty finds a county
+func (sa *Adapter) FindCounty(ctx context.Context, ID string) (*model.County, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	county := sa.findCounty(ID)
+	if county == nil {
+		//not found
+		return nil, nil
+	}
+	return copyCounty(county), nil
+}
+
+//SaveCounty saves a county
+func (sa *Adapter) SaveCounty(ctx context.Context, entity *model.County) error {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("counties")
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	county := sa.findCounty(entity.ID)
+	if county == nil {
+		//not found
+		return errors.New("there is no a county for the provided id")
+	}
+
+	//update the values
+	county.Name = entity.Name
+	county.StateProvince = entity.StateProvince
+	county.Country = entity.Country
+	return nil
+}
+
+//DeleteCounty deletes a county
+func (sa *Adapter) DeleteCounty(ctx context.Context, ID string) error {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("counties")
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	//1. check if there are associated locations for this county
+	for _, item := range sa.locations {
+		if item.County.ID == ID {
+			return errors.New("there are associated locations for this county")
+		}
+	}
+
+	//2. check if there are associated test types rules for this county
+	for _, item := range sa.rules {
+		if item.County.ID == ID {
+			return errors.New("there are associated test types rules for this county")
+		}
+	}
+
+	//3. check if there are associated symptom rules for this county
+	for _, item := range sa.symptomRules {
+		if item.County.ID == ID {
+			return errors.New("there are associated symptom rules for this county")
+		}
+	}
+
+	//4. check if there are associated access rules for this county
+	for _, item := range sa.accessRules {
+		if item.County.ID == ID {
+			return errors.New("there are associated access rules for this county")
+		}
+	}
+
+	//5. now we can delete the county
+	for index, item := range sa.counties {
+		if item.ID == ID {
+			sa.counties = append(sa.counties[:index], sa.counties[index+1:]...)
+			return nil
+		}
+	}
+	return errors.New("there is no a county for id " + ID)
+}
+
+//CreateCountyConfiguration creates a county with its guidelines, statuses, rules and locations and publishes the crules revisions
+func (sa *Adapter) CreateCountyConfiguration(ctx context.Context, county *model.County, rules []*model.Rule, accessRule *model.AccessRule,
+	symptomRule *model.SymptomRule, cRulesRevisions []*model.ConfigRevision, locations []*model.Location) error {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("counties")
+	defer sa.notifyChanged("rules")
+	defer sa.notifyChanged("accessrules")
+	defer sa.notifyChanged("crules")
+	defer sa.notifyChanged("locations")
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	//everything is applied under the lock, so it is done at once
+	sa.counties = append(sa.counties, copyCounty(county))
+	for _, item := range rules {
+		sa.rules = append(sa.rules, copyRule(item))
+	}
+	if accessRule != nil {
+		sa.accessRules = append(sa.accessRules, copyAccessRule(accessRule))
+	}
+	if symptomRule != nil {
+		sa.symptomRules = append(sa.symptomRules, copySymptomRule(symptomRule))
+	}
+	for _, revision := range cRulesRevisions {
+		sa.publishConfigRevision(revision)
+	}
+	for _, item := range locations {
+		sa.locations = append(sa.locations, copyLocation(item))
+	}
+	return nil
+}
+
+//UpdateCountyConfiguration replaces the county data, rules, access rule and symptom rule, publishes the crules revisions
+//and creates or updates the locations. Only the bundle fields of the existing locations are updated.
+func (sa *Adapter) UpdateCountyConfiguration(ctx context.Context, county *model.County, rules []*model.Rule, accessRule *model.AccessRule,
+	symptomRule *model.SymptomRule, cRulesRevisions []*model.ConfigRevision, locations []*model.Location) error {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("counties")
+	defer sa.notifyChanged("rules")
+	defer sa.notifyChanged("accessrules")
+	defer sa.notifyChanged("crules")
+	defer sa.notifyChanged("locations")
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	//everything is applied under the lock, so it is done at once
+	countyIndex := -1
+	for index, item := range sa.counties {
+		if item.ID == county.ID {
+			countyIndex = index
+		}
+	}
+	if countyIndex < 0 {
+		return errors.New("there is no a county for the provided id")
+	}
+	sa.counties[countyIndex] = copyCounty(county)
+
+	var remainingRules []*model.Rule
+	for _, item := range sa.rules {
+		if item.County.ID != county.ID {
+			remainingRules = append(remainingRules, item)
+		}
+	}
+	for _, item := range rules {
+		remainingRules = append(remainingRules, copyRule(item))
+	}
+	sa.rules = remainingRules
+
+	var remainingAccessRules []*model.AccessRule
+	for _, item := range sa.accessRules {
+		if item.County.ID != county.ID {
+			remainingAccessRules = append(remainingAccessRules, item)
+		}
+	}
+	if accessRule != nil {
+		remainingAccessRules = append(remainingAccessRules, copyAccessRule(accessRule))
+	}
+	sa.accessRules = remainingAccessRules
+
+	var remainingSymptomRules []*model.SymptomRule
+	for _, item := range sa.symptomRules {
+		if item.County.ID != county.ID {
+			remainingSymptomRules = append(remainingSymptomRules, item)
+		}
+	}
+	if symptomRule != nil {
+		remainingSymptomRules = append(remainingSymptomRules, copySymptomRule(symptomRule))
+	}
+	sa.symptomRules = remainingSymptomRules
+
+	for _, revision := range cRulesRevisions {
+		sa.publishConfigRevision(revision)
+	}
+
+	for _, entity := range locations {
+		location := copyLocation(entity)
+		location.Provider = model.Provider{ID: entity.Provider.ID}
+		location.County = model.County{ID: county.ID}
+		var avTests []model.TestType
+		for _, tt := range entity.AvailableTests {
+			avTests = append(avTests, model.TestType{ID: tt.ID})
+		}
+		location.AvailableTests = avTests
+
+		updated := false
+		for index, item := range sa.locations {
+			if item.ID == entity.ID {
+				//the exceptions, the wait time and the appointment settings are not changed
+				location.Exceptions = item.Exceptions
+				location.WaitTime = item.WaitTime
+				location.WaitTimeColor = item.WaitTimeColor
+				location.AppointmentSettings = item.AppointmentSettings
+				sa.locations[index] = location
+				updated = true
+			}
+		}
+		if !updated {
+			location.Exceptions = nil
+			location.WaitTime = nil
+			location.WaitTimeColor = nil
+			location.AppointmentSettings = nil
+			sa.locations = append(sa.locations, location)
+		}
+	}
+	return nil
+}
+
+//CreateGuideline creates a guidline
+func (sa *Adapter) CreateGuideline(ctx context.Context, countyID string, name string, description string, items []model.GuidelineItem) (*model.Guideline, error) {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("counties")
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	//1. find the county
+	county := sa.findCounty(countyID)
+	if county == nil {
+		return nil, errors.New("there is no a county for the provided id")
+	}
+
+	//2. create guideline
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+	guideline := model.Guideline{ID: id.String(), Name: name, Description: description, Items: copyGuidelineItems(items)}
+
+	//3. add the guideline to the county
+	county.Guidelines = append(county.Guidelines, guideline)
+
+	//4. return the inserted item
+	createdItem := &model.Guideline{ID: id.String(), Name: name, Description: description, Items: items}
+	return createdItem, nil
+}
+
+//FindGuideline finds a guideline
+func (sa *Adapter) FindGuideline(ctx context.Context, ID string) (*model.Guideline, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	for _, county := range sa.counties {
+		for _, gl := range county.Guidelines {
+			if gl.ID == ID {
+				return &model.Guideline{ID: gl.ID, Name: gl.Name, Description: gl.Description,
+					Items: copyGuidelineItems(gl.Items)}, nil
+			}
+		}
+	}
+	//not found
+	return nil, nil
+}
+
+//FindGuidelineByCountyID finds guidelines for the provided county id
+func (sa *Adapter) FindGuidelineByCountyID(ctx context.Context, countyID string) ([]*model.Guideline, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	county := sa.findCounty(countyID)
+	if county == nil {
+		//not found
+		return nil, nil
+	}
+
+	var resultList []*model.Guideline
+	for _, gl := range county.Guidelines {
+		item := &model.Guideline{ID: gl.ID, Name: gl.Name, Description: gl.Description,
+			Items: copyGuidelineItems(gl.Items)}
+		resultList = append(resultList, item)
+	}
+	return resultList, nil
+}
+
+//SaveGuideline saves a guideline
+func (sa *Adapter) SaveGuideline(ctx context.Context, guideline *model.Guideline) error {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("counties")
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for _, county := range sa.counties {
+		for index, gl := range county.Guidelines {
+			if gl.ID == guideline.ID {
+				gl.Name = guideline.Name
+				gl.Description = guideline.Description
+				gl.Items = copyGuidelineItems(guideline.Items)
+				county.Guidelines[index] = gl
+				return nil
+			}
+		}
+	}
+	//not found - the mongoDB adapter does not report it as an error
+	return nil
+}
+
+//DeleteGuideline deletes a guideline
+func (sa *Adapter) DeleteGuideline(ctx context.Context, ID string) error {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("counties")
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for _, county := range sa.counties {
+		for index, gl := range county.Guidelines {
+			if gl.ID == ID {
+				county.Guidelines = append(county.Guidelines[:index], county.Guidelines[index+1:]...)
+				return nil
+			}
+		}
+	}
+	return errors.New("there is no a guideline for id " + ID)
+}
+
+//CreateCountyStatus creates county status
+func (sa *Adapter) CreateCountyStatus(ctx context.Context, countyID string, name string, description string) (*model.CountyStatus, error) {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("counties")
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	//1. find the county
+	county := sa.findCounty(countyID)
+	if county == nil {
+		return nil, errors.New("there is no a county for the provided id")
+	}
+
+	//2. create county status
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+	countyStatus := model.CountyStatus{ID: id.String(), Name: name, Description: description}
+
+	//3. add the county status to the county
+	county.CountyStatuses = append(county.CountyStatuses, countyStatus)
+
+	//4. return the inserted item
+	return &countyStatus, nil
+}
+
+//FindCountyStatus finds county status by ID
+func (sa *Adapter) FindCountyStatus(ctx context.Context, ID string) (*model.CountyStatus, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	for _, county := range sa.counties {
+		for _, cs := range county.CountyStatuses {
+			if cs.ID == ID {
+				return &model.CountyStatus{ID: cs.ID, Name: cs.Name, Description: cs.Description}, nil
+			}
+		}
+	}
+	//not found
+	return nil, nil
+}
+
+//FindCountyStatusesByCountyID finds county statuses by county ID
+func (sa *Adapter) FindCountyStatusesByCountyID(ctx context.Context, countyID string) ([]*model.CountyStatus, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	county := sa.findCounty(countyID)
+	if county == nil {
+		//not found
+		return nil, nil
+	}
+
+	var resultList []*model.CountyStatus
+	for _, cs := range county.CountyStatuses {
+		item := &model.CountyStatus{ID: cs.ID, Name: cs.Name, Description: cs.Description}
+		resultList = append(resultList, item)
+	}
+	return resultList, nil
+}
+
+//SaveCountyStatus saves the county status
+func (sa *Adapter) SaveCountyStatus(ctx context.Context, entity *model.CountyStatus) error {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("counties")
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for _, county := range sa.counties {
+		for index, cs := range county.CountyStatuses {
+			if cs.ID == entity.ID {
+				county.CountyStatuses[index].Name = entity.Name
+				county.CountyStatuses[index].Description = entity.Description
+				return nil
+			}
+		}
+	}
+	//not found - the mongoDB adapter does not report it as an error
+	return nil
+}
+
+//DeleteCountyStatus deletes county status
+func (sa *Adapter) DeleteCountyStatus(ctx context.Context, ID string) error {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("counties")
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	//1. check if there are associated rules for this county status
+	for _, item := range sa.rules {
+		for _, rs := range item.ResultsStates {
+			if rs.CountyStatusID == ID {
+				return errors.New("there are associated test type rules for this county status")
+			}
+		}
+	}
+
+	//2. check if there are associated symptom rules for this county status
+	for _, item := range sa.symptomRules {
+		for _, sri := range item.Items {
+			if sri.CountyStatus.ID == ID {
+				return errors.New("there are associated symptom rules for this county status")
+			}
+		}
+	}
+
+	//3. check if there are associated access rules for this county status
+	for _, item := range sa.accessRules {
+		for _, arcs := range item.Rules {
+			if arcs.CountyStatusID == ID {
+				return errors.New("there are associated access rules for this county status")
+			}
+		}
+	}
+
+	//4. remove the county status from the county
+	for _, county := range sa.counties {
+		for index, cs := range county.CountyStatuses {
+			if cs.ID == ID {
+				county.CountyStatuses = append(county.CountyStatuses[:index], county.CountyStatuses[index+1:]...)
+				return nil
+			}
+		}
+	}
+	return errors.New("there is no a county status for id " + ID)
+}
+
+//ReadAllTestTypes reads all test types
+func (sa *Adapter) ReadAllTestTypes(ctx context.Context) ([]*model.TestType, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var resultList []*model.TestType
+	for _, item := range sa.testTypes {
+		resultList = append(resultList, copyTestType(item))
+	}
+	return resultList, nil
+}
+
+//CreateTestType creates a test type
+func (sa *Adapter) CreateTestType(ctx context.Context, name string, priority *int) (*model.TestType, error) {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("testtypes")
+
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	testType := model.TestType{ID: id.String(), Name: name, Priority: priority}
+	sa.testTypes = append(sa.testTypes, copyTestType(&testType))
+
+	//return the inserted item
+	return &testType, nil
+}
+
+//FindTestType finds a test type by ID
+func (sa *Adapter) FindTestType(ctx context.Context, ID string) (*model.TestType, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	testType := sa.findTestType(ID)
+	if testType == nil {
+		//not found
+		return nil, nil
+	}
+	return copyTestType(testType), nil
+}
+
+//FindTestTypesByIDs finds the test types for the provided ids
+func (sa *Adapter) FindTestTypesByIDs(ctx context.Context, ids []string) ([]*model.TestType, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var resultList []*model.TestType
+	for _, item := range sa.testTypes {
+		if containsString(ids, item.ID) {
+			resultList = append(resultList, copyTestType(item))
+		}
+	}
+	return resultList, nil
+}
+
+//SaveTestType saves the test type
+func (sa *Adapter) SaveTestType(ctx context.Context, entity *model.TestType) error {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("testtypes")
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	testType := sa.findTestType(entity.ID)
+	if testType == nil {
+		//not found
+		return errors.New("there is no a test type for the provided id")
+	}
+
+	//update the values
+	testType.Name = entity.Name
+	testType.Priority = copyInt(entity.Priority)
+	return nil
+}
+
+//DeleteTestType delete a test type
+func (sa *Adapter) DeleteTestType(ctx context.Context, ID string) error {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("testtypes")
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	//1. check if there are associated locations for this test type
+	for _, item := range sa.locations {
+		for _, tt := range item.AvailableTests {
+			if tt.ID == ID {
+				return errors.New("there are associated locations for this test type")
+			}
+		}
+	}
+
+	//2. check if there are associated county rules for this test type
+	for _, item := range sa.rules {
+		if item.TestType.ID == ID {
+			return errors.New("there are associated county rules for this test type")
+		}
+	}
+
+	//3. check if there are associated test type results for this test type
+	testType := sa.findTestType(ID)
+	if testType != nil && len(testType.Results) > 0 {
+		return errors.New("there are associated test type results for this test type")
+	}
+
+	//4. now we can delete the test type
+	for index, item := range sa.testTypes {
+		if item.ID == ID {
+			sa.testTypes = append(sa.testTypes[:index], sa.testTypes[index+1:]...)
+			return nil
+		}
+	}
+	return errors.New("there is no a test type for id " + ID)
+}
+
+//CreateTestTypeResult creates a test type result
+func (sa *Adapter) CreateTestTypeResult(ctx context.Context, testTypeID string, name string, nextStep string, nextStepOffset *int,
+	resultExpiresOffset *int) (*model.TestTypeResult, error) {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("testtypes")
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	//1. find the test type
+	testType := sa.findTestType(testTypeID)
+	if testType == nil {
+		return nil, errors.New("there is no a test type for the provided id")
+	}
+
+	//2. create test type result
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+	testTypeResult := model.TestTypeResult{ID: id.String(), Name: name, NextStep: nextStep,
+		NextStepOffset: copyInt(nextStepOffset), ResultExpiresOffset: copyInt(resultExpiresOffset)}
+
+	//3. add the test type result to the test type
+	testType.Results = append(testType.Results, testTypeResult)
+
+	//4. return the inserted item
+	createdItem := &model.TestTypeResult{ID: testTypeResult.ID, Name: name, NextStep: nextStep,
+		NextStepOffset: nextStepOffset, ResultExpiresOffset: resultExpiresOffset}
+	return createdItem, nil
+}
+
+//FindTestTypeResult finds a test type result by id
+func (sa *Adapter) FindTestTypeResult(ctx context.Context, ID string) (*model.TestTypeResult, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	for _, testType := range sa.testTypes {
+		for _, ttr := range testType.Results {
+			if ttr.ID == ID {
+				return copyTestTypeResult(ttr), nil
+			}
+		}
+	}
+	//not found
+	return nil, nil
+}
+
+//FindTestTypeResultsByTestTypeID finds all test type results for a test type
+func (sa *Adapter) FindTestTypeResultsByTestTypeID(ctx context.Context, testTypeID string) ([]*model.TestTypeResult, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	testType := sa.findTestType(testTypeID)
+	if testType == nil {
+		//not found
+		return nil, nil
+	}
+
+	var resultList []*model.TestTypeResult
+	for _, ttr := range testType.Results {
+		resultList = append(resultList, copyTestTypeResult(ttr))
+	}
+	return resultList, nil
+}
+
+//SaveTestTypeResult save the test type result
+func (sa *Adapter) SaveTestTypeResult(ctx context.Context, entity *model.TestTypeResult) error {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("testtypes")
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for _, testType := range sa.testTypes {
+		for index, ttr := range testType.Results {
+			if ttr.ID == entity.ID {
+				testType.Results[index] = *copyTestTypeResult(*entity)
+				return nil
+			}
+		}
+	}
+	//not found - the mongoDB adapter does not report it as an error
+	return nil
+}
+
+//DeleteTestTypeResult deletes a test type result
+func (sa *Adapter) DeleteTestTypeResult(ctx context.Context, ID string) error {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("testtypes")
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	//1. check if there are associated county rules for this test type result
+	for _, item := range sa.rules {
+		for _, rs := range item.ResultsStates {
+			if rs.TestTypeResultID == ID {
+				return errors.New("there are associated county rules for this test type result")
+			}
+		}
+	}
+
+	//2. remove the test type result from the test type
+	for _, testType := range sa.testTypes {
+		for index, ttr := range testType.Results {
+			if ttr.ID == ID {
+				testType.Results = append(testType.Results[:index], testType.Results[index+1:]...)
+				return nil
+			}
+		}
+	}
+	return errors.New("there is no a test type result for id " + ID)
+}
+
+//ReadAllRules reads all rules
+func (sa *Adapter) ReadAllRules(ctx context.Context) ([]*model.Rule, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var resultList []*model.Rule
+	for _, item := range sa.rules {
+		resultList = append(resultList, copyRule(item))
+	}
+	return resultList, nil
+}
+
+//FindRulesByCountyID finds the rules for a county
+func (sa *Adapter) FindRulesByCountyID(ctx context.Context, countyID string) ([]*model.Rule, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var resultList []*model.Rule
+	for _, item := range sa.rules {
+		if item.County.ID == countyID {
+			resultList = append(resultList, copyRule(item))
+		}
+	}
+	return resultList, nil
+}
+
+//FindRule finds a rule
+func (sa *Adapter) FindRule(ctx context.Context, ID string) (*model.Rule, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	for _, item := range sa.rules {
+		if item.ID == ID {
+			return copyRule(item), nil
+		}
+	}
+	//not found
+	return nil, nil
+}
+
+//FindRuleByCountyIDTestTypeID finds the rule for a county and test type
+func (sa *Adapter) FindRuleByCountyIDTestTypeID(ctx context.Context, countyID string, testTypeID string) (*model.Rule, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	for _, item := range sa.rules {
+		if item.County.ID == countyID && item.TestType.ID == testTypeID {
+			return copyRule(item), nil
+		}
+	}
+	//not found
+	return nil, nil
+}
+
+//CreateRule create a rule
+func (sa *Adapter) CreateRule(ctx context.Context, countyID string, testTypeID string, priority *int, resultsStates []model.TestTypeResultCountyStatus) (*model.Rule, error) {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("rules")
+
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	//1. create rule
+	rule := model.Rule{ID: id.String(), County: model.County{ID: countyID}, TestType: model.TestType{ID: testTypeID},
+		Priority: priority, ResultsStates: resultsStates}
+	sa.rules = append(sa.rules, copyRule(&rule))
+
+	//2. delete all statuses of the users
+	sa.estatuses = nil
+
+	return &rule, nil
+}
+
+//SaveRule saves a rule
+func (sa *Adapter) SaveRule(ctx context.Context, entity *model.Rule) error {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("rules")
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	//1. save
+	var rule *model.Rule
+	for _, item := range sa.rules {
+		if item.ID == entity.ID {
+			rule = item
+			break
+		}
+	}
+	if rule == nil {
+		return errors.New("there is no a rule for the provided id")
+	}
+	rule.Priority = copyInt(entity.Priority)
+	rule.ResultsStates = copyResultsStates(entity.ResultsStates)
+
+	//2. delete all statuses of the users
+	sa.estatuses = nil
+
+	return nil
+}
+
+//DeleteRule deletes a rule
+func (sa *Adapter) DeleteRule(ctx context.Context, ID string) error {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("rules")
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	//1. delete
+	index := -1
+	for i, item := range sa.rules {
+		if item.ID == ID {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return errors.New("there is no a rule for id " + ID)
+	}
+	sa.rules = append(sa.rules[:index], sa.rules[index+1:]...)
+
+	//2. delete all statuses of the users
+	sa.estatuses = nil
+
+	return nil
+}
+
+//ReadAllLocations reads all the locations
+func (sa *Adapter) ReadAllLocations(ctx context.Context) ([]*model.Location, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var resultList []*model.Location
+	for _, item := range sa.locations {
+		resultList = append(resultList, copyLocation(item))
+	}
+	return resultList, nil
+}
+
+//CreateLocation creates a location
+func (sa *Adapter) CreateLocation(ctx context.Context, providerID string, countyID string, name string, address1 string, address2 string, city string,
+	state string, zip string, country string, latitude float64, longitude float64, contact string, daysOfOperation []model.OperationDay,
+	url string, notes string, waitTimeColor *string, availableTests []string) (*model.Location, error) {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("locations")
+
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	var avTests []model.TestType
+	for _, ttID := range availableTests {
+		avTests = append(avTests, model.TestType{ID: ttID})
+	}
+	location := model.Location{ID: id.String(), Name: name, Address1: address1, Address2: address2, City: city,
+		State: state, ZIP: zip, Country: country, Latitude: latitude, Longitude: longitude, Timezone: "America/Chicago", Contact: contact,
+		DaysOfOperation: daysOfOperation, URL: url, Notes: notes, WaitTimeColor: waitTimeColor,
+		Provider: model.Provider{ID: providerID}, County: model.County{ID: countyID}, AvailableTests: avTests}
+	sa.locations = append(sa.locations, copyLocation(&location))
+
+	//return the inserted item
+	return &location, nil
+}
+
+//FindLocationsByProviderIDCountyID finds the locations for a provider and county
+func (sa *Adapter) FindLocationsByProviderIDCountyID(ctx context.Context, providerID string, countyID string) ([]*model.Location, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var resultList []*model.Location
+	for _, item := range sa.locations {
+		if item.Provider.ID == providerID && item.County.ID == countyID {
+			resultList = append(resultList, copyLocation(item))
+		}
+	}
+	return resultList, nil
+}
+
+//FindLocationsByCountyIDDeep finds the locations for a county - deep request!
+func (sa *Adapter) FindLocationsByCountyIDDeep(ctx context.Context, countyID string) ([]*model.Location, error) {
+	return sa.FindLocationsByCountiesDeep(ctx, []string{countyID})
+}
+
+//FindLocationsByCountiesDeep finds the locations for a list of county items - deep request!
+func (sa *Adapter) FindLocationsByCountiesDeep(ctx context.Context, countyIDs []string) ([]*model.Location, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var resultList []*model.Location
+	for _, item := range sa.locations {
+		if !containsString(countyIDs, item.County.ID) {
+			continue
+		}
+		//the same as $unwind - skip the locations without a provider
+		provider := sa.findProvider(item.Provider.ID)
+		if provider == nil {
+			continue
+		}
+		location := copyLocation(item)
+		location.Provider = model.Provider{ID: provider.ID, Name: provider.Name,
+			AvailableMechanisms: copyStrings(provider.AvailableMechanisms)}
+		resultList = append(resultList, location)
+	}
+	return resultList, nil
+}
+
+//FindLocationsNearby finds the locations within the radius in meters from the point, the nearest first - deep request!
+//The test type and the provider are optional filters, there is no limit if the limit is 0.
+func (sa *Adapter) FindLocationsNearby(ctx context.Context, latitude float64, longitude float64, radius float64,
+	testTypeID *string, providerID *string, limit int64) ([]*model.NearbyLocation, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var resultList []*model.NearbyLocation
+	for _, item := range sa.locations {
+		if providerID != nil && item.Provider.ID != *providerID {
+			continue
+		}
+		if testTypeID != nil && !hasTestType(item.AvailableTests, *testTypeID) {
+			continue
+		}
+		distance := sphereDistance(latitude, longitude, item.Latitude, item.Longitude)
+		if distance > radius {
+			continue
+		}
+		//the same as $unwind - skip the locations without a provider
+		provider := sa.findProvider(item.Provider.ID)
+		if provider == nil {
+			continue
+		}
+		location := copyLocation(item)
+		location.Provider = model.Provider{ID: provider.ID, Name: provider.Name,
+			AvailableMechanisms: copyStrings(provider.AvailableMechanisms)}
+		resultList = append(resultList, &model.NearbyLocation{Location: location, Distance: distance})
+	}
+	sort.SliceStable(resultList, func(i, j int) bool {
+		return resultList[i].Distance < resultList[j].Distance
+	})
+	if limit > 0 && int64(len(resultList)) > limit {
+		resultList = resultList[:limit]
+	}
+	return resultList, nil
+}
+
+//FindLocation finds a location by id
+func (sa *Adapter) FindLocation(ctx context.Context, ID string) (*model.Location, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	for _, item := range sa.locations {
+		if item.ID == ID {
+			return copyLocation(item), nil
+		}
+	}
+	//not found
+	return nil, nil
+}
+
+//SaveLocation save a location
+func (sa *Adapter) SaveLocation(ctx context.Context, entity *model.Location) error {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("locations")
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for index, item := range sa.locations {
+		if item.ID == entity.ID {
+			//provider and county cannot be changed
+			location := copyLocation(entity)
+			location.Provider = model.Provider{ID: item.Provider.ID}
+			location.County = model.County{ID: item.County.ID}
+			var avTests []model.TestType
+			for _, tt := range entity.AvailableTests {
+				avTests = append(avTests, model.TestType{ID: tt.ID})
+			}
+			location.AvailableTests = avTests
+			//the exceptions, the wait time and the appointment settings are changed only by their own update functions
+			location.Exceptions = item.Exceptions
+			location.WaitTime = item.WaitTime
+			location.AppointmentSettings = item.AppointmentSettings
+			sa.locations[index] = location
+			return nil
+		}
+	}
+	return errors.New("there is no a location for the provided id")
+}
+
+//UpdateLocationExceptions replaces the exceptions of a location, the other location fields are not changed
+func (sa *Adapter) UpdateLocationExceptions(ctx context.Context, ID string, exceptions []model.LocationException) error {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("locations")
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for _, item := range sa.locations {
+		if item.ID == ID {
+			item.Exceptions = append([]model.LocationException(nil), exceptions...)
+			return nil
+		}
+	}
+	return errors.New("there is no a location for the provided id")
+}
+
+//UpdateLocationWaitTime sets the latest reported wait time and the wait time color of a location, the other location fields are not changed
+func (sa *Adapter) UpdateLocationWaitTime(ctx context.Context, ID string, waitTime *model.WaitTimeReport, waitTimeColor *string) error {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("locations")
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for _, item := range sa.locations {
+		if item.ID == ID {
+			if waitTime != nil {
+				report := *waitTime
+				item.WaitTime = &report
+			} else {
+				item.WaitTime = nil
+			}
+			if waitTimeColor != nil {
+				color := *waitTimeColor
+				item.WaitTimeColor = &color
+			} else {
+				item.WaitTimeColor = nil
+			}
+			return nil
+		}
+	}
+	return errors.New("there is no a location for the provided id")
+}
+
+//CreateWaitTimeReport adds a wait time report to the history
+func (sa *Adapter) CreateWaitTimeReport(ctx context.Context, report *model.WaitTimeReport) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	item := *report
+	sa.waitTimeReports = append(sa.waitTimeReports, &item)
+	return nil
+}
+
+//FindWaitTimeReports finds the latest wait time reports of a location
+func (sa *Adapter) FindWaitTimeReports(ctx context.Context, locationID string, limit int64) ([]*model.WaitTimeReport, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var result []*model.WaitTimeReport
+	for _, item := range sa.waitTimeReports {
+		if item.LocationID == locationID {
+			report := *item
+			result = append(result, &report)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].DateCreated.After(result[j].DateCreated)
+	})
+	if limit > 0 && int64(len(result)) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+//UpdateLocationAppointmentSettings sets the appointment settings of a location, the other location fields are not changed
+func (sa *Adapter) UpdateLocationAppointmentSettings(ctx context.Context, ID string, settings *model.AppointmentSettings) error {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("locations")
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for _, item := range sa.locations {
+		if item.ID == ID {
+			if settings != nil {
+				value := *settings
+				item.AppointmentSettings = &value
+			} else {
+				item.AppointmentSettings = nil
+			}
+			return nil
+		}
+	}
+	return errors.New("there is no a location for the provided id")
+}
+
+//CreateAppointment creates an appointment if its slot is not full. It gives false if the slot is full. The user can have only one
+//booked appointment at a location.
+func (sa *Adapter) CreateAppointment(ctx context.Context, appointment *model.Appointment, capacity int) (bool, error) {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	if sa.bookedAppointments(appointment.LocationID, appointment.Start) >= capacity {
+		return false, nil
+	}
+	for _, item := range sa.appointments {
+		if item.UserID == appointment.UserID && item.LocationID == appointment.LocationID && item.Status == "booked" {
+			return false, errors.New("there is a booked appointment at this location, reschedule it instead")
+		}
+	}
+	item := *appointment
+	sa.appointments = append(sa.appointments, &item)
+	return true, nil
+}
+
+//RescheduleAppointment moves a booked appointment to the start and the end it has. It gives false if the new slot is full.
+func (sa *Adapter) RescheduleAppointment(ctx context.Context, appointment *model.Appointment, previousStart time.Time, capacity int) (bool, error) {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for _, item := range sa.appointments {
+		if item.ID == appointment.ID && item.Status == "booked" {
+			if sa.bookedAppointments(appointment.LocationID, appointment.Start) >= capacity {
+				return false, nil
+			}
+			item.Start = appointment.Start
+			item.End = appointment.End
+			item.ReminderSent = appointment.ReminderSent
+			item.DateUpdated = appointment.DateUpdated
+			return true, nil
+		}
+	}
+	return false, errors.New("there is no a booked appointment for the provided id")
+}
+
+//CancelAppointment cancels a booked appointment
+func (sa *Adapter) CancelAppointment(ctx context.Context, appointment *model.Appointment) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for _, item := range sa.appointments {
+		if item.ID == appointment.ID && item.Status == "booked" {
+			now := time.Now()
+			item.Status = "cancelled"
+			item.DateUpdated = &now
+			return nil
+		}
+	}
+	return errors.New("there is no a booked appointment for the provided id")
+}
+
+//FindAppointment finds an appointment by id
+func (sa *Adapter) FindAppointment(ctx context.Context, ID string) (*model.Appointment, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	for _, item := range sa.appointments {
+		if item.ID == ID {
+			appointment := *item
+			return &appointment, nil
+		}
+	}
+	//not found
+	return nil, nil
+}
+
+//FindUserAppointments finds all the appointments of a user, the earliest first
+func (sa *Adapter) FindUserAppointments(ctx context.Context, userID string) ([]*model.Appointment, error) {
+	return sa.findAppointments(func(item *model.Appointment) bool {
+		return item.UserID == userID
+	}), nil
+}
+
+//FindLocationAppointments finds the booked appointments of a location which start in the period, the earliest first
+func (sa *Adapter) FindLocationAppointments(ctx context.Context, locationID string, from time.Time, to time.Time) ([]*model.Appointment, error) {
+	return sa.findAppointments(func(item *model.Appointment) bool {
+		return item.LocationID == locationID && item.Status == "booked" && !item.Start.Before(from) && item.Start.Before(to)
+	}), nil
+}
+
+//FindAppointmentsToRemind finds the booked appointments which start in the period and the users have not been reminded for them
+func (sa *Adapter) FindAppointmentsToRemind(ctx context.Context, from time.Time, to time.Time) ([]*model.Appointment, error) {
+	return sa.findAppointments(func(item *model.Appointment) bool {
+		return item.Status == "booked" && !item.ReminderSent && !item.Start.Before(from) && item.Start.Before(to)
+	}), nil
+}
+
+//SetAppointmentStatus sets the status of a booked appointment
+func (sa *Adapter) SetAppointmentStatus(ctx context.Context, ID string, status string) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for _, item := range sa.appointments {
+		if item.ID == ID && item.Status == "booked" {
+			now := time.Now()
+			item.Status = status
+			item.DateUpdated = &now
+			return nil
+		}
+	}
+	return errors.New("there is no a booked appointment for the provided id")
+}
+
+//SetAppointmentReminderSent marks that the user has been reminded for the appointment. It gives false if it has been already marked.
+func (sa *Adapter) SetAppointmentReminderSent(ctx context.Context, ID string) (bool, error) {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for _, item := range sa.appointments {
+		if item.ID == ID {
+			if item.ReminderSent {
+				return false, nil
+			}
+			item.ReminderSent = true
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (sa *Adapter) findAppointments(match func(item *model.Appointment) bool) []*model.Appointment {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var result []*model.Appointment
+	for _, item := range sa.appointments {
+		if match(item) {
+			appointment := *item
+			result = append(result, &appointment)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Start.Before(result[j].Start)
+	})
+	return result
+}
+
+//bookedAppointments gives how many appointments are booked for the slot, the lock must be held
+func (sa *Adapter) bookedAppointments(locationID string, start time.Time) int {
+	count := 0
+	for _, item := range sa.appointments {
+		if item.LocationID == locationID && item.Status == "booked" && item.Start.Equal(start) {
+			count++
+		}
+	}
+	return count
+}
+
+//DeleteLocation deletes a location
+func (sa *Adapter) DeleteLocation(ctx context.Context, ID string) error {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("locations")
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for index, item := range sa.locations {
+		if item.ID == ID {
+			sa.locations = append(sa.locations[:index], sa.locations[index+1:]...)
+			return nil
+		}
+	}
+	return errors.New("there is no a location for id " + ID)
+}
+
+//FindSymptom finds a symptom
+func (sa *Adapter) FindSymptom(ctx context.Context, ID string) (*model.Symptom, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	for _, sg := range sa.symptomGroups {
+		for _, s := range sg.Symptoms {
+			if s.ID == ID {
+				return &model.Symptom{ID: s.ID, Name: s.Name}, nil
+			}
+		}
+	}
+	//not found
+	return nil, nil
+}
+
+//CreateSymptom creates a symptom
+func (sa *Adapter) CreateSymptom(ctx context.Context, name string, group string) (*model.Symptom, error) {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	//1. find the symptom group
+	var symptomGroup *model.SymptomGroup
+	for _, sg := range sa.symptomGroups {
+		if sg.Name == group {
+			symptomGroup = sg
+			break
+		}
+	}
+	if symptomGroup == nil {
+		return nil, errors.New("there is no a symptom group for the provided name")
+	}
+
+	//2. create a symptom
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+	symptom := model.Symptom{ID: id.String(), Name: name}
+
+	//3. add the symptom to the symtom group
+	symptomGroup.Symptoms = append(symptomGroup.Symptoms, symptom)
+
+	//4. return the inserted item
+	return &symptom, nil
+}
+
+//DeleteSymptom deletes a symptom
+func (sa *Adapter) DeleteSymptom(ctx context.Context, ID string) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for _, sg := range sa.symptomGroups {
+		for index, s := range sg.Symptoms {
+			if s.ID == ID {
+				sg.Symptoms = append(sg.Symptoms[:index], sg.Symptoms[index+1:]...)
+				return nil
+			}
+		}
+	}
+	return errors.New("there is no a symptom for id " + ID)
+}
+
+//SaveSymptom saves a symptom
+func (sa *Adapter) SaveSymptom(ctx context.Context, entity *model.Symptom) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for _, sg := range sa.symptomGroups {
+		for index, s := range sg.Symptoms {
+			if s.ID == entity.ID {
+				sg.Symptoms[index].Name = entity.Name
+				return nil
+			}
+		}
+	}
+	//not found - the mongoDB adapter does not report it as an error
+	return nil
+}
+
+//ReadAllSymptomGroups reads all symptom groups
+func (sa *Adapter) ReadAllSymptomGroups(ctx context.Context) ([]*model.SymptomGroup, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var resultList []*model.SymptomGroup
+	for _, sg := range sa.symptomGroups {
+		var symptoms []model.Symptom
+		for _, s := range sg.Symptoms {
+			symptoms = append(symptoms, model.Symptom{ID: s.ID, Name: s.Name})
+		}
+		resultList = append(resultList, &model.SymptomGroup{ID: sg.ID, Name: sg.Name, Symptoms: symptoms})
+	}
+
+	//sort by "name" //gr1 and gr2
+	sort.SliceStable(resultList, func(i, j int) bool {
+		return resultList[i].Name < resultList[j].Name
+	})
+	return resultList, nil
+}
+
+//ReadSymptoms reads all the symptoms
+func (sa *Adapter) ReadSymptoms(ctx context.Context, appVersion string) (*model.Symptoms, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	for _, item := range sa.symptoms {
+		if item.AppVersion == appVersion {
+			symptoms := *item
+			return &symptoms, nil
+		}
+	}
+	return nil, errNoDocuments
+}
+
+//ReadAllSymptoms reads the symptoms for all app versions and ranges
+func (sa *Adapter) ReadAllSymptoms(ctx context.Context) ([]*model.Symptoms, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	result := make([]*model.Symptoms, len(sa.symptoms))
+	for i, item := range sa.symptoms {
+		symptoms := *item
+		result[i] = &symptoms
+	}
+	return result, nil
+}
+
+//CreateOrUpdateSymptoms creates symptoms for the provided version or update them if already created
+func (sa *Adapter) CreateOrUpdateSymptoms(ctx context.Context, appVersion string, items string) (*bool, error) {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	create := true
+	for _, item := range sa.symptoms {
+		if item.AppVersion == appVersion {
+			item.Items = items
+			create = false //modified
+			return &create, nil
+		}
+	}
+	sa.symptoms = append(sa.symptoms, &model.Symptoms{AppVersion: appVersion, Items: items})
+	return &create, nil
+}
+
+//ReadAllSymptomRules reads all the symptom rules
+func (sa *Adapter) ReadAllSymptomRules(ctx context.Context) ([]*model.SymptomRule, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var resultList []*model.SymptomRule
+	for _, item := range sa.symptomRules {
+		resultList = append(resultList, copySymptomRule(item))
+	}
+	return resultList, nil
+}
+
+//CreateSymptomRule creates a symptom rule
+func (sa *Adapter) CreateSymptomRule(ctx context.Context, countyID string, gr1Count int, gr2Count int, items []model.SymptomRuleItem) (*model.SymptomRule, error) {
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	//1. create symptom rule - the county id is unique
+	for _, item := range sa.symptomRules {
+		if item.County.ID == countyID {
+			return nil, errors.New("there is already a symptom rule for county " + countyID)
+		}
+	}
+	symptomRule := model.SymptomRule{ID: id.String(), County: model.County{ID: countyID},
+		Gr1Count: gr1Count, Gr2Count: gr2Count, Items: items}
+	sa.symptomRules = append(sa.symptomRules, copySymptomRule(&symptomRule))
+
+	//2. delete all statuses of the users
+	sa.estatuses = nil
+
+	return &symptomRule, nil
+}
+
+//FindSymptomRule finds a symptom rule
+func (sa *Adapter) FindSymptomRule(ctx context.Context, ID string) (*model.SymptomRule, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	for _, item := range sa.symptomRules {
+		if item.ID == ID {
+			return copySymptomRule(item), nil
+		}
+	}
+	//not found
+	return nil, nil
+}
+
+//FindSymptomRuleByCountyID finds a symptom rule by county id
+func (sa *Adapter) FindSymptomRuleByCountyID(ctx context.Context, countyID string) (*model.SymptomRule, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	for _, item := range sa.symptomRules {
+		if item.County.ID == countyID {
+			return copySymptomRule(item), nil
+		}
+	}
+	//not found
+	return nil, nil
+}
+
+//SaveSymptomRule saves a symptom rule
+func (sa *Adapter) SaveSymptomRule(ctx context.Context, entity *model.SymptomRule) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	//1. save
+	index := -1
+	for i, item := range sa.symptomRules {
+		if item.ID == entity.ID {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return errors.New("there is no a symptom rule for the provided id")
+	}
+	sa.symptomRules[index] = copySymptomRule(entity)
+
+	//2. delete all statuses of the users
+	sa.estatuses = nil
+
+	return nil
+}
+
+//DeleteSymptomRule deletes a symptom rule
+func (sa *Adapter) DeleteSymptomRule(ctx context.Context, ID string) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	//1. delete
+	index := -1
+	for i, item := range sa.symptomRules {
+		if item.ID == ID {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return errors.New("there is no a symptom rule for id " + ID)
+	}
+	sa.symptomRules = append(sa.symptomRules[:index], sa.symptomRules[index+1:]...)
+
+	//2. delete all statuses of the users
+	sa.estatuses = nil
+
+	return nil
+}
+
+//FindCRulesByCountyID finds the rules for county
+func (sa *Adapter) FindCRulesByCountyID(ctx context.Context, appVersion string, countyID string) (*model.CRules, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	for _, item := range sa.cRules {
+		if item.AppVersion == appVersion && item.CountyID == countyID {
+			cRules := *item
+			return &cRules, nil
+		}
+	}
+	return nil, errNoDocuments
+}
+
+//FindAllCRulesByCountyID finds the crules for all app versions for a county
+func (sa *Adapter) FindAllCRulesByCountyID(ctx context.Context, countyID string) ([]*model.CRules, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var result []*model.CRules
+	for _, item := range sa.cRules {
+		if item.CountyID == countyID {
+			cRules := *item
+			result = append(result, &cRules)
+		}
+	}
+	return result, nil
+}
+
+//CreateOrUpdateCRules creates crule or update it if already created
+func (sa *Adapter) CreateOrUpdateCRules(ctx context.Context, appVersion string, countyID string, data string) (*bool, error) {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("crules")
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	create := true
+	for _, item := range sa.cRules {
+		if item.AppVersion == appVersion && item.CountyID == countyID {
+			item.Data = data
+			create = false //modified
+			return &create, nil
+		}
+	}
+	sa.cRules = append(sa.cRules, &model.CRules{AppVersion: appVersion, CountyID: countyID, Data: data})
+	return &create, nil
+}
+
+//FindConfigRevisions finds the revisions for the crules of a county or for the symptoms, the latest are first
+func (sa *Adapter) FindConfigRevisions(ctx context.Context, configType string, appVersion string, countyID string) ([]*model.ConfigRevision, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	result := []*model.ConfigRevision{}
+	for _, item := range sa.configRevisions {
+		if item.Type == configType && item.AppVersion == appVersion && item.CountyID == countyID {
+			result = append(result, copyConfigRevision(item))
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Number > result[j].Number
+	})
+	return result, nil
+}
+
+//SaveConfigRevision creates the revision or replaces it if already created
+func (sa *Adapter) SaveConfigRevision(ctx context.Context, revision *model.ConfigRevision) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	sa.saveConfigRevision(revision)
+	return nil
+}
+
+//PublishConfigRevision archives the published revision, saves the provided one and sets its data to the crules or the symptoms.
+//It gives true if the crules or the symptoms are created.
+func (sa *Adapter) PublishConfigRevision(ctx context.Context, revision *model.ConfigRevision) (*bool, error) {
+	if revision.Type != "crules" && revision.Type != "symptoms" {
+		return nil, errors.New("not supported revision type " + revision.Type)
+	}
+	if revision.Type == "crules" {
+		//there is no change stream so notify directly
+		defer sa.notifyChanged("crules")
+	}
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	create := sa.publishConfigRevision(revision)
+	return &create, nil
+}
+
+//publishConfigRevision publishes the revision under the lock, it gives true if the crules or the symptoms are created
+func (sa *Adapter) publishConfigRevision(revision *model.ConfigRevision) bool {
+	for _, item := range sa.configRevisions {
+		if item.Type == revision.Type && item.AppVersion == revision.AppVersion && item.CountyID == revision.CountyID && item.Status == "published" {
+			item.Status = "archived"
+		}
+	}
+	sa.saveConfigRevision(revision)
+
+	create := true
+	if revision.Type == "crules" {
+		for _, item := range sa.cRules {
+			if item.AppVersion == revision.AppVersion && item.CountyID == revision.CountyID {
+				item.Data = revision.Data
+				create = false
+			}
+		}
+		if create {
+			sa.cRules = append(sa.cRules, &model.CRules{AppVersion: revision.AppVersion, CountyID: revision.CountyID, Data: revision.Data})
+		}
+	} else {
+		for _, item := range sa.symptoms {
+			if item.AppVersion == revision.AppVersion {
+				item.Items = revision.Data
+				create = false
+			}
+		}
+		if create {
+			sa.symptoms = append(sa.symptoms, &model.Symptoms{AppVersion: revision.AppVersion, Items: revision.Data})
+		}
+	}
+	return create
+}
+
+func (sa *Adapter) saveConfigRevision(revision *model.ConfigRevision) {
+	item := copyConfigRevision(revision)
+	for index, current := range sa.configRevisions {
+		if current.ID == item.ID {
+			sa.configRevisions[index] = item
+			return
+		}
+	}
+	sa.configRevisions = append(sa.configRevisions, item)
+}
+
+//FindConfigSchemas finds the config schemas for an app version or all of them if the app version is not provided
+func (sa *Adapter) FindConfigSchemas(ctx context.Context, appVersion *string) ([]*model.ConfigSchema, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	result := []*model.ConfigSchema{}
+	for _, item := range sa.configSchemas {
+		if appVersion == nil || item.AppVersion == *appVersion {
+			result = append(result, copyConfigSchema(item))
+		}
+	}
+	return result, nil
+}
+
+//FindConfigSchema finds the config schema for a type and an app version
+func (sa *Adapter) FindConfigSchema(ctx context.Context, configType string, appVersion string) (*model.ConfigSchema, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	for _, item := range sa.configSchemas {
+		if item.Type == configType && item.AppVersion == appVersion {
+			return copyConfigSchema(item), nil
+		}
+	}
+	return nil, nil
+}
+
+//SaveConfigSchema creates the config schema or replaces it if already created
+func (sa *Adapter) SaveConfigSchema(ctx context.Context, schema *model.ConfigSchema) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	item := copyConfigSchema(schema)
+	for index, current := range sa.configSchemas {
+		if current.ID == item.ID {
+			sa.configSchemas[index] = item
+			return nil
+		}
+	}
+	sa.configSchemas = append(sa.configSchemas, item)
+	return nil
+}
+
+//DeleteConfigSchema deletes a config schema
+func (sa *Adapter) DeleteConfigSchema(ctx context.Context, ID string) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for index, item := range sa.configSchemas {
+		if item.ID == ID {
+			sa.configSchemas = append(sa.configSchemas[:index], sa.configSchemas[index+1:]...)
+			return nil
+		}
+	}
+	return errors.New("error occured while deleting a config schema with id " + ID)
+}
+
+//CreateTraceReports creates trace reports items
+func (sa *Adapter) CreateTraceReports(ctx context.Context, items []model.TraceExposure) (int, error) {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for _, item := range items {
+		item.Expirestamp = copyInt64(item.Expirestamp)
+		sa.traceExposures = append(sa.traceExposures, item)
+	}
+	return len(items), nil
+}
+
+//ReadTraceExposures reads the exposures
+func (sa *Adapter) ReadTraceExposures(ctx context.Context, timestamp *int64, dateAdded *int64) ([]model.TraceExposure, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var result []model.TraceExposure
+	for _, item := range sa.traceExposures {
+		if timestamp != nil && item.Timestamp < *timestamp {
+			continue
+		}
+		if dateAdded != nil && item.DateAdded < *dateAdded {
+			continue
+		}
+		item.Expirestamp = copyInt64(item.Expirestamp)
+		result = append(result, item)
+	}
+
+	//sort by "timestamp"
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Timestamp < result[j].Timestamp
+	})
+	return result, nil
+}
+
+//FindManualTestsByCountyIDDeep find the manual test for a county
+func (sa *Adapter) FindManualTestsByCountyIDDeep(ctx context.Context, countyID string, status *string) ([]*model.EManualTest, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var locationIDs []string
+	if countyID != "all" {
+		//we need to filter by county
+		for _, item := range sa.locations {
+			if item.County.ID == countyID {
+				locationIDs = append(locationIDs, item.ID)
+			}
+		}
+		if len(locationIDs) == 0 {
+			return nil, errors.New("there is no any location for this county")
+		}
+	}
+
+	var resultList []*model.EManualTest
+	for _, item := range sa.manualTests {
+		if countyID != "all" {
+			inCounty := item.CountyID != nil && *item.CountyID == countyID
+			inLocation := item.LocationID != nil && containsString(locationIDs, *item.LocationID)
+			if !inCounty && !inLocation {
+				continue
+			}
+		}
+		if status != nil && item.Status != *status {
+			continue
+		}
+		//the same as $unwind - skip the manual tests without a user
+		user := sa.findUser(item.User.ID)
+		if user == nil {
+			continue
+		}
+
+		mUser := model.User{ID: user.ID, UUID: user.UUID, PublicKey: user.PublicKey,
+			Consent: user.Consent, ExposureNotification: user.ExposureNotification,
+			EncryptedKey: user.EncryptedKey, EncryptedBlob: user.EncryptedBlob}
+		mt := model.EManualTest{ID: item.ID, HistoryID: item.HistoryID, LocationID: item.LocationID, CountyID: item.CountyID,
+			EncryptedKey: item.EncryptedKey, EncryptedBlob: item.EncryptedBlob,
+			Status: item.Status, Date: item.Date, User: mUser}
+		resultList = append(resultList, &mt)
+	}
+
+	//sort by "date_created"
+	sort.SliceStable(resultList, func(i, j int) bool {
+		return resultList[i].Date.After(resultList[j].Date)
+	})
+	return resultList, nil
+}
+
+//FindManualTestsByUserID finds the manual tests of the user with their images
+func (sa *Adapter) FindManualTestsByUserID(ctx context.Context, userID string) ([]*model.EManualTest, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var resultList []*model.EManualTest
+	for _, item := range sa.manualTests {
+		if item.User.ID != userID {
+			continue
+		}
+		mt := *item
+		mt.User = model.User{ID: item.User.ID}
+		resultList = append(resultList, &mt)
+	}
+
+	//sort by "date_created"
+	sort.SliceStable(resultList, func(i, j int) bool {
+		return resultList[i].Date.After(resultList[j].Date)
+	})
+	return resultList, nil
+}
+
+//FindManualTestImage finds the manual test image
+func (sa *Adapter) FindManualTestImage(ctx context.Context, ID string) (*string, *string, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	for _, item := range sa.manualTests {
+		if item.ID == ID {
+			key := item.EncryptedImageKey
+			blob := item.EncryptedImageBlob
+			return &key, &blob, nil
+		}
+	}
+	//not found
+	return nil, nil, nil
+}
+
+//ProcessManualTest processes manual test
+func (sa *Adapter) ProcessManualTest(ctx context.Context, ID string, status string, encryptedKey *string, encryptedBlob *string) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	//1. find the manual test
+	mtIndex := -1
+	for index, item := range sa.manualTests {
+		if item.ID == ID {
+			mtIndex = index
+			break
+		}
+	}
+	if mtIndex == -1 {
+		return errNoDocuments
+	}
+	mt := sa.manualTests[mtIndex]
+
+	if status != "verified" {
+		//2. update the manual test status
+		mt.Status = status
+		return nil
+	}
+
+	//3. find the history
+	var history *model.EHistory
+	for _, item := range sa.ehistories {
+		if item.ID == mt.HistoryID {
+			history = item
+			break
+		}
+	}
+	if history == nil {
+		return errNoDocuments
+	}
+	if encryptedKey == nil || encryptedBlob == nil {
+		return errors.New("encrypted key and blob are required for a verified manual test")
+	}
+
+	//4. delete the manual test and update the history
+	sa.manualTests = append(sa.manualTests[:mtIndex], sa.manualTests[mtIndex+1:]...)
+
+	history.Type = "verified_manual_test"
+	history.EncryptedKey = *encryptedKey
+	history.EncryptedBlob = *encryptedBlob
+	//it is encrypted for the current user key
+	if user := sa.findUser(history.UserID); user != nil {
+		history.KeyVersion = user.KeyVersion
+	}
+
+	//5. remove the status of the user
+	var estatuses []*model.EStatus
+	for _, item := range sa.estatuses {
+		if item.UserID != history.UserID {
+			estatuses = append(estatuses, item)
+		}
+	}
+	sa.estatuses = estatuses
+
+	return nil
+}
+
+//ReadAllAccessRules reads all access rules
+func (sa *Adapter) ReadAllAccessRules(ctx context.Context) ([]*model.AccessRule, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var resultList []*model.AccessRule
+	for _, item := range sa.accessRules {
+		resultList = append(resultList, copyAccessRule(item))
+	}
+	return resultList, nil
+}
+
+//CreateAccessRule creates an access rule
+func (sa *Adapter) CreateAccessRule(ctx context.Context, countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error) {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("accessrules")
+
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	//1. validate the input data
+	err = sa.validateAccessRuleData(countyID, rules)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range sa.accessRules {
+		if item.County.ID == countyID {
+			return nil, errors.New("there is already an access rule for county " + countyID)
+		}
+	}
+
+	//2. create access rule
+	accessRule := model.AccessRule{ID: id.String(), County: model.County{ID: countyID}, Rules: rules}
+	sa.accessRules = append(sa.accessRules, copyAccessRule(&accessRule))
+
+	return &accessRule, nil
+}
+
+//UpdateAccessRule updates an access rule
+func (sa *Adapter) UpdateAccessRule(ctx context.Context, ID string, countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error) {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("accessrules")
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	//1. find the access rule item
+	index := -1
+	for i, item := range sa.accessRules {
+		if item.ID == ID {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return nil, errors.New("there is no an access rule for the provided id")
+	}
+
+	//2. validate the input data
+	err := sa.validateAccessRuleData(countyID, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	//3. update the access rule
+	accessRule := model.AccessRule{ID: ID, County: model.County{ID: countyID}, Rules: rules}
+	sa.accessRules[index] = copyAccessRule(&accessRule)
+
+	return &accessRule, nil
+}
+
+//FindAccessRuleByCountyID finds the access rule for a specific county
+func (sa *Adapter) FindAccessRuleByCountyID(ctx context.Context, countyID string) (*model.AccessRule, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	for _, item := range sa.accessRules {
+		if item.County.ID == countyID {
+			return copyAccessRule(item), nil
+		}
+	}
+	//not found
+	return nil, nil
+}
+
+//DeleteAccessRule deletes an access rule
+func (sa *Adapter) DeleteAccessRule(ctx context.Context, ID string) error {
+	//there is no change stream so notify directly
+	defer sa.notifyChanged("accessrules")
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for index, item := range sa.accessRules {
+		if item.ID == ID {
+			sa.accessRules = append(sa.accessRules[:index], sa.accessRules[index+1:]...)
+			return nil
+		}
+	}
+	return errors.New("there is no an access rule for id " + ID)
+}
+
+//FindExternalUserIDsByTestsOrderNumbers finds the external users ids for the tests orders numbers
+func (sa *Adapter) FindExternalUserIDsByTestsOrderNumbers(ctx context.Context, orderNumbers []string) (map[string]*string, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	mapData := make(map[string]*string)
+	for _, item := range sa.ctests {
+		if item.OrderNumber == nil || !containsString(orderNumbers, *item.OrderNumber) {
+			continue
+		}
+		user := sa.findUser(item.UserID)
+		if user == nil {
+			continue
+		}
+		externalID := user.ExternalID
+		mapData[*item.OrderNumber] = &externalID
+	}
+	if len(mapData) == 0 {
+		//not found
+		return nil, nil
+	}
+	return mapData, nil
+}
+
+//CreateOrUpdateUINOverride creates a new uin override entity or updates it if already created
+func (sa *Adapter) CreateOrUpdateUINOverride(ctx context.Context, uin string, interval int, category *string, expiration *time.Time) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	uinOverride := model.UINOverride{UIN: uin, Interval: interval, Category: category, Expiration: expiration}
+	for index, item := range sa.uinOverrides {
+		if item.UIN == uin {
+			sa.uinOverrides[index] = &uinOverride
+			return nil
+		}
+	}
+	sa.uinOverrides = append(sa.uinOverrides, &uinOverride)
+	return nil
+}
+
+//FindUINOverride finds the uin override for the provided uin. It makes additional check for the expiration
+func (sa *Adapter) FindUINOverride(ctx context.Context, uin string) (*model.UINOverride, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	now := time.Now()
+	for _, item := range sa.uinOverrides {
+		if item.UIN == uin && (item.Expiration == nil || !item.Expiration.Before(now)) {
+			uinOverride := *item
+			return &uinOverride, nil
+		}
+	}
+	//not found
+	return nil, nil
+}
+
+//FindUINOverrides finds the uin override for the provided uin. If uin is nil then it gives all
+func (sa *Adapter) FindUINOverrides(ctx context.Context, uin *string, sortBy *string) ([]*model.UINOverride, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var result []*model.UINOverride
+	for _, item := range sa.uinOverrides {
+		if uin != nil && item.UIN != *uin {
+			continue
+		}
+		uinOverride := *item
+		result = append(result, &uinOverride)
+	}
+
+	// sort by if provided
+	if sortBy != nil {
+		sort.SliceStable(result, func(i, j int) bool {
+			return lessUINOverride(result[i], result[j], *sortBy)
+		})
+	}
+	return result, nil
+}
+
+//CreateUINOverride creates a new uin override entity
+func (sa *Adapter) CreateUINOverride(ctx context.Context, uin string, interval int, category *string, expiration *time.Time) (*model.UINOverride, error) {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	//the uin is unique
+	for _, item := range sa.uinOverrides {
+		if item.UIN == uin {
+			return nil, errors.New("duplicate uin override for uin " + uin)
+		}
+	}
+	uinOverride := model.UINOverride{UIN: uin, Interval: interval, Category: category, Expiration: expiration}
+	item := uinOverride
+	sa.uinOverrides = append(sa.uinOverrides, &item)
+
+	return &uinOverride, nil
+}
+
+//UpdateUINOverride updates uin override entity
+func (sa *Adapter) UpdateUINOverride(ctx context.Context, uin string, interval int, category *string, expiration *time.Time) (*string, error) {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	var matchedCount, modifiedCount int
+	for index, item := range sa.uinOverrides {
+		if item.UIN == uin {
+			matchedCount++
+			uinOverride := model.UINOverride{UIN: uin, Interval: interval, Category: category, Expiration: expiration}
+			if item.Interval != interval || !equalStringPointers(item.Category, category) || !equalTimePointers(item.Expiration, expiration) {
+				modifiedCount++
+			}
+			sa.uinOverrides[index] = &uinOverride
+			break
+		}
+	}
+
+	res := fmt.Sprintf("%d matched, %d modified", matchedCount, modifiedCount)
+	return &res, nil
+}
+
+//DeleteUINOverride deletes uin override entity
+func (sa *Adapter) DeleteUINOverride(ctx context.Context, uin string) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	for index, item := range sa.uinOverrides {
+		if item.UIN == uin {
+			sa.uinOverrides = append(sa.uinOverrides[:index], sa.uinOverrides[index+1:]...)
+			return nil
+		}
+	}
+	return errors.New("there is no a uin override for uin " + uin)
+}
+
+//FindUINBuildingAccess finds the UIN buinding access for the provided uin
+func (sa *Adapter) FindUINBuildingAccess(ctx context.Context, uin string) (*model.UINBuildingAccess, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	for _, item := range sa.uinBuildingAccess {
+		if item.UIN == uin {
+			uinBuildingAccess := *item
+			return &uinBuildingAccess, nil
+		}
+	}
+	return nil, errNoDocuments
+}
+
+//FindUINBuildingAccessByUINs finds the building access for the provided uins
+func (sa *Adapter) FindUINBuildingAccessByUINs(ctx context.Context, uins []string) ([]*model.UINBuildingAccess, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var result []*model.UINBuildingAccess
+	for _, item := range sa.uinBuildingAccess {
+		if containsString(uins, item.UIN) {
+			uinBuildingAccess := *item
+			result = append(result, &uinBuildingAccess)
+		}
+	}
+	return result, nil
+}
+
+//CreateOrUpdateUINBuildingAccess creates UIN building access or update it if already created
+func (sa *Adapter) CreateOrUpdateUINBuildingAccess(ctx context.Context, uin string, date time.Time, access string) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	uinBuildingAccess := model.UINBuildingAccess{UIN: uin, Date: date, Access: access}
+	for index, item := range sa.uinBuildingAccess {
+		if item.UIN == uin {
+			sa.uinBuildingAccess[index] = &uinBuildingAccess
+			return nil
+		}
+	}
+	sa.uinBuildingAccess = append(sa.uinBuildingAccess, &uinBuildingAccess)
+	return nil
+}
+
+//ReadRetentionPolicies reads the retention policies
+func (sa *Adapter) ReadRetentionPolicies(ctx context.Context) ([]*model.RetentionPolicy, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	var resultList []*model.RetentionPolicy
+	for _, item := range sa.retentionPolicies {
+		policy := *item
+		resultList = append(resultList, &policy)
+	}
+	return resultList, nil
+}
+
+//SaveRetentionPolicy creates the retention policy for the entity or replaces it if already created
+func (sa *Adapter) SaveRetentionPolicy(ctx context.Context, policy *model.RetentionPolicy) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	item := *policy
+	for index, current := range sa.retentionPolicies {
+		if current.Entity == policy.Entity {
+			sa.retentionPolicies[index] = &item
+			return nil
+		}
+	}
+	sa.retentionPolicies = append(sa.retentionPolicies, &item)
+	return nil
+}
+
+//SaveErasureReceipt creates the erasure receipt or replaces it if already created
+func (sa *Adapter) SaveErasureReceipt(ctx context.Context, receipt *model.ErasureReceipt) error {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	item := copyErasureReceipt(receipt)
+	for index, current := range sa.erasureReceipts {
+		if current.ID == receipt.ID {
+			sa.erasureReceipts[index] = item
+			return nil
+		}
+	}
+	sa.erasureReceipts = append(sa.erasureReceipts, item)
+	return nil
+}
+
+//FindErasureReceipt finds an erasure receipt
+func (sa *Adapter) FindErasureReceipt(ctx context.Context, ID string) (*model.ErasureReceipt, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	for _, item := range sa.erasureReceipts {
+		if item.ID == ID {
+			return copyErasureReceipt(item), nil
+		}
+	}
+	//not found
+	return nil, nil
+}
+
+//FindErasureReceiptsBySubjectHash finds the erasure receipts for a subject, the latest are first
+func (sa *Adapter) FindErasureReceiptsBySubjectHash(ctx context.Context, subjectHash string) ([]*model.ErasureReceipt, error) {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	result := []*model.ErasureReceipt{}
+	for i := len(sa.erasureReceipts) - 1; i >= 0; i-- {
+		if sa.erasureReceipts[i].SubjectHash == subjectHash {
+			result = append(result, copyErasureReceipt(sa.erasureReceipts[i]))
+		}
+	}
+	return result, nil
+}
+
+//DeleteEHistoriesOlderThan deletes the history items with date before the provided one
+func (sa *Adapter) DeleteEHistoriesOlderThan(ctx context.Context, date time.Time) (int64, error) {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	var count int64
+	var remaining []*model.EHistory
+	for _, item := range sa.ehistories {
+		if item.Date.Before(date) {
+			count++
+			continue
+		}
+		remaining = append(remaining, item)
+	}
+	sa.ehistories = remaining
+	return count, nil
+}
+
+//DeleteProcessedCTestsOlderThan deletes the processed ctests created before the provided date
+func (sa *Adapter) DeleteProcessedCTestsOlderThan(ctx context.Context, date time.Time) (int64, error) {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	var count int64
+	var remaining []*model.CTest
+	for _, item := range sa.ctests {
+		if item.Processed && item.DateCreated.Before(date) {
+			count++
+			continue
+		}
+		remaining = append(remaining, item)
+	}
+	sa.ctests = remaining
+	return count, nil
+}
+
+//ClearManualTestsImagesOlderThan removes the images of the manual tests created before the provided date. The manual tests are kept.
+func (sa *Adapter) ClearManualTestsImagesOlderThan(ctx context.Context, date time.Time) (int64, error) {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	var count int64
+	for _, item := range sa.manualTests {
+		if item.Date.Before(date) && len(item.EncryptedImageBlob) > 0 {
+			item.EncryptedImageKey = ""
+			item.EncryptedImageBlob = ""
+			count++
+		}
+	}
+	return count, nil
+}
+
+//DeleteTraceExposuresExpiredBefore deletes the trace exposures with expirestamp before the provided one
+func (sa *Adapter) DeleteTraceExposuresExpiredBefore(ctx context.Context, expirestamp int64) (int64, error) {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	var count int64
+	var remaining []model.TraceExposure
+	for _, item := range sa.traceExposures {
+		if item.Expirestamp != nil && *item.Expirestamp < expirestamp {
+			count++
+			continue
+		}
+		remaining = append(remaining, item)
+	}
+	sa.traceExposures = remaining
+	return count, nil
+}
+
+//DeleteUINOverridesExpiredBefore deletes the uin overrides with expiration before the provided date
+func (sa *Adapter) DeleteUINOverridesExpiredBefore(ctx context.Context, date time.Time) (int64, error) {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	var count int64
+	var remaining []*model.UINOverride
+	for _, item := range sa.uinOverrides {
+		if item.Expiration != nil && item.Expiration.Before(date) {
+			count++
+			continue
+		}
+		remaining = append(remaining, item)
+	}
+	sa.uinOverrides = remaining
+	return count, nil
+}
+
+//DeleteWaitTimeReportsOlderThan deletes the wait time reports created before the provided date. The latest report of a location is kept in the location.
+func (sa *Adapter) DeleteWaitTimeReportsOlderThan(ctx context.Context, date time.Time) (int64, error) {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	var count int64
+	var remaining []*model.WaitTimeReport
+	for _, item := range sa.waitTimeReports {
+		if item.DateCreated.Before(date) {
+			count++
+			continue
+		}
+		remaining = append(remaining, item)
+	}
+	sa.waitTimeReports = remaining
+	return count, nil
+}
+
+//DeleteAppointmentsOlderThan deletes the appointments which start before the provided date
+func (sa *Adapter) DeleteAppointmentsOlderThan(ctx context.Context, date time.Time) (int64, error) {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	var count int64
+	var remaining []*model.Appointment
+	for _, item := range sa.appointments {
+		if item.Start.Before(date) {
+			count++
+			continue
+		}
+		remaining = append(remaining, item)
+	}
+	sa.appointments = remaining
+	return count, nil
+}
+
+func (sa *Adapter) createCTest(providerID string, userID string, keyVersion int, encryptedKey string, encryptedBlob string, processed bool, orderNumber *string) (*model.CTest, error) {
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+	cTest := model.CTest{ID: id.String(), ProviderID: providerID, UserID: userID, EncryptedKey: encryptedKey, EncryptedBlob: encryptedBlob,
+		KeyVersion: keyVersion, Processed: processed, OrderNumber: orderNumber, DateCreated: time.Now()}
+	item := cTest
+	sa.ctests = append(sa.ctests, &item)
+	return &cTest, nil
+}
+
+func (sa *Adapter) validateAccessRuleData(countyID string, rules []model.AccessRuleCountyStatus) error {
+	//1. validate the county id
+	county := sa.findCounty(countyID)
+	if county == nil {
+		return errors.New("there is no a county for the provided id")
+	}
+
+	//2. validate the county statuses ids
+	if len(county.CountyStatuses) == 0 {
+		return errors.New("there is no county statuses for this county")
+	}
+	for _, r := range rules {
+		contains := false
+		for _, cs := range county.CountyStatuses {
+			if cs.ID == r.CountyStatusID {
+				contains = true
+				break
+			}
+		}
+		if !contains {
+			return errors.New("there is invalid county status id")
+		}
+	}
+	return nil
+}
+
+func (sa *Adapter) findUser(ID string) *model.User {
+	for _, item := range sa.users {
+		if item.ID == ID {
+			return item
+		}
+	}
+	return nil
+}
+
+func (sa *Adapter) findUserByExternalID(externalID string) *model.User {
+	for _, item := range sa.users {
+		if item.ExternalID == externalID {
+			return item
+		}
+	}
+	return nil
+}
+
+func (sa *Adapter) notifyChanged(collection string) {
+	if sa.listener == nil {
+		return
+	}
+
+	//the same notifications as the mongoDB change stream gives
+	switch collection {
+	case "configs":
+		go sa.listener.OnConfigsChanged()
+	case "appversions":
+		go sa.listener.OnAppVersionsChanged()
+	case "counties":
+		go sa.listener.OnCountiesChanged()
+	case "rules":
+		go sa.listener.OnRulesChanged()
+	case "accessrules":
+		go sa.listener.OnAccessRulesChanged()
+	case "testtypes":
+		go sa.listener.OnTestTypesChanged()
+	case "locations":
+		go sa.listener.OnLocationsChanged()
+	case "providers":
+		go sa.listener.OnProvidersChanged()
+	case "crules":
+		go sa.listener.OnCRulesChanged()
+	}
+}
+
+func (sa *Adapter) findProvider(ID string) *model.Provider {
+	for _, item := range sa.providers {
+		if item.ID == ID {
+			return item
+		}
+	}
+	return nil
+}
+
+func (sa *Adapter) findCounty(ID string) *model.County {
+	for _, item := range sa.counties {
+		if item.ID == ID {
+			return item
+		}
+	}
+	return nil
+}
+
+func (sa *Adapter) findTestType(ID string) *model.TestType {
+	for _, item := range sa.testTypes {
+		if item.ID == ID {
+			return item
+		}
+	}
+	return nil
+}
+
+//matchesCountyFilter applies the filter the same way as the mongoDB adapter does - the fields are the stored field names
+func matchesCountyFilter(county *model.County, f *utils.Filter) bool {
+	if f == nil {
+		return true
+	}
+	for _, item := range f.Items {
+		var value string
+		switch item.Field {
+		case "_id":
+			value = county.ID
+		case "name":
+			value = county.Name
+		case "state_province":
+			value = county.StateProvince
+		case "country":
+			value = county.Country
+		default:
+			//there is no a such field
+			return false
+		}
+		if value != item.Value {
+			return false
+		}
+	}
+	return true
+}
+
+func lessUINOverride(a *model.UINOverride, b *model.UINOverride, sortBy string) bool {
+	switch sortBy {
+	case "uin":
+		return a.UIN < b.UIN
+	case "interval":
+		return a.Interval < b.Interval
+	case "category":
+		return utils.GetString(a.Category) < utils.GetString(b.Category)
+	case "expiration":
+		if a.Expiration == nil || b.Expiration == nil {
+			return a.Expiration == nil && b.Expiration != nil
+		}
+		return a.Expiration.Before(*b.Expiration)
+	default:
+		return false
+	}
+}
+
+//copyEntity makes a deep copy of the source into the destination. The stored items must never be shared with the callers
+func copyEntity(source interface{}, destination interface{}) {
+	data, err := json.Marshal(source)
+	if err != nil {
+		log.Printf("error copying an entity - %s", err)
+		return
+	}
+	err = json.Unmarshal(data, destination)
+	if err != nil {
+		log.Printf("error copying an entity - %s", err)
+	}
+}
+
+func copyUser(user *model.User) *model.User {
+	var result model.User
+	copyEntity(user, &result)
+	return &result
+}
+
+func copyResource(resource *model.Resource) *model.Resource {
+	result := *resource
+	return &result
+}
+
+func copyProvider(provider *model.Provider) *model.Provider {
+	return &model.Provider{ID: provider.ID, Name: provider.Name, ManualTest: provider.ManualTest,
+		AvailableMechanisms: copyStrings(provider.AvailableMechanisms)}
+}
+
+func copyCounty(county *model.County) *model.County {
+	var guidelines []model.Guideline
+	for _, gl := range county.Guidelines {
+		guidelines = append(guidelines, model.Guideline{ID: gl.ID, Name: gl.Name, Description: gl.Description,
+			Items: copyGuidelineItems(gl.Items)})
+	}
+	var countyStatuses []model.CountyStatus
+	for _, cs := range county.CountyStatuses {
+		countyStatuses = append(countyStatuses, model.CountyStatus{ID: cs.ID, Name: cs.Name, Description: cs.Description})
+	}
+	return &model.County{ID: county.ID, Name: county.Name, StateProvince: county.StateProvince,
+		Country: county.Country, Guidelines: guidelines, CountyStatuses: countyStatuses}
+}
+
+func copyGuidelineItems(items []model.GuidelineItem) []model.GuidelineItem {
+	if items == nil {
+		return nil
+	}
+	result := make([]model.GuidelineItem, len(items))
+	copy(result, items)
+	return result
+}
+
+func copyTestType(testType *model.TestType) *model.TestType {
+	var results []model.TestTypeResult
+	for _, ttr := range testType.Results {
+		results = append(results, *copyTestTypeResult(ttr))
+	}
+	return &model.TestType{ID: testType.ID, Name: testType.Name, Priority: copyInt(testType.Priority), Results: results}
+}
+
+func copyTestTypeResult(ttr model.TestTypeResult) *model.TestTypeResult {
+	return &model.TestTypeResult{ID: ttr.ID, Name: ttr.Name, NextStep: ttr.NextStep,
+		NextStepOffset: copyInt(ttr.NextStepOffset), ResultExpiresOffset: copyInt(ttr.ResultExpiresOffset)}
+}
+
+func copyRule(rule *model.Rule) *model.Rule {
+	return &model.Rule{ID: rule.ID, County: model.County{ID: rule.County.ID}, TestType: model.TestType{ID: rule.TestType.ID},
+		Priority: copyInt(rule.Priority), ResultsStates: copyResultsStates(rule.ResultsStates)}
+}
+
+func copyResultsStates(items []model.TestTypeResultCountyStatus) []model.TestTypeResultCountyStatus {
+	if items == nil {
+		return nil
+	}
+	result := make([]model.TestTypeResultCountyStatus, len(items))
+	copy(result, items)
+	return result
+}
+
+func copyLocation(location *model.Location) *model.Location {
+	var result model.Location
+	copyEntity(location, &result)
+	return &result
+}
+
+func copySymptomRule(symptomRule *model.SymptomRule) *model.SymptomRule {
+	var items []model.SymptomRuleItem
+	for _, item := range symptomRule.Items {
+		items = append(items, model.SymptomRuleItem{Gr1: item.Gr1, Gr2: item.Gr2,
+			CountyStatus: model.CountyStatus{ID: item.CountyStatus.ID}, NextStep: item.NextStep})
+	}
+	return &model.SymptomRule{ID: symptomRule.ID, County: model.County{ID: symptomRule.County.ID},
+		Gr1Count: symptomRule.Gr1Count, Gr2Count: symptomRule.Gr2Count, Items: items}
+}
+
+func copyAccessRule(accessRule *model.AccessRule) *model.AccessRule {
+	var rules []model.AccessRuleCountyStatus
+	for _, item := range accessRule.Rules {
+		rules = append(rules, model.AccessRuleCountyStatus{CountyStatusID: item.CountyStatusID, Value: item.Value})
+	}
+	return &model.AccessRule{ID: accessRule.ID, County: model.County{ID: accessRule.County.ID}, Rules: rules}
+}
+
+func copyErasureReceipt(receipt *model.ErasureReceipt) *model.ErasureReceipt {
+	item := *receipt
+	item.Counts = make(map[string]int64, len(receipt.Counts))
+	for key, value := range receipt.Counts {
+		item.Counts[key] = value
+	}
+	return &item
+}
+
+func copyConfigRevision(revision *model.ConfigRevision) *model.ConfigRevision {
+	var result model.ConfigRevision
+	copyEntity(revision, &result)
+	return &result
+}
+
+func copyConfigSchema(schema *model.ConfigSchema) *model.ConfigSchema {
+	var result model.ConfigSchema
+	copyEntity(schema, &result)
+	return &result
+}
+
+func copyStrings(list []string) []string {
+	if list == nil {
+		return nil
+	}
+	result := make([]string, len(list))
+	copy(result, list)
+	return result
+}
+
+func copyInt(v *int) *int {
+	if v == nil {
+		return nil
+	}
+	value := *v
+	return &value
+}
+
+func copyInt64(v *int64) *int64 {
+	if v == nil {
+		return nil
+	}
+	value := *v
+	return &value
+}
+
+func hasTestType(list []model.TestType, ID string) bool {
+	for _, item := range list {
+		if item.ID == ID {
+			return true
+		}
+	}
+	return false
+}
+
+//sphereDistance gives the distance in meters between two points by the haversine formula with the radius mongoDB uses
+func sphereDistance(latitude1 float64, longitude1 float64, latitude2 float64, longitude2 float64) float64 {
+	const earthRadius = 6378100
+	toRadians := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+	dLatitude := toRadians(latitude2 - latitude1)
+	dLongitude := toRadians(longitude2 - longitude1)
+	a := math.Sin(dLatitude/2)*math.Sin(dLatitude/2) +
+		math.Cos(toRadians(latitude1))*math.Cos(toRadians(latitude2))*math.Sin(dLongitude/2)*math.Sin(dLongitude/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func equalStringPointers(a *string, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func equalTimePointers(a *time.Time, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
+//NewMemoryAdapter creates a new in-memory storage adapter instance. It contains the data which the
+//application needs for starting - the covid19 config, an empty FAQ and the symptom groups
+func NewMemoryAdapter() *Adapter {
+	general := []*model.General{}
+	sections := []*model.Section{}
+	faq := &model.FAQ{DateUpdate: time.Now(), General: &general, Sections: &sections}
+
+	covid19Config := &model.COVID19Config{Name: "covid19", NewsUpdatePeriod: 60}
+
+	gr1ID, _ := uuid.NewUUID()
+	gr2ID, _ := uuid.NewUUID()
+	symptomGroups := []*model.SymptomGroup{{ID: gr1ID.String(), Name: "gr1"}, {ID: gr2ID.String(), Name: "gr2"}}
+
+	return &Adapter{lock: &sync.RWMutex{}, covid19Config: covid19Config, faq: faq, symptomGroups: symptomGroups}
+}
@@ -0,0 +1,242 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"health/core"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+//AuditAdapter implements the Audit interface keeping the audit items in memory
+type AuditAdapter struct {
+	lock  *sync.RWMutex
+	items []core.AuditEntity
+}
+
+//Start starts the audit
+func (a *AuditAdapter) Start() error {
+	log.Println("memory audit -> start")
+	return nil
+}
+
+//LogCreateEvent logs a create event item
+func (a *AuditAdapter) LogCreateEvent(userIdentifier string, userInfo string, usedGroup string, entity string, entityID string,
+	data []core.AuditDataEntry, clientData *string) {
+	auditEntity := core.AuditEntity{UserIdentifier: userIdentifier, UserInfo: userInfo,
+		UsedGroup: usedGroup, Entity: entity, EntityID: entityID,
+		Operation: "create", Data: a.prepareData(data), ClientData: clientData, CreatedAt: time.Now()}
+	a.log(auditEntity)
+}
+
+//LogUpdateEvent logs an update event item
+func (a *AuditAdapter) LogUpdateEvent(userIdentifier string, userInfo string, usedGroup string, entity string, entityID string,
+	data []core.AuditDataEntry, clientData *string) {
+	auditEntity := core.AuditEntity{UserIdentifier: userIdentifier, UserInfo: userInfo,
+		UsedGroup: usedGroup, Entity: entity, EntityID: entityID,
+		Operation: "update", Data: a.prepareData(data), ClientData: clientData, CreatedAt: time.Now()}
+	a.log(auditEntity)
+}
+
+//LogDeleteEvent logs a delete event item
+func (a *AuditAdapter) LogDeleteEvent(userIdentifier string, userInfo string, usedGroup string, entity string, entityID string) {
+	auditEntity := core.AuditEntity{UserIdentifier: userIdentifier, UserInfo: userInfo,
+		UsedGroup: usedGroup, Entity: entity, EntityID: entityID,
+		Operation: "delete", Data: nil, CreatedAt: time.Now()}
+	a.log(auditEntity)
+}
+
+func (a *AuditAdapter) prepareData(data []core.AuditDataEntry) *string {
+	if len(data) <= 0 {
+		return nil
+	}
+
+	var b bytes.Buffer
+	for i, current := range data {
+		b.WriteString(fmt.Sprintf("%s:%s", current.Key, current.Value))
+		if i < (len(data) - 1) {
+			b.WriteString(", ")
+		}
+	}
+	dataFormatted := b.String()
+	return &dataFormatted
+}
+
+func (a *AuditAdapter) log(entity core.AuditEntity) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.items = append(a.items, entity)
+}
+
+//Find finds items
+func (a *AuditAdapter) Find(userIdentifier *string, usedGroup *string, entity *string, entityID *string, operation *string,
+	clientData *string, createdAt *time.Time, sortBy *string, asc *bool, limit *int64) ([]*core.AuditEntity, error) {
+	var clientDataRegex *regexp.Regexp
+	if clientData != nil {
+		var err error
+		clientDataRegex, err = regexp.Compile("(?i)" + *clientData)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	//add filter
+	var result []*core.AuditEntity
+	for _, item := range a.items {
+		if userIdentifier != nil && item.UserIdentifier != *userIdentifier {
+			continue
+		}
+		if usedGroup != nil && item.UsedGroup != *usedGroup {
+			continue
+		}
+		if entity != nil && item.Entity != *entity {
+			continue
+		}
+		if entityID != nil && item.EntityID != *entityID {
+			continue
+		}
+		if operation != nil && item.Operation != *operation {
+			continue
+		}
+		if clientDataRegex != nil && (item.ClientData == nil || !clientDataRegex.MatchString(*item.ClientData)) {
+			continue
+		}
+		if createdAt != nil && item.CreatedAt.Before(*createdAt) {
+			continue
+		}
+		current := item
+		result = append(result, &current)
+	}
+
+	//add sort
+	if sortBy != nil && asc != nil {
+		sort.SliceStable(result, func(i, j int) bool {
+			if *asc {
+				return lessAuditEntity(result[i], result[j], *sortBy)
+			}
+			return lessAuditEntity(result[j], result[i], *sortBy)
+		})
+	}
+
+	//add limit
+	var limitValue int64
+	limitValue = 1000 //default is 1000
+	if limit != nil {
+		limitValue = *limit
+	}
+	if limitValue > 0 && int64(len(result)) > limitValue {
+		result = result[:limitValue]
+	}
+	return result, nil
+}
+
+//DeleteOlderThan deletes the items created before the provided date except the ones for the excluded entities
+func (a *AuditAdapter) DeleteOlderThan(ctx context.Context, date time.Time, excludedEntities []string) (int64, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	var count int64
+	var remaining []core.AuditEntity
+	for _, item := range a.items {
+		if item.CreatedAt.Before(date) && !containsString(excludedEntities, item.Entity) {
+			count++
+			continue
+		}
+		remaining = append(remaining, item)
+	}
+	a.items = remaining
+	return count, nil
+}
+
+//AnonymizeUser replaces the provided user identifiers in the items
+func (a *AuditAdapter) AnonymizeUser(ctx context.Context, identifiers []string) (int64, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	var count int64
+	for i := range a.items {
+		if anonymizeAuditEntity(&a.items[i], identifiers) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+//anonymizedValue replaces the erased user identifiers as the mongoDB audit does
+const anonymizedValue = "erased"
+
+func anonymizeAuditEntity(item *core.AuditEntity, identifiers []string) bool {
+	changed := false
+	for _, identifier := range identifiers {
+		if item.UserIdentifier == identifier {
+			item.UserIdentifier = anonymizedValue
+			item.UserInfo = ""
+			changed = true
+		}
+		if item.EntityID == identifier {
+			item.EntityID = anonymizedValue
+			changed = true
+		}
+		if item.Data != nil && strings.Contains(*item.Data, identifier) {
+			data := strings.ReplaceAll(*item.Data, identifier, anonymizedValue)
+			item.Data = &data
+			changed = true
+		}
+		if item.ClientData != nil && strings.Contains(*item.ClientData, identifier) {
+			clientData := strings.ReplaceAll(*item.ClientData, identifier, anonymizedValue)
+			item.ClientData = &clientData
+			changed = true
+		}
+	}
+	return changed
+}
+
+func lessAuditEntity(a *core.AuditEntity, b *core.AuditEntity, sortBy string) bool {
+	switch sortBy {
+	case "user_identifier":
+		return a.UserIdentifier < b.UserIdentifier
+	case "used_group":
+		return a.UsedGroup < b.UsedGroup
+	case "entity":
+		return a.Entity < b.Entity
+	case "entity_id":
+		return a.EntityID < b.EntityID
+	case "operation":
+		return a.Operation < b.Operation
+	case "created_at":
+		return a.CreatedAt.Before(b.CreatedAt)
+	default:
+		return false
+	}
+}
+
+//NewMemoryAuditAdapter creates a new in-memory audit adapter instance
+func NewMemoryAuditAdapter() *AuditAdapter {
+	return &AuditAdapter{lock: &sync.RWMutex{}}
+}
@@ -18,6 +18,7 @@
 package profilebb
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -34,11 +35,11 @@ type Adapter struct {
 }
 
 //LoadUserData loads the user data by uuid
-func (a *Adapter) LoadUserData(uuid string) (*core.ProfileUserData, error) {
+func (a *Adapter) LoadUserData(ctx context.Context, uuid string) (*core.ProfileUserData, error) {
 	url := fmt.Sprintf("%s/%s", a.host, uuid)
 
 	client := &http.Client{}
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		log.Printf("error creating load user data request - %s", err)
 		return nil, err
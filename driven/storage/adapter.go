@@ -105,21 +105,26 @@ type symptomGroup struct {
 type location struct {
 	ID string `bson:"_id"`
 
-	Name            string         `bson:"name"`
-	Address1        string         `bson:"address_1"`
-	Address2        string         `bson:"address_2"`
-	City            string         `bson:"city"`
-	State           string         `bson:"state"`
-	ZIP             string         `bson:"zip"`
-	Country         string         `bson:"country"`
-	Latitude        float64        `bson:"latitude"`
-	Longitude       float64        `bson:"longitude"`
-	Timezone        string         `bson:"timezone"`
-	Contact         string         `bson:"contact"`
-	DaysOfOperation []operationDay `bson:"days_of_operation"`
-	URL             string         `bson:"url"`
-	Notes           string         `bson:"notes"`
-	WaitTimeColor   *string        `bson:"wait_time_color"`
+	Name            string                `bson:"name"`
+	Address1        string                `bson:"address_1"`
+	Address2        string                `bson:"address_2"`
+	City            string                `bson:"city"`
+	State           string                `bson:"state"`
+	ZIP             string                `bson:"zip"`
+	Country         string                `bson:"country"`
+	Latitude        float64               `bson:"latitude"`
+	Longitude       float64               `bson:"longitude"`
+	GeoPoint        *geoPoint             `bson:"geo_point"`
+	Timezone        string                `bson:"timezone"`
+	Contact         string                `bson:"contact"`
+	DaysOfOperation []operationDay        `bson:"days_of_operation"`
+	Exceptions      []locationException   `bson:"exceptions"`
+	URL             string                `bson:"url"`
+	Notes           string                `bson:"notes"`
+	WaitTimeColor   *string               `bson:"wait_time_color"`
+	WaitTime        *model.WaitTimeReport `bson:"wait_time"`
+
+	AppointmentSettings *model.AppointmentSettings `bson:"appointment_settings"`
 
 	ProviderID string `bson:"provider_id"`
 	CountyID   string `bson:"county_id"`
@@ -130,10 +135,32 @@ type location struct {
 	DateUpdated *time.Time `bson:"date_updated"`
 }
 
+//geoPoint is a GeoJSON point for the 2dsphere index, the coordinates are longitude and latitude
+type geoPoint struct {
+	Type        string    `bson:"type"`
+	Coordinates []float64 `bson:"coordinates"`
+}
+
 type operationDay struct {
-	Name      string `bson:"name"`
+	Name      string              `bson:"name"`
+	AllDay    bool                `bson:"all_day"`
+	Intervals []operationInterval `bson:"intervals"`
+}
+
+type operationInterval struct {
+	OpenTime  string `bson:"open_time"`
+	CloseTime string `bson:"close_time"`
+}
+
+type locationException struct {
+	ID        string `bson:"id"`
+	StartDate string `bson:"start_date"`
+	EndDate   string `bson:"end_date"`
+	Type      string `bson:"type"`
 	OpenTime  string `bson:"open_time"`
 	CloseTime string `bson:"close_time"`
+	Address   string `bson:"address"`
+	Note      string `bson:"note"`
 }
 
 type rule struct {
@@ -236,16 +263,32 @@ func (sa *Adapter) Start() error {
 	return err
 }
 
+//Connect connects to the database without applying the migrations. It is used by the migrate and county-bundle commands
+func (sa *Adapter) Connect() error {
+	err := sa.db.connect()
+	return err
+}
+
+//Migrate applies the pending migrations. If dry run is true it only gives the pending migrations without applying them
+func (sa *Adapter) Migrate(dryRun bool) ([]MigrationStatus, error) {
+	return sa.db.migrate(dryRun)
+}
+
+//MigrationsStatus gives the state of all migrations
+func (sa *Adapter) MigrationsStatus() ([]MigrationStatus, error) {
+	return sa.db.migrationsStatus()
+}
+
 //SetStorageListener sets listener for the storage
 func (sa *Adapter) SetStorageListener(storageListener core.StorageListener) {
 	sa.db.listener = storageListener
 }
 
 //ReadAllAppVersions reads all the versions. It gives them in a sorted way as the latest version is on position 0
-func (sa *Adapter) ReadAllAppVersions() ([]string, error) {
+func (sa *Adapter) ReadAllAppVersions(ctx context.Context) ([]string, error) {
 	filter := bson.D{}
 	var result []interface{}
-	err := sa.db.appversions.Find(filter, &result, nil)
+	err := sa.db.appversions.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -268,9 +311,10 @@ func (sa *Adapter) ReadAllAppVersions() ([]string, error) {
 }
 
 //CreateAppVersion preates app version
-func (sa *Adapter) CreateAppVersion(version string) error {
-	item := bson.D{primitive.E{Key: "version", Value: version}}
-	_, err := sa.db.appversions.InsertOne(item)
+func (sa *Adapter) CreateAppVersion(ctx context.Context, version string) error {
+	item := bson.D{primitive.E{Key: "version", Value: version}, primitive.E{Key: "status", Value: "supported"},
+		primitive.E{Key: "date_updated", Value: time.Now().UTC()}}
+	_, err := sa.db.appversions.InsertOneWithContext(ctx, item)
 	if err != nil {
 		return err
 	}
@@ -278,65 +322,101 @@ func (sa *Adapter) CreateAppVersion(version string) error {
 	return nil
 }
 
+//FindAppVersions finds all the app versions with their statuses
+func (sa *Adapter) FindAppVersions(ctx context.Context) ([]*model.AppVersion, error) {
+	filter := bson.D{}
+	var result []*model.AppVersion
+	err := sa.db.appversions.FindWithContext(ctx, filter, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//UpdateAppVersionStatus updates the status of an app version
+func (sa *Adapter) UpdateAppVersionStatus(ctx context.Context, version string, status string) error {
+	filter := bson.D{primitive.E{Key: "version", Value: version}}
+	update := bson.D{
+		primitive.E{Key: "$set", Value: bson.D{
+			primitive.E{Key: "status", Value: status},
+			primitive.E{Key: "date_updated", Value: time.Now().UTC()},
+		}},
+	}
+	result, err := sa.db.appversions.UpdateOneWithContext(ctx, filter, update, nil)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("there is no an app version for the provided version")
+	}
+	return nil
+}
+
 //ClearUserData removes all the user data in the storage. It uses a transaction
-func (sa *Adapter) ClearUserData(userID string) error {
+func (sa *Adapter) ClearUserData(ctx context.Context, userID string, uins []string) (map[string]int64, error) {
+	counts := map[string]int64{"uinoverrides": 0, "uinbuildingaccess": 0}
+
+	//the data linked to the user id
+	userIDFilter := bson.D{primitive.E{Key: "user_id", Value: userID}}
+	items := []struct {
+		name   string
+		coll   *collectionWrapper
+		filter bson.D
+	}{
+		{"ctests", sa.db.ctests, userIDFilter},
+		{"ehistory", sa.db.ehistory, userIDFilter},
+		{"estatus", sa.db.estatus, userIDFilter},
+		{"emanualtests", sa.db.emanualtests, userIDFilter}, //the images are part of the manual tests
+		{"appointments", sa.db.appointments, userIDFilter},
+		//the old collections are not used anymore but they can still keep user data
+		{"status", sa.db.collection("status"), userIDFilter},
+		{"history", sa.db.collection("history"), userIDFilter},
+		{"manualtests", sa.db.collection("manualtests"), userIDFilter},
+		{"users", sa.db.users, bson.D{primitive.E{Key: "_id", Value: userID}}},
+	}
+	//the data linked to the uin
+	if len(uins) > 0 {
+		uinFilter := bson.D{primitive.E{Key: "uin", Value: bson.M{"$in": uins}}}
+		items = append(items, []struct {
+			name   string
+			coll   *collectionWrapper
+			filter bson.D
+		}{{"uinoverrides", sa.db.uinoverrides, uinFilter}, {"uinbuildingaccess", sa.db.uinbuildingaccess, uinFilter}}...)
+	}
+
 	// transaction
-	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
+	err := sa.db.dbClient.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
 		err := sessionContext.StartTransaction()
 		if err != nil {
 			fmt.Println(err)
 			return err
 		}
 
-		//remove from ctest
-		cTestFilter := bson.D{primitive.E{Key: "user_id", Value: userID}}
-		_, err = sa.db.ctests.DeleteManyWithContext(sessionContext, cTestFilter, nil)
-		if err != nil {
-			log.Printf("error deleting ctests for a user - %s", err)
-
-			abortTransaction(sessionContext)
-
-			return err
-		}
-
-		//remove from history
-		historyFilter := bson.D{primitive.E{Key: "user_id", Value: userID}}
-		//from ehistory
-		_, err = sa.db.ehistory.DeleteManyWithContext(sessionContext, historyFilter, nil)
-		if err != nil {
-			log.Printf("error deleting ehistories for a user - %s", err)
-			abortTransaction(sessionContext)
-			return err
-		}
-
-		//remove from status
-		statusFilter := bson.D{primitive.E{Key: "user_id", Value: userID}}
-		//from estatus
-		_, err = sa.db.estatus.DeleteManyWithContext(sessionContext, statusFilter, nil)
+		//free the places of the upcoming appointments before they are deleted
+		upcomingFilter := bson.D{primitive.E{Key: "user_id", Value: userID}, primitive.E{Key: "status", Value: "booked"},
+			primitive.E{Key: "start", Value: bson.M{"$gte": time.Now()}}}
+		var upcoming []*model.Appointment
+		err = sa.db.appointments.FindWithContext(sessionContext, upcomingFilter, &upcoming, nil)
 		if err != nil {
-			log.Printf("error deleting estatus for a user - %s", err)
 			abortTransaction(sessionContext)
 			return err
 		}
-
-		//remove from manual tests
-		mtFilter := bson.D{primitive.E{Key: "user_id", Value: userID}}
-		_, err = sa.db.emanualtests.DeleteOneWithContext(sessionContext, mtFilter, nil)
-		if err != nil {
-			log.Printf("error deleting manual tests for a user - %s", err)
-			abortTransaction(sessionContext)
-			return err
+		for _, appointment := range upcoming {
+			err = sa.releaseAppointmentSlot(sessionContext, appointment.LocationID, appointment.Start)
+			if err != nil {
+				abortTransaction(sessionContext)
+				return err
+			}
 		}
 
-		//remove from users
-		usersFilter := bson.D{primitive.E{Key: "_id", Value: userID}}
-		_, err = sa.db.users.DeleteOneWithContext(sessionContext, usersFilter, nil)
-		if err != nil {
-			log.Printf("error deleting user record for a user - %s", err)
-
-			abortTransaction(sessionContext)
-
-			return err
+		for _, item := range items {
+			result, err := item.coll.DeleteManyWithContext(sessionContext, item.filter, nil)
+			if err != nil {
+				log.Printf("error deleting %s for a user - %s", item.name, err)
+				abortTransaction(sessionContext)
+				return err
+			}
+			counts[item.name] = result.DeletedCount
 		}
 
 		err = sessionContext.CommitTransaction(sessionContext)
@@ -347,17 +427,17 @@ func (sa *Adapter) ClearUserData(userID string) error {
 		return nil
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return counts, nil
 }
 
 //FindUser finds the user for the provided id
-func (sa *Adapter) FindUser(ID string) (*model.User, error) {
+func (sa *Adapter) FindUser(ctx context.Context, ID string) (*model.User, error) {
 	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
 	var result []*model.User
-	err := sa.db.users.Find(filter, &result, nil)
+	err := sa.db.users.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -369,10 +449,10 @@ func (sa *Adapter) FindUser(ID string) (*model.User, error) {
 }
 
 //FindUserByExternalID finds the user for the provided external id
-func (sa *Adapter) FindUserByExternalID(externalID string) (*model.User, error) {
+func (sa *Adapter) FindUserByExternalID(ctx context.Context, externalID string) (*model.User, error) {
 	filter := bson.D{primitive.E{Key: "external_id", Value: externalID}}
 	var result []*model.User
-	err := sa.db.users.Find(filter, &result, nil)
+	err := sa.db.users.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -384,10 +464,10 @@ func (sa *Adapter) FindUserByExternalID(externalID string) (*model.User, error)
 }
 
 //FindUserByShibbolethID finds the user for the provided shibboleth id
-func (sa *Adapter) FindUserByShibbolethID(shibbolethID string) (*model.User, error) {
+func (sa *Adapter) FindUserByShibbolethID(ctx context.Context, shibbolethID string) (*model.User, error) {
 	filter := bson.D{primitive.E{Key: "shibboleth_auth.uiucedu_uin", Value: shibbolethID}}
 	var result []*model.User
-	err := sa.db.users.Find(filter, &result, nil)
+	err := sa.db.users.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -399,10 +479,10 @@ func (sa *Adapter) FindUserByShibbolethID(shibbolethID string) (*model.User, err
 }
 
 //FindUsersByRePost finds the users filtered by re_post
-func (sa *Adapter) FindUsersByRePost(rePost bool) ([]*model.User, error) {
+func (sa *Adapter) FindUsersByRePost(ctx context.Context, rePost bool) ([]*model.User, error) {
 	filter := bson.D{primitive.E{Key: "re_post", Value: rePost}}
 	var result []*model.User
-	err := sa.db.users.Find(filter, &result, nil)
+	err := sa.db.users.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -410,7 +490,7 @@ func (sa *Adapter) FindUsersByRePost(rePost bool) ([]*model.User, error) {
 }
 
 //CreateUser creates an user
-func (sa *Adapter) CreateUser(shibboAuth *model.ShibbolethAuth, externalID string,
+func (sa *Adapter) CreateUser(ctx context.Context, shibboAuth *model.ShibbolethAuth, externalID string,
 	userUUID string, publicKey string, consent bool, exposureNotification bool, rePost bool, encryptedKey *string, encryptedBlob *string) (*model.User, error) {
 	id, err := uuid.NewUUID()
 	if err != nil {
@@ -422,7 +502,8 @@ func (sa *Adapter) CreateUser(shibboAuth *model.ShibbolethAuth, externalID strin
 	user := model.User{ID: id.String(), ShibbolethAuth: shibboAuth, ExternalID: externalID, UUID: userUUID,
 		PublicKey: publicKey, Consent: consent, ExposureNotification: exposureNotification, RePost: rePost,
 		EncryptedKey: encryptedKey, EncryptedBlob: encryptedBlob, DateCreated: dateCreated}
-	_, err = sa.db.users.InsertOne(&user)
+	user.RotatePublicKey(publicKey)
+	_, err = sa.db.users.InsertOneWithContext(ctx, &user)
 	if err != nil {
 		return nil, err
 	}
@@ -432,13 +513,13 @@ func (sa *Adapter) CreateUser(shibboAuth *model.ShibbolethAuth, externalID strin
 }
 
 //SaveUser saves the user
-func (sa *Adapter) SaveUser(user *model.User) error {
+func (sa *Adapter) SaveUser(ctx context.Context, user *model.User) error {
 	filter := bson.D{primitive.E{Key: "_id", Value: user.ID}}
 
 	dateUpdated := time.Now()
 	user.DateUpdated = &dateUpdated
 
-	err := sa.db.users.ReplaceOne(filter, user, nil)
+	err := sa.db.users.ReplaceOneWithContext(ctx, filter, user, nil)
 	if err != nil {
 		return err
 	}
@@ -446,10 +527,10 @@ func (sa *Adapter) SaveUser(user *model.User) error {
 }
 
 //ReadCovid19Config reads the covid19 configuration from the storage
-func (sa *Adapter) ReadCovid19Config() (*model.COVID19Config, error) {
+func (sa *Adapter) ReadCovid19Config(ctx context.Context) (*model.COVID19Config, error) {
 	filter := bson.D{primitive.E{Key: "name", Value: "covid19"}}
 	var result []*model.COVID19Config
-	err := sa.db.configs.Find(filter, &result, nil)
+	err := sa.db.configs.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -463,9 +544,9 @@ func (sa *Adapter) ReadCovid19Config() (*model.COVID19Config, error) {
 }
 
 //SaveCovid19Config saves the covid19 configuration to the storage
-func (sa *Adapter) SaveCovid19Config(covid19Config *model.COVID19Config) error {
+func (sa *Adapter) SaveCovid19Config(ctx context.Context, covid19Config *model.COVID19Config) error {
 	filter := bson.D{primitive.E{Key: "name", Value: covid19Config.Name}}
-	err := sa.db.configs.ReplaceOne(filter, covid19Config, nil)
+	err := sa.db.configs.ReplaceOneWithContext(ctx, filter, covid19Config, nil)
 	if err != nil {
 		return err
 	}
@@ -473,10 +554,10 @@ func (sa *Adapter) SaveCovid19Config(covid19Config *model.COVID19Config) error {
 }
 
 //ReadAllResources reads all covid19 resources
-func (sa *Adapter) ReadAllResources() ([]*model.Resource, error) {
+func (sa *Adapter) ReadAllResources(ctx context.Context) ([]*model.Resource, error) {
 	filter := bson.D{}
 	var result []*model.Resource
-	err := sa.db.resources.Find(filter, &result, nil)
+	err := sa.db.resources.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -484,14 +565,14 @@ func (sa *Adapter) ReadAllResources() ([]*model.Resource, error) {
 }
 
 //CreateResource creates a resource item
-func (sa *Adapter) CreateResource(title string, link string, displayOrder int) (*model.Resource, error) {
+func (sa *Adapter) CreateResource(ctx context.Context, title string, link string, displayOrder int, sourceKey *string) (*model.Resource, error) {
 	id, err := uuid.NewUUID()
 	if err != nil {
 		return nil, err
 	}
 
-	resource := model.Resource{ID: id.String(), Title: title, Link: link, DisplayOrder: displayOrder}
-	_, err = sa.db.resources.InsertOne(&resource)
+	resource := model.Resource{ID: id.String(), Title: title, Link: link, DisplayOrder: displayOrder, SourceKey: sourceKey}
+	_, err = sa.db.resources.InsertOneWithContext(ctx, &resource)
 	if err != nil {
 		return nil, err
 	}
@@ -501,9 +582,9 @@ func (sa *Adapter) CreateResource(title string, link string, displayOrder int) (
 }
 
 //DeleteResource deletes a resource item
-func (sa *Adapter) DeleteResource(ID string) error {
+func (sa *Adapter) DeleteResource(ctx context.Context, ID string) error {
 	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
-	result, err := sa.db.resources.DeleteOne(filter, nil)
+	result, err := sa.db.resources.DeleteOneWithContext(ctx, filter, nil)
 	if err != nil {
 		return err
 	}
@@ -518,10 +599,10 @@ func (sa *Adapter) DeleteResource(ID string) error {
 }
 
 //FindResource finds resource
-func (sa *Adapter) FindResource(ID string) (*model.Resource, error) {
+func (sa *Adapter) FindResource(ctx context.Context, ID string) (*model.Resource, error) {
 	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
 	var result []*model.Resource
-	err := sa.db.resources.Find(filter, &result, nil)
+	err := sa.db.resources.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -533,20 +614,78 @@ func (sa *Adapter) FindResource(ID string) (*model.Resource, error) {
 }
 
 //SaveResource saves resource entity to the storage
-func (sa *Adapter) SaveResource(resource *model.Resource) error {
+func (sa *Adapter) SaveResource(ctx context.Context, resource *model.Resource) error {
 	filter := bson.D{primitive.E{Key: "_id", Value: resource.ID}}
-	err := sa.db.resources.ReplaceOne(filter, resource, nil)
+	err := sa.db.resources.ReplaceOneWithContext(ctx, filter, resource, nil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//FindResourceChanges finds the resource changes, all of them if the status is nil. The latest are first.
+func (sa *Adapter) FindResourceChanges(ctx context.Context, status *string) ([]*model.ResourceChange, error) {
+	filter := bson.D{}
+	if status != nil {
+		filter = append(filter, primitive.E{Key: "status", Value: *status})
+	}
+	var result []*model.ResourceChange
+
+	options := options.Find()
+	options.SetSort(bson.D{primitive.E{Key: "date_created", Value: -1}})
+
+	err := sa.db.resourcechanges.FindWithContext(ctx, filter, &result, options)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//FindResourceChange finds a resource change
+func (sa *Adapter) FindResourceChange(ctx context.Context, ID string) (*model.ResourceChange, error) {
+	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
+	var result []*model.ResourceChange
+	err := sa.db.resourcechanges.FindWithContext(ctx, filter, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		//not found
+		return nil, nil
+	}
+	return result[0], nil
+}
+
+//SaveResourceChange saves a resource change, it creates it if it does not exist
+func (sa *Adapter) SaveResourceChange(ctx context.Context, change *model.ResourceChange) error {
+	filter := bson.D{primitive.E{Key: "_id", Value: change.ID}}
+	opt := options.Replace()
+	opt.SetUpsert(true)
+	err := sa.db.resourcechanges.ReplaceOneWithContext(ctx, filter, change, opt)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+//DeleteResourceChange deletes a resource change
+func (sa *Adapter) DeleteResourceChange(ctx context.Context, ID string) error {
+	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
+	result, err := sa.db.resourcechanges.DeleteOneWithContext(ctx, filter, nil)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount != 1 {
+		return errors.New("there is no a resource change for the provided id")
+	}
+	return nil
+}
+
 //ReadFAQ reads the covid19 FAQs
-func (sa *Adapter) ReadFAQ() (*model.FAQ, error) {
+func (sa *Adapter) ReadFAQ(ctx context.Context) (*model.FAQ, error) {
 	filter := bson.D{}
 	var result []*model.FAQ
-	err := sa.db.faq.Find(filter, &result, nil)
+	err := sa.db.faq.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -557,9 +696,9 @@ func (sa *Adapter) ReadFAQ() (*model.FAQ, error) {
 }
 
 //SaveFAQ saves faq entity to the storage
-func (sa *Adapter) SaveFAQ(faq *model.FAQ) error {
+func (sa *Adapter) SaveFAQ(ctx context.Context, faq *model.FAQ) error {
 	//It is always 1 item
-	err := sa.db.faq.ReplaceOne(bson.D{}, faq, nil)
+	err := sa.db.faq.ReplaceOneWithContext(ctx, bson.D{}, faq, nil)
 	if err != nil {
 		return err
 	}
@@ -567,9 +706,9 @@ func (sa *Adapter) SaveFAQ(faq *model.FAQ) error {
 }
 
 //DeleteFAQSection deletes a faq section
-func (sa *Adapter) DeleteFAQSection(ID string) error {
+func (sa *Adapter) DeleteFAQSection(ctx context.Context, ID string) error {
 	// transaction
-	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
+	err := sa.db.dbClient.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
 		err := sessionContext.StartTransaction()
 		if err != nil {
 			log.Printf("error starting a transaction - %s", err)
@@ -645,7 +784,7 @@ func removeSection(sections []*model.Section, s int) []*model.Section {
 }
 
 //ReadNews reads all covid19 news
-func (sa *Adapter) ReadNews(limit int64) ([]*model.News, error) {
+func (sa *Adapter) ReadNews(ctx context.Context, limit int64) ([]*model.News, error) {
 	filter := bson.D{}
 	var result []*model.News
 
@@ -656,7 +795,36 @@ func (sa *Adapter) ReadNews(limit int64) ([]*model.News, error) {
 		options.SetLimit(limit)
 	}
 
-	err := sa.db.news.Find(filter, &result, options)
+	err := sa.db.news.FindWithContext(ctx, filter, &result, options)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//ReadPublishedNews reads the news the apps receive - published, with a publish time which has come and not expired.
+//The pinned news are first, the others are sorted by date. If the category is provided only the news in it are given.
+func (sa *Adapter) ReadPublishedNews(ctx context.Context, now time.Time, limit int64, category *string) ([]*model.News, error) {
+	filter := bson.D{
+		primitive.E{Key: "status", Value: bson.M{"$ne": "draft"}},
+		primitive.E{Key: "$and", Value: bson.A{
+			bson.M{"$or": bson.A{bson.M{"publish_at": nil}, bson.M{"publish_at": bson.M{"$lte": now}}}},
+			bson.M{"$or": bson.A{bson.M{"expire_at": nil}, bson.M{"expire_at": bson.M{"$gt": now}}}},
+		}},
+	}
+	if category != nil {
+		filter = append(filter, primitive.E{Key: "categories", Value: *category})
+	}
+	var result []*model.News
+
+	options := options.Find()
+	options.SetSort(bson.D{primitive.E{Key: "pinned", Value: -1}, primitive.E{Key: "date", Value: -1}})
+
+	if limit > 0 {
+		options.SetLimit(limit)
+	}
+
+	err := sa.db.news.FindWithContext(ctx, filter, &result, options)
 	if err != nil {
 		return nil, err
 	}
@@ -664,14 +832,17 @@ func (sa *Adapter) ReadNews(limit int64) ([]*model.News, error) {
 }
 
 //CreateNews creates a new covid19 news
-func (sa *Adapter) CreateNews(date time.Time, title string, description string, htmlContent string, link *string) (*model.News, error) {
+func (sa *Adapter) CreateNews(ctx context.Context, date time.Time, title string, description string, htmlContent string, link *string,
+	status string, publishAt *time.Time, expireAt *time.Time, pinned bool, categories []string) (*model.News, error) {
 	id, err := uuid.NewUUID()
 	if err != nil {
 		return nil, err
 	}
+	now := time.Now().UTC()
 	news := model.News{ID: id.String(), Date: date, Title: title, Description: description,
-		HTMLContent: htmlContent, Link: link}
-	insertedID, err := sa.db.news.InsertOne(&news)
+		HTMLContent: htmlContent, Link: link, Status: status, PublishAt: publishAt, ExpireAt: expireAt, Pinned: pinned, Categories: categories,
+		DateUpdated: &now}
+	insertedID, err := sa.db.news.InsertOneWithContext(ctx, &news)
 	if err != nil {
 		return nil, err
 	}
@@ -682,9 +853,9 @@ func (sa *Adapter) CreateNews(date time.Time, title string, description string,
 }
 
 //DeleteNews deletes a new covid19 news
-func (sa *Adapter) DeleteNews(ID string) error {
+func (sa *Adapter) DeleteNews(ctx context.Context, ID string) error {
 	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
-	result, err := sa.db.news.DeleteOne(filter, nil)
+	result, err := sa.db.news.DeleteOneWithContext(ctx, filter, nil)
 	if err != nil {
 		return err
 	}
@@ -699,10 +870,10 @@ func (sa *Adapter) DeleteNews(ID string) error {
 }
 
 //FindNews finds news
-func (sa *Adapter) FindNews(ID string) (*model.News, error) {
+func (sa *Adapter) FindNews(ctx context.Context, ID string) (*model.News, error) {
 	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
 	var result []*model.News
-	err := sa.db.news.Find(filter, &result, nil)
+	err := sa.db.news.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -714,9 +885,109 @@ func (sa *Adapter) FindNews(ID string) (*model.News, error) {
 }
 
 //SaveNews saves news entity to the storage
-func (sa *Adapter) SaveNews(news *model.News) error {
+func (sa *Adapter) SaveNews(ctx context.Context, news *model.News) error {
+	now := time.Now().UTC()
+	news.DateUpdated = &now
+
 	filter := bson.D{primitive.E{Key: "_id", Value: news.ID}}
-	err := sa.db.news.ReplaceOne(filter, news, nil)
+	err := sa.db.news.ReplaceOneWithContext(ctx, filter, news, nil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//InsertSourceNews inserts the news imported from a source if there is no a news with the same guid for the source.
+//It gives true if the news is inserted.
+func (sa *Adapter) InsertSourceNews(ctx context.Context, news *model.News) (bool, error) {
+	now := time.Now().UTC()
+	news.DateUpdated = &now
+
+	filter := bson.D{primitive.E{Key: "source_id", Value: news.SourceID}, primitive.E{Key: "guid", Value: news.GUID}}
+	update := bson.D{primitive.E{Key: "$setOnInsert", Value: news}}
+
+	//insert if not exists
+	opt := options.Update()
+	upsert := true
+	opt.Upsert = &upsert
+
+	result, err := sa.db.news.UpdateOneWithContext(ctx, filter, update, opt)
+	if err != nil {
+		return false, err
+	}
+	return result.UpsertedCount == 1, nil
+}
+
+//FindNewsSources finds all the news sources
+func (sa *Adapter) FindNewsSources(ctx context.Context) ([]*model.NewsSource, error) {
+	filter := bson.D{}
+	var result []*model.NewsSource
+
+	options := options.Find()
+	options.SetSort(bson.D{primitive.E{Key: "date_created", Value: 1}})
+
+	err := sa.db.newssources.FindWithContext(ctx, filter, &result, options)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//FindNewsSource finds a news source
+func (sa *Adapter) FindNewsSource(ctx context.Context, ID string) (*model.NewsSource, error) {
+	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
+	var result []*model.NewsSource
+	err := sa.db.newssources.FindWithContext(ctx, filter, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		//not found
+		return nil, nil
+	}
+	return result[0], nil
+}
+
+//SaveNewsSource saves a news source, it creates it if it does not exist
+func (sa *Adapter) SaveNewsSource(ctx context.Context, source *model.NewsSource) error {
+	filter := bson.D{primitive.E{Key: "_id", Value: source.ID}}
+	opt := options.Replace()
+	opt.SetUpsert(true)
+	err := sa.db.newssources.ReplaceOneWithContext(ctx, filter, source, opt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//DeleteNewsSource deletes a news source, the news imported from it are kept
+func (sa *Adapter) DeleteNewsSource(ctx context.Context, ID string) error {
+	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
+	result, err := sa.db.newssources.DeleteOneWithContext(ctx, filter, nil)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount != 1 {
+		return errors.New("there is no a news source for the provided id")
+	}
+	return nil
+}
+
+//UpdateNewsSourceFetchStatus updates only the last fetch fields, so the admin changes made during the fetch are kept
+func (sa *Adapter) UpdateNewsSourceFetchStatus(ctx context.Context, source *model.NewsSource) error {
+	filter := bson.D{primitive.E{Key: "_id", Value: source.ID}}
+	update := bson.D{
+		primitive.E{Key: "$set", Value: bson.D{
+			primitive.E{Key: "last_fetched_at", Value: source.LastFetchedAt},
+			primitive.E{Key: "last_fetch_status", Value: source.LastFetchStatus},
+			primitive.E{Key: "last_error", Value: source.LastError},
+			primitive.E{Key: "last_added_count", Value: source.LastAddedCount},
+			primitive.E{Key: "last_succeeded_at", Value: source.LastSucceededAt},
+			primitive.E{Key: "last_etag", Value: source.LastETag},
+			primitive.E{Key: "last_modified", Value: source.LastModified},
+		}},
+	}
+	_, err := sa.db.newssources.UpdateOneWithContext(ctx, filter, update, nil)
 	if err != nil {
 		return err
 	}
@@ -724,14 +995,14 @@ func (sa *Adapter) SaveNews(news *model.News) error {
 }
 
 //CreateEStatus creates a new covid19 passport status
-func (sa *Adapter) CreateEStatus(appVersion *string, userID string, date *time.Time, encryptedKey string, encryptedBlob string) (*model.EStatus, error) {
+func (sa *Adapter) CreateEStatus(ctx context.Context, appVersion *string, userID string, date *time.Time, encryptedKey string, encryptedBlob string) (*model.EStatus, error) {
 	id, err := uuid.NewUUID()
 	if err != nil {
 		return nil, err
 	}
 
 	status := model.EStatus{ID: id.String(), AppVersion: appVersion, UserID: userID, Date: date, EncryptedKey: encryptedKey, EncryptedBlob: encryptedBlob}
-	_, err = sa.db.estatus.InsertOne(&status)
+	_, err = sa.db.estatus.InsertOneWithContext(ctx, &status)
 	if err != nil {
 		return nil, err
 	}
@@ -741,11 +1012,11 @@ func (sa *Adapter) CreateEStatus(appVersion *string, userID string, date *time.T
 }
 
 //FindEStatusByUserID finds a status by user id
-func (sa *Adapter) FindEStatusByUserID(appVersion *string, userID string) (*model.EStatus, error) {
+func (sa *Adapter) FindEStatusByUserID(ctx context.Context, appVersion *string, userID string) (*model.EStatus, error) {
 	filter := bson.D{primitive.E{Key: "user_id", Value: userID},
 		primitive.E{Key: "app_version", Value: appVersion}}
 	var result []*model.EStatus
-	err := sa.db.estatus.Find(filter, &result, nil)
+	err := sa.db.estatus.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -756,14 +1027,25 @@ func (sa *Adapter) FindEStatusByUserID(appVersion *string, userID string) (*mode
 	return result[0], nil
 }
 
+//FindEStatusesByUserID finds the statuses of the user for all app versions
+func (sa *Adapter) FindEStatusesByUserID(ctx context.Context, userID string) ([]*model.EStatus, error) {
+	filter := bson.D{primitive.E{Key: "user_id", Value: userID}}
+	var result []*model.EStatus
+	err := sa.db.estatus.FindWithContext(ctx, filter, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 //SaveEStatus saves the status
-func (sa *Adapter) SaveEStatus(status *model.EStatus) error {
+func (sa *Adapter) SaveEStatus(ctx context.Context, status *model.EStatus) error {
 	filter := bson.D{primitive.E{Key: "_id", Value: status.ID}}
 
 	dateUpdated := time.Now()
 	status.DateUpdated = &dateUpdated
 
-	err := sa.db.estatus.ReplaceOne(filter, status, nil)
+	err := sa.db.estatus.ReplaceOneWithContext(ctx, filter, status, nil)
 	if err != nil {
 		return err
 	}
@@ -771,10 +1053,10 @@ func (sa *Adapter) SaveEStatus(status *model.EStatus) error {
 }
 
 //DeleteEStatus deletes the status for the user
-func (sa *Adapter) DeleteEStatus(appVersion *string, userID string) error {
+func (sa *Adapter) DeleteEStatus(ctx context.Context, appVersion *string, userID string) error {
 	filter := bson.D{primitive.E{Key: "user_id", Value: userID},
 		primitive.E{Key: "app_version", Value: appVersion}}
-	result, err := sa.db.estatus.DeleteOne(filter, nil)
+	result, err := sa.db.estatus.DeleteOneWithContext(ctx, filter, nil)
 	if err != nil {
 		log.Printf("error deleting a estatus - %s", err)
 		return err
@@ -793,15 +1075,15 @@ func (sa *Adapter) DeleteEStatus(appVersion *string, userID string) error {
 }
 
 //CreateEHistory creates a history
-func (sa *Adapter) CreateEHistory(userID string, date time.Time, eType string, encryptedKey string, encryptedBlob string) (*model.EHistory, error) {
+func (sa *Adapter) CreateEHistory(ctx context.Context, userID string, keyVersion int, date time.Time, eType string, encryptedKey string, encryptedBlob string) (*model.EHistory, error) {
 	id, err := uuid.NewUUID()
 	if err != nil {
 		return nil, err
 	}
 
 	history := model.EHistory{ID: id.String(), UserID: userID, Date: date, Type: eType,
-		EncryptedKey: encryptedKey, EncryptedBlob: encryptedBlob}
-	_, err = sa.db.ehistory.InsertOne(&history)
+		EncryptedKey: encryptedKey, EncryptedBlob: encryptedBlob, KeyVersion: keyVersion}
+	_, err = sa.db.ehistory.InsertOneWithContext(ctx, &history)
 	if err != nil {
 		return nil, err
 	}
@@ -811,12 +1093,12 @@ func (sa *Adapter) CreateEHistory(userID string, date time.Time, eType string, e
 }
 
 //CreateManualЕHistory creates a history
-func (sa *Adapter) CreateManualЕHistory(userID string, date time.Time, encryptedKey string, encryptedBlob string, encryptedImageKey *string, encryptedImageBlob *string,
+func (sa *Adapter) CreateManualЕHistory(ctx context.Context, userID string, keyVersion int, date time.Time, encryptedKey string, encryptedBlob string, encryptedImageKey *string, encryptedImageBlob *string,
 	countyID *string, locationID *string) (*model.EHistory, error) {
 	var history model.EHistory
 
 	// transaction
-	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
+	err := sa.db.dbClient.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
 		err := sessionContext.StartTransaction()
 		if err != nil {
 			log.Printf("error starting a transaction - %s", err)
@@ -826,7 +1108,7 @@ func (sa *Adapter) CreateManualЕHistory(userID string, date time.Time, encrypte
 		//1. insert history item
 		historyID, _ := uuid.NewUUID()
 		history = model.EHistory{ID: historyID.String(), UserID: userID, Date: date, Type: "unverified_manual_test",
-			EncryptedKey: encryptedKey, EncryptedBlob: encryptedBlob}
+			EncryptedKey: encryptedKey, EncryptedBlob: encryptedBlob, KeyVersion: keyVersion}
 		insertedID, err := sa.db.ehistory.InsertOneWithContext(sessionContext, &history)
 		if err != nil {
 			abortTransaction(sessionContext)
@@ -860,14 +1142,14 @@ func (sa *Adapter) CreateManualЕHistory(userID string, date time.Time, encrypte
 }
 
 //FindEHistories finds all histories for an user
-func (sa *Adapter) FindEHistories(userID string) ([]*model.EHistory, error) {
+func (sa *Adapter) FindEHistories(ctx context.Context, userID string) ([]*model.EHistory, error) {
 	filter := bson.D{primitive.E{Key: "user_id", Value: userID}}
 	var result []*model.EHistory
 
 	options := options.Find()
 	options.SetSort(bson.D{primitive.E{Key: "date", Value: -1}}) //sort by "date"
 
-	err := sa.db.ehistory.Find(filter, &result, options)
+	err := sa.db.ehistory.FindWithContext(ctx, filter, &result, options)
 	if err != nil {
 		return nil, err
 	}
@@ -875,10 +1157,10 @@ func (sa *Adapter) FindEHistories(userID string) ([]*model.EHistory, error) {
 }
 
 //DeleteEHistories deletes all histories for an user
-func (sa *Adapter) DeleteEHistories(userID string) (int64, error) {
+func (sa *Adapter) DeleteEHistories(ctx context.Context, userID string) (int64, error) {
 	filter := bson.D{primitive.E{Key: "user_id", Value: userID}}
 
-	result, err := sa.db.ehistory.DeleteMany(filter, nil)
+	result, err := sa.db.ehistory.DeleteManyWithContext(ctx, filter, nil)
 	if err != nil {
 		return -1, err
 	}
@@ -891,10 +1173,10 @@ func (sa *Adapter) DeleteEHistories(userID string) (int64, error) {
 }
 
 //FindEHistory finds a history item
-func (sa *Adapter) FindEHistory(ID string) (*model.EHistory, error) {
+func (sa *Adapter) FindEHistory(ctx context.Context, ID string) (*model.EHistory, error) {
 	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
 	var result []*model.EHistory
-	err := sa.db.ehistory.Find(filter, &result, nil)
+	err := sa.db.ehistory.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -907,9 +1189,9 @@ func (sa *Adapter) FindEHistory(ID string) (*model.EHistory, error) {
 }
 
 //SaveEHistory saves a history item
-func (sa *Adapter) SaveEHistory(history *model.EHistory) error {
+func (sa *Adapter) SaveEHistory(ctx context.Context, history *model.EHistory) error {
 	filter := bson.D{primitive.E{Key: "_id", Value: history.ID}}
-	err := sa.db.ehistory.ReplaceOne(filter, history, nil)
+	err := sa.db.ehistory.ReplaceOneWithContext(ctx, filter, history, nil)
 	if err != nil {
 		return err
 	}
@@ -918,10 +1200,10 @@ func (sa *Adapter) SaveEHistory(history *model.EHistory) error {
 }
 
 //ReadAllProviders reads all the providers
-func (sa *Adapter) ReadAllProviders() ([]*model.Provider, error) {
+func (sa *Adapter) ReadAllProviders(ctx context.Context) ([]*model.Provider, error) {
 	filter := bson.D{}
 	var result []*provider
-	err := sa.db.providers.Find(filter, &result, nil)
+	err := sa.db.providers.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -936,7 +1218,7 @@ func (sa *Adapter) ReadAllProviders() ([]*model.Provider, error) {
 }
 
 //CreateProvider creates a provider
-func (sa *Adapter) CreateProvider(providerName string, manualTest bool, availableMechanisms []string) (*model.Provider, error) {
+func (sa *Adapter) CreateProvider(ctx context.Context, providerName string, manualTest bool, availableMechanisms []string) (*model.Provider, error) {
 	id, err := uuid.NewUUID()
 	if err != nil {
 		return nil, err
@@ -945,7 +1227,7 @@ func (sa *Adapter) CreateProvider(providerName string, manualTest bool, availabl
 	dateCreated := time.Now()
 
 	provider := provider{ID: id.String(), ProviderName: providerName, ManualTest: manualTest, AvailableMechanisms: availableMechanisms, DateCreated: dateCreated}
-	_, err = sa.db.providers.InsertOne(&provider)
+	_, err = sa.db.providers.InsertOneWithContext(ctx, &provider)
 	if err != nil {
 		return nil, err
 	}
@@ -956,10 +1238,10 @@ func (sa *Adapter) CreateProvider(providerName string, manualTest bool, availabl
 }
 
 //FindProvider finds a provider
-func (sa *Adapter) FindProvider(ID string) (*model.Provider, error) {
+func (sa *Adapter) FindProvider(ctx context.Context, ID string) (*model.Provider, error) {
 	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
 	var result []*provider
-	err := sa.db.providers.Find(filter, &result, nil)
+	err := sa.db.providers.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -973,10 +1255,10 @@ func (sa *Adapter) FindProvider(ID string) (*model.Provider, error) {
 }
 
 //SaveProvider saves a provider
-func (sa *Adapter) SaveProvider(entity *model.Provider) error {
+func (sa *Adapter) SaveProvider(ctx context.Context, entity *model.Provider) error {
 	findFilter := bson.D{primitive.E{Key: "_id", Value: entity.ID}}
 	var result []*provider
-	err := sa.db.providers.Find(findFilter, &result, nil)
+	err := sa.db.providers.FindWithContext(ctx, findFilter, &result, nil)
 	if err != nil {
 		return err
 	}
@@ -994,7 +1276,7 @@ func (sa *Adapter) SaveProvider(entity *model.Provider) error {
 	provider.DateUpdated = &dateUpdated
 
 	filter := bson.D{primitive.E{Key: "_id", Value: provider.ID}}
-	err = sa.db.providers.ReplaceOne(filter, provider, nil)
+	err = sa.db.providers.ReplaceOneWithContext(ctx, filter, provider, nil)
 	if err != nil {
 		return err
 	}
@@ -1003,9 +1285,9 @@ func (sa *Adapter) SaveProvider(entity *model.Provider) error {
 }
 
 //DeleteProvider deletes a provider
-func (sa *Adapter) DeleteProvider(ID string) error {
+func (sa *Adapter) DeleteProvider(ctx context.Context, ID string) error {
 	// transaction
-	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
+	err := sa.db.dbClient.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
 		err := sessionContext.StartTransaction()
 		if err != nil {
 			log.Printf("error starting a transaction - %s", err)
@@ -1076,12 +1358,12 @@ func (sa *Adapter) DeleteProvider(ID string) error {
 }
 
 //CreateExternalCTest creates an external ctests record
-func (sa *Adapter) CreateExternalCTest(providerID string, uin string, encryptedKey string, encryptedBlob string, processed bool, orderNumber *string) (*model.CTest, *model.User, error) {
+func (sa *Adapter) CreateExternalCTest(ctx context.Context, providerID string, uin string, keyVersion *int, encryptedKey string, encryptedBlob string, processed bool, orderNumber *string) (*model.CTest, *model.User, error) {
 	var cTest model.CTest
 	var user model.User
 
 	// transaction
-	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
+	err := sa.db.dbClient.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
 		err := sessionContext.StartTransaction()
 		if err != nil {
 			log.Printf("error starting a transaction - %s", err)
@@ -1117,15 +1399,19 @@ func (sa *Adapter) CreateExternalCTest(providerID string, uin string, encryptedK
 		}
 		user = *userResult[0]
 
-		//3. create a ctest
+		//3. create a ctest, it is encrypted for the current user key if the provider does not say another version
 		id, err := uuid.NewUUID()
 		if err != nil {
 			abortTransaction(sessionContext)
 			return err
 		}
+		ctestKeyVersion := user.KeyVersion
+		if keyVersion != nil {
+			ctestKeyVersion = *keyVersion
+		}
 		dateCreated := time.Now()
-		cTest = model.CTest{ID: id.String(), ProviderID: providerID, UserID: user.ID,
-			EncryptedKey: encryptedKey, EncryptedBlob: encryptedBlob, Processed: processed, OrderNumber: orderNumber, DateCreated: dateCreated}
+		cTest = model.CTest{ID: id.String(), ProviderID: providerID, UserID: user.ID, EncryptedKey: encryptedKey, EncryptedBlob: encryptedBlob,
+			KeyVersion: ctestKeyVersion, Processed: processed, OrderNumber: orderNumber, DateCreated: dateCreated}
 		_, err = sa.db.ctests.InsertOneWithContext(sessionContext, &cTest)
 		if err != nil {
 			abortTransaction(sessionContext)
@@ -1159,12 +1445,12 @@ func (sa *Adapter) CreateExternalCTest(providerID string, uin string, encryptedK
 }
 
 //CreateAdminCTest creates an admin ctests record
-func (sa *Adapter) CreateAdminCTest(providerID string, userID string, encryptedKey string, encryptedBlob string, processed bool, orderNumber *string) (*model.CTest, *model.User, error) {
+func (sa *Adapter) CreateAdminCTest(ctx context.Context, providerID string, userID string, encryptedKey string, encryptedBlob string, processed bool, orderNumber *string) (*model.CTest, *model.User, error) {
 	var cTest model.CTest
 	var user model.User
 
 	// transaction
-	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
+	err := sa.db.dbClient.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
 		err := sessionContext.StartTransaction()
 		if err != nil {
 			log.Printf("error starting a transaction - %s", err)
@@ -1207,8 +1493,8 @@ func (sa *Adapter) CreateAdminCTest(providerID string, userID string, encryptedK
 			return err
 		}
 		dateCreated := time.Now()
-		cTest = model.CTest{ID: id.String(), ProviderID: providerID, UserID: user.ID,
-			EncryptedKey: encryptedKey, EncryptedBlob: encryptedBlob, Processed: processed, OrderNumber: orderNumber, DateCreated: dateCreated}
+		cTest = model.CTest{ID: id.String(), ProviderID: providerID, UserID: user.ID, EncryptedKey: encryptedKey, EncryptedBlob: encryptedBlob,
+			KeyVersion: user.KeyVersion, Processed: processed, OrderNumber: orderNumber, DateCreated: dateCreated}
 		_, err = sa.db.ctests.InsertOneWithContext(sessionContext, &cTest)
 		if err != nil {
 			abortTransaction(sessionContext)
@@ -1231,10 +1517,10 @@ func (sa *Adapter) CreateAdminCTest(providerID string, userID string, encryptedK
 }
 
 //FindCTest finds ctest
-func (sa *Adapter) FindCTest(ID string) (*model.CTest, error) {
+func (sa *Adapter) FindCTest(ctx context.Context, ID string) (*model.CTest, error) {
 	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
 	var result []*model.CTest
-	err := sa.db.ctests.Find(filter, &result, nil)
+	err := sa.db.ctests.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1247,7 +1533,7 @@ func (sa *Adapter) FindCTest(ID string) (*model.CTest, error) {
 }
 
 //FindCTests finds ctests for user and processed
-func (sa *Adapter) FindCTests(userID string, processed bool) ([]*model.CTest, error) {
+func (sa *Adapter) FindCTests(ctx context.Context, userID string, processed bool) ([]*model.CTest, error) {
 	filter := bson.D{primitive.E{Key: "user_id", Value: userID},
 		primitive.E{Key: "processed", Value: processed}}
 
@@ -1255,7 +1541,7 @@ func (sa *Adapter) FindCTests(userID string, processed bool) ([]*model.CTest, er
 	options.SetSort(bson.D{primitive.E{Key: "date_created", Value: 1}}) //sort by "date_created"
 
 	var result []*model.CTest
-	err := sa.db.ctests.Find(filter, &result, options)
+	err := sa.db.ctests.FindWithContext(ctx, filter, &result, options)
 	if err != nil {
 		return nil, err
 	}
@@ -1277,7 +1563,7 @@ type ctu2Join struct {
 }
 
 //FindCTestsByExternalUserIDs finds ctests lists for the provided external user IDs
-func (sa *Adapter) FindCTestsByExternalUserIDs(externalUserIDs []string) (map[string][]*model.CTest, error) {
+func (sa *Adapter) FindCTestsByExternalUserIDs(ctx context.Context, externalUserIDs []string) (map[string][]*model.CTest, error) {
 	pipeline := []bson.M{
 		{"$lookup": bson.M{
 			"from":         "users",
@@ -1294,7 +1580,7 @@ func (sa *Adapter) FindCTestsByExternalUserIDs(externalUserIDs []string) (map[st
 		}}}
 
 	var result []*ctu2Join
-	err := sa.db.ctests.Aggregate(pipeline, &result, nil)
+	err := sa.db.ctests.AggregateWithContext(ctx, pipeline, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1321,10 +1607,10 @@ func (sa *Adapter) FindCTestsByExternalUserIDs(externalUserIDs []string) (map[st
 }
 
 //DeleteCTests deletes all ctest for a user
-func (sa *Adapter) DeleteCTests(userID string) (int64, error) {
+func (sa *Adapter) DeleteCTests(ctx context.Context, userID string) (int64, error) {
 	filter := bson.D{primitive.E{Key: "user_id", Value: userID}}
 
-	result, err := sa.db.ctests.DeleteMany(filter, nil)
+	result, err := sa.db.ctests.DeleteManyWithContext(ctx, filter, nil)
 	if err != nil {
 		return -1, err
 	}
@@ -1337,10 +1623,10 @@ func (sa *Adapter) DeleteCTests(userID string) (int64, error) {
 }
 
 //SaveCTest saves the ctest
-func (sa *Adapter) SaveCTest(entity *model.CTest) error {
+func (sa *Adapter) SaveCTest(ctx context.Context, entity *model.CTest) error {
 	findFilter := bson.D{primitive.E{Key: "_id", Value: entity.ID}}
 	var result []*model.CTest
-	err := sa.db.ctests.Find(findFilter, &result, nil)
+	err := sa.db.ctests.FindWithContext(ctx, findFilter, &result, nil)
 	if err != nil {
 		return err
 	}
@@ -1352,11 +1638,14 @@ func (sa *Adapter) SaveCTest(entity *model.CTest) error {
 
 	//update the values
 	ctest.Processed = entity.Processed
+	ctest.EncryptedKey = entity.EncryptedKey
+	ctest.EncryptedBlob = entity.EncryptedBlob
+	ctest.KeyVersion = entity.KeyVersion
 	dateUpdated := time.Now()
 	ctest.DateUpdated = &dateUpdated
 
 	filter := bson.D{primitive.E{Key: "_id", Value: ctest.ID}}
-	err = sa.db.ctests.ReplaceOne(filter, ctest, nil)
+	err = sa.db.ctests.ReplaceOneWithContext(ctx, filter, ctest, nil)
 	if err != nil {
 		return err
 	}
@@ -1364,8 +1653,39 @@ func (sa *Adapter) SaveCTest(entity *model.CTest) error {
 	return nil
 }
 
+//FindCTestsWithOldKeyVersion finds the not processed ctests which are encrypted for an old public key of their user
+func (sa *Adapter) FindCTestsWithOldKeyVersion(ctx context.Context, providerID *string, userID *string) ([]*model.CTest, error) {
+	match := bson.M{"processed": false}
+	if providerID != nil {
+		match["provider_id"] = *providerID
+	}
+	if userID != nil {
+		match["user_id"] = *userID
+	}
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$lookup": bson.M{
+			"from":         "users",
+			"localField":   "user_id",
+			"foreignField": "_id",
+			"as":           "user",
+		}},
+		{"$unwind": "$user"},
+		{"$match": bson.M{"$expr": bson.M{"$lt": bson.A{"$key_version", "$user.key_version"}}}},
+		{"$project": bson.M{"user": 0}},
+		{"$sort": bson.D{primitive.E{Key: "date_created", Value: 1}}},
+	}
+
+	var result []*model.CTest
+	err := sa.db.ctests.AggregateWithContext(ctx, pipeline, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 //CreateCounty creates a county
-func (sa *Adapter) CreateCounty(name string, stateProvince string, country string) (*model.County, error) {
+func (sa *Adapter) CreateCounty(ctx context.Context, name string, stateProvince string, country string) (*model.County, error) {
 	id, err := uuid.NewUUID()
 	if err != nil {
 		return nil, err
@@ -1375,7 +1695,7 @@ func (sa *Adapter) CreateCounty(name string, stateProvince string, country strin
 
 	county := county{ID: id.String(), Name: name, StateProvince: stateProvince, Country: country,
 		DateCreated: dateCreated}
-	_, err = sa.db.counties.InsertOne(&county)
+	_, err = sa.db.counties.InsertOneWithContext(ctx, &county)
 	if err != nil {
 		return nil, err
 	}
@@ -1387,10 +1707,10 @@ func (sa *Adapter) CreateCounty(name string, stateProvince string, country strin
 }
 
 //FindCounty finds a county
-func (sa *Adapter) FindCounty(ID string) (*model.County, error) {
+func (sa *Adapter) FindCounty(ctx context.Context, ID string) (*model.County, error) {
 	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
 	var result []*county
-	err := sa.db.counties.Find(filter, &result, nil)
+	err := sa.db.counties.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1434,10 +1754,10 @@ func (sa *Adapter) FindCounty(ID string) (*model.County, error) {
 }
 
 //SaveCounty saves a county
-func (sa *Adapter) SaveCounty(entity *model.County) error {
+func (sa *Adapter) SaveCounty(ctx context.Context, entity *model.County) error {
 	findFilter := bson.D{primitive.E{Key: "_id", Value: entity.ID}}
 	var result []*county
-	err := sa.db.counties.Find(findFilter, &result, nil)
+	err := sa.db.counties.FindWithContext(ctx, findFilter, &result, nil)
 	if err != nil {
 		return err
 	}
@@ -1457,7 +1777,7 @@ func (sa *Adapter) SaveCounty(entity *model.County) error {
 	dateUpdated := time.Now()
 	county.DateUpdated = &dateUpdated
 
-	err = sa.db.counties.ReplaceOne(filter, county, nil)
+	err = sa.db.counties.ReplaceOneWithContext(ctx, filter, county, nil)
 	if err != nil {
 		return err
 	}
@@ -1466,7 +1786,7 @@ func (sa *Adapter) SaveCounty(entity *model.County) error {
 }
 
 //FindCounties finds counties
-func (sa *Adapter) FindCounties(f *utils.Filter) ([]*model.County, error) {
+func (sa *Adapter) FindCounties(ctx context.Context, f *utils.Filter) ([]*model.County, error) {
 	//add filter
 	var filter interface{}
 	if f != nil {
@@ -1474,7 +1794,7 @@ func (sa *Adapter) FindCounties(f *utils.Filter) ([]*model.County, error) {
 	}
 
 	var result []*county
-	err := sa.db.counties.Find(filter, &result, nil)
+	err := sa.db.counties.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1518,9 +1838,9 @@ func (sa *Adapter) FindCounties(f *utils.Filter) ([]*model.County, error) {
 }
 
 //DeleteCounty deletes a county
-func (sa *Adapter) DeleteCounty(ID string) error {
+func (sa *Adapter) DeleteCounty(ctx context.Context, ID string) error {
 	// transaction
-	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
+	err := sa.db.dbClient.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
 		err := sessionContext.StartTransaction()
 		if err != nil {
 			log.Printf("error starting a transaction - %s", err)
@@ -1618,58 +1938,300 @@ func (sa *Adapter) DeleteCounty(ID string) error {
 	return nil
 }
 
-//CreateGuideline creates a guidline
-func (sa *Adapter) CreateGuideline(countyID string, name string, description string, items []model.GuidelineItem) (*model.Guideline, error) {
-	//1. find the county
-	findFilter := bson.D{primitive.E{Key: "_id", Value: countyID}}
-	var result []*county
-	err := sa.db.counties.Find(findFilter, &result, nil)
-	if err != nil {
-		return nil, err
-	}
-	if result == nil || len(result) == 0 {
-		//not found
-		return nil, errors.New("there is no a county for the provided id")
-	}
-	county := result[0]
+//CreateCountyConfiguration creates a county with its guidelines, statuses, rules and locations and publishes the crules revisions
+func (sa *Adapter) CreateCountyConfiguration(ctx context.Context, county *model.County, rules []*model.Rule, countyAccessRule *model.AccessRule,
+	countySymptomRule *model.SymptomRule, cRulesRevisions []*model.ConfigRevision, locations []*model.Location) error {
+	now := time.Now()
 
-	//2. create guideline
-	id, err := uuid.NewUUID()
-	if err != nil {
-		return nil, err
-	}
-	var gdItems []guidlineItem
-	for _, v := range items {
-		item := guidlineItem{Icon: v.Icon, Description: v.Description, Type: v.Type.Value}
-		gdItems = append(gdItems, item)
-	}
-	dateCreated := time.Now()
-	guideline := guidline{ID: id.String(), Name: name, Description: description, Items: gdItems, DateCreated: dateCreated}
+	//prepare the county
+	countyItem := countyToStorage(county, now)
 
-	//3. add the guideline to the county
-	guidelines := county.Guidelines
-	guidelines = append(guidelines, guideline)
-	county.Guidelines = guidelines
+	//prepare the rules
+	rulesItems := rulesToStorage(county.ID, rules, now)
 
-	//4. save the county
-	saveFilter := bson.D{primitive.E{Key: "_id", Value: county.ID}}
-	err = sa.db.counties.ReplaceOne(saveFilter, county, nil)
-	if err != nil {
-		return nil, err
+	//prepare the locations
+	var locationsItems []interface{}
+	for _, l := range locations {
+		locationsItems = append(locationsItems, location{ID: l.ID, Name: l.Name, Address1: l.Address1, Address2: l.Address2, City: l.City,
+			State: l.State, ZIP: l.ZIP, Country: l.Country, Latitude: l.Latitude, Longitude: l.Longitude, GeoPoint: newGeoPoint(l.Latitude, l.Longitude), Timezone: l.Timezone, Contact: l.Contact,
+			DaysOfOperation: convertFromDaysOfOperation(l.DaysOfOperation), Exceptions: convertFromLocationExceptions(l.Exceptions), URL: l.URL, Notes: l.Notes, WaitTimeColor: l.WaitTimeColor,
+			ProviderID: l.Provider.ID, CountyID: county.ID, AvailableTests: availableTestsToStorage(l.AvailableTests), DateCreated: now})
 	}
 
-	//5. return the inserted item
-	createdItem := &model.Guideline{ID: id.String(), Name: name, Description: description, Items: items}
-	return createdItem, nil
-}
+	// transaction
+	err := sa.db.dbClient.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
+		err := sessionContext.StartTransaction()
+		if err != nil {
+			log.Printf("error starting a transaction - %s", err)
+			return err
+		}
 
-//FindGuideline finds a guideline
-func (sa *Adapter) FindGuideline(ID string) (*model.Guideline, error) {
-	//1. first find the county
-	filter := bson.D{primitive.E{Key: "guidelines.id", Value: ID}}
-	var result []*county
-	err := sa.db.counties.Find(filter, &result, nil)
-	if err != nil {
+		//1. insert the county
+		_, err = sa.db.counties.InsertOneWithContext(sessionContext, &countyItem)
+		if err != nil {
+			abortTransaction(sessionContext)
+			return err
+		}
+
+		//2. insert the test types rules
+		if len(rulesItems) > 0 {
+			_, err = sa.db.rules.InsertManyWithContext(sessionContext, rulesItems, nil)
+			if err != nil {
+				abortTransaction(sessionContext)
+				return err
+			}
+		}
+
+		//3. insert the access rule
+		if countyAccessRule != nil {
+			storageAccessRule := accessRuleToStorage(county.ID, countyAccessRule, now)
+			_, err = sa.db.accessrules.InsertOneWithContext(sessionContext, &storageAccessRule)
+			if err != nil {
+				abortTransaction(sessionContext)
+				return err
+			}
+		}
+
+		//4. insert the symptom rule
+		if countySymptomRule != nil {
+			storageSymptomRule := symptomRuleToStorage(county.ID, countySymptomRule, now)
+			_, err = sa.db.symptomrules.InsertOneWithContext(sessionContext, &storageSymptomRule)
+			if err != nil {
+				abortTransaction(sessionContext)
+				return err
+			}
+		}
+
+		//5. publish the crules
+		for _, revision := range cRulesRevisions {
+			_, err = sa.publishConfigRevision(sessionContext, revision)
+			if err != nil {
+				abortTransaction(sessionContext)
+				return err
+			}
+		}
+
+		//6. insert the locations
+		if len(locationsItems) > 0 {
+			_, err = sa.db.locations.InsertManyWithContext(sessionContext, locationsItems, nil)
+			if err != nil {
+				abortTransaction(sessionContext)
+				return err
+			}
+		}
+
+		//commit the transaction
+		err = sessionContext.CommitTransaction(sessionContext)
+		if err != nil {
+			fmt.Println(err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+//UpdateCountyConfiguration replaces the county data, rules, access rule and symptom rule, publishes the crules revisions
+//and creates or updates the locations. Only the bundle fields of the existing locations are updated.
+func (sa *Adapter) UpdateCountyConfiguration(ctx context.Context, county *model.County, rules []*model.Rule, countyAccessRule *model.AccessRule,
+	countySymptomRule *model.SymptomRule, cRulesRevisions []*model.ConfigRevision, locations []*model.Location) error {
+	now := time.Now()
+
+	//prepare the county
+	countyItem := countyToStorage(county, now)
+	countyFilter := bson.D{primitive.E{Key: "_id", Value: county.ID}}
+	countyUpdate := bson.D{
+		primitive.E{Key: "$set", Value: bson.D{
+			primitive.E{Key: "name", Value: countyItem.Name},
+			primitive.E{Key: "state_province", Value: countyItem.StateProvince},
+			primitive.E{Key: "country", Value: countyItem.Country},
+			primitive.E{Key: "guidelines", Value: countyItem.Guidelines},
+			primitive.E{Key: "county_statuses", Value: countyItem.CountyStatuses},
+			primitive.E{Key: "date_updated", Value: now},
+		}},
+	}
+
+	//prepare the rules
+	rulesItems := rulesToStorage(county.ID, rules, now)
+	countyItemsFilter := bson.D{primitive.E{Key: "county_id", Value: county.ID}}
+
+	// transaction
+	err := sa.db.dbClient.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
+		err := sessionContext.StartTransaction()
+		if err != nil {
+			log.Printf("error starting a transaction - %s", err)
+			return err
+		}
+
+		//1. update the county
+		result, err := sa.db.counties.UpdateOneWithContext(sessionContext, countyFilter, countyUpdate, nil)
+		if err != nil {
+			abortTransaction(sessionContext)
+			return err
+		}
+		if result.MatchedCount == 0 {
+			abortTransaction(sessionContext)
+			return errors.New("there is no a county for the provided id")
+		}
+
+		//2. replace the test types rules
+		_, err = sa.db.rules.DeleteManyWithContext(sessionContext, countyItemsFilter, nil)
+		if err != nil {
+			abortTransaction(sessionContext)
+			return err
+		}
+		if len(rulesItems) > 0 {
+			_, err = sa.db.rules.InsertManyWithContext(sessionContext, rulesItems, nil)
+			if err != nil {
+				abortTransaction(sessionContext)
+				return err
+			}
+		}
+
+		//3. replace the access rule
+		_, err = sa.db.accessrules.DeleteManyWithContext(sessionContext, countyItemsFilter, nil)
+		if err != nil {
+			abortTransaction(sessionContext)
+			return err
+		}
+		if countyAccessRule != nil {
+			storageAccessRule := accessRuleToStorage(county.ID, countyAccessRule, now)
+			_, err = sa.db.accessrules.InsertOneWithContext(sessionContext, &storageAccessRule)
+			if err != nil {
+				abortTransaction(sessionContext)
+				return err
+			}
+		}
+
+		//4. replace the symptom rule
+		_, err = sa.db.symptomrules.DeleteManyWithContext(sessionContext, countyItemsFilter, nil)
+		if err != nil {
+			abortTransaction(sessionContext)
+			return err
+		}
+		if countySymptomRule != nil {
+			storageSymptomRule := symptomRuleToStorage(county.ID, countySymptomRule, now)
+			_, err = sa.db.symptomrules.InsertOneWithContext(sessionContext, &storageSymptomRule)
+			if err != nil {
+				abortTransaction(sessionContext)
+				return err
+			}
+		}
+
+		//5. publish the crules
+		for _, revision := range cRulesRevisions {
+			_, err = sa.publishConfigRevision(sessionContext, revision)
+			if err != nil {
+				abortTransaction(sessionContext)
+				return err
+			}
+		}
+
+		//6. create or update the locations, the exceptions, the wait time and the appointment settings are not changed
+		for _, l := range locations {
+			filter := bson.D{primitive.E{Key: "_id", Value: l.ID}}
+			update := bson.D{
+				primitive.E{Key: "$set", Value: bson.D{
+					primitive.E{Key: "name", Value: l.Name},
+					primitive.E{Key: "address_1", Value: l.Address1},
+					primitive.E{Key: "address_2", Value: l.Address2},
+					primitive.E{Key: "city", Value: l.City},
+					primitive.E{Key: "state", Value: l.State},
+					primitive.E{Key: "zip", Value: l.ZIP},
+					primitive.E{Key: "country", Value: l.Country},
+					primitive.E{Key: "latitude", Value: l.Latitude},
+					primitive.E{Key: "longitude", Value: l.Longitude},
+					primitive.E{Key: "geo_point", Value: newGeoPoint(l.Latitude, l.Longitude)},
+					primitive.E{Key: "timezone", Value: l.Timezone},
+					primitive.E{Key: "contact", Value: l.Contact},
+					primitive.E{Key: "days_of_operation", Value: convertFromDaysOfOperation(l.DaysOfOperation)},
+					primitive.E{Key: "url", Value: l.URL},
+					primitive.E{Key: "notes", Value: l.Notes},
+					primitive.E{Key: "provider_id", Value: l.Provider.ID},
+					primitive.E{Key: "county_id", Value: county.ID},
+					primitive.E{Key: "available_tests", Value: availableTestsToStorage(l.AvailableTests)},
+					primitive.E{Key: "date_updated", Value: now},
+				}},
+				primitive.E{Key: "$setOnInsert", Value: bson.D{primitive.E{Key: "date_created", Value: now}}},
+			}
+			_, err = sa.db.locations.UpdateOneWithContext(sessionContext, filter, update, options.Update().SetUpsert(true))
+			if err != nil {
+				abortTransaction(sessionContext)
+				return err
+			}
+		}
+
+		//commit the transaction
+		err = sessionContext.CommitTransaction(sessionContext)
+		if err != nil {
+			log.Printf("error on commiting a transaction - %s", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+//CreateGuideline creates a guidline
+func (sa *Adapter) CreateGuideline(ctx context.Context, countyID string, name string, description string, items []model.GuidelineItem) (*model.Guideline, error) {
+	//1. find the county
+	findFilter := bson.D{primitive.E{Key: "_id", Value: countyID}}
+	var result []*county
+	err := sa.db.counties.FindWithContext(ctx, findFilter, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil || len(result) == 0 {
+		//not found
+		return nil, errors.New("there is no a county for the provided id")
+	}
+	county := result[0]
+
+	//2. create guideline
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+	var gdItems []guidlineItem
+	for _, v := range items {
+		item := guidlineItem{Icon: v.Icon, Description: v.Description, Type: v.Type.Value}
+		gdItems = append(gdItems, item)
+	}
+	dateCreated := time.Now()
+	guideline := guidline{ID: id.String(), Name: name, Description: description, Items: gdItems, DateCreated: dateCreated}
+
+	//3. add the guideline to the county
+	guidelines := county.Guidelines
+	guidelines = append(guidelines, guideline)
+	county.Guidelines = guidelines
+
+	//4. save the county
+	saveFilter := bson.D{primitive.E{Key: "_id", Value: county.ID}}
+	err = sa.db.counties.ReplaceOneWithContext(ctx, saveFilter, county, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	//5. return the inserted item
+	createdItem := &model.Guideline{ID: id.String(), Name: name, Description: description, Items: items}
+	return createdItem, nil
+}
+
+//FindGuideline finds a guideline
+func (sa *Adapter) FindGuideline(ctx context.Context, ID string) (*model.Guideline, error) {
+	//1. first find the county
+	filter := bson.D{primitive.E{Key: "guidelines.id", Value: ID}}
+	var result []*county
+	err := sa.db.counties.FindWithContext(ctx, filter, &result, nil)
+	if err != nil {
 		return nil, err
 	}
 	if result == nil || len(result) == 0 {
@@ -1703,11 +2265,11 @@ func (sa *Adapter) FindGuideline(ID string) (*model.Guideline, error) {
 }
 
 //FindGuidelineByCountyID finds guidelines for the provided county id
-func (sa *Adapter) FindGuidelineByCountyID(countyID string) ([]*model.Guideline, error) {
+func (sa *Adapter) FindGuidelineByCountyID(ctx context.Context, countyID string) ([]*model.Guideline, error) {
 	//1. first find the county
 	filter := bson.D{primitive.E{Key: "_id", Value: countyID}}
 	var result []*county
-	err := sa.db.counties.Find(filter, &result, nil)
+	err := sa.db.counties.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1738,11 +2300,11 @@ func (sa *Adapter) FindGuidelineByCountyID(countyID string) ([]*model.Guideline,
 }
 
 //SaveGuideline saves a guideline
-func (sa *Adapter) SaveGuideline(guideline *model.Guideline) error {
+func (sa *Adapter) SaveGuideline(ctx context.Context, guideline *model.Guideline) error {
 	//1. first find the county
 	filter := bson.D{primitive.E{Key: "guidelines.id", Value: guideline.ID}}
 	var result []*county
-	err := sa.db.counties.Find(filter, &result, nil)
+	err := sa.db.counties.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return err
 	}
@@ -1783,7 +2345,7 @@ func (sa *Adapter) SaveGuideline(guideline *model.Guideline) error {
 
 	//3. save the county
 	saveFilter := bson.D{primitive.E{Key: "_id", Value: county.ID}}
-	err = sa.db.counties.ReplaceOne(saveFilter, county, nil)
+	err = sa.db.counties.ReplaceOneWithContext(ctx, saveFilter, county, nil)
 	if err != nil {
 		return err
 	}
@@ -1796,9 +2358,9 @@ func remove(slice []int, s int) []int {
 }
 
 //DeleteGuideline deletes a guideline
-func (sa *Adapter) DeleteGuideline(ID string) error {
+func (sa *Adapter) DeleteGuideline(ctx context.Context, ID string) error {
 	// transaction
-	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
+	err := sa.db.dbClient.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
 		err := sessionContext.StartTransaction()
 		if err != nil {
 			log.Printf("error starting a transaction - %s", err)
@@ -1855,11 +2417,11 @@ func (sa *Adapter) DeleteGuideline(ID string) error {
 }
 
 //CreateCountyStatus creates county status
-func (sa *Adapter) CreateCountyStatus(countyID string, name string, description string) (*model.CountyStatus, error) {
+func (sa *Adapter) CreateCountyStatus(ctx context.Context, countyID string, name string, description string) (*model.CountyStatus, error) {
 	//1. find the county
 	findFilter := bson.D{primitive.E{Key: "_id", Value: countyID}}
 	var result []*county
-	err := sa.db.counties.Find(findFilter, &result, nil)
+	err := sa.db.counties.FindWithContext(ctx, findFilter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1884,7 +2446,7 @@ func (sa *Adapter) CreateCountyStatus(countyID string, name string, description
 
 	//4. save the county
 	saveFilter := bson.D{primitive.E{Key: "_id", Value: county.ID}}
-	err = sa.db.counties.ReplaceOne(saveFilter, county, nil)
+	err = sa.db.counties.ReplaceOneWithContext(ctx, saveFilter, county, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1895,11 +2457,11 @@ func (sa *Adapter) CreateCountyStatus(countyID string, name string, description
 }
 
 //FindCountyStatus finds county status by ID
-func (sa *Adapter) FindCountyStatus(ID string) (*model.CountyStatus, error) {
+func (sa *Adapter) FindCountyStatus(ctx context.Context, ID string) (*model.CountyStatus, error) {
 	//1. first find the county
 	filter := bson.D{primitive.E{Key: "county_statuses.id", Value: ID}}
 	var result []*county
-	err := sa.db.counties.Find(filter, &result, nil)
+	err := sa.db.counties.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1929,11 +2491,11 @@ func (sa *Adapter) FindCountyStatus(ID string) (*model.CountyStatus, error) {
 }
 
 //FindCountyStatusesByCountyID finds county statuses by county ID
-func (sa *Adapter) FindCountyStatusesByCountyID(countyID string) ([]*model.CountyStatus, error) {
+func (sa *Adapter) FindCountyStatusesByCountyID(ctx context.Context, countyID string) ([]*model.CountyStatus, error) {
 	//1. first find the county
 	filter := bson.D{primitive.E{Key: "_id", Value: countyID}}
 	var result []*county
-	err := sa.db.counties.Find(filter, &result, nil)
+	err := sa.db.counties.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1957,11 +2519,11 @@ func (sa *Adapter) FindCountyStatusesByCountyID(countyID string) ([]*model.Count
 }
 
 //SaveCountyStatus saves the county status
-func (sa *Adapter) SaveCountyStatus(entity *model.CountyStatus) error {
+func (sa *Adapter) SaveCountyStatus(ctx context.Context, entity *model.CountyStatus) error {
 	//1. first find the county
 	filter := bson.D{primitive.E{Key: "county_statuses.id", Value: entity.ID}}
 	var result []*county
-	err := sa.db.counties.Find(filter, &result, nil)
+	err := sa.db.counties.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return err
 	}
@@ -1991,7 +2553,7 @@ func (sa *Adapter) SaveCountyStatus(entity *model.CountyStatus) error {
 
 	//3. save the county
 	saveFilter := bson.D{primitive.E{Key: "_id", Value: county.ID}}
-	err = sa.db.counties.ReplaceOne(saveFilter, county, nil)
+	err = sa.db.counties.ReplaceOneWithContext(ctx, saveFilter, county, nil)
 	if err != nil {
 		return err
 	}
@@ -2000,9 +2562,9 @@ func (sa *Adapter) SaveCountyStatus(entity *model.CountyStatus) error {
 }
 
 //DeleteCountyStatus deletes county status
-func (sa *Adapter) DeleteCountyStatus(ID string) error {
+func (sa *Adapter) DeleteCountyStatus(ctx context.Context, ID string) error {
 	// transaction
-	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
+	err := sa.db.dbClient.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
 		err := sessionContext.StartTransaction()
 		if err != nil {
 			log.Printf("error starting a transaction - %s", err)
@@ -2098,10 +2660,10 @@ func (sa *Adapter) DeleteCountyStatus(ID string) error {
 }
 
 //ReadAllTestTypes reads all test types
-func (sa *Adapter) ReadAllTestTypes() ([]*model.TestType, error) {
+func (sa *Adapter) ReadAllTestTypes(ctx context.Context) ([]*model.TestType, error) {
 	filter := bson.D{}
 	var result []*testType
-	err := sa.db.testtypes.Find(filter, &result, nil)
+	err := sa.db.testtypes.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -2125,7 +2687,7 @@ func (sa *Adapter) ReadAllTestTypes() ([]*model.TestType, error) {
 }
 
 //CreateTestType creates a test type
-func (sa *Adapter) CreateTestType(name string, priority *int) (*model.TestType, error) {
+func (sa *Adapter) CreateTestType(ctx context.Context, name string, priority *int) (*model.TestType, error) {
 	id, err := uuid.NewUUID()
 	if err != nil {
 		return nil, err
@@ -2134,7 +2696,7 @@ func (sa *Adapter) CreateTestType(name string, priority *int) (*model.TestType,
 	dateCreated := time.Now()
 
 	testType := testType{ID: id.String(), Name: name, Priority: priority, DateCreated: dateCreated}
-	_, err = sa.db.testtypes.InsertOne(&testType)
+	_, err = sa.db.testtypes.InsertOneWithContext(ctx, &testType)
 	if err != nil {
 		return nil, err
 	}
@@ -2145,10 +2707,10 @@ func (sa *Adapter) CreateTestType(name string, priority *int) (*model.TestType,
 }
 
 //FindTestType finds a test type by ID
-func (sa *Adapter) FindTestType(ID string) (*model.TestType, error) {
+func (sa *Adapter) FindTestType(ctx context.Context, ID string) (*model.TestType, error) {
 	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
 	var result []*testType
-	err := sa.db.testtypes.Find(filter, &result, nil)
+	err := sa.db.testtypes.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -2172,11 +2734,11 @@ func (sa *Adapter) FindTestType(ID string) (*model.TestType, error) {
 }
 
 //FindTestTypesByIDs finds the test types for the provided ids
-func (sa *Adapter) FindTestTypesByIDs(ids []string) ([]*model.TestType, error) {
+func (sa *Adapter) FindTestTypesByIDs(ctx context.Context, ids []string) ([]*model.TestType, error) {
 	filter := bson.M{"_id": bson.M{"$in": ids}}
 
 	var result []*testType
-	err := sa.db.testtypes.Find(filter, &result, nil)
+	err := sa.db.testtypes.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -2203,10 +2765,10 @@ func (sa *Adapter) FindTestTypesByIDs(ids []string) ([]*model.TestType, error) {
 }
 
 //SaveTestType saves the test type
-func (sa *Adapter) SaveTestType(entity *model.TestType) error {
+func (sa *Adapter) SaveTestType(ctx context.Context, entity *model.TestType) error {
 	findFilter := bson.D{primitive.E{Key: "_id", Value: entity.ID}}
 	var result []*testType
-	err := sa.db.testtypes.Find(findFilter, &result, nil)
+	err := sa.db.testtypes.FindWithContext(ctx, findFilter, &result, nil)
 	if err != nil {
 		return err
 	}
@@ -2224,7 +2786,7 @@ func (sa *Adapter) SaveTestType(entity *model.TestType) error {
 
 	//save
 	filter := bson.D{primitive.E{Key: "_id", Value: testType.ID}}
-	err = sa.db.testtypes.ReplaceOne(filter, testType, nil)
+	err = sa.db.testtypes.ReplaceOneWithContext(ctx, filter, testType, nil)
 	if err != nil {
 		return err
 	}
@@ -2233,9 +2795,9 @@ func (sa *Adapter) SaveTestType(entity *model.TestType) error {
 }
 
 //DeleteTestType delete a test type
-func (sa *Adapter) DeleteTestType(ID string) error {
+func (sa *Adapter) DeleteTestType(ctx context.Context, ID string) error {
 	// transaction
-	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
+	err := sa.db.dbClient.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
 		err := sessionContext.StartTransaction()
 		if err != nil {
 			log.Printf("error starting a transaction - %s", err)
@@ -2322,13 +2884,13 @@ func (sa *Adapter) DeleteTestType(ID string) error {
 }
 
 //CreateTestTypeResult creates a test type result
-func (sa *Adapter) CreateTestTypeResult(testTypeID string, name string, nextStep string, nextStepOffset *int,
+func (sa *Adapter) CreateTestTypeResult(ctx context.Context, testTypeID string, name string, nextStep string, nextStepOffset *int,
 	resultExpiresOffset *int) (*model.TestTypeResult, error) {
 
 	//1. find the test type
 	findFilter := bson.D{primitive.E{Key: "_id", Value: testTypeID}}
 	var result []*testType
-	err := sa.db.testtypes.Find(findFilter, &result, nil)
+	err := sa.db.testtypes.FindWithContext(ctx, findFilter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -2354,7 +2916,7 @@ func (sa *Adapter) CreateTestTypeResult(testTypeID string, name string, nextStep
 
 	//4. save the test type
 	saveFilter := bson.D{primitive.E{Key: "_id", Value: testType.ID}}
-	err = sa.db.testtypes.ReplaceOne(saveFilter, testType, nil)
+	err = sa.db.testtypes.ReplaceOneWithContext(ctx, saveFilter, testType, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -2366,11 +2928,11 @@ func (sa *Adapter) CreateTestTypeResult(testTypeID string, name string, nextStep
 }
 
 //FindTestTypeResult finds a test type result by id
-func (sa *Adapter) FindTestTypeResult(ID string) (*model.TestTypeResult, error) {
+func (sa *Adapter) FindTestTypeResult(ctx context.Context, ID string) (*model.TestTypeResult, error) {
 	//1. first find the test type
 	filter := bson.D{primitive.E{Key: "results._id", Value: ID}}
 	var result []*testType
-	err := sa.db.testtypes.Find(filter, &result, nil)
+	err := sa.db.testtypes.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -2400,11 +2962,11 @@ func (sa *Adapter) FindTestTypeResult(ID string) (*model.TestTypeResult, error)
 }
 
 //FindTestTypeResultsByTestTypeID finds all test type results for a test type
-func (sa *Adapter) FindTestTypeResultsByTestTypeID(testTypeID string) ([]*model.TestTypeResult, error) {
+func (sa *Adapter) FindTestTypeResultsByTestTypeID(ctx context.Context, testTypeID string) ([]*model.TestTypeResult, error) {
 	//1. first find the test type
 	filter := bson.D{primitive.E{Key: "_id", Value: testTypeID}}
 	var result []*testType
-	err := sa.db.testtypes.Find(filter, &result, nil)
+	err := sa.db.testtypes.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -2429,11 +2991,11 @@ func (sa *Adapter) FindTestTypeResultsByTestTypeID(testTypeID string) ([]*model.
 }
 
 //SaveTestTypeResult save the test type result
-func (sa *Adapter) SaveTestTypeResult(entity *model.TestTypeResult) error {
+func (sa *Adapter) SaveTestTypeResult(ctx context.Context, entity *model.TestTypeResult) error {
 	//1. first find the test type
 	filter := bson.D{primitive.E{Key: "results._id", Value: entity.ID}}
 	var result []*testType
-	err := sa.db.testtypes.Find(filter, &result, nil)
+	err := sa.db.testtypes.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return err
 	}
@@ -2466,7 +3028,7 @@ func (sa *Adapter) SaveTestTypeResult(entity *model.TestTypeResult) error {
 
 	//3. save the test type
 	saveFilter := bson.D{primitive.E{Key: "_id", Value: testType.ID}}
-	err = sa.db.testtypes.ReplaceOne(saveFilter, testType, nil)
+	err = sa.db.testtypes.ReplaceOneWithContext(ctx, saveFilter, testType, nil)
 	if err != nil {
 		return err
 	}
@@ -2475,9 +3037,9 @@ func (sa *Adapter) SaveTestTypeResult(entity *model.TestTypeResult) error {
 }
 
 //DeleteTestTypeResult deletes a test type result
-func (sa *Adapter) DeleteTestTypeResult(ID string) error {
+func (sa *Adapter) DeleteTestTypeResult(ctx context.Context, ID string) error {
 	// transaction
-	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
+	err := sa.db.dbClient.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
 		err := sessionContext.StartTransaction()
 		if err != nil {
 			log.Printf("error starting a transaction - %s", err)
@@ -2547,10 +3109,10 @@ func (sa *Adapter) DeleteTestTypeResult(ID string) error {
 }
 
 //ReadAllRules reads all rules
-func (sa *Adapter) ReadAllRules() ([]*model.Rule, error) {
+func (sa *Adapter) ReadAllRules(ctx context.Context) ([]*model.Rule, error) {
 	filter := bson.D{}
 	var result []*rule
-	err := sa.db.rules.Find(filter, &result, nil)
+	err := sa.db.rules.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -2575,10 +3137,10 @@ func (sa *Adapter) ReadAllRules() ([]*model.Rule, error) {
 }
 
 //FindRulesByCountyID finds the rules for a county
-func (sa *Adapter) FindRulesByCountyID(countyID string) ([]*model.Rule, error) {
+func (sa *Adapter) FindRulesByCountyID(ctx context.Context, countyID string) ([]*model.Rule, error) {
 	filter := bson.D{primitive.E{Key: "county_id", Value: countyID}}
 	var result []*rule
-	err := sa.db.rules.Find(filter, &result, nil)
+	err := sa.db.rules.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -2607,10 +3169,10 @@ func (sa *Adapter) FindRulesByCountyID(countyID string) ([]*model.Rule, error) {
 }
 
 //FindRule finds a rule
-func (sa *Adapter) FindRule(ID string) (*model.Rule, error) {
+func (sa *Adapter) FindRule(ctx context.Context, ID string) (*model.Rule, error) {
 	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
 	var result []*rule
-	err := sa.db.rules.Find(filter, &result, nil)
+	err := sa.db.rules.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -2635,10 +3197,10 @@ func (sa *Adapter) FindRule(ID string) (*model.Rule, error) {
 }
 
 //FindRuleByCountyIDTestTypeID finds the rule for a county and test type
-func (sa *Adapter) FindRuleByCountyIDTestTypeID(countyID string, testTypeID string) (*model.Rule, error) {
+func (sa *Adapter) FindRuleByCountyIDTestTypeID(ctx context.Context, countyID string, testTypeID string) (*model.Rule, error) {
 	filter := bson.D{primitive.E{Key: "county_id", Value: countyID}, primitive.E{Key: "test_type_id", Value: testTypeID}}
 	var result []rule
-	err := sa.db.rules.Find(filter, &result, nil)
+	err := sa.db.rules.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -2663,12 +3225,12 @@ func (sa *Adapter) FindRuleByCountyIDTestTypeID(countyID string, testTypeID stri
 }
 
 //CreateRule create a rule
-func (sa *Adapter) CreateRule(countyID string, testTypeID string, priority *int, resultsStates []model.TestTypeResultCountyStatus) (*model.Rule, error) {
+func (sa *Adapter) CreateRule(ctx context.Context, countyID string, testTypeID string, priority *int, resultsStates []model.TestTypeResultCountyStatus) (*model.Rule, error) {
 
 	var resultItem model.Rule
 
 	// transaction
-	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
+	err := sa.db.dbClient.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
 		err := sessionContext.StartTransaction()
 		if err != nil {
 			log.Printf("error starting a transaction - %s", err)
@@ -2726,9 +3288,9 @@ func (sa *Adapter) CreateRule(countyID string, testTypeID string, priority *int,
 }
 
 //SaveRule saves a rule
-func (sa *Adapter) SaveRule(entity *model.Rule) error {
+func (sa *Adapter) SaveRule(ctx context.Context, entity *model.Rule) error {
 	// transaction
-	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
+	err := sa.db.dbClient.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
 		err := sessionContext.StartTransaction()
 		if err != nil {
 			log.Printf("error starting a transaction - %s", err)
@@ -2795,9 +3357,9 @@ func (sa *Adapter) SaveRule(entity *model.Rule) error {
 }
 
 //DeleteRule deletes a rule
-func (sa *Adapter) DeleteRule(ID string) error {
+func (sa *Adapter) DeleteRule(ctx context.Context, ID string) error {
 	// transaction
-	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
+	err := sa.db.dbClient.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
 		err := sessionContext.StartTransaction()
 		if err != nil {
 			log.Printf("error starting a transaction - %s", err)
@@ -2848,10 +3410,10 @@ func (sa *Adapter) DeleteRule(ID string) error {
 }
 
 //ReadAllLocations reads all the locations
-func (sa *Adapter) ReadAllLocations() ([]*model.Location, error) {
+func (sa *Adapter) ReadAllLocations(ctx context.Context) ([]*model.Location, error) {
 	filter := bson.D{}
 	var result []*location
-	err := sa.db.locations.Find(filter, &result, nil)
+	err := sa.db.locations.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -2871,7 +3433,7 @@ func (sa *Adapter) ReadAllLocations() ([]*model.Location, error) {
 			locationEntity := &model.Location{ID: location.ID, Name: location.Name, Address1: location.Address1,
 				Address2: location.Address2, City: location.City, State: location.State, ZIP: location.ZIP, Country: location.Country,
 				Latitude: location.Latitude, Longitude: location.Longitude, Contact: location.Contact, Timezone: location.Timezone,
-				DaysOfOperation: daysOfOperation, URL: location.URL, Notes: location.Notes, WaitTimeColor: location.WaitTimeColor,
+				DaysOfOperation: daysOfOperation, Exceptions: convertToLocationExceptions(location.Exceptions), URL: location.URL, Notes: location.Notes, WaitTimeColor: location.WaitTimeColor, WaitTime: location.WaitTime, AppointmentSettings: location.AppointmentSettings,
 				Provider: provider, County: county, AvailableTests: avTests}
 			resultList = append(resultList, locationEntity)
 		}
@@ -2880,7 +3442,7 @@ func (sa *Adapter) ReadAllLocations() ([]*model.Location, error) {
 }
 
 //CreateLocation creates a location
-func (sa *Adapter) CreateLocation(providerID string, countyID string, name string, address1 string, address2 string, city string,
+func (sa *Adapter) CreateLocation(ctx context.Context, providerID string, countyID string, name string, address1 string, address2 string, city string,
 	state string, zip string, country string, latitude float64, longitude float64, contact string, daysOfOperation []model.OperationDay,
 	url string, notes string, waitTimeColor *string, availableTests []string) (*model.Location, error) {
 
@@ -2893,10 +3455,10 @@ func (sa *Adapter) CreateLocation(providerID string, countyID string, name strin
 
 	doo := convertFromDaysOfOperation(daysOfOperation)
 	location := location{ID: id.String(), Name: name, Address1: address1, Address2: address2, City: city,
-		State: state, ZIP: zip, Country: country, Latitude: latitude, Longitude: longitude, Timezone: "America/Chicago", Contact: contact,
+		State: state, ZIP: zip, Country: country, Latitude: latitude, Longitude: longitude, GeoPoint: newGeoPoint(latitude, longitude), Timezone: "America/Chicago", Contact: contact,
 		DaysOfOperation: doo, URL: url, Notes: notes, WaitTimeColor: waitTimeColor, ProviderID: providerID, CountyID: countyID,
 		AvailableTests: availableTests, DateCreated: dateCreated}
-	_, err = sa.db.locations.InsertOne(&location)
+	_, err = sa.db.locations.InsertOneWithContext(ctx, &location)
 	if err != nil {
 		return nil, err
 	}
@@ -2913,16 +3475,16 @@ func (sa *Adapter) CreateLocation(providerID string, countyID string, name strin
 	}
 	result := &model.Location{ID: id.String(), Name: name, Address1: address1, Address2: address2, City: city,
 		State: state, ZIP: zip, Country: country, Latitude: latitude, Longitude: longitude, Contact: contact,
-		DaysOfOperation: daysOfOperation, URL: url, Notes: notes, WaitTimeColor: waitTimeColor, Provider: provider, County: county, AvailableTests: avTests}
+		DaysOfOperation: daysOfOperation, Exceptions: convertToLocationExceptions(location.Exceptions), URL: url, Notes: notes, WaitTimeColor: waitTimeColor, Provider: provider, County: county, AvailableTests: avTests}
 	return result, nil
 }
 
 //FindLocationsByProviderIDCountyID finds the locations for a provider and county
-func (sa *Adapter) FindLocationsByProviderIDCountyID(providerID string, countyID string) ([]*model.Location, error) {
+func (sa *Adapter) FindLocationsByProviderIDCountyID(ctx context.Context, providerID string, countyID string) ([]*model.Location, error) {
 	filter := bson.D{primitive.E{Key: "provider_id", Value: providerID},
 		primitive.E{Key: "county_id", Value: countyID}}
 	var result []*location
-	err := sa.db.locations.Find(filter, &result, nil)
+	err := sa.db.locations.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -2946,32 +3508,36 @@ func (sa *Adapter) FindLocationsByProviderIDCountyID(providerID string, countyID
 		locationEntity := &model.Location{ID: location.ID, Name: location.Name, Address1: location.Address1,
 			Address2: location.Address2, City: location.City, State: location.State, ZIP: location.ZIP,
 			Country: location.Country, Latitude: location.Latitude, Longitude: location.Longitude, Timezone: location.Timezone,
-			Contact: location.Contact, DaysOfOperation: daysOfOperations, URL: location.URL, Notes: location.Notes,
-			WaitTimeColor: location.WaitTimeColor, Provider: provider, County: county, AvailableTests: avTests}
+			Contact: location.Contact, DaysOfOperation: daysOfOperations, Exceptions: convertToLocationExceptions(location.Exceptions), URL: location.URL, Notes: location.Notes,
+			WaitTimeColor: location.WaitTimeColor, WaitTime: location.WaitTime, AppointmentSettings: location.AppointmentSettings, Provider: provider, County: county, AvailableTests: avTests}
 		resultList = append(resultList, locationEntity)
 	}
 	return resultList, nil
 }
 
 type locationProviderJoin struct {
-	ID              string         `bson:"_id"`
-	Name            string         `bson:"name"`
-	Address1        string         `bson:"address_1"`
-	Address2        string         `bson:"address_2"`
-	City            string         `bson:"city"`
-	State           string         `bson:"state"`
-	ZIP             string         `bson:"zip"`
-	Country         string         `bson:"country"`
-	Latitude        float64        `bson:"latitude"`
-	Longitude       float64        `bson:"longitude"`
-	Timezone        string         `bson:"timezone"`
-	Contact         string         `bson:"contact"`
-	DaysOfOperation []operationDay `bson:"days_of_operation"`
-	URL             string         `bson:"url"`
-	Notes           string         `bson:"notes"`
-	WaitTimeColor   *string        `bson:"wait_time_color"`
-	AvailableTests  []string       `bson:"available_tests"`
-	CountyID        string         `bson:"county_id"`
+	ID              string                `bson:"_id"`
+	Name            string                `bson:"name"`
+	Address1        string                `bson:"address_1"`
+	Address2        string                `bson:"address_2"`
+	City            string                `bson:"city"`
+	State           string                `bson:"state"`
+	ZIP             string                `bson:"zip"`
+	Country         string                `bson:"country"`
+	Latitude        float64               `bson:"latitude"`
+	Longitude       float64               `bson:"longitude"`
+	Timezone        string                `bson:"timezone"`
+	Contact         string                `bson:"contact"`
+	DaysOfOperation []operationDay        `bson:"days_of_operation"`
+	Exceptions      []locationException   `bson:"exceptions"`
+	URL             string                `bson:"url"`
+	Notes           string                `bson:"notes"`
+	WaitTimeColor   *string               `bson:"wait_time_color"`
+	WaitTime        *model.WaitTimeReport `bson:"wait_time"`
+	AvailableTests  []string              `bson:"available_tests"`
+	CountyID        string                `bson:"county_id"`
+
+	AppointmentSettings *model.AppointmentSettings `bson:"appointment_settings"`
 
 	ProviderID                  string   `bson:"provider_id"`
 	ProviderName                string   `bson:"provider_name"`
@@ -2979,7 +3545,7 @@ type locationProviderJoin struct {
 }
 
 //FindLocationsByCountyIDDeep finds the locations for a county - deep request!
-func (sa *Adapter) FindLocationsByCountyIDDeep(countyID string) ([]*model.Location, error) {
+func (sa *Adapter) FindLocationsByCountyIDDeep(ctx context.Context, countyID string) ([]*model.Location, error) {
 	pipeline := []bson.M{
 		{"$lookup": bson.M{
 			"from":         "providers",
@@ -2991,12 +3557,12 @@ func (sa *Adapter) FindLocationsByCountyIDDeep(countyID string) ([]*model.Locati
 		{"$unwind": "$provider"},
 		{"$project": bson.M{
 			"_id": 1, "name": 1, "address_1": 1, "address_2": 1, "city": 1, "state": 1, "zip": 1, "country": 1, "latitude": 1, "longitude": 1, "timezone": 1,
-			"contact": 1, "days_of_operation": 1, "url": 1, "notes": 1, "wait_time_color": 1, "available_tests": 1, "county_id": 1,
+			"contact": 1, "days_of_operation": 1, "exceptions": 1, "url": 1, "notes": 1, "wait_time_color": 1, "wait_time": 1, "appointment_settings": 1, "available_tests": 1, "county_id": 1,
 			"provider_id": "$provider._id", "provider_name": "$provider.provider_name", "provider_available_mechanisms": "$provider.available_mechanisms",
 		}}}
 
 	var result []*locationProviderJoin
-	err := sa.db.locations.Aggregate(pipeline, &result, nil)
+	err := sa.db.locations.AggregateWithContext(ctx, pipeline, &result, nil)
 	if err != nil {
 		return nil, err
 
@@ -3021,15 +3587,15 @@ func (sa *Adapter) FindLocationsByCountyIDDeep(countyID string) ([]*model.Locati
 		daysOfOperations := convertToDaysOfOperation(location.DaysOfOperation)
 		locationEntity := &model.Location{ID: location.ID, Name: location.Name, Address1: location.Address1, Address2: location.Address2,
 			City: location.City, State: location.State, ZIP: location.ZIP, Country: location.Country, Latitude: location.Latitude, Timezone: location.Timezone,
-			Longitude: location.Longitude, Contact: location.Contact, DaysOfOperation: daysOfOperations, URL: location.URL,
-			Notes: location.Notes, WaitTimeColor: location.WaitTimeColor, Provider: provider, County: county, AvailableTests: avTests}
+			Longitude: location.Longitude, Contact: location.Contact, DaysOfOperation: daysOfOperations, Exceptions: convertToLocationExceptions(location.Exceptions), URL: location.URL,
+			Notes: location.Notes, WaitTimeColor: location.WaitTimeColor, WaitTime: location.WaitTime, AppointmentSettings: location.AppointmentSettings, Provider: provider, County: county, AvailableTests: avTests}
 		resultList = append(resultList, locationEntity)
 	}
 	return resultList, nil
 }
 
 //FindLocationsByCountiesDeep finds the locations for a list of county items - deep request!
-func (sa *Adapter) FindLocationsByCountiesDeep(countyIDs []string) ([]*model.Location, error) {
+func (sa *Adapter) FindLocationsByCountiesDeep(ctx context.Context, countyIDs []string) ([]*model.Location, error) {
 	pipeline := []bson.M{
 		{"$lookup": bson.M{
 			"from":         "providers",
@@ -3041,12 +3607,12 @@ func (sa *Adapter) FindLocationsByCountiesDeep(countyIDs []string) ([]*model.Loc
 		{"$unwind": "$provider"},
 		{"$project": bson.M{
 			"_id": 1, "name": 1, "address_1": 1, "address_2": 1, "city": 1, "state": 1, "zip": 1, "country": 1, "latitude": 1, "longitude": 1, "timezone": 1,
-			"contact": 1, "days_of_operation": 1, "url": 1, "notes": 1, "wait_time_color": 1, "available_tests": 1, "county_id": 1,
+			"contact": 1, "days_of_operation": 1, "exceptions": 1, "url": 1, "notes": 1, "wait_time_color": 1, "wait_time": 1, "appointment_settings": 1, "available_tests": 1, "county_id": 1,
 			"provider_id": "$provider._id", "provider_name": "$provider.provider_name", "provider_available_mechanisms": "$provider.available_mechanisms",
 		}}}
 
 	var result []*locationProviderJoin
-	err := sa.db.locations.Aggregate(pipeline, &result, nil)
+	err := sa.db.locations.AggregateWithContext(ctx, pipeline, &result, nil)
 	if err != nil {
 		return nil, err
 
@@ -3072,18 +3638,94 @@ func (sa *Adapter) FindLocationsByCountiesDeep(countyIDs []string) ([]*model.Loc
 		locationEntity := &model.Location{ID: location.ID, Name: location.Name, Address1: location.Address1,
 			Address2: location.Address2, City: location.City, State: location.State, ZIP: location.ZIP,
 			Country: location.Country, Latitude: location.Latitude, Longitude: location.Longitude, Timezone: location.Timezone,
-			Contact: location.Contact, DaysOfOperation: daysOfOperations, URL: location.URL, Notes: location.Notes, WaitTimeColor: location.WaitTimeColor,
+			Contact: location.Contact, DaysOfOperation: daysOfOperations, Exceptions: convertToLocationExceptions(location.Exceptions), URL: location.URL, Notes: location.Notes, WaitTimeColor: location.WaitTimeColor, WaitTime: location.WaitTime, AppointmentSettings: location.AppointmentSettings,
 			Provider: provider, County: county, AvailableTests: avTests}
 		resultList = append(resultList, locationEntity)
 	}
 	return resultList, nil
 }
 
+type locationDistanceJoin struct {
+	locationProviderJoin `bson:",inline"`
+
+	Distance float64 `bson:"distance"`
+}
+
+//FindLocationsNearby finds the locations within the radius in meters from the point, the nearest first - deep request!
+//The test type and the provider are optional filters, there is no limit if the limit is 0.
+func (sa *Adapter) FindLocationsNearby(ctx context.Context, latitude float64, longitude float64, radius float64,
+	testTypeID *string, providerID *string, limit int64) ([]*model.NearbyLocation, error) {
+	query := bson.M{}
+	if testTypeID != nil {
+		query["available_tests"] = *testTypeID
+	}
+	if providerID != nil {
+		query["provider_id"] = *providerID
+	}
+	pipeline := []bson.M{
+		{"$geoNear": bson.M{
+			"near":          bson.M{"type": "Point", "coordinates": bson.A{longitude, latitude}},
+			"key":           "geo_point",
+			"distanceField": "distance",
+			"maxDistance":   radius,
+			"spherical":     true,
+			"query":         query,
+		}},
+	}
+	pipeline = append(pipeline,
+		bson.M{"$lookup": bson.M{
+			"from":         "providers",
+			"localField":   "provider_id",
+			"foreignField": "_id",
+			"as":           "provider",
+		}},
+		bson.M{"$unwind": "$provider"},
+		bson.M{"$project": bson.M{
+			"_id": 1, "name": 1, "address_1": 1, "address_2": 1, "city": 1, "state": 1, "zip": 1, "country": 1, "latitude": 1, "longitude": 1, "timezone": 1,
+			"contact": 1, "days_of_operation": 1, "exceptions": 1, "url": 1, "notes": 1, "wait_time_color": 1, "wait_time": 1, "appointment_settings": 1, "available_tests": 1, "county_id": 1,
+			"provider_id": "$provider._id", "provider_name": "$provider.provider_name", "provider_available_mechanisms": "$provider.available_mechanisms",
+			"distance": 1,
+		}},
+		//$lookup does not keep the order
+		bson.M{"$sort": bson.M{"distance": 1}})
+	//the locations without a provider are removed by $unwind so the limit is applied after it
+	if limit > 0 {
+		pipeline = append(pipeline, bson.M{"$limit": limit})
+	}
+
+	var result []*locationDistanceJoin
+	err := sa.db.locations.AggregateWithContext(ctx, pipeline, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resultList []*model.NearbyLocation
+	for _, location := range result {
+		provider := model.Provider{ID: location.ProviderID, Name: location.ProviderName,
+			AvailableMechanisms: location.ProviderAvailableMechanisms}
+		county := model.County{ID: location.CountyID}
+		var avTests []model.TestType
+		if location.AvailableTests != nil {
+			for _, id := range location.AvailableTests {
+				testType := model.TestType{ID: id}
+				avTests = append(avTests, testType)
+			}
+		}
+		daysOfOperations := convertToDaysOfOperation(location.DaysOfOperation)
+		locationEntity := &model.Location{ID: location.ID, Name: location.Name, Address1: location.Address1, Address2: location.Address2,
+			City: location.City, State: location.State, ZIP: location.ZIP, Country: location.Country, Latitude: location.Latitude, Timezone: location.Timezone,
+			Longitude: location.Longitude, Contact: location.Contact, DaysOfOperation: daysOfOperations, Exceptions: convertToLocationExceptions(location.Exceptions), URL: location.URL,
+			Notes: location.Notes, WaitTimeColor: location.WaitTimeColor, WaitTime: location.WaitTime, AppointmentSettings: location.AppointmentSettings, Provider: provider, County: county, AvailableTests: avTests}
+		resultList = append(resultList, &model.NearbyLocation{Location: locationEntity, Distance: location.Distance})
+	}
+	return resultList, nil
+}
+
 //FindLocation finds a location by id
-func (sa *Adapter) FindLocation(ID string) (*model.Location, error) {
+func (sa *Adapter) FindLocation(ctx context.Context, ID string) (*model.Location, error) {
 	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
 	var result []*location
-	err := sa.db.locations.Find(filter, &result, nil)
+	err := sa.db.locations.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -3106,16 +3748,16 @@ func (sa *Adapter) FindLocation(ID string) (*model.Location, error) {
 	resultEntity := &model.Location{ID: location.ID, Name: location.Name, Address1: location.Address1,
 		Address2: location.Address2, City: location.City, State: location.State, ZIP: location.ZIP,
 		Country: location.Country, Latitude: location.Latitude, Longitude: location.Longitude, Timezone: location.Timezone,
-		Contact: location.Contact, DaysOfOperation: daysOfOperations, URL: location.URL, Notes: location.Notes,
-		WaitTimeColor: location.WaitTimeColor, Provider: provider, County: county, AvailableTests: avTests}
+		Contact: location.Contact, DaysOfOperation: daysOfOperations, Exceptions: convertToLocationExceptions(location.Exceptions), URL: location.URL, Notes: location.Notes,
+		WaitTimeColor: location.WaitTimeColor, WaitTime: location.WaitTime, AppointmentSettings: location.AppointmentSettings, Provider: provider, County: county, AvailableTests: avTests}
 	return resultEntity, nil
 }
 
 //SaveLocation save a location
-func (sa *Adapter) SaveLocation(entity *model.Location) error {
+func (sa *Adapter) SaveLocation(ctx context.Context, entity *model.Location) error {
 	findFilter := bson.D{primitive.E{Key: "_id", Value: entity.ID}}
 	var result []*location
-	err := sa.db.locations.Find(findFilter, &result, nil)
+	err := sa.db.locations.FindWithContext(ctx, findFilter, &result, nil)
 	if err != nil {
 		return err
 	}
@@ -3135,6 +3777,7 @@ func (sa *Adapter) SaveLocation(entity *model.Location) error {
 	location.Country = entity.Country
 	location.Latitude = entity.Latitude
 	location.Longitude = entity.Longitude
+	location.GeoPoint = newGeoPoint(entity.Latitude, entity.Longitude)
 	location.Timezone = entity.Timezone
 	location.Contact = entity.Contact
 	location.DaysOfOperation = convertFromDaysOfOperation(entity.DaysOfOperation)
@@ -3153,7 +3796,7 @@ func (sa *Adapter) SaveLocation(entity *model.Location) error {
 	location.DateUpdated = &dateUpdated
 
 	filter := bson.D{primitive.E{Key: "_id", Value: location.ID}}
-	err = sa.db.locations.ReplaceOne(filter, location, nil)
+	err = sa.db.locations.ReplaceOneWithContext(ctx, filter, location, nil)
 	if err != nil {
 		return err
 	}
@@ -3161,39 +3804,421 @@ func (sa *Adapter) SaveLocation(entity *model.Location) error {
 	return nil
 }
 
-//DeleteLocation deletes a location
-func (sa *Adapter) DeleteLocation(ID string) error {
+//UpdateLocationExceptions replaces the exceptions of a location, the other location fields are not changed
+func (sa *Adapter) UpdateLocationExceptions(ctx context.Context, ID string, exceptions []model.LocationException) error {
 	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
-	result, err := sa.db.locations.DeleteOne(filter, nil)
+	update := bson.D{
+		primitive.E{Key: "$set", Value: bson.D{
+			primitive.E{Key: "exceptions", Value: convertFromLocationExceptions(exceptions)},
+			primitive.E{Key: "date_updated", Value: time.Now()},
+		}},
+	}
+	result, err := sa.db.locations.UpdateOneWithContext(ctx, filter, update, nil)
 	if err != nil {
 		return err
 	}
-	if result == nil {
-		return errors.New("result is nil for location item with id " + ID)
+	if result.MatchedCount == 0 {
+		return errors.New("there is no a location for the provided id")
 	}
-	deletedCount := result.DeletedCount
-	if deletedCount == 0 {
-		return errors.New("there is no a location for id " + ID)
+	return nil
+}
+
+//UpdateLocationWaitTime sets the latest reported wait time and the wait time color of a location, the other location fields are not changed
+func (sa *Adapter) UpdateLocationWaitTime(ctx context.Context, ID string, waitTime *model.WaitTimeReport, waitTimeColor *string) error {
+	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
+	update := bson.D{
+		primitive.E{Key: "$set", Value: bson.D{
+			primitive.E{Key: "wait_time", Value: waitTime},
+			primitive.E{Key: "wait_time_color", Value: waitTimeColor},
+			primitive.E{Key: "date_updated", Value: time.Now()},
+		}},
 	}
-	if deletedCount > 1 {
-		return errors.New("deleted more than one records for id " + ID)
+	result, err := sa.db.locations.UpdateOneWithContext(ctx, filter, update, nil)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("there is no a location for the provided id")
 	}
-
-	//success - count = 1
 	return nil
 }
 
-//FindSymptom finds a symptom
-func (sa *Adapter) FindSymptom(ID string) (*model.Symptom, error) {
-	//1. first find the symptom group
-	filter := bson.D{primitive.E{Key: "symptoms.id", Value: ID}}
-	var result []*symptomGroup
-	err := sa.db.symptomgroups.Find(filter, &result, nil)
+//CreateWaitTimeReport adds a wait time report to the history
+func (sa *Adapter) CreateWaitTimeReport(ctx context.Context, report *model.WaitTimeReport) error {
+	_, err := sa.db.waittimereports.InsertOneWithContext(ctx, report)
+	return err
+}
+
+//FindWaitTimeReports finds the latest wait time reports of a location
+func (sa *Adapter) FindWaitTimeReports(ctx context.Context, locationID string, limit int64) ([]*model.WaitTimeReport, error) {
+	filter := bson.D{primitive.E{Key: "location_id", Value: locationID}}
+	var result []*model.WaitTimeReport
+
+	options := options.Find()
+	options.SetSort(bson.D{primitive.E{Key: "date_created", Value: -1}})
+	options.SetLimit(limit)
+
+	err := sa.db.waittimereports.FindWithContext(ctx, filter, &result, options)
 	if err != nil {
 		return nil, err
 	}
-	if result == nil || len(result) == 0 {
-		//not found
+	return result, nil
+}
+
+//UpdateLocationAppointmentSettings sets the appointment settings of a location, the other location fields are not changed
+func (sa *Adapter) UpdateLocationAppointmentSettings(ctx context.Context, ID string, settings *model.AppointmentSettings) error {
+	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
+	update := bson.D{
+		primitive.E{Key: "$set", Value: bson.D{
+			primitive.E{Key: "appointment_settings", Value: settings},
+			primitive.E{Key: "date_updated", Value: time.Now()},
+		}},
+	}
+	result, err := sa.db.locations.UpdateOneWithContext(ctx, filter, update, nil)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("there is no a location for the provided id")
+	}
+	return nil
+}
+
+//appointmentSlot keeps how many appointments are booked for a slot, so the capacity can be checked atomically
+type appointmentSlot struct {
+	ID         string    `bson:"_id"` //the location id and the start time
+	LocationID string    `bson:"location_id"`
+	Start      time.Time `bson:"start"`
+	Booked     int       `bson:"booked"`
+}
+
+func appointmentSlotID(locationID string, start time.Time) string {
+	return fmt.Sprintf("%s_%d", locationID, start.Unix())
+}
+
+//reserveAppointmentSlot takes a place in the slot. It gives false if the slot is full.
+func (sa *Adapter) reserveAppointmentSlot(ctx context.Context, locationID string, start time.Time, capacity int) (bool, error) {
+	slotID := appointmentSlotID(locationID, start)
+
+	//create the slot if it does not exist
+	filter := bson.D{primitive.E{Key: "_id", Value: slotID}}
+	insert := bson.D{
+		primitive.E{Key: "$setOnInsert", Value: bson.D{
+			primitive.E{Key: "location_id", Value: locationID},
+			primitive.E{Key: "start", Value: start},
+			primitive.E{Key: "booked", Value: 0},
+		}},
+	}
+	_, err := sa.db.appointmentslots.UpdateOneWithContext(ctx, filter, insert, options.Update().SetUpsert(true))
+	if err != nil {
+		return false, err
+	}
+
+	//the update of a document is atomic, so the place is taken only if there is a free one
+	filter = bson.D{primitive.E{Key: "_id", Value: slotID}, primitive.E{Key: "booked", Value: bson.M{"$lt": capacity}}}
+	update := bson.D{primitive.E{Key: "$inc", Value: bson.D{primitive.E{Key: "booked", Value: 1}}}}
+	result, err := sa.db.appointmentslots.UpdateOneWithContext(ctx, filter, update, nil)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+//releaseAppointmentSlot frees a place in the slot
+func (sa *Adapter) releaseAppointmentSlot(ctx context.Context, locationID string, start time.Time) error {
+	filter := bson.D{primitive.E{Key: "_id", Value: appointmentSlotID(locationID, start)}, primitive.E{Key: "booked", Value: bson.M{"$gt": 0}}}
+	update := bson.D{primitive.E{Key: "$inc", Value: bson.D{primitive.E{Key: "booked", Value: -1}}}}
+	_, err := sa.db.appointmentslots.UpdateOneWithContext(ctx, filter, update, nil)
+	return err
+}
+
+//CreateAppointment creates an appointment if its slot is not full. It gives false if the slot is full. It uses a transaction
+//which is retried on transient errors. The user can have only one booked appointment at a location.
+func (sa *Adapter) CreateAppointment(ctx context.Context, appointment *model.Appointment, capacity int) (bool, error) {
+	created := false
+
+	err := retryTransientTransaction(func() error {
+		created = false
+
+		// transaction
+		return sa.db.dbClient.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
+			err := sessionContext.StartTransaction()
+			if err != nil {
+				log.Printf("error starting a transaction - %s", err)
+				return err
+			}
+
+			reserved, err := sa.reserveAppointmentSlot(sessionContext, appointment.LocationID, appointment.Start, capacity)
+			if err != nil {
+				abortTransaction(sessionContext)
+				return err
+			}
+			if !reserved {
+				abortTransaction(sessionContext)
+				return nil
+			}
+
+			_, err = sa.db.appointments.InsertOneWithContext(sessionContext, appointment)
+			if err != nil {
+				abortTransaction(sessionContext)
+				return err
+			}
+
+			err = sessionContext.CommitTransaction(sessionContext)
+			if err != nil {
+				log.Printf("error on commiting a transaction - %s", err)
+				return err
+			}
+			created = true
+			return nil
+		})
+	})
+	if err != nil {
+		//the unique index allows only one booked appointment per user and location
+		if isDuplicateKeyError(err) {
+			return false, errors.New("there is a booked appointment at this location, reschedule it instead")
+		}
+		return false, err
+	}
+	return created, nil
+}
+
+//RescheduleAppointment moves a booked appointment to the start and the end it has. It gives false if the new slot is full. It uses a transaction
+//which is retried on transient errors
+func (sa *Adapter) RescheduleAppointment(ctx context.Context, appointment *model.Appointment, previousStart time.Time, capacity int) (bool, error) {
+	rescheduled := false
+
+	err := retryTransientTransaction(func() error {
+		rescheduled = false
+
+		// transaction
+		return sa.db.dbClient.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
+			err := sessionContext.StartTransaction()
+			if err != nil {
+				log.Printf("error starting a transaction - %s", err)
+				return err
+			}
+
+			reserved, err := sa.reserveAppointmentSlot(sessionContext, appointment.LocationID, appointment.Start, capacity)
+			if err != nil {
+				abortTransaction(sessionContext)
+				return err
+			}
+			if !reserved {
+				abortTransaction(sessionContext)
+				return nil
+			}
+			err = sa.releaseAppointmentSlot(sessionContext, appointment.LocationID, previousStart)
+			if err != nil {
+				abortTransaction(sessionContext)
+				return err
+			}
+
+			filter := bson.D{primitive.E{Key: "_id", Value: appointment.ID}, primitive.E{Key: "status", Value: "booked"}}
+			update := bson.D{
+				primitive.E{Key: "$set", Value: bson.D{
+					primitive.E{Key: "start", Value: appointment.Start},
+					primitive.E{Key: "end", Value: appointment.End},
+					primitive.E{Key: "reminder_sent", Value: appointment.ReminderSent},
+					primitive.E{Key: "date_updated", Value: appointment.DateUpdated},
+				}},
+			}
+			result, err := sa.db.appointments.UpdateOneWithContext(sessionContext, filter, update, nil)
+			if err != nil {
+				abortTransaction(sessionContext)
+				return err
+			}
+			if result.MatchedCount == 0 {
+				abortTransaction(sessionContext)
+				return errors.New("there is no a booked appointment for the provided id")
+			}
+
+			err = sessionContext.CommitTransaction(sessionContext)
+			if err != nil {
+				log.Printf("error on commiting a transaction - %s", err)
+				return err
+			}
+			rescheduled = true
+			return nil
+		})
+	})
+	if err != nil {
+		return false, err
+	}
+	return rescheduled, nil
+}
+
+//CancelAppointment cancels a booked appointment and frees its place in the slot. It uses a transaction which is retried on transient errors
+func (sa *Adapter) CancelAppointment(ctx context.Context, appointment *model.Appointment) error {
+	return retryTransientTransaction(func() error {
+		// transaction
+		return sa.db.dbClient.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
+			err := sessionContext.StartTransaction()
+			if err != nil {
+				log.Printf("error starting a transaction - %s", err)
+				return err
+			}
+
+			filter := bson.D{primitive.E{Key: "_id", Value: appointment.ID}, primitive.E{Key: "status", Value: "booked"}}
+			update := bson.D{
+				primitive.E{Key: "$set", Value: bson.D{
+					primitive.E{Key: "status", Value: "cancelled"},
+					primitive.E{Key: "date_updated", Value: time.Now()},
+				}},
+			}
+			result, err := sa.db.appointments.UpdateOneWithContext(sessionContext, filter, update, nil)
+			if err != nil {
+				abortTransaction(sessionContext)
+				return err
+			}
+			if result.MatchedCount == 0 {
+				abortTransaction(sessionContext)
+				return errors.New("there is no a booked appointment for the provided id")
+			}
+			err = sa.releaseAppointmentSlot(sessionContext, appointment.LocationID, appointment.Start)
+			if err != nil {
+				abortTransaction(sessionContext)
+				return err
+			}
+
+			err = sessionContext.CommitTransaction(sessionContext)
+			if err != nil {
+				log.Printf("error on commiting a transaction - %s", err)
+				return err
+			}
+			return nil
+		})
+	})
+}
+
+//SetAppointmentStatus sets the status of a booked appointment
+func (sa *Adapter) SetAppointmentStatus(ctx context.Context, ID string, status string) error {
+	filter := bson.D{primitive.E{Key: "_id", Value: ID}, primitive.E{Key: "status", Value: "booked"}}
+	update := bson.D{
+		primitive.E{Key: "$set", Value: bson.D{
+			primitive.E{Key: "status", Value: status},
+			primitive.E{Key: "date_updated", Value: time.Now()},
+		}},
+	}
+	result, err := sa.db.appointments.UpdateOneWithContext(ctx, filter, update, nil)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("there is no a booked appointment for the provided id")
+	}
+	return nil
+}
+
+//FindAppointment finds an appointment by id
+func (sa *Adapter) FindAppointment(ctx context.Context, ID string) (*model.Appointment, error) {
+	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
+	var result []*model.Appointment
+	err := sa.db.appointments.FindWithContext(ctx, filter, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		//not found
+		return nil, nil
+	}
+	return result[0], nil
+}
+
+//FindUserAppointments finds all the appointments of a user, the earliest first
+func (sa *Adapter) FindUserAppointments(ctx context.Context, userID string) ([]*model.Appointment, error) {
+	filter := bson.D{primitive.E{Key: "user_id", Value: userID}}
+	var result []*model.Appointment
+
+	options := options.Find()
+	options.SetSort(bson.D{primitive.E{Key: "start", Value: 1}})
+
+	err := sa.db.appointments.FindWithContext(ctx, filter, &result, options)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//FindLocationAppointments finds the booked appointments of a location which start in the period, the earliest first
+func (sa *Adapter) FindLocationAppointments(ctx context.Context, locationID string, from time.Time, to time.Time) ([]*model.Appointment, error) {
+	filter := bson.D{primitive.E{Key: "location_id", Value: locationID},
+		primitive.E{Key: "start", Value: bson.M{"$gte": from, "$lt": to}},
+		primitive.E{Key: "status", Value: "booked"}}
+	var result []*model.Appointment
+
+	options := options.Find()
+	options.SetSort(bson.D{primitive.E{Key: "start", Value: 1}})
+
+	err := sa.db.appointments.FindWithContext(ctx, filter, &result, options)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//FindAppointmentsToRemind finds the booked appointments which start in the period and the users have not been reminded for them
+func (sa *Adapter) FindAppointmentsToRemind(ctx context.Context, from time.Time, to time.Time) ([]*model.Appointment, error) {
+	filter := bson.D{primitive.E{Key: "status", Value: "booked"},
+		primitive.E{Key: "reminder_sent", Value: false},
+		primitive.E{Key: "start", Value: bson.M{"$gte": from, "$lt": to}}}
+	var result []*model.Appointment
+	err := sa.db.appointments.FindWithContext(ctx, filter, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//SetAppointmentReminderSent marks that the user has been reminded for the appointment. It gives false if it has been already marked,
+//so only one instance sends the reminder.
+func (sa *Adapter) SetAppointmentReminderSent(ctx context.Context, ID string) (bool, error) {
+	filter := bson.D{primitive.E{Key: "_id", Value: ID}, primitive.E{Key: "reminder_sent", Value: false}}
+	update := bson.D{
+		primitive.E{Key: "$set", Value: bson.D{
+			primitive.E{Key: "reminder_sent", Value: true},
+		}},
+	}
+	result, err := sa.db.appointments.UpdateOneWithContext(ctx, filter, update, nil)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+//DeleteLocation deletes a location
+func (sa *Adapter) DeleteLocation(ctx context.Context, ID string) error {
+	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
+	result, err := sa.db.locations.DeleteOneWithContext(ctx, filter, nil)
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		return errors.New("result is nil for location item with id " + ID)
+	}
+	deletedCount := result.DeletedCount
+	if deletedCount == 0 {
+		return errors.New("there is no a location for id " + ID)
+	}
+	if deletedCount > 1 {
+		return errors.New("deleted more than one records for id " + ID)
+	}
+
+	//success - count = 1
+	return nil
+}
+
+//FindSymptom finds a symptom
+func (sa *Adapter) FindSymptom(ctx context.Context, ID string) (*model.Symptom, error) {
+	//1. first find the symptom group
+	filter := bson.D{primitive.E{Key: "symptoms.id", Value: ID}}
+	var result []*symptomGroup
+	err := sa.db.symptomgroups.FindWithContext(ctx, filter, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil || len(result) == 0 {
+		//not found
 		return nil, nil
 	}
 	symptomGroup := result[0]
@@ -3217,11 +4242,11 @@ func (sa *Adapter) FindSymptom(ID string) (*model.Symptom, error) {
 }
 
 //CreateSymptom creates a symptom
-func (sa *Adapter) CreateSymptom(name string, group string) (*model.Symptom, error) {
+func (sa *Adapter) CreateSymptom(ctx context.Context, name string, group string) (*model.Symptom, error) {
 	//1. find the symptom group
 	findFilter := bson.D{primitive.E{Key: "name", Value: group}}
 	var result []*symptomGroup
-	err := sa.db.symptomgroups.Find(findFilter, &result, nil)
+	err := sa.db.symptomgroups.FindWithContext(ctx, findFilter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -3246,7 +4271,7 @@ func (sa *Adapter) CreateSymptom(name string, group string) (*model.Symptom, err
 
 	//4. save the county
 	saveFilter := bson.D{primitive.E{Key: "_id", Value: symptomGroup.ID}}
-	err = sa.db.symptomgroups.ReplaceOne(saveFilter, symptomGroup, nil)
+	err = sa.db.symptomgroups.ReplaceOneWithContext(ctx, saveFilter, symptomGroup, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -3257,9 +4282,9 @@ func (sa *Adapter) CreateSymptom(name string, group string) (*model.Symptom, err
 }
 
 //DeleteSymptom deletes a symptom
-func (sa *Adapter) DeleteSymptom(ID string) error {
+func (sa *Adapter) DeleteSymptom(ctx context.Context, ID string) error {
 	// transaction
-	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
+	err := sa.db.dbClient.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
 		err := sessionContext.StartTransaction()
 		if err != nil {
 			log.Printf("error starting a transaction - %s", err)
@@ -3316,11 +4341,11 @@ func (sa *Adapter) DeleteSymptom(ID string) error {
 }
 
 //SaveSymptom saves a symptom
-func (sa *Adapter) SaveSymptom(entity *model.Symptom) error {
+func (sa *Adapter) SaveSymptom(ctx context.Context, entity *model.Symptom) error {
 	//1. first find the symptom group
 	filter := bson.D{primitive.E{Key: "symptoms.id", Value: entity.ID}}
 	var result []*symptomGroup
-	err := sa.db.symptomgroups.Find(filter, &result, nil)
+	err := sa.db.symptomgroups.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return err
 	}
@@ -3350,7 +4375,7 @@ func (sa *Adapter) SaveSymptom(entity *model.Symptom) error {
 
 	//3. save the symptom group
 	saveFilter := bson.D{primitive.E{Key: "_id", Value: symptomGroup.ID}}
-	err = sa.db.symptomgroups.ReplaceOne(saveFilter, symptomGroup, nil)
+	err = sa.db.symptomgroups.ReplaceOneWithContext(ctx, saveFilter, symptomGroup, nil)
 	if err != nil {
 		return err
 	}
@@ -3359,12 +4384,12 @@ func (sa *Adapter) SaveSymptom(entity *model.Symptom) error {
 }
 
 //ReadAllSymptomGroups reads all symptom groups
-func (sa *Adapter) ReadAllSymptomGroups() ([]*model.SymptomGroup, error) {
+func (sa *Adapter) ReadAllSymptomGroups(ctx context.Context) ([]*model.SymptomGroup, error) {
 	options := options.Find()
 	options.SetSort(bson.D{primitive.E{Key: "name", Value: 1}}) //sort by "name" //gr1 and gr2
 
 	var result []*symptomGroup
-	err := sa.db.symptomgroups.Find(nil, &result, options)
+	err := sa.db.symptomgroups.FindWithContext(ctx, nil, &result, options)
 	if err != nil {
 		return nil, err
 	}
@@ -3388,18 +4413,29 @@ func (sa *Adapter) ReadAllSymptomGroups() ([]*model.SymptomGroup, error) {
 }
 
 //ReadSymptoms reads all the symptoms
-func (sa *Adapter) ReadSymptoms(appVersion string) (*model.Symptoms, error) {
+func (sa *Adapter) ReadSymptoms(ctx context.Context, appVersion string) (*model.Symptoms, error) {
 	filter := bson.D{primitive.E{Key: "app_version", Value: appVersion}}
 	var symptoms *model.Symptoms
-	err := sa.db.symptoms.FindOne(filter, &symptoms, nil)
+	err := sa.db.symptoms.FindOneWithContext(ctx, filter, &symptoms, nil)
 	if err != nil {
 		return nil, err
 	}
 	return symptoms, nil
 }
 
+//ReadAllSymptoms reads the symptoms for all app versions and ranges
+func (sa *Adapter) ReadAllSymptoms(ctx context.Context) ([]*model.Symptoms, error) {
+	filter := bson.D{}
+	var result []*model.Symptoms
+	err := sa.db.symptoms.FindWithContext(ctx, filter, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 //CreateOrUpdateSymptoms creates symptoms for the provided version or update them if already created
-func (sa *Adapter) CreateOrUpdateSymptoms(appVersion string, items string) (*bool, error) {
+func (sa *Adapter) CreateOrUpdateSymptoms(ctx context.Context, appVersion string, items string) (*bool, error) {
 	filter := bson.D{primitive.E{Key: "app_version", Value: appVersion}}
 	update := bson.D{
 		primitive.E{Key: "$set", Value: bson.D{
@@ -3412,7 +4448,7 @@ func (sa *Adapter) CreateOrUpdateSymptoms(appVersion string, items string) (*boo
 	upsert := true
 	opt.Upsert = &upsert
 
-	updateResult, err := sa.db.symptoms.UpdateOne(filter, update, opt)
+	updateResult, err := sa.db.symptoms.UpdateOneWithContext(ctx, filter, update, opt)
 	if err != nil {
 		return nil, err
 	}
@@ -3425,10 +4461,10 @@ func (sa *Adapter) CreateOrUpdateSymptoms(appVersion string, items string) (*boo
 }
 
 //ReadAllSymptomRules reads all the symptom rules
-func (sa *Adapter) ReadAllSymptomRules() ([]*model.SymptomRule, error) {
+func (sa *Adapter) ReadAllSymptomRules(ctx context.Context) ([]*model.SymptomRule, error) {
 	filter := bson.D{}
 	var result []*symptomRule
-	err := sa.db.symptomrules.Find(filter, &result, nil)
+	err := sa.db.symptomrules.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -3453,11 +4489,11 @@ func (sa *Adapter) ReadAllSymptomRules() ([]*model.SymptomRule, error) {
 }
 
 //CreateSymptomRule creates a symptom rule
-func (sa *Adapter) CreateSymptomRule(countyID string, gr1Count int, gr2Count int, items []model.SymptomRuleItem) (*model.SymptomRule, error) {
+func (sa *Adapter) CreateSymptomRule(ctx context.Context, countyID string, gr1Count int, gr2Count int, items []model.SymptomRuleItem) (*model.SymptomRule, error) {
 	var resultItem model.SymptomRule
 
 	// transaction
-	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
+	err := sa.db.dbClient.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
 		err := sessionContext.StartTransaction()
 		if err != nil {
 			log.Printf("error starting a transaction - %s", err)
@@ -3515,10 +4551,10 @@ func (sa *Adapter) CreateSymptomRule(countyID string, gr1Count int, gr2Count int
 }
 
 //FindSymptomRule finds a symptom rule
-func (sa *Adapter) FindSymptomRule(ID string) (*model.SymptomRule, error) {
+func (sa *Adapter) FindSymptomRule(ctx context.Context, ID string) (*model.SymptomRule, error) {
 	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
 	var result []*symptomRule
-	err := sa.db.symptomrules.Find(filter, &result, nil)
+	err := sa.db.symptomrules.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -3544,10 +4580,10 @@ func (sa *Adapter) FindSymptomRule(ID string) (*model.SymptomRule, error) {
 }
 
 //FindSymptomRuleByCountyID finds a symptom rule by county id
-func (sa *Adapter) FindSymptomRuleByCountyID(countyID string) (*model.SymptomRule, error) {
+func (sa *Adapter) FindSymptomRuleByCountyID(ctx context.Context, countyID string) (*model.SymptomRule, error) {
 	filter := bson.D{primitive.E{Key: "county_id", Value: countyID}}
 	var result []*symptomRule
-	err := sa.db.symptomrules.Find(filter, &result, nil)
+	err := sa.db.symptomrules.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -3573,9 +4609,9 @@ func (sa *Adapter) FindSymptomRuleByCountyID(countyID string) (*model.SymptomRul
 }
 
 //SaveSymptomRule saves a symptom rule
-func (sa *Adapter) SaveSymptomRule(entity *model.SymptomRule) error {
+func (sa *Adapter) SaveSymptomRule(ctx context.Context, entity *model.SymptomRule) error {
 	// transaction
-	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
+	err := sa.db.dbClient.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
 		err := sessionContext.StartTransaction()
 		if err != nil {
 			log.Printf("error starting a transaction - %s", err)
@@ -3643,9 +4679,9 @@ func (sa *Adapter) SaveSymptomRule(entity *model.SymptomRule) error {
 }
 
 //DeleteSymptomRule deletes a symptom rule
-func (sa *Adapter) DeleteSymptomRule(ID string) error {
+func (sa *Adapter) DeleteSymptomRule(ctx context.Context, ID string) error {
 	// transaction
-	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
+	err := sa.db.dbClient.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
 		err := sessionContext.StartTransaction()
 		if err != nil {
 			log.Printf("error starting a transaction - %s", err)
@@ -3696,19 +4732,30 @@ func (sa *Adapter) DeleteSymptomRule(ID string) error {
 }
 
 //FindCRulesByCountyID finds the rules for county
-func (sa *Adapter) FindCRulesByCountyID(appVersion string, countyID string) (*model.CRules, error) {
+func (sa *Adapter) FindCRulesByCountyID(ctx context.Context, appVersion string, countyID string) (*model.CRules, error) {
 	filter := bson.D{primitive.E{Key: "app_version", Value: appVersion},
 		primitive.E{Key: "county_id", Value: countyID}}
 	var symptomsRules *model.CRules
-	err := sa.db.crules.FindOne(filter, &symptomsRules, nil)
+	err := sa.db.crules.FindOneWithContext(ctx, filter, &symptomsRules, nil)
 	if err != nil {
 		return nil, err
 	}
 	return symptomsRules, nil
 }
 
+//FindAllCRulesByCountyID finds the crules for all app versions for a county
+func (sa *Adapter) FindAllCRulesByCountyID(ctx context.Context, countyID string) ([]*model.CRules, error) {
+	filter := bson.D{primitive.E{Key: "county_id", Value: countyID}}
+	var result []*model.CRules
+	err := sa.db.crules.FindWithContext(ctx, filter, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 //CreateOrUpdateCRules creates crule or update it if already created
-func (sa *Adapter) CreateOrUpdateCRules(appVersion string, countyID string, data string) (*bool, error) {
+func (sa *Adapter) CreateOrUpdateCRules(ctx context.Context, appVersion string, countyID string, data string) (*bool, error) {
 	filter := bson.D{primitive.E{Key: "app_version", Value: appVersion}, primitive.E{Key: "county_id", Value: countyID}}
 	update := bson.D{
 		primitive.E{Key: "$set", Value: bson.D{
@@ -3721,7 +4768,7 @@ func (sa *Adapter) CreateOrUpdateCRules(appVersion string, countyID string, data
 	upsert := true
 	opt.Upsert = &upsert
 
-	updateResult, err := sa.db.crules.UpdateOne(filter, update, opt)
+	updateResult, err := sa.db.crules.UpdateOneWithContext(ctx, filter, update, opt)
 	if err != nil {
 		return nil, err
 	}
@@ -3733,8 +4780,162 @@ func (sa *Adapter) CreateOrUpdateCRules(appVersion string, countyID string, data
 	return &create, nil
 }
 
+//FindConfigRevisions finds the revisions for the crules of a county or for the symptoms, the latest are first
+func (sa *Adapter) FindConfigRevisions(ctx context.Context, configType string, appVersion string, countyID string) ([]*model.ConfigRevision, error) {
+	filter := bson.D{primitive.E{Key: "type", Value: configType}, primitive.E{Key: "app_version", Value: appVersion},
+		primitive.E{Key: "county_id", Value: countyID}}
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{primitive.E{Key: "number", Value: -1}})
+	result := []*model.ConfigRevision{}
+	err := sa.db.configrevisions.FindWithContext(ctx, filter, &result, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//SaveConfigRevision creates the revision or replaces it if already created
+func (sa *Adapter) SaveConfigRevision(ctx context.Context, revision *model.ConfigRevision) error {
+	filter := bson.D{primitive.E{Key: "_id", Value: revision.ID}}
+	opts := options.Replace().SetUpsert(true)
+	err := sa.db.configrevisions.ReplaceOneWithContext(ctx, filter, revision, opts)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//PublishConfigRevision archives the published revision, saves the provided one and sets its data to the crules or the symptoms.
+//It gives true if the crules or the symptoms are created.
+func (sa *Adapter) PublishConfigRevision(ctx context.Context, revision *model.ConfigRevision) (*bool, error) {
+	var create bool
+	// transaction
+	err := sa.db.dbClient.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
+		err := sessionContext.StartTransaction()
+		if err != nil {
+			log.Printf("error starting a transaction - %s", err)
+			return err
+		}
+
+		create, err = sa.publishConfigRevision(sessionContext, revision)
+		if err != nil {
+			abortTransaction(sessionContext)
+			return err
+		}
+
+		err = sessionContext.CommitTransaction(sessionContext)
+		if err != nil {
+			log.Printf("error on commiting a transaction - %s", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &create, nil
+}
+
+//publishConfigRevision publishes the revision in the session transaction, it gives true if the crules or the symptoms are created
+func (sa *Adapter) publishConfigRevision(sessionContext mongo.SessionContext, revision *model.ConfigRevision) (bool, error) {
+	var coll *collectionWrapper
+	var filter bson.D
+	var update bson.D
+	switch revision.Type {
+	case "crules":
+		coll = sa.db.crules
+		filter = bson.D{primitive.E{Key: "app_version", Value: revision.AppVersion}, primitive.E{Key: "county_id", Value: revision.CountyID}}
+		update = bson.D{primitive.E{Key: "$set", Value: bson.D{primitive.E{Key: "data", Value: revision.Data}}}}
+	case "symptoms":
+		coll = sa.db.symptoms
+		filter = bson.D{primitive.E{Key: "app_version", Value: revision.AppVersion}}
+		update = bson.D{primitive.E{Key: "$set", Value: bson.D{primitive.E{Key: "items", Value: revision.Data}}}}
+	default:
+		return false, errors.New("not supported revision type " + revision.Type)
+	}
+
+	//1. archive the published revision
+	archiveFilter := bson.D{primitive.E{Key: "type", Value: revision.Type}, primitive.E{Key: "app_version", Value: revision.AppVersion},
+		primitive.E{Key: "county_id", Value: revision.CountyID}, primitive.E{Key: "status", Value: "published"}}
+	archiveUpdate := bson.D{primitive.E{Key: "$set", Value: bson.D{primitive.E{Key: "status", Value: "archived"}}}}
+	_, err := sa.db.configrevisions.UpdateManyWithContext(sessionContext, archiveFilter, archiveUpdate, nil)
+	if err != nil {
+		return false, err
+	}
+
+	//2. save the revision
+	revisionFilter := bson.D{primitive.E{Key: "_id", Value: revision.ID}}
+	err = sa.db.configrevisions.ReplaceOneWithContext(sessionContext, revisionFilter, revision, options.Replace().SetUpsert(true))
+	if err != nil {
+		return false, err
+	}
+
+	//3. set the data, insert if not exists
+	updateResult, err := coll.UpdateOneWithContext(sessionContext, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return false, err
+	}
+	return updateResult.MatchedCount == 0, nil
+}
+
+//FindConfigSchemas finds the config schemas for an app version or all of them if the app version is not provided
+func (sa *Adapter) FindConfigSchemas(ctx context.Context, appVersion *string) ([]*model.ConfigSchema, error) {
+	filter := bson.D{}
+	if appVersion != nil {
+		filter = append(filter, primitive.E{Key: "app_version", Value: *appVersion})
+	}
+	result := []*model.ConfigSchema{}
+	err := sa.db.configschemas.FindWithContext(ctx, filter, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//FindConfigSchema finds the config schema for a type and an app version
+func (sa *Adapter) FindConfigSchema(ctx context.Context, configType string, appVersion string) (*model.ConfigSchema, error) {
+	filter := bson.D{primitive.E{Key: "type", Value: configType}, primitive.E{Key: "app_version", Value: appVersion}}
+	var result []*model.ConfigSchema
+	err := sa.db.configschemas.FindWithContext(ctx, filter, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		//not found
+		return nil, nil
+	}
+	return result[0], nil
+}
+
+//SaveConfigSchema creates the config schema or replaces it if already created
+func (sa *Adapter) SaveConfigSchema(ctx context.Context, schema *model.ConfigSchema) error {
+	filter := bson.D{primitive.E{Key: "_id", Value: schema.ID}}
+	opts := options.Replace().SetUpsert(true)
+	err := sa.db.configschemas.ReplaceOneWithContext(ctx, filter, schema, opts)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//DeleteConfigSchema deletes a config schema
+func (sa *Adapter) DeleteConfigSchema(ctx context.Context, ID string) error {
+	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
+	result, err := sa.db.configschemas.DeleteOneWithContext(ctx, filter, nil)
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		return errors.New("result is nil for config schema item with id " + ID)
+	}
+	if result.DeletedCount != 1 {
+		return errors.New("error occured while deleting a config schema with id " + ID)
+	}
+	return nil
+}
+
 //CreateTraceReports creates trace reports items
-func (sa *Adapter) CreateTraceReports(items []model.TraceExposure) (int, error) {
+func (sa *Adapter) CreateTraceReports(ctx context.Context, items []model.TraceExposure) (int, error) {
 
 	//we need create []Interface{}!
 	data := make([]interface{}, len(items))
@@ -3743,7 +4944,7 @@ func (sa *Adapter) CreateTraceReports(items []model.TraceExposure) (int, error)
 	}
 
 	//insert the items
-	result, err := sa.db.traceexposures.InsertMany(data, nil)
+	result, err := sa.db.traceexposures.InsertManyWithContext(ctx, data, nil)
 	if err != nil {
 		return 0, err
 	}
@@ -3757,7 +4958,7 @@ func (sa *Adapter) CreateTraceReports(items []model.TraceExposure) (int, error)
 }
 
 //ReadTraceExposures reads the exposures
-func (sa *Adapter) ReadTraceExposures(timestamp *int64, dateAdded *int64) ([]model.TraceExposure, error) {
+func (sa *Adapter) ReadTraceExposures(ctx context.Context, timestamp *int64, dateAdded *int64) ([]model.TraceExposure, error) {
 	filter := bson.M{}
 
 	if timestamp != nil {
@@ -3771,7 +4972,7 @@ func (sa *Adapter) ReadTraceExposures(timestamp *int64, dateAdded *int64) ([]mod
 	options.SetSort(bson.D{primitive.E{Key: "timestamp", Value: 1}}) //sort by "timestamp"
 
 	var result []model.TraceExposure
-	err := sa.db.traceexposures.Find(filter, &result, options)
+	err := sa.db.traceexposures.FindWithContext(ctx, filter, &result, options)
 	if err != nil {
 		return nil, err
 	}
@@ -3798,7 +4999,7 @@ type manualTestUserJoin struct {
 }
 
 //FindManualTestsByCountyIDDeep find the manual test for a county
-func (sa *Adapter) FindManualTestsByCountyIDDeep(countyID string, status *string) ([]*model.EManualTest, error) {
+func (sa *Adapter) FindManualTestsByCountyIDDeep(ctx context.Context, countyID string, status *string) ([]*model.EManualTest, error) {
 	// construct the query
 	pipeline := []bson.M{}
 	pipeline = append(pipeline, bson.M{"$lookup": bson.M{
@@ -3812,7 +5013,7 @@ func (sa *Adapter) FindManualTestsByCountyIDDeep(countyID string, status *string
 
 		locsFilter := bson.D{primitive.E{Key: "county_id", Value: countyID}}
 		var locsResult []*location
-		err := sa.db.locations.Find(locsFilter, &locsResult, nil)
+		err := sa.db.locations.FindWithContext(ctx, locsFilter, &locsResult, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -3839,7 +5040,7 @@ func (sa *Adapter) FindManualTestsByCountyIDDeep(countyID string, status *string
 		bson.M{"$sort": bson.D{primitive.E{Key: "date_created", Value: -1}}})
 
 	var result []*manualTestUserJoin
-	err := sa.db.emanualtests.Aggregate(pipeline, &result, nil)
+	err := sa.db.emanualtests.AggregateWithContext(ctx, pipeline, &result, nil)
 	if err != nil {
 		return nil, err
 
@@ -3864,11 +5065,33 @@ func (sa *Adapter) FindManualTestsByCountyIDDeep(countyID string, status *string
 	return resultList, nil
 }
 
+//FindManualTestsByUserID finds the manual tests of the user with their images
+func (sa *Adapter) FindManualTestsByUserID(ctx context.Context, userID string) ([]*model.EManualTest, error) {
+	filter := bson.D{primitive.E{Key: "user_id", Value: userID}}
+	options := options.Find()
+	options.SetSort(bson.D{primitive.E{Key: "date_created", Value: -1}})
+	var result []*eManualTest
+	err := sa.db.emanualtests.FindWithContext(ctx, filter, &result, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var resultList []*model.EManualTest
+	for _, item := range result {
+		mt := model.EManualTest{ID: item.ID, HistoryID: item.EHistoryID, LocationID: item.LocationID, CountyID: item.CountyID,
+			EncryptedKey: item.EncryptedKey, EncryptedBlob: item.EncryptedBlob,
+			EncryptedImageKey: item.EncryptedImageKey, EncryptedImageBlob: item.EncryptedImageBlob,
+			Status: item.Status, Date: item.DateCreated, User: model.User{ID: item.UserID}}
+		resultList = append(resultList, &mt)
+	}
+	return resultList, nil
+}
+
 //FindManualTestImage finds the manual test image
-func (sa *Adapter) FindManualTestImage(ID string) (*string, *string, error) {
+func (sa *Adapter) FindManualTestImage(ctx context.Context, ID string) (*string, *string, error) {
 	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
 	var result []*eManualTest
-	err := sa.db.emanualtests.Find(filter, &result, nil)
+	err := sa.db.emanualtests.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -3881,9 +5104,9 @@ func (sa *Adapter) FindManualTestImage(ID string) (*string, *string, error) {
 }
 
 //ProcessManualTest processes manual test
-func (sa *Adapter) ProcessManualTest(ID string, status string, encryptedKey *string, encryptedBlob *string) error {
+func (sa *Adapter) ProcessManualTest(ctx context.Context, ID string, status string, encryptedKey *string, encryptedBlob *string) error {
 	// transaction
-	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
+	err := sa.db.dbClient.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
 		err := sessionContext.StartTransaction()
 		if err != nil {
 			fmt.Println(err)
@@ -3941,7 +5164,17 @@ func (sa *Adapter) ProcessManualTest(ID string, status string, encryptedKey *str
 				return errors.New("there is no a history for the provided manual test")
 			}
 
-			//3.2 update the history
+			//3.2 update the history, it is encrypted for the current user key
+			userFilter := bson.D{primitive.E{Key: "_id", Value: history.UserID}}
+			var users []*model.User
+			err = sa.db.users.FindWithContext(sessionContext, userFilter, &users, nil)
+			if err != nil {
+				abortTransaction(sessionContext)
+				return err
+			}
+			if len(users) > 0 {
+				history.KeyVersion = users[0].KeyVersion
+			}
 			history.Type = "verified_manual_test"
 			history.EncryptedKey = *encryptedKey
 			history.EncryptedBlob = *encryptedBlob
@@ -3977,10 +5210,10 @@ func (sa *Adapter) ProcessManualTest(ID string, status string, encryptedKey *str
 }
 
 //ReadAllAccessRules reads all access rules
-func (sa *Adapter) ReadAllAccessRules() ([]*model.AccessRule, error) {
+func (sa *Adapter) ReadAllAccessRules(ctx context.Context) ([]*model.AccessRule, error) {
 	filter := bson.D{}
 	var result []*accessRule
-	err := sa.db.accessrules.Find(filter, &result, nil)
+	err := sa.db.accessrules.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -4003,11 +5236,11 @@ func (sa *Adapter) ReadAllAccessRules() ([]*model.AccessRule, error) {
 }
 
 //CreateAccessRule creates an access rule
-func (sa *Adapter) CreateAccessRule(countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error) {
+func (sa *Adapter) CreateAccessRule(ctx context.Context, countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error) {
 	var resultItem model.AccessRule
 
 	// transaction
-	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
+	err := sa.db.dbClient.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
 		err := sessionContext.StartTransaction()
 		if err != nil {
 			log.Printf("error starting a transaction - %s", err)
@@ -4062,11 +5295,11 @@ func (sa *Adapter) CreateAccessRule(countyID string, rules []model.AccessRuleCou
 }
 
 //UpdateAccessRule updates an access rule
-func (sa *Adapter) UpdateAccessRule(ID string, countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error) {
+func (sa *Adapter) UpdateAccessRule(ctx context.Context, ID string, countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error) {
 	var resultItem model.AccessRule
 
 	// transaction
-	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
+	err := sa.db.dbClient.UseSession(ctx, func(sessionContext mongo.SessionContext) error {
 		err := sessionContext.StartTransaction()
 		if err != nil {
 			log.Printf("error starting a transaction - %s", err)
@@ -4163,10 +5396,10 @@ func (sa *Adapter) validateAccessRuleData(sessionContext mongo.SessionContext, c
 }
 
 //FindAccessRuleByCountyID finds the access rule for a specific county
-func (sa *Adapter) FindAccessRuleByCountyID(countyID string) (*model.AccessRule, error) {
+func (sa *Adapter) FindAccessRuleByCountyID(ctx context.Context, countyID string) (*model.AccessRule, error) {
 	filter := bson.D{primitive.E{Key: "county_id", Value: countyID}}
 	var result []*accessRule
-	err := sa.db.accessrules.Find(filter, &result, nil)
+	err := sa.db.accessrules.FindWithContext(ctx, filter, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -4189,9 +5422,9 @@ func (sa *Adapter) FindAccessRuleByCountyID(countyID string) (*model.AccessRule,
 }
 
 //DeleteAccessRule deletes an access rule
-func (sa *Adapter) DeleteAccessRule(ID string) error {
+func (sa *Adapter) DeleteAccessRule(ctx context.Context, ID string) error {
 	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
-	result, err := sa.db.accessrules.DeleteOne(filter, nil)
+	result, err := sa.db.accessrules.DeleteOneWithContext(ctx, filter, nil)
 	if err != nil {
 		log.Printf("error deleting an access rule - %s", err)
 		return err
@@ -4218,7 +5451,7 @@ type ctuJoin struct {
 }
 
 //FindExternalUserIDsByTestsOrderNumbers finds the external users ids for the tests orders numbers
-func (sa *Adapter) FindExternalUserIDsByTestsOrderNumbers(orderNumbers []string) (map[string]*string, error) {
+func (sa *Adapter) FindExternalUserIDsByTestsOrderNumbers(ctx context.Context, orderNumbers []string) (map[string]*string, error) {
 	pipeline := []bson.M{
 		{"$lookup": bson.M{
 			"from":         "users",
@@ -4234,7 +5467,7 @@ func (sa *Adapter) FindExternalUserIDsByTestsOrderNumbers(orderNumbers []string)
 		}}}
 
 	var result []*ctuJoin
-	err := sa.db.ctests.Aggregate(pipeline, &result, nil)
+	err := sa.db.ctests.AggregateWithContext(ctx, pipeline, &result, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -4250,7 +5483,7 @@ func (sa *Adapter) FindExternalUserIDsByTestsOrderNumbers(orderNumbers []string)
 }
 
 //CreateOrUpdateUINOverride creates a new uin override entity or updates it if already created
-func (sa *Adapter) CreateOrUpdateUINOverride(uin string, interval int, category *string, expiration *time.Time) error {
+func (sa *Adapter) CreateOrUpdateUINOverride(ctx context.Context, uin string, interval int, category *string, expiration *time.Time) error {
 	filter := bson.D{primitive.E{Key: "uin", Value: uin}}
 	update := bson.D{
 		primitive.E{Key: "$set", Value: bson.D{
@@ -4265,7 +5498,7 @@ func (sa *Adapter) CreateOrUpdateUINOverride(uin string, interval int, category
 	upsert := true
 	opt.Upsert = &upsert
 
-	_, err := sa.db.uinoverrides.UpdateOne(filter, update, opt)
+	_, err := sa.db.uinoverrides.UpdateOneWithContext(ctx, filter, update, opt)
 	if err != nil {
 		return err
 	}
@@ -4274,7 +5507,7 @@ func (sa *Adapter) CreateOrUpdateUINOverride(uin string, interval int, category
 }
 
 //FindUINOverride finds the uin override for the provided uin. It makes additional check for the expiration because of the mongoDB TTL delay
-func (sa *Adapter) FindUINOverride(uin string) (*model.UINOverride, error) {
+func (sa *Adapter) FindUINOverride(ctx context.Context, uin string) (*model.UINOverride, error) {
 	now := time.Now()
 	filter := bson.D{
 		primitive.E{Key: "uin", Value: uin},
@@ -4285,7 +5518,7 @@ func (sa *Adapter) FindUINOverride(uin string) (*model.UINOverride, error) {
 	}
 
 	var uinOverrides []*model.UINOverride
-	err := sa.db.uinoverrides.Find(filter, &uinOverrides, nil)
+	err := sa.db.uinoverrides.FindWithContext(ctx, filter, &uinOverrides, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -4298,7 +5531,7 @@ func (sa *Adapter) FindUINOverride(uin string) (*model.UINOverride, error) {
 }
 
 //FindUINOverrides finds the uin override for the provided uin. If uin is nil then it gives all
-func (sa *Adapter) FindUINOverrides(uin *string, sort *string) ([]*model.UINOverride, error) {
+func (sa *Adapter) FindUINOverrides(ctx context.Context, uin *string, sort *string) ([]*model.UINOverride, error) {
 	//filter by uin if provided
 	filter := bson.D{}
 	if uin != nil {
@@ -4313,7 +5546,7 @@ func (sa *Adapter) FindUINOverrides(uin *string, sort *string) ([]*model.UINOver
 	}
 
 	var result []*model.UINOverride
-	err := sa.db.uinoverrides.Find(filter, &result, opt)
+	err := sa.db.uinoverrides.FindWithContext(ctx, filter, &result, opt)
 	if err != nil {
 		return nil, err
 	}
@@ -4321,9 +5554,9 @@ func (sa *Adapter) FindUINOverrides(uin *string, sort *string) ([]*model.UINOver
 }
 
 //CreateUINOverride creates a new uin override entity
-func (sa *Adapter) CreateUINOverride(uin string, interval int, category *string, expiration *time.Time) (*model.UINOverride, error) {
+func (sa *Adapter) CreateUINOverride(ctx context.Context, uin string, interval int, category *string, expiration *time.Time) (*model.UINOverride, error) {
 	uinOverride := model.UINOverride{UIN: uin, Interval: interval, Category: category, Expiration: expiration}
-	_, err := sa.db.uinoverrides.InsertOne(&uinOverride)
+	_, err := sa.db.uinoverrides.InsertOneWithContext(ctx, &uinOverride)
 	if err != nil {
 		return nil, err
 	}
@@ -4332,7 +5565,7 @@ func (sa *Adapter) CreateUINOverride(uin string, interval int, category *string,
 }
 
 //UpdateUINOverride updates uin override entity
-func (sa *Adapter) UpdateUINOverride(uin string, interval int, category *string, expiration *time.Time) (*string, error) {
+func (sa *Adapter) UpdateUINOverride(ctx context.Context, uin string, interval int, category *string, expiration *time.Time) (*string, error) {
 	filter := bson.D{primitive.E{Key: "uin", Value: uin}}
 	update := bson.D{
 		primitive.E{Key: "$set", Value: bson.D{
@@ -4342,7 +5575,7 @@ func (sa *Adapter) UpdateUINOverride(uin string, interval int, category *string,
 		}},
 	}
 
-	result, err := sa.db.uinoverrides.UpdateOne(filter, update, nil)
+	result, err := sa.db.uinoverrides.UpdateOneWithContext(ctx, filter, update, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -4352,9 +5585,9 @@ func (sa *Adapter) UpdateUINOverride(uin string, interval int, category *string,
 }
 
 //DeleteUINOverride deletes uin override entity
-func (sa *Adapter) DeleteUINOverride(uin string) error {
+func (sa *Adapter) DeleteUINOverride(ctx context.Context, uin string) error {
 	filter := bson.D{primitive.E{Key: "uin", Value: uin}}
-	result, err := sa.db.uinoverrides.DeleteOne(filter, nil)
+	result, err := sa.db.uinoverrides.DeleteOneWithContext(ctx, filter, nil)
 	if err != nil {
 		return err
 	}
@@ -4374,18 +5607,29 @@ func (sa *Adapter) DeleteUINOverride(uin string) error {
 }
 
 //FindUINBuildingAccess finds the UIN buinding access for the provided uin
-func (sa *Adapter) FindUINBuildingAccess(uin string) (*model.UINBuildingAccess, error) {
+func (sa *Adapter) FindUINBuildingAccess(ctx context.Context, uin string) (*model.UINBuildingAccess, error) {
 	filter := bson.D{primitive.E{Key: "uin", Value: uin}}
 	var uinBuildingAccess *model.UINBuildingAccess
-	err := sa.db.uinbuildingaccess.FindOne(filter, &uinBuildingAccess, nil)
+	err := sa.db.uinbuildingaccess.FindOneWithContext(ctx, filter, &uinBuildingAccess, nil)
 	if err != nil {
 		return nil, err
 	}
 	return uinBuildingAccess, nil
 }
 
+//FindUINBuildingAccessByUINs finds the building access for the provided uins
+func (sa *Adapter) FindUINBuildingAccessByUINs(ctx context.Context, uins []string) ([]*model.UINBuildingAccess, error) {
+	filter := bson.D{primitive.E{Key: "uin", Value: bson.M{"$in": uins}}}
+	var result []*model.UINBuildingAccess
+	err := sa.db.uinbuildingaccess.FindWithContext(ctx, filter, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 //CreateOrUpdateUINBuildingAccess creates UIN building access or update it if already created
-func (sa *Adapter) CreateOrUpdateUINBuildingAccess(uin string, date time.Time, access string) error {
+func (sa *Adapter) CreateOrUpdateUINBuildingAccess(ctx context.Context, uin string, date time.Time, access string) error {
 	filter := bson.D{primitive.E{Key: "uin", Value: uin}}
 	update := bson.D{
 		primitive.E{Key: "$set", Value: bson.D{
@@ -4399,14 +5643,177 @@ func (sa *Adapter) CreateOrUpdateUINBuildingAccess(uin string, date time.Time, a
 	upsert := true
 	opt.Upsert = &upsert
 
-	_, err := sa.db.uinbuildingaccess.UpdateOne(filter, update, opt)
+	_, err := sa.db.uinbuildingaccess.UpdateOneWithContext(ctx, filter, update, opt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+//ReadRetentionPolicies reads the retention policies
+func (sa *Adapter) ReadRetentionPolicies(ctx context.Context) ([]*model.RetentionPolicy, error) {
+	filter := bson.D{}
+	var result []*model.RetentionPolicy
+	err := sa.db.retentionpolicies.FindWithContext(ctx, filter, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//SaveRetentionPolicy creates the retention policy for the entity or replaces it if already created
+func (sa *Adapter) SaveRetentionPolicy(ctx context.Context, policy *model.RetentionPolicy) error {
+	filter := bson.D{primitive.E{Key: "_id", Value: policy.Entity}}
+	opts := options.Replace().SetUpsert(true)
+	err := sa.db.retentionpolicies.ReplaceOneWithContext(ctx, filter, policy, opts)
 	if err != nil {
 		return err
 	}
+	return nil
+}
 
+//SaveErasureReceipt creates the erasure receipt or replaces it if already created
+func (sa *Adapter) SaveErasureReceipt(ctx context.Context, receipt *model.ErasureReceipt) error {
+	filter := bson.D{primitive.E{Key: "_id", Value: receipt.ID}}
+	opts := options.Replace().SetUpsert(true)
+	err := sa.db.erasurereceipts.ReplaceOneWithContext(ctx, filter, receipt, opts)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
+//FindErasureReceipt finds an erasure receipt
+func (sa *Adapter) FindErasureReceipt(ctx context.Context, ID string) (*model.ErasureReceipt, error) {
+	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
+	var result []*model.ErasureReceipt
+	err := sa.db.erasurereceipts.FindWithContext(ctx, filter, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		//not found
+		return nil, nil
+	}
+	return result[0], nil
+}
+
+//FindErasureReceiptsBySubjectHash finds the erasure receipts for a subject, the latest are first
+func (sa *Adapter) FindErasureReceiptsBySubjectHash(ctx context.Context, subjectHash string) ([]*model.ErasureReceipt, error) {
+	filter := bson.D{primitive.E{Key: "subject_hash", Value: subjectHash}}
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{primitive.E{Key: "requested_at", Value: -1}})
+	result := []*model.ErasureReceipt{}
+	err := sa.db.erasurereceipts.FindWithContext(ctx, filter, &result, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//DeleteEHistoriesOlderThan deletes the history items with date before the provided one
+func (sa *Adapter) DeleteEHistoriesOlderThan(ctx context.Context, date time.Time) (int64, error) {
+	filter := bson.D{primitive.E{Key: "date", Value: bson.M{"$lt": date}}}
+	return sa.purgeInBatches(ctx, sa.db.ehistory, filter, nil)
+}
+
+//DeleteProcessedCTestsOlderThan deletes the processed ctests created before the provided date
+func (sa *Adapter) DeleteProcessedCTestsOlderThan(ctx context.Context, date time.Time) (int64, error) {
+	filter := bson.D{primitive.E{Key: "processed", Value: true},
+		primitive.E{Key: "date_created", Value: bson.M{"$lt": date}}}
+	return sa.purgeInBatches(ctx, sa.db.ctests, filter, nil)
+}
+
+//ClearManualTestsImagesOlderThan removes the images of the manual tests created before the provided date. The manual tests are kept.
+func (sa *Adapter) ClearManualTestsImagesOlderThan(ctx context.Context, date time.Time) (int64, error) {
+	filter := bson.D{primitive.E{Key: "date_created", Value: bson.M{"$lt": date}},
+		primitive.E{Key: "encrypted_image_blob", Value: bson.M{"$ne": ""}}}
+	update := bson.D{
+		primitive.E{Key: "$set", Value: bson.D{
+			primitive.E{Key: "encrypted_image_key", Value: ""},
+			primitive.E{Key: "encrypted_image_blob", Value: ""},
+		}},
+	}
+	return sa.purgeInBatches(ctx, sa.db.emanualtests, filter, update)
+}
+
+//DeleteTraceExposuresExpiredBefore deletes the trace exposures with expirestamp before the provided one
+func (sa *Adapter) DeleteTraceExposuresExpiredBefore(ctx context.Context, expirestamp int64) (int64, error) {
+	filter := bson.D{primitive.E{Key: "expirestamp", Value: bson.M{"$lt": expirestamp}}}
+	return sa.purgeInBatches(ctx, sa.db.traceexposures, filter, nil)
+}
+
+//DeleteUINOverridesExpiredBefore deletes the uin overrides with expiration before the provided date
+func (sa *Adapter) DeleteUINOverridesExpiredBefore(ctx context.Context, date time.Time) (int64, error) {
+	filter := bson.D{primitive.E{Key: "expiration", Value: bson.M{"$lt": date}}}
+	return sa.purgeInBatches(ctx, sa.db.uinoverrides, filter, nil)
+}
+
+//DeleteWaitTimeReportsOlderThan deletes the wait time reports created before the provided date. The latest report of a location is kept in the location.
+func (sa *Adapter) DeleteWaitTimeReportsOlderThan(ctx context.Context, date time.Time) (int64, error) {
+	filter := bson.D{primitive.E{Key: "date_created", Value: bson.M{"$lt": date}}}
+	return sa.purgeInBatches(ctx, sa.db.waittimereports, filter, nil)
+}
+
+//DeleteAppointmentsOlderThan deletes the appointments which start before the provided date together with their slots
+func (sa *Adapter) DeleteAppointmentsOlderThan(ctx context.Context, date time.Time) (int64, error) {
+	filter := bson.D{primitive.E{Key: "start", Value: bson.M{"$lt": date}}}
+	count, err := sa.purgeInBatches(ctx, sa.db.appointments, filter, nil)
+	if err != nil {
+		return count, err
+	}
+	_, err = sa.purgeInBatches(ctx, sa.db.appointmentslots, filter, nil)
+	return count, err
+}
+
+const purgeBatchSize = 500
+
+//purgeInBatches deletes the matching documents or updates them if update is provided. It works on small batches
+//as one big operation could exceed the mongoDB timeout. The update must make the documents not matching the filter.
+func (sa *Adapter) purgeInBatches(ctx context.Context, coll *collectionWrapper, filter bson.D, update interface{}) (int64, error) {
+	var count int64
+	for {
+		//find the next batch
+		findOptions := options.Find()
+		findOptions.SetLimit(purgeBatchSize)
+		findOptions.SetProjection(bson.D{primitive.E{Key: "_id", Value: 1}})
+		var result []bson.M
+		err := coll.FindWithContext(ctx, filter, &result, findOptions)
+		if err != nil {
+			return count, err
+		}
+		if len(result) == 0 {
+			return count, nil
+		}
+
+		ids := make([]interface{}, len(result))
+		for i, item := range result {
+			ids[i] = item["_id"]
+		}
+		idsFilter := bson.D{primitive.E{Key: "_id", Value: bson.M{"$in": ids}}}
+
+		//process it
+		if update == nil {
+			deleteResult, err := coll.DeleteManyWithContext(ctx, idsFilter, nil)
+			if err != nil {
+				return count, err
+			}
+			count += deleteResult.DeletedCount
+		} else {
+			updateResult, err := coll.UpdateManyWithContext(ctx, idsFilter, update, nil)
+			if err != nil {
+				return count, err
+			}
+			count += updateResult.ModifiedCount
+		}
+
+		if len(result) < purgeBatchSize {
+			return count, nil
+		}
+	}
+}
+
 func (sa *Adapter) containsCountyStatus(ID string, list []countyStatus) bool {
 	if list == nil {
 		return false
@@ -4430,7 +5837,7 @@ func (sa *Adapter) deleteAllStatuses(sessionContext mongo.SessionContext) error
 }
 
 //NewStorageAdapter creates a new storage adapter instance
-func NewStorageAdapter(mongoDBAuth string, mongoDBName string, mongoTimeout string) *Adapter {
+func NewStorageAdapter(mongoDBAuth string, mongoDBName string, mongoTimeout string, mongoPollInterval string) *Adapter {
 	timeout, err := strconv.Atoi(mongoTimeout)
 	if err != nil {
 		log.Println("Set default timeout - 500")
@@ -4438,7 +5845,15 @@ func NewStorageAdapter(mongoDBAuth string, mongoDBName string, mongoTimeout stri
 	}
 	timeoutMS := time.Millisecond * time.Duration(timeout)
 
-	db := &database{mongoDBAuth: mongoDBAuth, mongoDBName: mongoDBName, mongoTimeout: timeoutMS}
+	//used only when the change streams are not available
+	pollInterval, err := strconv.Atoi(mongoPollInterval)
+	if err != nil || pollInterval <= 0 {
+		log.Println("Set default poll interval - 60")
+		pollInterval = 60
+	}
+	pollIntervalSec := time.Second * time.Duration(pollInterval)
+
+	db := &database{mongoDBAuth: mongoDBAuth, mongoDBName: mongoDBName, mongoTimeout: timeoutMS, pollInterval: pollIntervalSec}
 	return &Adapter{db: db}
 }
 
@@ -4453,11 +5868,27 @@ func constructFilter(f *utils.Filter) interface{} {
 	return filter
 }
 
+//newGeoPoint gives the point for the coordinates, it is nil for invalid coordinates as the 2dsphere index does not accept them.
+//It is nil for (0,0) too as these are the coordinates of the locations for which they are not set.
+func newGeoPoint(latitude float64, longitude float64) *geoPoint {
+	if latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+		return nil
+	}
+	if latitude == 0 && longitude == 0 {
+		return nil
+	}
+	return &geoPoint{Type: "Point", Coordinates: []float64{longitude, latitude}}
+}
+
 func convertToDaysOfOperation(list []operationDay) []model.OperationDay {
 	var result []model.OperationDay
 	if list != nil {
 		for _, d := range list {
-			item := model.OperationDay{Name: d.Name, OpenTime: d.OpenTime, CloseTime: d.CloseTime}
+			var intervals []model.OperationInterval
+			for _, interval := range d.Intervals {
+				intervals = append(intervals, model.OperationInterval{OpenTime: interval.OpenTime, CloseTime: interval.CloseTime})
+			}
+			item := model.OperationDay{Name: d.Name, AllDay: d.AllDay, Intervals: intervals}
 			result = append(result, item)
 		}
 	}
@@ -4468,16 +5899,118 @@ func convertFromDaysOfOperation(list []model.OperationDay) []operationDay {
 	var result []operationDay
 	if list != nil {
 		for _, d := range list {
-			item := operationDay{Name: d.Name, OpenTime: d.OpenTime, CloseTime: d.CloseTime}
+			var intervals []operationInterval
+			for _, interval := range d.Intervals {
+				intervals = append(intervals, operationInterval{OpenTime: interval.OpenTime, CloseTime: interval.CloseTime})
+			}
+			item := operationDay{Name: d.Name, AllDay: d.AllDay, Intervals: intervals}
 			result = append(result, item)
 		}
 	}
 	return result
 }
 
+func convertToLocationExceptions(list []locationException) []model.LocationException {
+	var result []model.LocationException
+	for _, e := range list {
+		item := model.LocationException{ID: e.ID, StartDate: e.StartDate, EndDate: e.EndDate, Type: e.Type,
+			OpenTime: e.OpenTime, CloseTime: e.CloseTime, Address: e.Address, Note: e.Note}
+		result = append(result, item)
+	}
+	return result
+}
+
+func convertFromLocationExceptions(list []model.LocationException) []locationException {
+	var result []locationException
+	for _, e := range list {
+		item := locationException{ID: e.ID, StartDate: e.StartDate, EndDate: e.EndDate, Type: e.Type,
+			OpenTime: e.OpenTime, CloseTime: e.CloseTime, Address: e.Address, Note: e.Note}
+		result = append(result, item)
+	}
+	return result
+}
+
+func countyToStorage(item *model.County, dateCreated time.Time) county {
+	var guidelines []guidline
+	for _, gl := range item.Guidelines {
+		var items []guidlineItem
+		for _, glItem := range gl.Items {
+			items = append(items, guidlineItem{Icon: glItem.Icon, Description: glItem.Description, Type: glItem.Type.Value})
+		}
+		guidelines = append(guidelines, guidline{ID: gl.ID, Name: gl.Name, Description: gl.Description, Items: items, DateCreated: dateCreated})
+	}
+
+	var countyStatuses []countyStatus
+	for _, cs := range item.CountyStatuses {
+		countyStatuses = append(countyStatuses, countyStatus{ID: cs.ID, Name: cs.Name, Description: cs.Description, DateCreated: dateCreated})
+	}
+
+	return county{ID: item.ID, Name: item.Name, StateProvince: item.StateProvince, Country: item.Country,
+		Guidelines: guidelines, CountyStatuses: countyStatuses, DateCreated: dateCreated}
+}
+
+func rulesToStorage(countyID string, rules []*model.Rule, dateCreated time.Time) []interface{} {
+	var result []interface{}
+	for _, r := range rules {
+		var resultsStates []testTypeResultCountyStatus
+		for _, rs := range r.ResultsStates {
+			resultsStates = append(resultsStates, testTypeResultCountyStatus{TestTypeResultID: rs.TestTypeResultID, CountyStatusID: rs.CountyStatusID})
+		}
+		result = append(result, rule{ID: r.ID, CountyID: countyID, TestTypeID: r.TestType.ID, Priority: r.Priority,
+			ResultsStates: resultsStates, DateCreated: dateCreated})
+	}
+	return result
+}
+
+func accessRuleToStorage(countyID string, item *model.AccessRule, dateCreated time.Time) accessRule {
+	var items []accessRuleCountyStatus
+	for _, ar := range item.Rules {
+		items = append(items, accessRuleCountyStatus{CountyStatusID: ar.CountyStatusID, Value: ar.Value})
+	}
+	return accessRule{ID: item.ID, CountyID: countyID, Rules: items, DateCreated: dateCreated}
+}
+
+func symptomRuleToStorage(countyID string, item *model.SymptomRule, dateCreated time.Time) symptomRule {
+	var items []symptomRuleItem
+	for _, sr := range item.Items {
+		items = append(items, symptomRuleItem{Gr1: sr.Gr1, Gr2: sr.Gr2, CountyStatusID: sr.CountyStatus.ID, NextStep: sr.NextStep})
+	}
+	return symptomRule{ID: item.ID, CountyID: countyID, Gr1Count: item.Gr1Count, Gr2Count: item.Gr2Count, Items: items, DateCreated: dateCreated}
+}
+
+func availableTestsToStorage(testTypes []model.TestType) []string {
+	var result []string
+	for _, tt := range testTypes {
+		result = append(result, tt.ID)
+	}
+	return result
+}
+
 func abortTransaction(sessionContext mongo.SessionContext) {
 	err := sessionContext.AbortTransaction(sessionContext)
 	if err != nil {
 		log.Printf("error on aborting a transaction - %s", err)
 	}
 }
+
+//transactionAttempts is how many times a transaction is run if it fails with a transient error
+const transactionAttempts = 3
+
+//retryTransientTransaction calls the function again while the transaction it runs fails with a transient error.
+//Such a transaction has not been committed so it is safe to run it again.
+func retryTransientTransaction(fn func() error) error {
+	var err error
+	for attempt := 1; attempt <= transactionAttempts; attempt++ {
+		err = fn()
+		if err == nil || !isTransientTransactionError(err) {
+			return err
+		}
+		log.Printf("transient transaction error on attempt %d - %s", attempt, err)
+	}
+	return err
+}
+
+func isTransientTransactionError(err error) bool {
+	labeled, ok := err.(interface{ HasErrorLabel(label string) bool })
+	return ok && labeled.HasErrorLabel("TransientTransactionError")
+}
@@ -0,0 +1,669 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"health/core/model"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	migrationsLockID = "migrations"
+	//migrationsLockTTL is how long the lock is valid if the instance holding it dies without releasing it
+	migrationsLockTTL = 10 * time.Minute
+	//migrationsLockRenewPeriod is how often the lock is extended while the migrations are applied
+	migrationsLockRenewPeriod = time.Minute
+	//migrationsLockWait is how long an instance waits for another one to finish the migrations
+	migrationsLockWait = 15 * time.Minute
+)
+
+//migration represents a single schema or data change. Once applied it is recorded in the migrations collection and it is never applied again.
+//Never change or remove an applied migration - add a new one instead.
+type migration struct {
+	version int
+	name    string
+	apply   func(m *database) error
+}
+
+//migrationRecord represents an applied migration stored in the migrations collection
+type migrationRecord struct {
+	Version    int       `bson:"_id"`
+	Name       string    `bson:"name"`
+	AppliedAt  time.Time `bson:"applied_at"`
+	DurationMS int64     `bson:"duration_ms"`
+}
+
+//lock represents a lock stored in the locks collection
+type lock struct {
+	ID        string    `bson:"_id"`
+	Owner     string    `bson:"owner"`
+	LockedAt  time.Time `bson:"locked_at"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
+
+//MigrationStatus represents the state of a migration
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+//migrations is the ordered list of all migrations. The first ones replace the checks which were applied on every start.
+//Creating an index which already exists is a no-op so they are safe for the databases created before the migrations.
+var migrations = []migration{
+	{version: 1, name: "users_indexes", apply: func(m *database) error {
+		//add external id index - unique
+		err := m.users.AddIndex(bson.D{primitive.E{Key: "external_id", Value: 1}}, true)
+		if err != nil {
+			return err
+		}
+		//add shibboleth index
+		err = m.users.AddIndex(bson.D{primitive.E{Key: "shibboleth_auth.uiucedu_uin", Value: 1}}, false)
+		if err != nil {
+			return err
+		}
+		//add uuid index
+		err = m.users.AddIndex(bson.D{primitive.E{Key: "uuid", Value: 1}}, false)
+		if err != nil {
+			return err
+		}
+		//add re_post index
+		return m.users.AddIndex(bson.D{primitive.E{Key: "re_post", Value: 1}}, false)
+	}},
+	{version: 2, name: "locations_indexes", apply: func(m *database) error {
+		err := m.locations.AddIndex(bson.D{primitive.E{Key: "provider_id", Value: 1}}, false)
+		if err != nil {
+			return err
+		}
+		return m.locations.AddIndex(bson.D{primitive.E{Key: "county_id", Value: 1}}, false)
+	}},
+	{version: 3, name: "ctests_indexes", apply: func(m *database) error {
+		err := m.ctests.AddIndex(bson.D{primitive.E{Key: "user_id", Value: 1}}, false)
+		if err != nil {
+			return err
+		}
+		err = m.ctests.AddIndex(bson.D{primitive.E{Key: "provider_id", Value: 1}}, false)
+		if err != nil {
+			return err
+		}
+		return m.ctests.AddIndex(bson.D{primitive.E{Key: "order_number", Value: 1}}, false)
+	}},
+	{version: 4, name: "manualtests_indexes", apply: func(m *database) error {
+		//old collection
+		manualtests := m.collection("manualtests")
+		err := manualtests.AddIndex(bson.D{primitive.E{Key: "user_id", Value: 1}}, false)
+		if err != nil {
+			return err
+		}
+		err = manualtests.AddIndex(bson.D{primitive.E{Key: "location_id", Value: 1}}, false)
+		if err != nil {
+			return err
+		}
+		err = manualtests.AddIndex(bson.D{primitive.E{Key: "county_id", Value: 1}}, false)
+		if err != nil {
+			return err
+		}
+		return manualtests.AddIndex(bson.D{primitive.E{Key: "status", Value: 1}}, false)
+	}},
+	{version: 5, name: "emanualtests_indexes", apply: func(m *database) error {
+		err := m.emanualtests.AddIndex(bson.D{primitive.E{Key: "user_id", Value: 1}}, false)
+		if err != nil {
+			return err
+		}
+		err = m.emanualtests.AddIndex(bson.D{primitive.E{Key: "location_id", Value: 1}}, false)
+		if err != nil {
+			return err
+		}
+		err = m.emanualtests.AddIndex(bson.D{primitive.E{Key: "county_id", Value: 1}}, false)
+		if err != nil {
+			return err
+		}
+		return m.emanualtests.AddIndex(bson.D{primitive.E{Key: "status", Value: 1}}, false)
+	}},
+	{version: 6, name: "news_indexes", apply: func(m *database) error {
+		return m.news.AddIndex(bson.D{primitive.E{Key: "date", Value: 1}}, false)
+	}},
+	{version: 7, name: "status_indexes", apply: func(m *database) error {
+		//old collection
+		status := m.collection("status")
+		return status.AddIndex(bson.D{primitive.E{Key: "user_id", Value: 1}}, true)
+	}},
+	{version: 8, name: "estatus_indexes", apply: func(m *database) error {
+		err := m.estatus.AddIndex(bson.D{primitive.E{Key: "user_id", Value: 1}}, false)
+		if err != nil {
+			return err
+		}
+		return m.estatus.AddIndex(bson.D{primitive.E{Key: "app_version", Value: 1}}, false)
+	}},
+	{version: 9, name: "history_indexes", apply: func(m *database) error {
+		//old collection
+		history := m.collection("history")
+		err := history.AddIndex(bson.D{primitive.E{Key: "user_id", Value: 1}}, false)
+		if err != nil {
+			return err
+		}
+		return history.AddIndex(bson.D{primitive.E{Key: "date", Value: 1}}, false)
+	}},
+	{version: 10, name: "ehistory_indexes", apply: func(m *database) error {
+		err := m.ehistory.AddIndex(bson.D{primitive.E{Key: "user_id", Value: 1}}, false)
+		if err != nil {
+			return err
+		}
+		return m.ehistory.AddIndex(bson.D{primitive.E{Key: "date", Value: 1}}, false)
+	}},
+	{version: 11, name: "counties_indexes", apply: func(m *database) error {
+		err := m.counties.AddIndex(bson.D{primitive.E{Key: "guidelines.id", Value: 1}}, false)
+		if err != nil {
+			return err
+		}
+		return m.counties.AddIndex(bson.D{primitive.E{Key: "county_statuses.id", Value: 1}}, false)
+	}},
+	{version: 12, name: "testtypes_indexes", apply: func(m *database) error {
+		return m.testtypes.AddIndex(bson.D{primitive.E{Key: "results._id", Value: 1}}, false)
+	}},
+	{version: 13, name: "rules_indexes", apply: func(m *database) error {
+		err := m.rules.AddIndex(bson.D{primitive.E{Key: "county_id", Value: 1}}, false)
+		if err != nil {
+			return err
+		}
+		return m.rules.AddIndex(bson.D{primitive.E{Key: "test_type_id", Value: 1}}, false)
+	}},
+	{version: 14, name: "symptomgroups_indexes", apply: func(m *database) error {
+		return m.symptomgroups.AddIndex(bson.D{primitive.E{Key: "symptoms.id", Value: 1}}, false)
+	}},
+	{version: 15, name: "symptomrules_indexes", apply: func(m *database) error {
+		return m.symptomrules.AddIndex(bson.D{primitive.E{Key: "county_id", Value: 1}}, true)
+	}},
+	{version: 16, name: "symptoms_indexes", apply: func(m *database) error {
+		return m.symptoms.AddIndex(bson.D{primitive.E{Key: "app_version", Value: 1}}, false)
+	}},
+	{version: 17, name: "crules_indexes", apply: func(m *database) error {
+		err := m.crules.AddIndex(bson.D{primitive.E{Key: "app_version", Value: 1}}, false)
+		if err != nil {
+			return err
+		}
+		return m.crules.AddIndex(bson.D{primitive.E{Key: "county_id", Value: 1}}, false)
+	}},
+	{version: 18, name: "traceexposures_indexes", apply: func(m *database) error {
+		err := m.traceexposures.AddIndex(bson.D{primitive.E{Key: "date_added", Value: 1}}, false)
+		if err != nil {
+			return err
+		}
+		return m.traceexposures.AddIndex(bson.D{primitive.E{Key: "timestamp", Value: 1}}, false)
+	}},
+	{version: 19, name: "accessrules_indexes", apply: func(m *database) error {
+		return m.accessrules.AddIndex(bson.D{primitive.E{Key: "county_id", Value: 1}}, true)
+	}},
+	{version: 20, name: "uinoverrides_indexes", apply: func(m *database) error {
+		err := m.uinoverrides.AddIndex(bson.D{primitive.E{Key: "uin", Value: 1}}, true)
+		if err != nil {
+			return err
+		}
+		err = m.uinoverrides.AddIndex(bson.D{primitive.E{Key: "category", Value: 1}}, false)
+		if err != nil {
+			return err
+		}
+		//delete records when expiration - time to live 0
+		options := options.Index()
+		eas := int32(0)
+		options.ExpireAfterSeconds = &eas
+		return m.uinoverrides.AddIndexWithOptions(bson.D{primitive.E{Key: "expiration", Value: 1}}, options)
+	}},
+	{version: 21, name: "uinbuildingaccess_indexes", apply: func(m *database) error {
+		return m.uinbuildingaccess.AddIndex(bson.D{primitive.E{Key: "uin", Value: 1}}, true)
+	}},
+	{version: 22, name: "appversions_indexes", apply: func(m *database) error {
+		return m.appversions.AddIndex(bson.D{primitive.E{Key: "version", Value: 1}}, true)
+	}},
+	{version: 23, name: "retention_indexes", apply: func(m *database) error {
+		err := m.ctests.AddIndex(bson.D{primitive.E{Key: "date_created", Value: 1}}, false)
+		if err != nil {
+			return err
+		}
+		err = m.emanualtests.AddIndex(bson.D{primitive.E{Key: "date_created", Value: 1}}, false)
+		if err != nil {
+			return err
+		}
+		return m.traceexposures.AddIndex(bson.D{primitive.E{Key: "expirestamp", Value: 1}}, false)
+	}},
+	{version: 24, name: "erasurereceipts_indexes", apply: func(m *database) error {
+		return m.erasurereceipts.AddIndex(bson.D{primitive.E{Key: "subject_hash", Value: 1}}, false)
+	}},
+	{version: 25, name: "users_key_versions", apply: func(m *database) error {
+		//the current public key of the existing users becomes version 1
+		usersFilter := bson.D{
+			primitive.E{Key: "key_version", Value: bson.M{"$exists": false}},
+			primitive.E{Key: "public_key", Value: bson.M{"$nin": bson.A{nil, ""}}},
+		}
+		usersUpdate := bson.A{bson.M{"$set": bson.M{
+			"key_version": 1,
+			"public_keys": bson.A{bson.M{"version": 1, "public_key": "$public_key", "date_created": "$date_created"}},
+		}}}
+		_, err := m.users.UpdateMany(usersFilter, usersUpdate, nil)
+		if err != nil {
+			return err
+		}
+
+		//the existing items are considered encrypted for it
+		itemsFilter := bson.D{primitive.E{Key: "key_version", Value: bson.M{"$exists": false}}}
+		itemsUpdate := bson.D{primitive.E{Key: "$set", Value: bson.D{primitive.E{Key: "key_version", Value: 1}}}}
+		_, err = m.ctests.UpdateMany(itemsFilter, itemsUpdate, nil)
+		if err != nil {
+			return err
+		}
+		_, err = m.ehistory.UpdateMany(itemsFilter, itemsUpdate, nil)
+		if err != nil {
+			return err
+		}
+		return m.ctests.AddIndex(bson.D{primitive.E{Key: "processed", Value: 1}, primitive.E{Key: "provider_id", Value: 1}}, false)
+	}},
+	{version: 26, name: "configrevisions", apply: func(m *database) error {
+		err := m.configrevisions.AddIndex(bson.D{primitive.E{Key: "type", Value: 1}, primitive.E{Key: "app_version", Value: 1},
+			primitive.E{Key: "county_id", Value: 1}, primitive.E{Key: "number", Value: 1}}, true)
+		if err != nil {
+			return err
+		}
+
+		//the current crules and symptoms become the first published revisions so they can be rolled back to
+		var cRules []*model.CRules
+		err = m.crules.Find(bson.D{}, &cRules, nil)
+		if err != nil {
+			return err
+		}
+		var symptoms []*model.Symptoms
+		err = m.symptoms.Find(bson.D{}, &symptoms, nil)
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		var revisions []interface{}
+		newRevision := func(configType string, appVersion string, countyID string, data string) error {
+			id, err := uuid.NewUUID()
+			if err != nil {
+				return err
+			}
+			revisions = append(revisions, model.ConfigRevision{ID: id.String(), Type: configType, AppVersion: appVersion, CountyID: countyID,
+				Number: 1, Data: data, Status: "published", CreatedBy: "migration", DateCreated: now, DatePublished: &now})
+			return nil
+		}
+		for _, item := range cRules {
+			err = newRevision("crules", item.AppVersion, item.CountyID, item.Data)
+			if err != nil {
+				return err
+			}
+		}
+		for _, item := range symptoms {
+			err = newRevision("symptoms", item.AppVersion, "", item.Items)
+			if err != nil {
+				return err
+			}
+		}
+		if len(revisions) == 0 {
+			return nil
+		}
+		_, err = m.configrevisions.InsertMany(revisions, nil)
+		return err
+	}},
+	{version: 27, name: "configschemas_indexes", apply: func(m *database) error {
+		return m.configschemas.AddIndex(bson.D{primitive.E{Key: "type", Value: 1}, primitive.E{Key: "app_version", Value: 1}}, true)
+	}},
+	{version: 28, name: "appversions_statuses", apply: func(m *database) error {
+		//the existing versions are supported
+		filter := bson.D{primitive.E{Key: "status", Value: bson.M{"$exists": false}}}
+		update := bson.D{primitive.E{Key: "$set", Value: bson.D{primitive.E{Key: "status", Value: "supported"}}}}
+		_, err := m.appversions.UpdateMany(filter, update, nil)
+		return err
+	}},
+	{version: 29, name: "news_statuses", apply: func(m *database) error {
+		//the existing news are published
+		filter := bson.D{primitive.E{Key: "status", Value: bson.M{"$exists": false}}}
+		update := bson.D{primitive.E{Key: "$set", Value: bson.D{primitive.E{Key: "status", Value: "published"}, primitive.E{Key: "pinned", Value: false}}}}
+		_, err := m.news.UpdateMany(filter, update, nil)
+		if err != nil {
+			return err
+		}
+		return m.news.AddIndex(bson.D{primitive.E{Key: "status", Value: 1}, primitive.E{Key: "publish_at", Value: 1}, primitive.E{Key: "expire_at", Value: 1}}, false)
+	}},
+	{version: 30, name: "news_sources_indexes", apply: func(m *database) error {
+		//the imported items are unique per source, the news created by the admins do not have a guid
+		options := options.Index()
+		options.SetUnique(true)
+		options.SetPartialFilterExpression(bson.M{"guid": bson.M{"$type": "string"}})
+		return m.news.AddIndexWithOptions(bson.D{primitive.E{Key: "source_id", Value: 1}, primitive.E{Key: "guid", Value: 1}}, options)
+	}},
+	{version: 31, name: "resources_sync", apply: func(m *database) error {
+		//the existing resources are not stale
+		filter := bson.D{primitive.E{Key: "stale", Value: bson.M{"$exists": false}}}
+		update := bson.D{primitive.E{Key: "$set", Value: bson.D{primitive.E{Key: "stale", Value: false}}}}
+		_, err := m.resources.UpdateMany(filter, update, nil)
+		if err != nil {
+			return err
+		}
+
+		//a resource is synced with one upstream item, the resources created by the admins do not have a source key
+		resourcesOptions := options.Index()
+		resourcesOptions.SetUnique(true)
+		resourcesOptions.SetPartialFilterExpression(bson.M{"source_key": bson.M{"$type": "string"}})
+		err = m.resources.AddIndexWithOptions(bson.D{primitive.E{Key: "source_key", Value: 1}}, resourcesOptions)
+		if err != nil {
+			return err
+		}
+
+		//there is one pending change per upstream item, so the instances do not queue the same change twice
+		changesOptions := options.Index()
+		changesOptions.SetUnique(true)
+		changesOptions.SetPartialFilterExpression(bson.M{"status": "pending"})
+		err = m.resourcechanges.AddIndexWithOptions(bson.D{primitive.E{Key: "source_key", Value: 1}}, changesOptions)
+		if err != nil {
+			return err
+		}
+		return m.resourcechanges.AddIndex(bson.D{primitive.E{Key: "status", Value: 1}, primitive.E{Key: "date_created", Value: -1}}, false)
+	}},
+	{version: 32, name: "news_categories_index", apply: func(m *database) error {
+		return m.news.AddIndex(bson.D{primitive.E{Key: "categories", Value: 1}}, false)
+	}},
+	{version: 33, name: "locations_operation_intervals", apply: func(m *database) error {
+		//the open and close times of every day become its only interval, they are moved as they are so nothing is lost
+		filter := bson.D{primitive.E{Key: "days_of_operation.open_time", Value: bson.M{"$exists": true}}}
+		update := bson.A{bson.M{"$set": bson.M{
+			"days_of_operation": bson.M{"$map": bson.M{
+				"input": "$days_of_operation",
+				"as":    "day",
+				"in": bson.M{
+					"name":      "$$day.name",
+					"all_day":   false,
+					"intervals": bson.A{bson.M{"open_time": "$$day.open_time", "close_time": "$$day.close_time"}},
+				},
+			}},
+		}}}
+		_, err := m.locations.UpdateMany(filter, update, nil)
+		return err
+	}},
+	{version: 34, name: "waittimereports_indexes", apply: func(m *database) error {
+		err := m.waittimereports.AddIndex(bson.D{primitive.E{Key: "location_id", Value: 1}, primitive.E{Key: "date_created", Value: -1}}, false)
+		if err != nil {
+			return err
+		}
+		return m.waittimereports.AddIndex(bson.D{primitive.E{Key: "date_created", Value: 1}}, false)
+	}},
+	{version: 35, name: "locations_geo_point", apply: func(m *database) error {
+		//the locations with invalid or not set (0,0) coordinates do not have a point, the 2dsphere index skips them
+		filter := bson.D{
+			primitive.E{Key: "latitude", Value: bson.M{"$gte": -90, "$lte": 90}},
+			primitive.E{Key: "longitude", Value: bson.M{"$gte": -180, "$lte": 180}},
+			primitive.E{Key: "$nor", Value: bson.A{bson.M{"latitude": 0, "longitude": 0}}},
+		}
+		update := bson.A{bson.M{"$set": bson.M{
+			"geo_point": bson.M{"type": "Point", "coordinates": bson.A{"$longitude", "$latitude"}},
+		}}}
+		_, err := m.locations.UpdateMany(filter, update, nil)
+		if err != nil {
+			return err
+		}
+		return m.locations.AddIndex(bson.D{primitive.E{Key: "geo_point", Value: "2dsphere"}}, false)
+	}},
+	{version: 36, name: "appointments_indexes", apply: func(m *database) error {
+		err := m.appointments.AddIndex(bson.D{primitive.E{Key: "user_id", Value: 1}}, false)
+		if err != nil {
+			return err
+		}
+		err = m.appointments.AddIndex(bson.D{primitive.E{Key: "location_id", Value: 1}, primitive.E{Key: "start", Value: 1}}, false)
+		if err != nil {
+			return err
+		}
+		err = m.appointments.AddIndex(bson.D{primitive.E{Key: "start", Value: 1}, primitive.E{Key: "reminder_sent", Value: 1}}, false)
+		if err != nil {
+			return err
+		}
+		//the user can have only one booked appointment at a location
+		bookedOptions := options.Index()
+		bookedOptions.SetUnique(true)
+		bookedOptions.SetPartialFilterExpression(bson.M{"status": "booked"})
+		err = m.appointments.AddIndexWithOptions(bson.D{primitive.E{Key: "user_id", Value: 1}, primitive.E{Key: "location_id", Value: 1}}, bookedOptions)
+		if err != nil {
+			return err
+		}
+		//the slots collection must exist before the first transaction which uses it
+		return m.appointmentslots.AddIndex(bson.D{primitive.E{Key: "start", Value: 1}}, false)
+	}},
+}
+
+//migrate applies the pending migrations in order. If dry run is true it only gives the pending migrations without applying them.
+func (m *database) migrate(dryRun bool) ([]MigrationStatus, error) {
+	log.Printf("apply migrations - dry run:%t .....", dryRun)
+
+	err := validateMigrations(migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	var owner string
+	if !dryRun {
+		//only one instance applies the migrations, the others wait for it
+		owner, err = m.acquireLock(migrationsLockID, migrationsLockTTL, migrationsLockWait)
+		if err != nil {
+			return nil, err
+		}
+		defer m.releaseLock(migrationsLockID, owner)
+
+		//a long migration can outlive the lock so it is extended until the migrations finish
+		stopRenewing := m.keepLock(migrationsLockID, owner, migrationsLockTTL, migrationsLockRenewPeriod)
+		defer stopRenewing()
+	}
+
+	//find the pending migrations. It must be done after the lock is taken as another instance could have applied some of them
+	applied, err := m.loadAppliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []MigrationStatus
+	for _, current := range migrations {
+		if _, ok := applied[current.version]; ok {
+			continue
+		}
+
+		if dryRun {
+			log.Printf("migration %d %s is pending", current.version, current.name)
+			result = append(result, MigrationStatus{Version: current.version, Name: current.name, Applied: false})
+			continue
+		}
+
+		//never apply a migration if the lock has been lost
+		err = m.extendLock(migrationsLockID, owner, migrationsLockTTL)
+		if err != nil {
+			return result, fmt.Errorf("error extending the lock before migration %d %s - %s", current.version, current.name, err.Error())
+		}
+
+		log.Printf("apply migration %d %s .....", current.version, current.name)
+		startedAt := time.Now()
+		err = current.apply(m)
+		if err != nil {
+			return result, fmt.Errorf("error applying migration %d %s - %s", current.version, current.name, err.Error())
+		}
+		appliedAt := time.Now()
+
+		record := migrationRecord{Version: current.version, Name: current.name, AppliedAt: appliedAt,
+			DurationMS: appliedAt.Sub(startedAt).Milliseconds()}
+		_, err = m.migrations.InsertOne(&record)
+		if err != nil {
+			return result, fmt.Errorf("error recording migration %d %s - %s", current.version, current.name, err.Error())
+		}
+		result = append(result, MigrationStatus{Version: current.version, Name: current.name, Applied: true, AppliedAt: &appliedAt})
+		log.Printf("migration %d %s applied", current.version, current.name)
+	}
+
+	log.Printf("migrations passed - %d processed", len(result))
+	return result, nil
+}
+
+//migrationsStatus gives the state of all migrations
+func (m *database) migrationsStatus() ([]MigrationStatus, error) {
+	applied, err := m.loadAppliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]MigrationStatus, len(migrations))
+	for i, current := range migrations {
+		status := MigrationStatus{Version: current.version, Name: current.name}
+		if record, ok := applied[current.version]; ok {
+			appliedAt := record.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		result[i] = status
+	}
+	return result, nil
+}
+
+func (m *database) loadAppliedMigrations() (map[int]migrationRecord, error) {
+	var records []migrationRecord
+	err := m.migrations.Find(bson.D{}, &records, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[int]migrationRecord, len(records))
+	for _, record := range records {
+		result[record.Version] = record
+	}
+	return result, nil
+}
+
+//acquireLock takes the lock with the provided id. It waits if the lock is held by someone else. It gives the owner of the lock
+func (m *database) acquireLock(ID string, ttl time.Duration, wait time.Duration) (string, error) {
+	ownerID, err := uuid.NewUUID()
+	if err != nil {
+		return "", err
+	}
+	owner := ownerID.String()
+
+	deadline := time.Now().Add(wait)
+	for {
+		now := time.Now()
+
+		//the lock is free if there is no record for it or if it is expired
+		filter := bson.D{primitive.E{Key: "_id", Value: ID}, primitive.E{Key: "expires_at", Value: bson.M{"$lt": now}}}
+		update := bson.D{
+			primitive.E{Key: "$set", Value: bson.D{
+				primitive.E{Key: "owner", Value: owner},
+				primitive.E{Key: "locked_at", Value: now},
+				primitive.E{Key: "expires_at", Value: now.Add(ttl)},
+			}},
+		}
+		opts := options.Update().SetUpsert(true)
+		_, err = m.locks.UpdateOne(filter, update, opts)
+		if err == nil {
+			log.Printf("lock %s acquired by %s", ID, owner)
+			return owner, nil
+		}
+		if !isDuplicateKeyError(err) {
+			return "", err
+		}
+
+		//held by someone else
+		if now.After(deadline) {
+			return "", errors.New("timeout waiting for lock " + ID)
+		}
+		log.Printf("lock %s is held by another instance, waiting.....", ID)
+		time.Sleep(time.Second)
+	}
+}
+
+//extendLock moves the expiry of the lock held by the owner. It gives an error if the owner does not hold the lock anymore
+func (m *database) extendLock(ID string, owner string, ttl time.Duration) error {
+	filter := bson.D{primitive.E{Key: "_id", Value: ID}, primitive.E{Key: "owner", Value: owner}}
+	update := bson.D{
+		primitive.E{Key: "$set", Value: bson.D{
+			primitive.E{Key: "expires_at", Value: time.Now().Add(ttl)},
+		}},
+	}
+	result, err := m.locks.UpdateOne(filter, update, nil)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("the lock " + ID + " is not held by " + owner)
+	}
+	return nil
+}
+
+//keepLock extends the lock held by the owner periodically until the returned function is called
+func (m *database) keepLock(ID string, owner string, ttl time.Duration, period time.Duration) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				err := m.extendLock(ID, owner, ttl)
+				if err != nil {
+					log.Printf("error extending lock %s - %s", ID, err)
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+func (m *database) releaseLock(ID string, owner string) {
+	filter := bson.D{primitive.E{Key: "_id", Value: ID}, primitive.E{Key: "owner", Value: owner}}
+	_, err := m.locks.DeleteOne(filter, nil)
+	if err != nil {
+		log.Printf("error releasing lock %s - %s", ID, err)
+		return
+	}
+	log.Printf("lock %s released by %s", ID, owner)
+}
+
+func validateMigrations(list []migration) error {
+	for i := 1; i < len(list); i++ {
+		if list[i].version <= list[i-1].version {
+			return fmt.Errorf("migration %d %s is not in order", list[i].version, list[i].name)
+		}
+	}
+	return nil
+}
+
+func isDuplicateKeyError(err error) bool {
+	if writeException, ok := err.(mongo.WriteException); ok {
+		for _, writeError := range writeException.WriteErrors {
+			if writeError.Code == 11000 {
+				return true
+			}
+		}
+	}
+	if commandError, ok := err.(mongo.CommandError); ok {
+		return commandError.Code == 11000
+	}
+	return false
+}
@@ -18,6 +18,7 @@
 package web
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"health/core"
@@ -27,8 +28,11 @@ import (
 	"io/ioutil"
 	"log"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/casbin/casbin"
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"gopkg.in/go-playground/validator.v9"
 
@@ -37,9 +41,10 @@ import (
 
 //Adapter entity
 type Adapter struct {
-	host          string
-	auth          *Auth
-	authorization *casbin.Enforcer
+	host           string
+	requestTimeout time.Duration
+	auth           *Auth
+	authorization  *casbin.Enforcer
 
 	apisHandler      rest.ApisHandler
 	adminApisHandler rest.AdminApisHandler
@@ -85,6 +90,7 @@ func (we Adapter) Start() {
 	we.auth.Start()
 
 	router := mux.NewRouter().StrictSlash(true)
+	router.Use(we.requestContextMiddleware)
 
 	// handle apis
 	subrouter := router.PathPrefix("/health").Subrouter()
@@ -99,6 +105,11 @@ func (we Adapter) Start() {
 	covid19RestSubrouter.HandleFunc("/login", we.loginUser).Methods("POST")
 	covid19RestSubrouter.HandleFunc("/user", we.getUser).Methods("GET")
 	covid19RestSubrouter.HandleFunc("/user/clear", we.userAuthWrapFunc(we.apisHandler.ClearUserData)).Methods("GET")
+	covid19RestSubrouter.HandleFunc("/user/erasure", we.userAuthWrapFunc(we.apisHandler.EraseUserData)).Methods("POST")
+	covid19RestSubrouter.HandleFunc("/user/erasures", we.userAuthWrapFunc(we.apisHandler.GetUserErasureReceipts)).Methods("GET")
+	covid19RestSubrouter.HandleFunc("/user/erasures/{id}", we.userAuthWrapFunc(we.apisHandler.GetUserErasureReceipt)).Methods("GET")
+	covid19RestSubrouter.HandleFunc("/user/export", we.userAuthWrapFunc(we.apisHandler.ExportUserData)).Methods("GET")
+	covid19RestSubrouter.HandleFunc("/user/keys", we.userAuthWrapFunc(we.apisHandler.GetUserKeysStatus)).Methods("GET")
 
 	covid19RestSubrouter.HandleFunc("/ctests", we.userAuthWrapFunc(we.apisHandler.GetCTests)).Methods("GET")
 	covid19RestSubrouter.HandleFunc("/ctests/{id}", we.userAuthWrapFunc(we.apisHandler.UpdateCTest)).Methods("PUT")
@@ -121,6 +132,11 @@ func (we Adapter) Start() {
 
 	covid19RestSubrouter.HandleFunc("/building-access", we.userAuthWrapFunc(we.apisHandler.SetUINBuildingAccess)).Methods("PUT")
 
+	covid19RestSubrouter.HandleFunc("/appointments", we.userAuthWrapFunc(we.apisHandler.GetAppointments)).Methods("GET")
+	covid19RestSubrouter.HandleFunc("/appointments", we.userAuthWrapFunc(we.apisHandler.BookAppointment)).Methods("POST")
+	covid19RestSubrouter.HandleFunc("/appointments/{id}", we.userAuthWrapFunc(we.apisHandler.RescheduleAppointment)).Methods("PUT")
+	covid19RestSubrouter.HandleFunc("/appointments/{id}", we.userAuthWrapFunc(we.apisHandler.CancelAppointment)).Methods("DELETE")
+
 	//provider auth
 	covid19RestSubrouter.HandleFunc("/users/uin/{uin}", we.providerAuthWrapFunc(we.apisHandler.GetUserByShibbolethUIN)).Methods("GET")
 	covid19RestSubrouter.HandleFunc("/users/re-post", we.providerAuthWrapFunc(we.apisHandler.GetUsersForRePost)).Methods("GET")
@@ -132,10 +148,14 @@ func (we Adapter) Start() {
 	covid19RestSubrouter.HandleFunc("/ext/uin-overrides/uin/{uin}", we.providerAuthWrapFunc(we.apisHandler.UpdateExtUINOverride)).Methods("PUT")
 	covid19RestSubrouter.HandleFunc("/ext/uin-overrides/uin/{uin}", we.providerAuthWrapFunc(we.apisHandler.DeleteExtUINOverride)).Methods("DELETE")
 	covid19RestSubrouter.HandleFunc("/ext/building-access", we.providerAuthWrapFunc(we.apisHandler.GetExtBuildingAccess)).Methods("GET").Queries("uin", "")
+	covid19RestSubrouter.HandleFunc("/ext/key-rotations", we.providerAuthWrapFunc(we.apisHandler.GetPendingReEncryptions)).Methods("GET")
+	covid19RestSubrouter.HandleFunc("/ext/ctests/{id}", we.providerAuthWrapFunc(we.apisHandler.ReEncryptCTest)).Methods("PUT")
+	covid19RestSubrouter.HandleFunc("/ext/locations/{id}/wait-time", we.providerAuthWrapFunc(we.apisHandler.ReportWaitTime)).Methods("POST")
 
 	// api key auth
 	covid19RestSubrouter.HandleFunc("/counties", we.authWrapFunc(we.apisHandler.GetCounties)).Methods("GET")
 	covid19RestSubrouter.HandleFunc("/counties/{id}", we.authWrapFunc(we.apisHandler.GetCounty)).Methods("GET")
+	covid19RestSubrouter.HandleFunc("/counties/{id}/status-evaluation", we.authWrapFunc(we.apisHandler.EvaluateCountyStatus)).Methods("POST")
 
 	covid19RestSubrouter.HandleFunc("/rules/county/{county-id}", we.authWrapFunc(we.apisHandler.GetRulesByCounty)).Methods("GET")
 	//deprecated
@@ -148,6 +168,9 @@ func (we Adapter) Start() {
 	covid19RestSubrouter.HandleFunc("/faq", we.authWrapFunc(we.apisHandler.GetFAQ)).Methods("GET")
 
 	covid19RestSubrouter.HandleFunc("/news", we.authWrapFunc(we.apisHandler.GetNews)).Methods("GET")
+	//public feeds
+	covid19RestSubrouter.HandleFunc("/news/rss", we.wrapFunc(we.apisHandler.GetNewsRSS)).Methods("GET")
+	covid19RestSubrouter.HandleFunc("/news/atom", we.wrapFunc(we.apisHandler.GetNewsAtom)).Methods("GET")
 
 	covid19RestSubrouter.HandleFunc("/providers", we.authWrapFunc(we.apisHandler.GetProvidersByCounties)).Methods("GET").Queries("county-ids", "")
 	covid19RestSubrouter.HandleFunc("/providers", we.authWrapFunc(we.apisHandler.GetProviders)).Methods("GET")
@@ -155,7 +178,9 @@ func (we Adapter) Start() {
 
 	covid19RestSubrouter.HandleFunc("/locations", we.authWrapFunc(we.apisHandler.GetLocationsByCountyIDProviderID)).Methods("GET").Queries("county-id", "", "provider-id", "")
 	covid19RestSubrouter.HandleFunc("/locations", we.authWrapFunc(we.apisHandler.GetLocationsByCountyID)).Methods("GET").Queries("county-id", "")
+	covid19RestSubrouter.HandleFunc("/locations/nearby", we.authWrapFunc(we.apisHandler.GetLocationsNearby)).Methods("GET")
 	covid19RestSubrouter.HandleFunc("/locations/{id}", we.authWrapFunc(we.apisHandler.GetLocation)).Methods("GET")
+	covid19RestSubrouter.HandleFunc("/locations/{id}/appointment-slots", we.authWrapFunc(we.apisHandler.GetAppointmentSlots)).Methods("GET")
 
 	covid19RestSubrouter.HandleFunc("/test-types", we.authWrapFunc(we.apisHandler.GetTestTypesByIDs)).Methods("GET").Queries("ids", "")
 	covid19RestSubrouter.HandleFunc("/test-types", we.authWrapFunc(we.apisHandler.GetTestTypes)).Methods("GET")
@@ -176,17 +201,28 @@ func (we Adapter) Start() {
 
 	adminRestSubrouter.HandleFunc("/app-versions", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetAppVersions)).Methods("GET")
 	adminRestSubrouter.HandleFunc("/app-versions", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateAppVersion)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/app-versions/statuses", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetAppVersionsStatuses)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/app-versions/status", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateAppVersionStatus)).Methods("PUT")
 
 	adminRestSubrouter.HandleFunc("/news", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetNews)).Methods("GET")
 	adminRestSubrouter.HandleFunc("/news", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateNews)).Methods("POST")
 	adminRestSubrouter.HandleFunc("/news/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateNews)).Methods("PUT")
 	adminRestSubrouter.HandleFunc("/news/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DeleteNews)).Methods("DELETE")
+	adminRestSubrouter.HandleFunc("/news-sources", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetNewsSources)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/news-sources", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateNewsSource)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/news-sources/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateNewsSource)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/news-sources/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DeleteNewsSource)).Methods("DELETE")
+	adminRestSubrouter.HandleFunc("/data-provider/metrics", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetDataProviderMetrics)).Methods("GET")
 
 	adminRestSubrouter.HandleFunc("/resources", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetResources)).Methods("GET")
 	adminRestSubrouter.HandleFunc("/resources", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateResources)).Methods("POST")
 	adminRestSubrouter.HandleFunc("/resources/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateResource)).Methods("PUT")
 	adminRestSubrouter.HandleFunc("/resources/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DeleteResource)).Methods("DELETE")
 	adminRestSubrouter.HandleFunc("/resources/display-order", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateDisplaOrderResources)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/resources/sync", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.SyncResources)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/resource-changes", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetResourceChanges)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/resource-changes/{id}/approve", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.ApproveResourceChange)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/resource-changes/{id}/reject", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.RejectResourceChange)).Methods("PUT")
 
 	//TODO refactor
 	adminRestSubrouter.HandleFunc("/faq", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetFAQs)).Methods("GET")
@@ -215,6 +251,14 @@ func (we Adapter) Start() {
 	adminRestSubrouter.HandleFunc("/counties", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateCounty)).Methods("POST")
 	adminRestSubrouter.HandleFunc("/counties/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateCounty)).Methods("PUT")
 	adminRestSubrouter.HandleFunc("/counties/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DeleteCounty)).Methods("DELETE")
+	adminRestSubrouter.HandleFunc("/counties/{id}/bundle", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.ExportCountyBundle)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/counties/{id}/bundle", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.ApplyCountyBundle)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/counties/{id}/bundle/diff", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DiffCountyBundle)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/counties/{id}/status-simulation", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.SimulateCountyStatus)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/counties/bundle", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.ImportCountyBundle)).Methods("POST")
+
+	adminRestSubrouter.HandleFunc("/erasures", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetErasureReceipts)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/erasures/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetErasureReceipt)).Methods("GET")
 
 	adminRestSubrouter.HandleFunc("/guidelines", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateGuideline)).Methods("POST")
 	adminRestSubrouter.HandleFunc("/guidelines/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateGuideline)).Methods("PUT")
@@ -235,6 +279,11 @@ func (we Adapter) Start() {
 	adminRestSubrouter.HandleFunc("/locations", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateLocation)).Methods("POST")
 	adminRestSubrouter.HandleFunc("/locations/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateLocation)).Methods("PUT")
 	adminRestSubrouter.HandleFunc("/locations/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DeleteLocation)).Methods("DELETE")
+	adminRestSubrouter.HandleFunc("/locations/{id}/exceptions", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateLocationExceptions)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/locations/{id}/wait-time", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.ReportWaitTime)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/locations/{id}/wait-times", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetWaitTimeReports)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/locations/{id}/appointment-settings", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateLocationAppointmentSettings)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/locations/{id}/appointments", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetLocationAppointments)).Methods("GET")
 
 	//deprecated
 	adminRestSubrouter.HandleFunc("/symptoms", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateSymptom)).Methods("POST")
@@ -262,9 +311,23 @@ func (we Adapter) Start() {
 
 	adminRestSubrouter.HandleFunc("/crules", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetCRules)).Methods("GET").Queries("county-id", "", "app-version", "")
 	adminRestSubrouter.HandleFunc("/crules", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateOrUpdateCRules)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/crules/revisions", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetCRulesRevisions)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/crules/revisions/draft", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.SaveCRulesDraft)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/crules/revisions/draft/publish", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.PublishCRulesDraft)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/crules/revisions/draft/discard", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DiscardCRulesDraft)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/crules/revisions/rollback", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.RollbackCRules)).Methods("POST")
 
 	adminRestSubrouter.HandleFunc("/symptoms", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetSymptoms)).Methods("GET").Queries("app-version", "")
 	adminRestSubrouter.HandleFunc("/symptoms", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateOrUpdateSymptoms)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/symptoms/revisions", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetSymptomsRevisions)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/symptoms/revisions/draft", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.SaveSymptomsDraft)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/symptoms/revisions/draft/publish", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.PublishSymptomsDraft)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/symptoms/revisions/draft/discard", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DiscardSymptomsDraft)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/symptoms/revisions/rollback", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.RollbackSymptoms)).Methods("POST")
+
+	adminRestSubrouter.HandleFunc("/config-schemas", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetConfigSchemas)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/config-schemas", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.SaveConfigSchema)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/config-schemas", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DeleteConfigSchema)).Methods("DELETE")
 
 	adminRestSubrouter.HandleFunc("/uin-overrides", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetUINOverrides)).Methods("GET")
 	adminRestSubrouter.HandleFunc("/uin-overrides", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateUINOverride)).Methods("POST")
@@ -277,6 +340,9 @@ func (we Adapter) Start() {
 
 	adminRestSubrouter.HandleFunc("/audit", we.adminAppIDTokenAuthWrapFunc(we.apisHandler.GetAudit)).Methods("GET")
 
+	adminRestSubrouter.HandleFunc("/retention-policies", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetRetentionPolicies)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/retention-policies/{entity}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateRetentionPolicy)).Methods("PUT")
+
 	log.Fatal(http.ListenAndServe(":80", router))
 }
 
@@ -290,6 +356,28 @@ func (we Adapter) serveDocUI() http.Handler {
 	return httpSwagger.Handler(httpSwagger.URL(url))
 }
 
+//requestContextMiddleware sets the request id and the deadline for the request. The context is cancelled when the client goes away
+func (we Adapter) requestContextMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requestID := req.Header.Get("X-Request-ID")
+		if len(requestID) == 0 {
+			id, err := uuid.NewUUID()
+			if err != nil {
+				log.Printf("Error on generating request id - %s", err.Error())
+			} else {
+				requestID = id.String()
+			}
+		}
+		w.Header().Set("X-Request-ID", requestID)
+
+		ctx, cancel := context.WithTimeout(req.Context(), we.requestTimeout)
+		defer cancel()
+		ctx = utils.WithRequestID(ctx, requestID)
+
+		next.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
+
 func (we Adapter) wrapFunc(handler http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		utils.LogRequest(req)
@@ -308,11 +396,31 @@ func (we Adapter) authWrapFunc(handler apiKeysAuthFunc) http.HandlerFunc {
 		if !authenticated {
 			return
 		}
+		if !we.appVersionStatusCheck(w, appVersion) {
+			return
+		}
 
 		handler(appVersion, w, req)
 	}
 }
 
+//appVersionStatusCheck rejects the blocked app versions with 426 Upgrade Required and marks the responses for the deprecated ones
+func (we Adapter) appVersionStatusCheck(w http.ResponseWriter, appVersion *string) bool {
+	if appVersion == nil {
+		return true
+	}
+
+	switch we.app.Services.GetAppVersionStatus(*appVersion) {
+	case "blocked":
+		log.Printf("426 - App version %s is blocked", *appVersion)
+		http.Error(w, "app version is blocked, upgrade is required", http.StatusUpgradeRequired)
+		return false
+	case "deprecated":
+		w.Header().Set("X-App-Version-Status", "deprecated")
+	}
+	return true
+}
+
 type adminAuthFunc = func(model.User, string, http.ResponseWriter, *http.Request)
 
 func (we Adapter) adminAppIDTokenAuthWrapFunc(handler adminAuthFunc) http.HandlerFunc {
@@ -327,7 +435,7 @@ func (we Adapter) adminAppIDTokenAuthWrapFunc(handler adminAuthFunc) http.Handle
 		}
 		if user == nil {
 			//it is valid but the user does not exist, so create it first
-			user, err = we.auth.createAdminAppUser(shibboAuth)
+			user, err = we.auth.createAdminAppUser(req.Context(), shibboAuth)
 			if err != nil {
 				log.Printf("Error on creating admin app user - %s", err.Error())
 				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -361,6 +469,9 @@ func (we Adapter) userAuthWrapFunc(handler authFunc) http.HandlerFunc {
 		if !ok {
 			return
 		}
+		if appVersion, found := mux.Vars(req)["app-version"]; found && !we.appVersionStatusCheck(w, &appVersion) {
+			return
+		}
 		if user == nil {
 			//it is valid but the user is not logged in - return 200/null
 			log.Println("200 - Not logged in")
@@ -443,7 +554,7 @@ func (we Adapter) loginUser(w http.ResponseWriter, r *http.Request) {
 		if authType != nil && *authType == "shibboleth" {
 			rePostValue = true
 		}
-		err = we.auth.createAppUser(*externalID, uuid, publicKey, *consent, *exposureNotification, rePostValue, encryptedKey, encryptedBlob)
+		err = we.auth.createAppUser(r.Context(), *externalID, uuid, publicKey, *consent, *exposureNotification, rePostValue, encryptedKey, encryptedBlob)
 		if err != nil {
 			log.Println("Error on creating user")
 			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -456,7 +567,7 @@ func (we Adapter) loginUser(w http.ResponseWriter, r *http.Request) {
 	} else {
 		//we need to update
 
-		err = we.auth.updateAppUser(*user, uuid, publicKey, *consent, *exposureNotification, rePost, encryptedKey, encryptedBlob)
+		err = we.auth.updateAppUser(r.Context(), *user, uuid, publicKey, *consent, *exposureNotification, rePost, encryptedKey, encryptedBlob)
 		if err != nil {
 			log.Println("Error on updating user")
 			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -493,7 +604,7 @@ func (we Adapter) getUser(w http.ResponseWriter, r *http.Request) {
 
 	}
 
-	response := rest.AppUserResponse{UUID: user.UUID, PublicKey: user.PublicKey,
+	response := rest.AppUserResponse{UUID: user.UUID, PublicKey: user.PublicKey, KeyVersion: user.KeyVersion,
 		Consent: user.Consent, ExposureNotification: user.ExposureNotification, RePost: user.RePost,
 		EncryptedKey: user.EncryptedKey, EncryptedBlob: user.EncryptedBlob}
 	data, err := json.Marshal(response)
@@ -522,14 +633,21 @@ func (we Adapter) providerAuthWrapFunc(handler http.HandlerFunc) http.HandlerFun
 }
 
 //NewWebAdapter creates new WebAdapter instance
-func NewWebAdapter(host string, app *core.Application, appKeys []string, oidcProvider string,
+func NewWebAdapter(host string, requestTimeout string, app *core.Application, appKeys []string, oidcProvider string,
 	oidcAppClientID string, adminAppClientID string, adminWebAppClientID string, phoneAuthSecret string, providersKeys []string) Adapter {
+	timeout, err := strconv.Atoi(requestTimeout)
+	if err != nil {
+		log.Println("Set default request timeout - 30")
+		timeout = 30
+	}
+	timeoutS := time.Second * time.Duration(timeout)
+
 	auth := NewAuth(app, appKeys, oidcProvider, oidcAppClientID, adminAppClientID, adminWebAppClientID, phoneAuthSecret, providersKeys)
 	authorization := casbin.NewEnforcer("driver/web/authorization_model.conf", "driver/web/authorization_policy.csv")
 
 	apisHandler := rest.NewApisHandler(app)
 	adminApisHandler := rest.NewAdminApisHandler(app)
-	return Adapter{host: host, auth: auth, authorization: authorization, apisHandler: apisHandler, adminApisHandler: adminApisHandler, app: app}
+	return Adapter{host: host, requestTimeout: timeoutS, auth: auth, authorization: authorization, apisHandler: apisHandler, adminApisHandler: adminApisHandler, app: app}
 }
 
 //AppListener implements core.ApplicationListener interface
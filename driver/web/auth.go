@@ -64,8 +64,8 @@ func (auth *Auth) adminCheck(w http.ResponseWriter, r *http.Request) (bool, *mod
 	return auth.adminAuth.check(w, r)
 }
 
-func (auth *Auth) createAdminAppUser(shibboAuth *model.ShibbolethAuth) (*model.User, error) {
-	return auth.adminAuth.createAdminAppUser(shibboAuth)
+func (auth *Auth) createAdminAppUser(ctx context.Context, shibboAuth *model.ShibbolethAuth) (*model.User, error) {
+	return auth.adminAuth.createAdminAppUser(ctx, shibboAuth)
 }
 
 func (auth *Auth) providersCheck(w http.ResponseWriter, r *http.Request) bool {
@@ -76,12 +76,12 @@ func (auth *Auth) userCheck(w http.ResponseWriter, r *http.Request) (bool, *mode
 	return auth.userAuth.check(w, r)
 }
 
-func (auth *Auth) updateAppUser(user model.User, uuid string, publicKey string, consent bool, exposureNotification bool, rePost *bool, encryptedKey *string, encryptedBlob *string) error {
-	return auth.userAuth.updateAppUser(user, uuid, publicKey, consent, exposureNotification, rePost, encryptedKey, encryptedBlob)
+func (auth *Auth) updateAppUser(ctx context.Context, user model.User, uuid string, publicKey string, consent bool, exposureNotification bool, rePost *bool, encryptedKey *string, encryptedBlob *string) error {
+	return auth.userAuth.updateAppUser(ctx, user, uuid, publicKey, consent, exposureNotification, rePost, encryptedKey, encryptedBlob)
 }
 
-func (auth *Auth) createAppUser(externalID string, uuid string, publicKey string, consent bool, exposureNotification bool, rePost bool, encryptedKey *string, encryptedBlob *string) error {
-	return auth.userAuth.createAppUser(externalID, uuid, publicKey, consent, exposureNotification, rePost, encryptedKey, encryptedBlob)
+func (auth *Auth) createAppUser(ctx context.Context, externalID string, uuid string, publicKey string, consent bool, exposureNotification bool, rePost bool, encryptedKey *string, encryptedBlob *string) error {
+	return auth.userAuth.createAppUser(ctx, externalID, uuid, publicKey, consent, exposureNotification, rePost, encryptedKey, encryptedBlob)
 }
 
 //NewAuth creates new auth handler
@@ -262,7 +262,7 @@ func (auth *AdminAuth) check(w http.ResponseWriter, r *http.Request) (bool, *mod
 	}
 
 	//5. Get the user for the provided external id.
-	user, err := auth.getUser(*userData.UIuceduUIN)
+	user, err := auth.getUser(r.Context(), *userData.UIuceduUIN)
 	if err != nil {
 		log.Printf("error getting an user for external id - %s\n", err)
 
@@ -279,7 +279,7 @@ func (auth *AdminAuth) check(w http.ResponseWriter, r *http.Request) (bool, *mod
 		return true, nil, "", shibboAuth
 	}
 	//we have a such user, check if need to update the shibbo data before to return it
-	user, err = auth.updateShiboDataIfNeeded(*user, userData)
+	user, err = auth.updateShiboDataIfNeeded(r.Context(), *user, userData)
 	if err != nil {
 		log.Printf("error updating an user for external id - %s\n", err)
 
@@ -340,8 +340,8 @@ func (auth *AdminAuth) verify(rawIDToken string, tokenType string) (*oidc.IDToke
 	}
 }
 
-func (auth *AdminAuth) createAdminAppUser(shibboAuth *model.ShibbolethAuth) (*model.User, error) {
-	user, err := auth.app.CreateAdminAppUser(shibboAuth)
+func (auth *AdminAuth) createAdminAppUser(ctx context.Context, shibboAuth *model.ShibbolethAuth) (*model.User, error) {
+	user, err := auth.app.CreateAdminAppUser(ctx, shibboAuth)
 	if err != nil {
 		return nil, err
 	}
@@ -349,7 +349,7 @@ func (auth *AdminAuth) createAdminAppUser(shibboAuth *model.ShibbolethAuth) (*mo
 	return user, nil
 }
 
-func (auth *AdminAuth) updateShiboDataIfNeeded(current model.User, userData userData) (*model.User, error) {
+func (auth *AdminAuth) updateShiboDataIfNeeded(ctx context.Context, current model.User, userData userData) (*model.User, error) {
 	currentList := current.ShibbolethAuth.IsMemberOf
 	newList := userData.UIuceduIsMemberOf
 
@@ -362,7 +362,7 @@ func (auth *AdminAuth) updateShiboDataIfNeeded(current model.User, userData user
 
 		//2. update it
 		current.ShibbolethAuth.IsMemberOf = userData.UIuceduIsMemberOf
-		err := auth.app.UpdateUser(&current)
+		err := auth.app.UpdateUser(ctx, &current)
 		if err != nil {
 			return nil, err
 		}
@@ -408,7 +408,7 @@ func (auth *AdminAuth) deleteCacheUser(externalID string) {
 	auth.cachedUsersLock.RUnlock()
 }
 
-func (auth *AdminAuth) getUser(uiUceduUIN string) (*model.User, error) {
+func (auth *AdminAuth) getUser(ctx context.Context, uiUceduUIN string) (*model.User, error) {
 	var err error
 
 	//1. First check if cached
@@ -418,7 +418,7 @@ func (auth *AdminAuth) getUser(uiUceduUIN string) (*model.User, error) {
 	}
 
 	//2. Check if we have a such user in the application
-	user, err := auth.app.FindUserByShibbolethID(uiUceduUIN)
+	user, err := auth.app.FindUserByShibbolethID(ctx, uiUceduUIN)
 	if err != nil {
 		log.Printf("error finding an for external id - %s\n", err)
 		return nil, err
@@ -644,7 +644,7 @@ func (auth *UserAuth) check(w http.ResponseWriter, r *http.Request) (bool, *mode
 	}
 
 	// get the user for the provided external id.
-	user, err := auth.getUser(externalID)
+	user, err := auth.getUser(r.Context(), externalID)
 	if err != nil {
 		log.Printf("error getting an user for external id - %s\n", err)
 
@@ -716,10 +716,10 @@ func (auth *UserAuth) getTokenType(token string) (*int, error) {
 	return nil, errors.New("not supported token type")
 }
 
-func (auth *UserAuth) createAppUser(externalID string, uuid string, publicKey string,
+func (auth *UserAuth) createAppUser(ctx context.Context, externalID string, uuid string, publicKey string,
 	consent bool, exposureNotification bool, rePost bool, encryptedKey *string, encryptedBlob *string) error {
 
-	_, err := auth.app.CreateAppUser(externalID, uuid, publicKey, consent, exposureNotification, rePost, encryptedKey, encryptedBlob)
+	_, err := auth.app.CreateAppUser(ctx, externalID, uuid, publicKey, consent, exposureNotification, rePost, encryptedKey, encryptedBlob)
 	if err != nil {
 		return err
 	}
@@ -727,7 +727,7 @@ func (auth *UserAuth) createAppUser(externalID string, uuid string, publicKey st
 	return nil
 }
 
-func (auth *UserAuth) updateAppUser(user model.User, uuid string, publicKey string, consent bool, exposureNotification bool, rePost *bool,
+func (auth *UserAuth) updateAppUser(ctx context.Context, user model.User, uuid string, publicKey string, consent bool, exposureNotification bool, rePost *bool,
 	encryptedKey *string, encryptedBlob *string) error {
 
 	//1. remove it from the cache
@@ -745,7 +745,7 @@ func (auth *UserAuth) updateAppUser(user model.User, uuid string, publicKey stri
 	user.EncryptedBlob = encryptedBlob
 
 	//3. Update the user
-	err := auth.app.UpdateUser(&user)
+	err := auth.app.UpdateUser(ctx, &user)
 	if err != nil {
 		return err
 	}
@@ -790,7 +790,7 @@ func (auth *UserAuth) deleteCacheUser(externalID string) {
 	auth.cachedUsersLock.RUnlock()
 }
 
-func (auth *UserAuth) getUser(externalID string) (*model.User, error) {
+func (auth *UserAuth) getUser(ctx context.Context, externalID string) (*model.User, error) {
 	var err error
 
 	//1. First check if cached
@@ -800,7 +800,7 @@ func (auth *UserAuth) getUser(externalID string) (*model.User, error) {
 	}
 
 	//2. Check if we have a such user in the application
-	user, err := auth.app.FindUserByExternalID(externalID)
+	user, err := auth.app.FindUserByExternalID(ctx, externalID)
 	if err != nil {
 		log.Printf("error finding an user for external id - %s\n", err)
 		return nil, err
@@ -38,7 +38,7 @@ type AdminApisHandler struct {
 
 //GetCovid19Config gets the covid19 config
 func (h AdminApisHandler) GetCovid19Config(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	config, err := h.app.Administration.GetCovid19Config()
+	config, err := h.app.Administration.GetCovid19Config(r.Context())
 	if err != nil {
 		log.Printf("Error on getting covid19 config - %s\n", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -70,7 +70,7 @@ func (h AdminApisHandler) UpdateCovid19Config(current model.User, group string,
 		return
 	}
 
-	err = h.app.Administration.UpdateCovid19Config(&config)
+	err = h.app.Administration.UpdateCovid19Config(r.Context(), &config)
 	if err != nil {
 		log.Printf("Error on updating covid19 config - %s\n", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -92,7 +92,7 @@ func (h AdminApisHandler) UpdateCovid19Config(current model.User, group string,
 // @Security AdminGroupAuth
 // @Router /admin/app-versions [get]
 func (h AdminApisHandler) GetAppVersions(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	appVersions, err := h.app.Administration.GetAppVersions()
+	appVersions, err := h.app.Administration.GetAppVersions(r.Context())
 	if err != nil {
 		log.Println("Error on getting the app versions")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -116,7 +116,7 @@ type createAppVersionRequest struct {
 } //@name createAppVersionRequest
 
 //CreateAppVersion creates an app version
-// @Description Creates an app version. The supported version format is x.x.x or x.x which is the short for x.x.0
+// @Description Creates an app version. The supported version format is semver - x.x.x, x.x which is the short for x.x.0 or x.x.x-pre.release+build
 // @Tags Admin
 // @ID CreateAppVersion
 // @Accept json
@@ -154,7 +154,7 @@ func (h AdminApisHandler) CreateAppVersion(current model.User, group string, w h
 	audit := requestData.Audit
 	version := requestData.Version
 
-	err = h.app.Administration.CreateAppVersion(current, group, audit, version)
+	err = h.app.Administration.CreateAppVersion(r.Context(), current, group, audit, version)
 	if err != nil {
 		log.Printf("Error on creating app version - %s\n", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -166,6 +166,88 @@ func (h AdminApisHandler) CreateAppVersion(current model.User, group string, w h
 	w.Write([]byte("Successfully created"))
 }
 
+//GetAppVersionsStatuses gives the app versions with their lifecycle statuses
+// @Description Gives the app versions with their lifecycle statuses - supported, deprecated or blocked
+// @Tags Admin
+// @ID GetAppVersionsStatuses
+// @Accept  json
+// @Success 200 {array} model.AppVersion
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/app-versions/statuses [get]
+func (h AdminApisHandler) GetAppVersionsStatuses(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	appVersions, err := h.app.Administration.GetAppVersionsStatuses(r.Context())
+	if err != nil {
+		log.Printf("Error on getting the app versions statuses - %s\n", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	data, err := json.Marshal(appVersions)
+	if err != nil {
+		log.Println("Error on marshal the app versions statuses")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+type updateAppVersionStatusRequest struct {
+	Audit   *string `json:"audit"`
+	Version string  `json:"version" validate:"required"`
+	Status  string  `json:"status" validate:"required,oneof=supported deprecated blocked"`
+} //@name updateAppVersionStatusRequest
+
+//UpdateAppVersionStatus updates the lifecycle status of an app version
+// @Description Updates the lifecycle status of an app version. The deprecated versions receive the X-App-Version-Status header, the blocked versions receive 426 Upgrade Required.
+// @Tags Admin
+// @ID UpdateAppVersionStatus
+// @Accept json
+// @Produce json
+// @Param data body updateAppVersionStatusRequest true "body data"
+// @Success 200 {object} string
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/app-versions/status [put]
+func (h AdminApisHandler) UpdateAppVersionStatus(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal update app version status - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var requestData updateAppVersionStatusRequest
+	err = json.Unmarshal(data, &requestData)
+	if err != nil {
+		log.Printf("Error on unmarshal the update app version status data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating update app version status data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.app.Administration.UpdateAppVersionStatus(r.Context(), current, group, requestData.Audit, requestData.Version, requestData.Status)
+	if err != nil {
+		log.Printf("Error on updating app version status - %s\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Successfully updated"))
+}
+
 //GetNews gets news
 // @Description Gives news.
 // @Tags Admin
@@ -179,7 +261,7 @@ func (h AdminApisHandler) CreateAppVersion(current model.User, group string, w h
 // @Security AdminGroupAuth
 // @Router /admin/news [get]
 func (h AdminApisHandler) GetNews(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	newsItems, err := h.app.Administration.GetNews()
+	newsItems, err := h.app.Administration.GetNews(r.Context())
 	if err != nil {
 		log.Println("Error on getting the news items")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -203,10 +285,17 @@ type createNews struct {
 	Title       string    `json:"title"`
 	Description string    `json:"description"`
 	HTMLContent string    `json:"htmlContent"`
+
+	Status    string     `json:"status" validate:"omitempty,oneof=draft published"`
+	PublishAt *time.Time `json:"publishAt"`
+	ExpireAt  *time.Time `json:"expireAt"`
+	Pinned    bool       `json:"pinned"`
+
+	Categories []string `json:"categories"`
 } // @name createNewsRequest
 
 //CreateNews creates a news
-// @Description Creates news
+// @Description Creates news. It is published right away if the status and the publish time are not provided.
 // @Tags Admin
 // @ID CreateNews
 // @Accept json
@@ -231,6 +320,15 @@ func (h AdminApisHandler) CreateNews(current model.User, group string, w http.Re
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating create news data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	audit := requestData.Audit
 	date := requestData.Date
 	title := requestData.Title
@@ -249,10 +347,11 @@ func (h AdminApisHandler) CreateNews(current model.User, group string, w http.Re
 		return
 	}
 
-	news, err := h.app.Administration.CreateNews(current, group, audit, date, title, description, htmlContent, nil)
+	news, err := h.app.Administration.CreateNews(r.Context(), current, group, audit, date, title, description, htmlContent, nil,
+		requestData.Status, requestData.PublishAt, requestData.ExpireAt, requestData.Pinned, requestData.Categories)
 	if err != nil {
-		log.Println("Error on creating a new")
-		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		log.Printf("Error on creating a new - %s\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 	data, err = json.Marshal(news)
@@ -273,10 +372,17 @@ type updateNews struct {
 	Title       string    `json:"title"`
 	Description string    `json:"description"`
 	HTMLContent string    `json:"htmlContent"`
+
+	Status    string     `json:"status" validate:"omitempty,oneof=draft published"`
+	PublishAt *time.Time `json:"publishAt"`
+	ExpireAt  *time.Time `json:"expireAt"`
+	Pinned    bool       `json:"pinned"`
+
+	Categories []string `json:"categories"`
 } // @name updateNewsRequest
 
 //UpdateNews updates news
-// @Description Updates news.
+// @Description Updates news. The status, the publish and the expire times and pinned are replaced as the other fields.
 // @Tags Admin
 // @ID UpdateNews
 // @Accept json
@@ -310,12 +416,22 @@ func (h AdminApisHandler) UpdateNews(current model.User, group string, w http.Re
 		return
 	}
 
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating update news item data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	audit := requestData.Audit
-	news, err := h.app.Administration.UpdateNews(current, group, audit, ID, requestData.Date, requestData.Title,
-		requestData.Description, requestData.HTMLContent, nil)
+	news, err := h.app.Administration.UpdateNews(r.Context(), current, group, audit, ID, requestData.Date, requestData.Title,
+		requestData.Description, requestData.HTMLContent, nil, requestData.Status, requestData.PublishAt, requestData.ExpireAt, requestData.Pinned,
+		requestData.Categories)
 	if err != nil {
-		log.Println("Error on updating the news item")
-		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		log.Printf("Error on updating the news item - %s\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 	data, err = json.Marshal(news)
@@ -348,7 +464,7 @@ func (h AdminApisHandler) DeleteNews(current model.User, group string, w http.Re
 		http.Error(w, "News item id is required", http.StatusBadRequest)
 		return
 	}
-	err := h.app.Administration.DeleteNews(current, group, ID)
+	err := h.app.Administration.DeleteNews(r.Context(), current, group, ID)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -359,6 +475,197 @@ func (h AdminApisHandler) DeleteNews(current model.User, group string, w http.Re
 	w.Write([]byte("Successfully deleted news item"))
 }
 
+//GetNewsSources gives the news sources
+// @Description Gives the news feeds the news are imported from with their last fetch status and error.
+// @Tags Admin
+// @ID GetNewsSources
+// @Accept  json
+// @Success 200 {array} model.NewsSource
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/news-sources [get]
+func (h AdminApisHandler) GetNewsSources(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	sources, err := h.app.Administration.GetNewsSources(r.Context())
+	if err != nil {
+		log.Printf("Error on getting the news sources - %s\n", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	data, err := json.Marshal(sources)
+	if err != nil {
+		log.Println("Error on marshal the news sources")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+type newsSourceRequest struct {
+	Audit        *string `json:"audit"`
+	Name         string  `json:"name" validate:"required"`
+	URL          string  `json:"url" validate:"required,url"`
+	Format       string  `json:"format" validate:"required,oneof=rss atom json"`
+	PollInterval int     `json:"poll_interval" validate:"min=0"`
+	Enabled      bool    `json:"enabled"`
+} // @name newsSourceRequest
+
+//CreateNewsSource creates a news source
+// @Description Creates a news source. The poll interval is in minutes, the news update period from the config is used if it is 0.
+// @Tags Admin
+// @ID CreateNewsSource
+// @Accept json
+// @Produce json
+// @Param data body newsSourceRequest true "body data"
+// @Success 200 {object} model.NewsSource
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/news-sources [post]
+func (h AdminApisHandler) CreateNewsSource(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	requestData, ok := readNewsSourceRequest(w, r)
+	if !ok {
+		return
+	}
+
+	source, err := h.app.Administration.CreateNewsSource(r.Context(), current, group, requestData.Audit, requestData.Name, requestData.URL,
+		requestData.Format, requestData.PollInterval, requestData.Enabled)
+	if err != nil {
+		log.Printf("Error on creating a news source - %s\n", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	writeNewsSourceResponse(w, source)
+}
+
+//UpdateNewsSource updates a news source
+// @Description Updates a news source. The disabled sources are not fetched, their news are kept.
+// @Tags Admin
+// @ID UpdateNewsSource
+// @Accept json
+// @Produce json
+// @Param data body newsSourceRequest true "body data"
+// @Param id path string true "ID"
+// @Success 200 {object} model.NewsSource
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/news-sources/{id} [put]
+func (h AdminApisHandler) UpdateNewsSource(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	ID := params["id"]
+	if len(ID) <= 0 {
+		log.Println("News source id is required")
+		http.Error(w, "News source id is required", http.StatusBadRequest)
+		return
+	}
+	requestData, ok := readNewsSourceRequest(w, r)
+	if !ok {
+		return
+	}
+
+	source, err := h.app.Administration.UpdateNewsSource(r.Context(), current, group, requestData.Audit, ID, requestData.Name, requestData.URL,
+		requestData.Format, requestData.PollInterval, requestData.Enabled)
+	if err != nil {
+		log.Printf("Error on updating the news source - %s\n", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	writeNewsSourceResponse(w, source)
+}
+
+//DeleteNewsSource deletes a news source
+// @Description Deletes a news source. The news imported from it are kept.
+// @Tags Admin
+// @ID DeleteNewsSource
+// @Accept plain
+// @Param id path string true "ID"
+// @Success 200 {object} string "Successfully deleted news source"
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/news-sources/{id} [delete]
+func (h AdminApisHandler) DeleteNewsSource(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	ID := params["id"]
+	if len(ID) <= 0 {
+		log.Println("News source id is required")
+		http.Error(w, "News source id is required", http.StatusBadRequest)
+		return
+	}
+	err := h.app.Administration.DeleteNewsSource(r.Context(), current, group, ID)
+	if err != nil {
+		log.Println(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Successfully deleted news source"))
+}
+
+//GetDataProviderMetrics gives the data provider fetch health
+// @Description Gives the fetch health for every url the data provider loads - fetches, failures, retries and the last response. They are since this instance is started.
+// @Tags Admin
+// @ID GetDataProviderMetrics
+// @Accept  json
+// @Success 200 {array} model.ProviderFetchMetrics
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/data-provider/metrics [get]
+func (h AdminApisHandler) GetDataProviderMetrics(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	metrics := h.app.Administration.GetDataProviderMetrics()
+	data, err := json.Marshal(metrics)
+	if err != nil {
+		log.Println("Error on marshal the data provider metrics")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func readNewsSourceRequest(w http.ResponseWriter, r *http.Request) (*newsSourceRequest, bool) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal the news source - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return nil, false
+	}
+
+	var requestData newsSourceRequest
+	err = json.Unmarshal(data, &requestData)
+	if err != nil {
+		log.Printf("Error on unmarshal the news source request data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating the news source data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	return &requestData, true
+}
+
+func writeNewsSourceResponse(w http.ResponseWriter, source *model.NewsSource) {
+	data, err := json.Marshal(source)
+	if err != nil {
+		log.Println("Error on marshal the news source")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
 //GetResources gets the resources
 // @Description Gives the resources.
 // @Tags Admin
@@ -372,7 +679,7 @@ func (h AdminApisHandler) DeleteNews(current model.User, group string, w http.Re
 // @Security AdminGroupAuth
 // @Router /admin/resources [get]
 func (h AdminApisHandler) GetResources(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	resources, err := h.app.Administration.GetResources()
+	resources, err := h.app.Administration.GetResources(r.Context())
 	if err != nil {
 		log.Println("Error on getting the resource items")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -440,7 +747,7 @@ func (h AdminApisHandler) CreateResources(current model.User, group string, w ht
 		return
 	}
 
-	resource, err := h.app.Administration.CreateResource(current, group, audit, title, link, displayOrder)
+	resource, err := h.app.Administration.CreateResource(r.Context(), current, group, audit, title, link, displayOrder)
 	if err != nil {
 		log.Println("Error on creating a resource")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -507,7 +814,7 @@ func (h AdminApisHandler) UpdateResource(current model.User, group string, w htt
 	}
 
 	audit := requestData.Audit
-	resource, err := h.app.Administration.UpdateResource(current, group, audit, ID, requestData.Title, requestData.Link, displayOrder)
+	resource, err := h.app.Administration.UpdateResource(r.Context(), current, group, audit, ID, requestData.Title, requestData.Link, displayOrder)
 	if err != nil {
 		log.Println("Error on updating the resource item")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -543,7 +850,7 @@ func (h AdminApisHandler) DeleteResource(current model.User, group string, w htt
 		http.Error(w, "Resource item id is required", http.StatusBadRequest)
 		return
 	}
-	err := h.app.Administration.DeleteResource(current, group, ID)
+	err := h.app.Administration.DeleteResource(r.Context(), current, group, ID)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -590,7 +897,7 @@ func (h AdminApisHandler) UpdateDisplaOrderResources(current model.User, group s
 		return
 	}
 
-	err = h.app.Administration.UpdateResourceDisplayOrder(IDs)
+	err = h.app.Administration.UpdateResourceDisplayOrder(r.Context(), IDs)
 	if err != nil {
 		log.Println("Error on updating resources display order")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -601,70 +908,233 @@ func (h AdminApisHandler) UpdateDisplaOrderResources(current model.User, group s
 	w.Write([]byte("Successfully updated resource items"))
 }
 
-type createFAQ struct {
-	Audit        *string           `json:"audit"`
-	Section      string            `json:"section"`
-	DisplayOrder int               `json:"display_order"`
-	Question     createFAQQuestion `json:"question"`
-} //@name createFAQRequest
-
-type createFAQQuestion struct {
-	Title        string `json:"title"`
-	Description  string `json:"description"`
-	DisplayOrder int    `json:"display_order"`
-} //@name createFAQQuestionRequest
-
-//GetFAQs gives FAQs list
-// @Description Gives FAQs list
+//GetResourceChanges gets the upstream resource changes
+// @Description Gives the upstream resource changes, the latest are first. The pending ones wait for a review before they go live.
 // @Tags Admin
-// @ID getFAQs
+// @ID getResourceChanges
 // @Accept  json
-// @Success 200 {array} model.FAQ
-// @Failure 400 {object} string "Authentication error"
-// @Failure 404 {object} string "Not Found"
+// @Param status query string false "pending, approved or rejected"
+// @Success 200 {array} model.ResourceChange
 // @Failure 500 {object} string "Internal Server error"
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
-// @Router /admin/faq [get]
-func (h AdminApisHandler) GetFAQs(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	faq, err := h.app.Administration.GetFAQs()
+// @Router /admin/resource-changes [get]
+func (h AdminApisHandler) GetResourceChanges(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	//status
+	var status *string
+	statusKeys, ok := r.URL.Query()["status"]
+	if ok && len(statusKeys[0]) > 0 {
+		status = &statusKeys[0]
+	}
+
+	changes, err := h.app.Administration.GetResourceChanges(r.Context(), status)
 	if err != nil {
-		log.Println("Error on getting the faqs items")
+		log.Printf("Error on getting the resource changes - %s\n", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
+	writeResourceChangesResponse(w, changes)
+}
 
-	//sort
-	faq.Sort()
-
-	data, err := json.Marshal(faq.Sections)
+//SyncResources syncs the resources with the upstream site
+// @Description Reads the upstream site and queues the changes for a review. It gives the pending changes.
+// @Tags Admin
+// @ID syncResources
+// @Accept  json
+// @Success 200 {array} model.ResourceChange
+// @Failure 500 {object} string "Internal Server error"
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/resources/sync [post]
+func (h AdminApisHandler) SyncResources(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	changes, err := h.app.Administration.SyncResources(r.Context())
 	if err != nil {
-		log.Println("Error on marshal the faqs items")
-		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		log.Printf("Error on syncing the resources - %s\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	w.WriteHeader(http.StatusOK)
-	w.Write(data)
+	writeResourceChangesResponse(w, changes)
 }
 
-//CreateFAQItem creates a faq item
-// @Description Creates a faq item
+type reviewResourceChangeRequest struct {
+	Audit *string `json:"audit"`
+} // @name reviewResourceChangeRequest
+
+//ApproveResourceChange approves a resource change
+// @Description Applies a pending resource change. The icon and the display order of the resource are kept, the removed resources are marked as stale.
 // @Tags Admin
-// @ID createFAQItem
+// @ID approveResourceChange
 // @Accept json
 // @Produce json
-// @Param data body createFAQ true "body data"
-// @Success 200 {string} Successfully created
+// @Param data body reviewResourceChangeRequest false "body data"
+// @Param id path string true "ID"
+// @Success 200 {object} model.ResourceChange
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
-// @Router /admin/faq [post]
-func (h AdminApisHandler) CreateFAQItem(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	data, err := ioutil.ReadAll(r.Body)
-	if err != nil {
-		log.Printf("Error on marshal create a faq item - %s\n", err.Error())
-		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+// @Router /admin/resource-changes/{id}/approve [put]
+func (h AdminApisHandler) ApproveResourceChange(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	ID, requestData, ok := readReviewResourceChangeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	change, err := h.app.Administration.ApproveResourceChange(r.Context(), current, group, requestData.Audit, ID)
+	if err != nil {
+		log.Printf("Error on approving the resource change - %s\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeResourceChangeResponse(w, change)
+}
+
+//RejectResourceChange rejects a resource change
+// @Description Rejects a pending resource change. The same change is not queued again.
+// @Tags Admin
+// @ID rejectResourceChange
+// @Accept json
+// @Produce json
+// @Param data body reviewResourceChangeRequest false "body data"
+// @Param id path string true "ID"
+// @Success 200 {object} model.ResourceChange
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/resource-changes/{id}/reject [put]
+func (h AdminApisHandler) RejectResourceChange(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	ID, requestData, ok := readReviewResourceChangeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	change, err := h.app.Administration.RejectResourceChange(r.Context(), current, group, requestData.Audit, ID)
+	if err != nil {
+		log.Printf("Error on rejecting the resource change - %s\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeResourceChangeResponse(w, change)
+}
+
+func readReviewResourceChangeRequest(w http.ResponseWriter, r *http.Request) (string, reviewResourceChangeRequest, bool) {
+	var requestData reviewResourceChangeRequest
+
+	params := mux.Vars(r)
+	ID := params["id"]
+	if len(ID) <= 0 {
+		log.Println("Resource change id is required")
+		http.Error(w, "Resource change id is required", http.StatusBadRequest)
+		return "", requestData, false
+	}
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on reading the review resource change request - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return "", requestData, false
+	}
+	//the body is optional
+	if len(data) > 0 {
+		err = json.Unmarshal(data, &requestData)
+		if err != nil {
+			log.Printf("Error on unmarshal the review resource change request data - %s\n", err.Error())
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return "", requestData, false
+		}
+	}
+	return ID, requestData, true
+}
+
+func writeResourceChangesResponse(w http.ResponseWriter, changes []*model.ResourceChange) {
+	if changes == nil {
+		changes = []*model.ResourceChange{}
+	}
+	data, err := json.Marshal(changes)
+	if err != nil {
+		log.Println("Error on marshal the resource changes")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func writeResourceChangeResponse(w http.ResponseWriter, change *model.ResourceChange) {
+	data, err := json.Marshal(change)
+	if err != nil {
+		log.Println("Error on marshal the resource change")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+type createFAQ struct {
+	Audit        *string           `json:"audit"`
+	Section      string            `json:"section"`
+	DisplayOrder int               `json:"display_order"`
+	Question     createFAQQuestion `json:"question"`
+} //@name createFAQRequest
+
+type createFAQQuestion struct {
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	DisplayOrder int    `json:"display_order"`
+} //@name createFAQQuestionRequest
+
+//GetFAQs gives FAQs list
+// @Description Gives FAQs list
+// @Tags Admin
+// @ID getFAQs
+// @Accept  json
+// @Success 200 {array} model.FAQ
+// @Failure 400 {object} string "Authentication error"
+// @Failure 404 {object} string "Not Found"
+// @Failure 500 {object} string "Internal Server error"
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/faq [get]
+func (h AdminApisHandler) GetFAQs(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	faq, err := h.app.Administration.GetFAQs(r.Context())
+	if err != nil {
+		log.Println("Error on getting the faqs items")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	//sort
+	faq.Sort()
+
+	data, err := json.Marshal(faq.Sections)
+	if err != nil {
+		log.Println("Error on marshal the faqs items")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//CreateFAQItem creates a faq item
+// @Description Creates a faq item
+// @Tags Admin
+// @ID createFAQItem
+// @Accept json
+// @Produce json
+// @Param data body createFAQ true "body data"
+// @Success 200 {string} Successfully created
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/faq [post]
+func (h AdminApisHandler) CreateFAQItem(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal create a faq item - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		return
 	}
 
@@ -694,7 +1164,7 @@ func (h AdminApisHandler) CreateFAQItem(current model.User, group string, w http
 		return
 	}
 
-	err = h.app.Administration.CreateFAQ(current, group, audit, section, sdo, title, description, qdo)
+	err = h.app.Administration.CreateFAQ(r.Context(), current, group, audit, section, sdo, title, description, qdo)
 	if err != nil {
 		log.Println("Error on creating a faq item")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -757,7 +1227,7 @@ func (h AdminApisHandler) UpdateFAQItem(current model.User, group string, w http
 	}
 
 	audit := requestData.Audit
-	err = h.app.Administration.UpdateFAQ(current, group, audit, ID, requestData.Title, requestData.Description, requestData.DisplayOrder)
+	err = h.app.Administration.UpdateFAQ(r.Context(), current, group, audit, ID, requestData.Title, requestData.Description, requestData.DisplayOrder)
 	if err != nil {
 		log.Println("Error on updating the FAQ item")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -819,7 +1289,7 @@ func (h AdminApisHandler) UpdateFAQSection(current model.User, group string, w h
 		return
 	}
 
-	err = h.app.Administration.UpdateFAQSection(current, group, audit, ID, requestData.Title, requestData.DisplayOrder)
+	err = h.app.Administration.UpdateFAQSection(r.Context(), current, group, audit, ID, requestData.Title, requestData.DisplayOrder)
 	if err != nil {
 		log.Println("Error on updating the FAQ section")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -848,7 +1318,7 @@ func (h AdminApisHandler) DeleteFAQItem(current model.User, group string, w http
 		http.Error(w, "FAQ item id is required", http.StatusBadRequest)
 		return
 	}
-	err := h.app.Administration.DeleteFAQ(current, group, ID)
+	err := h.app.Administration.DeleteFAQ(r.Context(), current, group, ID)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -877,7 +1347,7 @@ func (h AdminApisHandler) DeleteFAQSection(current model.User, group string, w h
 		http.Error(w, "FAQ section id is required", http.StatusBadRequest)
 		return
 	}
-	err := h.app.Administration.DeleteFAQSection(current, group, ID)
+	err := h.app.Administration.DeleteFAQSection(r.Context(), current, group, ID)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -902,7 +1372,7 @@ func (h AdminApisHandler) DeleteFAQSection(current model.User, group string, w h
 // @Security AdminGroupAuth
 // @Router /admin/providers [get]
 func (h AdminApisHandler) GetProviders(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	providers, err := h.app.Administration.GetProviders()
+	providers, err := h.app.Administration.GetProviders(r.Context())
 	if err != nil {
 		log.Println("Error on getting the providers items")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -980,7 +1450,7 @@ func (h AdminApisHandler) CreateProvider(current model.User, group string, w htt
 	manualTest := requestData.ManualTest
 	mechanisms := requestData.AvailableMechanisms
 
-	provider, err := h.app.Administration.CreateProvider(current, group, audit, providerName, *manualTest, mechanisms)
+	provider, err := h.app.Administration.CreateProvider(r.Context(), current, group, audit, providerName, *manualTest, mechanisms)
 	if err != nil {
 		log.Println("Error on creating a provider")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -1058,7 +1528,7 @@ func (h AdminApisHandler) UpdateProvider(current model.User, group string, w htt
 	}
 
 	audit := requestData.Audit
-	provider, err := h.app.Administration.UpdateProvider(current, group, audit, ID, requestData.ProviderName, *requestData.ManualTest, requestData.AvailableMechanisms)
+	provider, err := h.app.Administration.UpdateProvider(r.Context(), current, group, audit, ID, requestData.ProviderName, *requestData.ManualTest, requestData.AvailableMechanisms)
 	if err != nil {
 		log.Println("Error on updating the provider item")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -1095,7 +1565,7 @@ func (h AdminApisHandler) DeleteProvider(current model.User, group string, w htt
 		http.Error(w, "Provider id is required", http.StatusBadRequest)
 		return
 	}
-	err := h.app.Administration.DeleteProvider(current, group, ID)
+	err := h.app.Administration.DeleteProvider(r.Context(), current, group, ID)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -1161,7 +1631,7 @@ func (h AdminApisHandler) CreateCounty(current model.User, group string, w http.
 	stateProvince := requestData.StateProvince
 	country := requestData.Country
 
-	county, err := h.app.Administration.CreateCounty(current, group, audit, name, stateProvince, country)
+	county, err := h.app.Administration.CreateCounty(r.Context(), current, group, audit, name, stateProvince, country)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -1241,7 +1711,7 @@ func (h AdminApisHandler) UpdateCounty(current model.User, group string, w http.
 	}
 
 	audit := requestData.Audit
-	county, err := h.app.Administration.UpdateCounty(current, group, audit, ID, requestData.Name,
+	county, err := h.app.Administration.UpdateCounty(r.Context(), current, group, audit, ID, requestData.Name,
 		requestData.StateProvince, requestData.Country)
 	if err != nil {
 		log.Println(err.Error())
@@ -1280,7 +1750,7 @@ func (h AdminApisHandler) DeleteCounty(current model.User, group string, w http.
 		http.Error(w, "County id is required", http.StatusBadRequest)
 		return
 	}
-	err := h.app.Administration.DeleteCounty(current, group, ID)
+	err := h.app.Administration.DeleteCounty(r.Context(), current, group, ID)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -1312,7 +1782,7 @@ type getCountiesResponse struct {
 // @Security AdminGroupAuth
 // @Router /admin/counties [get]
 func (h AdminApisHandler) GetCounties(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	counties, err := h.app.Administration.FindCounties(nil)
+	counties, err := h.app.Administration.FindCounties(r.Context(), nil)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -1414,7 +1884,7 @@ func (h AdminApisHandler) CreateGuideline(current model.User, group string, w ht
 		items = append(items, r)
 	}
 
-	guideline, err := h.app.Administration.CreateGuideline(current, group, audit, countyID, name, description, items)
+	guideline, err := h.app.Administration.CreateGuideline(r.Context(), current, group, audit, countyID, name, description, items)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -1522,7 +1992,7 @@ func (h AdminApisHandler) UpdateGuideline(current model.User, group string, w ht
 		items = append(items, r)
 	}
 
-	guideline, err := h.app.Administration.UpdateGuideline(current, group, audit, ID, name, description, items)
+	guideline, err := h.app.Administration.UpdateGuideline(r.Context(), current, group, audit, ID, name, description, items)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -1565,7 +2035,7 @@ func (h AdminApisHandler) DeleteGuideline(current model.User, group string, w ht
 		http.Error(w, "Guideline id is required", http.StatusBadRequest)
 		return
 	}
-	err := h.app.Administration.DeleteGuideline(current, group, ID)
+	err := h.app.Administration.DeleteGuideline(r.Context(), current, group, ID)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -1598,7 +2068,7 @@ func (h AdminApisHandler) GetGuidelinesByCountyID(current model.User, group stri
 	}
 	countyID := keys[0]
 
-	guidelines, err := h.app.Administration.GetGuidelinesByCountyID(countyID)
+	guidelines, err := h.app.Administration.GetGuidelinesByCountyID(r.Context(), countyID)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -1683,7 +2153,7 @@ func (h AdminApisHandler) CreateCountyStatus(current model.User, group string, w
 	name := requestData.Name
 	description := requestData.Description
 
-	countyStatus, err := h.app.Administration.CreateCountyStatus(current, group, audit, countyID, name, description)
+	countyStatus, err := h.app.Administration.CreateCountyStatus(r.Context(), current, group, audit, countyID, name, description)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -1763,7 +2233,7 @@ func (h AdminApisHandler) UpdateCountyStatus(current model.User, group string, w
 	name := requestData.Name
 	description := requestData.Description
 
-	countyStatus, err := h.app.Administration.UpdateCountyStatus(current, group, audit, ID, name, description)
+	countyStatus, err := h.app.Administration.UpdateCountyStatus(r.Context(), current, group, audit, ID, name, description)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -1801,7 +2271,7 @@ func (h AdminApisHandler) DeleteCountyStatus(current model.User, group string, w
 		http.Error(w, "County status id is required", http.StatusBadRequest)
 		return
 	}
-	err := h.app.Administration.DeleteCountyStatus(current, group, ID)
+	err := h.app.Administration.DeleteCountyStatus(r.Context(), current, group, ID)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -1840,7 +2310,7 @@ func (h AdminApisHandler) GetCountyStatusesByCountyID(current model.User, group
 	}
 	countyID := keys[0]
 
-	countyStatuses, err := h.app.Administration.GetCountyStatusByCountyID(countyID)
+	countyStatuses, err := h.app.Administration.GetCountyStatusByCountyID(r.Context(), countyID)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -1916,7 +2386,7 @@ func (h AdminApisHandler) CreateTestType(current model.User, group string, w htt
 	name := requestData.Name
 	priority := requestData.Priority
 
-	testType, err := h.app.Administration.CreateTestType(current, group, audit, name, priority)
+	testType, err := h.app.Administration.CreateTestType(r.Context(), current, group, audit, name, priority)
 	if err != nil {
 		log.Println("Error on creating a test type")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -1993,7 +2463,7 @@ func (h AdminApisHandler) UpdateTestType(current model.User, group string, w htt
 	}
 
 	audit := requestData.Audit
-	testType, err := h.app.Administration.UpdateTestType(current, group, audit, ID, requestData.Name, requestData.Priority)
+	testType, err := h.app.Administration.UpdateTestType(r.Context(), current, group, audit, ID, requestData.Name, requestData.Priority)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -2030,7 +2500,7 @@ func (h AdminApisHandler) DeleteTestType(current model.User, group string, w htt
 		http.Error(w, "Test type id is required", http.StatusBadRequest)
 		return
 	}
-	err := h.app.Administration.DeleteTestType(current, group, ID)
+	err := h.app.Administration.DeleteTestType(r.Context(), current, group, ID)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -2066,7 +2536,7 @@ func (h AdminApisHandler) GetTestTypes(current model.User, group string, w http.
 		return
 	} */
 
-	testTypes, err := h.app.Administration.GetTestTypes()
+	testTypes, err := h.app.Administration.GetTestTypes(r.Context())
 	if err != nil {
 		log.Println("Error on getting the test types items")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -2142,7 +2612,7 @@ func (h AdminApisHandler) CreateTestTypeResult(current model.User, group string,
 	testTypeID := requestData.TestTypeID
 	name := requestData.Name
 
-	testTypeResult, err := h.app.Administration.CreateTestTypeResult(current, group, audit, testTypeID, name, "", nil, nil)
+	testTypeResult, err := h.app.Administration.CreateTestTypeResult(r.Context(), current, group, audit, testTypeID, name, "", nil, nil)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -2220,7 +2690,7 @@ func (h AdminApisHandler) UpdateTestTypeResult(current model.User, group string,
 	audit := requestData.Audit
 	name := requestData.Name
 
-	testTypeResult, err := h.app.Administration.UpdateTestTypeResult(current, group, audit, ID, name, "", nil, nil)
+	testTypeResult, err := h.app.Administration.UpdateTestTypeResult(r.Context(), current, group, audit, ID, name, "", nil, nil)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -2258,7 +2728,7 @@ func (h AdminApisHandler) DeleteTestTypeResult(current model.User, group string,
 		http.Error(w, "Test type result id is required", http.StatusBadRequest)
 		return
 	}
-	err := h.app.Administration.DeleteTestTypeResult(current, group, ID)
+	err := h.app.Administration.DeleteTestTypeResult(r.Context(), current, group, ID)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -2296,7 +2766,7 @@ func (h AdminApisHandler) GetTestTypeResultsByTestTypeID(current model.User, gro
 	}
 	testTypeID := keys[0]
 
-	testTypeResults, err := h.app.Administration.GetTestTypeResultsByTestTypeID(testTypeID)
+	testTypeResults, err := h.app.Administration.GetTestTypeResultsByTestTypeID(r.Context(), testTypeID)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -2399,7 +2869,7 @@ func (h AdminApisHandler) CreateRule(current model.User, group string, w http.Re
 		}
 	}
 
-	rule, err := h.app.Administration.CreateRule(current, group, audit, countyID, testTypeID, priority, rsItems)
+	rule, err := h.app.Administration.CreateRule(r.Context(), current, group, audit, countyID, testTypeID, priority, rsItems)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -2511,7 +2981,7 @@ func (h AdminApisHandler) UpdateRule(current model.User, group string, w http.Re
 		}
 	}
 
-	rule, err := h.app.Administration.UpdateRule(current, group, audit, ID, priority, rsItems)
+	rule, err := h.app.Administration.UpdateRule(r.Context(), current, group, audit, ID, priority, rsItems)
 	if err != nil {
 		log.Printf("Error on updating the rule item - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -2556,7 +3026,7 @@ func (h AdminApisHandler) DeleteRule(current model.User, group string, w http.Re
 		http.Error(w, "Rule item id is required", http.StatusBadRequest)
 		return
 	}
-	err := h.app.Administration.DeleteRule(current, group, ID)
+	err := h.app.Administration.DeleteRule(r.Context(), current, group, ID)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -2595,7 +3065,7 @@ type getRulesResultsStatusesTypeResponse struct {
 // @Security AdminGroupAuth
 // @Router /admin/rules [get]
 func (h AdminApisHandler) GetRules(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	rules, err := h.app.Administration.GetRules()
+	rules, err := h.app.Administration.GetRules(r.Context())
 	if err != nil {
 		log.Println("Error on getting the rules items")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -2652,13 +3122,22 @@ type createLocationRequest struct {
 } //@name createLocationRequest
 
 type locationOperationDayRequest struct {
-	Name      string `json:"name"`
+	Name      string                             `json:"name"`
+	AllDay    bool                               `json:"all_day"`
+	Intervals []locationOperationIntervalRequest `json:"intervals"`
+
+	//one interval, used only if there are no intervals
 	OpenTime  string `json:"open_time"`
 	CloseTime string `json:"close_time"`
 } //@name locationOperationDayRequest
 
+type locationOperationIntervalRequest struct {
+	OpenTime  string `json:"open_time"`
+	CloseTime string `json:"close_time"`
+} //@name locationOperationIntervalRequest
+
 //CreateLocation creates a location
-// @Description Creates a location.
+// @Description Creates a location. Every day of operation is open all day or has intervals with times like "08:00am", an interval crosses midnight if its close time is before its open time.
 // @Tags Admin
 // @ID CreateLocation
 // @Accept json
@@ -2713,7 +3192,7 @@ func (h AdminApisHandler) CreateLocation(current model.User, group string, w htt
 
 	availableTests := requestData.AvailableTests
 
-	location, err := h.app.Administration.CreateLocation(current, group, audit, providerID, countyID, name, address1, address2, city,
+	location, err := h.app.Administration.CreateLocation(r.Context(), current, group, audit, providerID, countyID, name, address1, address2, city,
 		state, zip, country, latitude, longitude, contact, daysOfOperation, url, notes, waitTimeColor, availableTests)
 	if err != nil {
 		log.Printf("Error on creating a location - %s\n", err.Error())
@@ -2729,7 +3208,7 @@ func (h AdminApisHandler) CreateLocation(current model.User, group string, w htt
 	response := locationResponse{ID: location.ID, Name: location.Name, Address1: location.Address1, Address2: location.Address2,
 		City: location.City, State: location.State, ZIP: location.ZIP, Latitude: location.Latitude, Longitude: location.Longitude,
 		Timezone: location.Timezone, Country: location.Country, Contact: location.Contact, DaysOfOperation: convertFromDaysOfOperations(location.DaysOfOperation),
-		URL: location.URL, Notes: location.Notes, WaitTimeColor: location.WaitTimeColor, ProviderID: location.Provider.ID,
+		Exceptions: convertFromLocationExceptions(location.Exceptions), URL: location.URL, Notes: location.Notes, WaitTimeColor: location.WaitTimeColor, WaitTime: convertFromLocationWaitTime(location.WaitTime), AppointmentSettings: location.AppointmentSettings, ProviderID: location.Provider.ID,
 		CountyID: location.County.ID, AvailableTests: availableTestsRes}
 	data, err = json.Marshal(response)
 	if err != nil {
@@ -2764,7 +3243,7 @@ type updateLocationRequest struct {
 } //@name updateLocationRequest
 
 //UpdateLocation updates a location
-// @Description Updates a location.
+// @Description Updates a location. Every day of operation is open all day or has intervals with times like "08:00am", an interval crosses midnight if its close time is before its open time.
 // @Tags Admin
 // @ID UpdateLocation
 // @Accept json
@@ -2825,7 +3304,7 @@ func (h AdminApisHandler) UpdateLocation(current model.User, group string, w htt
 
 	availableTests := requestData.AvailableTests
 
-	location, err := h.app.Administration.UpdateLocation(current, group, audit, ID, name, address1, address2, city,
+	location, err := h.app.Administration.UpdateLocation(r.Context(), current, group, audit, ID, name, address1, address2, city,
 		state, zip, country, latitude, longitude, contact, daysOfOperation, url, notes, waitTimeColor, availableTests)
 	if err != nil {
 		log.Printf("Error on creating a location - %s\n", err.Error())
@@ -2841,7 +3320,7 @@ func (h AdminApisHandler) UpdateLocation(current model.User, group string, w htt
 	response := locationResponse{ID: location.ID, Name: location.Name, Address1: location.Address1, Address2: location.Address2,
 		City: location.City, State: location.State, ZIP: location.ZIP, Latitude: location.Latitude, Longitude: location.Longitude,
 		Timezone: location.Timezone, Country: location.Country, Contact: location.Contact, DaysOfOperation: convertFromDaysOfOperations(location.DaysOfOperation),
-		URL: location.URL, Notes: location.Notes, WaitTimeColor: location.WaitTimeColor, ProviderID: location.Provider.ID,
+		Exceptions: convertFromLocationExceptions(location.Exceptions), URL: location.URL, Notes: location.Notes, WaitTimeColor: location.WaitTimeColor, WaitTime: convertFromLocationWaitTime(location.WaitTime), AppointmentSettings: location.AppointmentSettings, ProviderID: location.Provider.ID,
 		CountyID: location.County.ID, AvailableTests: availableTestsRes}
 	data, err = json.Marshal(response)
 	if err != nil {
@@ -2868,7 +3347,7 @@ func (h AdminApisHandler) UpdateLocation(current model.User, group string, w htt
 // @Security AdminGroupAuth
 // @Router /admin/locations [get]
 func (h AdminApisHandler) GetLocations(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	locations, err := h.app.Administration.GetLocations()
+	locations, err := h.app.Administration.GetLocations(r.Context())
 	if err != nil {
 		log.Println("Error on getting the lcoations items")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -2886,7 +3365,7 @@ func (h AdminApisHandler) GetLocations(current model.User, group string, w http.
 			loc := locationResponse{ID: location.ID, Name: location.Name, Address1: location.Address1, Address2: location.Address2,
 				City: location.City, State: location.State, ZIP: location.ZIP, Country: location.Country, Latitude: location.Latitude, Longitude: location.Longitude,
 				Timezone: location.Timezone, Contact: location.Contact, DaysOfOperation: convertFromDaysOfOperations(location.DaysOfOperation),
-				URL: location.URL, Notes: location.Notes, WaitTimeColor: location.WaitTimeColor, ProviderID: location.Provider.ID,
+				Exceptions: convertFromLocationExceptions(location.Exceptions), URL: location.URL, Notes: location.Notes, WaitTimeColor: location.WaitTimeColor, WaitTime: convertFromLocationWaitTime(location.WaitTime), AppointmentSettings: location.AppointmentSettings, ProviderID: location.Provider.ID,
 				CountyID: location.County.ID, AvailableTests: availableTestsRes}
 			responseList = append(responseList, loc)
 		}
@@ -2921,7 +3400,7 @@ func (h AdminApisHandler) DeleteLocation(current model.User, group string, w htt
 		http.Error(w, "Location id is required", http.StatusBadRequest)
 		return
 	}
-	err := h.app.Administration.DeleteLocation(current, group, ID)
+	err := h.app.Administration.DeleteLocation(r.Context(), current, group, ID)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -2933,35 +3412,53 @@ func (h AdminApisHandler) DeleteLocation(current model.User, group string, w htt
 	w.Write([]byte("Successfully deleted"))
 }
 
-type createSymptomRequest struct {
-	Name         string `json:"name" validate:"required"`
-	SymptomGroup string `json:"symptom_group" validate:"required,oneof=gr1 gr2"`
-} //@name createSymptomRequest
+type updateLocationExceptionsRequest struct {
+	Audit      *string                    `json:"audit"`
+	Exceptions []locationExceptionRequest `json:"exceptions" validate:"dive"`
+} //@name updateLocationExceptionsRequest
 
-//CreateSymptom creates a symptom
-// @Deprecated
-// @Description Creates a symptom
+type locationExceptionRequest struct {
+	StartDate string `json:"start_date" validate:"required"`
+	EndDate   string `json:"end_date"`
+	Type      string `json:"type" validate:"required,oneof=closed special-hours relocated"`
+	OpenTime  string `json:"open_time"`
+	CloseTime string `json:"close_time"`
+	Address   string `json:"address"`
+	Note      string `json:"note"`
+} //@name locationExceptionRequest
+
+//UpdateLocationExceptions replaces the exceptions of a location
+// @Description Replaces the holidays, special hours and temporary relocations of a location. The dates are in "2006-01-02" format, the end date is inclusive and it is the start date if not provided. The times are in "03:04pm" format.
 // @Tags Admin
-// @ID CreateSymptom
+// @ID UpdateLocationExceptions
 // @Accept json
 // @Produce json
-// @Param data body createSymptomRequest true "body data"
-// @Success 200 {object} symptomResponse
+// @Param data body updateLocationExceptionsRequest true "body data"
+// @Param id path string true "ID"
+// @Success 200 {object} locationResponse
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
-// @Router /admin/symptoms [post]
-func (h AdminApisHandler) CreateSymptom(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+// @Router /admin/locations/{id}/exceptions [put]
+func (h AdminApisHandler) UpdateLocationExceptions(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	ID := params["id"]
+	if len(ID) <= 0 {
+		log.Println("Location id is required")
+		http.Error(w, "Location id is required", http.StatusBadRequest)
+		return
+	}
+
 	data, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		log.Printf("Error on marshal create a symptom - %s\n", err.Error())
+		log.Printf("Error on marshal update location exceptions - %s\n", err.Error())
 		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		return
 	}
 
-	var requestData createSymptomRequest
+	var requestData updateLocationExceptionsRequest
 	err = json.Unmarshal(data, &requestData)
 	if err != nil {
-		log.Printf("Error on unmarshal the create symptom request data - %s\n", err.Error())
+		log.Printf("Error on unmarshal the update location exceptions request data - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -2970,23 +3467,37 @@ func (h AdminApisHandler) CreateSymptom(current model.User, group string, w http
 	validate := validator.New()
 	err = validate.Struct(requestData)
 	if err != nil {
-		log.Printf("Error on validating create symptom data - %s\n", err.Error())
+		log.Printf("Error on validating update location exceptions data - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	name := requestData.Name
-	symptomGroup := requestData.SymptomGroup
 
-	symptom, err := h.app.Administration.CreateSymptom(current, group, name, symptomGroup)
+	exceptions := make([]model.LocationException, len(requestData.Exceptions))
+	for i, item := range requestData.Exceptions {
+		exceptions[i] = model.LocationException{StartDate: item.StartDate, EndDate: item.EndDate, Type: item.Type,
+			OpenTime: item.OpenTime, CloseTime: item.CloseTime, Address: item.Address, Note: item.Note}
+	}
+
+	location, err := h.app.Administration.UpdateLocationExceptions(r.Context(), current, group, requestData.Audit, ID, exceptions)
 	if err != nil {
-		log.Printf("Error on creating a location - %s\n", err.Error())
+		log.Printf("Error on updating the location exceptions - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	response := symptomResponse{ID: symptom.ID, Name: symptom.Name}
+	var availableTestsRes []string
+	if location.AvailableTests != nil {
+		for _, testType := range location.AvailableTests {
+			availableTestsRes = append(availableTestsRes, testType.ID)
+		}
+	}
+	response := locationResponse{ID: location.ID, Name: location.Name, Address1: location.Address1, Address2: location.Address2,
+		City: location.City, State: location.State, ZIP: location.ZIP, Latitude: location.Latitude, Longitude: location.Longitude,
+		Timezone: location.Timezone, Country: location.Country, Contact: location.Contact, DaysOfOperation: convertFromDaysOfOperations(location.DaysOfOperation),
+		Exceptions: convertFromLocationExceptions(location.Exceptions), URL: location.URL, Notes: location.Notes, WaitTimeColor: location.WaitTimeColor, WaitTime: convertFromLocationWaitTime(location.WaitTime), AppointmentSettings: location.AppointmentSettings, ProviderID: location.Provider.ID,
+		CountyID: location.County.ID, AvailableTests: availableTestsRes}
 	data, err = json.Marshal(response)
 	if err != nil {
-		log.Println("Error on marshal a symptom")
+		log.Println("Error on marshal a location")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
@@ -2996,43 +3507,44 @@ func (h AdminApisHandler) CreateSymptom(current model.User, group string, w http
 	w.Write(data)
 }
 
-type updateSymptomRequest struct {
-	Name string `json:"name" validate:"required"`
-} //@name updateSymptomRequest
+type adminReportWaitTimeRequest struct {
+	Audit       *string `json:"audit"`
+	WaitMinutes *int    `json:"wait_minutes" validate:"required,min=0"`
+	QueueLength *int    `json:"queue_length" validate:"omitempty,min=0"`
+} //@name adminReportWaitTimeRequest
 
-//UpdateSymptom updates a symptom
-// @Deprecated
-// @Description Updates a symptom.
+//ReportWaitTime reports the current wait time of a location
+// @Description Reports the current wait time and optionally the queue length of a location on behalf of the staff.
 // @Tags Admin
-// @ID UpdateSymptom
+// @ID AdminReportWaitTime
 // @Accept json
 // @Produce json
-// @Param data body updateSymptomRequest true "body data"
-// @Param id path string true "ID"
-// @Success 200 {object} symptomResponse
+// @Param data body adminReportWaitTimeRequest true "body data"
+// @Param id path string true "Location ID"
+// @Success 200 {object} model.WaitTimeReport
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
-// @Router /admin/symptoms/{id} [put]
-func (h AdminApisHandler) UpdateSymptom(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+// @Router /admin/locations/{id}/wait-time [post]
+func (h AdminApisHandler) ReportWaitTime(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
 	ID := params["id"]
 	if len(ID) <= 0 {
-		log.Println("Symptom id is required")
-		http.Error(w, "Symptom id is required", http.StatusBadRequest)
+		log.Println("Location id is required")
+		http.Error(w, "Location id is required", http.StatusBadRequest)
 		return
 	}
 
 	data, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		log.Printf("Error on marshal update a symptom - %s\n", err.Error())
+		log.Printf("Error on marshal report a wait time - %s\n", err.Error())
 		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		return
 	}
 
-	var requestData updateSymptomRequest
+	var requestData adminReportWaitTimeRequest
 	err = json.Unmarshal(data, &requestData)
 	if err != nil {
-		log.Printf("Error on unmarshal the update symptom request data - %s\n", err.Error())
+		log.Printf("Error on unmarshal the report wait time request data - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -3041,107 +3553,165 @@ func (h AdminApisHandler) UpdateSymptom(current model.User, group string, w http
 	validate := validator.New()
 	err = validate.Struct(requestData)
 	if err != nil {
-		log.Printf("Error on validating update test type result data - %s\n", err.Error())
+		log.Printf("Error on validating report wait time data - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	name := requestData.Name
-	symptom, err := h.app.Administration.UpdateSymptom(current, group, ID, name)
+	report, err := h.app.Administration.ReportWaitTime(r.Context(), current, group, requestData.Audit, ID, *requestData.WaitMinutes, requestData.QueueLength)
 	if err != nil {
-		log.Println(err.Error())
+		log.Printf("Error on reporting a wait time - %s\n", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	result := symptomResponse{ID: symptom.ID, Name: symptom.Name}
-	data, err = json.Marshal(result)
+	data, err = json.Marshal(report)
 	if err != nil {
-		log.Println("Error on marshal a symptom")
+		log.Println("Error on marshal a wait time report")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
-
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	w.Write(data)
 }
 
-//DeleteSymptom deletes a symptom
-// @Deprecated
-// @Description Deletes a symptom
+//GetWaitTimeReports gives the wait time history of a location
+// @Description Gives the latest wait time reports of a location, the newest first.
 // @Tags Admin
-// @ID deleteSymptom
-// @Accept plain
-// @Param id path string true "ID"
-// @Success 200 {object} string "Successfuly deleted"
+// @ID GetWaitTimeReports
+// @Accept json
+// @Produce json
+// @Param id path string true "Location ID"
+// @Param limit query int false "Limit, 100 by default"
+// @Success 200 {array} model.WaitTimeReport
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
-// @Router /admin/symptoms/{id} [delete]
-func (h AdminApisHandler) DeleteSymptom(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+// @Router /admin/locations/{id}/wait-times [get]
+func (h AdminApisHandler) GetWaitTimeReports(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
 	ID := params["id"]
 	if len(ID) <= 0 {
-		log.Println("Symptom id is required")
-		http.Error(w, "Symptom id is required", http.StatusBadRequest)
+		log.Println("Location id is required")
+		http.Error(w, "Location id is required", http.StatusBadRequest)
 		return
 	}
-	err := h.app.Administration.DeleteSymptom(current, group, ID)
+
+	var limit int64 = 100
+	limitParam := r.URL.Query().Get("limit")
+	if len(limitParam) > 0 {
+		value, err := strconv.ParseInt(limitParam, 10, 64)
+		if err != nil || value < 1 {
+			log.Printf("Invalid limit %s\n", limitParam)
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = value
+	}
+
+	reports, err := h.app.Administration.GetWaitTimeReports(r.Context(), ID, limit)
 	if err != nil {
-		log.Println(err.Error())
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Printf("Error on getting the wait time reports - %s\n", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
+	if reports == nil {
+		reports = []*model.WaitTimeReport{}
+	}
 
-	w.Header().Set("Content-Type", "text/plain")
+	data, err := json.Marshal(reports)
+	if err != nil {
+		log.Println("Error on marshal the wait time reports")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("Successfully deleted"))
+	w.Write(data)
 }
 
-type getSymptomGroupsResponse struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+type updateLocationAppointmentSettingsRequest struct {
+	Audit    *string                             `json:"audit"`
+	Settings *locationAppointmentSettingsRequest `json:"settings"` //null if the location does not offer appointments
+} //@name updateLocationAppointmentSettingsRequest
 
-	Symptoms []symptomResponse `json:"symptoms"`
-} // @name SymptomGroup
+type locationAppointmentSettingsRequest struct {
+	SlotMinutes int `json:"slot_minutes" validate:"required,min=5,max=240"`
+	Capacity    int `json:"capacity" validate:"required,min=1,max=1000"`
+	DaysAhead   int `json:"days_ahead" validate:"required,min=1,max=90"`
+} //@name locationAppointmentSettingsRequest
 
-//GetSymptomGroups gets the symptom groups
-// @Deprecated
-// @Description Gives the symptom groups
+//UpdateLocationAppointmentSettings sets the appointment settings of a location
+// @Description Sets how the appointment slots of a location are generated from its days of operation - the slot length in minutes, how many appointments a slot can have and how many days ahead can be booked. The location does not offer appointments if the settings are null, the booked appointments are kept.
 // @Tags Admin
-// @ID getSymptomGroups
-// @Accept  json
-// @Success 200 {array} getSymptomGroupsResponse
-// @Failure 400 {object} string "Authentication error"
-// @Failure 404 {object} string "Not Found"
-// @Failure 500 {object} string "Internal Server error"
+// @ID UpdateLocationAppointmentSettings
+// @Accept json
+// @Produce json
+// @Param data body updateLocationAppointmentSettingsRequest true "body data"
+// @Param id path string true "ID"
+// @Success 200 {object} locationResponse
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
-// @Router /admin/symptom-groups [get]
-func (h AdminApisHandler) GetSymptomGroups(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	symptomGroups, err := h.app.Administration.GetSymptomGroups()
-	if err != nil {
-		log.Println("Error on getting the symptom groups items")
-		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
-		return
-	}
-	var response []getSymptomGroupsResponse
-	if symptomGroups != nil {
-		for _, sg := range symptomGroups {
-			var symptoms []symptomResponse
-			if sg.Symptoms != nil {
-				for _, s := range sg.Symptoms {
-					item := symptomResponse{ID: s.ID, Name: s.Name}
-					symptoms = append(symptoms, item)
-				}
-			}
-			r := getSymptomGroupsResponse{ID: sg.ID, Name: sg.Name, Symptoms: symptoms}
-			response = append(response, r)
+// @Router /admin/locations/{id}/appointment-settings [put]
+func (h AdminApisHandler) UpdateLocationAppointmentSettings(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	ID := params["id"]
+	if len(ID) <= 0 {
+		log.Println("Location id is required")
+		http.Error(w, "Location id is required", http.StatusBadRequest)
+		return
+	}
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal update location appointment settings - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var requestData updateLocationAppointmentSettingsRequest
+	err = json.Unmarshal(data, &requestData)
+	if err != nil {
+		log.Printf("Error on unmarshal the update location appointment settings request data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating update location appointment settings data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var settings *model.AppointmentSettings
+	if requestData.Settings != nil {
+		settings = &model.AppointmentSettings{SlotMinutes: requestData.Settings.SlotMinutes, Capacity: requestData.Settings.Capacity,
+			DaysAhead: requestData.Settings.DaysAhead}
+	}
+
+	location, err := h.app.Administration.UpdateLocationAppointmentSettings(r.Context(), current, group, requestData.Audit, ID, settings)
+	if err != nil {
+		log.Printf("Error on updating the location appointment settings - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var availableTestsRes []string
+	if location.AvailableTests != nil {
+		for _, testType := range location.AvailableTests {
+			availableTestsRes = append(availableTestsRes, testType.ID)
 		}
 	}
-	data, err := json.Marshal(response)
+	response := locationResponse{ID: location.ID, Name: location.Name, Address1: location.Address1, Address2: location.Address2,
+		City: location.City, State: location.State, ZIP: location.ZIP, Latitude: location.Latitude, Longitude: location.Longitude,
+		Timezone: location.Timezone, Country: location.Country, Contact: location.Contact, DaysOfOperation: convertFromDaysOfOperations(location.DaysOfOperation),
+		Exceptions: convertFromLocationExceptions(location.Exceptions), URL: location.URL, Notes: location.Notes, WaitTimeColor: location.WaitTimeColor, WaitTime: convertFromLocationWaitTime(location.WaitTime), AppointmentSettings: location.AppointmentSettings, ProviderID: location.Provider.ID,
+		CountyID: location.County.ID, AvailableTests: availableTestsRes}
+	data, err = json.Marshal(response)
 	if err != nil {
-		log.Println("Error on marshal the symptom groups items")
+		log.Println("Error on marshal a location")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
@@ -3151,63 +3721,78 @@ func (h AdminApisHandler) GetSymptomGroups(current model.User, group string, w h
 	w.Write(data)
 }
 
-type createSymptomRuleRequest struct {
-	CountyID string `json:"county_id" validate:"required,uuid"`
-
-	Gr1Count int `json:"gr1_count" validate:"required"`
-	Gr2Count int `json:"gr2_count" validate:"required"`
-
-	Items []createSymptomRuleItemRequest `json:"items" validate:"required,max=4,min=4,dive"`
-} //@name createSymptomRuleRequest
-
-type createSymptomRuleItemRequest struct {
-	Gr1            *bool  `json:"gr1" validate:"required"`
-	Gr2            *bool  `json:"gr2" validate:"required"`
-	CountyStatusID string `json:"county_status_id" validate:"required"`
-	NextStep       string `json:"next_step" validate:"required"`
-} // @name createSymptomRuleItemRequest
-
-type symptomRuleResponse struct {
-	ID       string `json:"id"`
-	CountyID string `json:"county_id"`
+//GetLocationAppointments gives the roster of a location
+// @Description Gives the booked appointments of a location for a day, the earliest first.
+// @Tags Admin
+// @ID GetLocationAppointments
+// @Accept json
+// @Produce json
+// @Param id path string true "Location ID"
+// @Param date query string false "The day in the location time zone in yyyy-mm-dd format, today by default"
+// @Success 200 {array} model.Appointment
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/locations/{id}/appointments [get]
+func (h AdminApisHandler) GetLocationAppointments(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	ID := params["id"]
+	if len(ID) <= 0 {
+		log.Println("Location id is required")
+		http.Error(w, "Location id is required", http.StatusBadRequest)
+		return
+	}
+	date := r.URL.Query().Get("date")
 
-	Gr1Count int `json:"gr1_count"`
-	Gr2Count int `json:"gr2_count"`
+	appointments, err := h.app.Administration.GetLocationAppointments(r.Context(), ID, date)
+	if err != nil {
+		log.Printf("Error on getting the location appointments - %s\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if appointments == nil {
+		appointments = []*model.Appointment{}
+	}
 
-	Items []symptomRuleItemResponse `json:"items"`
-} // @name SymptomRule
+	data, err := json.Marshal(appointments)
+	if err != nil {
+		log.Println("Error on marshal the location appointments")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
 
-type symptomRuleItemResponse struct {
-	Gr1            bool   `json:"gr1"`
-	Gr2            bool   `json:"gr2"`
-	CountyStatusID string `json:"county_status_id"`
-	NextStep       string `json:"next_step"`
-} // @name SymptomRuleItem
+type createSymptomRequest struct {
+	Name         string `json:"name" validate:"required"`
+	SymptomGroup string `json:"symptom_group" validate:"required,oneof=gr1 gr2"`
+} //@name createSymptomRequest
 
-//CreateSymptomRule creates a symptom rule
+//CreateSymptom creates a symptom
 // @Deprecated
-// @Description Creates a symptom rule.
+// @Description Creates a symptom
 // @Tags Admin
-// @ID CreateSymptomRule
+// @ID CreateSymptom
 // @Accept json
 // @Produce json
-// @Param data body createSymptomRuleRequest true "body data"
-// @Success 200 {object} symptomRuleResponse
+// @Param data body createSymptomRequest true "body data"
+// @Success 200 {object} symptomResponse
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
-// @Router /admin/symptom-rules [post]
-func (h AdminApisHandler) CreateSymptomRule(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+// @Router /admin/symptoms [post]
+func (h AdminApisHandler) CreateSymptom(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	data, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		log.Printf("Error on marshal create a symptom rule - %s\n", err.Error())
+		log.Printf("Error on marshal create a symptom - %s\n", err.Error())
 		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		return
 	}
 
-	var requestData createSymptomRuleRequest
+	var requestData createSymptomRequest
 	err = json.Unmarshal(data, &requestData)
 	if err != nil {
-		log.Printf("Error on unmarshal the create a symptom rule request data - %s\n", err.Error())
+		log.Printf("Error on unmarshal the create symptom request data - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -3216,45 +3801,23 @@ func (h AdminApisHandler) CreateSymptomRule(current model.User, group string, w
 	validate := validator.New()
 	err = validate.Struct(requestData)
 	if err != nil {
-		log.Printf("Error on validating create a symptom rule data - %s\n", err.Error())
+		log.Printf("Error on validating create symptom data - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	name := requestData.Name
+	symptomGroup := requestData.SymptomGroup
 
-	countyID := requestData.CountyID
-	gr1Count := requestData.Gr1Count
-	gr2Count := requestData.Gr2Count
-	items := requestData.Items
-
-	var rsItems []model.SymptomRuleItem
-	if items != nil {
-		for _, rs := range items {
-			countyStatus := model.CountyStatus{ID: rs.CountyStatusID}
-			r := model.SymptomRuleItem{Gr1: *rs.Gr1, Gr2: *rs.Gr2, CountyStatus: countyStatus, NextStep: rs.NextStep}
-			rsItems = append(rsItems, r)
-		}
-	}
-
-	symptomRule, err := h.app.Administration.CreateSymptomRule(current, group, countyID, gr1Count, gr2Count, rsItems)
+	symptom, err := h.app.Administration.CreateSymptom(r.Context(), current, group, name, symptomGroup)
 	if err != nil {
-		log.Println(err.Error())
+		log.Printf("Error on creating a location - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-
-	var rsResponseItems []symptomRuleItemResponse
-	if symptomRule.Items != nil {
-		for _, item := range symptomRule.Items {
-			r := symptomRuleItemResponse{Gr1: item.Gr1, Gr2: item.Gr2, CountyStatusID: item.CountyStatus.ID, NextStep: item.NextStep}
-			rsResponseItems = append(rsResponseItems, r)
-		}
-	}
-
-	resultItem := symptomRuleResponse{ID: symptomRule.ID, CountyID: symptomRule.County.ID,
-		Gr1Count: symptomRule.Gr1Count, Gr2Count: symptomRule.Gr2Count, Items: rsResponseItems}
-	data, err = json.Marshal(resultItem)
+	response := symptomResponse{ID: symptom.ID, Name: symptom.Name}
+	data, err = json.Marshal(response)
 	if err != nil {
-		log.Println("Error on marshal a symptom rule")
+		log.Println("Error on marshal a symptom")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
@@ -3264,55 +3827,43 @@ func (h AdminApisHandler) CreateSymptomRule(current model.User, group string, w
 	w.Write(data)
 }
 
-type updateSymptomRuleRequest struct {
-	CountyID string `json:"county_id" validate:"required,uuid"`
-
-	Gr1Count int `json:"gr1_count" validate:"required"`
-	Gr2Count int `json:"gr2_count" validate:"required"`
-
-	Items []updateSymptomRuleItemRequest `json:"items" validate:"required,max=4,min=4,dive"`
-} //@name updateSymptomRuleRequest
-
-type updateSymptomRuleItemRequest struct {
-	Gr1            *bool  `json:"gr1" validate:"required"`
-	Gr2            *bool  `json:"gr2" validate:"required"`
-	CountyStatusID string `json:"county_status_id" validate:"required"`
-	NextStep       string `json:"next_step" validate:"required"`
-} //@name updateSymptomRuleItemRequest
+type updateSymptomRequest struct {
+	Name string `json:"name" validate:"required"`
+} //@name updateSymptomRequest
 
-//UpdateSymptomRule updates a symptom rule
+//UpdateSymptom updates a symptom
 // @Deprecated
-// @Description Updates a symptom rule.
+// @Description Updates a symptom.
 // @Tags Admin
-// @ID UpdateSymptomRule
+// @ID UpdateSymptom
 // @Accept json
 // @Produce json
-// @Param data body updateSymptomRuleRequest true "body data"
+// @Param data body updateSymptomRequest true "body data"
 // @Param id path string true "ID"
-// @Success 200 {object} symptomRuleResponse
+// @Success 200 {object} symptomResponse
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
-// @Router /admin/symptom-rules/{id} [put]
-func (h AdminApisHandler) UpdateSymptomRule(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+// @Router /admin/symptoms/{id} [put]
+func (h AdminApisHandler) UpdateSymptom(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
 	ID := params["id"]
 	if len(ID) <= 0 {
-		log.Println("Symptom rule id is required")
-		http.Error(w, "Symptom rule id is required", http.StatusBadRequest)
+		log.Println("Symptom id is required")
+		http.Error(w, "Symptom id is required", http.StatusBadRequest)
 		return
 	}
 
 	data, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		log.Printf("Error on marshal the update symptom rule item - %s\n", err.Error())
+		log.Printf("Error on marshal update a symptom - %s\n", err.Error())
 		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		return
 	}
 
-	var requestData updateSymptomRuleRequest
+	var requestData updateSymptomRequest
 	err = json.Unmarshal(data, &requestData)
 	if err != nil {
-		log.Printf("Error on unmarshal the update symptom rule item request data - %s\n", err.Error())
+		log.Printf("Error on unmarshal the update symptom request data - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -3321,45 +3872,23 @@ func (h AdminApisHandler) UpdateSymptomRule(current model.User, group string, w
 	validate := validator.New()
 	err = validate.Struct(requestData)
 	if err != nil {
-		log.Printf("Error on validating update rule data - %s\n", err.Error())
+		log.Printf("Error on validating update test type result data - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	countyID := requestData.CountyID
-	gr1Count := requestData.Gr1Count
-	gr2Count := requestData.Gr2Count
-	items := requestData.Items
-
-	var rsItems []model.SymptomRuleItem
-	if items != nil {
-		for _, rs := range items {
-			countyStatus := model.CountyStatus{ID: rs.CountyStatusID}
-			r := model.SymptomRuleItem{Gr1: *rs.Gr1, Gr2: *rs.Gr2, CountyStatus: countyStatus, NextStep: rs.NextStep}
-			rsItems = append(rsItems, r)
-		}
-	}
-
-	symptomRule, err := h.app.Administration.UpdateSymptomRule(current, group, ID, countyID, gr1Count, gr2Count, rsItems)
+	name := requestData.Name
+	symptom, err := h.app.Administration.UpdateSymptom(r.Context(), current, group, ID, name)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	var rsResponseItems []symptomRuleItemResponse
-	if symptomRule.Items != nil {
-		for _, item := range symptomRule.Items {
-			r := symptomRuleItemResponse{Gr1: item.Gr1, Gr2: item.Gr2, CountyStatusID: item.CountyStatus.ID, NextStep: item.NextStep}
-			rsResponseItems = append(rsResponseItems, r)
-		}
-	}
-
-	resultItem := symptomRuleResponse{ID: symptomRule.ID, CountyID: symptomRule.County.ID,
-		Gr1Count: symptomRule.Gr1Count, Gr2Count: symptomRule.Gr2Count, Items: rsResponseItems}
-	data, err = json.Marshal(resultItem)
+	result := symptomResponse{ID: symptom.ID, Name: symptom.Name}
+	data, err = json.Marshal(result)
 	if err != nil {
-		log.Println("Error on marshal a symptom rule")
+		log.Println("Error on marshal a symptom")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
@@ -3369,48 +3898,81 @@ func (h AdminApisHandler) UpdateSymptomRule(current model.User, group string, w
 	w.Write(data)
 }
 
-//GetSymptomRules gets the symptom rules
+//DeleteSymptom deletes a symptom
 // @Deprecated
-// @Description Gives the symptom rules
+// @Description Deletes a symptom
 // @Tags Admin
-// @ID getSymptomRules
-// @Accept  json
-// @Success 200 {array} symptomRuleResponse
-// @Failure 400 {object} string "Authentication error"
-// @Failure 404 {object} string "Not Found"
-// @Failure 500 {object} string "Internal Server error"
-// @Security AdminUserAuth
-// @Security AdminGroupAuth
-// @Router /admin/symptom-rules [get]
-func (h AdminApisHandler) GetSymptomRules(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	symptomRules, err := h.app.Administration.GetSymptomRules()
+// @ID deleteSymptom
+// @Accept plain
+// @Param id path string true "ID"
+// @Success 200 {object} string "Successfuly deleted"
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/symptoms/{id} [delete]
+func (h AdminApisHandler) DeleteSymptom(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	ID := params["id"]
+	if len(ID) <= 0 {
+		log.Println("Symptom id is required")
+		http.Error(w, "Symptom id is required", http.StatusBadRequest)
+		return
+	}
+	err := h.app.Administration.DeleteSymptom(r.Context(), current, group, ID)
 	if err != nil {
-		log.Println("Error on getting the rules items")
+		log.Println(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Successfully deleted"))
+}
+
+type getSymptomGroupsResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+
+	Symptoms []symptomResponse `json:"symptoms"`
+} // @name SymptomGroup
+
+//GetSymptomGroups gets the symptom groups
+// @Deprecated
+// @Description Gives the symptom groups
+// @Tags Admin
+// @ID getSymptomGroups
+// @Accept  json
+// @Success 200 {array} getSymptomGroupsResponse
+// @Failure 400 {object} string "Authentication error"
+// @Failure 404 {object} string "Not Found"
+// @Failure 500 {object} string "Internal Server error"
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/symptom-groups [get]
+func (h AdminApisHandler) GetSymptomGroups(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	symptomGroups, err := h.app.Administration.GetSymptomGroups(r.Context())
+	if err != nil {
+		log.Println("Error on getting the symptom groups items")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
-	var response []symptomRuleResponse
-	if symptomRules != nil {
-		for _, symptomRule := range symptomRules {
-			var rsResponseItems []symptomRuleItemResponse
-			if symptomRule.Items != nil {
-				for _, item := range symptomRule.Items {
-					r := symptomRuleItemResponse{Gr1: item.Gr1, Gr2: item.Gr2, CountyStatusID: item.CountyStatus.ID, NextStep: item.NextStep}
-					rsResponseItems = append(rsResponseItems, r)
+	var response []getSymptomGroupsResponse
+	if symptomGroups != nil {
+		for _, sg := range symptomGroups {
+			var symptoms []symptomResponse
+			if sg.Symptoms != nil {
+				for _, s := range sg.Symptoms {
+					item := symptomResponse{ID: s.ID, Name: s.Name}
+					symptoms = append(symptoms, item)
 				}
 			}
-
-			resultItem := symptomRuleResponse{ID: symptomRule.ID, CountyID: symptomRule.County.ID,
-				Gr1Count: symptomRule.Gr1Count, Gr2Count: symptomRule.Gr2Count, Items: rsResponseItems}
-			response = append(response, resultItem)
+			r := getSymptomGroupsResponse{ID: sg.ID, Name: sg.Name, Symptoms: symptoms}
+			response = append(response, r)
 		}
 	}
-	if len(response) == 0 {
-		response = make([]symptomRuleResponse, 0)
-	}
 	data, err := json.Marshal(response)
 	if err != nil {
-		log.Println("Error on marshal the symptom rules items")
+		log.Println("Error on marshal the symptom groups items")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
@@ -3420,91 +3982,110 @@ func (h AdminApisHandler) GetSymptomRules(current model.User, group string, w ht
 	w.Write(data)
 }
 
-//DeleteSymptomRule deletes a symptom rule
+type createSymptomRuleRequest struct {
+	CountyID string `json:"county_id" validate:"required,uuid"`
+
+	Gr1Count int `json:"gr1_count" validate:"required"`
+	Gr2Count int `json:"gr2_count" validate:"required"`
+
+	Items []createSymptomRuleItemRequest `json:"items" validate:"required,max=4,min=4,dive"`
+} //@name createSymptomRuleRequest
+
+type createSymptomRuleItemRequest struct {
+	Gr1            *bool  `json:"gr1" validate:"required"`
+	Gr2            *bool  `json:"gr2" validate:"required"`
+	CountyStatusID string `json:"county_status_id" validate:"required"`
+	NextStep       string `json:"next_step" validate:"required"`
+} // @name createSymptomRuleItemRequest
+
+type symptomRuleResponse struct {
+	ID       string `json:"id"`
+	CountyID string `json:"county_id"`
+
+	Gr1Count int `json:"gr1_count"`
+	Gr2Count int `json:"gr2_count"`
+
+	Items []symptomRuleItemResponse `json:"items"`
+} // @name SymptomRule
+
+type symptomRuleItemResponse struct {
+	Gr1            bool   `json:"gr1"`
+	Gr2            bool   `json:"gr2"`
+	CountyStatusID string `json:"county_status_id"`
+	NextStep       string `json:"next_step"`
+} // @name SymptomRuleItem
+
+//CreateSymptomRule creates a symptom rule
 // @Deprecated
-// @Description Deletes a symptom rule.
+// @Description Creates a symptom rule.
 // @Tags Admin
-// @ID deleteSymptomRule
-// @Accept plain
-// @Param id path string true "ID"
-// @Success 200 {object} string "Successfuly deleted"
+// @ID CreateSymptomRule
+// @Accept json
+// @Produce json
+// @Param data body createSymptomRuleRequest true "body data"
+// @Success 200 {object} symptomRuleResponse
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
-// @Router /admin/symptom-rules/{id} [delete]
-func (h AdminApisHandler) DeleteSymptomRule(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	params := mux.Vars(r)
-	ID := params["id"]
-	if len(ID) <= 0 {
-		log.Println("Symptom rule id is required")
-		http.Error(w, "Symptom rule id is required", http.StatusBadRequest)
+// @Router /admin/symptom-rules [post]
+func (h AdminApisHandler) CreateSymptomRule(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal create a symptom rule - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		return
 	}
-	err := h.app.Administration.DeleteSymptomRule(current, group, ID)
+
+	var requestData createSymptomRuleRequest
+	err = json.Unmarshal(data, &requestData)
 	if err != nil {
-		log.Println(err.Error())
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Printf("Error on unmarshal the create a symptom rule request data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	w.Header().Set("Content-Type", "text/plain")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("Successfully deleted"))
-}
-
-//GetManualTestsByCountyID gets the manual tests for a county
-// @Description Gives the manual tests for a county
-// @Tags Admin
-// @ID getManualTestsByCountyID
-// @Accept  json
-// @Success 200 {array} eManualTestResponse
-// @Failure 400 {object} string "Authentication error"
-// @Failure 404 {object} string "Not Found"
-// @Failure 500 {object} string "Internal Server error"
-// @Security AdminUserAuth
-// @Security AdminGroupAuth
-// @Router /admin/manual-tests [get]
-func (h AdminApisHandler) GetManualTestsByCountyID(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	//county id
-	countyIDKeys, ok := r.URL.Query()["county-id"]
-	if !ok || len(countyIDKeys[0]) < 1 {
-		log.Println("url param 'county-id' is missing")
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating create a symptom rule data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	countyID := countyIDKeys[0]
 
-	//status
-	var status *string
-	statusKeys, ok := r.URL.Query()["status"]
-	if ok && len(statusKeys[0]) > 0 {
-		status = &statusKeys[0]
+	countyID := requestData.CountyID
+	gr1Count := requestData.Gr1Count
+	gr2Count := requestData.Gr2Count
+	items := requestData.Items
+
+	var rsItems []model.SymptomRuleItem
+	if items != nil {
+		for _, rs := range items {
+			countyStatus := model.CountyStatus{ID: rs.CountyStatusID}
+			r := model.SymptomRuleItem{Gr1: *rs.Gr1, Gr2: *rs.Gr2, CountyStatus: countyStatus, NextStep: rs.NextStep}
+			rsItems = append(rsItems, r)
+		}
 	}
 
-	manualTests, err := h.app.Administration.GetManualTestByCountyID(countyID, status)
+	symptomRule, err := h.app.Administration.CreateSymptomRule(r.Context(), current, group, countyID, gr1Count, gr2Count, rsItems)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	var resultList []eManualTestResponse
-
-	if manualTests != nil {
-		for _, item := range manualTests {
-			user := item.User
-			userResponse := AppUserResponse{UUID: user.UUID, PublicKey: user.PublicKey,
-				Consent: user.Consent, ExposureNotification: user.ExposureNotification, RePost: user.RePost,
-				EncryptedKey: user.EncryptedKey, EncryptedBlob: user.EncryptedBlob}
 
-			r := eManualTestResponse{ID: item.ID, HistoryID: item.HistoryID, LocationID: item.LocationID,
-				CountyID: item.CountyID, EncryptedKey: item.EncryptedKey, EncryptedBlob: item.EncryptedBlob,
-				Status: item.Status, Date: item.Date, User: userResponse}
-			resultList = append(resultList, r)
+	var rsResponseItems []symptomRuleItemResponse
+	if symptomRule.Items != nil {
+		for _, item := range symptomRule.Items {
+			r := symptomRuleItemResponse{Gr1: item.Gr1, Gr2: item.Gr2, CountyStatusID: item.CountyStatus.ID, NextStep: item.NextStep}
+			rsResponseItems = append(rsResponseItems, r)
 		}
-	} else {
-		resultList = make([]eManualTestResponse, 0)
 	}
-	data, err := json.Marshal(resultList)
+
+	resultItem := symptomRuleResponse{ID: symptomRule.ID, CountyID: symptomRule.County.ID,
+		Gr1Count: symptomRule.Gr1Count, Gr2Count: symptomRule.Gr2Count, Items: rsResponseItems}
+	data, err = json.Marshal(resultItem)
 	if err != nil {
-		log.Println("Error on marshal the manual tests")
+		log.Println("Error on marshal a symptom rule")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
@@ -3514,113 +4095,1200 @@ func (h AdminApisHandler) GetManualTestsByCountyID(current model.User, group str
 	w.Write(data)
 }
 
-type processManualTestRequest struct {
-	Status        string  `json:"status" validate:"required,oneof=unverified verified rejected"`
-	EncryptedKey  *string `json:"encrypted_key"`
-	EncryptedBlob *string `json:"encrypted_blob"`
-} //@name processManualTestRequest
+type updateSymptomRuleRequest struct {
+	CountyID string `json:"county_id" validate:"required,uuid"`
 
-//ProcessManualTest processes manual test
-// @Description Processes manual test.
+	Gr1Count int `json:"gr1_count" validate:"required"`
+	Gr2Count int `json:"gr2_count" validate:"required"`
+
+	Items []updateSymptomRuleItemRequest `json:"items" validate:"required,max=4,min=4,dive"`
+} //@name updateSymptomRuleRequest
+
+type updateSymptomRuleItemRequest struct {
+	Gr1            *bool  `json:"gr1" validate:"required"`
+	Gr2            *bool  `json:"gr2" validate:"required"`
+	CountyStatusID string `json:"county_status_id" validate:"required"`
+	NextStep       string `json:"next_step" validate:"required"`
+} //@name updateSymptomRuleItemRequest
+
+//UpdateSymptomRule updates a symptom rule
+// @Deprecated
+// @Description Updates a symptom rule.
 // @Tags Admin
-// @ID ProcessManualTest
+// @ID UpdateSymptomRule
 // @Accept json
 // @Produce json
-// @Param data body processManualTestRequest true "body data"
+// @Param data body updateSymptomRuleRequest true "body data"
 // @Param id path string true "ID"
-// @Success 200 {string} Successfully processed
+// @Success 200 {object} symptomRuleResponse
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
-// @Router /admin/manual-tests/{id}/process [put]
-func (h AdminApisHandler) ProcessManualTest(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+// @Router /admin/symptom-rules/{id} [put]
+func (h AdminApisHandler) UpdateSymptomRule(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
 	ID := params["id"]
 	if len(ID) <= 0 {
-		log.Println("Manual test id is required")
-		http.Error(w, "Manual test id is required", http.StatusBadRequest)
+		log.Println("Symptom rule id is required")
+		http.Error(w, "Symptom rule id is required", http.StatusBadRequest)
 		return
 	}
+
 	data, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		log.Printf("Error on marshal process manual test - %s\n", err.Error())
+		log.Printf("Error on marshal the update symptom rule item - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var requestData updateSymptomRuleRequest
+	err = json.Unmarshal(data, &requestData)
+	if err != nil {
+		log.Printf("Error on unmarshal the update symptom rule item request data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating update rule data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	countyID := requestData.CountyID
+	gr1Count := requestData.Gr1Count
+	gr2Count := requestData.Gr2Count
+	items := requestData.Items
+
+	var rsItems []model.SymptomRuleItem
+	if items != nil {
+		for _, rs := range items {
+			countyStatus := model.CountyStatus{ID: rs.CountyStatusID}
+			r := model.SymptomRuleItem{Gr1: *rs.Gr1, Gr2: *rs.Gr2, CountyStatus: countyStatus, NextStep: rs.NextStep}
+			rsItems = append(rsItems, r)
+		}
+	}
+
+	symptomRule, err := h.app.Administration.UpdateSymptomRule(r.Context(), current, group, ID, countyID, gr1Count, gr2Count, rsItems)
+	if err != nil {
+		log.Println(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var rsResponseItems []symptomRuleItemResponse
+	if symptomRule.Items != nil {
+		for _, item := range symptomRule.Items {
+			r := symptomRuleItemResponse{Gr1: item.Gr1, Gr2: item.Gr2, CountyStatusID: item.CountyStatus.ID, NextStep: item.NextStep}
+			rsResponseItems = append(rsResponseItems, r)
+		}
+	}
+
+	resultItem := symptomRuleResponse{ID: symptomRule.ID, CountyID: symptomRule.County.ID,
+		Gr1Count: symptomRule.Gr1Count, Gr2Count: symptomRule.Gr2Count, Items: rsResponseItems}
+	data, err = json.Marshal(resultItem)
+	if err != nil {
+		log.Println("Error on marshal a symptom rule")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//GetSymptomRules gets the symptom rules
+// @Deprecated
+// @Description Gives the symptom rules
+// @Tags Admin
+// @ID getSymptomRules
+// @Accept  json
+// @Success 200 {array} symptomRuleResponse
+// @Failure 400 {object} string "Authentication error"
+// @Failure 404 {object} string "Not Found"
+// @Failure 500 {object} string "Internal Server error"
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/symptom-rules [get]
+func (h AdminApisHandler) GetSymptomRules(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	symptomRules, err := h.app.Administration.GetSymptomRules(r.Context())
+	if err != nil {
+		log.Println("Error on getting the rules items")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	var response []symptomRuleResponse
+	if symptomRules != nil {
+		for _, symptomRule := range symptomRules {
+			var rsResponseItems []symptomRuleItemResponse
+			if symptomRule.Items != nil {
+				for _, item := range symptomRule.Items {
+					r := symptomRuleItemResponse{Gr1: item.Gr1, Gr2: item.Gr2, CountyStatusID: item.CountyStatus.ID, NextStep: item.NextStep}
+					rsResponseItems = append(rsResponseItems, r)
+				}
+			}
+
+			resultItem := symptomRuleResponse{ID: symptomRule.ID, CountyID: symptomRule.County.ID,
+				Gr1Count: symptomRule.Gr1Count, Gr2Count: symptomRule.Gr2Count, Items: rsResponseItems}
+			response = append(response, resultItem)
+		}
+	}
+	if len(response) == 0 {
+		response = make([]symptomRuleResponse, 0)
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		log.Println("Error on marshal the symptom rules items")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//DeleteSymptomRule deletes a symptom rule
+// @Deprecated
+// @Description Deletes a symptom rule.
+// @Tags Admin
+// @ID deleteSymptomRule
+// @Accept plain
+// @Param id path string true "ID"
+// @Success 200 {object} string "Successfuly deleted"
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/symptom-rules/{id} [delete]
+func (h AdminApisHandler) DeleteSymptomRule(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	ID := params["id"]
+	if len(ID) <= 0 {
+		log.Println("Symptom rule id is required")
+		http.Error(w, "Symptom rule id is required", http.StatusBadRequest)
+		return
+	}
+	err := h.app.Administration.DeleteSymptomRule(r.Context(), current, group, ID)
+	if err != nil {
+		log.Println(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Successfully deleted"))
+}
+
+//GetManualTestsByCountyID gets the manual tests for a county
+// @Description Gives the manual tests for a county
+// @Tags Admin
+// @ID getManualTestsByCountyID
+// @Accept  json
+// @Success 200 {array} eManualTestResponse
+// @Failure 400 {object} string "Authentication error"
+// @Failure 404 {object} string "Not Found"
+// @Failure 500 {object} string "Internal Server error"
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/manual-tests [get]
+func (h AdminApisHandler) GetManualTestsByCountyID(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	//county id
+	countyIDKeys, ok := r.URL.Query()["county-id"]
+	if !ok || len(countyIDKeys[0]) < 1 {
+		log.Println("url param 'county-id' is missing")
+		return
+	}
+	countyID := countyIDKeys[0]
+
+	//status
+	var status *string
+	statusKeys, ok := r.URL.Query()["status"]
+	if ok && len(statusKeys[0]) > 0 {
+		status = &statusKeys[0]
+	}
+
+	manualTests, err := h.app.Administration.GetManualTestByCountyID(r.Context(), countyID, status)
+	if err != nil {
+		log.Println(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var resultList []eManualTestResponse
+
+	if manualTests != nil {
+		for _, item := range manualTests {
+			user := item.User
+			userResponse := AppUserResponse{UUID: user.UUID, PublicKey: user.PublicKey,
+				Consent: user.Consent, ExposureNotification: user.ExposureNotification, RePost: user.RePost,
+				EncryptedKey: user.EncryptedKey, EncryptedBlob: user.EncryptedBlob}
+
+			r := eManualTestResponse{ID: item.ID, HistoryID: item.HistoryID, LocationID: item.LocationID,
+				CountyID: item.CountyID, EncryptedKey: item.EncryptedKey, EncryptedBlob: item.EncryptedBlob,
+				Status: item.Status, Date: item.Date, User: userResponse}
+			resultList = append(resultList, r)
+		}
+	} else {
+		resultList = make([]eManualTestResponse, 0)
+	}
+	data, err := json.Marshal(resultList)
+	if err != nil {
+		log.Println("Error on marshal the manual tests")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+type processManualTestRequest struct {
+	Status        string  `json:"status" validate:"required,oneof=unverified verified rejected"`
+	EncryptedKey  *string `json:"encrypted_key"`
+	EncryptedBlob *string `json:"encrypted_blob"`
+} //@name processManualTestRequest
+
+//ProcessManualTest processes manual test
+// @Description Processes manual test.
+// @Tags Admin
+// @ID ProcessManualTest
+// @Accept json
+// @Produce json
+// @Param data body processManualTestRequest true "body data"
+// @Param id path string true "ID"
+// @Success 200 {string} Successfully processed
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/manual-tests/{id}/process [put]
+func (h AdminApisHandler) ProcessManualTest(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	ID := params["id"]
+	if len(ID) <= 0 {
+		log.Println("Manual test id is required")
+		http.Error(w, "Manual test id is required", http.StatusBadRequest)
+		return
+	}
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal process manual test - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var requestData processManualTestRequest
+	err = json.Unmarshal(data, &requestData)
+	if err != nil {
+		log.Printf("Error on unmarshal the process manual test request data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating manual test data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	status := requestData.Status
+	encryptedKey := requestData.EncryptedKey
+	encryptedBlob := requestData.EncryptedBlob
+	if status == "verified" && (encryptedKey == nil || encryptedBlob == nil) {
+		http.Error(w, "encrypted key and encrypted blob are required when the status is verified", http.StatusBadRequest)
+		return
+	}
+
+	err = h.app.Administration.ProcessManualTest(r.Context(), ID, status, encryptedKey, encryptedBlob)
+	if err != nil {
+		log.Println(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Successfully processed"))
+}
+
+//GetManualTestImage gets the image for a manual test
+// @Description Gives the image for a manual test
+// @Tags Admin
+// @ID getManualTestImage
+// @Accept  json
+// @Param id path string true "Manual Test ID"
+// @Success 200 {object} manualTestImageResponse
+// @Failure 400 {object} string "Authentication error"
+// @Failure 404 {object} string "Not Found"
+// @Failure 500 {object} string "Internal Server error"
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/manual-tests/{id}/image [get]
+func (h AdminApisHandler) GetManualTestImage(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	ID := params["id"]
+	if len(ID) <= 0 {
+		log.Println("id is required")
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return
+	}
+
+	encryptedImageKey, encryptedImageBlob, err := h.app.Administration.GetManualTestImage(r.Context(), ID)
+	if err != nil {
+		log.Printf("Error on getting the manual test image - %s", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if encryptedImageKey == nil || encryptedImageBlob == nil {
+		//not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	responseItem := manualTestImageResponse{EncryptedImageKey: *encryptedImageKey, EncryptedImageBlob: *encryptedImageBlob}
+	data, err := json.Marshal(responseItem)
+	if err != nil {
+		log.Println("Error on marshal the manual test image")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+type createAccessRuleRequest struct {
+	Audit    *string                       `json:"audit"`
+	CountyID string                        `json:"county_id" validate:"required,uuid"`
+	Rules    []createAccessRuleItemRequest `json:"rules" validate:"required,dive"`
+} // @name createAccessRuleRequest
+
+type createAccessRuleItemRequest struct {
+	CountyStatusID string `json:"county_status_id" validate:"required"`
+	Value          string `json:"value" validate:"required,oneof=granted denied"`
+} // @name createAccessRuleItemRequest
+
+type accessRuleResponse struct {
+	ID       string `json:"id"`
+	CountyID string `json:"county_id"`
+
+	Rules []accessRuleItemResponse `json:"rules"`
+} // @name AccessRule
+
+type accessRuleItemResponse struct {
+	CountyStatusID string `json:"county_status_id"`
+	Value          string `json:"value"`
+} // @name AccessRuleCountyStatus
+
+//CreateAccessRule creates an access rule
+// @Description Creates an access rule.
+// @Tags Admin
+// @ID CreateAccessRule
+// @Accept json
+// @Produce json
+// @Param data body createAccessRuleRequest true "body data"
+// @Success 200 {object} accessRuleResponse
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/access-rules [post]
+func (h AdminApisHandler) CreateAccessRule(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal create a access rule - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var requestData createAccessRuleRequest
+	err = json.Unmarshal(data, &requestData)
+	if err != nil {
+		log.Printf("Error on unmarshal the create an access rule request data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating create an access rule data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	audit := requestData.Audit
+	countyID := requestData.CountyID
+	rules := requestData.Rules
+
+	var arRules []model.AccessRuleCountyStatus
+	if rules != nil {
+		for _, ar := range rules {
+			r := model.AccessRuleCountyStatus{CountyStatusID: ar.CountyStatusID, Value: ar.Value}
+			arRules = append(arRules, r)
+		}
+	}
+
+	accessRule, err := h.app.Administration.CreateAccessRule(r.Context(), current, group, audit, countyID, arRules)
+	if err != nil {
+		log.Println(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var rsResponseItems []accessRuleItemResponse
+	if accessRule.Rules != nil {
+		for _, item := range accessRule.Rules {
+			r := accessRuleItemResponse{CountyStatusID: item.CountyStatusID, Value: item.Value}
+			rsResponseItems = append(rsResponseItems, r)
+		}
+	}
+
+	resultItem := accessRuleResponse{ID: accessRule.ID, CountyID: accessRule.County.ID, Rules: rsResponseItems}
+	data, err = json.Marshal(resultItem)
+	if err != nil {
+		log.Println("Error on marshal an access rule")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//GetAccessRules gives the access rules
+// @Description Gives the access rules
+// @Tags Admin
+// @ID GetAccessRules
+// @Accept  json
+// @Success 200 {array} accessRuleResponse
+// @Failure 400 {object} string "Authentication error"
+// @Failure 404 {object} string "Not Found"
+// @Failure 500 {object} string "Internal Server error"
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/access-rules [get]
+func (h AdminApisHandler) GetAccessRules(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	accessRules, err := h.app.Administration.GetAccessRules(r.Context())
+	if err != nil {
+		log.Println("Error on getting the access rules items")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var response []accessRuleResponse
+	if accessRules != nil {
+		for _, accessRule := range accessRules {
+			var rsResponseItems []accessRuleItemResponse
+			if accessRule.Rules != nil {
+				for _, item := range accessRule.Rules {
+					r := accessRuleItemResponse{CountyStatusID: item.CountyStatusID, Value: item.Value}
+					rsResponseItems = append(rsResponseItems, r)
+				}
+			}
+
+			resultItem := accessRuleResponse{ID: accessRule.ID, CountyID: accessRule.County.ID, Rules: rsResponseItems}
+			response = append(response, resultItem)
+		}
+	}
+	if len(response) == 0 {
+		response = make([]accessRuleResponse, 0)
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		log.Println("Error on marshal the access rules items")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+type updateAccessRuleRequest struct {
+	Audit    *string                       `json:"audit"`
+	CountyID string                        `json:"county_id" validate:"required,uuid"`
+	Rules    []updateAccessRuleItemRequest `json:"rules" validate:"required,dive"`
+} //@name updateAccessRuleRequest
+
+type updateAccessRuleItemRequest struct {
+	CountyStatusID string `json:"county_status_id" validate:"required"`
+	Value          string `json:"value" validate:"required,oneof=granted denied"`
+} // @name updateAccessRuleItemRequest
+
+//UpdateAccessRule updates an access rule
+// @Description Updates an access rule.
+// @Tags Admin
+// @ID UpdateAccessRule
+// @Accept json
+// @Produce json
+// @Param data body updateAccessRuleRequest true "body data"
+// @Param id path string true "ID"
+// @Success 200 {object} accessRuleResponse
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/access-rules/{id} [put]
+func (h AdminApisHandler) UpdateAccessRule(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	ID := params["id"]
+	if len(ID) <= 0 {
+		log.Println("Access rule id is required")
+		http.Error(w, "Access rule id is required", http.StatusBadRequest)
+		return
+	}
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal the update access rule item - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var requestData updateAccessRuleRequest
+	err = json.Unmarshal(data, &requestData)
+	if err != nil {
+		log.Printf("Error on unmarshal the update access rule item request data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating update an access rule data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	audit := requestData.Audit
+	countyID := requestData.CountyID
+	rules := requestData.Rules
+
+	var arRules []model.AccessRuleCountyStatus
+	if rules != nil {
+		for _, ar := range rules {
+			r := model.AccessRuleCountyStatus{CountyStatusID: ar.CountyStatusID, Value: ar.Value}
+			arRules = append(arRules, r)
+		}
+	}
+
+	accessRule, err := h.app.Administration.UpdateAccessRule(r.Context(), current, group, audit, ID, countyID, arRules)
+	if err != nil {
+		log.Println(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var rsResponseItems []accessRuleItemResponse
+	if accessRule.Rules != nil {
+		for _, item := range accessRule.Rules {
+			r := accessRuleItemResponse{CountyStatusID: item.CountyStatusID, Value: item.Value}
+			rsResponseItems = append(rsResponseItems, r)
+		}
+	}
+
+	resultItem := accessRuleResponse{ID: accessRule.ID, CountyID: accessRule.County.ID, Rules: rsResponseItems}
+	data, err = json.Marshal(resultItem)
+	if err != nil {
+		log.Println("Error on marshal an access rule")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//DeleteAccessRule deletes an access rule
+// @Description Deletes an access rule
+// @Tags Admin
+// @ID deleteAccessRule
+// @Accept plain
+// @Param id path string true "ID"
+// @Success 200 {object} string "Successfuly deleted"
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/access-rules/{id} [delete]
+func (h AdminApisHandler) DeleteAccessRule(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	ID := params["id"]
+	if len(ID) <= 0 {
+		log.Println("Access rule id is required")
+		http.Error(w, "Access rule id is required", http.StatusBadRequest)
+		return
+	}
+	err := h.app.Administration.DeleteAccessRule(r.Context(), current, group, ID)
+	if err != nil {
+		log.Println(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Successfully deleted"))
+}
+
+//GetCRules gets the rules
+// @Description Gives the rules
+// @Tags Admin
+// @ID GetCRules
+// @Accept json
+// @Param county-id query string false "County ID"
+// @Param app-version query string false "App version"
+// @Success 200 {object} string
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/crules [get]
+func (h AdminApisHandler) GetCRules(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	countyKeys, ok := r.URL.Query()["county-id"]
+	if !ok || len(countyKeys[0]) < 1 {
+		log.Println("url param 'county-id' is missing")
+		return
+	}
+	appVersionKeys, ok := r.URL.Query()["app-version"]
+	if !ok || len(appVersionKeys[0]) < 1 {
+		log.Println("url param 'app-version' is missing")
+		return
+	}
+	countyID := countyKeys[0]
+	appVersion := appVersionKeys[0]
+
+	cRules, err := h.app.Administration.GetCRules(r.Context(), countyID, appVersion)
+	if err != nil {
+		log.Printf("Error on getting crules - %s", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if cRules == nil {
+		//not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	data := []byte(cRules.Data)
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+type createOrUpdateCRulesRequest struct {
+	Audit      *string `json:"audit"`
+	AppVersion string  `json:"app_version" validate:"required"`
+	CountyID   string  `json:"county_id" validate:"required"`
+	Data       string  `json:"data" validate:"required"`
+} //@name createOrUpdateCRulesRequest
+
+//CreateOrUpdateCRules creates rules, updates them if already created
+// @Description Creates rules, updates them if already created. The data is published right away as a new revision, use the crules revisions draft APIs for a review step. The data must be JSON and must follow the schema registered for the app version.
+// @Tags Admin
+// @ID CreateOrUpdateCRules
+// @Accept json
+// @Produce json
+// @Param data body createOrUpdateCRulesRequest true "body data"
+// @Success 200 {object} string
+// @Failure 400 {object} configValidationErrorResponse
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/crules [put]
+func (h AdminApisHandler) CreateOrUpdateCRules(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	bodyData, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal the update crules items  - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var requestData createOrUpdateCRulesRequest
+	err = json.Unmarshal(bodyData, &requestData)
+	if err != nil {
+		log.Printf("Error on unmarshal the update crules items request data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating update crules data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	audit := requestData.Audit
+	appVersion := requestData.AppVersion
+	countyID := requestData.CountyID
+	data := requestData.Data
+
+	err = h.app.Administration.CreateOrUpdateCRules(r.Context(), current, group, audit, countyID, appVersion, data)
+	if writeConfigValidationError(w, err) {
+		return
+	}
+	if err != nil {
+		log.Println(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Successfully processed"))
+}
+
+//GetSymptoms gets the symptoms
+// @Description Gives the symptoms
+// @Tags Admin
+// @ID GetASymptoms
+// @Accept json
+// @Param app-version query string false "App version"
+// @Success 200 {object} string
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/symptoms [get]
+func (h AdminApisHandler) GetSymptoms(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	appVersionKeys, ok := r.URL.Query()["app-version"]
+	if !ok || len(appVersionKeys[0]) < 1 {
+		log.Println("url param 'app-version' is missing")
+		return
+	}
+	appVersion := appVersionKeys[0]
+
+	symptoms, err := h.app.Administration.GetSymptoms(r.Context(), appVersion)
+	if err != nil {
+		log.Printf("Error on getting symptoms - %s", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if symptoms == nil {
+		//not found
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	data := []byte(symptoms.Items)
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+type createOrUpdateSymptomsRequest struct {
+	Audit      *string `json:"audit"`
+	AppVersion string  `json:"app_version" validate:"required"`
+	Items      string  `json:"items" validate:"required"`
+} //@name createOrUpdateSymptomsRequest
+
+//CreateOrUpdateSymptoms creates symptoms or update them if already created
+// @Description Creates symptoms or update them if already created. The items are published right away as a new revision, use the symptoms revisions draft APIs for a review step. The items must be JSON and must follow the schema registered for the app version.
+// @Tags Admin
+// @ID CreateorUpdateSymptoms
+// @Accept json
+// @Produce json
+// @Param data body createOrUpdateSymptomsRequest true "body data"
+// @Success 200 {string} Successfully processed
+// @Failure 400 {object} configValidationErrorResponse
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/symptoms [put]
+func (h AdminApisHandler) CreateOrUpdateSymptoms(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	bodyData, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal the update symptoms items  - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var requestData createOrUpdateSymptomsRequest
+	err = json.Unmarshal(bodyData, &requestData)
+	if err != nil {
+		log.Printf("Error on unmarshal the update symptoms items request data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating update symptoms data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	audit := requestData.Audit
+	appVersion := requestData.AppVersion
+	items := requestData.Items
+
+	err = h.app.Administration.CreateOrUpdateSymptoms(r.Context(), current, group, audit, appVersion, items)
+	if writeConfigValidationError(w, err) {
+		return
+	}
+	if err != nil {
+		log.Println(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Successfully processed"))
+}
+
+//GetCRulesRevisions gives the crules revisions
+// @Description Gives the revisions of the crules for a county and an app version, the latest are first. Only the published revision is received by the apps.
+// @Tags Admin
+// @ID GetCRulesRevisions
+// @Accept json
+// @Param county-id query string true "County ID"
+// @Param app-version query string true "App version"
+// @Success 200 {array} model.ConfigRevision
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/crules/revisions [get]
+func (h AdminApisHandler) GetCRulesRevisions(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	countyKeys, ok := r.URL.Query()["county-id"]
+	if !ok || len(countyKeys[0]) < 1 {
+		log.Println("url param 'county-id' is missing")
+		http.Error(w, "url param 'county-id' is missing", http.StatusBadRequest)
+		return
+	}
+	h.getConfigRevisions("crules", countyKeys[0], w, r)
+}
+
+type saveCRulesDraftRequest struct {
+	Audit      *string `json:"audit"`
+	AppVersion string  `json:"app_version" validate:"required"`
+	CountyID   string  `json:"county_id" validate:"required"`
+	Data       string  `json:"data" validate:"required"`
+} //@name saveCRulesDraftRequest
+
+//SaveCRulesDraft creates a crules draft or updates the current one
+// @Description Creates a draft revision of the crules for a county and an app version or updates the current draft. The apps do not receive it until it is published.
+// @Tags Admin
+// @ID SaveCRulesDraft
+// @Accept json
+// @Produce json
+// @Param data body saveCRulesDraftRequest true "body data"
+// @Success 200 {object} model.ConfigRevision
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/crules/revisions/draft [put]
+func (h AdminApisHandler) SaveCRulesDraft(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	var requestData saveCRulesDraftRequest
+	if !readConfigRevisionRequest(w, r, &requestData) {
+		return
+	}
+
+	revision, err := h.app.Administration.SaveConfigDraft(r.Context(), current, group, requestData.Audit, "crules",
+		requestData.CountyID, requestData.AppVersion, requestData.Data)
+	writeConfigRevisionResponse(w, revision, err)
+}
+
+type configRevisionActionRequest struct {
+	Audit      *string `json:"audit"`
+	AppVersion string  `json:"app_version" validate:"required"`
+	CountyID   string  `json:"county_id" validate:"required"`
+} //@name configRevisionActionRequest
+
+//PublishCRulesDraft publishes the crules draft
+// @Description Publishes the current crules draft for a county and an app version. The previous published revision is archived.
+// @Tags Admin
+// @ID PublishCRulesDraft
+// @Accept json
+// @Produce json
+// @Param data body configRevisionActionRequest true "body data"
+// @Success 200 {object} model.ConfigRevision
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/crules/revisions/draft/publish [post]
+func (h AdminApisHandler) PublishCRulesDraft(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	var requestData configRevisionActionRequest
+	if !readConfigRevisionRequest(w, r, &requestData) {
+		return
+	}
+
+	revision, err := h.app.Administration.PublishConfigDraft(r.Context(), current, group, requestData.Audit, "crules",
+		requestData.CountyID, requestData.AppVersion)
+	writeConfigRevisionResponse(w, revision, err)
+}
+
+//DiscardCRulesDraft discards the crules draft
+// @Description Discards the current crules draft for a county and an app version. It stays in the revisions history.
+// @Tags Admin
+// @ID DiscardCRulesDraft
+// @Accept json
+// @Produce json
+// @Param data body configRevisionActionRequest true "body data"
+// @Success 200 {object} model.ConfigRevision
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/crules/revisions/draft/discard [post]
+func (h AdminApisHandler) DiscardCRulesDraft(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	var requestData configRevisionActionRequest
+	if !readConfigRevisionRequest(w, r, &requestData) {
+		return
+	}
+
+	revision, err := h.app.Administration.DiscardConfigDraft(r.Context(), current, group, requestData.Audit, "crules",
+		requestData.CountyID, requestData.AppVersion)
+	writeConfigRevisionResponse(w, revision, err)
+}
+
+type rollbackCRulesRequest struct {
+	Audit      *string `json:"audit"`
+	AppVersion string  `json:"app_version" validate:"required"`
+	CountyID   string  `json:"county_id" validate:"required"`
+	Revision   int     `json:"revision" validate:"required,min=1"`
+} //@name rollbackCRulesRequest
+
+//RollbackCRules rolls back the crules to an earlier revision
+// @Description Publishes a new crules revision with the data of an earlier archived revision for a county and an app version.
+// @Tags Admin
+// @ID RollbackCRules
+// @Accept json
+// @Produce json
+// @Param data body rollbackCRulesRequest true "body data"
+// @Success 200 {object} model.ConfigRevision
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/crules/revisions/rollback [post]
+func (h AdminApisHandler) RollbackCRules(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	var requestData rollbackCRulesRequest
+	if !readConfigRevisionRequest(w, r, &requestData) {
+		return
+	}
+
+	revision, err := h.app.Administration.RollbackConfig(r.Context(), current, group, requestData.Audit, "crules",
+		requestData.CountyID, requestData.AppVersion, requestData.Revision)
+	writeConfigRevisionResponse(w, revision, err)
+}
+
+//GetSymptomsRevisions gives the symptoms revisions
+// @Description Gives the revisions of the symptoms for an app version, the latest are first. Only the published revision is received by the apps.
+// @Tags Admin
+// @ID GetSymptomsRevisions
+// @Accept json
+// @Param app-version query string true "App version"
+// @Success 200 {array} model.ConfigRevision
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/symptoms/revisions [get]
+func (h AdminApisHandler) GetSymptomsRevisions(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	h.getConfigRevisions("symptoms", "", w, r)
+}
+
+type saveSymptomsDraftRequest struct {
+	Audit      *string `json:"audit"`
+	AppVersion string  `json:"app_version" validate:"required"`
+	Items      string  `json:"items" validate:"required"`
+} //@name saveSymptomsDraftRequest
+
+//SaveSymptomsDraft creates a symptoms draft or updates the current one
+// @Description Creates a draft revision of the symptoms for an app version or updates the current draft. The apps do not receive it until it is published.
+// @Tags Admin
+// @ID SaveSymptomsDraft
+// @Accept json
+// @Produce json
+// @Param data body saveSymptomsDraftRequest true "body data"
+// @Success 200 {object} model.ConfigRevision
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/symptoms/revisions/draft [put]
+func (h AdminApisHandler) SaveSymptomsDraft(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	var requestData saveSymptomsDraftRequest
+	if !readConfigRevisionRequest(w, r, &requestData) {
+		return
+	}
+
+	revision, err := h.app.Administration.SaveConfigDraft(r.Context(), current, group, requestData.Audit, "symptoms",
+		"", requestData.AppVersion, requestData.Items)
+	writeConfigRevisionResponse(w, revision, err)
+}
+
+type symptomsRevisionActionRequest struct {
+	Audit      *string `json:"audit"`
+	AppVersion string  `json:"app_version" validate:"required"`
+} //@name symptomsRevisionActionRequest
+
+//PublishSymptomsDraft publishes the symptoms draft
+// @Description Publishes the current symptoms draft for an app version. The previous published revision is archived.
+// @Tags Admin
+// @ID PublishSymptomsDraft
+// @Accept json
+// @Produce json
+// @Param data body symptomsRevisionActionRequest true "body data"
+// @Success 200 {object} model.ConfigRevision
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/symptoms/revisions/draft/publish [post]
+func (h AdminApisHandler) PublishSymptomsDraft(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	var requestData symptomsRevisionActionRequest
+	if !readConfigRevisionRequest(w, r, &requestData) {
+		return
+	}
+
+	revision, err := h.app.Administration.PublishConfigDraft(r.Context(), current, group, requestData.Audit, "symptoms",
+		"", requestData.AppVersion)
+	writeConfigRevisionResponse(w, revision, err)
+}
+
+//DiscardSymptomsDraft discards the symptoms draft
+// @Description Discards the current symptoms draft for an app version. It stays in the revisions history.
+// @Tags Admin
+// @ID DiscardSymptomsDraft
+// @Accept json
+// @Produce json
+// @Param data body symptomsRevisionActionRequest true "body data"
+// @Success 200 {object} model.ConfigRevision
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/symptoms/revisions/draft/discard [post]
+func (h AdminApisHandler) DiscardSymptomsDraft(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	var requestData symptomsRevisionActionRequest
+	if !readConfigRevisionRequest(w, r, &requestData) {
+		return
+	}
+
+	revision, err := h.app.Administration.DiscardConfigDraft(r.Context(), current, group, requestData.Audit, "symptoms",
+		"", requestData.AppVersion)
+	writeConfigRevisionResponse(w, revision, err)
+}
+
+type rollbackSymptomsRequest struct {
+	Audit      *string `json:"audit"`
+	AppVersion string  `json:"app_version" validate:"required"`
+	Revision   int     `json:"revision" validate:"required,min=1"`
+} //@name rollbackSymptomsRequest
+
+//RollbackSymptoms rolls back the symptoms to an earlier revision
+// @Description Publishes a new symptoms revision with the items of an earlier archived revision for an app version.
+// @Tags Admin
+// @ID RollbackSymptoms
+// @Accept json
+// @Produce json
+// @Param data body rollbackSymptomsRequest true "body data"
+// @Success 200 {object} model.ConfigRevision
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/symptoms/revisions/rollback [post]
+func (h AdminApisHandler) RollbackSymptoms(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	var requestData rollbackSymptomsRequest
+	if !readConfigRevisionRequest(w, r, &requestData) {
+		return
+	}
+
+	revision, err := h.app.Administration.RollbackConfig(r.Context(), current, group, requestData.Audit, "symptoms",
+		"", requestData.AppVersion, requestData.Revision)
+	writeConfigRevisionResponse(w, revision, err)
+}
+
+func (h AdminApisHandler) getConfigRevisions(configType string, countyID string, w http.ResponseWriter, r *http.Request) {
+	appVersionKeys, ok := r.URL.Query()["app-version"]
+	if !ok || len(appVersionKeys[0]) < 1 {
+		log.Println("url param 'app-version' is missing")
+		http.Error(w, "url param 'app-version' is missing", http.StatusBadRequest)
+		return
+	}
+
+	revisions, err := h.app.Administration.GetConfigRevisions(r.Context(), configType, countyID, appVersionKeys[0])
+	if err != nil {
+		log.Printf("Error on getting %s revisions - %s", configType, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	data, err := json.Marshal(revisions)
+	if err != nil {
+		log.Printf("Error on marshal the %s revisions", configType)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//readConfigRevisionRequest reads and validates the request data, it writes the error if it is not valid
+func readConfigRevisionRequest(w http.ResponseWriter, r *http.Request, requestData interface{}) bool {
+	bodyData, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal the revision request - %s\n", err.Error())
 		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
-		return
+		return false
 	}
-
-	var requestData processManualTestRequest
-	err = json.Unmarshal(data, &requestData)
+	err = json.Unmarshal(bodyData, requestData)
 	if err != nil {
-		log.Printf("Error on unmarshal the process manual test request data - %s\n", err.Error())
+		log.Printf("Error on unmarshal the revision request data - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+		return false
 	}
 
 	//validate
 	validate := validator.New()
 	err = validate.Struct(requestData)
 	if err != nil {
-		log.Printf("Error on validating manual test data - %s\n", err.Error())
+		log.Printf("Error on validating the revision request data - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+		return false
 	}
-	status := requestData.Status
-	encryptedKey := requestData.EncryptedKey
-	encryptedBlob := requestData.EncryptedBlob
-	if status == "verified" && (encryptedKey == nil || encryptedBlob == nil) {
-		http.Error(w, "encrypted key and encrypted blob are required when the status is verified", http.StatusBadRequest)
+	return true
+}
+
+func writeConfigRevisionResponse(w http.ResponseWriter, revision *model.ConfigRevision, err error) {
+	if writeConfigValidationError(w, err) {
 		return
 	}
-
-	err = h.app.Administration.ProcessManualTest(ID, status, encryptedKey, encryptedBlob)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	data, err := json.Marshal(revision)
+	if err != nil {
+		log.Println("Error on marshal the revision")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
 
-	w.Header().Set("Content-Type", "text/plain")
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("Successfully processed"))
+	w.Write(data)
 }
 
-//GetManualTestImage gets the image for a manual test
-// @Description Gives the image for a manual test
+//GetConfigSchemas gives the config schemas
+// @Description Gives the JSON Schemas registered for the crules and the symptoms, for an app version if provided.
 // @Tags Admin
-// @ID getManualTestImage
-// @Accept  json
-// @Param id path string true "Manual Test ID"
-// @Success 200 {object} manualTestImageResponse
-// @Failure 400 {object} string "Authentication error"
-// @Failure 404 {object} string "Not Found"
-// @Failure 500 {object} string "Internal Server error"
+// @ID GetConfigSchemas
+// @Accept json
+// @Param app-version query string false "App version"
+// @Success 200 {array} model.ConfigSchema
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
-// @Router /admin/manual-tests/{id}/image [get]
-func (h AdminApisHandler) GetManualTestImage(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	params := mux.Vars(r)
-	ID := params["id"]
-	if len(ID) <= 0 {
-		log.Println("id is required")
-		http.Error(w, "id is required", http.StatusBadRequest)
-		return
+// @Router /admin/config-schemas [get]
+func (h AdminApisHandler) GetConfigSchemas(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	var appVersion *string
+	appVersionKeys, ok := r.URL.Query()["app-version"]
+	if ok && len(appVersionKeys[0]) > 0 {
+		appVersion = &appVersionKeys[0]
 	}
 
-	encryptedImageKey, encryptedImageBlob, err := h.app.Administration.GetManualTestImage(ID)
+	schemas, err := h.app.Administration.GetConfigSchemas(r.Context(), appVersion)
 	if err != nil {
-		log.Printf("Error on getting the manual test image - %s", err)
+		log.Printf("Error on getting config schemas - %s", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	if encryptedImageKey == nil || encryptedImageBlob == nil {
-		//not found
-		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
-		return
-	}
-
-	responseItem := manualTestImageResponse{EncryptedImageKey: *encryptedImageKey, EncryptedImageBlob: *encryptedImageBlob}
-	data, err := json.Marshal(responseItem)
+	data, err := json.Marshal(schemas)
 	if err != nil {
-		log.Println("Error on marshal the manual test image")
+		log.Println("Error on marshal the config schemas")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
@@ -3630,52 +5298,36 @@ func (h AdminApisHandler) GetManualTestImage(current model.User, group string, w
 	w.Write(data)
 }
 
-type createAccessRuleRequest struct {
-	Audit    *string                       `json:"audit"`
-	CountyID string                        `json:"county_id" validate:"required,uuid"`
-	Rules    []createAccessRuleItemRequest `json:"rules" validate:"required,dive"`
-} // @name createAccessRuleRequest
-
-type createAccessRuleItemRequest struct {
-	CountyStatusID string `json:"county_status_id" validate:"required"`
-	Value          string `json:"value" validate:"required,oneof=granted denied"`
-} // @name createAccessRuleItemRequest
-
-type accessRuleResponse struct {
-	ID       string `json:"id"`
-	CountyID string `json:"county_id"`
-
-	Rules []accessRuleItemResponse `json:"rules"`
-} // @name AccessRule
-
-type accessRuleItemResponse struct {
-	CountyStatusID string `json:"county_status_id"`
-	Value          string `json:"value"`
-} // @name AccessRuleCountyStatus
+type saveConfigSchemaRequest struct {
+	Audit      *string `json:"audit"`
+	Type       string  `json:"type" validate:"required,oneof=crules symptoms"`
+	AppVersion string  `json:"app_version" validate:"required"`
+	Schema     string  `json:"schema" validate:"required"`
+} //@name saveConfigSchemaRequest
 
-//CreateAccessRule creates an access rule
-// @Description Creates an access rule.
+//SaveConfigSchema registers a config schema
+// @Description Registers the JSON Schema the crules data or the symptoms items must follow for an app version, it replaces the current one. The string values can be marked as references with the formats county-status-id, test-type-id, test-type-result-id and symptom-id - the referred entities must exist, the county statuses must be of the crules county and the crules test types must have a rule in the county.
 // @Tags Admin
-// @ID CreateAccessRule
+// @ID SaveConfigSchema
 // @Accept json
 // @Produce json
-// @Param data body createAccessRuleRequest true "body data"
-// @Success 200 {object} accessRuleResponse
+// @Param data body saveConfigSchemaRequest true "body data"
+// @Success 200 {object} model.ConfigSchema
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
-// @Router /admin/access-rules [post]
-func (h AdminApisHandler) CreateAccessRule(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	data, err := ioutil.ReadAll(r.Body)
+// @Router /admin/config-schemas [put]
+func (h AdminApisHandler) SaveConfigSchema(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	bodyData, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		log.Printf("Error on marshal create a access rule - %s\n", err.Error())
+		log.Printf("Error on marshal the config schema - %s\n", err.Error())
 		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		return
 	}
 
-	var requestData createAccessRuleRequest
-	err = json.Unmarshal(data, &requestData)
+	var requestData saveConfigSchemaRequest
+	err = json.Unmarshal(bodyData, &requestData)
 	if err != nil {
-		log.Printf("Error on unmarshal the create an access rule request data - %s\n", err.Error())
+		log.Printf("Error on unmarshal the config schema request data - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -3684,42 +5336,131 @@ func (h AdminApisHandler) CreateAccessRule(current model.User, group string, w h
 	validate := validator.New()
 	err = validate.Struct(requestData)
 	if err != nil {
-		log.Printf("Error on validating create an access rule data - %s\n", err.Error())
+		log.Printf("Error on validating config schema data - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	audit := requestData.Audit
-	countyID := requestData.CountyID
-	rules := requestData.Rules
+	schema, err := h.app.Administration.SaveConfigSchema(r.Context(), current, group, requestData.Audit, requestData.Type,
+		requestData.AppVersion, requestData.Schema)
+	if err != nil {
+		log.Println(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		log.Println("Error on marshal the config schema")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
 
-	var arRules []model.AccessRuleCountyStatus
-	if rules != nil {
-		for _, ar := range rules {
-			r := model.AccessRuleCountyStatus{CountyStatusID: ar.CountyStatusID, Value: ar.Value}
-			arRules = append(arRules, r)
-		}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//DeleteConfigSchema deletes a config schema
+// @Description Deletes the JSON Schema for the crules or the symptoms of an app version. The data is only checked to be JSON after that.
+// @Tags Admin
+// @ID DeleteConfigSchema
+// @Accept plain
+// @Param type query string true "crules or symptoms"
+// @Param app-version query string true "App version"
+// @Success 200 {object} string "Successfully deleted"
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/config-schemas [delete]
+func (h AdminApisHandler) DeleteConfigSchema(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	typeKeys, ok := r.URL.Query()["type"]
+	if !ok || len(typeKeys[0]) < 1 {
+		log.Println("url param 'type' is missing")
+		http.Error(w, "url param 'type' is missing", http.StatusBadRequest)
+		return
+	}
+	appVersionKeys, ok := r.URL.Query()["app-version"]
+	if !ok || len(appVersionKeys[0]) < 1 {
+		log.Println("url param 'app-version' is missing")
+		http.Error(w, "url param 'app-version' is missing", http.StatusBadRequest)
+		return
 	}
 
-	accessRule, err := h.app.Administration.CreateAccessRule(current, group, audit, countyID, arRules)
+	err := h.app.Administration.DeleteConfigSchema(r.Context(), current, group, typeKeys[0], appVersionKeys[0])
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	var rsResponseItems []accessRuleItemResponse
-	if accessRule.Rules != nil {
-		for _, item := range accessRule.Rules {
-			r := accessRuleItemResponse{CountyStatusID: item.CountyStatusID, Value: item.Value}
-			rsResponseItems = append(rsResponseItems, r)
-		}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Successfully deleted"))
+}
+
+type configValidationErrorResponse struct {
+	Message string                  `json:"message"`
+	Issues  []model.ValidationIssue `json:"issues"`
+} // @name configValidationErrorResponse
+
+//writeConfigValidationError writes bad request with the issues if the error is a validation one
+func writeConfigValidationError(w http.ResponseWriter, err error) bool {
+	validationErr, ok := err.(*core.ConfigValidationError)
+	if !ok {
+		return false
 	}
+	log.Println(validationErr.Error())
 
-	resultItem := accessRuleResponse{ID: accessRule.ID, CountyID: accessRule.County.ID, Rules: rsResponseItems}
-	data, err = json.Marshal(resultItem)
+	data, err := json.Marshal(configValidationErrorResponse{Message: validationErr.Error(), Issues: validationErr.Issues})
 	if err != nil {
-		log.Println("Error on marshal an access rule")
+		log.Println("Error on marshal the validation error")
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return true
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusBadRequest)
+	w.Write(data)
+	return true
+}
+
+//GetUINOverrides gives uin override items
+// @Description Gives uin override items
+// @Tags Admin
+// @ID GetUINOverrides
+// @Accept json
+// @Param uin query string false "UIN"
+// @Param sort query string false "Sort by uin or category"
+// @Success 200 {array} model.UINOverride
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/uin-overrides [get]
+func (h AdminApisHandler) GetUINOverrides(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	//uin
+	var uin *string
+	uinKeys, ok := r.URL.Query()["uin"]
+	if ok && len(uinKeys[0]) > 0 {
+		uin = &uinKeys[0]
+	}
+
+	//sort by
+	var sort *string
+	sortByKeys, ok := r.URL.Query()["sort"]
+	if ok {
+		sort = &sortByKeys[0]
+	}
+
+	uinOverrides, err := h.app.Administration.GetUINOverrides(r.Context(), uin, sort)
+	if err != nil {
+		log.Println("Error on getting the uin overrides items")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if len(uinOverrides) == 0 {
+		uinOverrides = make([]*model.UINOverride, 0)
+	}
+	data, err := json.Marshal(uinOverrides)
+	if err != nil {
+		log.Println("Error on marshal the uin overrides items")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
@@ -3729,46 +5470,66 @@ func (h AdminApisHandler) CreateAccessRule(current model.User, group string, w h
 	w.Write(data)
 }
 
-//GetAccessRules gives the access rules
-// @Description Gives the access rules
+type createUINOverrideRequest struct {
+	Audit      *string    `json:"audit"`
+	UIN        string     `json:"uin" validate:"required"`
+	Interval   int        `json:"interval" validate:"required"`
+	Category   *string    `json:"category"`
+	Expiration *time.Time `json:"expiration"`
+} // @name createUINOverrideRequest
+
+//CreateUINOverride creates an uin override
+// @Description Creates an uin override.
 // @Tags Admin
-// @ID GetAccessRules
-// @Accept  json
-// @Success 200 {array} accessRuleResponse
-// @Failure 400 {object} string "Authentication error"
-// @Failure 404 {object} string "Not Found"
-// @Failure 500 {object} string "Internal Server error"
+// @ID CreateUINOverride
+// @Accept json
+// @Produce json
+// @Param data body createUINOverrideRequest true "body data"
+// @Success 200 {object} model.UINOverride
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
-// @Router /admin/access-rules [get]
-func (h AdminApisHandler) GetAccessRules(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	accessRules, err := h.app.Administration.GetAccessRules()
+// @Router /admin/uin-overrides [post]
+func (h AdminApisHandler) CreateUINOverride(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	data, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		log.Println("Error on getting the access rules items")
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Printf("Error on marshal create uin override - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		return
 	}
-	var response []accessRuleResponse
-	if accessRules != nil {
-		for _, accessRule := range accessRules {
-			var rsResponseItems []accessRuleItemResponse
-			if accessRule.Rules != nil {
-				for _, item := range accessRule.Rules {
-					r := accessRuleItemResponse{CountyStatusID: item.CountyStatusID, Value: item.Value}
-					rsResponseItems = append(rsResponseItems, r)
-				}
-			}
 
-			resultItem := accessRuleResponse{ID: accessRule.ID, CountyID: accessRule.County.ID, Rules: rsResponseItems}
-			response = append(response, resultItem)
-		}
+	var requestData createUINOverrideRequest
+	err = json.Unmarshal(data, &requestData)
+	if err != nil {
+		log.Printf("Error on unmarshal the create an uin override request data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
-	if len(response) == 0 {
-		response = make([]accessRuleResponse, 0)
+
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating create an uin override data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	audit := requestData.Audit
+	uin := requestData.UIN
+	interval := requestData.Interval
+	category := requestData.Category
+	expiration := requestData.Expiration
+
+	uinOverride, err := h.app.Administration.CreateUINOverride(r.Context(), current, group, audit, uin, interval, category, expiration)
+	if err != nil {
+		log.Println(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
-	data, err := json.Marshal(response)
+
+	data, err = json.Marshal(uinOverride)
 	if err != nil {
-		log.Println("Error on marshal the access rules items")
+		log.Println("Error on marshal an uin override")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
@@ -3778,49 +5539,45 @@ func (h AdminApisHandler) GetAccessRules(current model.User, group string, w htt
 	w.Write(data)
 }
 
-type updateAccessRuleRequest struct {
-	Audit    *string                       `json:"audit"`
-	CountyID string                        `json:"county_id" validate:"required,uuid"`
-	Rules    []updateAccessRuleItemRequest `json:"rules" validate:"required,dive"`
-} //@name updateAccessRuleRequest
-
-type updateAccessRuleItemRequest struct {
-	CountyStatusID string `json:"county_status_id" validate:"required"`
-	Value          string `json:"value" validate:"required,oneof=granted denied"`
-} // @name updateAccessRuleItemRequest
+type updateUINOverrideRequest struct {
+	Audit      *string    `json:"audit"`
+	Interval   int        `json:"interval" validate:"required"`
+	Category   *string    `json:"category"`
+	Expiration *time.Time `json:"expiration"`
+} // @name updateUINOverrideRequest
 
-//UpdateAccessRule updates an access rule
-// @Description Updates an access rule.
+//UpdateUINOverride updates uin override
+// @Description Updates uin override.
 // @Tags Admin
-// @ID UpdateAccessRule
+// @ID UpdateUINOverride
 // @Accept json
 // @Produce json
-// @Param data body updateAccessRuleRequest true "body data"
-// @Param id path string true "ID"
-// @Success 200 {object} accessRuleResponse
+// @Param data body updateUINOverrideRequest true "body data"
+// @Param uin path string true "UIN"
+// @Success 200 {object} string
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
-// @Router /admin/access-rules/{id} [put]
-func (h AdminApisHandler) UpdateAccessRule(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+// @Router /admin/uin-overrides/uin/{uin} [put]
+func (h AdminApisHandler) UpdateUINOverride(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
-	ID := params["id"]
-	if len(ID) <= 0 {
-		log.Println("Access rule id is required")
-		http.Error(w, "Access rule id is required", http.StatusBadRequest)
+	uin := params["uin"]
+	if len(uin) <= 0 {
+		log.Println("uin is required")
+		http.Error(w, "uin is required", http.StatusBadRequest)
 		return
 	}
 
 	data, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		log.Printf("Error on marshal the update access rule item - %s\n", err.Error())
+		log.Printf("Error on marshal the update uin override item - %s\n", err.Error())
 		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		return
 	}
 
-	var requestData updateAccessRuleRequest
+	var requestData updateUINOverrideRequest
 	err = json.Unmarshal(data, &requestData)
 	if err != nil {
-		log.Printf("Error on unmarshal the update access rule item request data - %s\n", err.Error())
+		log.Printf("Error on unmarshal the update uin override item request data - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -3829,42 +5586,26 @@ func (h AdminApisHandler) UpdateAccessRule(current model.User, group string, w h
 	validate := validator.New()
 	err = validate.Struct(requestData)
 	if err != nil {
-		log.Printf("Error on validating update an access rule data - %s\n", err.Error())
+		log.Printf("Error on validating update an uin override data - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	audit := requestData.Audit
-	countyID := requestData.CountyID
-	rules := requestData.Rules
-
-	var arRules []model.AccessRuleCountyStatus
-	if rules != nil {
-		for _, ar := range rules {
-			r := model.AccessRuleCountyStatus{CountyStatusID: ar.CountyStatusID, Value: ar.Value}
-			arRules = append(arRules, r)
-		}
-	}
+	interval := requestData.Interval
+	category := requestData.Category
+	expiration := requestData.Expiration
 
-	accessRule, err := h.app.Administration.UpdateAccessRule(current, group, audit, ID, countyID, arRules)
+	uinOverride, err := h.app.Administration.UpdateUINOverride(r.Context(), current, group, audit, uin, interval, category, expiration)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	var rsResponseItems []accessRuleItemResponse
-	if accessRule.Rules != nil {
-		for _, item := range accessRule.Rules {
-			r := accessRuleItemResponse{CountyStatusID: item.CountyStatusID, Value: item.Value}
-			rsResponseItems = append(rsResponseItems, r)
-		}
-	}
-
-	resultItem := accessRuleResponse{ID: accessRule.ID, CountyID: accessRule.County.ID, Rules: rsResponseItems}
-	data, err = json.Marshal(resultItem)
+	data, err = json.Marshal(uinOverride)
 	if err != nil {
-		log.Println("Error on marshal an access rule")
+		log.Println("Error on marshal an uin override")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
@@ -3874,25 +5615,25 @@ func (h AdminApisHandler) UpdateAccessRule(current model.User, group string, w h
 	w.Write(data)
 }
 
-//DeleteAccessRule deletes an access rule
-// @Description Deletes an access rule
+//DeleteUINOverride deletes an uin override
+// @Description Deletes an uin override
 // @Tags Admin
-// @ID deleteAccessRule
+// @ID DeleteUINOverride
 // @Accept plain
-// @Param id path string true "ID"
+// @Param uin path string true "UIN"
 // @Success 200 {object} string "Successfuly deleted"
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
-// @Router /admin/access-rules/{id} [delete]
-func (h AdminApisHandler) DeleteAccessRule(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+// @Router /admin/uin-overrides/uin/{uin} [delete]
+func (h AdminApisHandler) DeleteUINOverride(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
-	ID := params["id"]
-	if len(ID) <= 0 {
-		log.Println("Access rule id is required")
-		http.Error(w, "Access rule id is required", http.StatusBadRequest)
+	uin := params["uin"]
+	if len(uin) <= 0 {
+		log.Println("uin is required")
+		http.Error(w, "uin is required", http.StatusBadRequest)
 		return
 	}
-	err := h.app.Administration.DeleteAccessRule(current, group, ID)
+	err := h.app.Administration.DeleteUINOverride(r.Context(), current, group, uin)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -3904,176 +5645,308 @@ func (h AdminApisHandler) DeleteAccessRule(current model.User, group string, w h
 	w.Write([]byte("Successfully deleted"))
 }
 
-//GetCRules gets the rules
-// @Description Gives the rules
+//GetUserByExternalID gets the user by external id
+// @Description Gets the user by external id.
 // @Tags Admin
-// @ID GetCRules
+// @ID GetUserByExternalID
 // @Accept json
-// @Param county-id query string false "County ID"
-// @Param app-version query string false "App version"
-// @Success 200 {object} string
+// @Param external-id query string true "External ID"
+// @Success 200 {object} AppUserResponse
+// @Failure 400 {object} string "Authentication error"
+// @Failure 404 {object} string "Not Found"
+// @Failure 500 {object} string "Internal Server error"
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
-// @Router /admin/crules [get]
-func (h AdminApisHandler) GetCRules(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	countyKeys, ok := r.URL.Query()["county-id"]
-	if !ok || len(countyKeys[0]) < 1 {
-		log.Println("url param 'county-id' is missing")
-		return
-	}
-	appVersionKeys, ok := r.URL.Query()["app-version"]
-	if !ok || len(appVersionKeys[0]) < 1 {
-		log.Println("url param 'app-version' is missing")
+// @Router /admin/user [get]
+func (h AdminApisHandler) GetUserByExternalID(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	keys, ok := r.URL.Query()["external-id"]
+	if !ok || len(keys[0]) < 1 {
+		log.Println("url param 'external-id' is missing")
 		return
 	}
-	countyID := countyKeys[0]
-	appVersion := appVersionKeys[0]
+	externalID := keys[0]
 
-	cRules, err := h.app.Administration.GetCRules(countyID, appVersion)
+	user, err := h.app.Administration.GetUserByExternalID(r.Context(), externalID)
 	if err != nil {
-		log.Printf("Error on getting crules - %s", err)
+		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	if cRules == nil {
+	if user == nil {
 		//not found
 		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
 		return
 	}
+	responseUser := AppUserResponse{ID: user.ID, UUID: user.UUID, PublicKey: user.PublicKey,
+		Consent: user.Consent, ExposureNotification: user.ExposureNotification, RePost: user.RePost,
+		EncryptedKey: user.EncryptedKey, EncryptedBlob: user.EncryptedBlob}
+	data, err := json.Marshal(responseUser)
+	if err != nil {
+		log.Println("Error on marshal the test type result items")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+type createActionRequest struct {
+	Audit         *string `json:"audit"`
+	ProviderID    string  `json:"provider_id" validate:"required"`
+	UserID        string  `json:"user_id" validate:"required"`
+	EncryptedKey  string  `json:"encrypted_key" validate:"required"`
+	EncryptedBlob string  `json:"encrypted_blob" validate:"required"`
+} // @name createActionRequest
+
+//CreateAction creates an action
+// @Description Creates an action
+// @Tags Admin
+// @ID CreateAction
+// @Accept json
+// @Produce json
+// @Param data body createActionRequest true "body data"
+// @Success 200 {object} model.CTest
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/actions [post]
+func (h ApisHandler) CreateAction(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal create an action - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var requestData createActionRequest
+	err = json.Unmarshal(data, &requestData)
+	if err != nil {
+		log.Printf("Error on unmarshal the create action request data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating create action data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	audit := requestData.Audit
+	providerID := requestData.ProviderID
+	userID := requestData.UserID
+	encryptedKey := requestData.EncryptedKey
+	encryptedBlob := requestData.EncryptedBlob
+
+	item, err := h.app.Administration.CreateAction(r.Context(), current, group, audit, providerID, userID, encryptedKey, encryptedBlob)
+	if err != nil {
+		log.Printf("Error on creating an action - %s\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data, err = json.Marshal(item)
+	if err != nil {
+		log.Println("Error on marshal an action")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//GetAudit gets the audilt/log history
+// @Description Gives the audilt/log history
+// @Tags Admin
+// @ID GetAudit
+// @Accept json
+// @Param user-identifier query string false "User identifier"
+// @Param entity query string false "Entity"
+// @Param entity-id query string false "Entity ID"
+// @Param operation query string false "Operation"
+// @Param client-data query string false "Client data"
+// @Param created-at query string false "Created At"
+// @Param sort query string false "Sort By"
+// @Param asc query string false "Ascending"
+// @Param limit query string false "Limit"
+// @Success 200 {array} core.AuditEntity
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/audit [get]
+func (h ApisHandler) GetAudit(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+
+	//user identifier
+	var userIdentifier *string
+	userIdentifierKeys, ok := r.URL.Query()["user-identifier"]
+	if ok {
+		userIdentifier = &userIdentifierKeys[0]
+	}
+
+	//entity
+	var entity *string
+	entityKeys, ok := r.URL.Query()["entity"]
+	if ok {
+		entity = &entityKeys[0]
+	}
 
-	data := []byte(cRules.Data)
+	//entity id
+	var entityID *string
+	entityIDKeys, ok := r.URL.Query()["entity-id"]
+	if ok {
+		entityID = &entityIDKeys[0]
+	}
 
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	w.WriteHeader(http.StatusOK)
-	w.Write(data)
-}
+	//operation
+	var operation *string
+	operationKeys, ok := r.URL.Query()["operation"]
+	if ok {
+		operation = &operationKeys[0]
+	}
 
-type createOrUpdateCRulesRequest struct {
-	Audit      *string `json:"audit"`
-	AppVersion string  `json:"app_version" validate:"required"`
-	CountyID   string  `json:"county_id" validate:"required"`
-	Data       string  `json:"data" validate:"required"`
-} //@name createOrUpdateCRulesRequest
+	//client data
+	var clientData *string
+	clientDataKeys, ok := r.URL.Query()["client-data"]
+	if ok {
+		clientData = &clientDataKeys[0]
+	}
 
-//CreateOrUpdateCRules creates rules, updates them if already created
-// @Description Creates rules, updates them if already created.
-// @Tags Admin
-// @ID CreateOrUpdateCRules
-// @Accept json
-// @Produce json
-// @Param data body createOrUpdateCRulesRequest true "body data"
-// @Success 200 {object} string
-// @Security AdminUserAuth
-// @Security AdminGroupAuth
-// @Router /admin/crules [put]
-func (h AdminApisHandler) CreateOrUpdateCRules(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	bodyData, err := ioutil.ReadAll(r.Body)
-	if err != nil {
-		log.Printf("Error on marshal the update crules items  - %s\n", err.Error())
-		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
-		return
+	//created at
+	var createdAt *time.Time
+	createdAtKeys, ok := r.URL.Query()["created-at"]
+	if ok {
+		createdAtValue := &createdAtKeys[0]
+		layout := "2006-01-02T15:04:05.000Z"
+		t, err := time.Parse(layout, *createdAtValue)
+		if err == nil {
+			createdAt = &t
+		} else {
+			log.Printf("error parsing date - %s\n", err)
+		}
 	}
 
-	var requestData createOrUpdateCRulesRequest
-	err = json.Unmarshal(bodyData, &requestData)
-	if err != nil {
-		log.Printf("Error on unmarshal the update crules items request data - %s\n", err.Error())
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+	//sort by
+	var sort *string
+	sortByKeys, ok := r.URL.Query()["sort"]
+	if ok {
+		sort = &sortByKeys[0]
 	}
 
-	//validate
-	validate := validator.New()
-	err = validate.Struct(requestData)
-	if err != nil {
-		log.Printf("Error on validating update crules data - %s\n", err.Error())
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+	//asc
+	var asc *bool
+	ascKeys, ok := r.URL.Query()["asc"]
+	if ok {
+		ascValue, err := strconv.ParseBool(ascKeys[0])
+		if err == nil {
+			asc = &ascValue
+		} else {
+			log.Printf("error parsing asc - %s\n", err)
+		}
 	}
 
-	audit := requestData.Audit
-	appVersion := requestData.AppVersion
-	countyID := requestData.CountyID
-	data := requestData.Data
+	//limit
+	var limit *int64
+	limitKeys, ok := r.URL.Query()["limit"]
+	if ok {
+		limitValue, err := strconv.ParseInt(limitKeys[0], 10, 64)
+		if err == nil {
+			limit = &limitValue
+		} else {
+			log.Printf("error parsing limit - %s\n", err)
+		}
+	}
 
-	err = h.app.Administration.CreateOrUpdateCRules(current, group, audit, countyID, appVersion, data)
+	items, err := h.app.Administration.GetAudit(r.Context(), current, group, userIdentifier, entity, entityID, operation, clientData, createdAt, sort, asc, limit)
 	if err != nil {
 		log.Println(err.Error())
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if len(items) == 0 {
+		items = make([]*core.AuditEntity, 0)
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		log.Println("Error on marshal the audit items")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
 
-	w.Header().Set("Content-Type", "text/plain")
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("Successfully processed"))
+	w.Write(data)
 }
 
-//GetSymptoms gets the symptoms
-// @Description Gives the symptoms
+//GetRetentionPolicies gives the retention policies
+// @Description Gives the retention policies for all supported entities - ehistory, ctest, emanualtest-image, trace-exposure, uin-override and audit. The entities without a policy are kept forever.
 // @Tags Admin
-// @ID GetASymptoms
-// @Accept json
-// @Param app-version query string false "App version"
-// @Success 200 {object} string
+// @ID GetRetentionPolicies
+// @Accept  json
+// @Success 200 {array} model.RetentionPolicy
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
-// @Router /admin/symptoms [get]
-func (h AdminApisHandler) GetSymptoms(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	appVersionKeys, ok := r.URL.Query()["app-version"]
-	if !ok || len(appVersionKeys[0]) < 1 {
-		log.Println("url param 'app-version' is missing")
-		return
-	}
-	appVersion := appVersionKeys[0]
-
-	symptoms, err := h.app.Administration.GetSymptoms(appVersion)
+// @Router /admin/retention-policies [get]
+func (h AdminApisHandler) GetRetentionPolicies(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	policies, err := h.app.Administration.GetRetentionPolicies(r.Context())
 	if err != nil {
-		log.Printf("Error on getting symptoms - %s", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Printf("Error on getting the retention policies - %s\n", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
-	if symptoms == nil {
-		//not found
-		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	data, err := json.Marshal(policies)
+	if err != nil {
+		log.Println("Error on marshal the retention policies")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
 
-	data := []byte(symptoms.Items)
-
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	w.Write(data)
 }
 
-type createOrUpdateSymptomsRequest struct {
-	Audit      *string `json:"audit"`
-	AppVersion string  `json:"app_version" validate:"required"`
-	Items      string  `json:"items" validate:"required"`
-} //@name createOrUpdateSymptomsRequest
+type updateRetentionPolicyRequest struct {
+	Audit   *string `json:"audit"`
+	Days    int     `json:"days" validate:"required,min=1"`
+	Enabled bool    `json:"enabled"`
+} // @name updateRetentionPolicyRequest
 
-//CreateOrUpdateSymptoms creates symptoms or update them if already created
-// @Description Creates symptoms or update them if already created.
+//UpdateRetentionPolicy updates the retention policy for an entity
+// @Description Updates the retention policy for an entity. The records older than the provided days are purged by a daily job and every run is audited as "retention-run". The audit policy never purges the "retention-run" and "erasure-receipt" entries.
 // @Tags Admin
-// @ID CreateorUpdateSymptoms
+// @ID UpdateRetentionPolicy
 // @Accept json
 // @Produce json
-// @Param data body createOrUpdateSymptomsRequest true "body data"
-// @Success 200 {string} Successfully processed
+// @Param data body updateRetentionPolicyRequest true "body data"
+// @Param entity path string true "Entity"
+// @Success 200 {object} model.RetentionPolicy
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
-// @Router /admin/symptoms [put]
-func (h AdminApisHandler) CreateOrUpdateSymptoms(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	bodyData, err := ioutil.ReadAll(r.Body)
+// @Router /admin/retention-policies/{entity} [put]
+func (h AdminApisHandler) UpdateRetentionPolicy(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	entity := params["entity"]
+	if len(entity) <= 0 {
+		log.Println("Entity is required")
+		http.Error(w, "Entity is required", http.StatusBadRequest)
+		return
+	}
+	data, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		log.Printf("Error on marshal the update symptoms items  - %s\n", err.Error())
+		log.Printf("Error on marshal the update retention policy - %s\n", err.Error())
 		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		return
 	}
 
-	var requestData createOrUpdateSymptomsRequest
-	err = json.Unmarshal(bodyData, &requestData)
+	var requestData updateRetentionPolicyRequest
+	err = json.Unmarshal(data, &requestData)
 	if err != nil {
-		log.Printf("Error on unmarshal the update symptoms items request data - %s\n", err.Error())
+		log.Printf("Error on unmarshal the update retention policy request data - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -4082,66 +5955,58 @@ func (h AdminApisHandler) CreateOrUpdateSymptoms(current model.User, group strin
 	validate := validator.New()
 	err = validate.Struct(requestData)
 	if err != nil {
-		log.Printf("Error on validating update symptoms data - %s\n", err.Error())
+		log.Printf("Error on validating update retention policy data - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	audit := requestData.Audit
-	appVersion := requestData.AppVersion
-	items := requestData.Items
-
-	err = h.app.Administration.CreateOrUpdateSymptoms(current, group, audit, appVersion, items)
+	policy, err := h.app.Administration.UpdateRetentionPolicy(r.Context(), current, group, audit, entity, requestData.Days, requestData.Enabled)
 	if err != nil {
-		log.Println(err.Error())
+		log.Printf("Error on updating the retention policy - %s\n", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	data, err = json.Marshal(policy)
+	if err != nil {
+		log.Println("Error on marshal the retention policy")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
 
-	w.Header().Set("Content-Type", "text/plain")
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("Successfully processed"))
+	w.Write(data)
 }
 
-//GetUINOverrides gives uin override items
-// @Description Gives uin override items
+//ExportCountyBundle exports the complete configuration of a county
+// @Description Exports the county with its statuses, guidelines, test types rules, access rule, symptom rule, crules and locations as a self-contained bundle. The county statuses are referred by ref, the test types and the providers by name.
 // @Tags Admin
-// @ID GetUINOverrides
+// @ID ExportCountyBundle
 // @Accept json
-// @Param uin query string false "UIN"
-// @Param sort query string false "Sort by uin or category"
-// @Success 200 {array} model.UINOverride
+// @Param id path string true "County ID"
+// @Success 200 {object} model.CountyBundle
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
-// @Router /admin/uin-overrides [get]
-func (h AdminApisHandler) GetUINOverrides(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	//uin
-	var uin *string
-	uinKeys, ok := r.URL.Query()["uin"]
-	if ok && len(uinKeys[0]) > 0 {
-		uin = &uinKeys[0]
-	}
-
-	//sort by
-	var sort *string
-	sortByKeys, ok := r.URL.Query()["sort"]
-	if ok {
-		sort = &sortByKeys[0]
+// @Router /admin/counties/{id}/bundle [get]
+func (h AdminApisHandler) ExportCountyBundle(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	ID := params["id"]
+	if len(ID) <= 0 {
+		log.Println("County id is required")
+		http.Error(w, "County id is required", http.StatusBadRequest)
+		return
 	}
 
-	uinOverrides, err := h.app.Administration.GetUINOverrides(uin, sort)
+	bundle, err := h.app.Administration.ExportCountyBundle(r.Context(), ID)
 	if err != nil {
-		log.Println("Error on getting the uin overrides items")
+		log.Printf("Error on exporting the county bundle - %s\n", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-
-	if len(uinOverrides) == 0 {
-		uinOverrides = make([]*model.UINOverride, 0)
-	}
-	data, err := json.Marshal(uinOverrides)
+	data, err := json.Marshal(bundle)
 	if err != nil {
-		log.Println("Error on marshal the uin overrides items")
+		log.Println("Error on marshal the county bundle")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
@@ -4151,37 +6016,35 @@ func (h AdminApisHandler) GetUINOverrides(current model.User, group string, w ht
 	w.Write(data)
 }
 
-type createUINOverrideRequest struct {
-	Audit      *string    `json:"audit"`
-	UIN        string     `json:"uin" validate:"required"`
-	Interval   int        `json:"interval" validate:"required"`
-	Category   *string    `json:"category"`
-	Expiration *time.Time `json:"expiration"`
-} // @name createUINOverrideRequest
+type importCountyBundleRequest struct {
+	Audit  *string            `json:"audit"`
+	Name   *string            `json:"name"` //overrides the county name from the bundle
+	Bundle model.CountyBundle `json:"bundle"`
+} // @name importCountyBundleRequest
 
-//CreateUINOverride creates an uin override
-// @Description Creates an uin override.
+//ImportCountyBundle creates a county from a bundle
+// @Description Creates a new county with all its configuration from a bundle. New ids are generated for all entities. The crules are validated and published as revisions. The bundle is validated first and all problems are returned together - nothing is created if there is any of them. Use the apply API for an existing county.
 // @Tags Admin
-// @ID CreateUINOverride
+// @ID ImportCountyBundle
 // @Accept json
 // @Produce json
-// @Param data body createUINOverrideRequest true "body data"
-// @Success 200 {object} model.UINOverride
+// @Param data body importCountyBundleRequest true "body data"
+// @Success 200 {object} createCountyResponse
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
-// @Router /admin/uin-overrides [post]
-func (h AdminApisHandler) CreateUINOverride(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+// @Router /admin/counties/bundle [post]
+func (h AdminApisHandler) ImportCountyBundle(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	data, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		log.Printf("Error on marshal create uin override - %s\n", err.Error())
+		log.Printf("Error on marshal the import county bundle - %s\n", err.Error())
 		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		return
 	}
 
-	var requestData createUINOverrideRequest
+	var requestData importCountyBundleRequest
 	err = json.Unmarshal(data, &requestData)
 	if err != nil {
-		log.Printf("Error on unmarshal the create an uin override request data - %s\n", err.Error())
+		log.Printf("Error on unmarshal the import county bundle request data - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -4190,27 +6053,23 @@ func (h AdminApisHandler) CreateUINOverride(current model.User, group string, w
 	validate := validator.New()
 	err = validate.Struct(requestData)
 	if err != nil {
-		log.Printf("Error on validating create an uin override data - %s\n", err.Error())
+		log.Printf("Error on validating import county bundle data - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	audit := requestData.Audit
-	uin := requestData.UIN
-	interval := requestData.Interval
-	category := requestData.Category
-	expiration := requestData.Expiration
-
-	uinOverride, err := h.app.Administration.CreateUINOverride(current, group, audit, uin, interval, category, expiration)
+	county, err := h.app.Administration.ImportCountyBundle(r.Context(), current, group, audit, requestData.Bundle, requestData.Name)
 	if err != nil {
-		log.Println(err.Error())
+		log.Printf("Error on importing the county bundle - %s\n", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-
-	data, err = json.Marshal(uinOverride)
+	response := createCountyResponse{ID: county.ID, Name: county.Name,
+		StateProvince: county.StateProvince, Country: county.Country}
+	data, err = json.Marshal(response)
 	if err != nil {
-		log.Println("Error on marshal an uin override")
+		log.Println("Error on marshal a county")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
@@ -4220,73 +6079,56 @@ func (h AdminApisHandler) CreateUINOverride(current model.User, group string, w
 	w.Write(data)
 }
 
-type updateUINOverrideRequest struct {
-	Audit      *string    `json:"audit"`
-	Interval   int        `json:"interval" validate:"required"`
-	Category   *string    `json:"category"`
-	Expiration *time.Time `json:"expiration"`
-} // @name updateUINOverrideRequest
+type applyCountyBundleRequest struct {
+	Audit  *string            `json:"audit"`
+	Bundle model.CountyBundle `json:"bundle"`
+} // @name applyCountyBundleRequest
 
-//UpdateUINOverride updates uin override
-// @Description Updates uin override.
+//ApplyCountyBundle updates a county from a bundle
+// @Description Updates an existing county so it matches the bundle and gives the applied changes - the same ones the diff gives. The ids of the matched county statuses, guidelines, rules and locations are kept. The crules are validated and published as new revisions. The locations and the crules app versions which are not in the bundle are kept. Nothing is changed if the bundle is not valid.
 // @Tags Admin
-// @ID UpdateUINOverride
+// @ID ApplyCountyBundle
 // @Accept json
-// @Produce json
-// @Param data body updateUINOverrideRequest true "body data"
-// @Param uin path string true "UIN"
-// @Success 200 {object} string
+// @Produce json
+// @Param data body applyCountyBundleRequest true "body data"
+// @Param id path string true "County ID"
+// @Success 200 {array} model.BundleChange
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
-// @Router /admin/uin-overrides/uin/{uin} [put]
-func (h AdminApisHandler) UpdateUINOverride(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+// @Router /admin/counties/{id}/bundle [put]
+func (h AdminApisHandler) ApplyCountyBundle(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
-	uin := params["uin"]
-	if len(uin) <= 0 {
-		log.Println("uin is required")
-		http.Error(w, "uin is required", http.StatusBadRequest)
+	ID := params["id"]
+	if len(ID) <= 0 {
+		log.Println("County id is required")
+		http.Error(w, "County id is required", http.StatusBadRequest)
 		return
 	}
-
 	data, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		log.Printf("Error on marshal the update uin override item - %s\n", err.Error())
+		log.Printf("Error on marshal the apply county bundle - %s\n", err.Error())
 		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		return
 	}
 
-	var requestData updateUINOverrideRequest
+	var requestData applyCountyBundleRequest
 	err = json.Unmarshal(data, &requestData)
 	if err != nil {
-		log.Printf("Error on unmarshal the update uin override item request data - %s\n", err.Error())
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	//validate
-	validate := validator.New()
-	err = validate.Struct(requestData)
-	if err != nil {
-		log.Printf("Error on validating update an uin override data - %s\n", err.Error())
+		log.Printf("Error on unmarshal the apply county bundle request data - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	audit := requestData.Audit
-	interval := requestData.Interval
-	category := requestData.Category
-	expiration := requestData.Expiration
-
-	uinOverride, err := h.app.Administration.UpdateUINOverride(current, group, audit, uin, interval, category, expiration)
+	changes, err := h.app.Administration.ApplyCountyBundle(r.Context(), current, group, audit, ID, requestData.Bundle)
 	if err != nil {
-		log.Println(err.Error())
+		log.Printf("Error on applying the county bundle - %s\n", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-
-	data, err = json.Marshal(uinOverride)
+	data, err = json.Marshal(changes)
 	if err != nil {
-		log.Println("Error on marshal an uin override")
+		log.Println("Error on marshal the county bundle changes")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
@@ -4296,74 +6138,54 @@ func (h AdminApisHandler) UpdateUINOverride(current model.User, group string, w
 	w.Write(data)
 }
 
-//DeleteUINOverride deletes an uin override
-// @Description Deletes an uin override
+type diffCountyBundleRequest struct {
+	Bundle model.CountyBundle `json:"bundle"`
+} // @name diffCountyBundleRequest
+
+//DiffCountyBundle gives what will change in a county if a bundle is applied
+// @Description Compares a county bundle with the current county configuration - county statuses, guidelines and their items, test types rules and their results states, access rule values, symptom rule, the referred test types and results, crules data and locations. Nothing is changed, it gives a list of add, modify and delete changes for review.
 // @Tags Admin
-// @ID DeleteUINOverride
-// @Accept plain
-// @Param uin path string true "UIN"
-// @Success 200 {object} string "Successfuly deleted"
+// @ID DiffCountyBundle
+// @Accept json
+// @Produce json
+// @Param data body diffCountyBundleRequest true "body data"
+// @Param id path string true "County ID"
+// @Success 200 {array} model.BundleChange
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
-// @Router /admin/uin-overrides/uin/{uin} [delete]
-func (h AdminApisHandler) DeleteUINOverride(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+// @Router /admin/counties/{id}/bundle/diff [post]
+func (h AdminApisHandler) DiffCountyBundle(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
-	uin := params["uin"]
-	if len(uin) <= 0 {
-		log.Println("uin is required")
-		http.Error(w, "uin is required", http.StatusBadRequest)
+	ID := params["id"]
+	if len(ID) <= 0 {
+		log.Println("County id is required")
+		http.Error(w, "County id is required", http.StatusBadRequest)
 		return
 	}
-	err := h.app.Administration.DeleteUINOverride(current, group, uin)
+	data, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		log.Println(err.Error())
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Printf("Error on marshal the diff county bundle - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		return
 	}
 
-	w.Header().Set("Content-Type", "text/plain")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("Successfully deleted"))
-}
-
-//GetUserByExternalID gets the user by external id
-// @Description Gets the user by external id.
-// @Tags Admin
-// @ID GetUserByExternalID
-// @Accept json
-// @Param external-id query string true "External ID"
-// @Success 200 {object} AppUserResponse
-// @Failure 400 {object} string "Authentication error"
-// @Failure 404 {object} string "Not Found"
-// @Failure 500 {object} string "Internal Server error"
-// @Security AdminUserAuth
-// @Security AdminGroupAuth
-// @Router /admin/user [get]
-func (h AdminApisHandler) GetUserByExternalID(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	keys, ok := r.URL.Query()["external-id"]
-	if !ok || len(keys[0]) < 1 {
-		log.Println("url param 'external-id' is missing")
+	var requestData diffCountyBundleRequest
+	err = json.Unmarshal(data, &requestData)
+	if err != nil {
+		log.Printf("Error on unmarshal the diff county bundle request data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	externalID := keys[0]
 
-	user, err := h.app.Administration.GetUserByExternalID(externalID)
+	changes, err := h.app.Administration.DiffCountyBundle(r.Context(), ID, requestData.Bundle)
 	if err != nil {
-		log.Println(err.Error())
+		log.Printf("Error on diff the county bundle - %s\n", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	if user == nil {
-		//not found
-		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
-		return
-	}
-	responseUser := AppUserResponse{ID: user.ID, UUID: user.UUID, PublicKey: user.PublicKey,
-		Consent: user.Consent, ExposureNotification: user.ExposureNotification, RePost: user.RePost,
-		EncryptedKey: user.EncryptedKey, EncryptedBlob: user.EncryptedBlob}
-	data, err := json.Marshal(responseUser)
+	data, err = json.Marshal(changes)
 	if err != nil {
-		log.Println("Error on marshal the test type result items")
+		log.Println("Error on marshal the county bundle changes")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
@@ -4373,37 +6195,50 @@ func (h AdminApisHandler) GetUserByExternalID(current model.User, group string,
 	w.Write(data)
 }
 
-type createActionRequest struct {
-	Audit         *string `json:"audit"`
-	ProviderID    string  `json:"provider_id" validate:"required"`
-	UserID        string  `json:"user_id" validate:"required"`
-	EncryptedKey  string  `json:"encrypted_key" validate:"required"`
-	EncryptedBlob string  `json:"encrypted_blob" validate:"required"`
-} // @name createActionRequest
-
-//CreateAction creates an action
-// @Description Creates an action
+type simulateCountyStatusEventRequest struct {
+	Date     time.Time `json:"date" validate:"required"`
+	TestType *string   `json:"test_type"`
+	Result   *string   `json:"result" validate:"required_with=TestType"`
+	Symptoms []string  `json:"symptoms"`
+} // @name simulateCountyStatusEventRequest
+
+type simulateCountyStatusRequest struct {
+	RulesDraft *model.RulesDraft                  `json:"rules_draft"`
+	Timeline   []simulateCountyStatusEventRequest `json:"timeline" validate:"required,min=1,dive"`
+	Points     []time.Time                        `json:"points"`
+} // @name simulateCountyStatusRequest
+
+//SimulateCountyStatus simulates the county status over a timeline
+// @Description Evaluates the county status and the access over a timeline of hypothetical test results and symptom reports. The rules draft parts - rules, access rule and symptom rule, replace the current county ones if provided. The test types, the results and the county statuses are given by id or by name. The status is evaluated at every event date, at every provided point and when the status given by a test result expires. Nothing is stored.
 // @Tags Admin
-// @ID CreateAction
+// @ID SimulateCountyStatus
 // @Accept json
 // @Produce json
-// @Param data body createActionRequest true "body data"
-// @Success 200 {object} model.CTest
+// @Param data body simulateCountyStatusRequest true "body data"
+// @Param id path string true "County ID"
+// @Success 200 {array} model.SimulationStep
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
-// @Router /admin/actions [post]
-func (h ApisHandler) CreateAction(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+// @Router /admin/counties/{id}/status-simulation [post]
+func (h AdminApisHandler) SimulateCountyStatus(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	ID := params["id"]
+	if len(ID) <= 0 {
+		log.Println("County id is required")
+		http.Error(w, "County id is required", http.StatusBadRequest)
+		return
+	}
 	data, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		log.Printf("Error on marshal create an action - %s\n", err.Error())
+		log.Printf("Error on marshal the simulate county status - %s\n", err.Error())
 		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		return
 	}
 
-	var requestData createActionRequest
+	var requestData simulateCountyStatusRequest
 	err = json.Unmarshal(data, &requestData)
 	if err != nil {
-		log.Printf("Error on unmarshal the create action request data - %s\n", err.Error())
+		log.Printf("Error on unmarshal the simulate county status request data - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -4412,27 +6247,25 @@ func (h ApisHandler) CreateAction(current model.User, group string, w http.Respo
 	validate := validator.New()
 	err = validate.Struct(requestData)
 	if err != nil {
-		log.Printf("Error on validating create action data - %s\n", err.Error())
+		log.Printf("Error on validating simulate county status data - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	audit := requestData.Audit
-	providerID := requestData.ProviderID
-	userID := requestData.UserID
-	encryptedKey := requestData.EncryptedKey
-	encryptedBlob := requestData.EncryptedBlob
+	timeline := make([]model.SimulationEvent, len(requestData.Timeline))
+	for i, event := range requestData.Timeline {
+		timeline[i] = model.SimulationEvent{Date: event.Date, TestType: event.TestType, Result: event.Result, Symptoms: event.Symptoms}
+	}
 
-	item, err := h.app.Administration.CreateAction(current, group, audit, providerID, userID, encryptedKey, encryptedBlob)
+	steps, err := h.app.Administration.SimulateCountyStatus(r.Context(), ID, requestData.RulesDraft, timeline, requestData.Points)
 	if err != nil {
-		log.Printf("Error on creating an action - %s\n", err)
+		log.Printf("Error on simulating the county status - %s\n", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-
-	data, err = json.Marshal(item)
+	data, err = json.Marshal(steps)
 	if err != nil {
-		log.Println("Error on marshal an action")
+		log.Println("Error on marshal the county status simulation")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
@@ -4442,128 +6275,89 @@ func (h ApisHandler) CreateAction(current model.User, group string, w http.Respo
 	w.Write(data)
 }
 
-//GetAudit gets the audilt/log history
-// @Description Gives the audilt/log history
+//NewAdminApisHandler creates new admin rest Handler instance
+func NewAdminApisHandler(app *core.Application) AdminApisHandler {
+	return AdminApisHandler{app: app}
+}
+
+//GetErasureReceipts gives the erasure receipts for an uin
+// @Description Gives the receipts for the erasures of the user data for an uin. The receipts do not keep the uin, they are found by its hash.
 // @Tags Admin
-// @ID GetAudit
+// @ID GetErasureReceipts
 // @Accept json
-// @Param user-identifier query string false "User identifier"
-// @Param entity query string false "Entity"
-// @Param entity-id query string false "Entity ID"
-// @Param operation query string false "Operation"
-// @Param client-data query string false "Client data"
-// @Param created-at query string false "Created At"
-// @Param sort query string false "Sort By"
-// @Param asc query string false "Ascending"
-// @Param limit query string false "Limit"
-// @Success 200 {array} core.AuditEntity
+// @Param uin query string true "UIN"
+// @Success 200 {array} model.ErasureReceipt
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
-// @Router /admin/audit [get]
-func (h ApisHandler) GetAudit(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-
-	//user identifier
-	var userIdentifier *string
-	userIdentifierKeys, ok := r.URL.Query()["user-identifier"]
-	if ok {
-		userIdentifier = &userIdentifierKeys[0]
-	}
-
-	//entity
-	var entity *string
-	entityKeys, ok := r.URL.Query()["entity"]
-	if ok {
-		entity = &entityKeys[0]
-	}
-
-	//entity id
-	var entityID *string
-	entityIDKeys, ok := r.URL.Query()["entity-id"]
-	if ok {
-		entityID = &entityIDKeys[0]
-	}
-
-	//operation
-	var operation *string
-	operationKeys, ok := r.URL.Query()["operation"]
-	if ok {
-		operation = &operationKeys[0]
-	}
-
-	//client data
-	var clientData *string
-	clientDataKeys, ok := r.URL.Query()["client-data"]
-	if ok {
-		clientData = &clientDataKeys[0]
+// @Router /admin/erasures [get]
+func (h AdminApisHandler) GetErasureReceipts(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	uinKeys, ok := r.URL.Query()["uin"]
+	if !ok || len(uinKeys[0]) < 1 {
+		log.Println("url param 'uin' is missing")
+		http.Error(w, "url param 'uin' is missing", http.StatusBadRequest)
+		return
 	}
 
-	//created at
-	var createdAt *time.Time
-	createdAtKeys, ok := r.URL.Query()["created-at"]
-	if ok {
-		createdAtValue := &createdAtKeys[0]
-		layout := "2006-01-02T15:04:05.000Z"
-		t, err := time.Parse(layout, *createdAtValue)
-		if err == nil {
-			createdAt = &t
-		} else {
-			log.Printf("error parsing date - %s\n", err)
-		}
+	receipts, err := h.app.Administration.GetErasureReceiptsByUIN(r.Context(), uinKeys[0])
+	if err != nil {
+		log.Printf("Error on getting the erasure receipts - %s\n", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
 	}
 
-	//sort by
-	var sort *string
-	sortByKeys, ok := r.URL.Query()["sort"]
-	if ok {
-		sort = &sortByKeys[0]
+	data, err := json.Marshal(receipts)
+	if err != nil {
+		log.Println("Error on marshal the erasure receipts")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
 	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
 
-	//asc
-	var asc *bool
-	ascKeys, ok := r.URL.Query()["asc"]
-	if ok {
-		ascValue, err := strconv.ParseBool(ascKeys[0])
-		if err == nil {
-			asc = &ascValue
-		} else {
-			log.Printf("error parsing asc - %s\n", err)
-		}
-	}
+type getErasureReceiptResponse struct {
+	model.ErasureReceipt
+	SignatureValid bool `json:"signature_valid"`
+} // @name getErasureReceiptResponse
 
-	//limit
-	var limit *int64
-	limitKeys, ok := r.URL.Query()["limit"]
-	if ok {
-		limitValue, err := strconv.ParseInt(limitKeys[0], 10, 64)
-		if err == nil {
-			limit = &limitValue
-		} else {
-			log.Printf("error parsing limit - %s\n", err)
-		}
+//GetErasureReceipt gives an erasure receipt with its signature check
+// @Description Gives an erasure receipt. The signature is checked against the service signing key - it is not valid if the receipt has been changed after the erasure.
+// @Tags Admin
+// @ID GetErasureReceipt
+// @Accept json
+// @Param id path string true "Receipt ID"
+// @Success 200 {object} getErasureReceiptResponse
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/erasures/{id} [get]
+func (h AdminApisHandler) GetErasureReceipt(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	ID := params["id"]
+	if len(ID) <= 0 {
+		log.Println("id is required")
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return
 	}
 
-	items, err := h.app.Administration.GetAudit(current, group, userIdentifier, entity, entityID, operation, clientData, createdAt, sort, asc, limit)
+	receipt, valid, err := h.app.Administration.GetErasureReceipt(r.Context(), ID)
 	if err != nil {
-		log.Println(err.Error())
+		log.Printf("Error on getting the erasure receipt - %s\n", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
-	if len(items) == 0 {
-		items = make([]*core.AuditEntity, 0)
+	if receipt == nil {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
 	}
-	data, err := json.Marshal(items)
+
+	data, err := json.Marshal(getErasureReceiptResponse{ErasureReceipt: *receipt, SignatureValid: valid})
 	if err != nil {
-		log.Println("Error on marshal the audit items")
+		log.Println("Error on marshal the erasure receipt")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
-
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	w.Write(data)
 }
-
-//NewAdminApisHandler creates new admin rest Handler instance
-func NewAdminApisHandler(app *core.Application) AdminApisHandler {
-	return AdminApisHandler{app: app}
-}
@@ -18,6 +18,7 @@
 package rest
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -60,7 +61,7 @@ func (h ApisHandler) Version(w http.ResponseWriter, r *http.Request) {
 // @Security AppUserAuth
 // @Router /covid19/user/clear [get]
 func (h ApisHandler) ClearUserData(current model.User, w http.ResponseWriter, r *http.Request) {
-	err := h.app.Services.ClearUserData(current)
+	err := h.app.Services.ClearUserData(r.Context(), current)
 	if err != nil {
 		log.Printf("error on clearing the user data - %s", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -72,9 +73,157 @@ func (h ApisHandler) ClearUserData(current model.User, w http.ResponseWriter, r
 	w.Write([]byte("Successfully cleared"))
 }
 
+//EraseUserData erases everything in the service for that user and gives a receipt for it
+// @Description Erases everything in the service for that user - the user, ctests, histories, statuses, manual tests with their images, uin overrides and building access. The audit entries are anonymized. Gives a receipt with the erased items count per collection.
+// @Tags Covid19
+// @ID EraseUserData
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.ErasureReceipt
+// @Security AppUserAuth
+// @Router /covid19/user/erasure [post]
+func (h ApisHandler) EraseUserData(current model.User, w http.ResponseWriter, r *http.Request) {
+	receipt, err := h.app.Services.EraseUserData(r.Context(), current)
+	if err != nil {
+		log.Printf("error on erasing the user data - %s", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	data, err := json.Marshal(receipt)
+	if err != nil {
+		log.Println("Error on marshal the erasure receipt")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//GetUserErasureReceipts gives the receipts for the previous erasures of the user
+// @Description Gives the receipts for the previous erasures of the user. They are found by the user uin so they are available after the user logs in again.
+// @Tags Covid19
+// @ID GetUserErasureReceipts
+// @Accept json
+// @Success 200 {array} model.ErasureReceipt
+// @Security AppUserAuth
+// @Router /covid19/user/erasures [get]
+func (h ApisHandler) GetUserErasureReceipts(current model.User, w http.ResponseWriter, r *http.Request) {
+	receipts, err := h.app.Services.GetUserErasureReceipts(r.Context(), current)
+	if err != nil {
+		log.Printf("Error on getting the erasure receipts - %s\n", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	data, err := json.Marshal(receipts)
+	if err != nil {
+		log.Println("Error on marshal the erasure receipts")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//GetUserErasureReceipt gives an erasure receipt of the user
+// @Description Gives an erasure receipt of the user.
+// @Tags Covid19
+// @ID GetUserErasureReceipt
+// @Accept json
+// @Param id path string true "Receipt ID"
+// @Success 200 {object} model.ErasureReceipt
+// @Security AppUserAuth
+// @Router /covid19/user/erasures/{id} [get]
+func (h ApisHandler) GetUserErasureReceipt(current model.User, w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	ID := params["id"]
+	if len(ID) <= 0 {
+		log.Println("id is required")
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return
+	}
+
+	receipt, err := h.app.Services.GetUserErasureReceipt(r.Context(), current, ID)
+	if err != nil {
+		log.Printf("Error on getting the erasure receipt - %s\n", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if receipt == nil {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	data, err := json.Marshal(receipt)
+	if err != nil {
+		log.Println("Error on marshal the erasure receipt")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//ExportUserData gives everything stored in the service for that user as a zip archive
+// @Description Gives everything stored in the service for that user as a zip archive - the user, ctests with the providers names, histories, statuses for all app versions, manual tests with their images, uin overrides and building access. The data is in the same encrypted form as it is stored. The archive has a manifest.json file with the hash and the items count of every other file.
+// @Tags Covid19
+// @ID ExportUserData
+// @Produce application/zip
+// @Success 200 {file} file
+// @Security AppUserAuth
+// @Router /covid19/user/export [get]
+func (h ApisHandler) ExportUserData(current model.User, w http.ResponseWriter, r *http.Request) {
+	//write the archive in memory first so that an error does not give a broken archive
+	var archive bytes.Buffer
+	manifest, err := h.app.Services.ExportUserData(r.Context(), current, &archive)
+	if err != nil {
+		log.Printf("error on exporting the user data - %s", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	fileName := fmt.Sprintf("health-data-%s.zip", manifest.GeneratedAt.Format("20060102-150405"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+	w.WriteHeader(http.StatusOK)
+	w.Write(archive.Bytes())
+}
+
+//GetUserKeysStatus gives the user public key versions and the items encrypted for the previous versions
+// @Description Gives the user public key versions. Gives also the not processed ctests and the histories which are encrypted for a previous key - the providers re-submit the ctests, the app must re-encrypt the histories if it still has the previous private key.
+// @Tags Covid19
+// @ID GetUserKeysStatus
+// @Accept json
+// @Success 200 {object} model.UserKeysStatus
+// @Security AppUserAuth
+// @Router /covid19/user/keys [get]
+func (h ApisHandler) GetUserKeysStatus(current model.User, w http.ResponseWriter, r *http.Request) {
+	status, err := h.app.Services.GetUserKeysStatus(r.Context(), current)
+	if err != nil {
+		log.Printf("Error on getting the user keys status - %s\n", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	data, err := json.Marshal(status)
+	if err != nil {
+		log.Println("Error on marshal the user keys status")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
 type getUserByShibbolethIDResponse struct {
-	PublicKey string `json:"public_key"`
-	Consent   bool   `json:"consent"`
+	PublicKey  string `json:"public_key"`
+	KeyVersion int    `json:"key_version"`
+	Consent    bool   `json:"consent"`
 } // @name GetUserByShibbolethUINResponse
 
 //GetUserByShibbolethUIN gives the user info needed for the providers
@@ -95,7 +244,7 @@ func (h ApisHandler) GetUserByShibbolethUIN(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	user, err := h.app.Services.GetUserByShibbolethUIN(shibbolethUIN)
+	user, err := h.app.Services.GetUserByShibbolethUIN(r.Context(), shibbolethUIN)
 	if err != nil {
 		log.Printf("Error on getting user by shibboleth id %s", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -108,7 +257,7 @@ func (h ApisHandler) GetUserByShibbolethUIN(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	result := getUserByShibbolethIDResponse{PublicKey: user.PublicKey, Consent: user.Consent}
+	result := getUserByShibbolethIDResponse{PublicKey: user.PublicKey, KeyVersion: user.KeyVersion, Consent: user.Consent}
 
 	data, err := json.Marshal(result)
 	if err != nil {
@@ -130,7 +279,7 @@ func (h ApisHandler) GetUserByShibbolethUIN(w http.ResponseWriter, r *http.Reque
 // @Security ProvidersAuth
 // @Router /covid19/users/re-post [get]
 func (h ApisHandler) GetUsersForRePost(w http.ResponseWriter, r *http.Request) {
-	users, err := h.app.Services.GetUsersForRePost()
+	users, err := h.app.Services.GetUsersForRePost(r.Context())
 	if err != nil {
 		log.Printf("Error on getting users for re-post %s", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -142,7 +291,7 @@ func (h ApisHandler) GetUsersForRePost(w http.ResponseWriter, r *http.Request) {
 		result = make([]PUserResponse, 0)
 	} else {
 		for _, user := range users {
-			pUser := PUserResponse{UIN: user.ExternalID, Consent: user.Consent, PublicKey: user.PublicKey}
+			pUser := PUserResponse{UIN: user.ExternalID, Consent: user.Consent, PublicKey: user.PublicKey, KeyVersion: user.KeyVersion}
 			result = append(result, pUser)
 		}
 	}
@@ -163,6 +312,7 @@ type createCTestRequest struct {
 	UIN           string  `json:"uin" validate:"required"`
 	EncryptedKey  string  `json:"encrypted_key" validate:"required"`
 	EncryptedBlob string  `json:"encrypted_blob" validate:"required"`
+	KeyVersion    *int    `json:"key_version"` //the user public key version the data is encrypted for, the current one if not provided
 	OrderNumber   *string `json:"order_number"`
 } // @name createCTestRequest
 
@@ -205,9 +355,10 @@ func (h ApisHandler) CreateExternalCTest(w http.ResponseWriter, r *http.Request)
 	uin := requestData.UIN
 	encryptedKey := requestData.EncryptedKey
 	encryptedBlob := requestData.EncryptedBlob
+	keyVersion := requestData.KeyVersion
 	orderNumber := requestData.OrderNumber
 
-	err = h.app.Services.CreateExternalCTest(providerID, uin, encryptedKey, encryptedBlob, orderNumber)
+	err = h.app.Services.CreateExternalCTest(r.Context(), providerID, uin, keyVersion, encryptedKey, encryptedBlob, orderNumber)
 	if err != nil {
 		log.Printf("Error on creating a ctest - %s\n", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -219,6 +370,170 @@ func (h ApisHandler) CreateExternalCTest(w http.ResponseWriter, r *http.Request)
 	w.Write([]byte("Successfully created"))
 }
 
+//GetPendingReEncryptions gives the ctests which are encrypted for an old user public key
+// @Description Gives the not processed ctests which are encrypted for an old public key of their user, grouped by user. The users cannot decrypt them - the providers must re-submit them encrypted for the current public key.
+// @Tags Providers
+// @ID GetPendingReEncryptions
+// @Accept json
+// @Param provider-id query string false "Provider ID"
+// @Success 200 {array} model.PendingReEncryption
+// @Security ProvidersAuth
+// @Router /covid19/ext/key-rotations [get]
+func (h ApisHandler) GetPendingReEncryptions(w http.ResponseWriter, r *http.Request) {
+	var providerID *string
+	providerIDKeys, ok := r.URL.Query()["provider-id"]
+	if ok && len(providerIDKeys[0]) > 0 {
+		providerID = &providerIDKeys[0]
+	}
+
+	items, err := h.app.Services.GetPendingReEncryptions(r.Context(), providerID)
+	if err != nil {
+		log.Printf("Error on getting the pending re-encryptions - %s\n", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		log.Println("Error on marshal the pending re-encryptions")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+type reEncryptCTestRequest struct {
+	ProviderID    string `json:"provider_id" validate:"required"`
+	KeyVersion    int    `json:"key_version" validate:"required"`
+	EncryptedKey  string `json:"encrypted_key" validate:"required"`
+	EncryptedBlob string `json:"encrypted_blob" validate:"required"`
+} // @name reEncryptCTestRequest
+
+//ReEncryptCTest re-submits a ctest for the current user public key
+// @Description Replaces the encrypted data of a not processed ctest with data encrypted for the current user public key. The key version must be the current one.
+// @Tags Providers
+// @ID ReEncryptCTest
+// @Accept json
+// @Produce plain
+// @Param data body reEncryptCTestRequest true "body data"
+// @Param id path string true "CTest ID"
+// @Success 200 {object} string "Successfully re-encrypted"
+// @Security ProvidersAuth
+// @Router /covid19/ext/ctests/{id} [put]
+func (h ApisHandler) ReEncryptCTest(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	ID := params["id"]
+	if len(ID) <= 0 {
+		log.Println("id is required")
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return
+	}
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal re-encrypt a ctest - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var requestData reEncryptCTestRequest
+	err = json.Unmarshal(data, &requestData)
+	if err != nil {
+		log.Printf("Error on unmarshal the re-encrypt ctest request data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating re-encrypt ctest data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.app.Services.ReEncryptCTest(r.Context(), requestData.ProviderID, ID, requestData.KeyVersion, requestData.EncryptedKey, requestData.EncryptedBlob)
+	if err != nil {
+		log.Printf("Error on re-encrypting a ctest - %s\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Successfully re-encrypted"))
+}
+
+type reportWaitTimeRequest struct {
+	WaitMinutes *int `json:"wait_minutes" validate:"required,min=0"`
+	QueueLength *int `json:"queue_length" validate:"omitempty,min=0"`
+} // @name reportWaitTimeRequest
+
+//ReportWaitTime reports the current wait time of a location
+// @Description Reports the current wait time and optionally the queue length of a location. The wait time color is set by the configured thresholds and it is shown until the report gets stale.
+// @Tags Providers
+// @ID ReportWaitTime
+// @Accept json
+// @Produce json
+// @Param data body reportWaitTimeRequest true "body data"
+// @Param id path string true "Location ID"
+// @Success 200 {object} model.WaitTimeReport
+// @Security ProvidersAuth
+// @Router /covid19/ext/locations/{id}/wait-time [post]
+func (h ApisHandler) ReportWaitTime(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	ID := params["id"]
+	if len(ID) <= 0 {
+		log.Println("Location id is required")
+		http.Error(w, "Location id is required", http.StatusBadRequest)
+		return
+	}
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal report a wait time - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var requestData reportWaitTimeRequest
+	err = json.Unmarshal(data, &requestData)
+	if err != nil {
+		log.Printf("Error on unmarshal the report wait time request data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating report wait time data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.app.Services.ReportWaitTime(r.Context(), ID, *requestData.WaitMinutes, requestData.QueueLength)
+	if err != nil {
+		log.Printf("Error on reporting a wait time - %s\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data, err = json.Marshal(report)
+	if err != nil {
+		log.Println("Error on marshal a wait time report")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
 type getMCountyResponse struct {
 	ID            string `json:"id"`
 	Name          string `json:"name"`
@@ -274,7 +589,7 @@ func (h ApisHandler) GetUINsByOrderNumbers(w http.ResponseWriter, r *http.Reques
 	}
 
 	var resData gubonResponse
-	resData, err := h.app.Services.GetUINsByOrderNumbers(orderNumbers)
+	resData, err := h.app.Services.GetUINsByOrderNumbers(r.Context(), orderNumbers)
 	if err != nil {
 		log.Printf("Error on getting UINs - %s\n", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -322,7 +637,7 @@ func (h ApisHandler) GetItemsListsByUINs(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	resData, err := h.app.Services.GetCTestsByExternalUserIDs(uins)
+	resData, err := h.app.Services.GetCTestsByExternalUserIDs(r.Context(), uins)
 	if err != nil {
 		log.Printf("Error on getting track items by external id - %s\n", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -382,7 +697,7 @@ func (h ApisHandler) GetExtUINOverrides(w http.ResponseWriter, r *http.Request)
 		sort = &sortByKeys[0]
 	}
 
-	uinOverrides, err := h.app.Services.GetExtUINOverrides(uin, sort)
+	uinOverrides, err := h.app.Services.GetExtUINOverrides(r.Context(), uin, sort)
 	if err != nil {
 		log.Println("Error on getting the external uin overrides items")
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -451,7 +766,7 @@ func (h ApisHandler) CreateExtUINOverrides(w http.ResponseWriter, r *http.Reques
 	category := requestData.Category
 	expiration := requestData.Expiration
 
-	uinOverride, err := h.app.Services.CreateExtUINOverride(uin, interval, category, expiration)
+	uinOverride, err := h.app.Services.CreateExtUINOverride(r.Context(), uin, interval, category, expiration)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -524,7 +839,7 @@ func (h ApisHandler) UpdateExtUINOverride(w http.ResponseWriter, r *http.Request
 	category := requestData.Category
 	expiration := requestData.Expiration
 
-	uinOverride, err := h.app.Services.UpdateExtUINOverride(uin, interval, category, expiration)
+	uinOverride, err := h.app.Services.UpdateExtUINOverride(r.Context(), uin, interval, category, expiration)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -560,7 +875,7 @@ func (h ApisHandler) DeleteExtUINOverride(w http.ResponseWriter, r *http.Request
 		http.Error(w, "uin is required", http.StatusBadRequest)
 		return
 	}
-	err := h.app.Services.DeleteExtUINOverride(uin)
+	err := h.app.Services.DeleteExtUINOverride(r.Context(), uin)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -589,7 +904,7 @@ func (h ApisHandler) GetExtBuildingAccess(w http.ResponseWriter, r *http.Request
 	}
 	uin := uinKeys[0]
 
-	uinBuildingAccess, err := h.app.Services.GetExtUINBuildingAccess(uin)
+	uinBuildingAccess, err := h.app.Services.GetExtUINBuildingAccess(r.Context(), uin)
 	if err != nil {
 		log.Printf("Error on getting ext uin building access %s", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -607,6 +922,86 @@ func (h ApisHandler) GetExtBuildingAccess(w http.ResponseWriter, r *http.Request
 	w.Write(data)
 }
 
+type evaluateStatusTestResultRequest struct {
+	TestType string    `json:"test_type" validate:"required"`
+	Result   string    `json:"result" validate:"required"`
+	Date     time.Time `json:"date" validate:"required"`
+} // @name evaluateStatusTestResultRequest
+
+type evaluateStatusSymptomReportRequest struct {
+	Symptoms []string  `json:"symptoms"`
+	Date     time.Time `json:"date" validate:"required"`
+} // @name evaluateStatusSymptomReportRequest
+
+type evaluateStatusRequest struct {
+	Date           *time.Time                           `json:"date"`
+	TestResults    []evaluateStatusTestResultRequest    `json:"test_results" validate:"dive"`
+	SymptomReports []evaluateStatusSymptomReportRequest `json:"symptom_reports" validate:"dive"`
+} // @name evaluateStatusRequest
+
+//EvaluateCountyStatus evaluates the county status
+// @Description Evaluates the county status for test results and symptom reports with the current county rules. The test types, the results and the symptoms are given by id or by name. Nothing is stored. The date is the moment of the evaluation - now if not provided.
+// @Tags Covid19
+// @ID EvaluateCountyStatus
+// @Accept json
+// @Produce json
+// @Param data body evaluateStatusRequest true "body data"
+// @Param id path string true "County ID"
+// @Success 200 {object} model.StatusEvaluation
+// @Security RokwireAuth
+// @Router /covid19/counties/{id}/status-evaluation [post]
+func (h ApisHandler) EvaluateCountyStatus(appVersion *string, w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	ID := params["id"]
+	if len(ID) <= 0 {
+		log.Println("id is required")
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return
+	}
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal evaluate status - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var requestData evaluateStatusRequest
+	err = json.Unmarshal(data, &requestData)
+	if err != nil {
+		log.Printf("Error on unmarshal the evaluate status request data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating evaluate status data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	input := convertToStatusEvaluationInput(requestData)
+	evaluation, err := h.app.Services.EvaluateCountyStatus(r.Context(), ID, input)
+	if err != nil {
+		log.Printf("Error on evaluating the county status - %s\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data, err = json.Marshal(evaluation)
+	if err != nil {
+		log.Println("Error on marshal the status evaluation")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
 //GetCounty gets a county
 // @Description Gets a county
 // @Tags Covid19
@@ -625,7 +1020,7 @@ func (h ApisHandler) GetCounty(appVersion *string, w http.ResponseWriter, r *htt
 		return
 	}
 
-	county, err := h.app.Services.GetCounty(ID)
+	county, err := h.app.Services.GetCounty(r.Context(), ID)
 	if err != nil {
 		log.Printf("Error on getting the counties items - %s", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -721,7 +1116,7 @@ type getMCountiesGuidelineItemResponse struct {
 // @Router /covid19/counties [get]
 func (h ApisHandler) GetCounties(appVersion *string, w http.ResponseWriter, r *http.Request) {
 	filter := utils.ConstructFilter(r)
-	counties, err := h.app.Services.FindCounties(filter)
+	counties, err := h.app.Services.FindCounties(r.Context(), filter)
 	if err != nil {
 		log.Println("Error on getting the counties items")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -783,6 +1178,7 @@ type getCTestsResponse struct {
 
 	EncryptedKey  string `json:"encrypted_key"`
 	EncryptedBlob string `json:"encrypted_blob"`
+	KeyVersion    int    `json:"key_version"`
 
 	Processed bool `json:"processed"`
 
@@ -808,7 +1204,7 @@ func (h ApisHandler) GetCTests(current model.User, w http.ResponseWriter, r *htt
 	}
 	processed, _ := strconv.ParseBool(keys[0])
 
-	ctests, providers, err := h.app.Services.GetCTests(current, processed)
+	ctests, providers, err := h.app.Services.GetCTests(r.Context(), current, processed)
 	if err != nil {
 		log.Println("Error on getting the ctests items")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -822,7 +1218,7 @@ func (h ApisHandler) GetCTests(current model.User, w http.ResponseWriter, r *htt
 			provider := h.findProvider(ctest.ProviderID, providers)
 
 			r := getCTestsResponse{ID: ctest.ID, ProviderID: provider.ID, ProviderName: provider.Name,
-				UserID: ctest.UserID, EncryptedKey: ctest.EncryptedKey, EncryptedBlob: ctest.EncryptedBlob,
+				UserID: ctest.UserID, EncryptedKey: ctest.EncryptedKey, EncryptedBlob: ctest.EncryptedBlob, KeyVersion: ctest.KeyVersion,
 				Processed: ctest.Processed, DateCreated: ctest.DateCreated, DateUpdated: ctest.DateUpdated}
 			resultList[i] = r
 		}
@@ -886,7 +1282,7 @@ func (h ApisHandler) UpdateCTest(current model.User, w http.ResponseWriter, r *h
 		return
 	}
 
-	ctest, err := h.app.Services.UpdateCTest(current, ID, requestData.Processed)
+	ctest, err := h.app.Services.UpdateCTest(r.Context(), current, ID, requestData.Processed)
 	if err != nil {
 		log.Printf("Error on updating the ctest item - %s\n", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -913,7 +1309,7 @@ func (h ApisHandler) UpdateCTest(current model.User, w http.ResponseWriter, r *h
 // @Security AppUserAuth
 // @Router /covid19/ctests [delete]
 func (h ApisHandler) DeleteCTests(current model.User, w http.ResponseWriter, r *http.Request) {
-	deletedCount, err := h.app.Services.DeleteCTests(current.ID)
+	deletedCount, err := h.app.Services.DeleteCTests(r.Context(), current.ID)
 	if err != nil {
 		log.Printf("Error on deleting the ctests items - %s\n", err.Error())
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -935,7 +1331,7 @@ func (h ApisHandler) DeleteCTests(current model.User, w http.ResponseWriter, r *
 // @Security RokwireAuth
 // @Router /covid19/resources [get]
 func (h ApisHandler) GetResources(appVersion *string, w http.ResponseWriter, r *http.Request) {
-	resources, err := h.app.Services.GetResources()
+	resources, err := h.app.Services.GetResources(r.Context())
 	if err != nil {
 		log.Printf("Error on getting resources %s", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -968,7 +1364,7 @@ func (h ApisHandler) GetResources(appVersion *string, w http.ResponseWriter, r *
 // @Security RokwireAuth
 // @Router /covid19/faq [get]
 func (h ApisHandler) GetFAQ(appVersion *string, w http.ResponseWriter, r *http.Request) {
-	faq, err := h.app.Services.GetFAQ()
+	faq, err := h.app.Services.GetFAQ(r.Context())
 	if err != nil {
 		log.Printf("Error on getting faq %s", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -991,26 +1387,22 @@ func (h ApisHandler) GetFAQ(appVersion *string, w http.ResponseWriter, r *http.R
 }
 
 //GetNews gives the covid19 news
-// @Description Gives the covid19 news.
+// @Description Gives the published covid19 news which are not expired. The pinned news are first.
 // @Tags Covid19
 // @ID GetNews
 // @Accept json
+// @Param limit query integer false "Limit"
+// @Param category query string false "Category"
 // @Success 200 {array} model.News
 // @Security RokwireAuth
 // @Router /covid19/news [get]
 func (h ApisHandler) GetNews(appVersion *string, w http.ResponseWriter, r *http.Request) {
-	var limit int64
-	var err error
-
-	limParam := r.URL.Query().Get("limit")
-	if len(limParam) > 0 {
-		limit, err = strconv.ParseInt(limParam, 10, 64)
-		if err != nil {
-			http.Error(w, "limit must be a number", http.StatusBadRequest)
-		}
+	limit, category, ok := readNewsQuery(w, r, 0)
+	if !ok {
+		return
 	}
 
-	news, err := h.app.Services.GetNews(limit)
+	news, err := h.app.Services.GetNews(r.Context(), limit, category)
 	if err != nil {
 		log.Printf("Error on getting news %s", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -1069,7 +1461,7 @@ func (h ApisHandler) GetStatusV2(current model.User, w http.ResponseWriter, r *h
 }
 
 func (h ApisHandler) processGetStatusV2(current model.User, appVersion *string, w http.ResponseWriter, r *http.Request) {
-	status, err := h.app.Services.GetEStatusByUserID(current.ID, appVersion)
+	status, err := h.app.Services.GetEStatusByUserID(r.Context(), current.ID, appVersion)
 	if err != nil {
 		log.Println("Error on getting a status")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -1159,7 +1551,7 @@ func (h ApisHandler) processCreateOrUpdateStatusV2(current model.User, appVersio
 	encryptedKey := requestData.EncryptedKey
 	encryptedBlob := requestData.EncryptedBlob
 
-	status, err := h.app.Services.CreateOrUpdateEStatus(current.ID, appVersion, date, encryptedKey, encryptedBlob)
+	status, err := h.app.Services.CreateOrUpdateEStatus(r.Context(), current.ID, appVersion, date, encryptedKey, encryptedBlob)
 	if err != nil {
 		log.Printf("Error on marshal a status - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -1200,7 +1592,7 @@ func (h ApisHandler) DeleteStatusV2(current model.User, w http.ResponseWriter, r
 }
 
 func (h ApisHandler) processDeleteStatusV2(current model.User, appVersion *string, w http.ResponseWriter, r *http.Request) {
-	err := h.app.Services.DeleteEStatus(current.ID, appVersion)
+	err := h.app.Services.DeleteEStatus(r.Context(), current.ID, appVersion)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -1278,14 +1670,14 @@ func (h ApisHandler) CreateHistoryV2(current model.User, w http.ResponseWriter,
 			return
 		}
 
-		history, err = h.app.Services.CreateManualЕHistory(current.ID, date, encryptedKey, encryptedBlob, encryptedImageKey, encryptedImageBlob, countyID, locationID)
+		history, err = h.app.Services.CreateManualЕHistory(r.Context(), current.ID, current.KeyVersion, date, encryptedKey, encryptedBlob, encryptedImageKey, encryptedImageBlob, countyID, locationID)
 		if err != nil {
 			log.Println(err.Error())
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 	} else {
-		history, err = h.app.Services.CreateЕHistory(current.ID, date, eType, encryptedKey, encryptedBlob)
+		history, err = h.app.Services.CreateЕHistory(r.Context(), current.ID, current.KeyVersion, date, eType, encryptedKey, encryptedBlob)
 		if err != nil {
 			log.Println(err.Error())
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -1391,7 +1783,7 @@ func (h ApisHandler) UpdateHistoryV2(current model.User, w http.ResponseWriter,
 	date := requestData.Date
 	encryptedKey := requestData.EncryptedKey
 	encryptedBlob := requestData.EncryptedBlob
-	history, err := h.app.Services.UpdateEHistory(current.ID, ID, date, encryptedKey, encryptedBlob)
+	history, err := h.app.Services.UpdateEHistory(r.Context(), current.ID, current.KeyVersion, ID, date, encryptedKey, encryptedBlob)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -1418,7 +1810,7 @@ func (h ApisHandler) UpdateHistoryV2(current model.User, w http.ResponseWriter,
 // @Security AppUserAuth
 // @Router /covid19/v2/histories [get]
 func (h ApisHandler) GetHistoriesV2(current model.User, w http.ResponseWriter, r *http.Request) {
-	historiesItems, err := h.app.Services.GetEHistoriesByUserID(current.ID)
+	historiesItems, err := h.app.Services.GetEHistoriesByUserID(r.Context(), current.ID)
 	if err != nil {
 		log.Println("Error on getting the histories items")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -1450,7 +1842,7 @@ func (h ApisHandler) GetHistoriesV2(current model.User, w http.ResponseWriter, r
 // @Security AppUserAuth
 // @Router /covid19/v2/histories [delete]
 func (h ApisHandler) DeleteHistoriesV2(current model.User, w http.ResponseWriter, r *http.Request) {
-	deletedCount, err := h.app.Services.DeleteEHitories(current.ID)
+	deletedCount, err := h.app.Services.DeleteEHitories(r.Context(), current.ID)
 	if err != nil {
 		log.Printf("Error on deleting the ehistories items - %s\n", err.Error())
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -1472,7 +1864,7 @@ func (h ApisHandler) DeleteHistoriesV2(current model.User, w http.ResponseWriter
 // @Security AppUserAuth
 // @Router /covid19/uin-override [get]
 func (h ApisHandler) GetUINOverride(current model.User, w http.ResponseWriter, r *http.Request) {
-	uinOverride, err := h.app.Services.GetUINOverride(current)
+	uinOverride, err := h.app.Services.GetUINOverride(r.Context(), current)
 	if err != nil {
 		log.Printf("Error on getting the uin override item - %s\n", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -1535,7 +1927,7 @@ func (h ApisHandler) CreateOrUpdateUINOverride(current model.User, w http.Respon
 	category := requestData.Category
 	expiration := requestData.Expiration
 
-	err = h.app.Services.CreateOrUpdateUINOverride(current, interval, category, expiration)
+	err = h.app.Services.CreateOrUpdateUINOverride(r.Context(), current, interval, category, expiration)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -1588,7 +1980,7 @@ func (h ApisHandler) SetUINBuildingAccess(current model.User, w http.ResponseWri
 	date := requestData.Date
 	access := requestData.Access
 
-	err = h.app.Services.SetUINBuildingAccess(current, date, access)
+	err = h.app.Services.SetUINBuildingAccess(r.Context(), current, date, access)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -1609,7 +2001,7 @@ func (h ApisHandler) SetUINBuildingAccess(current model.User, w http.ResponseWri
 // @Security RokwireAuth
 // @Router /covid19/providers [get]
 func (h ApisHandler) GetProviders(appVersion *string, w http.ResponseWriter, r *http.Request) {
-	providers, err := h.app.Services.GetProviders()
+	providers, err := h.app.Services.GetProviders(r.Context())
 	if err != nil {
 		log.Println("Error on getting the providers items")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -1652,7 +2044,7 @@ func (h ApisHandler) GetProvidersByCounty(appVersion *string, w http.ResponseWri
 		return
 	}
 
-	locations, err := h.app.Services.GetLocationsByCountyID(countyID)
+	locations, err := h.app.Services.GetLocationsByCountyID(r.Context(), countyID)
 	if err != nil {
 		log.Println("Error on getting the providers for a county")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -1695,7 +2087,7 @@ func (h ApisHandler) GetProvidersByCounties(appVersion *string, w http.ResponseW
 		return
 	}
 
-	locations, err := h.app.Services.GetLocationsByCounties(countyIDs)
+	locations, err := h.app.Services.GetLocationsByCounties(r.Context(), countyIDs)
 	if err != nil {
 		log.Println("Error on getting the providers for a counties list")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -1789,7 +2181,7 @@ func (h ApisHandler) GetRulesByCounty(appVersion *string, w http.ResponseWriter,
 		http.Error(w, "county id is required", http.StatusBadRequest)
 		return
 	}
-	rules, countyStatuses, testTypes, err := h.app.Services.GetRulesByCounty(countyID)
+	rules, countyStatuses, testTypes, err := h.app.Services.GetRulesByCounty(r.Context(), countyID)
 	if err != nil {
 		log.Printf("Error on getting the rules items - %s", err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -1903,7 +2295,7 @@ func (h ApisHandler) GetLocationsByCountyIDProviderID(appVersion *string, w http
 	countyID := countyKeys[0]
 	providerID := providerKeys[0]
 
-	locations, err := h.app.Services.GetLocationsByProviderIDCountyID(providerID, countyID)
+	locations, err := h.app.Services.GetLocationsByProviderIDCountyID(r.Context(), providerID, countyID)
 	if err != nil {
 		log.Println("Error on getting the locations for provider and county")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -1921,7 +2313,7 @@ func (h ApisHandler) GetLocationsByCountyIDProviderID(appVersion *string, w http
 			locItem := locationResponse{ID: location.ID, Name: location.Name, Address1: location.Address1, Address2: location.Address2,
 				City: location.City, State: location.State, ZIP: location.ZIP, Country: location.Country, Latitude: location.Latitude, Longitude: location.Longitude,
 				Timezone: location.Timezone, Contact: location.Contact, DaysOfOperation: convertFromDaysOfOperations(location.DaysOfOperation),
-				URL: location.URL, Notes: location.Notes, WaitTimeColor: location.WaitTimeColor, ProviderID: location.Provider.ID,
+				Exceptions: convertFromLocationExceptions(location.Exceptions), URL: location.URL, Notes: location.Notes, WaitTimeColor: location.WaitTimeColor, WaitTime: convertFromLocationWaitTime(location.WaitTime), AppointmentSettings: location.AppointmentSettings, ProviderID: location.Provider.ID,
 				CountyID: location.County.ID, AvailableTests: availableTestsRes}
 
 			response = append(response, locItem)
@@ -1951,7 +2343,7 @@ func (h ApisHandler) GetLocationsByCountyID(appVersion *string, w http.ResponseW
 	}
 	countyID := countyKeys[0]
 
-	locations, err := h.app.Services.GetLocationsByCountyID(countyID)
+	locations, err := h.app.Services.GetLocationsByCountyID(r.Context(), countyID)
 	if err != nil {
 		log.Println("Error on getting the locations for county")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -1969,7 +2361,7 @@ func (h ApisHandler) GetLocationsByCountyID(appVersion *string, w http.ResponseW
 			locItem := locationResponse{ID: location.ID, Name: location.Name, Address1: location.Address1, Address2: location.Address2,
 				City: location.City, State: location.State, ZIP: location.ZIP, Country: location.Country, Latitude: location.Latitude, Longitude: location.Longitude,
 				Timezone: location.Timezone, Contact: location.Contact, DaysOfOperation: convertFromDaysOfOperations(location.DaysOfOperation),
-				URL: location.URL, Notes: location.Notes, WaitTimeColor: location.WaitTimeColor, ProviderID: location.Provider.ID,
+				Exceptions: convertFromLocationExceptions(location.Exceptions), URL: location.URL, Notes: location.Notes, WaitTimeColor: location.WaitTimeColor, WaitTime: convertFromLocationWaitTime(location.WaitTime), AppointmentSettings: location.AppointmentSettings, ProviderID: location.Provider.ID,
 				CountyID: location.County.ID, AvailableTests: availableTestsRes}
 
 			response = append(response, locItem)
@@ -1990,6 +2382,111 @@ func (h ApisHandler) GetLocationsByCountyID(appVersion *string, w http.ResponseW
 	w.Write(data)
 }
 
+type nearbyLocationResponse struct {
+	locationResponse
+
+	Distance float64 `json:"distance"` //in meters
+} // @name NearbyLocation
+
+//GetLocationsNearby gets the locations near a point
+// @Description Gets the locations within the radius from the point, the nearest first, with the distance in meters.
+// @Tags Covid19
+// @ID GetLocationsNearby
+// @Accept json
+// @Param lat query number true "Latitude"
+// @Param lng query number true "Longitude"
+// @Param radius query number false "Radius in meters, 10000 by default and 100000 at most"
+// @Param limit query int false "Limit, 20 by default and 100 at most"
+// @Param test-type-id query string false "Only the locations with this test type"
+// @Param provider-id query string false "Only the locations of this provider"
+// @Param open-now query bool false "Only the locations which are open at the moment"
+// @Success 200 {array} nearbyLocationResponse
+// @Security RokwireAuth
+// @Router /covid19/locations/nearby [get]
+func (h ApisHandler) GetLocationsNearby(appVersion *string, w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	latitude, err := strconv.ParseFloat(query.Get("lat"), 64)
+	if err != nil || latitude < -90 || latitude > 90 {
+		log.Println("url param 'lat' is missing or invalid")
+		http.Error(w, "url param 'lat' is missing or invalid", http.StatusBadRequest)
+		return
+	}
+	longitude, err := strconv.ParseFloat(query.Get("lng"), 64)
+	if err != nil || longitude < -180 || longitude > 180 {
+		log.Println("url param 'lng' is missing or invalid")
+		http.Error(w, "url param 'lng' is missing or invalid", http.StatusBadRequest)
+		return
+	}
+	var radius float64
+	if radiusParam := query.Get("radius"); len(radiusParam) > 0 {
+		radius, err = strconv.ParseFloat(radiusParam, 64)
+		if err != nil || radius <= 0 {
+			log.Printf("Invalid radius %s\n", radiusParam)
+			http.Error(w, "Invalid radius", http.StatusBadRequest)
+			return
+		}
+	}
+	var limit int
+	if limitParam := query.Get("limit"); len(limitParam) > 0 {
+		limit, err = strconv.Atoi(limitParam)
+		if err != nil || limit < 1 {
+			log.Printf("Invalid limit %s\n", limitParam)
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+	var testTypeID *string
+	if value := query.Get("test-type-id"); len(value) > 0 {
+		testTypeID = &value
+	}
+	var providerID *string
+	if value := query.Get("provider-id"); len(value) > 0 {
+		providerID = &value
+	}
+	var openNow bool
+	if value := query.Get("open-now"); len(value) > 0 {
+		openNow, err = strconv.ParseBool(value)
+		if err != nil {
+			log.Printf("Invalid open-now %s\n", value)
+			http.Error(w, "Invalid open-now", http.StatusBadRequest)
+			return
+		}
+	}
+
+	items, err := h.app.Services.GetLocationsNearby(r.Context(), latitude, longitude, radius, limit, testTypeID, providerID, openNow)
+	if err != nil {
+		log.Printf("Error on getting the locations nearby - %s\n", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	response := make([]nearbyLocationResponse, 0)
+	for _, item := range items {
+		location := item.Location
+		var availableTestsRes []string
+		if location.AvailableTests != nil {
+			for _, testType := range location.AvailableTests {
+				availableTestsRes = append(availableTestsRes, testType.ID)
+			}
+		}
+		locItem := locationResponse{ID: location.ID, Name: location.Name, Address1: location.Address1, Address2: location.Address2,
+			City: location.City, State: location.State, ZIP: location.ZIP, Country: location.Country, Latitude: location.Latitude, Longitude: location.Longitude,
+			Timezone: location.Timezone, Contact: location.Contact, DaysOfOperation: convertFromDaysOfOperations(location.DaysOfOperation),
+			Exceptions: convertFromLocationExceptions(location.Exceptions), URL: location.URL, Notes: location.Notes, WaitTimeColor: location.WaitTimeColor, WaitTime: convertFromLocationWaitTime(location.WaitTime), AppointmentSettings: location.AppointmentSettings, ProviderID: location.Provider.ID,
+			CountyID: location.County.ID, AvailableTests: availableTestsRes}
+		response = append(response, nearbyLocationResponse{locationResponse: locItem, Distance: item.Distance})
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		log.Println("Error on marshal the locations items")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
 //GetLocation gets a location
 // @Description Gets a location
 // @Tags Covid19
@@ -2007,7 +2504,7 @@ func (h ApisHandler) GetLocation(appVersion *string, w http.ResponseWriter, r *h
 		http.Error(w, "location id is required", http.StatusBadRequest)
 		return
 	}
-	location, err := h.app.Services.GetLocation(ID)
+	location, err := h.app.Services.GetLocation(r.Context(), ID)
 	if err != nil {
 		log.Printf("Error on getting the location- %s", err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -2028,7 +2525,7 @@ func (h ApisHandler) GetLocation(appVersion *string, w http.ResponseWriter, r *h
 	locItem := locationResponse{ID: location.ID, Name: location.Name, Address1: location.Address1, Address2: location.Address2,
 		City: location.City, State: location.State, ZIP: location.ZIP, Country: location.Country, Latitude: location.Latitude, Longitude: location.Longitude,
 		Timezone: location.Timezone, Contact: location.Contact, DaysOfOperation: convertFromDaysOfOperations(location.DaysOfOperation),
-		URL: location.URL, Notes: location.Notes, WaitTimeColor: location.WaitTimeColor, ProviderID: location.Provider.ID,
+		Exceptions: convertFromLocationExceptions(location.Exceptions), URL: location.URL, Notes: location.Notes, WaitTimeColor: location.WaitTimeColor, WaitTime: convertFromLocationWaitTime(location.WaitTime), AppointmentSettings: location.AppointmentSettings, ProviderID: location.Provider.ID,
 		CountyID: location.County.ID, AvailableTests: availableTestsRes}
 	data, err := json.Marshal(locItem)
 	if err != nil {
@@ -2042,6 +2539,234 @@ func (h ApisHandler) GetLocation(appVersion *string, w http.ResponseWriter, r *h
 	w.Write(data)
 }
 
+type appointmentSlotResponse struct {
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Capacity  int       `json:"capacity"`
+	Available int       `json:"available"`
+} // @name AppointmentSlot
+
+//GetAppointmentSlots gets the appointment slots of a location
+// @Description Gets the appointment slots of a location which can be booked. The slots are generated from the days of operation and the exceptions of the location, the times are in its time zone.
+// @Tags Covid19
+// @ID GetAppointmentSlots
+// @Accept json
+// @Param id path string true "Location ID"
+// @Success 200 {array} appointmentSlotResponse
+// @Security RokwireAuth
+// @Router /covid19/locations/{id}/appointment-slots [get]
+func (h ApisHandler) GetAppointmentSlots(appVersion *string, w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	ID := params["id"]
+	if len(ID) <= 0 {
+		log.Println("Location id is required")
+		http.Error(w, "Location id is required", http.StatusBadRequest)
+		return
+	}
+
+	slots, err := h.app.Services.GetAppointmentSlots(r.Context(), ID)
+	if err != nil {
+		log.Printf("Error on getting the appointment slots - %s\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	response := make([]appointmentSlotResponse, len(slots))
+	for i, slot := range slots {
+		available := slot.Capacity - slot.Booked
+		if available < 0 {
+			//the capacity has been decreased after the booking
+			available = 0
+		}
+		response[i] = appointmentSlotResponse{Start: slot.Start, End: slot.End, Capacity: slot.Capacity, Available: available}
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		log.Println("Error on marshal the appointment slots")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//GetAppointments gets the appointments of the user
+// @Description Gets all the appointments of the current user, the booked and the cancelled ones, the earliest first.
+// @Tags Covid19
+// @ID GetAppointments
+// @Accept json
+// @Success 200 {array} model.Appointment
+// @Security AppUserAuth
+// @Router /covid19/appointments [get]
+func (h ApisHandler) GetAppointments(current model.User, w http.ResponseWriter, r *http.Request) {
+	appointments, err := h.app.Services.GetUserAppointments(r.Context(), current)
+	if err != nil {
+		log.Printf("Error on getting the appointments - %s\n", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if appointments == nil {
+		appointments = []*model.Appointment{}
+	}
+	data, err := json.Marshal(appointments)
+	if err != nil {
+		log.Println("Error on marshal the appointments")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+type bookAppointmentRequest struct {
+	LocationID string     `json:"location_id" validate:"required"`
+	Start      *time.Time `json:"start" validate:"required"`
+} // @name bookAppointmentRequest
+
+//BookAppointment books an appointment
+// @Description Books an appointment at a location for the slot which starts at the provided time. The user can have only one booked appointment at a location. A confirmation is sent to the user.
+// @Tags Covid19
+// @ID BookAppointment
+// @Accept json
+// @Produce json
+// @Param data body bookAppointmentRequest true "body data"
+// @Success 200 {object} model.Appointment
+// @Security AppUserAuth
+// @Router /covid19/appointments [post]
+func (h ApisHandler) BookAppointment(current model.User, w http.ResponseWriter, r *http.Request) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal book an appointment - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var requestData bookAppointmentRequest
+	err = json.Unmarshal(data, &requestData)
+	if err != nil {
+		log.Printf("Error on unmarshal the book appointment request data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating book appointment data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	appointment, err := h.app.Services.BookAppointment(r.Context(), current, requestData.LocationID, *requestData.Start)
+	if err != nil {
+		log.Printf("Error on booking an appointment - %s\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.writeAppointment(appointment, w)
+}
+
+type rescheduleAppointmentRequest struct {
+	Start *time.Time `json:"start" validate:"required"`
+} // @name rescheduleAppointmentRequest
+
+//RescheduleAppointment reschedules an appointment
+// @Description Moves a booked appointment of the user to the slot which starts at the provided time at the same location. A confirmation is sent to the user.
+// @Tags Covid19
+// @ID RescheduleAppointment
+// @Accept json
+// @Produce json
+// @Param data body rescheduleAppointmentRequest true "body data"
+// @Param id path string true "ID"
+// @Success 200 {object} model.Appointment
+// @Security AppUserAuth
+// @Router /covid19/appointments/{id} [put]
+func (h ApisHandler) RescheduleAppointment(current model.User, w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	ID := params["id"]
+	if len(ID) <= 0 {
+		log.Println("id is required")
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return
+	}
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal reschedule an appointment - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var requestData rescheduleAppointmentRequest
+	err = json.Unmarshal(data, &requestData)
+	if err != nil {
+		log.Printf("Error on unmarshal the reschedule appointment request data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating reschedule appointment data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	appointment, err := h.app.Services.RescheduleAppointment(r.Context(), current, ID, *requestData.Start)
+	if err != nil {
+		log.Printf("Error on rescheduling an appointment - %s\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.writeAppointment(appointment, w)
+}
+
+//CancelAppointment cancels an appointment
+// @Description Cancels a booked appointment of the user. A confirmation is sent to the user.
+// @Tags Covid19
+// @ID CancelAppointment
+// @Accept json
+// @Produce json
+// @Param id path string true "ID"
+// @Success 200 {object} model.Appointment
+// @Security AppUserAuth
+// @Router /covid19/appointments/{id} [delete]
+func (h ApisHandler) CancelAppointment(current model.User, w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	ID := params["id"]
+	if len(ID) <= 0 {
+		log.Println("id is required")
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return
+	}
+
+	appointment, err := h.app.Services.CancelAppointment(r.Context(), current, ID)
+	if err != nil {
+		log.Printf("Error on cancelling an appointment - %s\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.writeAppointment(appointment, w)
+}
+
+func (h ApisHandler) writeAppointment(appointment *model.Appointment, w http.ResponseWriter) {
+	data, err := json.Marshal(appointment)
+	if err != nil {
+		log.Println("Error on marshal an appointment")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
 type getMTestTypesResponse struct {
 	ID       string `json:"id"`
 	Name     string `json:"name"`
@@ -2068,7 +2793,7 @@ func (h ApisHandler) GetTestTypesByIDs(appVersion *string, w http.ResponseWriter
 	idsKey := idsKeys[0]
 
 	ids := strings.Split(idsKey, ",")
-	testTypes, err := h.app.Services.GetTestTypesByIDs(ids)
+	testTypes, err := h.app.Services.GetTestTypesByIDs(r.Context(), ids)
 	if err != nil {
 		log.Println("Error on getting the test types for ids")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -2115,7 +2840,7 @@ func (h ApisHandler) GetTestTypesByIDs(appVersion *string, w http.ResponseWriter
 // @Security RokwireAuth
 // @Router /covid19/test-types [get]
 func (h ApisHandler) GetTestTypes(appVersion *string, w http.ResponseWriter, r *http.Request) {
-	testTypes, err := h.app.Services.GetAllTestTypes()
+	testTypes, err := h.app.Services.GetAllTestTypes(r.Context())
 	if err != nil {
 		log.Println("Error on getting the test types")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -2174,7 +2899,7 @@ type mSymptomResponse struct {
 // @Security RokwireAuth
 // @Router /covid19/symptom-groups [get]
 func (h ApisHandler) GetSymptomGroups(appVersion *string, w http.ResponseWriter, r *http.Request) {
-	symptomGroups, err := h.app.Services.GetSymptomGroups()
+	symptomGroups, err := h.app.Services.GetSymptomGroups(r.Context())
 	if err != nil {
 		log.Println("Error on getting the symptom groups items")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -2215,7 +2940,7 @@ func (h ApisHandler) GetSymptomGroups(appVersion *string, w http.ResponseWriter,
 // @Security RokwireAuth
 // @Router /covid19/symptoms [get]
 func (h ApisHandler) GetSymptoms(appVersion *string, w http.ResponseWriter, r *http.Request) {
-	symptoms, err := h.app.Services.GetSymptoms(appVersion)
+	symptoms, err := h.app.Services.GetSymptoms(r.Context(), appVersion)
 	if err != nil {
 		log.Printf("Error on getting the symptoms - %s", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -2267,7 +2992,7 @@ func (h ApisHandler) GetSymptomRuleByCounty(appVersion *string, w http.ResponseW
 		http.Error(w, "county id is required", http.StatusBadRequest)
 		return
 	}
-	symptomRule, countyStatuses, err := h.app.Services.GetSymptomRuleByCounty(countyID)
+	symptomRule, countyStatuses, err := h.app.Services.GetSymptomRuleByCounty(r.Context(), countyID)
 	if err != nil {
 		log.Printf("Error on getting the symptom rule - %s", err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -2320,7 +3045,7 @@ func (h ApisHandler) GetCRulesByCounty(appVersion *string, w http.ResponseWriter
 		return
 	}
 
-	symptomsRules, err := h.app.Services.GetCRulesByCounty(appVersion, countyID)
+	symptomsRules, err := h.app.Services.GetCRulesByCounty(r.Context(), appVersion, countyID)
 	if err != nil {
 		log.Printf("Error on getting the symptoms - %s", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -2355,7 +3080,7 @@ func (h ApisHandler) GetAccessRuleByCounty(appVersion *string, w http.ResponseWr
 		return
 	}
 
-	accessRule, countyStatuses, err := h.app.Services.GetAccessRuleByCounty(countyID)
+	accessRule, countyStatuses, err := h.app.Services.GetAccessRuleByCounty(r.Context(), countyID)
 	if err != nil {
 		log.Printf("Error on getting the access rule - %s", err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -2438,7 +3163,7 @@ func (h ApisHandler) AddTraceReport(appVersion *string, w http.ResponseWriter, r
 	}
 
 	//add it
-	insertedCount, err := h.app.Services.AddTraceReport(traceExposures)
+	insertedCount, err := h.app.Services.AddTraceReport(r.Context(), traceExposures)
 	if err != nil {
 		log.Printf("Error on adding a trace report - %s", err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -2487,7 +3212,7 @@ func (h ApisHandler) GetExposures(appVersion *string, w http.ResponseWriter, r *
 		dateAdded = &da
 	}
 
-	items, err := h.app.Services.GetExposures(timestamp, dateAdded)
+	items, err := h.app.Services.GetExposures(r.Context(), timestamp, dateAdded)
 	if err != nil {
 		log.Printf("Error on getting the trace exposures items - %s", err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -40,6 +40,7 @@ type AppUserResponse struct {
 	ID                   string  `json:"id"`
 	UUID                 string  `json:"uuid"`
 	PublicKey            string  `json:"public_key"`
+	KeyVersion           int     `json:"key_version"`
 	Consent              bool    `json:"consent"`
 	ExposureNotification bool    `json:"exposure_notification"`
 	RePost               bool    `json:"re_post"`
@@ -99,9 +100,13 @@ type locationResponse struct {
 	Timezone        string                         `json:"timezone"`
 	Contact         string                         `json:"contact"`
 	DaysOfOperation []locationOperationDayResponse `json:"days_of_operation"`
+	Exceptions      []locationExceptionResponse    `json:"exceptions"`
 	URL             string                         `json:"url"`
 	Notes           string                         `json:"notes"`
 	WaitTimeColor   *string                        `json:"wait_time_color"`
+	WaitTime        *locationWaitTimeResponse      `json:"wait_time"`
+
+	AppointmentSettings *model.AppointmentSettings `json:"appointment_settings"` //null if the location does not offer appointments
 
 	ProviderID string `json:"provider_id"`
 	CountyID   string `json:"county_id"`
@@ -110,11 +115,39 @@ type locationResponse struct {
 } // @name Location
 
 type locationOperationDayResponse struct {
-	Name      string `json:"name"`
+	Name      string                              `json:"name"`
+	AllDay    bool                                `json:"all_day"`
+	Intervals []locationOperationIntervalResponse `json:"intervals"`
+
+	//the first open and the last close time for the clients which do not support the intervals
 	OpenTime  string `json:"open_time"`
 	CloseTime string `json:"close_time"`
 } // @name OperationDay
 
+type locationOperationIntervalResponse struct {
+	OpenTime  string `json:"open_time"`
+	CloseTime string `json:"close_time"`
+} // @name OperationInterval
+
+type locationWaitTimeResponse struct {
+	WaitMinutes  int       `json:"wait_minutes"`
+	QueueLength  *int      `json:"queue_length"`
+	Color        string    `json:"color"`
+	DateReported time.Time `json:"date_reported"`
+	Stale        bool      `json:"stale"`
+} // @name LocationWaitTime
+
+type locationExceptionResponse struct {
+	ID        string `json:"id"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	Type      string `json:"type"`
+	OpenTime  string `json:"open_time"`
+	CloseTime string `json:"close_time"`
+	Address   string `json:"address"`
+	Note      string `json:"note"`
+} // @name LocationException
+
 type providerResponse struct {
 	ID                  string   `json:"id"`
 	ProviderName        string   `json:"provider_name"`
@@ -129,16 +162,25 @@ type symptomResponse struct {
 
 //PUserResponse represents user response entity used by the providers
 type PUserResponse struct {
-	UIN       string `json:"uin"`
-	PublicKey string `json:"public_key"`
-	Consent   bool   `json:"consent"`
+	UIN        string `json:"uin"`
+	PublicKey  string `json:"public_key"`
+	KeyVersion int    `json:"key_version"`
+	Consent    bool   `json:"consent"`
 } //@name PUser
 
 func convertToDaysOfOperations(list []locationOperationDayRequest) []model.OperationDay {
 	var doo []model.OperationDay
 	if list != nil {
 		for _, d := range list {
-			item := model.OperationDay{Name: d.Name, OpenTime: d.OpenTime, CloseTime: d.CloseTime}
+			var intervals []model.OperationInterval
+			for _, interval := range d.Intervals {
+				intervals = append(intervals, model.OperationInterval{OpenTime: interval.OpenTime, CloseTime: interval.CloseTime})
+			}
+			//the clients which do not support the intervals send one interval as open and close times
+			if len(intervals) == 0 && (len(d.OpenTime) > 0 || len(d.CloseTime) > 0) {
+				intervals = append(intervals, model.OperationInterval{OpenTime: d.OpenTime, CloseTime: d.CloseTime})
+			}
+			item := model.OperationDay{Name: d.Name, AllDay: d.AllDay, Intervals: intervals}
 			doo = append(doo, item)
 		}
 	}
@@ -149,9 +191,52 @@ func convertFromDaysOfOperations(list []model.OperationDay) []locationOperationD
 	var doo []locationOperationDayResponse
 	if list != nil {
 		for _, d := range list {
-			item := locationOperationDayResponse{Name: d.Name, OpenTime: d.OpenTime, CloseTime: d.CloseTime}
+			intervals := []locationOperationIntervalResponse{}
+			for _, interval := range d.Intervals {
+				intervals = append(intervals, locationOperationIntervalResponse{OpenTime: interval.OpenTime, CloseTime: interval.CloseTime})
+			}
+			item := locationOperationDayResponse{Name: d.Name, AllDay: d.AllDay, Intervals: intervals}
+			if d.AllDay {
+				item.OpenTime = "12:00am"
+				item.CloseTime = "11:59pm"
+			} else if len(intervals) > 0 {
+				item.OpenTime = intervals[0].OpenTime
+				item.CloseTime = intervals[len(intervals)-1].CloseTime
+			}
 			doo = append(doo, item)
 		}
 	}
 	return doo
 }
+
+func convertFromLocationWaitTime(waitTime *model.WaitTimeReport) *locationWaitTimeResponse {
+	if waitTime == nil {
+		return nil
+	}
+	return &locationWaitTimeResponse{WaitMinutes: waitTime.WaitMinutes, QueueLength: waitTime.QueueLength, Color: waitTime.Color,
+		DateReported: waitTime.DateCreated, Stale: waitTime.Stale}
+}
+
+func convertFromLocationExceptions(list []model.LocationException) []locationExceptionResponse {
+	result := []locationExceptionResponse{}
+	for _, e := range list {
+		item := locationExceptionResponse{ID: e.ID, StartDate: e.StartDate, EndDate: e.EndDate, Type: e.Type,
+			OpenTime: e.OpenTime, CloseTime: e.CloseTime, Address: e.Address, Note: e.Note}
+		result = append(result, item)
+	}
+	return result
+}
+
+func convertToStatusEvaluationInput(requestData evaluateStatusRequest) model.StatusEvaluationInput {
+	var input model.StatusEvaluationInput
+	if requestData.Date != nil {
+		input.Date = *requestData.Date
+	}
+	for _, item := range requestData.TestResults {
+		input.TestResults = append(input.TestResults, model.EvaluationTestResult{TestType: item.TestType, Result: item.Result, Date: item.Date})
+	}
+	for _, item := range requestData.SymptomReports {
+		input.SymptomReports = append(input.SymptomReports, model.EvaluationSymptomReport{Symptoms: item.Symptoms, Date: item.Date})
+	}
+	return input
+}
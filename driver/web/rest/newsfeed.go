@@ -0,0 +1,332 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package rest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"health/core/model"
+	"health/utils"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	newsFeedTitle       = "COVID-19 News"
+	newsFeedDescription = "The latest COVID-19 news"
+	newsFeedAuthor      = "Rokwire"
+
+	//the feeds are public so they always give a limited number of the latest items
+	newsFeedDefaultLimit = 50
+	newsFeedMaxLimit     = 100
+	newsFeedCacheControl = "public, max-age=300"
+)
+
+type rssFeed struct {
+	XMLName   xml.Name   `xml:"rss"`
+	Version   string     `xml:"version,attr"`
+	ContentNS string     `xml:"xmlns:content,attr"`
+	AtomNS    string     `xml:"xmlns:atom,attr"`
+	Channel   rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	SelfLink      atomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link,omitempty"`
+	Description string   `xml:"description"`
+	Content     string   `xml:"content:encoded,omitempty"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Categories  []string `xml:"category"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Link    []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Link       []atomLink     `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+	Content    *atomText      `xml:"content,omitempty"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+//GetNewsRSS gives the covid19 news as RSS 2.0 feed
+// @Description Gives the published covid19 news which are not expired as RSS 2.0 feed. It does not require authentication and it can be cached.
+// @Tags Covid19
+// @ID GetNewsRSS
+// @Produce xml
+// @Param limit query integer false "Limit from 1 to 100, 50 if not provided"
+// @Param category query string false "Category"
+// @Success 200 {string} string "RSS 2.0 feed"
+// @Success 304 {string} string "Not Modified"
+// @Failure 400 {string} string "Bad Request"
+// @Router /covid19/news/rss [get]
+func (h ApisHandler) GetNewsRSS(w http.ResponseWriter, r *http.Request) {
+	news, ok := h.getFeedNews(w, r)
+	if !ok {
+		return
+	}
+
+	selfURL := feedRequestURL(r)
+	lastModified := newsLastModified(news)
+	feed := rssFeed{Version: "2.0", ContentNS: "http://purl.org/rss/1.0/modules/content/", AtomNS: "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{Title: newsFeedTitle, Link: selfURL, Description: newsFeedDescription,
+			SelfLink: atomLink{Href: selfURL, Rel: "self", Type: "application/rss+xml"}, Items: []rssItem{}}}
+	if !lastModified.IsZero() {
+		feed.Channel.LastBuildDate = lastModified.Format(time.RFC1123Z)
+	}
+	for _, item := range news {
+		entry := rssItem{Title: item.Title, Link: utils.GetString(item.Link), Description: item.Description,
+			Content: utils.ModifyHTMLContent(item.HTMLContent), GUID: rssGUID{Value: "urn:uuid:" + item.ID},
+			PubDate: newsPublishedDate(item).Format(time.RFC1123Z), Categories: item.Categories}
+		feed.Channel.Items = append(feed.Channel.Items, entry)
+	}
+	writeNewsFeed(w, r, feed, "application/rss+xml; charset=utf-8", lastModified)
+}
+
+//GetNewsAtom gives the covid19 news as Atom 1.0 feed
+// @Description Gives the published covid19 news which are not expired as Atom 1.0 feed. It does not require authentication and it can be cached.
+// @Tags Covid19
+// @ID GetNewsAtom
+// @Produce xml
+// @Param limit query integer false "Limit from 1 to 100, 50 if not provided"
+// @Param category query string false "Category"
+// @Success 200 {string} string "Atom 1.0 feed"
+// @Success 304 {string} string "Not Modified"
+// @Failure 400 {string} string "Bad Request"
+// @Router /covid19/news/atom [get]
+func (h ApisHandler) GetNewsAtom(w http.ResponseWriter, r *http.Request) {
+	news, ok := h.getFeedNews(w, r)
+	if !ok {
+		return
+	}
+
+	selfURL := feedRequestURL(r)
+	lastModified := newsLastModified(news)
+	updated := lastModified
+	if updated.IsZero() {
+		//the updated element is required
+		updated = time.Unix(0, 0).UTC()
+	}
+	feed := atomFeed{ID: selfURL, Title: newsFeedTitle, Updated: updated.Format(time.RFC3339), Author: atomAuthor{Name: newsFeedAuthor},
+		Link: []atomLink{{Href: selfURL, Rel: "self", Type: "application/atom+xml"}}, Entries: []atomEntry{}}
+	for _, item := range news {
+		entry := atomEntry{ID: "urn:uuid:" + item.ID, Title: item.Title, Published: newsPublishedDate(item).Format(time.RFC3339),
+			Updated: newsModifiedDate(item).Format(time.RFC3339)}
+		if item.Link != nil && len(*item.Link) > 0 {
+			entry.Link = []atomLink{{Href: *item.Link, Rel: "alternate"}}
+		}
+		if len(item.Description) > 0 {
+			entry.Summary = &atomText{Type: "html", Value: item.Description}
+		}
+		if len(item.HTMLContent) > 0 {
+			entry.Content = &atomText{Type: "html", Value: utils.ModifyHTMLContent(item.HTMLContent)}
+		}
+		for _, category := range item.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: category})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	writeNewsFeed(w, r, feed, "application/atom+xml; charset=utf-8", lastModified)
+}
+
+func (h ApisHandler) getFeedNews(w http.ResponseWriter, r *http.Request) ([]*model.News, bool) {
+	limit, category, ok := readNewsQuery(w, r, newsFeedDefaultLimit)
+	if !ok {
+		return nil, false
+	}
+	//0 means no limit for the news api so it is not allowed here
+	if limit < 1 {
+		http.Error(w, "limit must be at least 1", http.StatusBadRequest)
+		return nil, false
+	}
+	if limit > newsFeedMaxLimit {
+		limit = newsFeedMaxLimit
+	}
+
+	news, err := h.app.Services.GetNews(r.Context(), limit, category)
+	if err != nil {
+		log.Printf("Error on getting the news feed %s", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return nil, false
+	}
+	return news, true
+}
+
+//readNewsQuery reads the limit and the category query params
+func readNewsQuery(w http.ResponseWriter, r *http.Request, defaultLimit int64) (int64, *string, bool) {
+	limit := defaultLimit
+	limParam := r.URL.Query().Get("limit")
+	if len(limParam) > 0 {
+		var err error
+		limit, err = strconv.ParseInt(limParam, 10, 64)
+		if err != nil {
+			http.Error(w, "limit must be a number", http.StatusBadRequest)
+			return 0, nil, false
+		}
+	}
+
+	var category *string
+	categoryParam := strings.TrimSpace(r.URL.Query().Get("category"))
+	if len(categoryParam) > 0 {
+		category = &categoryParam
+	}
+	return limit, category, true
+}
+
+//writeNewsFeed writes the feed with ETag and Last-Modified headers. It gives 304 if the client has the same feed.
+func writeNewsFeed(w http.ResponseWriter, r *http.Request, feed interface{}, contentType string, lastModified time.Time) {
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		log.Printf("Error on marshal the news feed %s", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	data = append([]byte(xml.Header), data...)
+
+	hash := sha256.Sum256(data)
+	etag := "\"" + hex.EncodeToString(hash[:16]) + "\""
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", newsFeedCacheControl)
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if isFeedNotModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//isFeedNotModified checks the conditional headers, If-Modified-Since is used only if there is no If-None-Match
+func isFeedNotModified(r *http.Request, etag string, lastModified time.Time) bool {
+	ifNoneMatch := r.Header.Get("If-None-Match")
+	if len(ifNoneMatch) > 0 {
+		for _, value := range strings.Split(ifNoneMatch, ",") {
+			value = strings.TrimPrefix(strings.TrimSpace(value), "W/")
+			if value == etag || value == "*" {
+				return true
+			}
+		}
+		return false
+	}
+
+	ifModifiedSince := r.Header.Get("If-Modified-Since")
+	if len(ifModifiedSince) == 0 || lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+	//the header has seconds precision
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+//newsPublishedDate gives when the item has been published - the publish time if it is scheduled after the date
+func newsPublishedDate(news *model.News) time.Time {
+	if news.PublishAt != nil && news.PublishAt.After(news.Date) {
+		return news.PublishAt.UTC()
+	}
+	return news.Date.UTC()
+}
+
+//newsModifiedDate gives when the item has been published or updated, the later one
+func newsModifiedDate(news *model.News) time.Time {
+	result := newsPublishedDate(news)
+	if news.DateUpdated != nil && news.DateUpdated.After(result) {
+		return news.DateUpdated.UTC()
+	}
+	return result
+}
+
+//newsLastModified gives the latest published or updated date, it is zero if there are no news
+func newsLastModified(news []*model.News) time.Time {
+	var result time.Time
+	for _, item := range news {
+		if modified := newsModifiedDate(item); modified.After(result) {
+			result = modified
+		}
+	}
+	return result
+}
+
+//feedRequestURL gives the url the feed has been requested with, the proxy scheme is used if provided
+func feedRequestURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); len(proto) > 0 {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host + r.URL.RequestURI()
+}
@@ -18,17 +18,24 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"health/core"
+	"health/core/model"
 	audit "health/driven/audit"
 	dataprovider "health/driven/dataprovider"
+	memory "health/driven/memory"
 	messaging "health/driven/messaging"
 	profilebb "health/driven/profilebb"
 	sender "health/driven/sender"
 	storage "health/driven/storage"
 	driver "health/driver/web"
+	"io/ioutil"
 	"log"
 	"os"
 	"strings"
+	"time"
 )
 
 var (
@@ -43,25 +50,23 @@ func main() {
 		Version = "dev"
 	}
 
-	//mongoDB adapter
-	mongoDBAuth := getEnvKey("HEALTH_MONGO_AUTH", true)
-	mongoDBName := getEnvKey("HEALTH_MONGO_DATABASE", true)
-	mongoTimeout := getEnvKey("HEALTH_MONGO_TIMEOUT", false)
-	storageAdapter := storage.NewStorageAdapter(mongoDBAuth, mongoDBName, mongoTimeout)
-	err := storageAdapter.Start()
-	if err != nil {
-		log.Fatal("Cannot start the mongoDB adapter - " + err.Error())
+	//migrate command - health migrate <status|dry-run|up>
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrateCommand(os.Args[2:])
+		return
 	}
 
-	//audit adapter
-	auditAdapter := audit.NewAuditAdapter(mongoDBAuth, mongoDBName, mongoTimeout)
-	err = auditAdapter.Start()
-	if err != nil {
-		log.Fatal("Cannot start the audit adapter - " + err.Error())
+	//county bundle command - health county-bundle <export county-id [file]|import file [name]|apply county-id file>
+	if len(os.Args) > 1 && os.Args[1] == "county-bundle" {
+		runCountyBundleCommand(os.Args[2:])
+		return
 	}
 
+	//storage and audit adapters
+	storageAdapter, auditAdapter := getStorageAdapters()
+
 	//data provider adapter
-	newsRSSURL := getEnvKey("HEALTH_NEWS_RSS_URL", true)
+	newsRSSURL := getEnvKey("HEALTH_NEWS_RSS_URL", false)
 	resourcesURL := getEnvKey("HEALTH_RESOURCES_URL", true)
 	dataProvider := dataprovider.NewDataProviderAdapter(newsRSSURL, resourcesURL)
 
@@ -84,23 +89,202 @@ func main() {
 	profileBBAdapter := profilebb.NewProfileBBAdapter(profileHost, profileAPIKey)
 
 	//application
-	application := core.NewApplication(Version, Build, dataProvider, sender, messaging, profileBBAdapter, storageAdapter, auditAdapter)
+	erasureSigningKey := getEnvKey("HEALTH_ERASURE_SIGNING_KEY", true)
+	application := core.NewApplication(Version, Build, dataProvider, sender, messaging, profileBBAdapter, storageAdapter, auditAdapter, erasureSigningKey)
 	application.Start()
 
 	//web adapter
 	apiKeys := getAPIKeys()
 	host := getEnvKey("HEALTH_HOST", true)
+	requestTimeout := getEnvKey("HEALTH_REQUEST_TIMEOUT", false)
 	oidcProvider := getEnvKey("HEALTH_OIDC_PROVIDER", true)
 	oidcAppClientID := getEnvKey("HEALTH_OIDC_APP_CLIENT_ID", true)
 	adminAppClientID := getEnvKey("HEALTH_OIDC_ADMIN_CLIENT_ID", true)
 	adminWebAppClientID := getEnvKey("HEALTH_OIDC_ADMIN_WEB_CLIENT_ID", true)
 	phoneSecret := getEnvKey("HEALTH_PHONE_SECRET", true)
 	providersKeys := getHSAPIKeys()
-	webAdapter := driver.NewWebAdapter(host, application, apiKeys, oidcProvider, oidcAppClientID, adminAppClientID, adminWebAppClientID, phoneSecret, providersKeys)
+	webAdapter := driver.NewWebAdapter(host, requestTimeout, application, apiKeys, oidcProvider, oidcAppClientID, adminAppClientID, adminWebAppClientID, phoneSecret, providersKeys)
 
 	webAdapter.Start()
 }
 
+func getStorageAdapters() (core.Storage, core.Audit) {
+	//the mongoDB storage is the default one
+	storageType := getEnvKey("HEALTH_STORAGE", false)
+	switch storageType {
+	case "memory":
+		//in-memory adapters - local development and tests only, the data is lost on restart
+		log.Println("Using in-memory storage")
+
+		storageAdapter := memory.NewMemoryAdapter()
+		err := storageAdapter.Start()
+		if err != nil {
+			log.Fatal("Cannot start the memory storage adapter - " + err.Error())
+		}
+
+		auditAdapter := memory.NewMemoryAuditAdapter()
+		err = auditAdapter.Start()
+		if err != nil {
+			log.Fatal("Cannot start the memory audit adapter - " + err.Error())
+		}
+		return storageAdapter, auditAdapter
+	case "", "mongodb":
+		//mongoDB adapter
+		mongoDBAuth := getEnvKey("HEALTH_MONGO_AUTH", true)
+		mongoDBName := getEnvKey("HEALTH_MONGO_DATABASE", true)
+		mongoTimeout := getEnvKey("HEALTH_MONGO_TIMEOUT", false)
+		mongoPollInterval := getEnvKey("HEALTH_MONGO_POLL_INTERVAL", false)
+		storageAdapter := storage.NewStorageAdapter(mongoDBAuth, mongoDBName, mongoTimeout, mongoPollInterval)
+		err := storageAdapter.Start()
+		if err != nil {
+			log.Fatal("Cannot start the mongoDB adapter - " + err.Error())
+		}
+
+		//audit adapter
+		auditAdapter := audit.NewAuditAdapter(mongoDBAuth, mongoDBName, mongoTimeout)
+		err = auditAdapter.Start()
+		if err != nil {
+			log.Fatal("Cannot start the audit adapter - " + err.Error())
+		}
+		return storageAdapter, auditAdapter
+	default:
+		log.Fatal("Not supported storage type - " + storageType)
+	}
+	return nil, nil
+}
+
+func runMigrateCommand(args []string) {
+	command := "status"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	mongoDBAuth := getEnvKey("HEALTH_MONGO_AUTH", true)
+	mongoDBName := getEnvKey("HEALTH_MONGO_DATABASE", true)
+	mongoTimeout := getEnvKey("HEALTH_MONGO_TIMEOUT", false)
+	storageAdapter := storage.NewStorageAdapter(mongoDBAuth, mongoDBName, mongoTimeout, "")
+	err := storageAdapter.Connect()
+	if err != nil {
+		log.Fatal("Cannot connect to the mongoDB - " + err.Error())
+	}
+
+	var migrations []storage.MigrationStatus
+	switch command {
+	case "status":
+		migrations, err = storageAdapter.MigrationsStatus()
+	case "dry-run":
+		migrations, err = storageAdapter.Migrate(true)
+	case "up":
+		migrations, err = storageAdapter.Migrate(false)
+	default:
+		log.Fatal("Not supported migrate command - " + command + ". Use status, dry-run or up")
+	}
+	//print what is done even if there is an error
+	for _, migration := range migrations {
+		state := "pending"
+		if migration.Applied {
+			state = "applied " + migration.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Printf("%4d %-40s %s\n", migration.Version, migration.Name, state)
+	}
+	if err != nil {
+		log.Fatal("Error executing migrate " + command + " - " + err.Error())
+	}
+}
+
+func runCountyBundleCommand(args []string) {
+	if len(args) < 2 {
+		log.Fatal("Use county-bundle export <county-id> [file], county-bundle import <file> [name] or county-bundle apply <county-id> <file>")
+	}
+	command := args[0]
+
+	mongoDBAuth := getEnvKey("HEALTH_MONGO_AUTH", true)
+	mongoDBName := getEnvKey("HEALTH_MONGO_DATABASE", true)
+	mongoTimeout := getEnvKey("HEALTH_MONGO_TIMEOUT", false)
+	storageAdapter := storage.NewStorageAdapter(mongoDBAuth, mongoDBName, mongoTimeout, "")
+	err := storageAdapter.Connect()
+	if err != nil {
+		log.Fatal("Cannot connect to the mongoDB - " + err.Error())
+	}
+	auditAdapter := audit.NewAuditAdapter(mongoDBAuth, mongoDBName, mongoTimeout)
+	err = auditAdapter.Start()
+	if err != nil {
+		log.Fatal("Cannot start the audit adapter - " + err.Error())
+	}
+
+	//the application is not started, only the administration is used
+	application := core.NewApplication(Version, Build, nil, nil, nil, nil, storageAdapter, auditAdapter, "")
+	ctx := context.Background()
+
+	switch command {
+	case "export":
+		bundle, err := application.Administration.ExportCountyBundle(ctx, args[1])
+		if err != nil {
+			log.Fatal("Error exporting the county bundle - " + err.Error())
+		}
+		data, err := json.MarshalIndent(bundle, "", "  ")
+		if err != nil {
+			log.Fatal("Error on marshal the county bundle - " + err.Error())
+		}
+		if len(args) > 2 {
+			err = ioutil.WriteFile(args[2], data, 0644)
+			if err != nil {
+				log.Fatal("Error writing the county bundle - " + err.Error())
+			}
+			return
+		}
+		fmt.Println(string(data))
+	case "import":
+		data, err := ioutil.ReadFile(args[1])
+		if err != nil {
+			log.Fatal("Error reading the county bundle - " + err.Error())
+		}
+		var bundle model.CountyBundle
+		err = json.Unmarshal(data, &bundle)
+		if err != nil {
+			log.Fatal("Error on unmarshal the county bundle - " + err.Error())
+		}
+		var name *string
+		if len(args) > 2 {
+			name = &args[2]
+		}
+
+		//there is no logged in user, the audit keeps the command as group
+		county, err := application.Administration.ImportCountyBundle(ctx, model.User{}, "county-bundle command", nil, bundle, name)
+		if err != nil {
+			log.Fatal("Error importing the county bundle - " + err.Error())
+		}
+		auditAdapter.Wait()
+		fmt.Printf("Created county %s - %s, %s, %s\n", county.ID, county.Name, county.StateProvince, county.Country)
+	case "apply":
+		if len(args) < 3 {
+			log.Fatal("Use county-bundle apply <county-id> <file>")
+		}
+		data, err := ioutil.ReadFile(args[2])
+		if err != nil {
+			log.Fatal("Error reading the county bundle - " + err.Error())
+		}
+		var bundle model.CountyBundle
+		err = json.Unmarshal(data, &bundle)
+		if err != nil {
+			log.Fatal("Error on unmarshal the county bundle - " + err.Error())
+		}
+
+		//there is no logged in user, the audit keeps the command as group
+		changes, err := application.Administration.ApplyCountyBundle(ctx, model.User{}, "county-bundle command", nil, args[1], bundle)
+		if err != nil {
+			log.Fatal("Error applying the county bundle - " + err.Error())
+		}
+		auditAdapter.Wait()
+		for _, change := range changes {
+			fmt.Printf("%s %s %s %s\n", change.Operation, change.Entity, change.Key, change.Field)
+		}
+		fmt.Printf("Applied %d changes to county %s\n", len(changes), args[1])
+	default:
+		log.Fatal("Not supported county-bundle command - " + command + ". Use export, import or apply")
+	}
+}
+
 func getEmailsRecepients() []string {
 	//get from the environment
 	emails, exist := os.LookupEnv("HEALTH_EMAIL_TO")
@@ -0,0 +1,312 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var versionRegexp = regexp.MustCompile(`^(0|[1-9]\d*)(?:\.(0|[1-9]\d*))?(?:\.(0|[1-9]\d*))?(?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?(?:\+([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?$`)
+
+//Version represents a semantic version. The minor and the patch are 0 if not provided - 2 is 2.0.0 and 2.8 is 2.8.0.
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	PreRelease []string
+	Build      string //it does not take part in the comparison
+}
+
+//ParseVersion parses x[.y[.z]][-pre.release][+build]
+func ParseVersion(v string) (*Version, error) {
+	match := versionRegexp.FindStringSubmatch(strings.TrimSpace(v))
+	if match == nil {
+		return nil, fmt.Errorf("invalid version %s", v)
+	}
+	var version Version
+	version.Major, _ = strconv.Atoi(match[1])
+	if len(match[2]) > 0 {
+		version.Minor, _ = strconv.Atoi(match[2])
+	}
+	if len(match[3]) > 0 {
+		version.Patch, _ = strconv.Atoi(match[3])
+	}
+	if len(match[4]) > 0 {
+		version.PreRelease = strings.Split(match[4], ".")
+		for _, identifier := range version.PreRelease {
+			if len(identifier) > 1 && identifier[0] == '0' && isNumeric(identifier) {
+				return nil, fmt.Errorf("invalid version %s - numeric pre-release identifiers must not have leading zeros", v)
+			}
+		}
+	}
+	version.Build = match[5]
+	return &version, nil
+}
+
+//String gives the short view the system works with - x.y when the patch is 0, the build metadata is not included
+func (v Version) String() string {
+	result := fmt.Sprintf("%d.%d", v.Major, v.Minor)
+	if v.Patch != 0 || len(v.PreRelease) > 0 {
+		result = fmt.Sprintf("%s.%d", result, v.Patch)
+	}
+	if len(v.PreRelease) > 0 {
+		result = result + "-" + strings.Join(v.PreRelease, ".")
+	}
+	return result
+}
+
+//Compare gives -1, 0 or 1 if the version is less than, equal to or greater than the other one by the semver precedence
+func (v Version) Compare(other Version) int {
+	if result := compareInts(v.Major, other.Major); result != 0 {
+		return result
+	}
+	if result := compareInts(v.Minor, other.Minor); result != 0 {
+		return result
+	}
+	if result := compareInts(v.Patch, other.Patch); result != 0 {
+		return result
+	}
+
+	//a pre-release version is less than the normal one
+	switch {
+	case len(v.PreRelease) == 0 && len(other.PreRelease) == 0:
+		return 0
+	case len(v.PreRelease) == 0:
+		return 1
+	case len(other.PreRelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.PreRelease) && i < len(other.PreRelease); i++ {
+		a, b := v.PreRelease[i], other.PreRelease[i]
+		aNumeric, bNumeric := isNumeric(a), isNumeric(b)
+		switch {
+		case aNumeric && bNumeric:
+			aValue, _ := strconv.Atoi(a)
+			bValue, _ := strconv.Atoi(b)
+			if result := compareInts(aValue, bValue); result != 0 {
+				return result
+			}
+		case aNumeric:
+			//numeric identifiers have lower precedence
+			return -1
+		case bNumeric:
+			return 1
+		default:
+			if result := strings.Compare(a, b); result != 0 {
+				return result
+			}
+		}
+	}
+	return compareInts(len(v.PreRelease), len(other.PreRelease))
+}
+
+type versionComparator struct {
+	operator string
+	version  Version
+}
+
+func (c versionComparator) matches(v Version) bool {
+	result := v.Compare(c.version)
+	switch c.operator {
+	case ">":
+		return result > 0
+	case ">=":
+		return result >= 0
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	default:
+		return result == 0
+	}
+}
+
+//VersionRange represents a set of versions - ">=2.8 <3.0", "^2.8", "~2.8.1", "2.8 || >=3.1" or "*".
+//The comparators separated by space must all match, the sets separated by || are alternatives.
+type VersionRange struct {
+	sets [][]versionComparator
+	text string
+}
+
+var versionRangeOperatorRegexp = regexp.MustCompile(`^(>=|<=|>|<|=|\^|~)?\s*(.+)$`)
+
+//ParseVersionRange parses a version range
+func ParseVersionRange(r string) (*VersionRange, error) {
+	var sets [][]versionComparator
+	var texts []string
+	for _, setText := range strings.Split(r, "||") {
+		fields := strings.Fields(setText)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid version range %s - empty set", r)
+		}
+		//allow a space between the operator and the version - ">= 2.8"
+		var items []string
+		for i := 0; i < len(fields); i++ {
+			if isRangeOperator(fields[i]) && i+1 < len(fields) {
+				items = append(items, fields[i]+fields[i+1])
+				i++
+				continue
+			}
+			items = append(items, fields[i])
+		}
+
+		var set []versionComparator
+		for _, item := range items {
+			comparators, err := parseVersionComparators(item)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version range %s - %s", r, err)
+			}
+			set = append(set, comparators...)
+		}
+		sets = append(sets, set)
+		texts = append(texts, strings.Join(items, " "))
+	}
+	return &VersionRange{sets: sets, text: strings.Join(texts, " || ")}, nil
+}
+
+func parseVersionComparators(item string) ([]versionComparator, error) {
+	if item == "*" || item == "x" {
+		return []versionComparator{{operator: ">=", version: Version{}}}, nil
+	}
+	match := versionRangeOperatorRegexp.FindStringSubmatch(item)
+	if match == nil {
+		return nil, errors.New("invalid comparator " + item)
+	}
+	operator, value := match[1], match[2]
+	version, err := ParseVersion(value)
+	if err != nil {
+		return nil, err
+	}
+	segments := len(strings.Split(strings.SplitN(strings.SplitN(value, "+", 2)[0], "-", 2)[0], "."))
+
+	switch operator {
+	case "^":
+		//the left-most non-zero segment does not change
+		upper := Version{Major: version.Major + 1}
+		if version.Major == 0 && segments > 1 {
+			upper = Version{Minor: version.Minor + 1}
+			if version.Minor == 0 && segments > 2 {
+				upper = Version{Patch: version.Patch + 1}
+			}
+		}
+		return []versionComparator{{operator: ">=", version: *version}, {operator: "<", version: upper}}, nil
+	case "~":
+		//the patch can change if the minor is provided, the minor otherwise
+		upper := Version{Major: version.Major + 1}
+		if segments > 1 {
+			upper = Version{Major: version.Major, Minor: version.Minor + 1}
+		}
+		return []versionComparator{{operator: ">=", version: *version}, {operator: "<", version: upper}}, nil
+	case "":
+		operator = "="
+	}
+	return []versionComparator{{operator: operator, version: *version}}, nil
+}
+
+//Contains checks if the version is in the range. A pre-release version is in the range only if a comparator
+//of the set is for a pre-release of the same x.y.z - ">=3.0.0-beta.1 <3.0.0" contains 3.0.0-beta.2 but "^2.9" does not contain 3.0.0-beta.1.
+func (r VersionRange) Contains(v Version) bool {
+	for _, set := range r.sets {
+		matches := len(v.PreRelease) == 0
+		for _, comparator := range set {
+			if !comparator.matches(v) {
+				matches = false
+				break
+			}
+			if len(comparator.version.PreRelease) > 0 && comparator.version.Major == v.Major &&
+				comparator.version.Minor == v.Minor && comparator.version.Patch == v.Patch {
+				matches = true
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+//MinVersion gives the lowest version the range could contain. It is used to find the most specific of several ranges.
+func (r VersionRange) MinVersion() Version {
+	var result *Version
+	for _, set := range r.sets {
+		setMin := Version{}
+		for _, comparator := range set {
+			switch comparator.operator {
+			case ">", ">=", "=":
+				if comparator.version.Compare(setMin) > 0 {
+					setMin = comparator.version
+				}
+			}
+		}
+		if result == nil || setMin.Compare(*result) < 0 {
+			result = &setMin
+		}
+	}
+	if result == nil {
+		return Version{}
+	}
+	return *result
+}
+
+//String gives the range with normalized spaces
+func (r VersionRange) String() string {
+	return r.text
+}
+
+//IsVersionRange checks if the value is a range and not a single version
+func IsVersionRange(value string) bool {
+	if _, err := ParseVersion(value); err == nil {
+		return false
+	}
+	_, err := ParseVersionRange(value)
+	return err == nil
+}
+
+func isRangeOperator(value string) bool {
+	switch value {
+	case ">", ">=", "<", "<=", "=", "^", "~":
+		return true
+	}
+	return false
+}
+
+func isNumeric(value string) bool {
+	if len(value) == 0 {
+		return false
+	}
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func compareInts(a int, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -18,11 +18,11 @@
 package utils
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 
@@ -117,6 +117,24 @@ func ModifyHTMLContent(input string) string {
 	return final
 }
 
+type contextKey string
+
+const requestIDContextKey contextKey = "request-id"
+
+//WithRequestID gives a copy of the context which holds the request id
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, requestID)
+}
+
+//GetRequestID gives the request id from the context. It gives an empty string if there is no request id
+func GetRequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDContextKey).(string)
+	return requestID
+}
+
 //LogRequest logs the request as hide some header fields because of security reasons
 func LogRequest(req *http.Request) {
 	if req == nil {
@@ -138,7 +156,7 @@ func LogRequest(req *http.Request) {
 		}
 		header[key] = logValue
 	}
-	log.Printf("%s %s %s", method, path, header)
+	log.Printf("%s %s %s %s", GetRequestID(req.Context()), method, path, header)
 }
 
 //GetLogValue prepares a sensitive data to be logged.
@@ -207,60 +225,21 @@ func GetTime(time *time.Time) string {
 func SortVersions(versions []string) {
 	//sort
 	sort.Slice(versions, func(i, j int) bool {
-		v1 := versions[i]
-		v2 := versions[j]
-		return !IsVersionLess(v1, v2)
+		//the latest is first
+		return IsVersionLess(versions[j], versions[i])
 	})
 }
 
-//IsVersionLess checks if v1 is less than v2. The format is x.x.x or x.x which is the short for x.x.0
+//IsVersionLess checks if v1 is less than v2 by the semver precedence. The short views x.y and x are x.y.0 and x.0.0.
+//It gives false if any of the versions is not valid.
 func IsVersionLess(v1 string, v2 string) bool {
-	var v1Major, v1Minor, v1Patch int
-	var v2Major, v2Minor, v2Patch int
-
-	v1Elements := strings.Split(v1, ".")
-	v2Elements := strings.Split(v2, ".")
-
-	v1Major, _ = strconv.Atoi(v1Elements[0])
-	v1Minor, _ = strconv.Atoi(v1Elements[1])
-	if len(v1Elements) == 2 {
-		v1Patch = 0
-	} else {
-		v1Patch, _ = strconv.Atoi(v1Elements[2])
-	}
-
-	v2Major, _ = strconv.Atoi(v2Elements[0])
-	v2Minor, _ = strconv.Atoi(v2Elements[1])
-	if len(v2Elements) == 2 {
-		v2Patch = 0
-	} else {
-		v2Patch, _ = strconv.Atoi(v2Elements[2])
-	}
-
-	//1 first check major
-	if v1Major < v2Major {
-		return true
-	}
-	if v1Major > v2Major {
-		return false
-	}
-
-	//2. majors are equals so check minors
-	if v1Minor < v2Minor {
-		return true
-	}
-	if v1Minor > v2Minor {
+	version1, err := ParseVersion(v1)
+	if err != nil {
 		return false
 	}
-
-	//3. minors are equals so check patch
-	if v1Patch < v2Patch {
-		return true
-	}
-	if v1Patch > v2Patch {
+	version2, err := ParseVersion(v2)
+	if err != nil {
 		return false
 	}
-
-	// they are equals
-	return false
+	return version1.Compare(*version2) < 0
 }